@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"runtime"
+	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -18,9 +19,15 @@ import (
 	"github.com/kubernetes-incubator/custom-metrics-apiserver/pkg/provider"
 
 	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+	"github.com/kedacore/keda/pkg/audit"
+	"github.com/kedacore/keda/pkg/generated/clientset/versioned"
+	"github.com/kedacore/keda/pkg/generated/informers/externalversions"
+	"github.com/kedacore/keda/pkg/globalconfig"
 	prommetrics "github.com/kedacore/keda/pkg/metrics"
 	kedaprovider "github.com/kedacore/keda/pkg/provider"
 	"github.com/kedacore/keda/pkg/scaling"
+	"github.com/kedacore/keda/pkg/tracing"
+	kedautil "github.com/kedacore/keda/pkg/util"
 	"github.com/kedacore/keda/version"
 )
 
@@ -35,26 +42,33 @@ type Adapter struct {
 var logger = klogr.New().WithName("keda_metrics_adapter")
 
 var (
-	prometheusMetricsPort int
-	prometheusMetricsPath string
+	prometheusMetricsPort   int
+	prometheusMetricsPath   string
+	tracingConfig           = tracing.Config{ServiceName: "keda-metrics-apiserver"}
+	otlpMetricsConfig       = prommetrics.OTLPConfig{ServiceName: "keda-metrics-apiserver"}
+	auditConfig             = audit.Config{Component: "keda-metrics-apiserver"}
+	globalConfigMap         string
+	scalerConcurrency       = scaling.ScalerConcurrencyConfig{}
+	scalerConcurrencyLimits string
 )
 
-func (a *Adapter) makeProviderOrDie() provider.MetricsProvider {
+// makeProvider returns the provider of the external metrics, the errors are logged
+func (a *Adapter) makeProvider() (provider.MetricsProvider, error) {
 	// Get a config to talk to the apiserver
 	cfg, err := config.GetConfig()
 	if err != nil {
 		logger.Error(err, "failed to get the config")
-		os.Exit(1)
+		return nil, err
 	}
 
 	scheme := scheme.Scheme
 	if err := appsv1.SchemeBuilder.AddToScheme(scheme); err != nil {
 		logger.Error(err, "failed to add apps/v1 scheme to runtime scheme")
-		os.Exit(1)
+		return nil, err
 	}
 	if err := kedav1alpha1.SchemeBuilder.AddToScheme(scheme); err != nil {
 		logger.Error(err, "failed to add keda scheme to runtime scheme")
-		os.Exit(1)
+		return nil, err
 	}
 
 	kubeclient, err := client.New(cfg, client.Options{
@@ -62,21 +76,43 @@ func (a *Adapter) makeProviderOrDie() provider.MetricsProvider {
 	})
 	if err != nil {
 		logger.Error(err, "unable to construct new client")
-		os.Exit(1)
+		return nil, err
 	}
 
 	handler := scaling.NewScaleHandler(kubeclient, nil, scheme)
 
+	leases, err := scaling.NewScalerConcurrencyLeases(kubeclient, kubeclient, scalerConcurrency)
+	if err != nil {
+		logger.Error(err, "unable to set up the scaler concurrency Lease")
+		return nil, err
+	}
+	go func() { _ = leases.Start(wait.NeverStop) }()
+
 	namespace, err := getWatchNamespace()
 	if err != nil {
 		logger.Error(err, "failed to get watch namespace")
-		os.Exit(1)
+		return nil, err
+	}
+
+	kedaClient, err := versioned.NewForConfig(cfg)
+	if err != nil {
+		logger.Error(err, "unable to construct new keda client")
+		return nil, err
+	}
+	// the informer only reports the deletions of the ScaledObjects, to evict their cached metrics
+	informerOptions := []externalversions.SharedInformerOption{}
+	if namespaces := kedautil.ParseWatchNamespaces(namespace); len(namespaces) == 1 {
+		informerOptions = append(informerOptions, externalversions.WithNamespace(namespaces[0]))
 	}
+	informerFactory := externalversions.NewSharedInformerFactoryWithOptions(kedaClient, 0, informerOptions...)
+	scaledObjectInformer := informerFactory.Keda().V1alpha1().ScaledObjects().Informer()
 
 	prometheusServer := &prommetrics.PrometheusMetricServer{}
 	go func() { prometheusServer.NewServer(fmt.Sprintf(":%v", prometheusMetricsPort), prometheusMetricsPath) }()
 
-	return kedaprovider.NewProvider(logger, handler, kubeclient, namespace)
+	kedaProvider := kedaprovider.NewProvider(logger, handler, kubeclient, namespace, scaledObjectInformer)
+	informerFactory.Start(wait.NeverStop)
+	return kedaProvider, nil
 }
 
 func printVersion() {
@@ -86,17 +122,24 @@ func printVersion() {
 	logger.Info(fmt.Sprintf("Go OS/Arch: %s/%s", runtime.GOOS, runtime.GOARCH))
 }
 
-// getWatchNamespace returns the namespace the operator should be watching for changes
+// getWatchNamespace returns the comma-separated namespaces the operator should be watching for changes, empty for all the namespaces
 func getWatchNamespace() (string, error) {
-	const WatchNamespaceEnvVar = "WATCH_NAMESPACE"
-	ns, found := os.LookupEnv(WatchNamespaceEnvVar)
+	ns, found := os.LookupEnv(kedautil.WatchNamespaceEnvVar)
 	if !found {
-		return "", fmt.Errorf("%s must be set", WatchNamespaceEnvVar)
+		return "", fmt.Errorf("%s must be set", kedautil.WatchNamespaceEnvVar)
 	}
 	return ns, nil
 }
 
 func main() {
+	// the errors are logged by run, the deferred shutdowns have run when it returns
+	if err := run(); err != nil {
+		os.Exit(1)
+	}
+}
+
+// run starts the metrics server and returns once it is stopped, the telemetry is flushed before it returns
+func run() error {
 	defer klog.Flush()
 
 	printVersion()
@@ -106,14 +149,75 @@ func main() {
 	cmd.Flags().AddGoFlagSet(flag.CommandLine) // make sure we get the klog flags
 	cmd.Flags().IntVar(&prometheusMetricsPort, "metrics-port", 9022, "Set the port to expose prometheus metrics")
 	cmd.Flags().StringVar(&prometheusMetricsPath, "metrics-path", "/metrics", "Set the path for the prometheus metrics endpoint")
+	cmd.Flags().StringVar(&tracingConfig.Endpoint, "otlp-endpoint", "", "Set the address of the OTLP collector the traces are exported to, tracing is disabled if not set")
+	cmd.Flags().BoolVar(&tracingConfig.Insecure, "otlp-insecure", false, "Disable the transport security of the connection to the OTLP collector")
+	cmd.Flags().Float64Var(&tracingConfig.SampleRatio, "trace-sample-ratio", 1, "Set the fraction of the traces that are sampled, between 0 and 1")
+	cmd.Flags().StringVar(&otlpMetricsConfig.Endpoint, "otlp-metrics-endpoint", "", "Set the address of the OTLP collector the metrics are pushed to, the metrics are only served to Prometheus if not set")
+	cmd.Flags().BoolVar(&otlpMetricsConfig.Insecure, "otlp-metrics-insecure", false, "Disable the transport security of the connection to the OTLP metrics collector")
+	cmd.Flags().DurationVar(&otlpMetricsConfig.Period, "otlp-metrics-period", 30*time.Second, "Set the interval the metrics are pushed to the OTLP collector at")
+	cmd.Flags().StringVar(&auditConfig.Sink, "audit-log", "", "Set the sink the metric values served to the HPA are logged to: stdout, file:<path> or configmap:<namespace>/<name>, the audit log is disabled if not set")
+	cmd.Flags().IntVar(&auditConfig.MaxEntries, "audit-log-max-entries", 500, "Set the number of the latest entries kept in a ConfigMap audit log")
+	cmd.Flags().StringVar(&globalConfigMap, "global-config", "", "Set the ConfigMap holding the global configuration, as <namespace>/<name>, its changes are applied without restarting, the defaults are used if not set")
+	cmd.Flags().IntVar(&scalerConcurrency.Limit, "scaler-concurrency-limit", 0, "Set the number of calls of the scalers of a trigger type made at the same time, shared by the operator and the metrics adapters. The calls aren't limited if not set")
+	cmd.Flags().StringVar(&scalerConcurrencyLimits, "scaler-concurrency-limits", "", "Set the concurrency limits of trigger types overriding scaler-concurrency-limit, as comma separated <trigger type>=<limit> pairs")
+	cmd.Flags().StringVar(&scalerConcurrency.Namespace, "scaler-concurrency-namespace", os.Getenv("POD_NAMESPACE"), "Set the namespace of the Leases the operator and the metrics adapters share the scaler concurrency limits through, the namespace of the pod by default. Each process has the whole limits if empty")
 	cmd.Flags().Parse(os.Args)
 
-	kedaProvider := cmd.makeProviderOrDie()
+	shutdownTracing, err := tracing.Init(tracingConfig)
+	if err != nil {
+		logger.Error(err, "unable to set up tracing")
+		return err
+	}
+	defer shutdownTracing()
+
+	shutdownOTLPMetrics, err := prommetrics.InitOTLP(otlpMetricsConfig)
+	if err != nil {
+		logger.Error(err, "unable to set up the OTLP metrics exporter")
+		return err
+	}
+	defer shutdownOTLPMetrics()
+
+	cfg, err := config.GetConfig()
+	if err != nil {
+		logger.Error(err, "failed to get the config")
+		return err
+	}
+	shutdownAudit, err := audit.Init(auditConfig, cfg)
+	if err != nil {
+		logger.Error(err, "unable to set up audit log")
+		return err
+	}
+	defer shutdownAudit()
+
+	globalconfig.BindKlogVerbosity()
+	globalconfig.BindHTTPTimeout()
+	stopGlobalConfig, err := globalconfig.Start(globalConfigMap, cfg)
+	if err != nil {
+		logger.Error(err, "unable to set up global configuration")
+		return err
+	}
+	defer stopGlobalConfig()
+
+	scalerConcurrency.Identity = os.Getenv("POD_NAME")
+	scalerConcurrency.Limits, err = scaling.ParseScalerConcurrencyLimits(scalerConcurrencyLimits)
+	if err == nil {
+		err = scaling.ConfigureScalerConcurrency(scalerConcurrency)
+	}
+	if err != nil {
+		logger.Error(err, "invalid scaler concurrency limits")
+		return err
+	}
+
+	kedaProvider, err := cmd.makeProvider()
+	if err != nil {
+		return err
+	}
 	cmd.WithExternalMetrics(kedaProvider)
 
 	logger.Info(cmd.Message)
 	if err := cmd.Run(wait.NeverStop); err != nil {
 		logger.Error(err, "unable to run external metrics adapter")
-		os.Exit(1)
+		return err
 	}
+	return nil
 }
@@ -0,0 +1,35 @@
+package v1
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+)
+
+// ConvertTo converts the ScaledObject to the v1alpha1 hub
+func (src *ScaledObject) ConvertTo(dstRaw conversion.Hub) error {
+	dst, ok := dstRaw.(*kedav1alpha1.ScaledObject)
+	if !ok {
+		return fmt.Errorf("unexpected type %T of the hub", dstRaw)
+	}
+
+	src.ObjectMeta.DeepCopyInto(&dst.ObjectMeta)
+	src.Spec.ScaledObjectSpec.DeepCopyInto(&dst.Spec)
+	src.Status.DeepCopyInto(&dst.Status)
+	return nil
+}
+
+// ConvertFrom converts the v1alpha1 hub to the ScaledObject
+func (dst *ScaledObject) ConvertFrom(srcRaw conversion.Hub) error {
+	src, ok := srcRaw.(*kedav1alpha1.ScaledObject)
+	if !ok {
+		return fmt.Errorf("unexpected type %T of the hub", srcRaw)
+	}
+
+	src.ObjectMeta.DeepCopyInto(&dst.ObjectMeta)
+	src.Spec.DeepCopyInto(&dst.Spec.ScaledObjectSpec)
+	src.Status.DeepCopyInto(&dst.Status)
+	return nil
+}
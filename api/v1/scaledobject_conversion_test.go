@@ -0,0 +1,56 @@
+package v1
+
+import (
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/conversion"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+)
+
+func TestScaledObjectConversion(t *testing.T) {
+	minReplicaCount := int32(1)
+	scaledObject := &ScaledObject{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "app", Annotations: map[string]string{"team": "orders"}},
+		Spec: ScaledObjectSpec{
+			ScaledObjectSpec: kedav1alpha1.ScaledObjectSpec{
+				ScaleTargetRef:  &kedav1alpha1.ScaleTarget{Name: "app"},
+				MinReplicaCount: &minReplicaCount,
+				Triggers:        []kedav1alpha1.ScaleTriggers{{Type: "prometheus", Name: "queue", Metadata: map[string]string{"threshold": "10"}}},
+			},
+		},
+	}
+
+	hub := &kedav1alpha1.ScaledObject{}
+	if err := scaledObject.ConvertTo(hub); err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	if !reflect.DeepEqual(hub.Spec, scaledObject.Spec.ScaledObjectSpec) || !reflect.DeepEqual(hub.ObjectMeta, scaledObject.ObjectMeta) {
+		t.Errorf("Expected the ScaledObject to be kept, got %+v", hub)
+	}
+
+	converted := &ScaledObject{}
+	if err := converted.ConvertFrom(hub); err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	if !reflect.DeepEqual(converted, scaledObject) {
+		t.Errorf("Expected the ScaledObject to be restored, got %+v", converted)
+	}
+}
+
+func TestScaledObjectIsConvertible(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = kedav1alpha1.AddToScheme(scheme)
+	_ = AddToScheme(scheme)
+
+	convertible, err := conversion.IsConvertible(scheme, &ScaledObject{})
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	if !convertible {
+		t.Error("Expected the ScaledObjects to be convertible")
+	}
+}
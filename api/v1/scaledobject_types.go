@@ -0,0 +1,50 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+)
+
+// +genclient
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:path=scaledobjects,scope=Namespaced,shortName=so
+// +kubebuilder:printcolumn:name="ScaleTargetKind",type="string",JSONPath=".status.scaleTargetKind"
+// +kubebuilder:printcolumn:name="ScaleTargetName",type="string",JSONPath=".spec.scaleTargetRef.name"
+// +kubebuilder:printcolumn:name="Min",type="integer",JSONPath=".status.minReplicas"
+// +kubebuilder:printcolumn:name="Max",type="integer",JSONPath=".status.maxReplicas"
+// +kubebuilder:printcolumn:name="Triggers",type="string",JSONPath=".status.triggers"
+// +kubebuilder:printcolumn:name="Authentication",type="string",JSONPath=".spec.triggers[*].authenticationRef.name"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status"
+// +kubebuilder:printcolumn:name="Active",type="string",JSONPath=".status.conditions[?(@.type==\"Active\")].status"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// ScaledObject is a specification for a ScaledObject resource, the v1alpha1 ScaledObject the controllers reconcile
+// served by keda.sh/v1
+type ScaledObject struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ScaledObjectSpec `json:"spec"`
+	// +optional
+	Status kedav1alpha1.ScaledObjectStatus `json:"status,omitempty"`
+}
+
+// ScaledObjectSpec is the spec for a ScaledObject resource, keda.sh/v1 serves the fields of v1alpha1
+type ScaledObjectSpec struct {
+	kedav1alpha1.ScaledObjectSpec `json:",inline"`
+}
+
+// +kubebuilder:object:root=true
+
+// ScaledObjectList is a list of ScaledObject resources
+type ScaledObjectList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []ScaledObject `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ScaledObject{}, &ScaledObjectList{})
+}
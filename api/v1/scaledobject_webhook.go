@@ -0,0 +1,13 @@
+package v1
+
+import (
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// SetupWebhookWithManager registers the conversion webhook of the ScaledObjects on the /convert path of the webhook server of the manager.
+// The scheme of the manager must contain the v1 and v1alpha1 ScaledObjects
+func (r *ScaledObject) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
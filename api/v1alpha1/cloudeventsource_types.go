@@ -0,0 +1,63 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DefaultCloudEventSourceClusterName is the cluster name used in the source of the CloudEvents if none is defined on the CloudEventSource
+const DefaultCloudEventSourceClusterName = "kubernetes-default"
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:path=cloudeventsources,scope=Namespaced
+// +kubebuilder:printcolumn:name="Destination",type="string",JSONPath=".spec.destination.http.uri"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// CloudEventSource defines a destination the KEDA events of the ScaledObjects and ScaledJobs in the namespace are sent to as CloudEvents
+type CloudEventSource struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec CloudEventSourceSpec `json:"spec"`
+}
+
+// CloudEventSourceSpec is the spec for a CloudEventSource resource
+type CloudEventSourceSpec struct {
+	// +optional
+	ClusterName string      `json:"clusterName,omitempty"`
+	Destination Destination `json:"destination"`
+	// +optional
+	EventSubscription EventSubscription `json:"eventSubscription,omitempty"`
+}
+
+// Destination holds the sink the CloudEvents are sent to
+type Destination struct {
+	// +optional
+	HTTP *CloudEventHTTP `json:"http,omitempty"`
+}
+
+// CloudEventHTTP holds the URI of an HTTP sink, the CloudEvents are sent in structured content mode
+type CloudEventHTTP struct {
+	URI string `json:"uri"`
+}
+
+// EventSubscription filters the types of the CloudEvents sent to the destination,
+// all the types are sent if no type is included
+type EventSubscription struct {
+	// +optional
+	IncludedEventTypes []string `json:"includedEventTypes,omitempty"`
+	// +optional
+	ExcludedEventTypes []string `json:"excludedEventTypes,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// CloudEventSourceList is a list of CloudEventSource resources
+type CloudEventSourceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []CloudEventSource `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&CloudEventSource{}, &CloudEventSourceList{})
+}
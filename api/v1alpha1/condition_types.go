@@ -14,6 +14,17 @@ const (
 	// ConditionActive specifies that the resource has finished.
 	// For resource which run to completion.
 	ConditionActive ConditionType = "Active"
+	// ConditionFallback specifies that some triggers failed on the last check,
+	// the scaling decision falls back to the other triggers.
+	ConditionFallback ConditionType = "Fallback"
+	// ConditionPaused specifies that KEDA doesn't scale the resource.
+	ConditionPaused ConditionType = "Paused"
+	// ConditionVPAConflict specifies that a VerticalPodAutoscaler updates the resources of the pods
+	// of the scale target, its replica count and the resources of its pods may oscillate.
+	ConditionVPAConflict ConditionType = "VPAConflict"
+	// ConditionScaleDownDeferred specifies that a scale down of the scale target is deferred,
+	// as it would violate a PodDisruptionBudget covering its pods.
+	ConditionScaleDownDeferred ConditionType = "ScaleDownDeferred"
 )
 
 // Condition to store the condition state
@@ -33,6 +44,10 @@ type Condition struct {
 	// A human readable message indicating details about the transition.
 	// +optional
 	Message string `json:"message,omitempty" description:"human-readable message indicating details about last transition"`
+
+	// Last time the condition transitioned from one status to another.
+	// +optional
+	LastTransitionTime *metav1.Time `json:"lastTransitionTime,omitempty" description:"last time the condition transitioned from one status to another"`
 }
 
 // Conditions an array representation to store multiple Conditions
@@ -64,7 +79,14 @@ func (c *Conditions) AreInitialized() bool {
 
 // GetInitializedConditions returns Conditions initialized to the default -> Status: Unknown
 func GetInitializedConditions() *Conditions {
-	return &Conditions{{Type: ConditionReady, Status: metav1.ConditionUnknown}, {Type: ConditionActive, Status: metav1.ConditionUnknown}}
+	return &Conditions{
+		{Type: ConditionReady, Status: metav1.ConditionUnknown},
+		{Type: ConditionActive, Status: metav1.ConditionUnknown},
+		{Type: ConditionFallback, Status: metav1.ConditionUnknown},
+		{Type: ConditionPaused, Status: metav1.ConditionUnknown},
+		{Type: ConditionVPAConflict, Status: metav1.ConditionUnknown},
+		{Type: ConditionScaleDownDeferred, Status: metav1.ConditionUnknown},
+	}
 }
 
 // IsTrue is true if the condition is True
@@ -94,7 +116,7 @@ func (c *Condition) IsUnknown() bool {
 // SetReadyCondition modifies Ready Condition according to input parameters
 func (c *Conditions) SetReadyCondition(status metav1.ConditionStatus, reason string, message string) {
 	if *c == nil {
-		c = GetInitializedConditions()
+		*c = *GetInitializedConditions()
 	}
 	c.setCondition(ConditionReady, status, reason, message)
 }
@@ -102,11 +124,51 @@ func (c *Conditions) SetReadyCondition(status metav1.ConditionStatus, reason str
 // SetActiveCondition modifies Active Condition according to input parameters
 func (c *Conditions) SetActiveCondition(status metav1.ConditionStatus, reason string, message string) {
 	if *c == nil {
-		c = GetInitializedConditions()
+		*c = *GetInitializedConditions()
 	}
 	c.setCondition(ConditionActive, status, reason, message)
 }
 
+// SetFallbackCondition modifies Fallback Condition according to input parameters
+func (c *Conditions) SetFallbackCondition(status metav1.ConditionStatus, reason string, message string) {
+	if *c == nil {
+		*c = *GetInitializedConditions()
+	}
+	c.setCondition(ConditionFallback, status, reason, message)
+}
+
+// SetPausedCondition modifies Paused Condition according to input parameters
+func (c *Conditions) SetPausedCondition(status metav1.ConditionStatus, reason string, message string) {
+	if *c == nil {
+		*c = *GetInitializedConditions()
+	}
+	c.setCondition(ConditionPaused, status, reason, message)
+}
+
+// SetVPAConflictCondition modifies VPAConflict Condition according to input parameters
+func (c *Conditions) SetVPAConflictCondition(status metav1.ConditionStatus, reason string, message string) {
+	if *c == nil {
+		*c = *GetInitializedConditions()
+	}
+	c.setCondition(ConditionVPAConflict, status, reason, message)
+}
+
+// SetScaleDownDeferredCondition modifies ScaleDownDeferred Condition according to input parameters
+func (c *Conditions) SetScaleDownDeferredCondition(status metav1.ConditionStatus, reason string, message string) {
+	if *c == nil {
+		*c = *GetInitializedConditions()
+	}
+	c.setCondition(ConditionScaleDownDeferred, status, reason, message)
+}
+
+// GetReadyCondition returns Condition of type Ready
+func (c *Conditions) GetReadyCondition() Condition {
+	if *c == nil {
+		c = GetInitializedConditions()
+	}
+	return c.getCondition(ConditionReady)
+}
+
 // GetActiveCondition returns Condition of type Active
 func (c *Conditions) GetActiveCondition() Condition {
 	if *c == nil {
@@ -115,6 +177,38 @@ func (c *Conditions) GetActiveCondition() Condition {
 	return c.getCondition(ConditionActive)
 }
 
+// GetFallbackCondition returns Condition of type Fallback
+func (c *Conditions) GetFallbackCondition() Condition {
+	if *c == nil {
+		c = GetInitializedConditions()
+	}
+	return c.getCondition(ConditionFallback)
+}
+
+// GetPausedCondition returns Condition of type Paused
+func (c *Conditions) GetPausedCondition() Condition {
+	if *c == nil {
+		c = GetInitializedConditions()
+	}
+	return c.getCondition(ConditionPaused)
+}
+
+// GetVPAConflictCondition returns Condition of type VPAConflict
+func (c *Conditions) GetVPAConflictCondition() Condition {
+	if *c == nil {
+		c = GetInitializedConditions()
+	}
+	return c.getCondition(ConditionVPAConflict)
+}
+
+// GetScaleDownDeferredCondition returns Condition of type ScaleDownDeferred
+func (c *Conditions) GetScaleDownDeferredCondition() Condition {
+	if *c == nil {
+		c = GetInitializedConditions()
+	}
+	return c.getCondition(ConditionScaleDownDeferred)
+}
+
 func (c Conditions) getCondition(conditionType ConditionType) Condition {
 	for i := range c {
 		if c[i].Type == conditionType {
@@ -124,13 +218,21 @@ func (c Conditions) getCondition(conditionType ConditionType) Condition {
 	return Condition{}
 }
 
-func (c Conditions) setCondition(conditionType ConditionType, status metav1.ConditionStatus, reason string, message string) {
-	for i := range c {
-		if c[i].Type == conditionType {
-			c[i].Status = status
-			c[i].Reason = reason
-			c[i].Message = message
-			break
+// setCondition updates the condition, it is added if the Conditions were initialized before the condition type existed
+func (c *Conditions) setCondition(conditionType ConditionType, status metav1.ConditionStatus, reason string, message string) {
+	for i := range *c {
+		condition := &(*c)[i]
+		if condition.Type == conditionType {
+			if condition.Status != status || condition.LastTransitionTime == nil {
+				now := metav1.Now()
+				condition.LastTransitionTime = &now
+			}
+			condition.Status = status
+			condition.Reason = reason
+			condition.Message = message
+			return
 		}
 	}
+	now := metav1.Now()
+	*c = append(*c, Condition{Type: conditionType, Status: status, Reason: reason, Message: message, LastTransitionTime: &now})
 }
@@ -0,0 +1,81 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DefaultHTTPInterceptorAddress is the address of the interceptor admin service used if none is defined on the HTTPScaledObject
+const DefaultHTTPInterceptorAddress = "http://keda-http-interceptor-admin.keda:9090"
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:path=httpscaledobjects,scope=Namespaced,shortName=httpso
+// +kubebuilder:printcolumn:name="ScaleTargetName",type="string",JSONPath=".spec.scaleTargetRef.name"
+// +kubebuilder:printcolumn:name="Host",type="string",JSONPath=".spec.host"
+// +kubebuilder:printcolumn:name="ScaledObject",type="string",JSONPath=".status.scaledObjectName"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// HTTPScaledObject is a specification for an HTTP service scaled on the requests pending in the interceptor
+type HTTPScaledObject struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec HTTPScaledObjectSpec `json:"spec"`
+	// +optional
+	Status HTTPScaledObjectStatus `json:"status,omitempty"`
+}
+
+// HTTPScaledObjectSpec is the spec for a HTTPScaledObject resource
+type HTTPScaledObjectSpec struct {
+	// Host the interceptor routes to the scale target
+	Host           string           `json:"host"`
+	ScaleTargetRef *HTTPScaleTarget `json:"scaleTargetRef"`
+	// +optional
+	InterceptorAddress string `json:"interceptorAddress,omitempty"`
+	// +optional
+	TargetPendingRequests *int32 `json:"targetPendingRequests,omitempty"`
+	// +optional
+	PollingInterval *int32 `json:"pollingInterval,omitempty"`
+	// +optional
+	CooldownPeriod *int32 `json:"cooldownPeriod,omitempty"`
+	// +optional
+	MinReplicaCount *int32 `json:"minReplicaCount,omitempty"`
+	// +optional
+	MaxReplicaCount *int32 `json:"maxReplicaCount,omitempty"`
+}
+
+// HTTPScaleTarget holds the reference to the scale target and the service the interceptor forwards requests to
+type HTTPScaleTarget struct {
+	Name string `json:"name"`
+	// +optional
+	APIVersion string `json:"apiVersion,omitempty"`
+	// +optional
+	Kind string `json:"kind,omitempty"`
+	// Service the interceptor forwards the requests of the host to, it must exist in the namespace of the HTTPScaledObject
+	Service string `json:"service"`
+	// Port of the Service the requests are forwarded to, it must be exposed by the Service
+	Port int32 `json:"port"`
+}
+
+// HTTPScaledObjectStatus is the status for a HTTPScaledObject resource
+// +optional
+type HTTPScaledObjectStatus struct {
+	// +optional
+	ScaledObjectName string `json:"scaledObjectName,omitempty"`
+	// +optional
+	Conditions Conditions `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// HTTPScaledObjectList is a list of HTTPScaledObject resources
+type HTTPScaledObjectList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []HTTPScaledObject `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&HTTPScaledObject{}, &HTTPScaledObjectList{})
+}
@@ -9,7 +9,9 @@ import (
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
 // +kubebuilder:resource:path=scaledjobs,scope=Namespaced,shortName=sj
-// +kubebuilder:printcolumn:name="Triggers",type="string",JSONPath=".spec.triggers[*].type"
+// +kubebuilder:printcolumn:name="Min",type="integer",JSONPath=".status.minReplicas"
+// +kubebuilder:printcolumn:name="Max",type="integer",JSONPath=".status.maxReplicas"
+// +kubebuilder:printcolumn:name="Triggers",type="string",JSONPath=".status.triggers"
 // +kubebuilder:printcolumn:name="Authentication",type="string",JSONPath=".spec.triggers[*].authenticationRef.name"
 // +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status"
 // +kubebuilder:printcolumn:name="Active",type="string",JSONPath=".status.conditions[?(@.type==\"Active\")].status"
@@ -35,9 +37,30 @@ type ScaledJobSpec struct {
 	FailedJobsHistoryLimit *int32 `json:"failedJobsHistoryLimit,omitempty"`
 	// +optional
 	EnvSourceContainerName string `json:"envSourceContainerName,omitempty"`
+	// MinReplicaCount is the number of Jobs kept running even if the triggers are inactive, a Job is started when one of them finishes
+	// +kubebuilder:validation:Minimum=0
 	// +optional
-	MaxReplicaCount *int32          `json:"maxReplicaCount,omitempty"`
-	Triggers        []ScaleTriggers `json:"triggers"`
+	MinReplicaCount *int32 `json:"minReplicaCount,omitempty"`
+	// +optional
+	MaxReplicaCount *int32 `json:"maxReplicaCount,omitempty"`
+	// +optional
+	ScalingStrategy *ScalingStrategy `json:"scalingStrategy,omitempty"`
+	Triggers        []ScaleTriggers  `json:"triggers"`
+}
+
+// ScalingStrategy defines how the number of Jobs to start is computed from the queue length and the running Jobs
+type ScalingStrategy struct {
+	// Strategy is default to start Jobs for the queue length within maxReplicaCount running Jobs, runningJobs if the queue length
+	// includes the messages locked by the running Jobs, e.g. the locked messages of Service Bus, so the running Jobs are deducted,
+	// or pendingJobs if the messages are hidden once received, e.g. the visibility timeout of SQS, so only the Jobs whose pod
+	// didn't start yet are deducted
+	// +kubebuilder:validation:Enum=default;runningJobs;pendingJobs
+	// +optional
+	Strategy string `json:"strategy,omitempty"`
+	// PendingPodConditions are the conditions the pod of a Job must have for the Job to be started with the pendingJobs strategy,
+	// e.g. Ready, a Job is started once its pod is running or completed if not set
+	// +optional
+	PendingPodConditions []string `json:"pendingPodConditions,omitempty"`
 }
 
 // ScaledJobStatus defines the observed state of ScaledJob
@@ -45,8 +68,20 @@ type ScaledJobSpec struct {
 type ScaledJobStatus struct {
 	// +optional
 	LastActiveTime *metav1.Time `json:"lastActiveTime,omitempty"`
+	// MinReplicas is the number of Jobs kept running
+	// +optional
+	MinReplicas *int32 `json:"minReplicas,omitempty"`
+	// MaxReplicas is the maximum number of Jobs running at the same time
+	// +optional
+	MaxReplicas *int32 `json:"maxReplicas,omitempty"`
+	// Triggers is a summary of the trigger types, e.g. cpu,prometheus(2)
+	// +optional
+	Triggers string `json:"triggers,omitempty"`
 	// +optional
 	Conditions Conditions `json:"conditions,omitempty"`
+	// TriggersActivity holds the last time each trigger was active, it is only kept if a trigger defines its own cooldownPeriod
+	// +optional
+	TriggersActivity []TriggerActivityStatus `json:"triggersActivity,omitempty"`
 }
 
 // ScaledJobList contains a list of ScaledJob
@@ -61,6 +96,15 @@ func init() {
 	SchemeBuilder.Register(&ScaledJob{}, &ScaledJobList{})
 }
 
+// MinReplicaCount returns MinReplicaCount, 0 if not set
+func (s ScaledJob) MinReplicaCount() int64 {
+	if s.Spec.MinReplicaCount != nil {
+		return int64(*s.Spec.MinReplicaCount)
+	}
+
+	return 0
+}
+
 // MaxReplicaCount returns MaxReplicaCount
 func (s ScaledJob) MaxReplicaCount() int64 {
 	if s.Spec.MaxReplicaCount != nil {
@@ -0,0 +1,5 @@
+package v1alpha1
+
+// Hub marks the v1alpha1 ScaledObject as the version the other versions are converted to and from,
+// it is the version the controllers reconcile
+func (*ScaledObject) Hub() {}
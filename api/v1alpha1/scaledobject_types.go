@@ -1,17 +1,23 @@
 package v1alpha1
 
 import (
+	"fmt"
+
 	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // +genclient
 // +kubebuilder:object:root=true
+// +kubebuilder:storageversion
 // +kubebuilder:subresource:status
 // +kubebuilder:resource:path=scaledobjects,scope=Namespaced,shortName=so
 // +kubebuilder:printcolumn:name="ScaleTargetKind",type="string",JSONPath=".status.scaleTargetKind"
 // +kubebuilder:printcolumn:name="ScaleTargetName",type="string",JSONPath=".spec.scaleTargetRef.name"
-// +kubebuilder:printcolumn:name="Triggers",type="string",JSONPath=".spec.triggers[*].type"
+// +kubebuilder:printcolumn:name="Min",type="integer",JSONPath=".status.minReplicas"
+// +kubebuilder:printcolumn:name="Max",type="integer",JSONPath=".status.maxReplicas"
+// +kubebuilder:printcolumn:name="Triggers",type="string",JSONPath=".status.triggers"
 // +kubebuilder:printcolumn:name="Authentication",type="string",JSONPath=".spec.triggers[*].authenticationRef.name"
 // +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status"
 // +kubebuilder:printcolumn:name="Active",type="string",JSONPath=".status.conditions[?(@.type==\"Active\")].status"
@@ -27,13 +33,73 @@ type ScaledObject struct {
 	Status ScaledObjectStatus `json:"status,omitempty"`
 }
 
+// The annotations of a ScaledObject scaling a StatefulSet defining the hook called on the pods before they are removed, either by KEDA
+// scaling the StatefulSet to zero or by the HPA scaling it down. The HPA is kept from removing the pods until their hook succeeded.
+const (
+	// PreScaleDownHookAnnotation is the URL of the HTTP hook the pods are sent a POST request to, e.g. http://:8080/drain,
+	// the host is replaced by the IP of the pod. The scale down is postponed until the hooks respond with a 2xx status,
+	// the hooks are called again on the next check, so they must be idempotent
+	PreScaleDownHookAnnotation = "scaledobject.keda.sh/pre-scale-down-hook"
+	// PreScaleDownExecHookAnnotation is the command of the exec hook run with /bin/sh -c in a container of the pods instead of the HTTP hook,
+	// e.g. /opt/consumer/drain.sh. The scale down is postponed until the command exits with 0
+	PreScaleDownExecHookAnnotation = "scaledobject.keda.sh/pre-scale-down-exec"
+	// PreScaleDownExecContainerAnnotation is the container the exec hook runs in, the first container of the pod if not set
+	PreScaleDownExecContainerAnnotation = "scaledobject.keda.sh/pre-scale-down-exec-container"
+	// PreScaleDownHookTimeoutAnnotation is the timeout of the hook of a pod, e.g. 30s, 10s if not set
+	PreScaleDownHookTimeoutAnnotation = "scaledobject.keda.sh/pre-scale-down-hook-timeout"
+)
+
+// RecommendedReplicasAnnotation is the annotation the replica count recommended for the ScaleTarget is written to
+// on a ScaledObject in recommendation mode, for the autoscaler scaling the ScaleTarget
+const RecommendedReplicasAnnotation = "scaledobject.keda.sh/recommended-replicas"
+
+// The labels of the selector of the External metrics in the HPA of a ScaledObject, they identify the ScaledObject and the trigger
+// serving a metric so the metrics server doesn't rely on the metric names being unique
+const (
+	// ScaledObjectNameLabel is the name of the ScaledObject, it is also set on the ScaledObject itself
+	ScaledObjectNameLabel = "scaledObjectName"
+	// ScaledObjectNamespaceLabel is the namespace of the ScaledObject, it must be the namespace of the HPA
+	ScaledObjectNamespaceLabel = "scaledObjectNamespace"
+	// TriggerIndexLabel is the index of the trigger of the ScaledObject serving the metric
+	TriggerIndexLabel = "scaledObjectTriggerIndex"
+)
+
 // ScaledObjectSpec is the spec for a ScaledObject resource
 type ScaledObjectSpec struct {
 	ScaleTargetRef *ScaleTarget `json:"scaleTargetRef"`
 	// +optional
 	PollingInterval *int32 `json:"pollingInterval,omitempty"`
+	// ActivationPollingInterval is the time in seconds between the checks of the triggers while the ScaleTarget is scaled to zero,
+	// the pollingInterval applies once it is activated. It is ignored if it isn't shorter than the pollingInterval
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	ActivationPollingInterval *int32 `json:"activationPollingInterval,omitempty"`
 	// +optional
 	CooldownPeriod *int32 `json:"cooldownPeriod,omitempty"`
+	// ScaleToZeroGracePeriod is the time in seconds the triggers must be inactive without interruption before the ScaleTarget
+	// is scaled to zero, unlike the cooldownPeriod it also applies if the triggers were never seen active
+	// +optional
+	ScaleToZeroGracePeriod *int32 `json:"scaleToZeroGracePeriod,omitempty"`
+	// InitialCooldownPeriod is the time in seconds after the creation of the ScaledObject during which the ScaleTarget isn't scaled to zero
+	// +optional
+	InitialCooldownPeriod *int32 `json:"initialCooldownPeriod,omitempty"`
+	// ScaleToZeroSchedule restricts the scaling to zero to its windows, outside of them the ScaleTarget is kept at minReplicaCount,
+	// at least 1 replica, even if the triggers are inactive
+	// +optional
+	ScaleToZeroSchedule *ScaleToZeroSchedule `json:"scaleToZeroSchedule,omitempty"`
+	// WaitForZeroQueueBeforeScaleToZero is the number of consecutive checks the metrics of the inactive triggers must be zero
+	// before the ScaleTarget is scaled to zero, so the consumers have processed all the messages
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	WaitForZeroQueueBeforeScaleToZero *int32 `json:"waitForZeroQueueBeforeScaleToZero,omitempty"`
+	// ScaleWebhooks are the HTTP webhooks called before the ScaleTarget is scaled from zero and after it is scaled to zero,
+	// so the application can warm its caches or flush its state around these transitions
+	// +optional
+	ScaleWebhooks *ScaleWebhooks `json:"scaleWebhooks,omitempty"`
+	// ActiveDuringRollout keeps the ScaleTarget active while its Deployment, StatefulSet or Argo Rollout rolls out new pods,
+	// so the cooldown doesn't start and the ScaleTarget isn't scaled to zero in the middle of the rollout of an update
+	// +optional
+	ActiveDuringRollout bool `json:"activeDuringRollout,omitempty"`
 	// +optional
 	MinReplicaCount *int32 `json:"minReplicaCount,omitempty"`
 	// +optional
@@ -50,6 +116,32 @@ type AdvancedConfig struct {
 	HorizontalPodAutoscalerConfig *HorizontalPodAutoscalerConfig `json:"horizontalPodAutoscalerConfig,omitempty"`
 	// +optional
 	RestoreToOriginalReplicaCount bool `json:"restoreToOriginalReplicaCount,omitempty"`
+	// DryRun evaluates the triggers and records the result in the status, without creating the HPA or scaling the ScaleTarget.
+	// An HPA created before enabling the dry-run mode, or not managed by KEDA, is left untouched and keeps scaling the ScaleTarget,
+	// it is named in the Ready condition and reported with an event.
+	// +optional
+	DryRun bool `json:"dryRun,omitempty"`
+	// Recommend evaluates the triggers as the dry-run mode does and writes the replica count KEDA and the HPA would scale the ScaleTarget to
+	// in the scaledobject.keda.sh/recommended-replicas annotation of the ScaledObject, for another autoscaler like the Knative one or a custom
+	// controller to scale the ScaleTarget. Neither the HPA is created nor the ScaleTarget scaled.
+	// +optional
+	Recommend bool `json:"recommend,omitempty"`
+	// OrphanHPA leaves the HPA in place when the ScaledObject is deleted, the HPA is no longer owned by the ScaledObject
+	// and the replica count of the ScaleTarget isn't restored, so the HPA can be managed by hand without a change of replicas.
+	// +optional
+	OrphanHPA bool `json:"orphanHPA,omitempty"`
+	// ActivationOnlyWithVPA restricts KEDA to activating and deactivating the ScaleTarget while a VerticalPodAutoscaler updates the resources
+	// of its pods, the HPA keeps the ScaleTarget at its minimum replica count so the replicas and the resources don't oscillate.
+	// +optional
+	ActivationOnlyWithVPA bool `json:"activationOnlyWithVPA,omitempty"`
+	// DisablePartitionReplicaCap lets the HPA scale beyond the partition count when all the triggers driving it read partitioned streams,
+	// the maximum replica count is capped at the partition or shard count otherwise as the replicas beyond it are idle
+	// +optional
+	DisablePartitionReplicaCap bool `json:"disablePartitionReplicaCap,omitempty"`
+	// DisablePodDisruptionBudgetCheck scales the ScaleTarget to zero and lowers the MinReplicas of the HPA even if the replicas left
+	// don't meet the minAvailable of a PodDisruptionBudget covering its pods, these scale downs are deferred otherwise
+	// +optional
+	DisablePodDisruptionBudgetCheck bool `json:"disablePodDisruptionBudgetCheck,omitempty"`
 }
 
 // HorizontalPodAutoscalerConfig specifies horizontal scale config
@@ -78,6 +170,101 @@ type ScaleTriggers struct {
 	Metadata map[string]string `json:"metadata"`
 	// +optional
 	AuthenticationRef *ScaledObjectAuthRef `json:"authenticationRef,omitempty"`
+	// UseCachedMetrics enables the metrics server to serve the HPA a recent metric value of the trigger instead of querying the scaler on every request
+	// +optional
+	UseCachedMetrics bool `json:"useCachedMetrics,omitempty"`
+	// MetricsCacheTTL is the time in seconds a cached metric value is served, the pollingInterval is used if not set
+	// +optional
+	MetricsCacheTTL *int32 `json:"metricsCacheTTL,omitempty"`
+	// ThresholdType is the type of the target of the metric in the HPA: AverageValue, the default, divides the metric value by the replica count
+	// before comparing it to the threshold, Value compares the metric value itself to the threshold. Utilization, only supported by the cpu and
+	// memory triggers, compares the average utilization of the pods in percent of their requests to the threshold, the cpu and memory triggers
+	// don't support Value. The thresholdType sets the type of the cpu and memory triggers without one.
+	// Only the triggers of ScaledObjects can set it, the ScaledJobs don't have an HPA.
+	// +kubebuilder:validation:Enum=AverageValue;Value;Utilization
+	// +optional
+	ThresholdType autoscalingv2beta2.MetricTargetType `json:"thresholdType,omitempty"`
+	// UseForActivationOnly excludes the metric of the trigger of a ScaledObject from the HPA, the trigger only activates and deactivates the ScaleTarget
+	// +optional
+	UseForActivationOnly bool `json:"useForActivationOnly,omitempty"`
+	// Enabled set to false disables the trigger without removing it from the spec, the trigger is neither checked nor used by the HPA
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+	// DesiredReplicas maps ranges of the metric value of the trigger to a replica count, the step with the greatest from lower than
+	// or equal to the metric value gives the replicas, 0 below the first step. The HPA then targets these replicas, so thresholdType is ignored
+	// +optional
+	DesiredReplicas []DesiredReplicasStep `json:"desiredReplicas,omitempty"`
+	// CooldownPeriod is the time in seconds the trigger keeps the ScaledObject or ScaledJob active after the trigger was last active,
+	// it overrides the cooldownPeriod of the ScaledObject for this trigger. The triggers of a ScaledJob don't cool down if not set
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	CooldownPeriod *int32 `json:"cooldownPeriod,omitempty"`
+	// RenderMetadataTemplates renders the metadata values containing {{ as Go templates executed against the ScaledObject or ScaledJob,
+	// e.g. {{.ScaledObject.Namespace}}. The values are used as they are if not set
+	// +optional
+	RenderMetadataTemplates bool `json:"renderMetadataTemplates,omitempty"`
+}
+
+// ScaleToZeroSchedule holds the windows the ScaleTarget of a ScaledObject can be scaled to zero in
+type ScaleToZeroSchedule struct {
+	// Timezone is the IANA timezone of the schedules of the windows, e.g. Europe/Paris, UTC if not set
+	// +optional
+	Timezone string `json:"timezone,omitempty"`
+	// +kubebuilder:validation:MinItems=1
+	Windows []ScheduleWindow `json:"windows"`
+}
+
+// ScheduleWindow is the time between two cron schedules, e.g. from 0 19 * * 1-5 to 0 7 * * 2-6 for the nights of the working days
+type ScheduleWindow struct {
+	// Start is the cron schedule of the start of the window in the standard format
+	Start string `json:"start"`
+	// End is the cron schedule of the end of the window in the standard format
+	End string `json:"end"`
+}
+
+// ScaleWebhooks holds the webhooks called around the activation and the deactivation of the ScaleTarget of a ScaledObject
+type ScaleWebhooks struct {
+	// PreActivation is called before the ScaleTarget is scaled from zero to its first replicas
+	// +optional
+	PreActivation *ScaleWebhook `json:"preActivation,omitempty"`
+	// PostDeactivation is called once the ScaleTarget is scaled to zero
+	// +optional
+	PostDeactivation *ScaleWebhook `json:"postDeactivation,omitempty"`
+}
+
+// ScaleWebhookFailurePolicy defines how KEDA handles a webhook failing or not responding within its timeout
+type ScaleWebhookFailurePolicy string
+
+const (
+	// ScaleWebhookFailurePolicyIgnore scales the ScaleTarget regardless of the failure, it is reported in an event
+	ScaleWebhookFailurePolicyIgnore ScaleWebhookFailurePolicy = "Ignore"
+	// ScaleWebhookFailurePolicyFail calls the webhook again on the next checks until it succeeds, the activation of the ScaleTarget
+	// is postponed until the preActivation webhook succeeds
+	ScaleWebhookFailurePolicyFail ScaleWebhookFailurePolicy = "Fail"
+)
+
+// ScaleWebhook is an HTTP endpoint KEDA sends a POST request with the ScaledObject and the transition of its ScaleTarget to,
+// it must respond with a 2xx status within its timeout
+type ScaleWebhook struct {
+	// URL is the http or https URL of the webhook
+	URL string `json:"url"`
+	// TimeoutSeconds is the time in seconds the webhook must respond within, 10 if not set
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TimeoutSeconds *int32 `json:"timeoutSeconds,omitempty"`
+	// FailurePolicy is Ignore or Fail, Ignore if not set
+	// +kubebuilder:validation:Enum=Ignore;Fail
+	// +optional
+	FailurePolicy ScaleWebhookFailurePolicy `json:"failurePolicy,omitempty"`
+}
+
+// DesiredReplicasStep is a step of the mapping of the metric value of a trigger to a replica count
+type DesiredReplicasStep struct {
+	// From is the lowest metric value of the step
+	From resource.Quantity `json:"from"`
+	// Replicas is the replica count while the metric value is in the step
+	// +kubebuilder:validation:Minimum=0
+	Replicas int32 `json:"replicas"`
 }
 
 // ScaledObjectStatus is the status for a ScaledObject resource
@@ -91,10 +278,109 @@ type ScaledObjectStatus struct {
 	OriginalReplicaCount *int32 `json:"originalReplicaCount,omitempty"`
 	// +optional
 	LastActiveTime *metav1.Time `json:"lastActiveTime,omitempty"`
+	// InactiveSince is the time the triggers were first seen inactive since they were last active, it is only set if a scaleToZeroGracePeriod is defined
+	// +optional
+	InactiveSince *metav1.Time `json:"inactiveSince,omitempty"`
+	// ZeroQueueChecks is the number of consecutive checks the metrics of the triggers were zero, it is only set if waitForZeroQueueBeforeScaleToZero is defined
+	// +optional
+	ZeroQueueChecks int32 `json:"zeroQueueChecks,omitempty"`
 	// +optional
 	ExternalMetricNames []string `json:"externalMetricNames,omitempty"`
+	// MinReplicas is the replica count the ScaleTarget is scaled to when the triggers aren't active
+	// +optional
+	MinReplicas *int32 `json:"minReplicas,omitempty"`
+	// MaxReplicas is the maximum replica count of the ScaleTarget
+	// +optional
+	MaxReplicas *int32 `json:"maxReplicas,omitempty"`
+	// PartitionReplicaCap is the partition or shard count of the triggers the maximum replica count of the HPA is capped at
+	// +optional
+	PartitionReplicaCap *int32 `json:"partitionReplicaCap,omitempty"`
+	// Triggers is a summary of the trigger types, e.g. cpu,prometheus(2)
+	// +optional
+	Triggers string `json:"triggers,omitempty"`
 	// +optional
 	Conditions Conditions `json:"conditions,omitempty"`
+	// +optional
+	DryRun *DryRunStatus `json:"dryRun,omitempty"`
+	// ExternalScalers holds the state of the gRPC connections to the external scalers of the triggers
+	// +optional
+	ExternalScalers []ExternalScalerStatus `json:"externalScalers,omitempty"`
+	// TriggersActivity holds the last time each trigger was active, it is only kept if a trigger defines its own cooldownPeriod
+	// +optional
+	TriggersActivity []TriggerActivityStatus `json:"triggersActivity,omitempty"`
+	// RolloutCanary holds the replica split of the Argo Rollout ScaleTarget while its canary is in progress,
+	// the ScaleTarget isn't scaled below the largest replica count it had during the canary until the canary completes
+	// +optional
+	RolloutCanary *RolloutCanaryStatus `json:"rolloutCanary,omitempty"`
+	// PostDeactivationWebhookPending is true while the postDeactivation webhook with the Fail policy hasn't succeeded
+	// since the ScaleTarget was scaled to zero, it is called again on the next checks
+	// +optional
+	PostDeactivationWebhookPending bool `json:"postDeactivationWebhookPending,omitempty"`
+	// ScaledToZero is true once the ScaleTarget was scaled to zero by KEDA, until it has replicas again
+	// +optional
+	ScaledToZero bool `json:"scaledToZero,omitempty"`
+	// PreScaleDownHookMinReplicas is the replica count the HPA doesn't scale the StatefulSet ScaleTarget below while it has a pre-scale-down hook,
+	// the pods above it already ran their hook
+	// +optional
+	PreScaleDownHookMinReplicas *int32 `json:"preScaleDownHookMinReplicas,omitempty"`
+}
+
+// TriggerActivityStatus holds the last time a trigger was active
+type TriggerActivityStatus struct {
+	// Index is the position of the trigger in the triggers of the spec
+	Index          int         `json:"index"`
+	Type           string      `json:"type"`
+	LastActiveTime metav1.Time `json:"lastActiveTime"`
+}
+
+// ExternalScalerStatus holds the state of the gRPC connection to an external scaler
+type ExternalScalerStatus struct {
+	ScalerAddress string `json:"scalerAddress"`
+	// State is the gRPC connectivity state after the last call, e.g. READY or TRANSIENT_FAILURE
+	State string `json:"state"`
+	// +optional
+	LastTransitionTime *metav1.Time `json:"lastTransitionTime,omitempty"`
+	// Message is the error of the last call if it failed
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// RolloutCanaryStatus holds the replica split of the canary of an Argo Rollout
+type RolloutCanaryStatus struct {
+	// StableReplicas is the replica count of the stable ReplicaSet of the Rollout
+	StableReplicas int32 `json:"stableReplicas"`
+	// CanaryReplicas is the replica count of the canary ReplicaSet of the Rollout
+	CanaryReplicas int32 `json:"canaryReplicas"`
+	// MinReplicas is the replica count the HPA doesn't scale the Rollout below until the canary completes
+	MinReplicas int32 `json:"minReplicas"`
+}
+
+// DryRunStatus holds the result of the latest evaluation of the triggers of a ScaledObject in dry-run mode
+type DryRunStatus struct {
+	// +optional
+	LastEvaluationTime *metav1.Time `json:"lastEvaluationTime,omitempty"`
+	Active             bool         `json:"active"`
+	// DesiredReplicas is the replica count KEDA and the HPA would scale the ScaleTarget to
+	DesiredReplicas int32 `json:"desiredReplicas"`
+	// +optional
+	Triggers []DryRunTriggerStatus `json:"triggers,omitempty"`
+}
+
+// DryRunTriggerStatus holds the result of the evaluation of a single trigger in dry-run mode
+type DryRunTriggerStatus struct {
+	Type string `json:"type"`
+	// +optional
+	MetricName string `json:"metricName,omitempty"`
+	Active     bool   `json:"active"`
+	// +optional
+	MetricValue int64 `json:"metricValue,omitempty"`
+	// +optional
+	Threshold int64 `json:"threshold,omitempty"`
+	// DesiredReplicas is the replica count for the metric value, it is only estimated for the AverageValue threshold type
+	// +optional
+	DesiredReplicas int64 `json:"desiredReplicas,omitempty"`
+	// +optional
+	Error string `json:"error,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -115,3 +401,61 @@ type ScaledObjectAuthRef struct {
 func init() {
 	SchemeBuilder.Register(&ScaledObject{}, &ScaledObjectList{})
 }
+
+// GetThresholdType returns the type of the target of the metric of the trigger in the HPA,
+// the replicas mapped by DesiredReplicas are always an average value
+func (t *ScaleTriggers) GetThresholdType() autoscalingv2beta2.MetricTargetType {
+	if t.ThresholdType == "" || len(t.DesiredReplicas) > 0 {
+		return autoscalingv2beta2.AverageValueMetricType
+	}
+	return t.ThresholdType
+}
+
+// IsResourceTrigger returns true for the cpu and memory triggers, their metric is a Resource metric of the HPA served by the Kubernetes metrics server
+func (t *ScaleTriggers) IsResourceTrigger() bool {
+	return t.Type == "cpu" || t.Type == "memory"
+}
+
+// ValidateThresholdType returns an error if the thresholdType isn't supported by the metric of the trigger
+func (t *ScaleTriggers) ValidateThresholdType() error {
+	switch {
+	case t.ThresholdType == "":
+		return nil
+	case t.IsResourceTrigger():
+		if t.ThresholdType == autoscalingv2beta2.ValueMetricType {
+			return fmt.Errorf("thresholdType %s isn't supported by the %s triggers, the HPA targets their Utilization or AverageValue", t.ThresholdType, t.Type)
+		}
+		if targetType := t.Metadata["type"]; targetType != "" && targetType != string(t.ThresholdType) {
+			return fmt.Errorf("thresholdType %s doesn't match the type %s of the %s trigger", t.ThresholdType, targetType, t.Type)
+		}
+	case t.ThresholdType == autoscalingv2beta2.UtilizationMetricType:
+		return fmt.Errorf("thresholdType %s is only supported by the cpu and memory triggers", t.ThresholdType)
+	}
+	return nil
+}
+
+// IsEnabled returns true if the trigger is enabled, the triggers are enabled by default
+func (t *ScaleTriggers) IsEnabled() bool {
+	return t.Enabled == nil || *t.Enabled
+}
+
+// IsOrphaningHPA returns true if the HPA is left in place when the ScaledObject is deleted
+func (s *ScaledObject) IsOrphaningHPA() bool {
+	return s.Spec.Advanced != nil && s.Spec.Advanced.OrphanHPA
+}
+
+// IsDryRun returns true if the triggers of the ScaledObject are only evaluated, without scaling the ScaleTarget,
+// in dry-run or recommendation mode
+func (s *ScaledObject) IsDryRun() bool {
+	return s.Spec.Advanced != nil && (s.Spec.Advanced.DryRun || s.Spec.Advanced.Recommend)
+}
+
+// IsRecommending returns true if the replica count recommended by the triggers of the ScaledObject is written for another autoscaler
+func (s *ScaledObject) IsRecommending() bool {
+	return s.Spec.Advanced != nil && s.Spec.Advanced.Recommend
+}
+
+// IsCheckingPodDisruptionBudgets returns true if the scale downs of the ScaleTarget violating a PodDisruptionBudget are deferred
+func (s *ScaledObject) IsCheckingPodDisruptionBudgets() bool {
+	return s.Spec.Advanced == nil || !s.Spec.Advanced.DisablePodDisruptionBudgetCheck
+}
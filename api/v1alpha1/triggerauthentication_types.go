@@ -32,6 +32,12 @@ type TriggerAuthenticationSpec struct {
 
 	// +optional
 	HashiCorpVault *HashiCorpVault `json:"hashiCorpVault,omitempty"`
+
+	// +optional
+	Conjur *Conjur `json:"conjur,omitempty"`
+
+	// +optional
+	SecretProviders []AuthSecretProviderRef `json:"secretProviders,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -49,12 +55,13 @@ type PodIdentityProvider string
 // PodIdentityProviderNone specifies the default state when there is no Identity Provider
 // PodIdentityProvider<IDENTITY_PROVIDER> specifies other available Identity providers
 const (
-	PodIdentityProviderNone    PodIdentityProvider = "none"
-	PodIdentityProviderAzure                       = "azure"
-	PodIdentityProviderGCP                         = "gcp"
-	PodIdentityProviderSpiffe                      = "spiffe"
-	PodIdentityProviderAwsEKS                      = "aws-eks"
-	PodIdentityProviderAwsKiam                     = "aws-kiam"
+	PodIdentityProviderNone          PodIdentityProvider = "none"
+	PodIdentityProviderAzure                             = "azure"
+	PodIdentityProviderAzureWorkload                     = "azure-workload"
+	PodIdentityProviderGCP                               = "gcp"
+	PodIdentityProviderSpiffe                            = "spiffe"
+	PodIdentityProviderAwsEKS                            = "aws-eks"
+	PodIdentityProviderAwsKiam                           = "aws-kiam"
 )
 
 // PodIdentityAnnotationEKS specifies aws role arn for aws-eks Identity Provider
@@ -68,6 +75,10 @@ const (
 // mechanism
 type AuthPodIdentity struct {
 	Provider PodIdentityProvider `json:"provider"`
+	// RoleArn is the AWS role assumed by the aws-eks and aws-kiam providers instead of the role of the scale target,
+	// so the triggers of an object referencing different TriggerAuthentications can assume different roles
+	// +optional
+	RoleArn string `json:"roleArn,omitempty"`
 }
 
 // AuthSecretTargetRef is used to authenticate using a reference to a secret
@@ -129,6 +140,87 @@ type VaultSecret struct {
 	Key       string `json:"key"`
 }
 
+// Conjur is used to authenticate using the variables of CyberArk Conjur
+type Conjur struct {
+	// ApplianceURL is the URL of the Conjur server, e.g. https://conjur.example.com
+	ApplianceURL   string               `json:"applianceURL"`
+	Account        string               `json:"account"`
+	Authentication ConjurAuthentication `json:"authentication"`
+	Secrets        []ConjurSecret       `json:"secrets"`
+
+	// +optional
+	Credential *ConjurCredential `json:"credential,omitempty"`
+
+	// ServiceID is the service ID of the authn-jwt or authn-k8s authenticator
+	// +optional
+	ServiceID string `json:"serviceID,omitempty"`
+
+	// CACert is the PEM encoded CA certificate of the Conjur server, the CAs of the system are used if empty
+	// +optional
+	CACert string `json:"caCert,omitempty"`
+}
+
+// ConjurCredential defines the Conjur credentials depending on the authentication method
+type ConjurCredential struct {
+	// HostID is the login of the host identity, e.g. host/keda/operator
+	// +optional
+	HostID string `json:"hostID,omitempty"`
+
+	// APIKey is the API key of the host identity, read from a Secret in the namespace of the TriggerAuthentication
+	// +optional
+	APIKey *ConjurAPIKey `json:"apiKey,omitempty"`
+
+	// ServiceAccount is the path of the JWT of the KEDA pods, e.g. a projected service account token,
+	// it must be in one of the secretFileDirectories of the global configuration
+	// +optional
+	ServiceAccount string `json:"serviceAccount,omitempty"`
+}
+
+// ConjurAPIKey references the API key of a Conjur host identity
+type ConjurAPIKey struct {
+	SecretKeyRef ConjurSecretKeyRef `json:"secretKeyRef"`
+}
+
+// ConjurSecretKeyRef references a key of a Secret in the namespace of the TriggerAuthentication
+type ConjurSecretKeyRef struct {
+	Name string `json:"name"`
+	Key  string `json:"key"`
+}
+
+// ConjurAuthentication contains the list of Conjur authentication methods
+type ConjurAuthentication string
+
+// Client authenticating to Conjur
+const (
+	ConjurAuthenticationAPIKey ConjurAuthentication = "apiKey"
+	ConjurAuthenticationJWT    ConjurAuthentication = "jwt"
+	// ConjurAuthenticationKubernetes is the authn-k8s authentication, Conjur injects a client certificate into the container
+	// of the KEDA pod named by its conjur.org/container-name annotation, which must share /etc/conjur/ssl with the KEDA container
+	ConjurAuthenticationKubernetes ConjurAuthentication = "kubernetes"
+)
+
+// ConjurSecret defines the mapping between the ID of the variable in Conjur to the parameter
+type ConjurSecret struct {
+	Parameter  string `json:"parameter"`
+	VariableID string `json:"variableID"`
+}
+
+// AuthSecretProviderRef is used to authenticate using a secret of a secret store whose provider is compiled in KEDA
+type AuthSecretProviderRef struct {
+	// Provider is the name the secret provider is registered with
+	Provider  string `json:"provider"`
+	Parameter string `json:"parameter"`
+	// Name is the name of the secret in the secret store
+	Name string `json:"name"`
+
+	// +optional
+	Key string `json:"key,omitempty"`
+
+	// Metadata is the configuration of the secret provider for the secret
+	// +optional
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
 func init() {
 	SchemeBuilder.Register(&TriggerAuthentication{}, &TriggerAuthenticationList{})
 }
@@ -76,6 +76,28 @@ func (in *AuthPodIdentity) DeepCopy() *AuthPodIdentity {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuthSecretProviderRef) DeepCopyInto(out *AuthSecretProviderRef) {
+	*out = *in
+	if in.Metadata != nil {
+		in, out := &in.Metadata, &out.Metadata
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthSecretProviderRef.
+func (in *AuthSecretProviderRef) DeepCopy() *AuthSecretProviderRef {
+	if in == nil {
+		return nil
+	}
+	out := new(AuthSecretProviderRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AuthSecretTargetRef) DeepCopyInto(out *AuthSecretTargetRef) {
 	*out = *in
@@ -91,66 +113,513 @@ func (in *AuthSecretTargetRef) DeepCopy() *AuthSecretTargetRef {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloudEventHTTP) DeepCopyInto(out *CloudEventHTTP) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudEventHTTP.
+func (in *CloudEventHTTP) DeepCopy() *CloudEventHTTP {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudEventHTTP)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloudEventSource) DeepCopyInto(out *CloudEventSource) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudEventSource.
+func (in *CloudEventSource) DeepCopy() *CloudEventSource {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudEventSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CloudEventSource) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloudEventSourceList) DeepCopyInto(out *CloudEventSourceList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CloudEventSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudEventSourceList.
+func (in *CloudEventSourceList) DeepCopy() *CloudEventSourceList {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudEventSourceList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CloudEventSourceList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloudEventSourceSpec) DeepCopyInto(out *CloudEventSourceSpec) {
+	*out = *in
+	in.Destination.DeepCopyInto(&out.Destination)
+	in.EventSubscription.DeepCopyInto(&out.EventSubscription)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudEventSourceSpec.
+func (in *CloudEventSourceSpec) DeepCopy() *CloudEventSourceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudEventSourceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Condition) DeepCopyInto(out *Condition) {
 	*out = *in
+	if in.LastTransitionTime != nil {
+		in, out := &in.LastTransitionTime, &out.LastTransitionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Condition.
+func (in *Condition) DeepCopy() *Condition {
+	if in == nil {
+		return nil
+	}
+	out := new(Condition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in Conditions) DeepCopyInto(out *Conditions) {
+	{
+		in := &in
+		*out = make(Conditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Conditions.
+func (in Conditions) DeepCopy() Conditions {
+	if in == nil {
+		return nil
+	}
+	out := new(Conditions)
+	in.DeepCopyInto(out)
+	return *out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Conjur) DeepCopyInto(out *Conjur) {
+	*out = *in
+	if in.Secrets != nil {
+		in, out := &in.Secrets, &out.Secrets
+		*out = make([]ConjurSecret, len(*in))
+		copy(*out, *in)
+	}
+	if in.Credential != nil {
+		in, out := &in.Credential, &out.Credential
+		*out = new(ConjurCredential)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Conjur.
+func (in *Conjur) DeepCopy() *Conjur {
+	if in == nil {
+		return nil
+	}
+	out := new(Conjur)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConjurAPIKey) DeepCopyInto(out *ConjurAPIKey) {
+	*out = *in
+	out.SecretKeyRef = in.SecretKeyRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConjurAPIKey.
+func (in *ConjurAPIKey) DeepCopy() *ConjurAPIKey {
+	if in == nil {
+		return nil
+	}
+	out := new(ConjurAPIKey)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConjurCredential) DeepCopyInto(out *ConjurCredential) {
+	*out = *in
+	if in.APIKey != nil {
+		in, out := &in.APIKey, &out.APIKey
+		*out = new(ConjurAPIKey)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConjurCredential.
+func (in *ConjurCredential) DeepCopy() *ConjurCredential {
+	if in == nil {
+		return nil
+	}
+	out := new(ConjurCredential)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConjurSecret) DeepCopyInto(out *ConjurSecret) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConjurSecret.
+func (in *ConjurSecret) DeepCopy() *ConjurSecret {
+	if in == nil {
+		return nil
+	}
+	out := new(ConjurSecret)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConjurSecretKeyRef) DeepCopyInto(out *ConjurSecretKeyRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConjurSecretKeyRef.
+func (in *ConjurSecretKeyRef) DeepCopy() *ConjurSecretKeyRef {
+	if in == nil {
+		return nil
+	}
+	out := new(ConjurSecretKeyRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Credential) DeepCopyInto(out *Credential) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Credential.
+func (in *Credential) DeepCopy() *Credential {
+	if in == nil {
+		return nil
+	}
+	out := new(Credential)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DesiredReplicasStep) DeepCopyInto(out *DesiredReplicasStep) {
+	*out = *in
+	out.From = in.From.DeepCopy()
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DesiredReplicasStep.
+func (in *DesiredReplicasStep) DeepCopy() *DesiredReplicasStep {
+	if in == nil {
+		return nil
+	}
+	out := new(DesiredReplicasStep)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Destination) DeepCopyInto(out *Destination) {
+	*out = *in
+	if in.HTTP != nil {
+		in, out := &in.HTTP, &out.HTTP
+		*out = new(CloudEventHTTP)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Destination.
+func (in *Destination) DeepCopy() *Destination {
+	if in == nil {
+		return nil
+	}
+	out := new(Destination)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DryRunStatus) DeepCopyInto(out *DryRunStatus) {
+	*out = *in
+	if in.LastEvaluationTime != nil {
+		in, out := &in.LastEvaluationTime, &out.LastEvaluationTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Triggers != nil {
+		in, out := &in.Triggers, &out.Triggers
+		*out = make([]DryRunTriggerStatus, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DryRunStatus.
+func (in *DryRunStatus) DeepCopy() *DryRunStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DryRunStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DryRunTriggerStatus) DeepCopyInto(out *DryRunTriggerStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DryRunTriggerStatus.
+func (in *DryRunTriggerStatus) DeepCopy() *DryRunTriggerStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DryRunTriggerStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EventSubscription) DeepCopyInto(out *EventSubscription) {
+	*out = *in
+	if in.IncludedEventTypes != nil {
+		in, out := &in.IncludedEventTypes, &out.IncludedEventTypes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExcludedEventTypes != nil {
+		in, out := &in.ExcludedEventTypes, &out.ExcludedEventTypes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EventSubscription.
+func (in *EventSubscription) DeepCopy() *EventSubscription {
+	if in == nil {
+		return nil
+	}
+	out := new(EventSubscription)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalScalerStatus) DeepCopyInto(out *ExternalScalerStatus) {
+	*out = *in
+	if in.LastTransitionTime != nil {
+		in, out := &in.LastTransitionTime, &out.LastTransitionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalScalerStatus.
+func (in *ExternalScalerStatus) DeepCopy() *ExternalScalerStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalScalerStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GroupVersionKindResource) DeepCopyInto(out *GroupVersionKindResource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GroupVersionKindResource.
+func (in *GroupVersionKindResource) DeepCopy() *GroupVersionKindResource {
+	if in == nil {
+		return nil
+	}
+	out := new(GroupVersionKindResource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPScaleTarget) DeepCopyInto(out *HTTPScaleTarget) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPScaleTarget.
+func (in *HTTPScaleTarget) DeepCopy() *HTTPScaleTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPScaleTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPScaledObject) DeepCopyInto(out *HTTPScaledObject) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Condition.
-func (in *Condition) DeepCopy() *Condition {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPScaledObject.
+func (in *HTTPScaledObject) DeepCopy() *HTTPScaledObject {
 	if in == nil {
 		return nil
 	}
-	out := new(Condition)
+	out := new(HTTPScaledObject)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HTTPScaledObject) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in Conditions) DeepCopyInto(out *Conditions) {
-	{
-		in := &in
-		*out = make(Conditions, len(*in))
-		copy(*out, *in)
+func (in *HTTPScaledObjectList) DeepCopyInto(out *HTTPScaledObjectList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]HTTPScaledObject, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Conditions.
-func (in Conditions) DeepCopy() Conditions {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPScaledObjectList.
+func (in *HTTPScaledObjectList) DeepCopy() *HTTPScaledObjectList {
 	if in == nil {
 		return nil
 	}
-	out := new(Conditions)
+	out := new(HTTPScaledObjectList)
 	in.DeepCopyInto(out)
-	return *out
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HTTPScaledObjectList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Credential) DeepCopyInto(out *Credential) {
+func (in *HTTPScaledObjectSpec) DeepCopyInto(out *HTTPScaledObjectSpec) {
 	*out = *in
+	if in.ScaleTargetRef != nil {
+		in, out := &in.ScaleTargetRef, &out.ScaleTargetRef
+		*out = new(HTTPScaleTarget)
+		**out = **in
+	}
+	if in.TargetPendingRequests != nil {
+		in, out := &in.TargetPendingRequests, &out.TargetPendingRequests
+		*out = new(int32)
+		**out = **in
+	}
+	if in.PollingInterval != nil {
+		in, out := &in.PollingInterval, &out.PollingInterval
+		*out = new(int32)
+		**out = **in
+	}
+	if in.CooldownPeriod != nil {
+		in, out := &in.CooldownPeriod, &out.CooldownPeriod
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MinReplicaCount != nil {
+		in, out := &in.MinReplicaCount, &out.MinReplicaCount
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxReplicaCount != nil {
+		in, out := &in.MaxReplicaCount, &out.MaxReplicaCount
+		*out = new(int32)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Credential.
-func (in *Credential) DeepCopy() *Credential {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPScaledObjectSpec.
+func (in *HTTPScaledObjectSpec) DeepCopy() *HTTPScaledObjectSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(Credential)
+	out := new(HTTPScaledObjectSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GroupVersionKindResource) DeepCopyInto(out *GroupVersionKindResource) {
+func (in *HTTPScaledObjectStatus) DeepCopyInto(out *HTTPScaledObjectStatus) {
 	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(Conditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GroupVersionKindResource.
-func (in *GroupVersionKindResource) DeepCopy() *GroupVersionKindResource {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPScaledObjectStatus.
+func (in *HTTPScaledObjectStatus) DeepCopy() *HTTPScaledObjectStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(GroupVersionKindResource)
+	out := new(HTTPScaledObjectStatus)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -211,6 +680,21 @@ func (in *HorizontalPodAutoscalerConfig) DeepCopy() *HorizontalPodAutoscalerConf
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutCanaryStatus) DeepCopyInto(out *RolloutCanaryStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutCanaryStatus.
+func (in *RolloutCanaryStatus) DeepCopy() *RolloutCanaryStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutCanaryStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ScaleTarget) DeepCopyInto(out *ScaleTarget) {
 	*out = *in
@@ -226,6 +710,26 @@ func (in *ScaleTarget) DeepCopy() *ScaleTarget {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScaleToZeroSchedule) DeepCopyInto(out *ScaleToZeroSchedule) {
+	*out = *in
+	if in.Windows != nil {
+		in, out := &in.Windows, &out.Windows
+		*out = make([]ScheduleWindow, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScaleToZeroSchedule.
+func (in *ScaleToZeroSchedule) DeepCopy() *ScaleToZeroSchedule {
+	if in == nil {
+		return nil
+	}
+	out := new(ScaleToZeroSchedule)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ScaleTriggers) DeepCopyInto(out *ScaleTriggers) {
 	*out = *in
@@ -241,6 +745,28 @@ func (in *ScaleTriggers) DeepCopyInto(out *ScaleTriggers) {
 		*out = new(ScaledObjectAuthRef)
 		**out = **in
 	}
+	if in.MetricsCacheTTL != nil {
+		in, out := &in.MetricsCacheTTL, &out.MetricsCacheTTL
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.DesiredReplicas != nil {
+		in, out := &in.DesiredReplicas, &out.DesiredReplicas
+		*out = make([]DesiredReplicasStep, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.CooldownPeriod != nil {
+		in, out := &in.CooldownPeriod, &out.CooldownPeriod
+		*out = new(int32)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScaleTriggers.
@@ -253,6 +779,51 @@ func (in *ScaleTriggers) DeepCopy() *ScaleTriggers {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScaleWebhook) DeepCopyInto(out *ScaleWebhook) {
+	*out = *in
+	if in.TimeoutSeconds != nil {
+		in, out := &in.TimeoutSeconds, &out.TimeoutSeconds
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScaleWebhook.
+func (in *ScaleWebhook) DeepCopy() *ScaleWebhook {
+	if in == nil {
+		return nil
+	}
+	out := new(ScaleWebhook)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScaleWebhooks) DeepCopyInto(out *ScaleWebhooks) {
+	*out = *in
+	if in.PreActivation != nil {
+		in, out := &in.PreActivation, &out.PreActivation
+		*out = new(ScaleWebhook)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PostDeactivation != nil {
+		in, out := &in.PostDeactivation, &out.PostDeactivation
+		*out = new(ScaleWebhook)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScaleWebhooks.
+func (in *ScaleWebhooks) DeepCopy() *ScaleWebhooks {
+	if in == nil {
+		return nil
+	}
+	out := new(ScaleWebhooks)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ScaledJob) DeepCopyInto(out *ScaledJob) {
 	*out = *in
@@ -335,11 +906,21 @@ func (in *ScaledJobSpec) DeepCopyInto(out *ScaledJobSpec) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.MinReplicaCount != nil {
+		in, out := &in.MinReplicaCount, &out.MinReplicaCount
+		*out = new(int32)
+		**out = **in
+	}
 	if in.MaxReplicaCount != nil {
 		in, out := &in.MaxReplicaCount, &out.MaxReplicaCount
 		*out = new(int32)
 		**out = **in
 	}
+	if in.ScalingStrategy != nil {
+		in, out := &in.ScalingStrategy, &out.ScalingStrategy
+		*out = new(ScalingStrategy)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Triggers != nil {
 		in, out := &in.Triggers, &out.Triggers
 		*out = make([]ScaleTriggers, len(*in))
@@ -366,10 +947,29 @@ func (in *ScaledJobStatus) DeepCopyInto(out *ScaledJobStatus) {
 		in, out := &in.LastActiveTime, &out.LastActiveTime
 		*out = (*in).DeepCopy()
 	}
+	if in.MinReplicas != nil {
+		in, out := &in.MinReplicas, &out.MinReplicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxReplicas != nil {
+		in, out := &in.MaxReplicas, &out.MaxReplicas
+		*out = new(int32)
+		**out = **in
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make(Conditions, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.TriggersActivity != nil {
+		in, out := &in.TriggersActivity, &out.TriggersActivity
+		*out = make([]TriggerActivityStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
@@ -470,11 +1070,41 @@ func (in *ScaledObjectSpec) DeepCopyInto(out *ScaledObjectSpec) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.ActivationPollingInterval != nil {
+		in, out := &in.ActivationPollingInterval, &out.ActivationPollingInterval
+		*out = new(int32)
+		**out = **in
+	}
 	if in.CooldownPeriod != nil {
 		in, out := &in.CooldownPeriod, &out.CooldownPeriod
 		*out = new(int32)
 		**out = **in
 	}
+	if in.ScaleToZeroGracePeriod != nil {
+		in, out := &in.ScaleToZeroGracePeriod, &out.ScaleToZeroGracePeriod
+		*out = new(int32)
+		**out = **in
+	}
+	if in.InitialCooldownPeriod != nil {
+		in, out := &in.InitialCooldownPeriod, &out.InitialCooldownPeriod
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ScaleToZeroSchedule != nil {
+		in, out := &in.ScaleToZeroSchedule, &out.ScaleToZeroSchedule
+		*out = new(ScaleToZeroSchedule)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.WaitForZeroQueueBeforeScaleToZero != nil {
+		in, out := &in.WaitForZeroQueueBeforeScaleToZero, &out.WaitForZeroQueueBeforeScaleToZero
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ScaleWebhooks != nil {
+		in, out := &in.ScaleWebhooks, &out.ScaleWebhooks
+		*out = new(ScaleWebhooks)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.MinReplicaCount != nil {
 		in, out := &in.MinReplicaCount, &out.MinReplicaCount
 		*out = new(int32)
@@ -526,15 +1156,65 @@ func (in *ScaledObjectStatus) DeepCopyInto(out *ScaledObjectStatus) {
 		in, out := &in.LastActiveTime, &out.LastActiveTime
 		*out = (*in).DeepCopy()
 	}
+	if in.InactiveSince != nil {
+		in, out := &in.InactiveSince, &out.InactiveSince
+		*out = (*in).DeepCopy()
+	}
 	if in.ExternalMetricNames != nil {
 		in, out := &in.ExternalMetricNames, &out.ExternalMetricNames
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.MinReplicas != nil {
+		in, out := &in.MinReplicas, &out.MinReplicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxReplicas != nil {
+		in, out := &in.MaxReplicas, &out.MaxReplicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.PartitionReplicaCap != nil {
+		in, out := &in.PartitionReplicaCap, &out.PartitionReplicaCap
+		*out = new(int32)
+		**out = **in
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make(Conditions, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DryRun != nil {
+		in, out := &in.DryRun, &out.DryRun
+		*out = new(DryRunStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExternalScalers != nil {
+		in, out := &in.ExternalScalers, &out.ExternalScalers
+		*out = make([]ExternalScalerStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.TriggersActivity != nil {
+		in, out := &in.TriggersActivity, &out.TriggersActivity
+		*out = make([]TriggerActivityStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.RolloutCanary != nil {
+		in, out := &in.RolloutCanary, &out.RolloutCanary
+		*out = new(RolloutCanaryStatus)
+		**out = **in
+	}
+	if in.PreScaleDownHookMinReplicas != nil {
+		in, out := &in.PreScaleDownHookMinReplicas, &out.PreScaleDownHookMinReplicas
+		*out = new(int32)
+		**out = **in
 	}
 }
 
@@ -548,6 +1228,57 @@ func (in *ScaledObjectStatus) DeepCopy() *ScaledObjectStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScalingStrategy) DeepCopyInto(out *ScalingStrategy) {
+	*out = *in
+	if in.PendingPodConditions != nil {
+		in, out := &in.PendingPodConditions, &out.PendingPodConditions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScalingStrategy.
+func (in *ScalingStrategy) DeepCopy() *ScalingStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(ScalingStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScheduleWindow) DeepCopyInto(out *ScheduleWindow) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduleWindow.
+func (in *ScheduleWindow) DeepCopy() *ScheduleWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(ScheduleWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TriggerActivityStatus) DeepCopyInto(out *TriggerActivityStatus) {
+	*out = *in
+	in.LastActiveTime.DeepCopyInto(&out.LastActiveTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TriggerActivityStatus.
+func (in *TriggerActivityStatus) DeepCopy() *TriggerActivityStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TriggerActivityStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TriggerAuthentication) DeepCopyInto(out *TriggerAuthentication) {
 	*out = *in
@@ -629,6 +1360,18 @@ func (in *TriggerAuthenticationSpec) DeepCopyInto(out *TriggerAuthenticationSpec
 		*out = new(HashiCorpVault)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Conjur != nil {
+		in, out := &in.Conjur, &out.Conjur
+		*out = new(Conjur)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecretProviders != nil {
+		in, out := &in.SecretProviders, &out.SecretProviders
+		*out = make([]AuthSecretProviderRef, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TriggerAuthenticationSpec.
@@ -3,26 +3,31 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"strconv"
 
 	"github.com/go-logr/logr"
 	version "github.com/kedacore/keda/version"
 	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
 	"k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
 	kedacontrollerutil "github.com/kedacore/keda/controllers/util"
+	kedautil "github.com/kedacore/keda/pkg/util"
 )
 
 const (
-	defaultHPAMinReplicas int32 = 1
 	defaultHPAMaxReplicas int32 = 100
+
+	// hpaFieldOwner is the field manager of the fields of the HPAs applied by KEDA
+	hpaFieldOwner = "keda-operator"
 )
 
 // createAndDeployNewHPA creates and deploy HPA in the cluster for specified ScaledObject
 func (r *ScaledObjectReconciler) createAndDeployNewHPA(logger logr.Logger, scaledObject *kedav1alpha1.ScaledObject, gvkr *kedav1alpha1.GroupVersionKindResource) error {
-	hpaName := getHPAName(scaledObject)
+	hpaName := kedautil.GetHPAName(scaledObject)
 	logger.Info("Creating a new HPA", "HPA.Namespace", scaledObject.Namespace, "HPA.Name", hpaName)
 	hpa, err := r.newHPAForScaledObject(logger, scaledObject, gvkr)
 	if err != nil {
@@ -30,15 +35,21 @@ func (r *ScaledObjectReconciler) createAndDeployNewHPA(logger logr.Logger, scale
 		return err
 	}
 
-	err = r.Client.Create(context.TODO(), hpa)
+	err = r.applyHPA(hpa)
 	if err != nil {
 		logger.Error(err, "Failed to create new HPA in cluster", "HPA.Namespace", scaledObject.Namespace, "HPA.Name", hpaName)
 		return err
 	}
+	r.reportHPADrift(logger, scaledObject, hpa, nil)
 
 	return nil
 }
 
+// applyHPA applies the HPA with server-side apply, the fields of the HPA set by KEDA are taken over from the other field managers
+func (r *ScaledObjectReconciler) applyHPA(hpa *autoscalingv2beta2.HorizontalPodAutoscaler) error {
+	return r.Client.Patch(context.TODO(), hpa.DeepCopy(), client.Apply, client.FieldOwner(hpaFieldOwner), client.ForceOwnership)
+}
+
 // newHPAForScaledObject returns HPA as it is specified in ScaledObject
 func (r *ScaledObjectReconciler) newHPAForScaledObject(logger logr.Logger, scaledObject *kedav1alpha1.ScaledObject, gvkr *kedav1alpha1.GroupVersionKindResource) (*autoscalingv2beta2.HorizontalPodAutoscaler, error) {
 	scaledObjectMetricSpecs, err := r.getScaledObjectMetricSpecs(logger, scaledObject)
@@ -54,7 +65,7 @@ func (r *ScaledObjectReconciler) newHPAForScaledObject(logger logr.Logger, scale
 	}
 
 	// label can have max 63 chars
-	labelName := getHPAName(scaledObject)
+	labelName := kedautil.GetHPAName(scaledObject)
 	if len(labelName) > 63 {
 		labelName = labelName[:63]
 	}
@@ -68,9 +79,10 @@ func (r *ScaledObjectReconciler) newHPAForScaledObject(logger logr.Logger, scale
 		labels[key] = value
 	}
 
+	minReplicas := kedautil.GetHPAMinReplicas(scaledObject)
 	hpa := &autoscalingv2beta2.HorizontalPodAutoscaler{
 		Spec: autoscalingv2beta2.HorizontalPodAutoscalerSpec{
-			MinReplicas: getHPAMinReplicas(scaledObject),
+			MinReplicas: &minReplicas,
 			MaxReplicas: getHPAMaxReplicas(scaledObject),
 			Metrics:     scaledObjectMetricSpecs,
 			Behavior:    behavior,
@@ -80,12 +92,13 @@ func (r *ScaledObjectReconciler) newHPAForScaledObject(logger logr.Logger, scale
 				APIVersion: gvkr.GroupVersion().String(),
 			}},
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      getHPAName(scaledObject),
+			Name:      kedautil.GetHPAName(scaledObject),
 			Namespace: scaledObject.Namespace,
 			Labels:    labels,
 		},
 		TypeMeta: metav1.TypeMeta{
-			APIVersion: "v2beta2",
+			APIVersion: "autoscaling/v2beta2",
+			Kind:       "HorizontalPodAutoscaler",
 		},
 	}
 
@@ -97,37 +110,40 @@ func (r *ScaledObjectReconciler) newHPAForScaledObject(logger logr.Logger, scale
 	return hpa, nil
 }
 
-// updateHPAIfNeeded checks whether update of HPA is needed
+// updateHPAIfNeeded checks whether update of HPA is needed, the HPA is applied back to the ScaledObject if it was changed outside of KEDA
 func (r *ScaledObjectReconciler) updateHPAIfNeeded(logger logr.Logger, scaledObject *kedav1alpha1.ScaledObject, foundHpa *autoscalingv2beta2.HorizontalPodAutoscaler, gvkr *kedav1alpha1.GroupVersionKindResource) error {
 	hpa, err := r.newHPAForScaledObject(logger, scaledObject, gvkr)
 	if err != nil {
-		logger.Error(err, "Failed to create new HPA resource", "HPA.Namespace", scaledObject.Namespace, "HPA.Name", getHPAName(scaledObject))
+		logger.Error(err, "Failed to create new HPA resource", "HPA.Namespace", scaledObject.Namespace, "HPA.Name", kedautil.GetHPAName(scaledObject))
 		return err
 	}
+	r.deferHPAMinReplicasLowering(logger, scaledObject, foundHpa, hpa, gvkr)
 
-	if !equality.Semantic.DeepDerivative(hpa.Spec, foundHpa.Spec) {
+	// DeepDerivative accepts the metrics appended to the found HPA outside of KEDA, they are removed by the apply
+	specChanged := !equality.Semantic.DeepDerivative(hpa.Spec, foundHpa.Spec) || len(hpa.Spec.Metrics) != len(foundHpa.Spec.Metrics)
+	if specChanged {
 		logger.V(1).Info("Found difference in the HPA spec accordint to ScaledObject", "currentHPA", foundHpa.Spec, "newHPA", hpa.Spec)
-		if r.Client.Update(context.TODO(), hpa) != nil {
-			foundHpa.Spec = hpa.Spec
-			logger.Error(err, "Failed to update HPA", "HPA.Namespace", foundHpa.Namespace, "HPA.Name", foundHpa.Name)
-			return err
-		}
-		// check if scaledObject.spec.behavior was defined, because it is supported only on k8s >= 1.18
-		r.checkMinK8sVersionforHPABehavior(logger, scaledObject)
-
-		logger.Info("Updated HPA according to ScaledObject", "HPA.Namespace", foundHpa.Namespace, "HPA.Name", foundHpa.Name)
 	}
-
-	if !equality.Semantic.DeepDerivative(hpa.ObjectMeta.Labels, foundHpa.ObjectMeta.Labels) {
+	labelsChanged := !equality.Semantic.DeepDerivative(hpa.ObjectMeta.Labels, foundHpa.ObjectMeta.Labels)
+	if labelsChanged {
 		logger.V(1).Info("Found difference in the HPA labels accordint to ScaledObject", "currentHPA", foundHpa.ObjectMeta.Labels, "newHPA", hpa.ObjectMeta.Labels)
-		if r.Client.Update(context.TODO(), hpa) != nil {
-			foundHpa.ObjectMeta.Labels = hpa.ObjectMeta.Labels
-			logger.Error(err, "Failed to update HPA", "HPA.Namespace", foundHpa.Namespace, "HPA.Name", foundHpa.Name)
-			return err
-		}
-		logger.Info("Updated HPA according to ScaledObject", "HPA.Namespace", foundHpa.Namespace, "HPA.Name", foundHpa.Name)
+	}
+	if !specChanged && !labelsChanged {
+		r.reportHPADrift(logger, scaledObject, hpa, foundHpa)
+		return nil
 	}
 
+	if err := r.applyHPA(hpa); err != nil {
+		logger.Error(err, "Failed to update HPA", "HPA.Namespace", foundHpa.Namespace, "HPA.Name", foundHpa.Name)
+		return err
+	}
+	if specChanged {
+		// check if scaledObject.spec.behavior was defined, because it is supported only on k8s >= 1.18
+		r.checkMinK8sVersionforHPABehavior(logger, scaledObject)
+	}
+	r.reportHPADrift(logger, scaledObject, hpa, foundHpa)
+
+	logger.Info("Updated HPA according to ScaledObject", "HPA.Namespace", foundHpa.Namespace, "HPA.Name", foundHpa.Name)
 	return nil
 }
 
@@ -135,6 +151,11 @@ func (r *ScaledObjectReconciler) updateHPAIfNeeded(logger logr.Logger, scaledObj
 func (r *ScaledObjectReconciler) getScaledObjectMetricSpecs(logger logr.Logger, scaledObject *kedav1alpha1.ScaledObject) ([]autoscalingv2beta2.MetricSpec, error) {
 	var scaledObjectMetricSpecs []autoscalingv2beta2.MetricSpec
 	var externalMetricNames []string
+	activationOnlyTriggers := 0
+	disabledTriggers := 0
+	// the partition counts of the triggers driving the HPA, 0 for the metrics not read from a partitioned stream
+	var partitionCounts []int64
+	capReplicasToPartitions := scaledObject.Spec.Advanced == nil || !scaledObject.Spec.Advanced.DisablePartitionReplicaCap
 
 	scalers, err := r.scaleHandler.GetScalers(scaledObject)
 	if err != nil {
@@ -146,24 +167,69 @@ func (r *ScaledObjectReconciler) getScaledObjectMetricSpecs(logger logr.Logger,
 	if scaledObject.Spec.Advanced != nil && scaledObject.Spec.Advanced.HorizontalPodAutoscalerConfig != nil {
 		metrics := getResourceMetrics(scaledObject.Spec.Advanced.HorizontalPodAutoscalerConfig.ResourceMetrics)
 		scaledObjectMetricSpecs = append(scaledObjectMetricSpecs, metrics...)
+		if len(metrics) > 0 {
+			partitionCounts = append(partitionCounts, 0)
+		}
 	}
 
-	for _, scaler := range scalers {
+	for i, scaler := range scalers {
+		if i < len(scaledObject.Spec.Triggers) && !scaledObject.Spec.Triggers[i].IsEnabled() {
+			disabledTriggers++
+			scaler.Close()
+			continue
+		}
+		if i < len(scaledObject.Spec.Triggers) && scaledObject.Spec.Triggers[i].UseForActivationOnly {
+			activationOnlyTriggers++
+			scaler.Close()
+			continue
+		}
 		metricSpecs := scaler.GetMetricSpecForScaling()
+		// the triggers without metric, like the webhook trigger, only activate the ScaleTarget
+		if len(metricSpecs) == 0 {
+			activationOnlyTriggers++
+			scaler.Close()
+			continue
+		}
 
-		// add the scaledObjectName label. This is how the MetricsAdapter will know which scaledobject a metric is for when the HPA queries it.
+		// add the labels of the ScaledObject and the trigger. This is how the MetricsAdapter will know which scaledobject and trigger a metric is for when the HPA queries it.
 		for _, metricSpec := range metricSpecs {
-			metricSpec.External.Metric.Selector = &metav1.LabelSelector{MatchLabels: make(map[string]string)}
-			metricSpec.External.Metric.Selector.MatchLabels["scaledObjectName"] = scaledObject.Name
+			// the Resource metrics of the cpu and memory triggers are served by the Kubernetes metrics server
+			if metricSpec.External == nil {
+				continue
+			}
+			metricSpec.External.Metric.Selector = &metav1.LabelSelector{MatchLabels: map[string]string{
+				kedav1alpha1.ScaledObjectNameLabel:      scaledObject.Name,
+				kedav1alpha1.ScaledObjectNamespaceLabel: scaledObject.Namespace,
+				kedav1alpha1.TriggerIndexLabel:          strconv.Itoa(i),
+			}}
+			if i < len(scaledObject.Spec.Triggers) {
+				setMetricTargetType(&metricSpec.External.Target, scaledObject.Spec.Triggers[i].GetThresholdType())
+			}
 			externalMetricNames = append(externalMetricNames, metricSpec.External.Metric.Name)
 		}
 		scaledObjectMetricSpecs = append(scaledObjectMetricSpecs, metricSpecs...)
+		if capReplicasToPartitions {
+			partitionCounts = append(partitionCounts, getScalerPartitionCount(logger, scaler))
+		}
 		scaler.Close()
 	}
 
+	if activationOnlyTriggers > 0 && len(scaledObjectMetricSpecs) == 0 {
+		err = fmt.Errorf("all the triggers are used for activation only, at least one trigger or resource metric must drive the HPA")
+		logger.Error(err, "Error getting metric specs for HPA")
+		return nil, err
+	}
+
+	if disabledTriggers > 0 && len(scaledObjectMetricSpecs) == 0 {
+		err = fmt.Errorf("all the triggers are disabled, at least one trigger or resource metric must drive the HPA")
+		logger.Error(err, "Error getting metric specs for HPA")
+		return nil, err
+	}
+
 	// store External.MetricNames used by scalers defined in the ScaledObject
 	status := scaledObject.Status.DeepCopy()
 	status.ExternalMetricNames = externalMetricNames
+	status.PartitionReplicaCap = getPartitionReplicaCap(partitionCounts)
 	err = kedacontrollerutil.UpdateScaledObjectStatus(r.Client, logger, scaledObject, status)
 	if err != nil {
 		logger.Error(err, "Error updating scaledObject status with used externalMetricNames")
@@ -173,6 +239,16 @@ func (r *ScaledObjectReconciler) getScaledObjectMetricSpecs(logger logr.Logger,
 	return scaledObjectMetricSpecs, nil
 }
 
+// setMetricTargetType converts the threshold of the scaler, which is an average value, to the target type of the trigger
+func setMetricTargetType(target *autoscalingv2beta2.MetricTarget, targetType autoscalingv2beta2.MetricTargetType) {
+	if targetType != autoscalingv2beta2.ValueMetricType || target.Type != autoscalingv2beta2.AverageValueMetricType {
+		return
+	}
+	target.Type = autoscalingv2beta2.ValueMetricType
+	target.Value = target.AverageValue
+	target.AverageValue = nil
+}
+
 func getResourceMetrics(resourceMetrics []*autoscalingv2beta2.ResourceMetricSource) []autoscalingv2beta2.MetricSpec {
 	metrics := make([]autoscalingv2beta2.MetricSpec, 0, len(resourceMetrics))
 	for _, resourceMetric := range resourceMetrics {
@@ -194,24 +270,15 @@ func (r *ScaledObjectReconciler) checkMinK8sVersionforHPABehavior(logger logr.Lo
 	}
 }
 
-// getHPAName returns generated HPA name for ScaledObject specified in the parameter
-func getHPAName(scaledObject *kedav1alpha1.ScaledObject) string {
-	return fmt.Sprintf("keda-hpa-%s", scaledObject.Name)
-}
-
-// getHPAMinReplicas returns MinReplicas based on definition in ScaledObject or default value if not defined
-func getHPAMinReplicas(scaledObject *kedav1alpha1.ScaledObject) *int32 {
-	if scaledObject.Spec.MinReplicaCount != nil && *scaledObject.Spec.MinReplicaCount > 0 {
-		return scaledObject.Spec.MinReplicaCount
-	}
-	tmp := defaultHPAMinReplicas
-	return &tmp
-}
-
-// getHPAMaxReplicas returns MaxReplicas based on definition in ScaledObject or default value if not defined
+// getHPAMaxReplicas returns MaxReplicas based on definition in ScaledObject or default value if not defined, capped at the partition count
+// of the triggers, or the MinReplicas while KEDA only activates the ScaleTarget whose pods are updated by a VerticalPodAutoscaler
 func getHPAMaxReplicas(scaledObject *kedav1alpha1.ScaledObject) int32 {
+	if isActivationOnlyWithVPA(scaledObject) {
+		return kedautil.GetHPAMinReplicas(scaledObject)
+	}
+	maxReplicas := defaultHPAMaxReplicas
 	if scaledObject.Spec.MaxReplicaCount != nil {
-		return *scaledObject.Spec.MaxReplicaCount
+		maxReplicas = *scaledObject.Spec.MaxReplicaCount
 	}
-	return defaultHPAMaxReplicas
+	return capHPAMaxReplicasToPartitions(scaledObject, maxReplicas)
 }
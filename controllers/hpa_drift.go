@@ -0,0 +1,109 @@
+package controllers
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-logr/logr"
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/client-go/tools/cache"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+	"github.com/kedacore/keda/pkg/eventemitter"
+)
+
+// describeHPADrift returns the changes of the found HPA made outside of KEDA, it is compared to the spec last applied by KEDA.
+// The fields whose desired value changed since the last apply are updated by KEDA, they aren't reported as changed outside of KEDA
+func describeHPADrift(applied, desired, found *autoscalingv2beta2.HorizontalPodAutoscalerSpec) []string {
+	var drift []string
+
+	if equality.Semantic.DeepEqual(desired.MinReplicas, applied.MinReplicas) && !equality.Semantic.DeepDerivative(desired.MinReplicas, found.MinReplicas) {
+		drift = append(drift, fmt.Sprintf("minReplicas reset from %s to %s", formatReplicas(found.MinReplicas), formatReplicas(desired.MinReplicas)))
+	}
+	if desired.MaxReplicas == applied.MaxReplicas && desired.MaxReplicas != found.MaxReplicas {
+		drift = append(drift, fmt.Sprintf("maxReplicas reset from %d to %d", found.MaxReplicas, desired.MaxReplicas))
+	}
+	if equality.Semantic.DeepEqual(desired.ScaleTargetRef, applied.ScaleTargetRef) && !equality.Semantic.DeepDerivative(desired.ScaleTargetRef, found.ScaleTargetRef) {
+		drift = append(drift, fmt.Sprintf("scaleTargetRef reset to %s %s", desired.ScaleTargetRef.Kind, desired.ScaleTargetRef.Name))
+	}
+	if equality.Semantic.DeepEqual(desired.Behavior, applied.Behavior) && !equality.Semantic.DeepDerivative(desired.Behavior, found.Behavior) {
+		drift = append(drift, "behavior reset")
+	}
+
+	for _, metric := range desired.Metrics {
+		name := metricSpecName(metric)
+		appliedMetric, wasApplied := findMetricSpec(applied.Metrics, name)
+		if !wasApplied || !equality.Semantic.DeepEqual(metric, appliedMetric) {
+			continue
+		}
+		if foundMetric, ok := findMetricSpec(found.Metrics, name); !ok {
+			drift = append(drift, fmt.Sprintf("metric %s restored", name))
+		} else if !equality.Semantic.DeepDerivative(metric, foundMetric) {
+			drift = append(drift, fmt.Sprintf("metric %s reset", name))
+		}
+	}
+	for _, metric := range found.Metrics {
+		name := metricSpecName(metric)
+		_, isDesired := findMetricSpec(desired.Metrics, name)
+		_, wasApplied := findMetricSpec(applied.Metrics, name)
+		if !isDesired && !wasApplied {
+			drift = append(drift, fmt.Sprintf("metric %s removed", name))
+		}
+	}
+
+	return drift
+}
+
+// reportHPADrift records the spec applied to the HPA of the ScaledObject and emits an event for the changes of the found HPA made outside of KEDA,
+// the changes are only detected once KEDA applied the HPA since the operator started
+func (r *ScaledObjectReconciler) reportHPADrift(logger logr.Logger, scaledObject *kedav1alpha1.ScaledObject, desired, found *autoscalingv2beta2.HorizontalPodAutoscaler) {
+	key, err := cache.MetaNamespaceKeyFunc(scaledObject)
+	if err != nil {
+		return
+	}
+	value, ok := r.appliedHPASpecs.Load(key)
+	r.appliedHPASpecs.Store(key, desired.Spec.DeepCopy())
+	if !ok || found == nil {
+		return
+	}
+
+	drift := describeHPADrift(value.(*autoscalingv2beta2.HorizontalPodAutoscalerSpec), &desired.Spec, &found.Spec)
+	if len(drift) == 0 {
+		return
+	}
+	message := fmt.Sprintf("Corrected the changes of the HPA %s made outside of KEDA: %s", found.Name, strings.Join(drift, ", "))
+	logger.Info(message)
+	r.eventEmitter.Emit("ScaledObject", scaledObject.Namespace, scaledObject.Name, eventemitter.HPADriftCorrectedType, message)
+}
+
+// metricSpecName returns the name of the metric of an HPA, the resource name for the Resource metrics
+func metricSpecName(metric autoscalingv2beta2.MetricSpec) string {
+	switch {
+	case metric.External != nil:
+		return metric.External.Metric.Name
+	case metric.Resource != nil:
+		return string(metric.Resource.Name)
+	case metric.Pods != nil:
+		return metric.Pods.Metric.Name
+	case metric.Object != nil:
+		return metric.Object.Metric.Name
+	}
+	return string(metric.Type)
+}
+
+func findMetricSpec(metrics []autoscalingv2beta2.MetricSpec, name string) (autoscalingv2beta2.MetricSpec, bool) {
+	for _, metric := range metrics {
+		if metricSpecName(metric) == name {
+			return metric, true
+		}
+	}
+	return autoscalingv2beta2.MetricSpec{}, false
+}
+
+func formatReplicas(replicas *int32) string {
+	if replicas == nil {
+		return "unset"
+	}
+	return fmt.Sprint(*replicas)
+}
@@ -0,0 +1,171 @@
+package controllers
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+	"github.com/kedacore/keda/pkg/scalers"
+)
+
+func newTestHPASpec(maxReplicas int32, metricNames ...string) *autoscalingv2beta2.HorizontalPodAutoscalerSpec {
+	minReplicas := int32(1)
+	spec := &autoscalingv2beta2.HorizontalPodAutoscalerSpec{
+		MinReplicas:    &minReplicas,
+		MaxReplicas:    maxReplicas,
+		ScaleTargetRef: autoscalingv2beta2.CrossVersionObjectReference{APIVersion: "apps/v1", Kind: "Deployment", Name: "app"},
+	}
+	for _, name := range metricNames {
+		target := resource.MustParse("5")
+		spec.Metrics = append(spec.Metrics, autoscalingv2beta2.MetricSpec{
+			Type: autoscalingv2beta2.ExternalMetricSourceType,
+			External: &autoscalingv2beta2.ExternalMetricSource{
+				Metric: autoscalingv2beta2.MetricIdentifier{Name: name},
+				Target: autoscalingv2beta2.MetricTarget{Type: autoscalingv2beta2.AverageValueMetricType, AverageValue: &target},
+			},
+		})
+	}
+	return spec
+}
+
+func TestDescribeHPADrift(t *testing.T) {
+	changedTarget := newTestHPASpec(10, "queue")
+	changedTarget.Metrics[0].External.Target.AverageValue = resource.NewQuantity(50, resource.DecimalSI)
+	cpu := newTestHPASpec(10, "queue")
+	cpu.Metrics = append(cpu.Metrics, autoscalingv2beta2.MetricSpec{
+		Type:     autoscalingv2beta2.ResourceMetricSourceType,
+		Resource: &autoscalingv2beta2.ResourceMetricSource{Name: corev1.ResourceCPU},
+	})
+
+	tests := []struct {
+		name     string
+		applied  *autoscalingv2beta2.HorizontalPodAutoscalerSpec
+		desired  *autoscalingv2beta2.HorizontalPodAutoscalerSpec
+		found    *autoscalingv2beta2.HorizontalPodAutoscalerSpec
+		expected []string
+	}{
+		{"unchanged", newTestHPASpec(10, "queue"), newTestHPASpec(10, "queue"), newTestHPASpec(10, "queue"), nil},
+		{"maxReplicas changed outside of KEDA", newTestHPASpec(10, "queue"), newTestHPASpec(10, "queue"), newTestHPASpec(20, "queue"), []string{"maxReplicas reset from 20 to 10"}},
+		{"maxReplicas changed by the ScaledObject", newTestHPASpec(10, "queue"), newTestHPASpec(30, "queue"), newTestHPASpec(20, "queue"), nil},
+		{"metric deleted outside of KEDA", newTestHPASpec(10, "queue", "backlog"), newTestHPASpec(10, "queue", "backlog"), newTestHPASpec(10, "queue"), []string{"metric backlog restored"}},
+		{"metric added by the ScaledObject", newTestHPASpec(10, "queue"), newTestHPASpec(10, "queue", "backlog"), newTestHPASpec(10, "queue"), nil},
+		{"metric target changed outside of KEDA", newTestHPASpec(10, "queue"), newTestHPASpec(10, "queue"), changedTarget, []string{"metric queue reset"}},
+		{"metric added outside of KEDA", newTestHPASpec(10, "queue"), newTestHPASpec(10, "queue"), cpu, []string{"metric cpu removed"}},
+		{"metric removed from the ScaledObject", newTestHPASpec(10, "queue", "backlog"), newTestHPASpec(10, "queue"), newTestHPASpec(10, "queue", "backlog"), nil},
+	}
+
+	for _, test := range tests {
+		drift := describeHPADrift(test.applied, test.desired, test.found)
+		if !reflect.DeepEqual(drift, test.expected) {
+			t.Errorf("%s: expected %v, got %v", test.name, test.expected, drift)
+		}
+	}
+}
+
+type recordingEventEmitter struct {
+	eventTypes []string
+	messages   []string
+}
+
+func (e *recordingEventEmitter) Emit(kind, namespace, name, eventType, message string) {
+	e.eventTypes = append(e.eventTypes, eventType)
+	e.messages = append(e.messages, message)
+}
+
+func TestReportHPADrift(t *testing.T) {
+	emitter := &recordingEventEmitter{}
+	reconciler := &ScaledObjectReconciler{appliedHPASpecs: &sync.Map{}, eventEmitter: emitter}
+	scaledObject := &kedav1alpha1.ScaledObject{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "app"}}
+	desired := &autoscalingv2beta2.HorizontalPodAutoscaler{ObjectMeta: metav1.ObjectMeta{Name: "keda-hpa-app"}, Spec: *newTestHPASpec(10, "queue")}
+	found := &autoscalingv2beta2.HorizontalPodAutoscaler{ObjectMeta: metav1.ObjectMeta{Name: "keda-hpa-app"}, Spec: *newTestHPASpec(20)}
+
+	// the changes aren't known before KEDA applied the HPA
+	reconciler.reportHPADrift(logf.Log, scaledObject, desired, found)
+	if len(emitter.messages) != 0 {
+		t.Fatalf("Expected no event before the HPA was applied, got %v", emitter.messages)
+	}
+
+	reconciler.reportHPADrift(logf.Log, scaledObject, desired, found)
+	expected := "Corrected the changes of the HPA keda-hpa-app made outside of KEDA: maxReplicas reset from 20 to 10, metric queue restored"
+	if len(emitter.messages) != 1 || emitter.messages[0] != expected {
+		t.Errorf("Expected the event %q, got %v", expected, emitter.messages)
+	}
+}
+
+// applyRecordingClient records the HPAs applied by the reconciler
+type applyRecordingClient struct {
+	client.Client
+	applied []*autoscalingv2beta2.HorizontalPodAutoscaler
+}
+
+func (c *applyRecordingClient) Patch(ctx context.Context, obj runtime.Object, patch client.Patch, opts ...client.PatchOption) error {
+	if hpa, ok := obj.(*autoscalingv2beta2.HorizontalPodAutoscaler); ok && patch.Type() == types.ApplyPatchType {
+		c.applied = append(c.applied, hpa)
+		return nil
+	}
+	return c.Client.Patch(ctx, obj, patch, opts...)
+}
+
+func TestUpdateHPAIfNeededRemovesMetricAddedOutsideOfKEDA(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = kedav1alpha1.AddToScheme(scheme)
+
+	scaledObject := &kedav1alpha1.ScaledObject{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "app", UID: "scaledobject-uid"},
+		Spec: kedav1alpha1.ScaledObjectSpec{
+			ScaleTargetRef: &kedav1alpha1.ScaleTarget{Name: "app"},
+			Triggers:       []kedav1alpha1.ScaleTriggers{{Type: "prometheus"}},
+		},
+	}
+	gvkr := &kedav1alpha1.GroupVersionKindResource{Group: "apps", Version: "v1", Kind: "Deployment", Resource: "deployments"}
+	recordingClient := &applyRecordingClient{Client: fake.NewFakeClientWithScheme(scheme, scaledObject)}
+	emitter := &recordingEventEmitter{}
+	reconciler := &ScaledObjectReconciler{
+		Client:          recordingClient,
+		Scheme:          scheme,
+		scaleHandler:    &fakeScaleHandler{scalers: []scalers.Scaler{&fakeMetricScaler{metricName: "queue"}}},
+		appliedHPASpecs: &sync.Map{},
+		eventEmitter:    emitter,
+	}
+
+	desired, err := reconciler.newHPAForScaledObject(logf.Log, scaledObject, gvkr)
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	reconciler.reportHPADrift(logf.Log, scaledObject, desired, nil)
+
+	// a metric is appended to the HPA outside of KEDA
+	found := desired.DeepCopy()
+	found.Spec.Metrics = append(found.Spec.Metrics, autoscalingv2beta2.MetricSpec{
+		Type:     autoscalingv2beta2.ResourceMetricSourceType,
+		Resource: &autoscalingv2beta2.ResourceMetricSource{Name: corev1.ResourceCPU},
+	})
+	if err := reconciler.updateHPAIfNeeded(logf.Log, scaledObject, found, gvkr); err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+
+	if len(recordingClient.applied) != 1 {
+		t.Fatalf("Expected the HPA to be applied once, got %d applies", len(recordingClient.applied))
+	}
+	if metrics := recordingClient.applied[0].Spec.Metrics; len(metrics) != 1 || metricSpecName(metrics[0]) != "queue" {
+		t.Errorf("Expected only the metric of the ScaledObject to be applied, got %+v", metrics)
+	}
+	expected := "Corrected the changes of the HPA " + found.Name + " made outside of KEDA: metric cpu removed"
+	if len(emitter.messages) != 1 || emitter.messages[0] != expected {
+		t.Errorf("Expected the event %q, got %v", expected, emitter.messages)
+	}
+}
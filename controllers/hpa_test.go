@@ -0,0 +1,256 @@
+package controllers
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/metrics/pkg/apis/external_metrics"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+	"github.com/kedacore/keda/pkg/scalers"
+	"github.com/kedacore/keda/pkg/scaling"
+)
+
+type fakeMetricScaler struct {
+	metricName string
+}
+
+func (s *fakeMetricScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
+	return nil, nil
+}
+
+func (s *fakeMetricScaler) GetMetricSpecForScaling() []autoscalingv2beta2.MetricSpec {
+	return []autoscalingv2beta2.MetricSpec{{
+		Type: "External",
+		External: &autoscalingv2beta2.ExternalMetricSource{
+			Metric: autoscalingv2beta2.MetricIdentifier{Name: s.metricName},
+			Target: autoscalingv2beta2.MetricTarget{Type: autoscalingv2beta2.AverageValueMetricType, AverageValue: resource.NewQuantity(1, resource.DecimalSI)},
+		},
+	}}
+}
+
+func (s *fakeMetricScaler) IsActive(ctx context.Context) (bool, error) {
+	return false, nil
+}
+
+func (s *fakeMetricScaler) Close() error {
+	return nil
+}
+
+type fakeScaleHandler struct {
+	scalers []scalers.Scaler
+}
+
+func (h *fakeScaleHandler) HandleScalableObject(scalableObject interface{}) error {
+	return nil
+}
+
+func (h *fakeScaleHandler) DeleteScalableObject(scalableObject interface{}) error {
+	return nil
+}
+
+func (h *fakeScaleHandler) GetScalers(scalableObject interface{}) ([]scalers.Scaler, error) {
+	return h.scalers, nil
+}
+
+func (h *fakeScaleHandler) EvaluateTrigger(ctx context.Context, namespace, scaledObjectName string, trigger kedav1alpha1.ScaleTriggers) (*scaling.TriggerEvaluation, error) {
+	return nil, nil
+}
+
+func TestSetMetricTargetType(t *testing.T) {
+	threshold := resource.NewQuantity(5, resource.DecimalSI)
+
+	target := autoscalingv2beta2.MetricTarget{Type: autoscalingv2beta2.AverageValueMetricType, AverageValue: threshold}
+	setMetricTargetType(&target, autoscalingv2beta2.AverageValueMetricType)
+	if target.Type != autoscalingv2beta2.AverageValueMetricType || target.AverageValue != threshold || target.Value != nil {
+		t.Errorf("Expected the AverageValue target to be kept, got %+v", target)
+	}
+
+	setMetricTargetType(&target, autoscalingv2beta2.ValueMetricType)
+	if target.Type != autoscalingv2beta2.ValueMetricType || target.Value != threshold || target.AverageValue != nil {
+		t.Errorf("Expected a Value target with the threshold, got %+v", target)
+	}
+
+	// a target which isn't an average value is kept as is
+	utilization := int32(50)
+	target = autoscalingv2beta2.MetricTarget{Type: autoscalingv2beta2.UtilizationMetricType, AverageUtilization: &utilization}
+	setMetricTargetType(&target, autoscalingv2beta2.ValueMetricType)
+	if target.Type != autoscalingv2beta2.UtilizationMetricType || target.Value != nil {
+		t.Errorf("Expected the Utilization target to be kept, got %+v", target)
+	}
+}
+
+func TestGetScaledObjectMetricSpecsActivationOnly(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = kedav1alpha1.AddToScheme(scheme)
+
+	newReconciler := func(scaledObject *kedav1alpha1.ScaledObject) *ScaledObjectReconciler {
+		return &ScaledObjectReconciler{
+			Client: fake.NewFakeClientWithScheme(scheme, scaledObject),
+			scaleHandler: &fakeScaleHandler{scalers: []scalers.Scaler{
+				&fakeMetricScaler{metricName: "queue"},
+				&fakeMetricScaler{metricName: "cpu"},
+			}},
+		}
+	}
+
+	scaledObject := &kedav1alpha1.ScaledObject{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "app"},
+		Spec: kedav1alpha1.ScaledObjectSpec{Triggers: []kedav1alpha1.ScaleTriggers{
+			{Type: "azure-queue", UseForActivationOnly: true},
+			{Type: "prometheus"},
+		}},
+	}
+	metricSpecs, err := newReconciler(scaledObject).getScaledObjectMetricSpecs(logf.Log, scaledObject)
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	if len(metricSpecs) != 1 || metricSpecs[0].External.Metric.Name != "cpu" {
+		t.Errorf("Expected only the metric of the trigger not used for activation only, got %+v", metricSpecs)
+	}
+	if len(scaledObject.Status.ExternalMetricNames) != 1 || scaledObject.Status.ExternalMetricNames[0] != "cpu" {
+		t.Errorf("Expected only the cpu metric in the status, got %v", scaledObject.Status.ExternalMetricNames)
+	}
+
+	scaledObject = scaledObject.DeepCopy()
+	scaledObject.Spec.Triggers[1].UseForActivationOnly = true
+	if _, err = newReconciler(scaledObject).getScaledObjectMetricSpecs(logf.Log, scaledObject); err == nil {
+		t.Error("Expected an error if all the triggers are used for activation only")
+	}
+}
+
+func TestGetScaledObjectMetricSpecsDisabledTriggers(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = kedav1alpha1.AddToScheme(scheme)
+	disabled := false
+
+	newReconciler := func(scaledObject *kedav1alpha1.ScaledObject) *ScaledObjectReconciler {
+		return &ScaledObjectReconciler{
+			Client: fake.NewFakeClientWithScheme(scheme, scaledObject),
+			scaleHandler: &fakeScaleHandler{scalers: []scalers.Scaler{
+				&fakeMetricScaler{metricName: "log-analytics"},
+				&fakeMetricScaler{metricName: "queue"},
+			}},
+		}
+	}
+
+	scaledObject := &kedav1alpha1.ScaledObject{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "app"},
+		Spec: kedav1alpha1.ScaledObjectSpec{Triggers: []kedav1alpha1.ScaleTriggers{
+			{Type: "azure-log-analytics", Enabled: &disabled},
+			{Type: "azure-queue"},
+		}},
+	}
+	metricSpecs, err := newReconciler(scaledObject).getScaledObjectMetricSpecs(logf.Log, scaledObject)
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	if len(metricSpecs) != 1 || metricSpecs[0].External.Metric.Name != "queue" {
+		t.Errorf("Expected only the metric of the enabled trigger, got %+v", metricSpecs)
+	}
+
+	scaledObject = scaledObject.DeepCopy()
+	scaledObject.Spec.Triggers[1].Enabled = &disabled
+	if _, err = newReconciler(scaledObject).getScaledObjectMetricSpecs(logf.Log, scaledObject); err == nil {
+		t.Error("Expected an error if all the triggers are disabled")
+	}
+}
+
+func TestGetScaledObjectMetricSpecsTriggersWithoutMetric(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = kedav1alpha1.AddToScheme(scheme)
+
+	scaledObject := &kedav1alpha1.ScaledObject{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "app"},
+		Spec: kedav1alpha1.ScaledObjectSpec{Triggers: []kedav1alpha1.ScaleTriggers{
+			{Type: "webhook"},
+			{Type: "azure-queue"},
+		}},
+	}
+	reconciler := &ScaledObjectReconciler{
+		Client: fake.NewFakeClientWithScheme(scheme, scaledObject),
+		scaleHandler: &fakeScaleHandler{scalers: []scalers.Scaler{
+			scalers.NewDisabledScaler(),
+			&fakeMetricScaler{metricName: "queue"},
+		}},
+	}
+	metricSpecs, err := reconciler.getScaledObjectMetricSpecs(logf.Log, scaledObject)
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	if len(metricSpecs) != 1 || metricSpecs[0].External.Metric.Name != "queue" {
+		t.Errorf("Expected only the metric of the trigger with a metric, got %+v", metricSpecs)
+	}
+
+	scaledObject = scaledObject.DeepCopy()
+	scaledObject.Spec.Triggers = scaledObject.Spec.Triggers[:1]
+	reconciler = &ScaledObjectReconciler{
+		Client:       fake.NewFakeClientWithScheme(scheme, scaledObject),
+		scaleHandler: &fakeScaleHandler{scalers: []scalers.Scaler{scalers.NewDisabledScaler()}},
+	}
+	if _, err = reconciler.getScaledObjectMetricSpecs(logf.Log, scaledObject); err == nil {
+		t.Error("Expected an error if no trigger drives the HPA")
+	}
+}
+
+type fakeResourceMetricScaler struct {
+	fakeMetricScaler
+}
+
+func (s *fakeResourceMetricScaler) GetMetricSpecForScaling() []autoscalingv2beta2.MetricSpec {
+	utilization := int32(50)
+	return []autoscalingv2beta2.MetricSpec{{
+		Type: autoscalingv2beta2.ResourceMetricSourceType,
+		Resource: &autoscalingv2beta2.ResourceMetricSource{
+			Name:   "cpu",
+			Target: autoscalingv2beta2.MetricTarget{Type: autoscalingv2beta2.UtilizationMetricType, AverageUtilization: &utilization},
+		},
+	}}
+}
+
+func TestGetScaledObjectMetricSpecsResourceTriggers(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = kedav1alpha1.AddToScheme(scheme)
+
+	scaledObject := &kedav1alpha1.ScaledObject{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "app"},
+		Spec: kedav1alpha1.ScaledObjectSpec{Triggers: []kedav1alpha1.ScaleTriggers{
+			{Type: "cpu"},
+			{Type: "azure-queue"},
+		}},
+	}
+	reconciler := &ScaledObjectReconciler{
+		Client: fake.NewFakeClientWithScheme(scheme, scaledObject),
+		scaleHandler: &fakeScaleHandler{scalers: []scalers.Scaler{
+			&fakeResourceMetricScaler{},
+			&fakeMetricScaler{metricName: "queue"},
+		}},
+	}
+
+	metricSpecs, err := reconciler.getScaledObjectMetricSpecs(logf.Log, scaledObject)
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	if len(metricSpecs) != 2 || metricSpecs[0].Resource == nil || metricSpecs[0].Resource.Name != "cpu" || metricSpecs[1].External.Metric.Name != "queue" {
+		t.Errorf("Expected the cpu Resource metric and the queue External metric, got %+v", metricSpecs)
+	}
+	if len(scaledObject.Status.ExternalMetricNames) != 1 || scaledObject.Status.ExternalMetricNames[0] != "queue" {
+		t.Errorf("Expected only the queue metric in the status, got %v", scaledObject.Status.ExternalMetricNames)
+	}
+	expectedLabels := map[string]string{
+		kedav1alpha1.ScaledObjectNameLabel:      "app",
+		kedav1alpha1.ScaledObjectNamespaceLabel: "default",
+		kedav1alpha1.TriggerIndexLabel:          "1",
+	}
+	if selector := metricSpecs[1].External.Metric.Selector; selector == nil || !reflect.DeepEqual(selector.MatchLabels, expectedLabels) {
+		t.Errorf("Expected the selector of the queue metric to have the labels %v, got %+v", expectedLabels, selector)
+	}
+}
@@ -0,0 +1,218 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+	kedacontrollerutil "github.com/kedacore/keda/controllers/util"
+	version "github.com/kedacore/keda/version"
+)
+
+const (
+	httpInterceptorTriggerType          = "http-interceptor"
+	defaultHTTPTargetPendingRequests    = 100
+	httpScaledObjectManagedByLabelValue = "keda-operator"
+)
+
+// +kubebuilder:rbac:groups=keda.sh,resources=httpscaledobjects;httpscaledobjects/finalizers;httpscaledobjects/status,verbs="*"
+
+// HTTPScaledObjectReconciler reconciles a HTTPScaledObject object into a ScaledObject with an http-interceptor trigger
+type HTTPScaledObjectReconciler struct {
+	Log    logr.Logger
+	Client client.Client
+	Scheme *runtime.Scheme
+}
+
+// SetupWithManager initializes the HTTPScaledObjectReconciler instance and starts a new controller managed by the passed Manager instance.
+func (r *HTTPScaledObjectReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		// Ignore updates to HTTPScaledObject Status (in this case metadata.Generation does not change)
+		// so reconcile loop is not started on Status updates
+		For(&kedav1alpha1.HTTPScaledObject{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Owns(&kedav1alpha1.ScaledObject{}).
+		Complete(r)
+}
+
+// Reconcile performs reconciliation on the identified HTTPScaledObject resource based on the request information passed, returns the result and an error (if any).
+func (r *HTTPScaledObjectReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
+	reqLogger := r.Log.WithValues("HTTPScaledObject.Namespace", req.Namespace, "HTTPScaledObject.Name", req.Name)
+
+	// Fetch the HTTPScaledObject instance
+	httpScaledObject := &kedav1alpha1.HTTPScaledObject{}
+	err := r.Client.Get(context.TODO(), req.NamespacedName, httpScaledObject)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			// Request object not found, could have been deleted after reconcile request.
+			// Owned ScaledObject is automatically garbage collected.
+			// Return and don't requeue
+			return ctrl.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		reqLogger.Error(err, "Failed to get HTTPScaledObject")
+		return ctrl.Result{}, err
+	}
+
+	reqLogger.Info("Reconciling HTTPScaledObject")
+
+	conditions := httpScaledObject.Status.Conditions.DeepCopy()
+	if !conditions.AreInitialized() {
+		conditions = *kedav1alpha1.GetInitializedConditions()
+	}
+
+	msg, err := r.reconcileHTTPScaledObject(reqLogger, httpScaledObject)
+	if err != nil {
+		reqLogger.Error(err, msg)
+		conditions.SetReadyCondition(metav1.ConditionFalse, "HTTPScaledObjectCheckFailed", msg)
+	} else {
+		reqLogger.V(1).Info(msg)
+		conditions.SetReadyCondition(metav1.ConditionTrue, "HTTPScaledObjectReady", msg)
+	}
+	kedacontrollerutil.SetStatusConditions(r.Client, reqLogger, httpScaledObject, &conditions)
+	return ctrl.Result{}, err
+}
+
+// reconcileHTTPScaledObject ensures the ScaledObject generated for the HTTPScaledObject exists and is up-to-date
+func (r *HTTPScaledObjectReconciler) reconcileHTTPScaledObject(logger logr.Logger, httpScaledObject *kedav1alpha1.HTTPScaledObject) (string, error) {
+	if httpScaledObject.Spec.Host == "" {
+		return "HTTPScaledObject doesn't have correct host specification", fmt.Errorf("HTTPScaledObject.spec.host is missing")
+	}
+	if httpScaledObject.Spec.ScaleTargetRef == nil || httpScaledObject.Spec.ScaleTargetRef.Name == "" {
+		return "HTTPScaledObject doesn't have correct scaleTargetRef specification", fmt.Errorf("HTTPScaledObject.spec.scaleTargetRef.name is missing")
+	}
+	if httpScaledObject.Spec.ScaleTargetRef.Service == "" || httpScaledObject.Spec.ScaleTargetRef.Port <= 0 {
+		return "HTTPScaledObject doesn't have correct scaleTargetRef specification", fmt.Errorf("HTTPScaledObject.spec.scaleTargetRef.service or port is missing")
+	}
+	// the interceptor forwards the requests of the host to the service, the pending requests would never be served otherwise
+	if err := r.checkTargetService(httpScaledObject); err != nil {
+		return "HTTPScaledObject's scaleTargetRef service isn't reachable", err
+	}
+
+	scaledObject, err := r.newScaledObjectForHTTPScaledObject(httpScaledObject)
+	if err != nil {
+		return "Failed to create new ScaledObject resource", err
+	}
+
+	foundScaledObject := &kedav1alpha1.ScaledObject{}
+	err = r.Client.Get(context.TODO(), types.NamespacedName{Name: scaledObject.Name, Namespace: scaledObject.Namespace}, foundScaledObject)
+	if err != nil && errors.IsNotFound(err) {
+		logger.Info("Creating a new ScaledObject", "ScaledObject.Namespace", scaledObject.Namespace, "ScaledObject.Name", scaledObject.Name)
+		if err = r.Client.Create(context.TODO(), scaledObject); err != nil {
+			return "Failed to create new ScaledObject in cluster", err
+		}
+	} else if err != nil {
+		return "Failed to get ScaledObject from cluster", err
+	} else if !metav1.IsControlledBy(foundScaledObject, httpScaledObject) {
+		return "ScaledObject with the same name is not managed by this HTTPScaledObject", fmt.Errorf("ScaledObject %s already exists", scaledObject.Name)
+	} else if !equality.Semantic.DeepDerivative(scaledObject.Spec, foundScaledObject.Spec) {
+		logger.V(1).Info("Found difference in the ScaledObject spec according to HTTPScaledObject", "currentScaledObject", foundScaledObject.Spec, "newScaledObject", scaledObject.Spec)
+		foundScaledObject.Spec = scaledObject.Spec
+		if err = r.Client.Update(context.TODO(), foundScaledObject); err != nil {
+			return "Failed to update ScaledObject", err
+		}
+		logger.Info("Updated ScaledObject according to HTTPScaledObject", "ScaledObject.Namespace", foundScaledObject.Namespace, "ScaledObject.Name", foundScaledObject.Name)
+	}
+
+	if httpScaledObject.Status.ScaledObjectName != scaledObject.Name {
+		patch := client.MergeFrom(httpScaledObject.DeepCopy())
+		httpScaledObject.Status.ScaledObjectName = scaledObject.Name
+		if err = r.Client.Status().Patch(context.TODO(), httpScaledObject, patch); err != nil {
+			return "Failed to update HTTPScaledObject status", err
+		}
+	}
+
+	return "HTTPScaledObject is defined correctly and is ready for scaling", nil
+}
+
+// checkTargetService returns an error if the service of the scaleTargetRef doesn't exist or doesn't expose the port
+func (r *HTTPScaledObjectReconciler) checkTargetService(httpScaledObject *kedav1alpha1.HTTPScaledObject) error {
+	target := httpScaledObject.Spec.ScaleTargetRef
+	service := &corev1.Service{}
+	if err := r.Client.Get(context.TODO(), types.NamespacedName{Name: target.Service, Namespace: httpScaledObject.Namespace}, service); err != nil {
+		if errors.IsNotFound(err) {
+			return fmt.Errorf("service %s not found", target.Service)
+		}
+		return err
+	}
+	for _, port := range service.Spec.Ports {
+		if port.Port == target.Port {
+			return nil
+		}
+	}
+	return fmt.Errorf("service %s doesn't expose the port %d", target.Service, target.Port)
+}
+
+// newScaledObjectForHTTPScaledObject returns ScaledObject with an http-interceptor trigger as it is specified in HTTPScaledObject
+func (r *HTTPScaledObjectReconciler) newScaledObjectForHTTPScaledObject(httpScaledObject *kedav1alpha1.HTTPScaledObject) (*kedav1alpha1.ScaledObject, error) {
+	interceptorAddress := httpScaledObject.Spec.InterceptorAddress
+	if interceptorAddress == "" {
+		interceptorAddress = kedav1alpha1.DefaultHTTPInterceptorAddress
+	}
+
+	targetPendingRequests := int32(defaultHTTPTargetPendingRequests)
+	if httpScaledObject.Spec.TargetPendingRequests != nil {
+		targetPendingRequests = *httpScaledObject.Spec.TargetPendingRequests
+	}
+
+	// scaling from zero is driven by the pending requests, so scale to zero is allowed if not configured otherwise
+	minReplicaCount := httpScaledObject.Spec.MinReplicaCount
+	if minReplicaCount == nil {
+		zero := int32(0)
+		minReplicaCount = &zero
+	}
+
+	scaledObject := &kedav1alpha1.ScaledObject{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      httpScaledObject.Name,
+			Namespace: httpScaledObject.Namespace,
+			Labels: map[string]string{
+				"app.kubernetes.io/name":       httpScaledObject.Name,
+				"app.kubernetes.io/version":    version.Version,
+				"app.kubernetes.io/part-of":    httpScaledObject.Name,
+				"app.kubernetes.io/managed-by": httpScaledObjectManagedByLabelValue,
+			},
+		},
+		Spec: kedav1alpha1.ScaledObjectSpec{
+			ScaleTargetRef: &kedav1alpha1.ScaleTarget{
+				Name:       httpScaledObject.Spec.ScaleTargetRef.Name,
+				APIVersion: httpScaledObject.Spec.ScaleTargetRef.APIVersion,
+				Kind:       httpScaledObject.Spec.ScaleTargetRef.Kind,
+			},
+			PollingInterval: httpScaledObject.Spec.PollingInterval,
+			CooldownPeriod:  httpScaledObject.Spec.CooldownPeriod,
+			MinReplicaCount: minReplicaCount,
+			MaxReplicaCount: httpScaledObject.Spec.MaxReplicaCount,
+			Triggers: []kedav1alpha1.ScaleTriggers{
+				{
+					Type: httpInterceptorTriggerType,
+					Metadata: map[string]string{
+						"host":                  httpScaledObject.Spec.Host,
+						"interceptorAddress":    interceptorAddress,
+						"targetPendingRequests": strconv.Itoa(int(targetPendingRequests)),
+					},
+				},
+			},
+		},
+	}
+
+	// Set HTTPScaledObject instance as the owner and controller
+	if err := controllerutil.SetControllerReference(httpScaledObject, scaledObject, r.Scheme); err != nil {
+		return nil, err
+	}
+
+	return scaledObject, nil
+}
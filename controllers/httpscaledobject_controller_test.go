@@ -0,0 +1,135 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+)
+
+func TestHTTPScaledObjectReconciler(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = kedav1alpha1.AddToScheme(scheme)
+
+	newHTTPScaledObject := func(service string, port int32) *kedav1alpha1.HTTPScaledObject {
+		return &kedav1alpha1.HTTPScaledObject{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "app", UID: "http-uid"},
+			Spec: kedav1alpha1.HTTPScaledObjectSpec{
+				Host:           "app.example.com",
+				ScaleTargetRef: &kedav1alpha1.HTTPScaleTarget{Name: "app", Service: service, Port: port},
+			},
+		}
+	}
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "app"},
+		Spec:       corev1.ServiceSpec{Ports: []corev1.ServicePort{{Name: "http", Port: 8080}}},
+	}
+	unmanaged := &kedav1alpha1.ScaledObject{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "app"}}
+
+	tests := []struct {
+		name             string
+		httpScaledObject *kedav1alpha1.HTTPScaledObject
+		objects          []runtime.Object
+		isError          bool
+	}{
+		{"service and port exposed", newHTTPScaledObject("app", 8080), []runtime.Object{service}, false},
+		{"no service", newHTTPScaledObject("", 8080), []runtime.Object{service}, true},
+		{"no port", newHTTPScaledObject("app", 0), []runtime.Object{service}, true},
+		{"service not found", newHTTPScaledObject("other", 8080), []runtime.Object{service}, true},
+		{"port not exposed", newHTTPScaledObject("app", 9090), []runtime.Object{service}, true},
+		{"ScaledObject not managed by the HTTPScaledObject", newHTTPScaledObject("app", 8080), []runtime.Object{service, unmanaged}, true},
+	}
+
+	for _, test := range tests {
+		kubeClient := fake.NewFakeClientWithScheme(scheme, append(test.objects, test.httpScaledObject.DeepCopy())...)
+		reconciler := &HTTPScaledObjectReconciler{Log: logf.Log, Client: kubeClient, Scheme: scheme}
+		_, err := reconciler.Reconcile(ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "app"}})
+		if (err != nil) != test.isError {
+			t.Errorf("%s: expected error %t, got %v", test.name, test.isError, err)
+		}
+
+		httpScaledObject := &kedav1alpha1.HTTPScaledObject{}
+		if err := kubeClient.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: "app"}, httpScaledObject); err != nil {
+			t.Fatal(err)
+		}
+		if ready := httpScaledObject.Status.Conditions.GetReadyCondition(); ready.IsTrue() == test.isError {
+			t.Errorf("%s: expected the Ready condition to be %t, got %s: %s", test.name, !test.isError, ready.Status, ready.Message)
+		}
+		if test.isError {
+			continue
+		}
+
+		scaledObject := &kedav1alpha1.ScaledObject{}
+		if err := kubeClient.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: "app"}, scaledObject); err != nil {
+			t.Fatalf("%s: expected the ScaledObject to be created: %s", test.name, err)
+		}
+		if !metav1.IsControlledBy(scaledObject, httpScaledObject) {
+			t.Errorf("%s: expected the ScaledObject to be controlled by the HTTPScaledObject", test.name)
+		}
+		if len(scaledObject.Spec.Triggers) != 1 || scaledObject.Spec.Triggers[0].Type != httpInterceptorTriggerType ||
+			scaledObject.Spec.Triggers[0].Metadata["host"] != "app.example.com" {
+			t.Errorf("%s: expected an http-interceptor trigger for the host, got %+v", test.name, scaledObject.Spec.Triggers)
+		}
+		if httpScaledObject.Status.ScaledObjectName != "app" {
+			t.Errorf("%s: expected the ScaledObject name in the status, got %q", test.name, httpScaledObject.Status.ScaledObjectName)
+		}
+	}
+}
+
+func TestHTTPScaledObjectReconcilerUpdatesTheScaledObject(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = kedav1alpha1.AddToScheme(scheme)
+
+	httpScaledObject := &kedav1alpha1.HTTPScaledObject{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "app", UID: "http-uid"},
+		Spec: kedav1alpha1.HTTPScaledObjectSpec{
+			Host:           "app.example.com",
+			ScaleTargetRef: &kedav1alpha1.HTTPScaleTarget{Name: "app", Service: "app", Port: 8080},
+		},
+	}
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "app"},
+		Spec:       corev1.ServiceSpec{Ports: []corev1.ServicePort{{Port: 8080}}},
+	}
+	kubeClient := fake.NewFakeClientWithScheme(scheme, httpScaledObject, service)
+	reconciler := &HTTPScaledObjectReconciler{Log: logf.Log, Client: kubeClient, Scheme: scheme}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "app"}}
+	if _, err := reconciler.Reconcile(req); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := kubeClient.Get(context.TODO(), req.NamespacedName, httpScaledObject); err != nil {
+		t.Fatal(err)
+	}
+	maxReplicaCount := int32(20)
+	httpScaledObject.Spec.MaxReplicaCount = &maxReplicaCount
+	httpScaledObject.Spec.Host = "www.example.com"
+	if err := kubeClient.Update(context.TODO(), httpScaledObject); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := reconciler.Reconcile(req); err != nil {
+		t.Fatal(err)
+	}
+
+	scaledObject := &kedav1alpha1.ScaledObject{}
+	if err := kubeClient.Get(context.TODO(), req.NamespacedName, scaledObject); err != nil {
+		t.Fatal(err)
+	}
+	if scaledObject.Spec.MaxReplicaCount == nil || *scaledObject.Spec.MaxReplicaCount != 20 {
+		t.Errorf("Expected the maxReplicaCount to be updated, got %v", scaledObject.Spec.MaxReplicaCount)
+	}
+	if host := scaledObject.Spec.Triggers[0].Metadata["host"]; host != "www.example.com" {
+		t.Errorf("Expected the host of the trigger to be updated, got %s", host)
+	}
+}
@@ -0,0 +1,135 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+	"github.com/kedacore/keda/pkg/eventemitter"
+)
+
+// OrphanedHPASweepConfig configures the sweep of the HPAs managed by KEDA whose ScaledObject is gone,
+// e.g. after a restore of etcd or when the finalizer of the ScaledObject was removed before it ran
+type OrphanedHPASweepConfig struct {
+	// Interval is the time between two sweeps, the HPAs aren't swept if not set
+	Interval time.Duration
+	// DryRun only reports the orphaned HPAs, they aren't deleted
+	DryRun bool
+}
+
+// orphanedHPASweeper deletes the orphaned HPAs of the watched namespaces at every interval of the sweep
+type orphanedHPASweeper struct {
+	client       client.Client
+	logger       logr.Logger
+	eventEmitter eventemitter.EventEmitter
+	namespaces   []string
+	config       OrphanedHPASweepConfig
+}
+
+// Start sweeps the orphaned HPAs once the cache is synced, then at every interval until the stop channel is closed
+func (s *orphanedHPASweeper) Start(stop <-chan struct{}) error {
+	s.logger.Info("Starting the sweep of the orphaned HPAs", "interval", s.config.Interval, "dryRun", s.config.DryRun)
+	ticker := time.NewTicker(s.config.Interval)
+	defer ticker.Stop()
+	for {
+		s.sweep(context.TODO())
+		select {
+		case <-ticker.C:
+		case <-stop:
+			return nil
+		}
+	}
+}
+
+// NeedLeaderElection returns true, the replicas of the operator would otherwise delete the same HPAs
+func (s *orphanedHPASweeper) NeedLeaderElection() bool {
+	return true
+}
+
+// sweep deletes, or only reports in dry-run mode, the orphaned HPAs and emits an event for each of them
+func (s *orphanedHPASweeper) sweep(ctx context.Context) {
+	namespaces := s.namespaces
+	if len(namespaces) == 0 {
+		namespaces = []string{metav1.NamespaceAll}
+	}
+
+	for _, namespace := range namespaces {
+		hpas := &autoscalingv2beta2.HorizontalPodAutoscalerList{}
+		if err := s.client.List(ctx, hpas, client.InNamespace(namespace), client.MatchingLabels{"app.kubernetes.io/managed-by": "keda-operator"}); err != nil {
+			s.logger.Error(err, "Failed to list the HPAs managed by KEDA", "namespace", namespace)
+			continue
+		}
+		for i := range hpas.Items {
+			hpa := &hpas.Items[i]
+			scaledObjectName, reason, err := s.getOrphanedHPAReason(ctx, hpa)
+			if err != nil {
+				s.logger.Error(err, "Failed to get the ScaledObject of the HPA", "HPA.Namespace", hpa.Namespace, "HPA.Name", hpa.Name)
+				continue
+			}
+			if reason != "" {
+				s.cleanUpOrphanedHPA(ctx, hpa, scaledObjectName, reason)
+			}
+		}
+	}
+}
+
+// getOrphanedHPAReason returns the name of the ScaledObject of the HPA, from its controller or the part-of label,
+// and the reason the HPA is orphaned. The reason is empty if the HPA belongs to an existing ScaledObject
+func (s *orphanedHPASweeper) getOrphanedHPAReason(ctx context.Context, hpa *autoscalingv2beta2.HorizontalPodAutoscaler) (string, string, error) {
+	var scaledObjectName string
+	var scaledObjectUID types.UID
+	if owner := metav1.GetControllerOf(hpa); owner != nil {
+		if owner.Kind != "ScaledObject" {
+			return "", "", nil
+		}
+		scaledObjectName = owner.Name
+		scaledObjectUID = owner.UID
+	} else {
+		scaledObjectName = hpa.Labels["app.kubernetes.io/part-of"]
+	}
+	if scaledObjectName == "" {
+		return "", "", nil
+	}
+
+	scaledObject := &kedav1alpha1.ScaledObject{}
+	err := s.client.Get(ctx, types.NamespacedName{Namespace: hpa.Namespace, Name: scaledObjectName}, scaledObject)
+	if errors.IsNotFound(err) {
+		return scaledObjectName, fmt.Sprintf("its ScaledObject %s doesn't exist", scaledObjectName), nil
+	} else if err != nil {
+		return scaledObjectName, "", err
+	}
+	if scaledObjectUID != "" && scaledObjectUID != scaledObject.UID {
+		return scaledObjectName, fmt.Sprintf("its ScaledObject %s was deleted, the ScaledObject %s was recreated", scaledObjectName, scaledObjectName), nil
+	}
+	return scaledObjectName, "", nil
+}
+
+// cleanUpOrphanedHPA deletes the orphaned HPA, unless it was replaced since it was listed, or only reports it in dry-run mode
+func (s *orphanedHPASweeper) cleanUpOrphanedHPA(ctx context.Context, hpa *autoscalingv2beta2.HorizontalPodAutoscaler, scaledObjectName, reason string) {
+	logger := s.logger.WithValues("HPA.Namespace", hpa.Namespace, "HPA.Name", hpa.Name)
+	if s.config.DryRun {
+		message := fmt.Sprintf("Found the orphaned HPA %s, %s. It isn't deleted in dry-run mode", hpa.Name, reason)
+		logger.Info(message)
+		s.eventEmitter.Emit("ScaledObject", hpa.Namespace, scaledObjectName, eventemitter.OrphanedHPAFoundType, message)
+		return
+	}
+
+	uid := hpa.UID
+	if err := s.client.Delete(ctx, hpa, client.Preconditions{UID: &uid}); err != nil {
+		if !errors.IsNotFound(err) && !errors.IsConflict(err) {
+			logger.Error(err, "Failed to delete the orphaned HPA")
+		}
+		return
+	}
+	message := fmt.Sprintf("Deleted the orphaned HPA %s, %s", hpa.Name, reason)
+	logger.Info(message)
+	s.eventEmitter.Emit("ScaledObject", hpa.Namespace, scaledObjectName, eventemitter.OrphanedHPADeletedType, message)
+}
@@ -0,0 +1,82 @@
+package controllers
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+	"github.com/kedacore/keda/pkg/eventemitter"
+)
+
+func newTestSweptHPA(name string, labels map[string]string, owner *metav1.OwnerReference) *autoscalingv2beta2.HorizontalPodAutoscaler {
+	hpa := &autoscalingv2beta2.HorizontalPodAutoscaler{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name, UID: types.UID(name + "-uid"), Labels: labels}}
+	if owner != nil {
+		hpa.OwnerReferences = []metav1.OwnerReference{*owner}
+	}
+	return hpa
+}
+
+func newTestScaledObjectOwner(name string, uid types.UID) *metav1.OwnerReference {
+	isController := true
+	return &metav1.OwnerReference{APIVersion: "keda.sh/v1alpha1", Kind: "ScaledObject", Name: name, UID: uid, Controller: &isController}
+}
+
+func TestSweepOrphanedHPAs(t *testing.T) {
+	managedBy := map[string]string{"app.kubernetes.io/managed-by": "keda-operator"}
+	tests := []struct {
+		dryRun             bool
+		expectedHPAs       []string
+		expectedEventTypes []string
+	}{
+		{false, []string{"keda-hpa-app", "keda-hpa-orphaned-by-user", "other-hpa"}, []string{eventemitter.OrphanedHPADeletedType, eventemitter.OrphanedHPADeletedType, eventemitter.OrphanedHPADeletedType}},
+		{true, []string{"keda-hpa-app", "keda-hpa-deleted", "keda-hpa-orphaned-by-user", "keda-hpa-recreated", "keda-hpa-unowned", "other-hpa"}, []string{eventemitter.OrphanedHPAFoundType, eventemitter.OrphanedHPAFoundType, eventemitter.OrphanedHPAFoundType}},
+	}
+
+	for _, test := range tests {
+		scheme := runtime.NewScheme()
+		_ = clientgoscheme.AddToScheme(scheme)
+		_ = kedav1alpha1.AddToScheme(scheme)
+		objects := []runtime.Object{
+			&kedav1alpha1.ScaledObject{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "app", UID: "app-uid"}},
+			&kedav1alpha1.ScaledObject{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "recreated", UID: "recreated-uid"}},
+			newTestSweptHPA("keda-hpa-app", managedBy, newTestScaledObjectOwner("app", "app-uid")),
+			newTestSweptHPA("keda-hpa-deleted", managedBy, newTestScaledObjectOwner("deleted", "deleted-uid")),
+			newTestSweptHPA("keda-hpa-recreated", managedBy, newTestScaledObjectOwner("recreated", "restored-uid")),
+			newTestSweptHPA("keda-hpa-unowned", map[string]string{"app.kubernetes.io/managed-by": "keda-operator", "app.kubernetes.io/part-of": "unowned"}, nil),
+			// the HPAs orphaned on purpose by the finalizer aren't managed by KEDA anymore
+			newTestSweptHPA("keda-hpa-orphaned-by-user", map[string]string{"app.kubernetes.io/part-of": "orphaned-by-user"}, nil),
+			newTestSweptHPA("other-hpa", nil, nil),
+		}
+		client := fake.NewFakeClientWithScheme(scheme, objects...)
+		emitter := &recordingEventEmitter{}
+		sweeper := &orphanedHPASweeper{client: client, logger: logf.Log, eventEmitter: emitter, config: OrphanedHPASweepConfig{DryRun: test.dryRun}}
+
+		sweeper.sweep(context.Background())
+
+		hpas := &autoscalingv2beta2.HorizontalPodAutoscalerList{}
+		if err := client.List(context.Background(), hpas); err != nil {
+			t.Fatalf("Unexpected error %s", err)
+		}
+		names := []string{}
+		for _, hpa := range hpas.Items {
+			names = append(names, hpa.Name)
+		}
+		sort.Strings(names)
+		if !reflect.DeepEqual(names, test.expectedHPAs) {
+			t.Errorf("dry run %t: expected the HPAs %v, got %v", test.dryRun, test.expectedHPAs, names)
+		}
+		if !reflect.DeepEqual(emitter.eventTypes, test.expectedEventTypes) {
+			t.Errorf("dry run %t: expected the events %v, got %v", test.dryRun, test.expectedEventTypes, emitter.eventTypes)
+		}
+	}
+}
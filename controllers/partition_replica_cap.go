@@ -0,0 +1,58 @@
+package controllers
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+	"github.com/kedacore/keda/pkg/scalers"
+	kedautil "github.com/kedacore/keda/pkg/util"
+)
+
+// getScalerPartitionCount returns the partition count of the stream read by the scaler, 0 if it doesn't read a partitioned stream
+// or if the partition count can't be read
+func getScalerPartitionCount(logger logr.Logger, scaler scalers.Scaler) int64 {
+	partitionCountScaler, ok := scalers.UnwrapScaler(scaler).(scalers.PartitionCountScaler)
+	if !ok {
+		return 0
+	}
+	partitionCount, err := partitionCountScaler.PartitionCount(context.TODO())
+	if err != nil {
+		logger.V(1).Info("Failed to get the partition count of the trigger, the maximum replica count isn't capped", "scaler", scalers.ScalerName(scaler), "error", err.Error())
+		return 0
+	}
+	return partitionCount
+}
+
+// getPartitionReplicaCap returns the highest partition count of the triggers driving the HPA,
+// nil if one of them doesn't read a partitioned stream as its replicas aren't bound to the partitions
+func getPartitionReplicaCap(partitionCounts []int64) *int32 {
+	if len(partitionCounts) == 0 {
+		return nil
+	}
+	var replicaCap int64
+	for _, partitionCount := range partitionCounts {
+		if partitionCount <= 0 {
+			return nil
+		}
+		if partitionCount > replicaCap {
+			replicaCap = partitionCount
+		}
+	}
+	result := int32(replicaCap)
+	return &result
+}
+
+// capHPAMaxReplicasToPartitions returns the maximum replica count capped at the partition count of the triggers of the ScaledObject,
+// the cap is never below the MinReplicas of the HPA
+func capHPAMaxReplicasToPartitions(scaledObject *kedav1alpha1.ScaledObject, maxReplicas int32) int32 {
+	replicaCap := scaledObject.Status.PartitionReplicaCap
+	if replicaCap == nil || *replicaCap >= maxReplicas || (scaledObject.Spec.Advanced != nil && scaledObject.Spec.Advanced.DisablePartitionReplicaCap) {
+		return maxReplicas
+	}
+	if minReplicas := kedautil.GetHPAMinReplicas(scaledObject); *replicaCap < minReplicas {
+		return minReplicas
+	}
+	return *replicaCap
+}
@@ -0,0 +1,116 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+	"github.com/kedacore/keda/pkg/scalers"
+)
+
+type fakePartitionScaler struct {
+	fakeMetricScaler
+	partitionCount int64
+	err            error
+}
+
+func (s *fakePartitionScaler) PartitionCount(ctx context.Context) (int64, error) {
+	return s.partitionCount, s.err
+}
+
+func TestGetPartitionReplicaCap(t *testing.T) {
+	tests := []struct {
+		name            string
+		partitionCounts []int64
+		replicaCap      int32
+	}{
+		{"no trigger", nil, 0},
+		{"single partitioned trigger", []int64{6}, 6},
+		{"highest partition count", []int64{6, 12}, 12},
+		{"trigger not partitioned", []int64{6, 0}, 0},
+	}
+
+	for _, test := range tests {
+		replicaCap := getPartitionReplicaCap(test.partitionCounts)
+		if test.replicaCap == 0 && replicaCap != nil {
+			t.Errorf("%s: expected no cap, got %d", test.name, *replicaCap)
+		}
+		if test.replicaCap != 0 && (replicaCap == nil || *replicaCap != test.replicaCap) {
+			t.Errorf("%s: expected a cap of %d, got %v", test.name, test.replicaCap, replicaCap)
+		}
+	}
+}
+
+func TestGetScalerPartitionCount(t *testing.T) {
+	if count := getScalerPartitionCount(logf.Log, &fakeMetricScaler{}); count != 0 {
+		t.Errorf("Expected no partition count for a scaler not partitioned, got %d", count)
+	}
+	if count := getScalerPartitionCount(logf.Log, &fakePartitionScaler{partitionCount: 8}); count != 8 {
+		t.Errorf("Expected 8 partitions, got %d", count)
+	}
+	if count := getScalerPartitionCount(logf.Log, &fakePartitionScaler{err: fmt.Errorf("unreachable")}); count != 0 {
+		t.Errorf("Expected no partition count if it can't be read, got %d", count)
+	}
+}
+
+func TestCapHPAMaxReplicasToPartitions(t *testing.T) {
+	replicaCap := int32(6)
+	minReplicaCount := int32(8)
+
+	scaledObject := &kedav1alpha1.ScaledObject{}
+	if maxReplicas := getHPAMaxReplicas(scaledObject); maxReplicas != defaultHPAMaxReplicas {
+		t.Errorf("Expected the default MaxReplicas without a cap, got %d", maxReplicas)
+	}
+
+	scaledObject.Status.PartitionReplicaCap = &replicaCap
+	if maxReplicas := getHPAMaxReplicas(scaledObject); maxReplicas != 6 {
+		t.Errorf("Expected the MaxReplicas capped at the partition count, got %d", maxReplicas)
+	}
+
+	// the cap is never below the MinReplicas of the HPA
+	scaledObject.Spec.MinReplicaCount = &minReplicaCount
+	if maxReplicas := getHPAMaxReplicas(scaledObject); maxReplicas != 8 {
+		t.Errorf("Expected the MaxReplicas capped at the MinReplicas, got %d", maxReplicas)
+	}
+
+	scaledObject.Spec.Advanced = &kedav1alpha1.AdvancedConfig{DisablePartitionReplicaCap: true}
+	if maxReplicas := getHPAMaxReplicas(scaledObject); maxReplicas != defaultHPAMaxReplicas {
+		t.Errorf("Expected the default MaxReplicas with the cap disabled, got %d", maxReplicas)
+	}
+}
+
+func TestGetScaledObjectMetricSpecsPartitionReplicaCap(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = kedav1alpha1.AddToScheme(scheme)
+
+	scaledObject := &kedav1alpha1.ScaledObject{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "app"},
+		Spec: kedav1alpha1.ScaledObjectSpec{Triggers: []kedav1alpha1.ScaleTriggers{
+			{Type: "kafka"},
+			{Type: "azure-eventhub"},
+			{Type: "prometheus", UseForActivationOnly: true},
+		}},
+	}
+	reconciler := &ScaledObjectReconciler{
+		Client: fake.NewFakeClientWithScheme(scheme, scaledObject),
+		scaleHandler: &fakeScaleHandler{scalers: []scalers.Scaler{
+			&fakePartitionScaler{fakeMetricScaler: fakeMetricScaler{metricName: "kafka"}, partitionCount: 4},
+			&fakePartitionScaler{fakeMetricScaler: fakeMetricScaler{metricName: "eventhub"}, partitionCount: 10},
+			&fakeMetricScaler{metricName: "prometheus"},
+		}},
+	}
+
+	// the trigger used for activation only doesn't drive the HPA
+	if _, err := reconciler.getScaledObjectMetricSpecs(logf.Log, scaledObject); err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	if replicaCap := scaledObject.Status.PartitionReplicaCap; replicaCap == nil || *replicaCap != 10 {
+		t.Errorf("Expected a cap of 10 replicas in the status, got %v", replicaCap)
+	}
+}
@@ -0,0 +1,70 @@
+package controllers
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/go-logr/logr"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+	kedacontrollerutil "github.com/kedacore/keda/controllers/util"
+)
+
+// getTriggersSummary returns the trigger types in the order of their first trigger,
+// followed by the number of triggers of the type if there are several, e.g. cpu,prometheus(2)
+func getTriggersSummary(triggers []kedav1alpha1.ScaleTriggers) string {
+	var types []string
+	counts := make(map[string]int)
+	for _, trigger := range triggers {
+		if counts[trigger.Type] == 0 {
+			types = append(types, trigger.Type)
+		}
+		counts[trigger.Type]++
+	}
+
+	summary := make([]string, len(types))
+	for i, triggerType := range types {
+		if counts[triggerType] > 1 {
+			summary[i] = fmt.Sprintf("%s(%d)", triggerType, counts[triggerType])
+		} else {
+			summary[i] = triggerType
+		}
+	}
+	return strings.Join(summary, ",")
+}
+
+// updatePrinterColumnsStatus stores the replica counts and the triggers summary shown by kubectl get in the status of the ScaledObject if they changed
+func (r *ScaledObjectReconciler) updatePrinterColumnsStatus(logger logr.Logger, scaledObject *kedav1alpha1.ScaledObject) error {
+	minReplicas := int32(0)
+	if scaledObject.Spec.MinReplicaCount != nil {
+		minReplicas = *scaledObject.Spec.MinReplicaCount
+	}
+	maxReplicas := getHPAMaxReplicas(scaledObject)
+	triggers := getTriggersSummary(scaledObject.Spec.Triggers)
+
+	status := scaledObject.Status.DeepCopy()
+	status.MinReplicas = &minReplicas
+	status.MaxReplicas = &maxReplicas
+	status.Triggers = triggers
+	if reflect.DeepEqual(status, &scaledObject.Status) {
+		return nil
+	}
+	return kedacontrollerutil.UpdateScaledObjectStatus(r.Client, logger, scaledObject, status)
+}
+
+// updatePrinterColumnsStatus stores the replica counts and the triggers summary shown by kubectl get in the status of the ScaledJob if they changed
+func (r *ScaledJobReconciler) updatePrinterColumnsStatus(logger logr.Logger, scaledJob *kedav1alpha1.ScaledJob) error {
+	minReplicas := int32(scaledJob.MinReplicaCount())
+	maxReplicas := int32(scaledJob.MaxReplicaCount())
+	triggers := getTriggersSummary(scaledJob.Spec.Triggers)
+
+	status := scaledJob.Status.DeepCopy()
+	status.MinReplicas = &minReplicas
+	status.MaxReplicas = &maxReplicas
+	status.Triggers = triggers
+	if reflect.DeepEqual(status, &scaledJob.Status) {
+		return nil
+	}
+	return kedacontrollerutil.UpdateScaledJobStatus(r.Client, logger, scaledJob, status)
+}
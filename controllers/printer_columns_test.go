@@ -0,0 +1,68 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+)
+
+func TestGetTriggersSummary(t *testing.T) {
+	tests := []struct {
+		types    []string
+		expected string
+	}{
+		{nil, ""},
+		{[]string{"cpu"}, "cpu"},
+		{[]string{"cpu", "prometheus"}, "cpu,prometheus"},
+		{[]string{"prometheus", "cpu", "prometheus", "prometheus"}, "prometheus(3),cpu"},
+	}
+
+	for _, test := range tests {
+		var triggers []kedav1alpha1.ScaleTriggers
+		for _, triggerType := range test.types {
+			triggers = append(triggers, kedav1alpha1.ScaleTriggers{Type: triggerType})
+		}
+		if summary := getTriggersSummary(triggers); summary != test.expected {
+			t.Errorf("Expected %q for %v, got %q", test.expected, test.types, summary)
+		}
+	}
+}
+
+func TestUpdatePrinterColumnsStatus(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = kedav1alpha1.AddToScheme(scheme)
+
+	minReplicas := int32(2)
+	scaledObject := &kedav1alpha1.ScaledObject{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test"},
+		Spec: kedav1alpha1.ScaledObjectSpec{
+			MinReplicaCount: &minReplicas,
+			Triggers:        []kedav1alpha1.ScaleTriggers{{Type: "cpu"}, {Type: "kafka"}},
+		},
+	}
+	r := &ScaledObjectReconciler{Client: fake.NewFakeClientWithScheme(scheme, scaledObject)}
+
+	if err := r.updatePrinterColumnsStatus(logf.Log, scaledObject); err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	if *scaledObject.Status.MinReplicas != 2 || *scaledObject.Status.MaxReplicas != defaultHPAMaxReplicas || scaledObject.Status.Triggers != "cpu,kafka" {
+		t.Errorf("Unexpected status %d %d %q", *scaledObject.Status.MinReplicas, *scaledObject.Status.MaxReplicas, scaledObject.Status.Triggers)
+	}
+
+	stored := &kedav1alpha1.ScaledObject{}
+	if err := r.Client.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: "test"}, stored); err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	if stored.Status.Triggers != "cpu,kafka" {
+		t.Errorf("Expected the stored triggers summary cpu,kafka, got %q", stored.Status.Triggers)
+	}
+}
@@ -3,6 +3,7 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/go-logr/logr"
 	batchv1 "k8s.io/api/batch/v1"
@@ -15,7 +16,12 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+	kedacontrollerutil "github.com/kedacore/keda/controllers/util"
+	"github.com/kedacore/keda/pkg/eventemitter"
+	"github.com/kedacore/keda/pkg/globalconfig"
+	prommetrics "github.com/kedacore/keda/pkg/metrics"
 	"github.com/kedacore/keda/pkg/scaling"
+	"github.com/kedacore/keda/pkg/tracing"
 )
 
 // +kubebuilder:rbac:groups=keda.sh,resources=scaledjobs;scaledjobs/finalizers;scaledjobs/status,verbs="*"
@@ -28,26 +34,34 @@ type ScaledJobReconciler struct {
 	Log          logr.Logger
 	Scheme       *runtime.Scheme
 	scaleHandler scaling.ScaleHandler
+	eventEmitter eventemitter.EventEmitter
 }
 
 // SetupWithManager initializes the ScaledJobReconciler instance and starts a new controller managed by the passed Manager instance.
 func (r *ScaledJobReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	r.scaleHandler = scaling.NewScaleHandler(mgr.GetClient(), nil, mgr.GetScheme())
+	r.eventEmitter = eventemitter.NewEventEmitter(mgr.GetClient())
 
 	return ctrl.NewControllerManagedBy(mgr).
 		// Ignore updates to ScaledJob Status (in this case metadata.Generation does not change)
 		// so reconcile loop is not started on Status updates
-		For(&kedav1alpha1.ScaledJob{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		For(&kedav1alpha1.ScaledJob{}, builder.WithPredicates(predicate.Or(predicate.GenerationChangedPredicate{}, kedacontrollerutil.LabelsChangedPredicate))).
 		Complete(r)
 }
 
 // Reconcile performs reconciliation on the identified ScaledJob resource based on the request information passed, returns the result and an error (if any).
-func (r *ScaledJobReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
+func (r *ScaledJobReconciler) Reconcile(req ctrl.Request) (_ ctrl.Result, err error) {
 	reqLogger := r.Log.WithValues("ScaledJob.Namespace", req.Namespace, "ScaledJob.Name", req.Name)
+	ctx, span := tracing.StartSpan(context.TODO(), "ScaledJobReconciler.Reconcile", tracing.ObjectAttributes("ScaledJob", req.Namespace, req.Name)...)
+	start := time.Now()
+	defer func() {
+		tracing.EndSpan(ctx, span, err)
+		prommetrics.RecordReconcile("ScaledJob", req.Namespace, req.Name, start, err)
+	}()
 
 	// Fetch the ScaledJob instance
 	scaledJob := &kedav1alpha1.ScaledJob{}
-	err := r.Client.Get(context.TODO(), req.NamespacedName, scaledJob)
+	err = r.Client.Get(ctx, req.NamespacedName, scaledJob)
 	if err != nil {
 		if errors.IsNotFound(err) {
 			// Request object not found, could have been deleted after reconcile request.
@@ -60,20 +74,41 @@ func (r *ScaledJobReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 		return ctrl.Result{}, err
 	}
 
+	// the scale loop is stopped if the ScaledJob stopped matching
+	if !globalconfig.Get().IsWatched(scaledJob.Namespace, scaledJob.Labels) {
+		reqLogger.V(1).Info("Ignoring ScaledJob, its namespace or labels aren't watched")
+		return ctrl.Result{}, r.scaleHandler.DeleteScalableObject(scaledJob)
+	}
+
 	reqLogger.Info("Reconciling ScaledJob")
 
 	var errMsg string
 	if scaledJob.Spec.JobTargetRef != nil {
 		reqLogger.Info("Detected ScaleType = Job")
+		if !scaledJob.Status.Conditions.AreInitialized() {
+			scaledJob.Status.Conditions = *kedav1alpha1.GetInitializedConditions()
+		}
 		conditions := scaledJob.Status.Conditions.DeepCopy()
 		msg, err := r.reconcileScaledJob(reqLogger, scaledJob)
 		if err != nil {
 			reqLogger.Error(err, msg)
-			conditions.SetReadyCondition(metav1.ConditionFalse, "ScaledJobCheckFailed", msg)
+			if _, ok := err.(*policyViolationError); ok {
+				if readyCondition := conditions.GetReadyCondition(); readyCondition.Reason != "ScalingPolicyViolation" {
+					r.eventEmitter.Emit("ScaledJob", scaledJob.Namespace, scaledJob.Name, eventemitter.ScaledJobPolicyViolationType, err.Error())
+				}
+				conditions.SetReadyCondition(metav1.ConditionFalse, "ScalingPolicyViolation", err.Error())
+				conditions.SetPausedCondition(metav1.ConditionTrue, "ScalingPolicyViolation", "Scaling is not performed because the ScaledJob violates the scaling policy")
+			} else {
+				conditions.SetReadyCondition(metav1.ConditionFalse, "ScaledJobCheckFailed", msg)
+			}
 			conditions.SetActiveCondition(metav1.ConditionUnknown, "UnknownState", "ScaledJob check failed")
 		} else {
 			reqLogger.V(1).Info(msg)
 			conditions.SetReadyCondition(metav1.ConditionTrue, "ScaledJobReady", msg)
+			conditions.SetPausedCondition(metav1.ConditionFalse, "ScalingEnabled", "Scaling is performed")
+		}
+		if statusErr := kedacontrollerutil.SetStatusConditions(r.Client, reqLogger, scaledJob, &conditions); statusErr != nil && err == nil {
+			err = statusErr
 		}
 
 		return ctrl.Result{}, err
@@ -92,6 +127,28 @@ func (r *ScaledJobReconciler) reconcileScaledJob(logger logr.Logger, scaledJob *
 		return msg, err
 	}
 
+	// the triggers exceeding the limits of the global configuration aren't evaluated
+	if err := globalconfig.Get().CheckTriggers(scaledJob.Spec.Triggers); err != nil {
+		if deleteErr := r.scaleHandler.DeleteScalableObject(scaledJob); deleteErr != nil {
+			logger.Error(deleteErr, "Failed to stop the scale loop of the ScaledJob violating the scaling policy")
+		}
+		return "ScaledJob violates the scaling policy of the cluster", &policyViolationError{message: err.Error()}
+	}
+
+	if err := validateScaledJobThresholdTypes(scaledJob.Spec.Triggers); err != nil {
+		return "ScaledJob has an invalid thresholdType", err
+	}
+
+	if scaledJob.MinReplicaCount() > scaledJob.MaxReplicaCount() {
+		err := fmt.Errorf("minReplicaCount %d must not be greater than maxReplicaCount %d", scaledJob.MinReplicaCount(), scaledJob.MaxReplicaCount())
+		return "ScaledJob has an invalid minReplicaCount", err
+	}
+
+	// Show the replica counts and the triggers in kubectl get
+	if err := r.updatePrinterColumnsStatus(logger, scaledJob); err != nil {
+		return "Failed to update the status of the ScaledJob", err
+	}
+
 	// scaledJob was created or modified - let's start a new ScaleLoop
 	err = r.requestScaleLoop(logger, scaledJob)
 	if err != nil {
@@ -0,0 +1,152 @@
+package controllers
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+	"knative.dev/pkg/apis/duck"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+)
+
+// The kinds of the objects the triggers of a ScaledObject read their authentication from
+const (
+	authRefKindTriggerAuthentication = "TriggerAuthentication"
+	authRefKindSecret                = "Secret"
+	authRefKindConfigMap             = "ConfigMap"
+)
+
+// authRef is a TriggerAuthentication, Secret or ConfigMap the triggers of a ScaledObject may read
+type authRef struct {
+	kind string
+	name string
+}
+
+// triggersReferenceAuth returns true if the triggers read the reference, through the authenticationRef of a trigger,
+// the secretTargetRef of a TriggerAuthentication referenced by a trigger or the environment of the containers of the pod spec
+func triggersReferenceAuth(ref authRef, triggers []kedav1alpha1.ScaleTriggers, triggerAuths map[string]*kedav1alpha1.TriggerAuthentication, podSpec *corev1.PodSpec) bool {
+	for _, trigger := range triggers {
+		if trigger.AuthenticationRef == nil || trigger.AuthenticationRef.Name == "" {
+			continue
+		}
+		if ref.kind == authRefKindTriggerAuthentication && trigger.AuthenticationRef.Name == ref.name {
+			return true
+		}
+		if triggerAuth, ok := triggerAuths[trigger.AuthenticationRef.Name]; ok && ref.kind == authRefKindSecret {
+			for _, secretRef := range triggerAuth.Spec.SecretTargetRef {
+				if secretRef.Name == ref.name {
+					return true
+				}
+			}
+		}
+	}
+	return podSpec != nil && podSpecReferencesAuth(ref, podSpec)
+}
+
+// podSpecReferencesAuth returns true if the environment of a container of the pod spec is read from the Secret or ConfigMap
+func podSpecReferencesAuth(ref authRef, podSpec *corev1.PodSpec) bool {
+	for _, container := range podSpec.Containers {
+		for _, source := range container.EnvFrom {
+			if ref.kind == authRefKindSecret && source.SecretRef != nil && source.SecretRef.Name == ref.name {
+				return true
+			}
+			if ref.kind == authRefKindConfigMap && source.ConfigMapRef != nil && source.ConfigMapRef.Name == ref.name {
+				return true
+			}
+		}
+		for _, envVar := range container.Env {
+			if envVar.ValueFrom == nil {
+				continue
+			}
+			if ref.kind == authRefKindSecret && envVar.ValueFrom.SecretKeyRef != nil && envVar.ValueFrom.SecretKeyRef.Name == ref.name {
+				return true
+			}
+			if ref.kind == authRefKindConfigMap && envVar.ValueFrom.ConfigMapKeyRef != nil && envVar.ValueFrom.ConfigMapKeyRef.Name == ref.name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// authRefMapper returns the mapper of the TriggerAuthentications, Secrets or ConfigMaps of the kind to the ScaledObjects of their namespace
+// reading them. The generation of the ScaledObjects is forgotten so the reconcile starts a new scale loop, the scalers built once per scale
+// loop like the push scalers then use the new authentication without an edit of the ScaledObject.
+func (r *ScaledObjectReconciler) authRefMapper(kind string) handler.Mapper {
+	return handler.ToRequestsFunc(func(obj handler.MapObject) []reconcile.Request {
+		ref := authRef{kind: kind, name: obj.Meta.GetName()}
+		scaledObjects, err := r.getScaledObjectsReferencingAuth(context.TODO(), obj.Meta.GetNamespace(), ref)
+		if err != nil {
+			r.Log.Error(err, "Failed to find the ScaledObjects referencing the authentication", "kind", kind, "namespace", obj.Meta.GetNamespace(), "name", ref.name)
+			return nil
+		}
+
+		requests := make([]reconcile.Request, 0, len(scaledObjects))
+		for i := range scaledObjects {
+			scaledObject := &scaledObjects[i]
+			if key, err := cache.MetaNamespaceKeyFunc(scaledObject); err == nil {
+				r.scaledObjectsGenerations.Delete(key)
+			}
+			r.Log.V(1).Info("Reloading the scalers of the ScaledObject, its authentication changed", "ScaledObject.Namespace", scaledObject.Namespace, "ScaledObject.Name", scaledObject.Name, "kind", kind, "name", ref.name)
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: scaledObject.Namespace, Name: scaledObject.Name}})
+		}
+		return requests
+	})
+}
+
+// getScaledObjectsReferencingAuth returns the ScaledObjects of the namespace whose triggers read the reference
+func (r *ScaledObjectReconciler) getScaledObjectsReferencingAuth(ctx context.Context, namespace string, ref authRef) ([]kedav1alpha1.ScaledObject, error) {
+	scaledObjects := &kedav1alpha1.ScaledObjectList{}
+	if err := r.Client.List(ctx, scaledObjects, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+
+	triggerAuths := make(map[string]*kedav1alpha1.TriggerAuthentication)
+	if ref.kind == authRefKindSecret {
+		triggerAuthList := &kedav1alpha1.TriggerAuthenticationList{}
+		if err := r.Client.List(ctx, triggerAuthList, client.InNamespace(namespace)); err != nil {
+			return nil, err
+		}
+		for i := range triggerAuthList.Items {
+			triggerAuths[triggerAuthList.Items[i].Name] = &triggerAuthList.Items[i]
+		}
+	}
+
+	var result []kedav1alpha1.ScaledObject
+	for _, scaledObject := range scaledObjects.Items {
+		var podSpec *corev1.PodSpec
+		if ref.kind != authRefKindTriggerAuthentication {
+			podSpec = r.getScaleTargetPodSpec(ctx, r.Log, &scaledObject)
+		}
+		if triggersReferenceAuth(ref, scaledObject.Spec.Triggers, triggerAuths, podSpec) {
+			result = append(result, scaledObject)
+		}
+	}
+	return result, nil
+}
+
+// getScaleTargetPodSpec returns the pod spec of the ScaleTarget of the ScaledObject, nil if the ScaleTarget isn't resolved yet or has no pod template
+func (r *ScaledObjectReconciler) getScaleTargetPodSpec(ctx context.Context, logger logr.Logger, scaledObject *kedav1alpha1.ScaledObject) *corev1.PodSpec {
+	if scaledObject.Status.ScaleTargetGVKR == nil || scaledObject.Spec.ScaleTargetRef == nil {
+		return nil
+	}
+	unstruct := &unstructured.Unstructured{}
+	unstruct.SetGroupVersionKind(scaledObject.Status.ScaleTargetGVKR.GroupVersionKind())
+	if err := r.Client.Get(ctx, client.ObjectKey{Namespace: scaledObject.Namespace, Name: scaledObject.Spec.ScaleTargetRef.Name}, unstruct); err != nil {
+		logger.V(1).Info("Failed to get the ScaleTarget", "ScaledObject.Namespace", scaledObject.Namespace, "ScaledObject.Name", scaledObject.Name, "error", err.Error())
+		return nil
+	}
+	withPods := &duckv1.WithPod{}
+	if err := duck.FromUnstructured(unstruct, withPods); err != nil {
+		return nil
+	}
+	return &withPods.Spec.Template.Spec
+}
@@ -0,0 +1,93 @@
+package controllers
+
+import (
+	"sync"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+)
+
+func TestTriggersReferenceAuth(t *testing.T) {
+	triggers := []kedav1alpha1.ScaleTriggers{
+		{Type: "prometheus"},
+		{Type: "azure-queue", AuthenticationRef: &kedav1alpha1.ScaledObjectAuthRef{Name: "queue-auth"}},
+	}
+	triggerAuths := map[string]*kedav1alpha1.TriggerAuthentication{
+		"queue-auth": {Spec: kedav1alpha1.TriggerAuthenticationSpec{
+			SecretTargetRef: []kedav1alpha1.AuthSecretTargetRef{{Parameter: "connection", Name: "queue-secret", Key: "connection"}},
+		}},
+	}
+	podSpec := &corev1.PodSpec{Containers: []corev1.Container{{
+		EnvFrom: []corev1.EnvFromSource{{ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "app-config"}}}},
+		Env:     []corev1.EnvVar{{Name: "PASSWORD", ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "app-secret"}, Key: "password"}}}},
+	}}}
+
+	tests := []struct {
+		ref      authRef
+		expected bool
+	}{
+		{authRef{kind: authRefKindTriggerAuthentication, name: "queue-auth"}, true},
+		{authRef{kind: authRefKindTriggerAuthentication, name: "other-auth"}, false},
+		{authRef{kind: authRefKindSecret, name: "queue-secret"}, true},
+		{authRef{kind: authRefKindSecret, name: "app-secret"}, true},
+		{authRef{kind: authRefKindSecret, name: "other-secret"}, false},
+		{authRef{kind: authRefKindConfigMap, name: "app-config"}, true},
+		{authRef{kind: authRefKindConfigMap, name: "app-secret"}, false},
+	}
+	for _, test := range tests {
+		if actual := triggersReferenceAuth(test.ref, triggers, triggerAuths, podSpec); actual != test.expected {
+			t.Errorf("%s %s: expected %v, got %v", test.ref.kind, test.ref.name, test.expected, actual)
+		}
+	}
+}
+
+func TestAuthRefMapper(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = kedav1alpha1.AddToScheme(scheme)
+
+	newScaledObject := func(name, authName string) *kedav1alpha1.ScaledObject {
+		return &kedav1alpha1.ScaledObject{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name, Generation: 1},
+			Spec: kedav1alpha1.ScaledObjectSpec{Triggers: []kedav1alpha1.ScaleTriggers{
+				{Type: "external-push", AuthenticationRef: &kedav1alpha1.ScaledObjectAuthRef{Name: authName}},
+			}},
+		}
+	}
+	triggerAuth := &kedav1alpha1.TriggerAuthentication{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "push-auth"},
+		Spec: kedav1alpha1.TriggerAuthenticationSpec{
+			SecretTargetRef: []kedav1alpha1.AuthSecretTargetRef{{Parameter: "token", Name: "push-secret", Key: "token"}},
+		},
+	}
+	r := &ScaledObjectReconciler{
+		Log:                      logf.Log,
+		Client:                   fake.NewFakeClientWithScheme(scheme, newScaledObject("push", "push-auth"), newScaledObject("other", "other-auth"), triggerAuth),
+		scaledObjectsGenerations: &sync.Map{},
+	}
+	r.scaledObjectsGenerations.Store("default/push", int64(1))
+	r.scaledObjectsGenerations.Store("default/other", int64(1))
+
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "push-secret"}}
+	requests := r.authRefMapper(authRefKindSecret).Map(handler.MapObject{Meta: secret, Object: secret})
+	if len(requests) != 1 || requests[0].Name != "push" {
+		t.Errorf("Expected a request for the ScaledObject reading the secret, got %v", requests)
+	}
+	if _, ok := r.scaledObjectsGenerations.Load("default/push"); ok {
+		t.Error("Expected the generation of the ScaledObject reading the secret to be forgotten")
+	}
+	if _, ok := r.scaledObjectsGenerations.Load("default/other"); !ok {
+		t.Error("Expected the generation of the other ScaledObject to be kept")
+	}
+
+	requests = r.authRefMapper(authRefKindTriggerAuthentication).Map(handler.MapObject{Meta: triggerAuth, Object: triggerAuth})
+	if len(requests) != 1 || requests[0].Name != "push" {
+		t.Errorf("Expected a request for the ScaledObject referencing the TriggerAuthentication, got %v", requests)
+	}
+}
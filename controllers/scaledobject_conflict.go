@@ -0,0 +1,139 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+	kedautil "github.com/kedacore/keda/pkg/util"
+)
+
+const (
+	defaultScaleTargetGroup = "apps"
+	defaultScaleTargetKind  = "Deployment"
+)
+
+// scaleTargetConflictError is returned when the ScaleTarget of a ScaledObject is already scaled by another ScaledObject or HPA
+type scaleTargetConflictError struct {
+	message string
+}
+
+func (e *scaleTargetConflictError) Error() string {
+	return e.message
+}
+
+// checkScaleTargetConflicts returns a scaleTargetConflictError if the ScaleTarget of the ScaledObject is already scaled
+// by an older ScaledObject or by an HPA not managed by KEDA, so no second HPA is created for the same ScaleTarget
+func (r *ScaledObjectReconciler) checkScaleTargetConflicts(logger logr.Logger, scaledObject *kedav1alpha1.ScaledObject) error {
+	err := findScaleTargetConflict(context.TODO(), r.Client, scaledObject)
+	if _, ok := err.(*scaleTargetConflictError); err != nil && !ok {
+		logger.Error(err, "Failed to check the conflicts of the scaleTargetRef")
+	}
+	return err
+}
+
+// findScaleTargetConflict returns a scaleTargetConflictError if the ScaleTarget of the ScaledObject is already scaled
+// by an older ScaledObject or by an HPA not managed by KEDA, or the error listing them
+func findScaleTargetConflict(ctx context.Context, kubeClient client.Client, scaledObject *kedav1alpha1.ScaledObject) error {
+	scaledObjects := &kedav1alpha1.ScaledObjectList{}
+	if err := kubeClient.List(ctx, scaledObjects, client.InNamespace(scaledObject.Namespace)); err != nil {
+		return fmt.Errorf("error listing ScaledObjects: %s", err)
+	}
+	for i := range scaledObjects.Items {
+		other := &scaledObjects.Items[i]
+		// dry-run ScaledObjects don't create an HPA so they can't conflict
+		if other.Name == scaledObject.Name || other.IsDryRun() || other.GetDeletionTimestamp() != nil {
+			continue
+		}
+		if !sameScaleTarget(scaledObject.Spec.ScaleTargetRef.APIVersion, scaledObject.Spec.ScaleTargetRef.Kind, scaledObject.Spec.ScaleTargetRef.Name,
+			other.Spec.ScaleTargetRef.APIVersion, other.Spec.ScaleTargetRef.Kind, other.Spec.ScaleTargetRef.Name) {
+			continue
+		}
+		// the ScaledObject created first keeps the ScaleTarget
+		if createdBefore(other.ObjectMeta, scaledObject.ObjectMeta) {
+			return &scaleTargetConflictError{
+				message: fmt.Sprintf("the scaleTargetRef %s is already scaled by the ScaledObject %s", scaledObject.Spec.ScaleTargetRef.Name, other.Name),
+			}
+		}
+	}
+
+	hpas := &autoscalingv2beta2.HorizontalPodAutoscalerList{}
+	if err := kubeClient.List(ctx, hpas, client.InNamespace(scaledObject.Namespace)); err != nil {
+		return fmt.Errorf("error listing HPAs: %s", err)
+	}
+	for i := range hpas.Items {
+		hpa := &hpas.Items[i]
+		// the HPAs of the other ScaledObjects were checked above
+		if hpa.Name == kedautil.GetHPAName(scaledObject) || isOwnedByScaledObject(hpa.ObjectMeta) {
+			continue
+		}
+		if sameScaleTarget(scaledObject.Spec.ScaleTargetRef.APIVersion, scaledObject.Spec.ScaleTargetRef.Kind, scaledObject.Spec.ScaleTargetRef.Name,
+			hpa.Spec.ScaleTargetRef.APIVersion, hpa.Spec.ScaleTargetRef.Kind, hpa.Spec.ScaleTargetRef.Name) {
+			return &scaleTargetConflictError{
+				message: fmt.Sprintf("the scaleTargetRef %s is already scaled by the HPA %s", scaledObject.Spec.ScaleTargetRef.Name, hpa.Name),
+			}
+		}
+	}
+
+	return nil
+}
+
+// findScaleTargetHPA returns the name of an HPA scaling the ScaleTarget of the ScaledObject, the HPA the ScaledObject created
+// before it was switched to dry-run mode included, an empty name if there is none
+func findScaleTargetHPA(ctx context.Context, kubeClient client.Client, scaledObject *kedav1alpha1.ScaledObject) (string, error) {
+	hpas := &autoscalingv2beta2.HorizontalPodAutoscalerList{}
+	if err := kubeClient.List(ctx, hpas, client.InNamespace(scaledObject.Namespace)); err != nil {
+		return "", fmt.Errorf("error listing HPAs: %s", err)
+	}
+	for i := range hpas.Items {
+		hpa := &hpas.Items[i]
+		if sameScaleTarget(scaledObject.Spec.ScaleTargetRef.APIVersion, scaledObject.Spec.ScaleTargetRef.Kind, scaledObject.Spec.ScaleTargetRef.Name,
+			hpa.Spec.ScaleTargetRef.APIVersion, hpa.Spec.ScaleTargetRef.Kind, hpa.Spec.ScaleTargetRef.Name) {
+			return hpa.Name, nil
+		}
+	}
+	return "", nil
+}
+
+// sameScaleTarget returns true if both references point to the same resource, the version of the group is ignored
+func sameScaleTarget(apiVersion, kind, name, otherAPIVersion, otherKind, otherName string) bool {
+	return name == otherName && scaleTargetKind(apiVersion, kind) == scaleTargetKind(otherAPIVersion, otherKind)
+}
+
+// scaleTargetKind returns the group and kind of a scaleTargetRef, the empty values default to apps and Deployment
+func scaleTargetKind(apiVersion, kind string) schema.GroupKind {
+	groupKind := schema.GroupKind{Group: defaultScaleTargetGroup, Kind: kind}
+	if apiVersion != "" {
+		if groupVersion, err := schema.ParseGroupVersion(apiVersion); err == nil {
+			groupKind.Group = groupVersion.Group
+		}
+	}
+	if groupKind.Kind == "" {
+		groupKind.Kind = defaultScaleTargetKind
+	}
+	return groupKind
+}
+
+// createdBefore orders the objects by creation time, the objects created at the same time are ordered by name
+func createdBefore(object, other metav1.ObjectMeta) bool {
+	if !object.CreationTimestamp.Equal(&other.CreationTimestamp) {
+		return object.CreationTimestamp.Before(&other.CreationTimestamp)
+	}
+	return object.Name < other.Name
+}
+
+// isOwnedByScaledObject returns true if the object is controlled by a ScaledObject
+func isOwnedByScaledObject(object metav1.ObjectMeta) bool {
+	owner := metav1.GetControllerOf(&object)
+	if owner == nil || owner.Kind != "ScaledObject" {
+		return false
+	}
+	groupVersion, err := schema.ParseGroupVersion(owner.APIVersion)
+	return err == nil && groupVersion.Group == kedav1alpha1.GroupVersion.Group
+}
@@ -0,0 +1,122 @@
+package controllers
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+	"github.com/kedacore/keda/pkg/eventemitter"
+	kedautil "github.com/kedacore/keda/pkg/util"
+)
+
+func TestCheckScaleTargetConflicts(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = kedav1alpha1.AddToScheme(scheme)
+
+	older := metav1.NewTime(time.Now().Add(-time.Hour))
+	newer := metav1.NewTime(time.Now())
+	isController := true
+
+	newScaledObject := func(name string, created metav1.Time, target kedav1alpha1.ScaleTarget) *kedav1alpha1.ScaledObject {
+		return &kedav1alpha1.ScaledObject{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name, CreationTimestamp: created},
+			Spec:       kedav1alpha1.ScaledObjectSpec{ScaleTargetRef: &target},
+		}
+	}
+	newHPA := func(name string, target autoscalingv2beta2.CrossVersionObjectReference, owners ...metav1.OwnerReference) *autoscalingv2beta2.HorizontalPodAutoscaler {
+		return &autoscalingv2beta2.HorizontalPodAutoscaler{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name, OwnerReferences: owners},
+			Spec:       autoscalingv2beta2.HorizontalPodAutoscalerSpec{ScaleTargetRef: target},
+		}
+	}
+	dryRun := newScaledObject("dry-run", older, kedav1alpha1.ScaleTarget{Name: "app"})
+	dryRun.Spec.Advanced = &kedav1alpha1.AdvancedConfig{DryRun: true}
+
+	scaledObject := newScaledObject("app", newer, kedav1alpha1.ScaleTarget{Name: "app"})
+
+	tests := []struct {
+		name       string
+		objects    []runtime.Object
+		isConflict bool
+	}{
+		{"no other object", nil, false},
+		{"older ScaledObject with the same target", []runtime.Object{newScaledObject("other", older, kedav1alpha1.ScaleTarget{Name: "app", APIVersion: "apps/v1", Kind: "Deployment"})}, true},
+		{"newer ScaledObject with the same target", []runtime.Object{newScaledObject("other", metav1.NewTime(newer.Add(time.Minute)), kedav1alpha1.ScaleTarget{Name: "app"})}, false},
+		{"older ScaledObject with another target", []runtime.Object{newScaledObject("other", older, kedav1alpha1.ScaleTarget{Name: "worker"})}, false},
+		{"older ScaledObject with another kind", []runtime.Object{newScaledObject("other", older, kedav1alpha1.ScaleTarget{Name: "app", APIVersion: "apps/v1", Kind: "StatefulSet"})}, false},
+		{"older dry-run ScaledObject", []runtime.Object{dryRun}, false},
+		{"HPA not managed by KEDA", []runtime.Object{newHPA("app", autoscalingv2beta2.CrossVersionObjectReference{Name: "app", APIVersion: "apps/v1", Kind: "Deployment"})}, true},
+		{"HPA of the ScaledObject", []runtime.Object{newHPA(kedautil.GetHPAName(scaledObject), autoscalingv2beta2.CrossVersionObjectReference{Name: "app", APIVersion: "apps/v1", Kind: "Deployment"})}, false},
+		{"HPA of another ScaledObject", []runtime.Object{newHPA("keda-hpa-other", autoscalingv2beta2.CrossVersionObjectReference{Name: "app", APIVersion: "apps/v1", Kind: "Deployment"},
+			metav1.OwnerReference{APIVersion: "keda.sh/v1alpha1", Kind: "ScaledObject", Name: "other", Controller: &isController})}, false},
+		{"HPA with another target", []runtime.Object{newHPA("worker", autoscalingv2beta2.CrossVersionObjectReference{Name: "worker", APIVersion: "apps/v1", Kind: "Deployment"})}, false},
+	}
+
+	for _, test := range tests {
+		reconciler := &ScaledObjectReconciler{Client: fake.NewFakeClientWithScheme(scheme, append(test.objects, scaledObject.DeepCopy())...)}
+		err := reconciler.checkScaleTargetConflicts(logf.Log, scaledObject)
+		_, isConflict := err.(*scaleTargetConflictError)
+		if err != nil && !isConflict {
+			t.Errorf("%s: unexpected error %s", test.name, err)
+		}
+		if isConflict != test.isConflict {
+			t.Errorf("%s: expected conflict %t, got %v", test.name, test.isConflict, err)
+		}
+	}
+}
+
+func TestReconcileDryRunScaledObjectReportsTheHPA(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = kedav1alpha1.AddToScheme(scheme)
+
+	scaledObject := &kedav1alpha1.ScaledObject{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "app", Generation: 1},
+		Spec: kedav1alpha1.ScaledObjectSpec{
+			ScaleTargetRef: &kedav1alpha1.ScaleTarget{Name: "app"},
+			Advanced:       &kedav1alpha1.AdvancedConfig{DryRun: true},
+		},
+		Status: kedav1alpha1.ScaledObjectStatus{Conditions: *kedav1alpha1.GetInitializedConditions()},
+	}
+	hpa := &autoscalingv2beta2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "app"},
+		Spec:       autoscalingv2beta2.HorizontalPodAutoscalerSpec{ScaleTargetRef: autoscalingv2beta2.CrossVersionObjectReference{Name: "app", APIVersion: "apps/v1", Kind: "Deployment"}},
+	}
+
+	emitter := &recordingEventEmitter{}
+	reconciler := &ScaledObjectReconciler{Client: fake.NewFakeClientWithScheme(scheme, scaledObject.DeepCopy()), eventEmitter: emitter, scaledObjectsGenerations: &sync.Map{}}
+	// the scale loop evaluating the triggers is already running
+	reconciler.scaledObjectsGenerations.Store("default/app", scaledObject.Generation)
+	msg, err := reconciler.reconcileDryRunScaledObject(logf.Log, scaledObject)
+	if err != nil || strings.Contains(msg, "HPA") || len(emitter.eventTypes) != 0 {
+		t.Fatalf("Expected no HPA reported without HPA, got %q, %v, %v", msg, err, emitter.eventTypes)
+	}
+
+	reconciler.Client = fake.NewFakeClientWithScheme(scheme, scaledObject.DeepCopy(), hpa)
+	msg, err = reconciler.reconcileDryRunScaledObject(logf.Log, scaledObject)
+	if err != nil || !strings.Contains(msg, "the HPA app") {
+		t.Fatalf("Expected the HPA to be reported, got %q, %v", msg, err)
+	}
+	if len(emitter.eventTypes) != 1 || emitter.eventTypes[0] != eventemitter.ScaledObjectDryRunHPAType {
+		t.Errorf("Expected a dry-run HPA event, got %v", emitter.eventTypes)
+	}
+
+	// the event is only emitted when the HPA is first reported
+	scaledObject.Status.Conditions.SetReadyCondition(metav1.ConditionTrue, "ScaledObjectReady", msg)
+	if _, err := reconciler.reconcileDryRunScaledObject(logf.Log, scaledObject); err != nil {
+		t.Fatal(err)
+	}
+	if len(emitter.eventTypes) != 1 {
+		t.Errorf("Expected no other event, got %v", emitter.eventTypes)
+	}
+}
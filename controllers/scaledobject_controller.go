@@ -3,10 +3,13 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"os"
 	"sync"
+	"time"
 
 	"github.com/go-logr/logr"
 	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -14,18 +17,22 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/discovery"
-	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/scale"
 	"k8s.io/client-go/tools/cache"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
 	kedacontrollerutil "github.com/kedacore/keda/controllers/util"
+	"github.com/kedacore/keda/pkg/eventemitter"
+	"github.com/kedacore/keda/pkg/globalconfig"
+	prommetrics "github.com/kedacore/keda/pkg/metrics"
 	"github.com/kedacore/keda/pkg/scaling"
+	"github.com/kedacore/keda/pkg/tracing"
 	kedautil "github.com/kedacore/keda/pkg/util"
 )
 
@@ -34,6 +41,13 @@ import (
 // +kubebuilder:rbac:groups=autoscaling,resources=horizontalpodautoscalers,verbs="*"
 // +kubebuilder:rbac:groups="",resources=configmaps;configmaps/status;events,verbs="*"
 // +kubebuilder:rbac:groups="",resources=pods;services;services;secrets;external,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=pods/exec,verbs=create
+// +kubebuilder:rbac:groups=coordination.k8s.io,resources=leases,verbs=get;list;create;update;delete
+// +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=nodes,verbs=get;list;watch
+// +kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;watch
+// +kubebuilder:rbac:groups=keda.sh,resources=cloudeventsources,verbs=get;list;watch
+// +kubebuilder:rbac:groups=autoscaling.k8s.io,resources=verticalpodautoscalers,verbs=get;list;watch
 // +kubebuilder:rbac:groups="*",resources="*/scale",verbs="*"
 // +kubebuilder:rbac:groups="*",resources="*",verbs=get
 
@@ -42,11 +56,15 @@ type ScaledObjectReconciler struct {
 	Log    logr.Logger
 	Client client.Client
 	Scheme *runtime.Scheme
+	// OrphanedHPASweep configures the sweep of the HPAs whose ScaledObject is gone, the HPAs aren't swept if its interval isn't set
+	OrphanedHPASweep OrphanedHPASweepConfig
 
 	scaleClient              *scale.ScalesGetter
 	restMapper               meta.RESTMapper
 	scaledObjectsGenerations *sync.Map
+	appliedHPASpecs          *sync.Map
 	scaleHandler             scaling.ScaleHandler
+	eventEmitter             eventemitter.EventEmitter
 	kubeVersion              kedautil.K8sVersion
 }
 
@@ -68,41 +86,64 @@ func (r *ScaledObjectReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		r.Log.Error(err, "Not able to get Kubernetes version")
 	}
 
+	// autoscaling/v2beta2 isn't served from Kubernetes 1.26, the HPAs are managed with autoscaling/v2 if the cluster serves it
+	hpaVersion, err := kedautil.DetectHPAVersion(clientset)
+	if err != nil {
+		r.Log.Error(err, "Not able to detect the versions of the HPA API, using "+hpaVersion.String())
+	}
+	r.Log.Info("Managing the HPAs with " + hpaVersion.String())
+	r.Client = kedautil.NewHPAVersionClient(r.Client, hpaVersion)
+
 	// Create Scale Client
-	scaleClient := initScaleClient(mgr, clientset)
+	scaleClient := kedautil.NewScaleClient(clientset, mgr.GetRESTMapper())
 	r.scaleClient = &scaleClient
 
 	// Init the rest of ScaledObjectReconciler
 	r.restMapper = mgr.GetRESTMapper()
 	r.scaledObjectsGenerations = &sync.Map{}
-	r.scaleHandler = scaling.NewScaleHandler(mgr.GetClient(), r.scaleClient, mgr.GetScheme())
+	r.appliedHPASpecs = &sync.Map{}
+	r.scaleHandler = scaling.NewScaleHandler(r.Client, r.scaleClient, mgr.GetScheme())
+	r.eventEmitter = eventemitter.NewEventEmitter(mgr.GetClient())
+
+	if r.OrphanedHPASweep.Interval > 0 {
+		if err := mgr.Add(&orphanedHPASweeper{
+			client:       r.Client,
+			logger:       r.Log.WithName("OrphanedHPASweep"),
+			eventEmitter: r.eventEmitter,
+			namespaces:   kedautil.ParseWatchNamespaces(os.Getenv(kedautil.WatchNamespaceEnvVar)),
+			config:       r.OrphanedHPASweep,
+		}); err != nil {
+			return err
+		}
+	}
 
 	// Start controller
 	return ctrl.NewControllerManagedBy(mgr).
 		// predicate.GenerationChangedPredicate{} ignore updates to ScaledObject Status
 		// (in this case metadata.Generation does not change)
-		// so reconcile loop is not started on Status updates
-		For(&kedav1alpha1.ScaledObject{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
-		Owns(&autoscalingv2beta2.HorizontalPodAutoscaler{}).
+		// so reconcile loop is not started on Status updates, the changes of the labels are reconciled for the watchLabelSelector
+		For(&kedav1alpha1.ScaledObject{}, builder.WithPredicates(predicate.Or(predicate.GenerationChangedPredicate{}, kedacontrollerutil.LabelsChangedPredicate))).
+		Owns(kedautil.NewHPAWatchObject(hpaVersion)).
+		// the scalers are reloaded when the authentication their triggers read changes
+		Watches(&source.Kind{Type: &kedav1alpha1.TriggerAuthentication{}}, &handler.EnqueueRequestsFromMapFunc{ToRequests: r.authRefMapper(authRefKindTriggerAuthentication)}, builder.WithPredicates(predicate.ResourceVersionChangedPredicate{})).
+		Watches(&source.Kind{Type: &corev1.Secret{}}, &handler.EnqueueRequestsFromMapFunc{ToRequests: r.authRefMapper(authRefKindSecret)}, builder.WithPredicates(predicate.ResourceVersionChangedPredicate{})).
+		Watches(&source.Kind{Type: &corev1.ConfigMap{}}, &handler.EnqueueRequestsFromMapFunc{ToRequests: r.authRefMapper(authRefKindConfigMap)}, builder.WithPredicates(predicate.ResourceVersionChangedPredicate{})).
 		Complete(r)
 }
 
-func initScaleClient(mgr manager.Manager, clientset *discovery.DiscoveryClient) scale.ScalesGetter {
-	scaleKindResolver := scale.NewDiscoveryScaleKindResolver(clientset)
-	return scale.New(
-		clientset.RESTClient(), mgr.GetRESTMapper(),
-		dynamic.LegacyAPIPathResolverFunc,
-		scaleKindResolver,
-	)
-}
-
 // Reconcile performs reconciliation on the identified ScaledObject resource based on the request information passed, returns the result and an error (if any).
-func (r *ScaledObjectReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
+func (r *ScaledObjectReconciler) Reconcile(req ctrl.Request) (_ ctrl.Result, err error) {
 	reqLogger := r.Log.WithValues("ScaledObject.Namespace", req.Namespace, "ScaledObject.Name", req.Name)
+	ctx, span := tracing.StartSpan(context.TODO(), "ScaledObjectReconciler.Reconcile", tracing.ObjectAttributes("ScaledObject", req.Namespace, req.Name)...)
+	start := time.Now()
+	defer func() {
+		tracing.EndSpan(ctx, span, err)
+		prommetrics.RecordReconcile("ScaledObject", req.Namespace, req.Name, start, err)
+	}()
 
 	// Fetch the ScaledObject instance
 	scaledObject := &kedav1alpha1.ScaledObject{}
-	err := r.Client.Get(context.TODO(), req.NamespacedName, scaledObject)
+	err = r.Client.Get(ctx, req.NamespacedName, scaledObject)
 	if err != nil {
 		if errors.IsNotFound(err) {
 			// Request object not found, could have been deleted after reconcile request.
@@ -115,6 +156,13 @@ func (r *ScaledObjectReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error
 		return ctrl.Result{}, err
 	}
 
+	// the ScaledObjects not watched belong to another installation, which finalizes them,
+	// the scale loop is stopped if the ScaledObject stopped matching
+	if !globalconfig.Get().IsWatched(scaledObject.Namespace, scaledObject.Labels) {
+		reqLogger.V(1).Info("Ignoring ScaledObject, its namespace or labels aren't watched")
+		return ctrl.Result{}, r.stopScaleLoop(reqLogger, scaledObject)
+	}
+
 	reqLogger.Info("Reconciling ScaledObject")
 
 	// Check if the ScaledObject instance is marked to be deleted, which is
@@ -139,13 +187,41 @@ func (r *ScaledObjectReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error
 	conditions := scaledObject.Status.Conditions.DeepCopy()
 	if err != nil {
 		reqLogger.Error(err, msg)
-		conditions.SetReadyCondition(metav1.ConditionFalse, "ScaledObjectCheckFailed", msg)
+		switch err.(type) {
+		case *scaleTargetConflictError:
+			if readyCondition := conditions.GetReadyCondition(); readyCondition.Reason != "ScaleTargetConflict" {
+				r.eventEmitter.Emit("ScaledObject", scaledObject.Namespace, scaledObject.Name, eventemitter.ScaledObjectConflictType, err.Error())
+			}
+			conditions.SetReadyCondition(metav1.ConditionFalse, "ScaleTargetConflict", err.Error())
+		case *policyViolationError:
+			if readyCondition := conditions.GetReadyCondition(); readyCondition.Reason != "ScalingPolicyViolation" {
+				r.eventEmitter.Emit("ScaledObject", scaledObject.Namespace, scaledObject.Name, eventemitter.ScaledObjectPolicyViolationType, err.Error())
+			}
+			conditions.SetReadyCondition(metav1.ConditionFalse, "ScalingPolicyViolation", err.Error())
+			conditions.SetPausedCondition(metav1.ConditionTrue, "ScalingPolicyViolation", "Scaling is not performed because the ScaledObject violates the scaling policy")
+		default:
+			conditions.SetReadyCondition(metav1.ConditionFalse, "ScaledObjectCheckFailed", msg)
+		}
 		conditions.SetActiveCondition(metav1.ConditionUnknown, "UnkownState", "ScaledObject check failed")
 	} else {
 		reqLogger.V(1).Info(msg)
+		if readyCondition := conditions.GetReadyCondition(); !readyCondition.IsTrue() {
+			r.eventEmitter.Emit("ScaledObject", scaledObject.Namespace, scaledObject.Name, eventemitter.ScaledObjectReadyType, msg)
+		}
 		conditions.SetReadyCondition(metav1.ConditionTrue, "ScaledObjectReady", msg)
+		if scaledObject.IsRecommending() {
+			conditions.SetPausedCondition(metav1.ConditionTrue, "Recommend", "Scaling is not performed, the recommended replica count is written for another autoscaler")
+		} else if scaledObject.IsDryRun() {
+			conditions.SetPausedCondition(metav1.ConditionTrue, "DryRun", "Scaling is not performed in dry-run mode")
+		} else {
+			conditions.SetPausedCondition(metav1.ConditionFalse, "ScalingEnabled", "Scaling is performed")
+		}
 	}
 	kedacontrollerutil.SetStatusConditions(r.Client, reqLogger, scaledObject, &conditions)
+	// lowering the MinReplicas of the HPA is retried until the PodDisruptionBudgets allow it
+	if isHPAMinReplicasLoweringDeferred(conditions) {
+		return ctrl.Result{RequeueAfter: pdbRecheckInterval}, err
+	}
 	return ctrl.Result{}, err
 }
 
@@ -157,18 +233,64 @@ func (r *ScaledObjectReconciler) reconcileScaledObject(logger logr.Logger, scale
 		return "ScaledObject doesn't have correct scaleTargetRef specification", err
 	}
 
+	// Check the windows of the scaleToZeroSchedule are valid, the ScaleTarget is never scaled to zero otherwise
+	if scaledObject.Spec.ScaleToZeroSchedule != nil {
+		if _, err := kedautil.ParseScaleToZeroSchedule(scaledObject.Spec.ScaleToZeroSchedule); err != nil {
+			return "ScaledObject has an invalid scaleToZeroSchedule", fmt.Errorf("error parsing scaleToZeroSchedule: %s", err)
+		}
+	}
+
+	// Check the scaleWebhooks are valid, they would fail on each transition of the ScaleTarget otherwise
+	if err := kedautil.ValidateScaleWebhooks(scaledObject.Spec.ScaleWebhooks); err != nil {
+		return "ScaledObject has invalid scaleWebhooks", fmt.Errorf("error parsing scaleWebhooks: %s", err)
+	}
+
+	// Check the thresholdTypes are supported by the metrics of the triggers, the HPA would reject them otherwise
+	if err := validateThresholdTypes(scaledObject.Spec.Triggers); err != nil {
+		return "ScaledObject has an invalid thresholdType", err
+	}
+
 	// Check the label needed for Metrics servers is present on ScaledObject
 	err := r.ensureScaledObjectLabel(logger, scaledObject)
 	if err != nil {
 		return "Failed to update ScaledObject with scaledObjectName label", err
 	}
 
+	// Check the ScaledObject is within the limits of the global configuration, the triggers of a ScaledObject
+	// exceeding them aren't evaluated anymore
+	if err := r.checkScalingPolicy(logger, scaledObject); err != nil {
+		if _, ok := err.(*policyViolationError); ok {
+			if stopErr := r.stopScaleLoop(logger, scaledObject); stopErr != nil {
+				logger.Error(stopErr, "Failed to stop the scale loop of the ScaledObject violating the scaling policy")
+			}
+		}
+		return "ScaledObject violates the scaling policy of the cluster", err
+	}
+
 	// Check if resource targeted for scaling exists and exposes /scale subresource
 	gvkr, err := r.checkTargetResourceIsScalable(logger, scaledObject)
 	if err != nil {
 		return "ScaledObject doesn't have correct scaleTargetRef specification", err
 	}
 
+	// Show the replica counts and the triggers in kubectl get
+	if err := r.updatePrinterColumnsStatus(logger, scaledObject); err != nil {
+		return "Failed to update the status of the ScaledObject", err
+	}
+
+	// In dry-run and recommendation modes the triggers are only evaluated by the ScaleLoop, neither the HPA nor the ScaleTarget are mutated
+	if scaledObject.IsDryRun() {
+		return r.reconcileDryRunScaledObject(logger, scaledObject)
+	}
+
+	// Check the ScaleTarget isn't already scaled by another ScaledObject or HPA
+	if err := r.checkScaleTargetConflicts(logger, scaledObject); err != nil {
+		return "ScaledObject's scaleTargetRef conflicts with another ScaledObject or HPA", err
+	}
+
+	// Check whether a VerticalPodAutoscaler updates the pods of the ScaleTarget, before the HPA is restricted to activation only
+	r.checkVPAConflict(logger, scaledObject)
+
 	// Create a new HPA or update existing one according to ScaledObject
 	newHPACreated, err := r.ensureHPAForScaledObjectExists(logger, scaledObject, &gvkr)
 	if err != nil {
@@ -195,19 +317,47 @@ func (r *ScaledObjectReconciler) reconcileScaledObject(logger logr.Logger, scale
 	return "ScaledObject is defined correctly and is ready for scaling", nil
 }
 
+// reconcileDryRunScaledObject starts a new ScaleLoop evaluating the triggers if the ScaledObject was updated,
+// the HPA still scaling the ScaleTarget is reported in the Ready condition and with an event
+func (r *ScaledObjectReconciler) reconcileDryRunScaledObject(logger logr.Logger, scaledObject *kedav1alpha1.ScaledObject) (string, error) {
+	scaleObjectSpecChanged, err := r.scaledObjectGenerationChanged(logger, scaledObject)
+	if err != nil {
+		return "Failed to check whether ScaledObject's Generation was changed", err
+	}
+
+	if scaleObjectSpecChanged {
+		if err = r.requestScaleLoop(logger, scaledObject); err != nil {
+			return "Failed to start a new scale loop with scaling logic", err
+		}
+		logger.Info("Initializing dry-run evaluation of triggers according to ScaledObject Specification")
+	}
+
+	hpaName, err := findScaleTargetHPA(context.TODO(), r.Client, scaledObject)
+	if err != nil {
+		return "Failed to check the HPAs of the scaleTargetRef", err
+	}
+	if hpaName == "" {
+		return "ScaledObject is defined correctly and its triggers are evaluated in dry-run mode", nil
+	}
+	msg := fmt.Sprintf("ScaledObject is defined correctly and its triggers are evaluated in dry-run mode, the scaleTargetRef is still scaled by the HPA %s", hpaName)
+	if readyCondition := scaledObject.Status.Conditions.GetReadyCondition(); readyCondition.Message != msg {
+		r.eventEmitter.Emit("ScaledObject", scaledObject.Namespace, scaledObject.Name, eventemitter.ScaledObjectDryRunHPAType,
+			fmt.Sprintf("The ScaledObject is in dry-run mode, its scaleTargetRef %s is still scaled by the HPA %s", scaledObject.Spec.ScaleTargetRef.Name, hpaName))
+	}
+	return msg, nil
+}
+
 // ensureScaledObjectLabel ensures that scaledObjectName=<scaledObject.Name> label exist in the ScaledObject
 // This is how the MetricsAdapter will know which ScaledObject a metric is for when the HPA queries it.
 func (r *ScaledObjectReconciler) ensureScaledObjectLabel(logger logr.Logger, scaledObject *kedav1alpha1.ScaledObject) error {
-	const labelScaledObjectName = "scaledObjectName"
-
 	if scaledObject.Labels == nil {
-		scaledObject.Labels = map[string]string{labelScaledObjectName: scaledObject.Name}
+		scaledObject.Labels = map[string]string{kedav1alpha1.ScaledObjectNameLabel: scaledObject.Name}
 	} else {
-		value, found := scaledObject.Labels[labelScaledObjectName]
+		value, found := scaledObject.Labels[kedav1alpha1.ScaledObjectNameLabel]
 		if found && value == scaledObject.Name {
 			return nil
 		}
-		scaledObject.Labels[labelScaledObjectName] = scaledObject.Name
+		scaledObject.Labels[kedav1alpha1.ScaledObjectNameLabel] = scaledObject.Name
 	}
 
 	logger.V(1).Info("Adding scaledObjectName label on ScaledObject", "value", scaledObject.Name)
@@ -264,7 +414,7 @@ func (r *ScaledObjectReconciler) checkTargetResourceIsScalable(logger logr.Logge
 
 // ensureHPAForScaledObjectExists ensures that in cluster exist up-to-date HPA for specified ScaledObject, returns true if a new HPA was created
 func (r *ScaledObjectReconciler) ensureHPAForScaledObjectExists(logger logr.Logger, scaledObject *kedav1alpha1.ScaledObject, gvkr *kedav1alpha1.GroupVersionKindResource) (bool, error) {
-	hpaName := getHPAName(scaledObject)
+	hpaName := kedautil.GetHPAName(scaledObject)
 	foundHpa := &autoscalingv2beta2.HorizontalPodAutoscaler{}
 	// Check if HPA for this ScaledObject already exists
 	err := r.Client.Get(context.TODO(), types.NamespacedName{Name: hpaName, Namespace: scaledObject.Namespace}, foundHpa)
@@ -328,6 +478,7 @@ func (r *ScaledObjectReconciler) stopScaleLoop(logger logr.Logger, scaledObject
 	}
 	// delete ScaledObject's current Generation
 	r.scaledObjectsGenerations.Delete(key)
+	r.appliedHPASpecs.Delete(key)
 	return nil
 }
 
@@ -4,11 +4,14 @@ import (
 	"context"
 
 	"github.com/go-logr/logr"
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 
 	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
 	"github.com/kedacore/keda/controllers/util"
+	kedautil "github.com/kedacore/keda/pkg/util"
 )
 
 const (
@@ -25,8 +28,16 @@ func (r *ScaledObjectReconciler) finalizeScaledObject(logger logr.Logger, scaled
 			return err
 		}
 
+		// if enabled, the HPA is left in place and the scaleTarget keeps its current replica count
+		if scaledObject.IsOrphaningHPA() {
+			if err := r.orphanHPA(logger, scaledObject); err != nil {
+				return err
+			}
+		}
+
 		// if enabled, scale scaleTarget back to the original replica count (to the state it was before scaling with KEDA)
-		if scaledObject.Spec.Advanced != nil && scaledObject.Spec.Advanced.RestoreToOriginalReplicaCount {
+		// the scaleTarget isn't scaled in dry-run mode, so there is nothing to restore
+		if scaledObject.Spec.Advanced != nil && scaledObject.Spec.Advanced.RestoreToOriginalReplicaCount && !scaledObject.IsDryRun() && !scaledObject.IsOrphaningHPA() {
 			scale, err := (*r.scaleClient).Scales(scaledObject.Namespace).Get(context.TODO(), scaledObject.Status.ScaleTargetGVKR.GroupResource(), scaledObject.Spec.ScaleTargetRef.Name, metav1.GetOptions{})
 			if err != nil {
 				if errors.IsNotFound(err) {
@@ -57,6 +68,36 @@ func (r *ScaledObjectReconciler) finalizeScaledObject(logger logr.Logger, scaled
 	return nil
 }
 
+// orphanHPA removes the ScaledObject from the owners of its HPA, so the HPA isn't garbage collected with the ScaledObject
+func (r *ScaledObjectReconciler) orphanHPA(logger logr.Logger, scaledObject *kedav1alpha1.ScaledObject) error {
+	hpa := &autoscalingv2beta2.HorizontalPodAutoscaler{}
+	err := r.Client.Get(context.TODO(), types.NamespacedName{Name: kedautil.GetHPAName(scaledObject), Namespace: scaledObject.Namespace}, hpa)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			logger.V(1).Info("No HPA to orphan, it was probably deleted or never created")
+			return nil
+		}
+		logger.Error(err, "Failed to get HPA from a finalizer", "finalizer", scaledObjectFinalizer)
+		return err
+	}
+
+	owners := []metav1.OwnerReference{}
+	for _, owner := range hpa.GetOwnerReferences() {
+		if owner.UID != scaledObject.UID {
+			owners = append(owners, owner)
+		}
+	}
+	hpa.SetOwnerReferences(owners)
+	delete(hpa.Labels, "app.kubernetes.io/managed-by")
+
+	if err := r.Client.Update(context.TODO(), hpa); err != nil {
+		logger.Error(err, "Failed to remove the ScaledObject from the owners of the HPA", "HPA.Namespace", hpa.Namespace, "HPA.Name", hpa.Name)
+		return err
+	}
+	logger.Info("Orphaned HPA, it is no longer managed by KEDA", "HPA.Namespace", hpa.Namespace, "HPA.Name", hpa.Name)
+	return nil
+}
+
 // ensureFinalizer check there is finalizer present on the ScaledObject, if not it adds one
 func (r *ScaledObjectReconciler) ensureFinalizer(logger logr.Logger, scaledObject *kedav1alpha1.ScaledObject) error {
 	if !util.Contains(scaledObject.GetFinalizers(), scaledObjectFinalizer) {
@@ -0,0 +1,84 @@
+package controllers
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+	kedautil "github.com/kedacore/keda/pkg/util"
+)
+
+func TestFinalizeScaledObjectOrphansHPA(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = kedav1alpha1.AddToScheme(scheme)
+	isController := true
+
+	scaledObject := &kedav1alpha1.ScaledObject{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "app", UID: "scaledobject-uid", Finalizers: []string{scaledObjectFinalizer}},
+		Spec: kedav1alpha1.ScaledObjectSpec{
+			ScaleTargetRef: &kedav1alpha1.ScaleTarget{Name: "app"},
+			Advanced:       &kedav1alpha1.AdvancedConfig{OrphanHPA: true, RestoreToOriginalReplicaCount: true},
+		},
+	}
+	hpa := &autoscalingv2beta2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      kedautil.GetHPAName(scaledObject),
+			Labels:    map[string]string{"app.kubernetes.io/managed-by": "keda-operator", "app.kubernetes.io/part-of": "app"},
+			OwnerReferences: []metav1.OwnerReference{
+				{APIVersion: "keda.sh/v1alpha1", Kind: "ScaledObject", Name: "app", UID: "scaledobject-uid", Controller: &isController},
+				{APIVersion: "v1", Kind: "ConfigMap", Name: "other", UID: "configmap-uid"},
+			},
+		},
+	}
+
+	reconciler := &ScaledObjectReconciler{
+		Client:                   fake.NewFakeClientWithScheme(scheme, scaledObject, hpa),
+		scaleHandler:             &fakeScaleHandler{},
+		scaledObjectsGenerations: &sync.Map{},
+		appliedHPASpecs:          &sync.Map{},
+	}
+	// the replica count isn't restored, so the scale client isn't needed
+	if err := reconciler.finalizeScaledObject(logf.Log, scaledObject); err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+
+	orphaned := &autoscalingv2beta2.HorizontalPodAutoscaler{}
+	if err := reconciler.Client.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: kedautil.GetHPAName(scaledObject)}, orphaned); err != nil {
+		t.Fatalf("Expected the HPA to be kept, got %s", err)
+	}
+	if len(orphaned.OwnerReferences) != 1 || orphaned.OwnerReferences[0].UID != "configmap-uid" {
+		t.Errorf("Expected only the other owner to be kept, got %+v", orphaned.OwnerReferences)
+	}
+	if _, ok := orphaned.Labels["app.kubernetes.io/managed-by"]; ok {
+		t.Errorf("Expected the managed-by label to be removed, got %v", orphaned.Labels)
+	}
+	if orphaned.Labels["app.kubernetes.io/part-of"] != "app" {
+		t.Errorf("Expected the other labels to be kept, got %v", orphaned.Labels)
+	}
+	if len(scaledObject.Finalizers) != 0 {
+		t.Errorf("Expected the finalizer to be removed, got %v", scaledObject.Finalizers)
+	}
+}
+
+func TestOrphanHPANotFound(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = kedav1alpha1.AddToScheme(scheme)
+
+	scaledObject := &kedav1alpha1.ScaledObject{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "app"}}
+	reconciler := &ScaledObjectReconciler{Client: fake.NewFakeClientWithScheme(scheme, scaledObject)}
+	if err := reconciler.orphanHPA(logf.Log, scaledObject); err != nil {
+		t.Errorf("Expected no error if there is no HPA, got %s", err)
+	}
+}
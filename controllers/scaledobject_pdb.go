@@ -0,0 +1,82 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+	"github.com/kedacore/keda/pkg/eventemitter"
+	kedautil "github.com/kedacore/keda/pkg/util"
+)
+
+const (
+	// minReplicasDeferredReason is the reason of the ScaleDownDeferred condition set by the controller,
+	// the executor sets its own reason when it defers the scale to zero
+	minReplicasDeferredReason = "MinReplicasViolatesPDB"
+	// pdbRecheckInterval is the interval the ScaledObject is reconciled at while lowering the MinReplicas of its HPA is deferred
+	pdbRecheckInterval = 30 * time.Second
+)
+
+// deferHPAMinReplicasLowering keeps the MinReplicas of the found HPA in the new HPA if lowering it would violate a PodDisruptionBudget
+// covering the pods of the ScaleTarget, the ScaleDownDeferred condition of the ScaledObject is set accordingly.
+// Lowering the MinReplicas isn't deferred if the PodDisruptionBudgets can't be read
+func (r *ScaledObjectReconciler) deferHPAMinReplicasLowering(logger logr.Logger, scaledObject *kedav1alpha1.ScaledObject, foundHpa, hpa *autoscalingv2beta2.HorizontalPodAutoscaler, gvkr *kedav1alpha1.GroupVersionKindResource) {
+	lowered := foundHpa.Spec.MinReplicas != nil && hpa.Spec.MinReplicas != nil && *hpa.Spec.MinReplicas < *foundHpa.Spec.MinReplicas
+	if !lowered || !scaledObject.IsCheckingPodDisruptionBudgets() {
+		clearHPAMinReplicasDeferred(scaledObject)
+		return
+	}
+
+	scale, err := (*r.scaleClient).Scales(scaledObject.Namespace).Get(context.TODO(), gvkr.GroupResource(), scaledObject.Spec.ScaleTargetRef.Name, metav1.GetOptions{})
+	if err != nil {
+		logger.Error(err, "Failed to get the scale of the ScaleTarget, lowering the MinReplicas of the HPA isn't deferred")
+		return
+	}
+	r.deferHPAMinReplicasLoweringForPods(logger, scaledObject, foundHpa, hpa, scale.Status.Selector)
+}
+
+// deferHPAMinReplicasLoweringForPods checks the PodDisruptionBudgets covering the pods of the selector of the ScaleTarget
+func (r *ScaledObjectReconciler) deferHPAMinReplicasLoweringForPods(logger logr.Logger, scaledObject *kedav1alpha1.ScaledObject, foundHpa, hpa *autoscalingv2beta2.HorizontalPodAutoscaler, podSelector string) {
+	violated, err := kedautil.GetViolatedPodDisruptionBudgets(context.TODO(), r.Client, scaledObject.Namespace, podSelector, *hpa.Spec.MinReplicas)
+	if err != nil {
+		logger.Error(err, "Failed to check the PodDisruptionBudgets of the ScaleTarget, lowering the MinReplicas of the HPA isn't deferred")
+		return
+	}
+	if len(violated) == 0 {
+		clearHPAMinReplicasDeferred(scaledObject)
+		return
+	}
+
+	msg := fmt.Sprintf("lowering the MinReplicas of the HPA from %d to %d is deferred as it violates the PodDisruptionBudget %s",
+		*foundHpa.Spec.MinReplicas, *hpa.Spec.MinReplicas, strings.Join(violated, ", "))
+	hpa.Spec.MinReplicas = foundHpa.Spec.MinReplicas
+	if hpa.Spec.MaxReplicas < *hpa.Spec.MinReplicas {
+		hpa.Spec.MaxReplicas = *hpa.Spec.MinReplicas
+	}
+
+	conditions := &scaledObject.Status.Conditions
+	if condition := conditions.GetScaleDownDeferredCondition(); !condition.IsTrue() || condition.Message != msg {
+		logger.Info("The MinReplicas of the HPA is kept, " + msg)
+		r.eventEmitter.Emit("ScaledObject", scaledObject.Namespace, scaledObject.Name, eventemitter.ScaleDownDeferredType, msg)
+	}
+	conditions.SetScaleDownDeferredCondition(metav1.ConditionTrue, minReplicasDeferredReason, msg)
+}
+
+// clearHPAMinReplicasDeferred sets the ScaleDownDeferred condition to false if the controller deferred lowering the MinReplicas of the HPA
+func clearHPAMinReplicasDeferred(scaledObject *kedav1alpha1.ScaledObject) {
+	if isHPAMinReplicasLoweringDeferred(scaledObject.Status.Conditions) {
+		scaledObject.Status.Conditions.SetScaleDownDeferredCondition(metav1.ConditionFalse, "NoScaleDownDeferred", "No scale down of the ScaleTarget is deferred")
+	}
+}
+
+// isHPAMinReplicasLoweringDeferred returns true if lowering the MinReplicas of the HPA is deferred, the ScaledObject is then reconciled again
+func isHPAMinReplicasLoweringDeferred(conditions kedav1alpha1.Conditions) bool {
+	condition := conditions.GetScaleDownDeferredCondition()
+	return condition.IsTrue() && condition.Reason == minReplicasDeferredReason
+}
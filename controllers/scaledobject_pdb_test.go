@@ -0,0 +1,85 @@
+package controllers
+
+import (
+	"testing"
+
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
+	corev1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+	"github.com/kedacore/keda/pkg/eventemitter"
+)
+
+func newTestHPAMinReplicas(minReplicas, maxReplicas int32) *autoscalingv2beta2.HorizontalPodAutoscaler {
+	return &autoscalingv2beta2.HorizontalPodAutoscaler{
+		Spec: autoscalingv2beta2.HorizontalPodAutoscalerSpec{MinReplicas: &minReplicas, MaxReplicas: maxReplicas},
+	}
+}
+
+func TestDeferHPAMinReplicasLoweringForPods(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = kedav1alpha1.AddToScheme(scheme)
+
+	minAvailable := intstr.FromInt(3)
+	pdb := &policyv1beta1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "worker"},
+		Spec: policyv1beta1.PodDisruptionBudgetSpec{
+			MinAvailable: &minAvailable,
+			Selector:     &metav1.LabelSelector{MatchLabels: map[string]string{"app": "worker"}},
+		},
+	}
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "worker-1", Labels: map[string]string{"app": "worker"}}}
+	scaledObject := &kedav1alpha1.ScaledObject{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "worker"}}
+	scaledObject.Status.Conditions = *kedav1alpha1.GetInitializedConditions()
+
+	client := fake.NewFakeClientWithScheme(scheme, pdb, pod)
+	reconciler := &ScaledObjectReconciler{Client: client, eventEmitter: eventemitter.NewEventEmitter(client)}
+
+	// lowering the MinReplicas from 4 to 2 leaves less than the 3 pods the PodDisruptionBudget requires
+	foundHpa := newTestHPAMinReplicas(4, 4)
+	hpa := newTestHPAMinReplicas(2, 2)
+	reconciler.deferHPAMinReplicasLoweringForPods(logf.Log, scaledObject, foundHpa, hpa, "app=worker")
+	if *hpa.Spec.MinReplicas != 4 || hpa.Spec.MaxReplicas != 4 {
+		t.Errorf("Expected the MinReplicas and MaxReplicas of the found HPA kept, got %d and %d", *hpa.Spec.MinReplicas, hpa.Spec.MaxReplicas)
+	}
+	if !isHPAMinReplicasLoweringDeferred(scaledObject.Status.Conditions) {
+		t.Error("Expected the lowering of the MinReplicas deferred in the conditions")
+	}
+
+	// lowering the MinReplicas to 3 meets the PodDisruptionBudget
+	hpa = newTestHPAMinReplicas(3, 10)
+	reconciler.deferHPAMinReplicasLoweringForPods(logf.Log, scaledObject, foundHpa, hpa, "app=worker")
+	if *hpa.Spec.MinReplicas != 3 {
+		t.Errorf("Expected the MinReplicas lowered to 3, got %d", *hpa.Spec.MinReplicas)
+	}
+	if condition := scaledObject.Status.Conditions.GetScaleDownDeferredCondition(); !condition.IsFalse() {
+		t.Errorf("Expected the ScaleDownDeferred condition cleared, got %s", condition.Status)
+	}
+}
+
+func TestDeferHPAMinReplicasLoweringDisabled(t *testing.T) {
+	scaledObject := &kedav1alpha1.ScaledObject{
+		Spec: kedav1alpha1.ScaledObjectSpec{Advanced: &kedav1alpha1.AdvancedConfig{DisablePodDisruptionBudgetCheck: true}},
+	}
+	scaledObject.Status.Conditions = *kedav1alpha1.GetInitializedConditions()
+	scaledObject.Status.Conditions.SetScaleDownDeferredCondition(metav1.ConditionTrue, minReplicasDeferredReason, "")
+
+	// the scale client isn't used as the PodDisruptionBudgets aren't checked
+	hpa := newTestHPAMinReplicas(1, 4)
+	reconciler := &ScaledObjectReconciler{}
+	reconciler.deferHPAMinReplicasLowering(logf.Log, scaledObject, newTestHPAMinReplicas(4, 4), hpa, nil)
+	if *hpa.Spec.MinReplicas != 1 {
+		t.Errorf("Expected the MinReplicas lowered to 1, got %d", *hpa.Spec.MinReplicas)
+	}
+	if isHPAMinReplicasLoweringDeferred(scaledObject.Status.Conditions) {
+		t.Error("Expected the ScaleDownDeferred condition cleared")
+	}
+}
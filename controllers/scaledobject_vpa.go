@@ -0,0 +1,85 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+	"github.com/kedacore/keda/pkg/eventemitter"
+)
+
+// vpaListGroupVersionKind is the kind of the lists of VerticalPodAutoscalers, they are read as unstructured objects as their CRD may not be installed
+var vpaListGroupVersionKind = schema.GroupVersionKind{Group: "autoscaling.k8s.io", Version: "v1", Kind: "VerticalPodAutoscalerList"}
+
+// vpaUpdatesScaleTarget returns true if the VerticalPodAutoscaler targets the ScaleTarget of the ScaledObject and updates the resources
+// of its running pods, the Off and Initial update modes only recommend the resources or set them on the new pods
+func vpaUpdatesScaleTarget(scaledObject *kedav1alpha1.ScaledObject, vpa *unstructured.Unstructured) bool {
+	apiVersion, _, _ := unstructured.NestedString(vpa.Object, "spec", "targetRef", "apiVersion")
+	kind, _, _ := unstructured.NestedString(vpa.Object, "spec", "targetRef", "kind")
+	name, _, _ := unstructured.NestedString(vpa.Object, "spec", "targetRef", "name")
+	if !sameScaleTarget(scaledObject.Spec.ScaleTargetRef.APIVersion, scaledObject.Spec.ScaleTargetRef.Kind, scaledObject.Spec.ScaleTargetRef.Name, apiVersion, kind, name) {
+		return false
+	}
+
+	// the update mode defaults to Auto
+	updateMode, _, _ := unstructured.NestedString(vpa.Object, "spec", "updatePolicy", "updateMode")
+	return updateMode != "Off" && updateMode != "Initial"
+}
+
+// checkVPAConflict sets the VPAConflict condition of the ScaledObject, true if a VerticalPodAutoscaler updates the pods of its ScaleTarget.
+// The VerticalPodAutoscalers are ignored if their CRD isn't installed, the check doesn't fail the reconcile.
+func (r *ScaledObjectReconciler) checkVPAConflict(logger logr.Logger, scaledObject *kedav1alpha1.ScaledObject) {
+	vpas := &unstructured.UnstructuredList{}
+	vpas.SetGroupVersionKind(vpaListGroupVersionKind)
+	if err := r.Client.List(context.TODO(), vpas, client.InNamespace(scaledObject.Namespace)); err != nil {
+		if !meta.IsNoMatchError(err) {
+			logger.V(1).Info("Failed to list the VerticalPodAutoscalers, the VPAConflict condition is left as is", "error", err.Error())
+		}
+		return
+	}
+
+	var names []string
+	for i := range vpas.Items {
+		if vpaUpdatesScaleTarget(scaledObject, &vpas.Items[i]) {
+			names = append(names, vpas.Items[i].GetName())
+		}
+	}
+
+	conditions := &scaledObject.Status.Conditions
+	if len(names) == 0 {
+		conditions.SetVPAConflictCondition(metav1.ConditionFalse, "NoVPAConflict", "No VerticalPodAutoscaler updates the pods of the ScaleTarget")
+		return
+	}
+
+	msg := fmt.Sprintf("the pods of the scaleTargetRef %s are updated by the VerticalPodAutoscaler %s", scaledObject.Spec.ScaleTargetRef.Name, strings.Join(names, ", "))
+	if isActivationOnlyWithVPAEnabled(scaledObject) {
+		msg += ", KEDA only activates and deactivates the ScaleTarget"
+	}
+	if condition := conditions.GetVPAConflictCondition(); !condition.IsTrue() {
+		logger.Info("The replicas and the resources of the ScaleTarget may oscillate, " + msg)
+		r.eventEmitter.Emit("ScaledObject", scaledObject.Namespace, scaledObject.Name, eventemitter.ScaledObjectVPAConflictType, msg)
+	}
+	conditions.SetVPAConflictCondition(metav1.ConditionTrue, "VPAUpdatesScaleTarget", msg)
+}
+
+// isActivationOnlyWithVPAEnabled returns true if KEDA only activates the ScaleTarget of the ScaledObject while a VerticalPodAutoscaler updates its pods
+func isActivationOnlyWithVPAEnabled(scaledObject *kedav1alpha1.ScaledObject) bool {
+	return scaledObject.Spec.Advanced != nil && scaledObject.Spec.Advanced.ActivationOnlyWithVPA
+}
+
+// isActivationOnlyWithVPA returns true if the ScaleTarget of the ScaledObject is only activated, as a VerticalPodAutoscaler updates its pods
+func isActivationOnlyWithVPA(scaledObject *kedav1alpha1.ScaledObject) bool {
+	if !isActivationOnlyWithVPAEnabled(scaledObject) {
+		return false
+	}
+	condition := scaledObject.Status.Conditions.GetVPAConflictCondition()
+	return condition.IsTrue()
+}
@@ -0,0 +1,97 @@
+package controllers
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+	"github.com/kedacore/keda/pkg/eventemitter"
+	kedautil "github.com/kedacore/keda/pkg/util"
+)
+
+func newTestVPA(name, targetKind, targetName, updateMode string) *unstructured.Unstructured {
+	spec := map[string]interface{}{
+		"targetRef": map[string]interface{}{"apiVersion": "apps/v1", "kind": targetKind, "name": targetName},
+	}
+	if updateMode != "" {
+		spec["updatePolicy"] = map[string]interface{}{"updateMode": updateMode}
+	}
+	vpa := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+	vpa.SetAPIVersion("autoscaling.k8s.io/v1")
+	vpa.SetKind("VerticalPodAutoscaler")
+	vpa.SetNamespace("default")
+	vpa.SetName(name)
+	return vpa
+}
+
+func TestVPAUpdatesScaleTarget(t *testing.T) {
+	scaledObject := &kedav1alpha1.ScaledObject{
+		Spec: kedav1alpha1.ScaledObjectSpec{ScaleTargetRef: &kedav1alpha1.ScaleTarget{Name: "app"}},
+	}
+
+	tests := []struct {
+		name    string
+		vpa     *unstructured.Unstructured
+		updates bool
+	}{
+		{"default update mode", newTestVPA("vpa", "Deployment", "app", ""), true},
+		{"Auto", newTestVPA("vpa", "Deployment", "app", "Auto"), true},
+		{"Recreate", newTestVPA("vpa", "Deployment", "app", "Recreate"), true},
+		{"Initial", newTestVPA("vpa", "Deployment", "app", "Initial"), false},
+		{"Off", newTestVPA("vpa", "Deployment", "app", "Off"), false},
+		{"another target", newTestVPA("vpa", "Deployment", "worker", "Auto"), false},
+		{"another kind", newTestVPA("vpa", "StatefulSet", "app", "Auto"), false},
+	}
+
+	for _, test := range tests {
+		if updates := vpaUpdatesScaleTarget(scaledObject, test.vpa); updates != test.updates {
+			t.Errorf("%s: expected %t, got %t", test.name, test.updates, updates)
+		}
+	}
+}
+
+func TestCheckVPAConflict(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = kedav1alpha1.AddToScheme(scheme)
+	// the fake client reads the unstructured VerticalPodAutoscalers through the scheme
+	scheme.AddKnownTypeWithName(vpaListGroupVersionKind.GroupVersion().WithKind("VerticalPodAutoscaler"), &unstructured.Unstructured{})
+	scheme.AddKnownTypeWithName(vpaListGroupVersionKind, &unstructured.UnstructuredList{})
+
+	scaledObject := &kedav1alpha1.ScaledObject{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "app"},
+		Spec: kedav1alpha1.ScaledObjectSpec{
+			ScaleTargetRef: &kedav1alpha1.ScaleTarget{Name: "app"},
+			Advanced:       &kedav1alpha1.AdvancedConfig{ActivationOnlyWithVPA: true},
+		},
+	}
+
+	client := fake.NewFakeClientWithScheme(scheme, newTestVPA("app-vpa", "Deployment", "app", "Auto"), newTestVPA("worker-vpa", "Deployment", "worker", "Auto"))
+	reconciler := &ScaledObjectReconciler{Client: client, eventEmitter: eventemitter.NewEventEmitter(client)}
+	reconciler.checkVPAConflict(logf.Log, scaledObject)
+	if condition := scaledObject.Status.Conditions.GetVPAConflictCondition(); !condition.IsTrue() || condition.Reason != "VPAUpdatesScaleTarget" {
+		t.Errorf("Expected the VPAConflict condition to be true, got %+v", condition)
+	}
+	if !isActivationOnlyWithVPA(scaledObject) {
+		t.Errorf("Expected the ScaleTarget to be only activated")
+	}
+	if maxReplicas := getHPAMaxReplicas(scaledObject); maxReplicas != kedautil.DefaultHPAMinReplicas {
+		t.Errorf("Expected the MaxReplicas of the HPA to be its MinReplicas, got %d", maxReplicas)
+	}
+
+	client = fake.NewFakeClientWithScheme(scheme, newTestVPA("app-vpa", "Deployment", "app", "Off"))
+	reconciler = &ScaledObjectReconciler{Client: client, eventEmitter: eventemitter.NewEventEmitter(client)}
+	reconciler.checkVPAConflict(logf.Log, scaledObject)
+	if condition := scaledObject.Status.Conditions.GetVPAConflictCondition(); !condition.IsFalse() {
+		t.Errorf("Expected the VPAConflict condition to be false, got %+v", condition)
+	}
+	if maxReplicas := getHPAMaxReplicas(scaledObject); maxReplicas != defaultHPAMaxReplicas {
+		t.Errorf("Expected the default MaxReplicas of the HPA, got %d", maxReplicas)
+	}
+}
@@ -0,0 +1,166 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/discovery"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+	"github.com/kedacore/keda/pkg/globalconfig"
+	kedautil "github.com/kedacore/keda/pkg/util"
+)
+
+const (
+	// ScaledObjectValidationPath is the path of the validating webhook of the keda.sh/v1alpha1 ScaledObjects
+	ScaledObjectValidationPath = "/validate-keda-sh-v1alpha1-scaledobject"
+	// ScaledJobValidationPath is the path of the validating webhook of the ScaledJobs
+	ScaledJobValidationPath = "/validate-keda-sh-v1alpha1-scaledjob"
+)
+
+// scaledObjectValidator rejects the ScaledObjects violating the scaling policy of the global configuration, those whose triggers have an
+// unsupported thresholdType and those whose ScaleTarget
+// is already scaled by another ScaledObject or by an HPA, the reconciler only reports them in their status once they are created
+type scaledObjectValidator struct {
+	client  client.Client
+	decoder *admission.Decoder
+	// config returns the global configuration the ScaledObjects are checked against
+	config func() globalconfig.Config
+}
+
+// scaledJobValidator rejects the ScaledJobs whose triggers violate the scaling policy of the global configuration or set a thresholdType
+type scaledJobValidator struct {
+	decoder *admission.Decoder
+	config  func() globalconfig.Config
+}
+
+var webhookLog = ctrl.Log.WithName("webhooks")
+
+// SetupValidatingWebhooksWithManager registers the validating webhooks of the keda.sh/v1alpha1 ScaledObjects and of the ScaledJobs
+// on ScaledObjectValidationPath and ScaledJobValidationPath of the webhook server of the manager. The HPAs are listed with the
+// version of the HPA API served by the cluster, as the reconciler manages them
+func SetupValidatingWebhooksWithManager(mgr ctrl.Manager) error {
+	decoder, err := admission.NewDecoder(mgr.GetScheme())
+	if err != nil {
+		return err
+	}
+	clientset, err := discovery.NewDiscoveryClientForConfig(mgr.GetConfig())
+	if err != nil {
+		return err
+	}
+	hpaVersion, err := kedautil.DetectHPAVersion(clientset)
+	if err != nil {
+		webhookLog.Error(err, "Not able to detect the versions of the HPA API, using "+hpaVersion.String())
+	}
+	mgr.GetWebhookServer().Register(ScaledObjectValidationPath, &webhook.Admission{
+		Handler: &scaledObjectValidator{client: kedautil.NewHPAVersionClient(mgr.GetClient(), hpaVersion), decoder: decoder, config: globalconfig.Get},
+	})
+	mgr.GetWebhookServer().Register(ScaledJobValidationPath, &webhook.Admission{
+		Handler: &scaledJobValidator{decoder: decoder, config: globalconfig.Get},
+	})
+	return nil
+}
+
+// Handle validates the creation and the update of a ScaledObject
+func (v *scaledObjectValidator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	scaledObject := &kedav1alpha1.ScaledObject{}
+	if err := v.decoder.Decode(req, scaledObject); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	// the ScaledObjects being deleted remove their finalizer with an update
+	if scaledObject.GetDeletionTimestamp() != nil {
+		return admission.Allowed("")
+	}
+	var old *kedav1alpha1.ScaledObject
+	if req.Operation == admissionv1beta1.Update {
+		old = &kedav1alpha1.ScaledObject{}
+		if err := v.decoder.DecodeRaw(req.OldObject, old); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+	}
+	// the ScaledObject being created is the newest one
+	if scaledObject.CreationTimestamp.IsZero() {
+		scaledObject.CreationTimestamp = metav1.Now()
+	}
+
+	// the updates keeping the triggers were already checked, the ScaledObjects of the namespace are only counted on creation
+	config := v.config()
+	if old == nil || !equality.Semantic.DeepEqual(old.Spec.Triggers, scaledObject.Spec.Triggers) {
+		if err := config.CheckTriggers(scaledObject.Spec.Triggers); err != nil {
+			return admission.Denied(err.Error())
+		}
+		if err := validateThresholdTypes(scaledObject.Spec.Triggers); err != nil {
+			return admission.Denied(err.Error())
+		}
+	}
+	if old == nil {
+		if response, denied := deniedResponse(checkScaledObjectsPerNamespace(ctx, v.client, config, scaledObject)); denied {
+			return response
+		}
+	}
+
+	// dry-run ScaledObjects don't create an HPA, the updates keeping the ScaleTarget were already checked
+	if scaledObject.IsDryRun() || old != nil && !old.IsDryRun() &&
+		sameScaleTarget(old.Spec.ScaleTargetRef.APIVersion, old.Spec.ScaleTargetRef.Kind, old.Spec.ScaleTargetRef.Name,
+			scaledObject.Spec.ScaleTargetRef.APIVersion, scaledObject.Spec.ScaleTargetRef.Kind, scaledObject.Spec.ScaleTargetRef.Name) {
+		return admission.Allowed("")
+	}
+	// the reconciler checks the conflicts again, a ScaledObject isn't rejected because they couldn't be looked up
+	err := findScaleTargetConflict(ctx, v.client, scaledObject)
+	if _, ok := err.(*scaleTargetConflictError); err != nil && !ok {
+		webhookLog.Error(err, "Failed to check the conflicts of the scaleTargetRef, the ScaledObject is allowed", "namespace", scaledObject.Namespace, "name", scaledObject.Name)
+		return admission.Allowed(fmt.Sprintf("the conflicts of the scaleTargetRef couldn't be checked: %s", err))
+	}
+	if response, denied := deniedResponse(err); denied {
+		return response
+	}
+	return admission.Allowed("")
+}
+
+// Handle validates the creation and the update of a ScaledJob, the updates keeping the triggers were already checked
+func (v *scaledJobValidator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	scaledJob := &kedav1alpha1.ScaledJob{}
+	if err := v.decoder.Decode(req, scaledJob); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	if scaledJob.GetDeletionTimestamp() != nil {
+		return admission.Allowed("")
+	}
+	if req.Operation == admissionv1beta1.Update {
+		old := &kedav1alpha1.ScaledJob{}
+		if err := v.decoder.DecodeRaw(req.OldObject, old); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		if equality.Semantic.DeepEqual(old.Spec.Triggers, scaledJob.Spec.Triggers) {
+			return admission.Allowed("")
+		}
+	}
+	if err := v.config().CheckTriggers(scaledJob.Spec.Triggers); err != nil {
+		return admission.Denied(err.Error())
+	}
+	if err := validateScaledJobThresholdTypes(scaledJob.Spec.Triggers); err != nil {
+		return admission.Denied(err.Error())
+	}
+	return admission.Allowed("")
+}
+
+// deniedResponse returns the response denying the request for a conflict or a policy violation,
+// or failing it for the other errors. It returns false if there is no error
+func deniedResponse(err error) (admission.Response, bool) {
+	switch err.(type) {
+	case nil:
+		return admission.Response{}, false
+	case *scaleTargetConflictError, *policyViolationError:
+		return admission.Denied(err.Error()), true
+	default:
+		return admission.Errored(http.StatusInternalServerError, err), true
+	}
+}
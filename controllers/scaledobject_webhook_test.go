@@ -0,0 +1,155 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+	"github.com/kedacore/keda/pkg/globalconfig"
+	kedautil "github.com/kedacore/keda/pkg/util"
+)
+
+func TestScaledObjectValidator(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = kedav1alpha1.AddToScheme(scheme)
+	decoder, err := admission.NewDecoder(scheme)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newScaledObject := func(name, target string, created time.Time) *kedav1alpha1.ScaledObject {
+		return &kedav1alpha1.ScaledObject{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "keda.sh/v1alpha1", Kind: "ScaledObject"},
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name, CreationTimestamp: metav1.NewTime(created)},
+			Spec:       kedav1alpha1.ScaledObjectSpec{ScaleTargetRef: &kedav1alpha1.ScaleTarget{Name: target}},
+		}
+	}
+	existing := newScaledObject("existing", "app", time.Now().Add(-time.Hour))
+	validator := &scaledObjectValidator{client: fake.NewFakeClientWithScheme(scheme, existing), decoder: decoder, config: func() globalconfig.Config { return globalconfig.Config{} }}
+
+	newRequest := func(operation admissionv1beta1.Operation, object, old *kedav1alpha1.ScaledObject) admission.Request {
+		req := admission.Request{AdmissionRequest: admissionv1beta1.AdmissionRequest{Operation: operation}}
+		req.Object.Raw, _ = json.Marshal(object)
+		if old != nil {
+			req.OldObject.Raw, _ = json.Marshal(old)
+		}
+		return req
+	}
+
+	dryRun := newScaledObject("dry-run", "app", time.Time{})
+	dryRun.Spec.Advanced = &kedav1alpha1.AdvancedConfig{DryRun: true}
+	conflicting := newScaledObject("conflicting", "app", time.Now())
+	deleted := conflicting.DeepCopy()
+	deleted.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+	retargeted := newScaledObject("worker", "app", time.Now())
+
+	tests := []struct {
+		name    string
+		req     admission.Request
+		allowed bool
+	}{
+		{"create with another target", newRequest(admissionv1beta1.Create, newScaledObject("worker", "worker", time.Time{}), nil), true},
+		{"create with a scaled target", newRequest(admissionv1beta1.Create, newScaledObject("new", "app", time.Time{}), nil), false},
+		{"create in dry-run with a scaled target", newRequest(admissionv1beta1.Create, dryRun, nil), true},
+		{"update keeping the target", newRequest(admissionv1beta1.Update, conflicting, conflicting), true},
+		{"update of a ScaledObject being deleted", newRequest(admissionv1beta1.Update, deleted, conflicting), true},
+		{"update to a scaled target", newRequest(admissionv1beta1.Update, retargeted, newScaledObject("worker", "worker", time.Now())), false},
+		{"update of the oldest ScaledObject", newRequest(admissionv1beta1.Update, existing, newScaledObject("existing", "worker", time.Now())), true},
+	}
+
+	for _, test := range tests {
+		response := validator.Handle(context.TODO(), test.req)
+		if response.Allowed != test.allowed {
+			t.Errorf("%s: expected allowed %t, got %t: %v", test.name, test.allowed, response.Allowed, response.Result)
+		}
+	}
+}
+
+// restMapperClient fails the requests of the kinds its RESTMapper doesn't serve as the API server does, the fake client doesn't check them
+type restMapperClient struct {
+	client.Client
+	scheme *runtime.Scheme
+	mapper meta.RESTMapper
+}
+
+func (c *restMapperClient) checkMapping(obj runtime.Object) error {
+	gvk, err := apiutil.GVKForObject(obj, c.scheme)
+	if err != nil {
+		return err
+	}
+	_, err = c.mapper.RESTMapping(schema.GroupKind{Group: gvk.Group, Kind: strings.TrimSuffix(gvk.Kind, "List")}, gvk.Version)
+	return err
+}
+
+func (c *restMapperClient) List(ctx context.Context, list runtime.Object, opts ...client.ListOption) error {
+	if err := c.checkMapping(list); err != nil {
+		return err
+	}
+	return c.Client.List(ctx, list, opts...)
+}
+
+func TestScaledObjectValidatorWithHPAV2(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = kedav1alpha1.AddToScheme(scheme)
+	scheme.AddKnownTypeWithName(kedautil.HPAVersionV2.WithKind("HorizontalPodAutoscaler"), &unstructured.Unstructured{})
+	scheme.AddKnownTypeWithName(kedautil.HPAVersionV2.WithKind("HorizontalPodAutoscalerList"), &unstructured.UnstructuredList{})
+	decoder, err := admission.NewDecoder(scheme)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the cluster only serves autoscaling/v2
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(kedautil.HPAVersionV2.WithKind("HorizontalPodAutoscaler"), meta.RESTScopeNamespace)
+	mapper.Add(kedav1alpha1.GroupVersion.WithKind("ScaledObject"), meta.RESTScopeNamespace)
+	hpa := &unstructured.Unstructured{}
+	hpa.SetGroupVersionKind(kedautil.HPAVersionV2.WithKind("HorizontalPodAutoscaler"))
+	hpa.SetNamespace("default")
+	hpa.SetName("app")
+	_ = unstructured.SetNestedField(hpa.Object, map[string]interface{}{"apiVersion": "apps/v1", "kind": "Deployment", "name": "app"}, "spec", "scaleTargetRef")
+	kubeClient := &restMapperClient{Client: fake.NewFakeClientWithScheme(scheme, hpa), scheme: scheme, mapper: mapper}
+
+	newRequest := func(target string) admission.Request {
+		object := &kedav1alpha1.ScaledObject{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "keda.sh/v1alpha1", Kind: "ScaledObject"},
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "new"},
+			Spec:       kedav1alpha1.ScaledObjectSpec{ScaleTargetRef: &kedav1alpha1.ScaleTarget{Name: target}},
+		}
+		req := admission.Request{AdmissionRequest: admissionv1beta1.AdmissionRequest{Operation: admissionv1beta1.Create}}
+		req.Object.Raw, _ = json.Marshal(object)
+		return req
+	}
+	config := func() globalconfig.Config { return globalconfig.Config{} }
+
+	// the HPAs are listed with autoscaling/v2 as the reconciler lists them
+	validator := &scaledObjectValidator{client: kedautil.NewHPAVersionClient(kubeClient, kedautil.HPAVersionV2), decoder: decoder, config: config}
+	if response := validator.Handle(context.TODO(), newRequest("app")); response.Allowed {
+		t.Error("Expected the ScaledObject of a target scaled by an autoscaling/v2 HPA to be denied")
+	}
+	if response := validator.Handle(context.TODO(), newRequest("worker")); !response.Allowed {
+		t.Errorf("Expected the ScaledObject of another target to be allowed, got %v", response.Result)
+	}
+
+	// the ScaledObjects are allowed if the HPAs can't be listed, the reconciler checks the conflicts again
+	validator = &scaledObjectValidator{client: kubeClient, decoder: decoder, config: config}
+	if response := validator.Handle(context.TODO(), newRequest("app")); !response.Allowed {
+		t.Errorf("Expected the ScaledObject to be allowed when the HPAs can't be listed, got %v", response.Result)
+	}
+}
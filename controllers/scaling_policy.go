@@ -0,0 +1,61 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+	"github.com/kedacore/keda/pkg/globalconfig"
+)
+
+// policyViolationError is returned when a ScaledObject or ScaledJob exceeds the limits set by the global configuration
+type policyViolationError struct {
+	message string
+}
+
+func (e *policyViolationError) Error() string {
+	return e.message
+}
+
+// checkScalingPolicy returns a policyViolationError if the triggers of the ScaledObject exceed the limits of the global configuration
+// or if the namespace already has the maximum number of ScaledObjects created before this one
+func (r *ScaledObjectReconciler) checkScalingPolicy(logger logr.Logger, scaledObject *kedav1alpha1.ScaledObject) error {
+	config := globalconfig.Get()
+	if err := config.CheckTriggers(scaledObject.Spec.Triggers); err != nil {
+		return &policyViolationError{message: err.Error()}
+	}
+	err := checkScaledObjectsPerNamespace(context.TODO(), r.Client, config, scaledObject)
+	if _, ok := err.(*policyViolationError); err != nil && !ok {
+		logger.Error(err, "Failed to check the ScaledObjects of the namespace")
+	}
+	return err
+}
+
+// checkScaledObjectsPerNamespace returns a policyViolationError if the namespace already has the maximum number of ScaledObjects
+// of the configuration created before this one, or the error listing them
+func checkScaledObjectsPerNamespace(ctx context.Context, kubeClient client.Client, config globalconfig.Config, scaledObject *kedav1alpha1.ScaledObject) error {
+	if config.MaxScaledObjectsPerNamespace == 0 {
+		return nil
+	}
+	scaledObjects := &kedav1alpha1.ScaledObjectList{}
+	if err := kubeClient.List(ctx, scaledObjects, client.InNamespace(scaledObject.Namespace)); err != nil {
+		return fmt.Errorf("error listing ScaledObjects: %s", err)
+	}
+	// the ScaledObjects created first are kept, so a new ScaledObject can't disable the existing ones
+	olderScaledObjects := 0
+	for i := range scaledObjects.Items {
+		other := &scaledObjects.Items[i]
+		if other.Name != scaledObject.Name && other.GetDeletionTimestamp() == nil && createdBefore(other.ObjectMeta, scaledObject.ObjectMeta) {
+			olderScaledObjects++
+		}
+	}
+	if olderScaledObjects >= config.MaxScaledObjectsPerNamespace {
+		return &policyViolationError{
+			message: fmt.Sprintf("the namespace %s already has %d ScaledObjects, the limit is %d", scaledObject.Namespace, olderScaledObjects, config.MaxScaledObjectsPerNamespace),
+		}
+	}
+	return nil
+}
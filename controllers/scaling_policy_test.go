@@ -0,0 +1,200 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+	"sigs.k8s.io/yaml"
+
+	kedav1 "github.com/kedacore/keda/api/v1"
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+	"github.com/kedacore/keda/pkg/globalconfig"
+)
+
+func newPolicyTestScheme() *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = kedav1alpha1.AddToScheme(scheme)
+	return scheme
+}
+
+func newPolicyTestScaledObject(name string, created time.Time, triggerTypes ...string) *kedav1alpha1.ScaledObject {
+	scaledObject := &kedav1alpha1.ScaledObject{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "keda.sh/v1alpha1", Kind: "ScaledObject"},
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name, CreationTimestamp: metav1.NewTime(created)},
+		Spec:       kedav1alpha1.ScaledObjectSpec{ScaleTargetRef: &kedav1alpha1.ScaleTarget{Name: name}},
+	}
+	for _, triggerType := range triggerTypes {
+		scaledObject.Spec.Triggers = append(scaledObject.Spec.Triggers, kedav1alpha1.ScaleTriggers{Type: triggerType})
+	}
+	return scaledObject
+}
+
+func TestCheckScaledObjectsPerNamespace(t *testing.T) {
+	now := time.Now()
+	oldest := newPolicyTestScaledObject("oldest", now.Add(-2*time.Hour))
+	older := newPolicyTestScaledObject("older", now.Add(-time.Hour))
+	deleted := newPolicyTestScaledObject("deleted", now.Add(-3*time.Hour))
+	deleted.DeletionTimestamp = &metav1.Time{Time: now}
+	other := newPolicyTestScaledObject("other", now.Add(-3*time.Hour))
+	other.Namespace = "other"
+	kubeClient := fake.NewFakeClientWithScheme(newPolicyTestScheme(), oldest, older, deleted, other)
+
+	tests := []struct {
+		name         string
+		limit        int
+		scaledObject *kedav1alpha1.ScaledObject
+		violation    bool
+	}{
+		{"no limit", 0, newPolicyTestScaledObject("new", now), false},
+		{"new ScaledObject under the limit", 3, newPolicyTestScaledObject("new", now), false},
+		{"new ScaledObject at the limit", 2, newPolicyTestScaledObject("new", now), true},
+		{"oldest ScaledObject at the limit", 1, oldest, false},
+		{"newer ScaledObject over the limit", 1, older, true},
+	}
+
+	for _, test := range tests {
+		err := checkScaledObjectsPerNamespace(context.TODO(), kubeClient, globalconfig.Config{MaxScaledObjectsPerNamespace: test.limit}, test.scaledObject)
+		if _, ok := err.(*policyViolationError); ok != test.violation {
+			t.Errorf("%s: expected violation %t, got %v", test.name, test.violation, err)
+		}
+	}
+}
+
+func TestScalingPolicyValidation(t *testing.T) {
+	scheme := newPolicyTestScheme()
+	decoder, err := admission.NewDecoder(scheme)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := func() globalconfig.Config {
+		return globalconfig.Config{MaxScaledObjectsPerNamespace: 1, MaxTriggersPerObject: 2, BannedTriggerTypes: []string{"cron"}}
+	}
+	existing := newPolicyTestScaledObject("existing", time.Now().Add(-time.Hour), "cpu")
+	scaledObjectValidator := &scaledObjectValidator{client: fake.NewFakeClientWithScheme(scheme, existing), decoder: decoder, config: config}
+	scaledJobValidator := &scaledJobValidator{decoder: decoder, config: config}
+
+	newRequest := func(operation admissionv1beta1.Operation, object, old interface{}) admission.Request {
+		req := admission.Request{AdmissionRequest: admissionv1beta1.AdmissionRequest{Operation: operation}}
+		req.Object.Raw, _ = json.Marshal(object)
+		if old != nil {
+			req.OldObject.Raw, _ = json.Marshal(old)
+		}
+		return req
+	}
+	newScaledJob := func(triggerTypes ...string) *kedav1alpha1.ScaledJob {
+		scaledJob := &kedav1alpha1.ScaledJob{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "keda.sh/v1alpha1", Kind: "ScaledJob"},
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "job"},
+		}
+		for _, triggerType := range triggerTypes {
+			scaledJob.Spec.Triggers = append(scaledJob.Spec.Triggers, kedav1alpha1.ScaleTriggers{Type: triggerType})
+		}
+		return scaledJob
+	}
+	banned := existing.DeepCopy()
+	banned.Spec.Triggers = []kedav1alpha1.ScaleTriggers{{Type: "cron"}}
+	dryRun := newPolicyTestScaledObject("dry-run", time.Time{}, "cpu")
+	dryRun.Spec.Advanced = &kedav1alpha1.AdvancedConfig{DryRun: true}
+	utilization := newPolicyTestScaledObject("existing", time.Now().Add(-time.Hour), "kafka")
+	utilization.Spec.Triggers[0].ThresholdType = autoscalingv2beta2.UtilizationMetricType
+	thresholdJob := newScaledJob("cpu")
+	thresholdJob.Spec.Triggers[0].ThresholdType = autoscalingv2beta2.ValueMetricType
+	deleted := banned.DeepCopy()
+	deleted.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+
+	tests := []struct {
+		name      string
+		validator admission.Handler
+		req       admission.Request
+		allowed   bool
+	}{
+		{"ScaledObject over the namespace limit", scaledObjectValidator, newRequest(admissionv1beta1.Create, newPolicyTestScaledObject("new", time.Time{}, "cpu"), nil), false},
+		{"dry-run ScaledObject over the namespace limit", scaledObjectValidator, newRequest(admissionv1beta1.Create, dryRun, nil), false},
+		{"ScaledObject update at the namespace limit", scaledObjectValidator, newRequest(admissionv1beta1.Update, existing, existing), true},
+		{"ScaledObject update to a banned trigger", scaledObjectValidator, newRequest(admissionv1beta1.Update, banned, existing), false},
+		{"ScaledObject update keeping a banned trigger", scaledObjectValidator, newRequest(admissionv1beta1.Update, banned, banned), true},
+		{"ScaledObject being deleted", scaledObjectValidator, newRequest(admissionv1beta1.Update, deleted, banned), true},
+		{"ScaledObject update to an unsupported thresholdType", scaledObjectValidator, newRequest(admissionv1beta1.Update, utilization, existing), false},
+		{"ScaledJob with a thresholdType", scaledJobValidator, newRequest(admissionv1beta1.Create, thresholdJob, nil), false},
+		{"ScaledJob under the limits", scaledJobValidator, newRequest(admissionv1beta1.Create, newScaledJob("cpu"), nil), true},
+		{"ScaledJob with too many triggers", scaledJobValidator, newRequest(admissionv1beta1.Create, newScaledJob("cpu", "kafka", "rabbitmq"), nil), false},
+		{"ScaledJob with a banned trigger", scaledJobValidator, newRequest(admissionv1beta1.Create, newScaledJob("cron"), nil), false},
+		{"ScaledJob update to a banned trigger", scaledJobValidator, newRequest(admissionv1beta1.Update, newScaledJob("cron"), newScaledJob("cpu")), false},
+		{"ScaledJob update keeping a banned trigger", scaledJobValidator, newRequest(admissionv1beta1.Update, newScaledJob("cron"), newScaledJob("cron")), true},
+	}
+
+	for _, test := range tests {
+		response := test.validator.Handle(context.TODO(), test.req)
+		if response.Allowed != test.allowed {
+			t.Errorf("%s: expected allowed %t, got %t: %v", test.name, test.allowed, response.Allowed, response.Result)
+		}
+	}
+}
+
+func TestScalingPolicyValidationOfV1ScaledObject(t *testing.T) {
+	webhooks := &admissionregistrationv1.ValidatingWebhookConfiguration{}
+	manifest, err := ioutil.ReadFile("../config/webhook/validating_webhook.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := yaml.Unmarshal(manifest, webhooks); err != nil {
+		t.Fatal(err)
+	}
+	var policyWebhook *admissionregistrationv1.ValidatingWebhook
+	for i := range webhooks.Webhooks {
+		if webhooks.Webhooks[i].ClientConfig.Service != nil && webhooks.Webhooks[i].ClientConfig.Service.Path != nil &&
+			*webhooks.Webhooks[i].ClientConfig.Service.Path == "/validate-keda-sh-v1alpha1-scaledobject" {
+			policyWebhook = &webhooks.Webhooks[i]
+		}
+	}
+	if policyWebhook == nil {
+		t.Fatal("the scaling policy webhook of the ScaledObjects isn't in the manifest")
+	}
+	if policyWebhook.MatchPolicy == nil || *policyWebhook.MatchPolicy != admissionregistrationv1.Equivalent {
+		t.Fatalf("expected the scaling policy webhook to match the keda.sh/v1 ScaledObjects, got the matchPolicy %v", policyWebhook.MatchPolicy)
+	}
+
+	scheme := newPolicyTestScheme()
+	decoder, err := admission.NewDecoder(scheme)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := func() globalconfig.Config {
+		return globalconfig.Config{MaxScaledObjectsPerNamespace: 1}
+	}
+	existing := newPolicyTestScaledObject("existing", time.Now().Add(-time.Hour), "cpu")
+	validator := &scaledObjectValidator{client: fake.NewFakeClientWithScheme(scheme, existing), decoder: decoder, config: config}
+
+	// the API server converts the keda.sh/v1 ScaledObject to keda.sh/v1alpha1 before sending it to the webhook
+	scaledObject := &kedav1.ScaledObject{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "keda.sh/v1", Kind: "ScaledObject"},
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "new"},
+		Spec: kedav1.ScaledObjectSpec{ScaledObjectSpec: kedav1alpha1.ScaledObjectSpec{
+			ScaleTargetRef: &kedav1alpha1.ScaleTarget{Name: "new"},
+			Triggers:       []kedav1alpha1.ScaleTriggers{{Type: "cpu"}},
+		}},
+	}
+	converted := &kedav1alpha1.ScaledObject{}
+	if err := scaledObject.ConvertTo(converted); err != nil {
+		t.Fatal(err)
+	}
+	converted.TypeMeta = metav1.TypeMeta{APIVersion: "keda.sh/v1alpha1", Kind: "ScaledObject"}
+	req := admission.Request{AdmissionRequest: admissionv1beta1.AdmissionRequest{Operation: admissionv1beta1.Create}}
+	req.Object.Raw, _ = json.Marshal(converted)
+
+	if response := validator.Handle(context.TODO(), req); response.Allowed {
+		t.Errorf("expected the keda.sh/v1 ScaledObject over the namespace limit to be denied, got %v", response.Result)
+	}
+}
@@ -0,0 +1,27 @@
+package controllers
+
+import (
+	"fmt"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+)
+
+// validateThresholdTypes returns an error if the thresholdType of a trigger of a ScaledObject isn't supported by its metric
+func validateThresholdTypes(triggers []kedav1alpha1.ScaleTriggers) error {
+	for i := range triggers {
+		if err := triggers[i].ValidateThresholdType(); err != nil {
+			return fmt.Errorf("trigger #%d: %s", i, err)
+		}
+	}
+	return nil
+}
+
+// validateScaledJobThresholdTypes returns an error if a trigger of a ScaledJob sets a thresholdType, the ScaledJobs don't have an HPA
+func validateScaledJobThresholdTypes(triggers []kedav1alpha1.ScaleTriggers) error {
+	for i, trigger := range triggers {
+		if trigger.ThresholdType != "" {
+			return fmt.Errorf("trigger #%d: thresholdType is only supported by the triggers of ScaledObjects", i)
+		}
+	}
+	return nil
+}
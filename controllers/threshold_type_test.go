@@ -0,0 +1,37 @@
+package controllers
+
+import (
+	"testing"
+
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+)
+
+func TestValidateThresholdTypes(t *testing.T) {
+	tests := []struct {
+		name          string
+		trigger       kedav1alpha1.ScaleTriggers
+		isError       bool
+		isScaledJobOK bool
+	}{
+		{"no thresholdType", kedav1alpha1.ScaleTriggers{Type: "kafka"}, false, true},
+		{"Value", kedav1alpha1.ScaleTriggers{Type: "kafka", ThresholdType: autoscalingv2beta2.ValueMetricType}, false, false},
+		{"AverageValue", kedav1alpha1.ScaleTriggers{Type: "kafka", ThresholdType: autoscalingv2beta2.AverageValueMetricType}, false, false},
+		{"Utilization of an external metric", kedav1alpha1.ScaleTriggers{Type: "kafka", ThresholdType: autoscalingv2beta2.UtilizationMetricType}, true, false},
+		{"Utilization of cpu", kedav1alpha1.ScaleTriggers{Type: "cpu", ThresholdType: autoscalingv2beta2.UtilizationMetricType}, false, false},
+		{"AverageValue of memory", kedav1alpha1.ScaleTriggers{Type: "memory", ThresholdType: autoscalingv2beta2.AverageValueMetricType, Metadata: map[string]string{"type": "AverageValue"}}, false, false},
+		{"Value of cpu", kedav1alpha1.ScaleTriggers{Type: "cpu", ThresholdType: autoscalingv2beta2.ValueMetricType}, true, false},
+		{"thresholdType not matching the type of cpu", kedav1alpha1.ScaleTriggers{Type: "cpu", ThresholdType: autoscalingv2beta2.AverageValueMetricType, Metadata: map[string]string{"type": "Utilization"}}, true, false},
+	}
+
+	for _, test := range tests {
+		triggers := []kedav1alpha1.ScaleTriggers{{Type: "cron"}, test.trigger}
+		if err := validateThresholdTypes(triggers); (err != nil) != test.isError {
+			t.Errorf("%s: expected error %t, got %v", test.name, test.isError, err)
+		}
+		if err := validateScaledJobThresholdTypes(triggers); (err == nil) != test.isScaledJobOK {
+			t.Errorf("%s: expected ScaledJob error %t, got %v", test.name, !test.isScaledJobOK, err)
+		}
+	}
+}
@@ -0,0 +1,19 @@
+package util
+
+import (
+	"reflect"
+
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// LabelsChangedPredicate accepts the updates changing the labels of the object, they don't change its generation
+// but may make it match or stop matching the watchLabelSelector of the global configuration
+var LabelsChangedPredicate = predicate.Funcs{
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		if e.MetaOld == nil || e.MetaNew == nil {
+			return false
+		}
+		return !reflect.DeepEqual(e.MetaOld.GetLabels(), e.MetaNew.GetLabels())
+	},
+}
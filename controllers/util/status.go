@@ -23,6 +23,9 @@ func SetStatusConditions(client runtimeclient.Client, logger logr.Logger, object
 	case *kedav1alpha1.ScaledJob:
 		patch = runtimeclient.MergeFrom(obj.DeepCopy())
 		obj.Status.Conditions = *conditions
+	case *kedav1alpha1.HTTPScaledObject:
+		patch = runtimeclient.MergeFrom(obj.DeepCopy())
+		obj.Status.Conditions = *conditions
 	default:
 		err := fmt.Errorf("Unknown scalable object type %v", obj)
 		logger.Error(err, "Failed to patch Objects Status with Conditions")
@@ -46,3 +49,14 @@ func UpdateScaledObjectStatus(client runtimeclient.Client, logger logr.Logger, s
 	}
 	return err
 }
+
+// UpdateScaledJobStatus patches the given ScaledJob with the updated status passed to it or returns an error.
+func UpdateScaledJobStatus(client runtimeclient.Client, logger logr.Logger, scaledJob *kedav1alpha1.ScaledJob, status *kedav1alpha1.ScaledJobStatus) error {
+	patch := runtimeclient.MergeFrom(scaledJob.DeepCopy())
+	scaledJob.Status = *status
+	err := client.Status().Patch(context.TODO(), scaledJob, patch)
+	if err != nil {
+		logger.Error(err, "Failed to patch ScaledJobs Status")
+	}
+	return err
+}
@@ -0,0 +1,385 @@
+/*
+Copyright 2020 The KEDA Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// kubectl-keda is a kubectl plugin, installed on the PATH it is run as `kubectl keda`
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/transport/spdy"
+	"sigs.k8s.io/yaml"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+	"github.com/kedacore/keda/pkg/debugserver"
+	"github.com/kedacore/keda/pkg/generated/clientset/versioned"
+	"github.com/kedacore/keda/pkg/scaling"
+)
+
+const usage = `Usage: kubectl keda <command> [flags]
+
+Commands:
+  test-trigger        Check a trigger once and print its metric and activity, nothing is scaled
+  recover-finalizers  Remove the KEDA finalizer of the ScaledObjects stuck in deletion
+
+Run 'kubectl keda <command> -h' for the flags of a command.
+`
+
+// scaledObjectFinalizer is the finalizer the KEDA operator adds to the ScaledObjects
+const scaledObjectFinalizer = "finalizer.keda.sh"
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "test-trigger":
+		err = testTrigger(os.Args[2:], os.Stdout)
+	case "recover-finalizers":
+		err = recoverFinalizers(os.Args[2:], os.Stdout)
+	case "-h", "--help", "help":
+		fmt.Fprint(os.Stdout, usage)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %s\n\n%s", os.Args[1], usage)
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+func testTrigger(args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("test-trigger", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), "Usage: kubectl keda test-trigger -f <trigger file> [flags]\n\n"+
+			"The trigger file holds a single trigger of a ScaledObject in YAML or JSON:\n"+
+			"  type: azure-log-analytics\n  metadata:\n    ...\n  authenticationRef:\n    name: ...\n\n"+
+			"The operator must run with --debug-addr set to a loopback address with the operator port, e.g. 127.0.0.1:9666.\n"+
+			"The port of the operator pod is forwarded, so the pods/portforward permission is needed in the operator namespace.\n\nFlags:\n")
+		flags.PrintDefaults()
+	}
+	kubeconfig := flags.String("kubeconfig", "", "Path to the kubeconfig file, the default loading rules of kubectl apply if not set.")
+	kubeContext := flags.String("context", "", "The kubeconfig context to use.")
+	namespace := flags.String("namespace", "", "The namespace the trigger and its TriggerAuthentication are evaluated in, the namespace of the context if not set.")
+	flags.StringVar(namespace, "n", "", "Shorthand for --namespace.")
+	file := flags.String("f", "", "The file holding the trigger, - reads it from stdin.")
+	scaledObjectName := flags.String("scaledobject", "", "The ScaledObject whose scale target environment is used to resolve the trigger metadata.")
+	operatorNamespace := flags.String("operator-namespace", "keda", "The namespace of the KEDA operator.")
+	operatorSelector := flags.String("operator-selector", "app=keda-operator", "The label selector of the KEDA operator pods.")
+	operatorPort := flags.Int("operator-port", 9666, "The port the debug endpoint of the KEDA operator listens on.")
+	timeout := flags.Duration("timeout", 60*time.Second, "The time to wait for the evaluation of the trigger.")
+	_ = flags.Parse(args)
+
+	if *file == "" {
+		flags.Usage()
+		return fmt.Errorf("no trigger file given")
+	}
+	trigger, err := readTrigger(*file)
+	if err != nil {
+		return err
+	}
+
+	restConfig, err := loadConfig(*kubeconfig, *kubeContext, namespace)
+	if err != nil {
+		return err
+	}
+	clientset, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return fmt.Errorf("error creating kubernetes client: %s", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	podName, err := findOperatorPod(ctx, clientset, *operatorNamespace, *operatorSelector)
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(debugserver.EvaluateTriggerRequest{
+		Namespace:        *namespace,
+		ScaledObjectName: *scaledObjectName,
+		Trigger:          trigger,
+	})
+	if err != nil {
+		return err
+	}
+
+	// the debug endpoint only listens on the loopback interface of the operator pod, it is reached through a port-forward
+	stop := make(chan struct{})
+	defer close(stop)
+	localPort, err := forwardOperatorPort(restConfig, clientset, *operatorNamespace, podName, *operatorPort, stop)
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("http://127.0.0.1:%d%s", localPort, debugserver.EvaluateTriggerPath), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	httpResponse, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return fmt.Errorf("error calling the debug endpoint of the KEDA operator, is it started with --debug-addr? %s", err)
+	}
+	defer httpResponse.Body.Close()
+	raw, err := ioutil.ReadAll(httpResponse.Body)
+	if err != nil {
+		return fmt.Errorf("error reading the response of the KEDA operator: %s", err)
+	}
+
+	response := debugserver.EvaluateTriggerResponse{}
+	if err := json.Unmarshal(raw, &response); err != nil || (response.Evaluation == nil && response.Error == "") {
+		return fmt.Errorf("unexpected response of the KEDA operator: %s", string(raw))
+	}
+	if response.Error != "" {
+		return fmt.Errorf("error evaluating trigger: %s", response.Error)
+	}
+
+	printEvaluation(out, response.Evaluation)
+	return nil
+}
+
+func recoverFinalizers(args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("recover-finalizers", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), "Usage: kubectl keda recover-finalizers [flags]\n\n"+
+			"Removes the KEDA finalizer of the ScaledObjects deleted longer ago than --stuck-after, when the finalizer\n"+
+			"can't run because the KEDA operator is gone or keeps failing. The scale target keeps its current replica\n"+
+			"count. The HPA of the ScaledObject is deleted by the garbage collector, or by the sweep of the orphaned\n"+
+			"HPAs of the operator if it doesn't belong to the ScaledObject anymore.\n\nFlags:\n")
+		flags.PrintDefaults()
+	}
+	kubeconfig := flags.String("kubeconfig", "", "Path to the kubeconfig file, the default loading rules of kubectl apply if not set.")
+	kubeContext := flags.String("context", "", "The kubeconfig context to use.")
+	namespace := flags.String("namespace", "", "The namespace of the ScaledObjects, the namespace of the context if not set.")
+	flags.StringVar(namespace, "n", "", "Shorthand for --namespace.")
+	allNamespaces := flags.Bool("all-namespaces", false, "Recover the ScaledObjects of all the namespaces.")
+	flags.BoolVar(allNamespaces, "A", false, "Shorthand for --all-namespaces.")
+	stuckAfter := flags.Duration("stuck-after", 10*time.Minute, "The time since the deletion of a ScaledObject after which its finalizer is removed.")
+	dryRun := flags.Bool("dry-run", false, "Only print the ScaledObjects whose finalizer would be removed.")
+	timeout := flags.Duration("timeout", 60*time.Second, "The time to wait for the recovery of the ScaledObjects.")
+	_ = flags.Parse(args)
+
+	restConfig, err := loadConfig(*kubeconfig, *kubeContext, namespace)
+	if err != nil {
+		return err
+	}
+	if *allNamespaces {
+		*namespace = metav1.NamespaceAll
+	}
+	kedaClient, err := versioned.NewForConfig(restConfig)
+	if err != nil {
+		return fmt.Errorf("error creating KEDA client: %s", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	scaledObjects, err := kedaClient.KedaV1alpha1().ScaledObjects(*namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("error listing ScaledObjects: %s", err)
+	}
+	stuck, failed := 0, 0
+	for i := range scaledObjects.Items {
+		scaledObject := &scaledObjects.Items[i]
+		if !isStuckInDeletion(scaledObject, *stuckAfter, time.Now()) {
+			continue
+		}
+		deletedFor := time.Since(scaledObject.DeletionTimestamp.Time).Round(time.Second)
+		stuck++
+		if *dryRun {
+			fmt.Fprintf(out, "ScaledObject %s/%s deleted %s ago, its finalizer would be removed\n", scaledObject.Namespace, scaledObject.Name, deletedFor)
+			continue
+		}
+
+		finalizers := []string{}
+		for _, finalizer := range scaledObject.Finalizers {
+			if finalizer != scaledObjectFinalizer {
+				finalizers = append(finalizers, finalizer)
+			}
+		}
+		scaledObject.Finalizers = finalizers
+		if _, err := kedaClient.KedaV1alpha1().ScaledObjects(scaledObject.Namespace).Update(ctx, scaledObject, metav1.UpdateOptions{}); err != nil {
+			fmt.Fprintf(out, "ScaledObject %s/%s deleted %s ago, failed to remove its finalizer: %s\n", scaledObject.Namespace, scaledObject.Name, deletedFor, err)
+			failed++
+			continue
+		}
+		fmt.Fprintf(out, "ScaledObject %s/%s deleted %s ago, removed its finalizer\n", scaledObject.Namespace, scaledObject.Name, deletedFor)
+	}
+
+	if stuck == 0 {
+		fmt.Fprintln(out, "No ScaledObject stuck in deletion found")
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to remove the finalizer of %d ScaledObjects", failed)
+	}
+	return nil
+}
+
+// isStuckInDeletion returns true if the ScaledObject was deleted longer ago than stuckAfter and still has the KEDA finalizer
+func isStuckInDeletion(scaledObject *kedav1alpha1.ScaledObject, stuckAfter time.Duration, now time.Time) bool {
+	if scaledObject.DeletionTimestamp == nil || now.Sub(scaledObject.DeletionTimestamp.Time) < stuckAfter {
+		return false
+	}
+	for _, finalizer := range scaledObject.Finalizers {
+		if finalizer == scaledObjectFinalizer {
+			return true
+		}
+	}
+	return false
+}
+
+// loadConfig loads the kubeconfig of the context, the namespace is set to the namespace of the context if empty
+func loadConfig(kubeconfig, kubeContext string, namespace *string) (*rest.Config, error) {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = kubeconfig
+	clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, &clientcmd.ConfigOverrides{CurrentContext: kubeContext})
+	if *namespace == "" {
+		var err error
+		if *namespace, _, err = clientConfig.Namespace(); err != nil {
+			return nil, fmt.Errorf("error getting the namespace of the context: %s", err)
+		}
+	}
+	restConfig, err := clientConfig.ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("error loading kubeconfig: %s", err)
+	}
+	return restConfig, nil
+}
+
+// readTrigger reads a trigger from a YAML or JSON file
+func readTrigger(file string) (kedav1alpha1.ScaleTriggers, error) {
+	trigger := kedav1alpha1.ScaleTriggers{}
+	var content []byte
+	var err error
+	if file == "-" {
+		content, err = ioutil.ReadAll(os.Stdin)
+	} else {
+		content, err = ioutil.ReadFile(file)
+	}
+	if err != nil {
+		return trigger, fmt.Errorf("error reading trigger file: %s", err)
+	}
+	if err = yaml.UnmarshalStrict(content, &trigger); err != nil {
+		return trigger, fmt.Errorf("error parsing trigger file: %s", err)
+	}
+	if trigger.Type == "" {
+		return trigger, fmt.Errorf("no trigger type given in %s", file)
+	}
+	return trigger, nil
+}
+
+// forwardOperatorPort forwards a random local port to the port of the operator pod until the stop channel is closed, it returns the local port
+func forwardOperatorPort(restConfig *rest.Config, clientset kubernetes.Interface, namespace, podName string, port int, stop chan struct{}) (uint16, error) {
+	transport, upgrader, err := spdy.RoundTripperFor(restConfig)
+	if err != nil {
+		return 0, fmt.Errorf("error creating the port-forward transport: %s", err)
+	}
+	url := clientset.CoreV1().RESTClient().Post().
+		Namespace(namespace).
+		Resource("pods").
+		Name(podName).
+		SubResource("portforward").
+		URL()
+	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, http.MethodPost, url)
+
+	ready := make(chan struct{})
+	forwarder, err := portforward.NewOnAddresses(dialer, []string{"127.0.0.1"}, []string{fmt.Sprintf(":%d", port)}, stop, ready, ioutil.Discard, ioutil.Discard)
+	if err != nil {
+		return 0, fmt.Errorf("error forwarding the port of the KEDA operator: %s", err)
+	}
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- forwarder.ForwardPorts()
+	}()
+	select {
+	case err := <-errCh:
+		return 0, fmt.Errorf("error forwarding the port of the KEDA operator pod %s: %s", podName, err)
+	case <-ready:
+	}
+
+	ports, err := forwarder.GetPorts()
+	if err != nil || len(ports) == 0 {
+		return 0, fmt.Errorf("error getting the forwarded port of the KEDA operator: %v", err)
+	}
+	return ports[0].Local, nil
+}
+
+// findOperatorPod returns the name of a running KEDA operator pod
+func findOperatorPod(ctx context.Context, clientset kubernetes.Interface, namespace, selector string) (string, error) {
+	pods, err := clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return "", fmt.Errorf("error listing KEDA operator pods: %s", err)
+	}
+	for _, pod := range pods.Items {
+		if pod.Status.Phase == corev1.PodRunning && pod.DeletionTimestamp == nil {
+			return pod.Name, nil
+		}
+	}
+	return "", fmt.Errorf("no running KEDA operator pod found in namespace %s with selector %s", namespace, selector)
+}
+
+func printEvaluation(out io.Writer, evaluation *scaling.TriggerEvaluation) {
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(w, "Type:\t%s\n", evaluation.Type)
+	fmt.Fprintf(w, "Metadata:\t\n")
+	keys := make([]string, 0, len(evaluation.Metadata))
+	for key := range evaluation.Metadata {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Fprintf(w, "  %s:\t%s\n", key, evaluation.Metadata[key])
+	}
+	if len(evaluation.AuthParameters) > 0 {
+		fmt.Fprintf(w, "Auth Parameters:\t%s\n", strings.Join(evaluation.AuthParameters, ", "))
+	}
+	if evaluation.PodIdentity != "" {
+		fmt.Fprintf(w, "Pod Identity:\t%s\n", evaluation.PodIdentity)
+	}
+	fmt.Fprintf(w, "Metric Name:\t%s\n", evaluation.MetricName)
+	fmt.Fprintf(w, "Metric Value:\t%d\n", evaluation.MetricValue)
+	fmt.Fprintf(w, "Threshold:\t%d\n", evaluation.Threshold)
+	fmt.Fprintf(w, "Active:\t%t\n", evaluation.Active)
+	if evaluation.Error != "" {
+		fmt.Fprintf(w, "Error:\t%s\n", evaluation.Error)
+	}
+	w.Flush()
+}
@@ -21,17 +21,30 @@ import (
 	"fmt"
 	"os"
 	"runtime"
+	"time"
 
+	uberzap "go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 	apimachineryruntime "k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/discovery"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
-	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
 	"github.com/kedacore/keda/controllers"
+	"github.com/kedacore/keda/pkg/audit"
+	"github.com/kedacore/keda/pkg/debugserver"
+	"github.com/kedacore/keda/pkg/embedded"
+	"github.com/kedacore/keda/pkg/globalconfig"
+	prommetrics "github.com/kedacore/keda/pkg/metrics"
+	"github.com/kedacore/keda/pkg/scaling"
+	"github.com/kedacore/keda/pkg/tracing"
+	kedautil "github.com/kedacore/keda/pkg/util"
+	"github.com/kedacore/keda/pkg/webhookreceiver"
 	"github.com/kedacore/keda/version"
 	// +kubebuilder:scaffold:imports
 )
@@ -43,17 +56,57 @@ var (
 func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 
-	utilruntime.Must(kedav1alpha1.AddToScheme(scheme))
+	utilruntime.Must(embedded.AddToScheme(scheme))
 	// +kubebuilder:scaffold:scheme
 }
 
 func main() {
+	// the errors are logged by run, the deferred shutdowns have run when it returns
+	if err := run(); err != nil {
+		os.Exit(1)
+	}
+}
+
+// run starts the operator and returns once the manager is stopped, the telemetry is flushed before it returns
+func run() error {
 	var metricsAddr string
 	var enableLeaderElection bool
+	var debugAddr string
+	var webhookReceiverAddr string
+	var enableConversionWebhook bool
+	var globalConfigMap string
+	var leaderElectionID string
+	tracingConfig := tracing.Config{ServiceName: "keda-operator"}
+	otlpMetricsConfig := prommetrics.OTLPConfig{ServiceName: "keda-operator"}
+	auditConfig := audit.Config{Component: "keda-operator"}
+	triggerEvaluationConfig := scaling.TriggerEvaluationConfig{}
+	scalerConcurrencyConfig := scaling.ScalerConcurrencyConfig{}
+	orphanedHPASweepConfig := controllers.OrphanedHPASweepConfig{}
+	var scalerConcurrencyLimits string
 	flag.StringVar(&metricsAddr, "metrics-addr", ":8080", "The address the metric endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "enable-leader-election", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	flag.StringVar(&tracingConfig.Endpoint, "otlp-endpoint", "", "The address of the OTLP collector the traces are exported to. Tracing is disabled if not set.")
+	flag.BoolVar(&tracingConfig.Insecure, "otlp-insecure", false, "Disable the transport security of the connection to the OTLP collector.")
+	flag.Float64Var(&tracingConfig.SampleRatio, "trace-sample-ratio", 1, "The fraction of the traces that are sampled, between 0 and 1.")
+	flag.StringVar(&otlpMetricsConfig.Endpoint, "otlp-metrics-endpoint", "", "The address of the OTLP collector the metrics are pushed to. The metrics are only served to Prometheus if not set.")
+	flag.BoolVar(&otlpMetricsConfig.Insecure, "otlp-metrics-insecure", false, "Disable the transport security of the connection to the OTLP metrics collector.")
+	flag.DurationVar(&otlpMetricsConfig.Period, "otlp-metrics-period", 30*time.Second, "The interval the metrics are pushed to the OTLP collector at.")
+	flag.StringVar(&auditConfig.Sink, "audit-log", "", "The sink the scaling decisions are logged to: stdout, file:<path> or configmap:<namespace>/<name>. The audit log is disabled if not set.")
+	flag.IntVar(&auditConfig.MaxEntries, "audit-log-max-entries", 500, "The number of the latest scaling decisions kept in a ConfigMap audit log.")
+	flag.IntVar(&triggerEvaluationConfig.Concurrency, "trigger-evaluation-concurrency", 4, "The number of triggers of a ScaledObject evaluated at the same time.")
+	flag.DurationVar(&triggerEvaluationConfig.Timeout, "trigger-evaluation-timeout", 0, "The time limit of the evaluation of a single trigger. The polling interval of the ScaledObject is used if not set.")
+	flag.IntVar(&scalerConcurrencyConfig.Limit, "scaler-concurrency-limit", 0, "The number of calls of the scalers of a trigger type made at the same time, shared by the operator and the metrics adapters. The calls aren't limited if not set.")
+	flag.StringVar(&scalerConcurrencyLimits, "scaler-concurrency-limits", "", "The concurrency limits of trigger types overriding scaler-concurrency-limit, as comma separated <trigger type>=<limit> pairs, e.g. azure-log-analytics=10.")
+	flag.StringVar(&scalerConcurrencyConfig.Namespace, "scaler-concurrency-namespace", os.Getenv("POD_NAMESPACE"), "The namespace of the Leases the operator and the metrics adapters share the scaler concurrency limits through, the namespace of the pod by default. Each process has the whole limits if empty.")
+	flag.StringVar(&leaderElectionID, "leader-election-id", "operator.keda.sh", "The name of the leader election lock, each installation of KEDA sharing a namespace needs its own.")
+	flag.StringVar(&globalConfigMap, "global-config", "", "The ConfigMap holding the global configuration, as <namespace>/<name>. Its changes are applied without restarting. The defaults are used if not set.")
+	flag.StringVar(&debugAddr, "debug-addr", "", "The loopback address the debug endpoint used by the kubectl keda plugin binds to, e.g. 127.0.0.1:9666. The plugin reaches it through a port-forward. The debug endpoint is disabled if not set.")
+	flag.StringVar(&webhookReceiverAddr, "webhook-receiver-addr", "", "The address the receiver of the webhooks activating the webhook triggers binds to. The webhook receiver is disabled if not set.")
+	flag.DurationVar(&orphanedHPASweepConfig.Interval, "orphaned-hpa-sweep-interval", 10*time.Minute, "The interval the HPAs managed by KEDA whose ScaledObject is gone are deleted at. The HPAs aren't swept if set to 0.")
+	flag.BoolVar(&orphanedHPASweepConfig.DryRun, "orphaned-hpa-sweep-dry-run", false, "Only report the orphaned HPAs found by the sweep in the logs and the events, they aren't deleted.")
+	flag.BoolVar(&enableConversionWebhook, "enable-conversion-webhook", false, "Serve the conversion webhook of the ScaledObjects between keda.sh/v1alpha1 and keda.sh/v1, and the validating webhooks of the ScaledObjects and ScaledJobs, on port 9443. The certificate is read from /tmp/k8s-webhook-server/serving-certs.")
 
 	// Add the zap logger flag set to the CLI.
 	opts := zap.Options{}
@@ -61,54 +114,124 @@ func main() {
 
 	flag.Parse()
 
+	// the level set by the flags can be changed by the global configuration
+	logLevel, ok := opts.Level.(uberzap.AtomicLevel)
+	if !ok {
+		logLevel = uberzap.NewAtomicLevelAt(zapcore.InfoLevel)
+		if opts.Development {
+			logLevel.SetLevel(zapcore.DebugLevel)
+		}
+		opts.Level = logLevel
+	}
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 	setupLog := ctrl.Log.WithName("setup")
 
-	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
+	limits, err := scaling.ParseScalerConcurrencyLimits(scalerConcurrencyLimits)
+	if err != nil {
+		setupLog.Error(err, "invalid scaler concurrency limits")
+		return err
+	}
+	scalerConcurrencyConfig.Limits = limits
+	scalerConcurrencyConfig.Identity = os.Getenv("POD_NAME")
+
+	globalconfig.BindLogLevel(logLevel)
+	globalconfig.BindHTTPTimeout()
+	stopGlobalConfig, err := globalconfig.Start(globalConfigMap, ctrl.GetConfigOrDie())
+	if err != nil {
+		setupLog.Error(err, "unable to set up global configuration")
+		return err
+	}
+	defer stopGlobalConfig()
+
+	shutdownTracing, err := tracing.Init(tracingConfig)
+	if err != nil {
+		setupLog.Error(err, "unable to set up tracing")
+		return err
+	}
+	defer shutdownTracing()
+
+	shutdownOTLPMetrics, err := prommetrics.InitOTLP(otlpMetricsConfig)
+	if err != nil {
+		setupLog.Error(err, "unable to set up the OTLP metrics exporter")
+		return err
+	}
+	defer shutdownOTLPMetrics()
+
+	shutdownAudit, err := audit.Init(auditConfig, ctrl.GetConfigOrDie())
+	if err != nil {
+		setupLog.Error(err, "unable to set up audit log")
+		return err
+	}
+	defer shutdownAudit()
+
+	options := ctrl.Options{
 		Scheme:                 scheme,
 		MetricsBindAddress:     metricsAddr,
 		HealthProbeBindAddress: ":8081",
 		Port:                   9443,
 		LeaderElection:         enableLeaderElection,
-		LeaderElectionID:       "operator.keda.sh",
-	})
+		LeaderElectionID:       leaderElectionID,
+	}
+	// the objects of the namespaces not watched aren't even cached, so the operator only needs access to the watched namespaces
+	watchNamespaces := kedautil.ParseWatchNamespaces(os.Getenv(kedautil.WatchNamespaceEnvVar))
+	if len(watchNamespaces) == 1 {
+		options.Namespace = watchNamespaces[0]
+	} else if len(watchNamespaces) > 1 {
+		options.NewCache = cache.MultiNamespacedCacheBuilder(watchNamespaces)
+	}
+	setupLog.Info("Watching namespaces", "namespaces", watchNamespaces)
+
+	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), options)
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
-		os.Exit(1)
+		return err
 	}
 
 	// Add readiness probe
 	err = mgr.AddReadyzCheck("ready-ping", healthz.Ping)
 	if err != nil {
 		setupLog.Error(err, "Unable to add a readiness check")
-		os.Exit(1)
+		return err
 	}
 
 	// Add liveness probe
 	err = mgr.AddHealthzCheck("health-ping", healthz.Ping)
 	if err != nil {
 		setupLog.Error(err, "Unable to add a health check")
-		os.Exit(1)
+		return err
 	}
 
-	if err = (&controllers.ScaledObjectReconciler{
-		Client: mgr.GetClient(),
-		Log:    ctrl.Log.WithName("controllers").WithName("ScaledObject"),
-		Scheme: mgr.GetScheme(),
-	}).SetupWithManager(mgr); err != nil {
-		setupLog.Error(err, "unable to create controller", "controller", "ScaledObject")
-		os.Exit(1)
-	}
-	if err = (&controllers.ScaledJobReconciler{
-		Client: mgr.GetClient(),
-		Log:    ctrl.Log.WithName("controllers").WithName("ScaledJob"),
-		Scheme: mgr.GetScheme(),
-	}).SetupWithManager(mgr); err != nil {
-		setupLog.Error(err, "unable to create controller", "controller", "ScaledJob")
-		os.Exit(1)
+	if err = embedded.SetupWithManager(mgr, embedded.Options{TriggerEvaluation: triggerEvaluationConfig, ScalerConcurrency: scalerConcurrencyConfig, EnableConversionWebhook: enableConversionWebhook, OrphanedHPASweep: orphanedHPASweepConfig}); err != nil {
+		setupLog.Error(err, "unable to create controllers")
+		return err
 	}
 	// +kubebuilder:scaffold:builder
 
+	if debugAddr != "" {
+		clientset, err := discovery.NewDiscoveryClientForConfig(mgr.GetConfig())
+		if err != nil {
+			setupLog.Error(err, "unable to create the discovery client of the debug server")
+			return err
+		}
+		scaleClient := kedautil.NewScaleClient(clientset, mgr.GetRESTMapper())
+		debugServer, err := debugserver.NewServer(debugAddr, scaling.NewScaleHandler(mgr.GetClient(), &scaleClient, mgr.GetScheme()))
+		if err != nil {
+			setupLog.Error(err, "unable to create debug server")
+			return err
+		}
+		if err = mgr.Add(debugServer); err != nil {
+			setupLog.Error(err, "unable to add debug server")
+			return err
+		}
+	}
+
+	if webhookReceiverAddr != "" {
+		if err = mgr.Add(webhookreceiver.NewServer(webhookReceiverAddr, webhookreceiver.DefaultHub)); err != nil {
+			setupLog.Error(err, "unable to add webhook receiver")
+			return err
+		}
+	}
+
 	setupLog.Info("Starting manager")
 	setupLog.Info(fmt.Sprintf("KEDA Version: %s", version.Version))
 	setupLog.Info(fmt.Sprintf("KEDA Commit: %s", version.GitCommit))
@@ -117,6 +240,7 @@ func main() {
 
 	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
 		setupLog.Error(err, "problem running manager")
-		os.Exit(1)
+		return err
 	}
+	return nil
 }
@@ -0,0 +1,172 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// SourceOperator marks the decisions taken by the scale loop of the operator
+	SourceOperator = "operator"
+	// SourceMetricsServer marks the metric values served to the HPA by the metrics server
+	SourceMetricsServer = "metrics-server"
+
+	stdoutSinkName      = "stdout"
+	fileSinkPrefix      = "file:"
+	configMapSinkPrefix = "configmap:"
+
+	defaultMaxEntries = 500
+)
+
+// Decision is a single entry of the audit log
+type Decision struct {
+	Timestamp time.Time `json:"timestamp"`
+	Source    string    `json:"source"`
+	Kind      string    `json:"kind"`
+	Namespace string    `json:"namespace"`
+	Name      string    `json:"name"`
+	// Trigger is the type of the scaler the decision is based on, empty if all the triggers are involved
+	Trigger      string `json:"trigger,omitempty"`
+	TriggerIndex *int   `json:"triggerIndex,omitempty"`
+	Metric       string `json:"metric,omitempty"`
+	MetricValue  *int64 `json:"metricValue,omitempty"`
+	Threshold    *int64 `json:"threshold,omitempty"`
+	Active       *bool  `json:"active,omitempty"`
+	// DesiredReplicas is the replica count resulting from the decision,
+	// for the metrics server it is the count the HPA computes for the metric before applying the replica bounds
+	DesiredReplicas *int64 `json:"desiredReplicas,omitempty"`
+	Error           string `json:"error,omitempty"`
+}
+
+// Config holds the configuration of the sink the audit log is written to
+type Config struct {
+	// Sink is stdout, file:<path> or configmap:<namespace>/<name>, the audit log is disabled if empty
+	Sink string
+	// MaxEntries is the number of the latest entries kept in a ConfigMap sink
+	MaxEntries int
+	// Component is the process writing the decisions, a ConfigMap sink keeps its entries under <component>.decisions.jsonl
+	// so the operator and the metrics server can share the ConfigMap
+	Component string
+}
+
+type sink interface {
+	write(line []byte) error
+	close() error
+}
+
+var (
+	auditLog = logf.Log.WithName("audit")
+
+	mutex      sync.Mutex
+	activeSink sink
+)
+
+// Init configures the sink the decisions are written to, the restConfig is only used for a ConfigMap sink.
+// The returned function flushes and closes the sink.
+func Init(config Config, restConfig *rest.Config) (func(), error) {
+	s, err := newSink(config, restConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	mutex.Lock()
+	activeSink = s
+	mutex.Unlock()
+
+	return func() {
+		mutex.Lock()
+		defer mutex.Unlock()
+		if activeSink != nil {
+			if err := activeSink.close(); err != nil {
+				auditLog.Error(err, "error closing audit log sink")
+			}
+			activeSink = nil
+		}
+	}, nil
+}
+
+func newSink(config Config, restConfig *rest.Config) (sink, error) {
+	switch {
+	case config.Sink == "":
+		return nil, nil
+	case config.Sink == stdoutSinkName:
+		return &writerSink{writer: os.Stdout}, nil
+	case strings.HasPrefix(config.Sink, fileSinkPrefix):
+		path := strings.TrimPrefix(config.Sink, fileSinkPrefix)
+		if path == "" {
+			return nil, fmt.Errorf("no path given for audit log file sink")
+		}
+		file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		if err != nil {
+			return nil, fmt.Errorf("error opening audit log file: %s", err)
+		}
+		return &writerSink{writer: file, closer: file}, nil
+	case strings.HasPrefix(config.Sink, configMapSinkPrefix):
+		parts := strings.Split(strings.TrimPrefix(config.Sink, configMapSinkPrefix), "/")
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("audit log ConfigMap sink must be in the form configmap:<namespace>/<name>")
+		}
+		maxEntries := config.MaxEntries
+		if maxEntries <= 0 {
+			maxEntries = defaultMaxEntries
+		}
+		return newConfigMapSink(restConfig, types.NamespacedName{Namespace: parts[0], Name: parts[1]}, config.Component, maxEntries)
+	default:
+		return nil, fmt.Errorf("unknown audit log sink %s, supported sinks are stdout, file:<path> and configmap:<namespace>/<name>", config.Sink)
+	}
+}
+
+// Enabled returns true if the decisions are written to a sink
+func Enabled() bool {
+	mutex.Lock()
+	defer mutex.Unlock()
+	return activeSink != nil
+}
+
+// Record writes the decision as a JSON line to the configured sink, the timestamp is set if missing
+func Record(decision Decision) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	if activeSink == nil {
+		return
+	}
+
+	if decision.Timestamp.IsZero() {
+		decision.Timestamp = time.Now().UTC()
+	}
+	line, err := json.Marshal(decision)
+	if err != nil {
+		auditLog.Error(err, "error encoding audit log entry")
+		return
+	}
+	if err = activeSink.write(line); err != nil {
+		auditLog.Error(err, "error writing audit log entry")
+	}
+}
+
+// writerSink writes a JSON line per decision to stdout or to a file
+type writerSink struct {
+	writer io.Writer
+	closer io.Closer
+}
+
+func (s *writerSink) write(line []byte) error {
+	_, err := s.writer.Write(append(line, '\n'))
+	return err
+}
+
+func (s *writerSink) close() error {
+	if s.closer == nil {
+		return nil
+	}
+	return s.closer.Close()
+}
@@ -0,0 +1,142 @@
+package audit
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var testInvalidSinks = []string{
+	"syslog",
+	"file:",
+	"configmap:",
+	"configmap:keda",
+	"configmap:keda/",
+	"configmap:/audit",
+}
+
+func TestInitInvalidSink(t *testing.T) {
+	for _, sink := range testInvalidSinks {
+		_, err := Init(Config{Sink: sink}, nil)
+		assert.Error(t, err, sink)
+	}
+}
+
+func TestRecordDisabled(t *testing.T) {
+	shutdown, err := Init(Config{}, nil)
+	assert.NoError(t, err)
+	defer shutdown()
+
+	assert.False(t, Enabled())
+	Record(Decision{Name: "test"})
+}
+
+func TestRecordToFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "audit")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "decisions.jsonl")
+
+	shutdown, err := Init(Config{Sink: "file:" + path}, nil)
+	assert.NoError(t, err)
+	assert.True(t, Enabled())
+
+	value, threshold, replicas := int64(25), int64(10), int64(3)
+	Record(Decision{Source: SourceMetricsServer, Kind: "ScaledObject", Namespace: "default", Name: "test", Trigger: "kafka", MetricValue: &value, Threshold: &threshold, DesiredReplicas: &replicas})
+	Record(Decision{Source: SourceOperator, Kind: "ScaledObject", Namespace: "default", Name: "test", Error: "unreachable"})
+	shutdown()
+	assert.False(t, Enabled())
+
+	file, err := os.Open(path)
+	assert.NoError(t, err)
+	defer file.Close()
+
+	var decisions []Decision
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		decision := Decision{}
+		assert.NoError(t, json.Unmarshal(scanner.Bytes(), &decision))
+		decisions = append(decisions, decision)
+	}
+
+	assert.Len(t, decisions, 2)
+	assert.Equal(t, "kafka", decisions[0].Trigger)
+	assert.Equal(t, int64(25), *decisions[0].MetricValue)
+	assert.Equal(t, int64(10), *decisions[0].Threshold)
+	assert.Equal(t, int64(3), *decisions[0].DesiredReplicas)
+	assert.False(t, decisions[0].Timestamp.IsZero())
+	assert.Nil(t, decisions[1].DesiredReplicas)
+	assert.Equal(t, "unreachable", decisions[1].Error)
+}
+
+func TestConfigMapSink(t *testing.T) {
+	key := types.NamespacedName{Namespace: "keda", Name: "audit"}
+	kubeClient := fake.NewFakeClientWithScheme(scheme.Scheme)
+
+	s := newConfigMapSinkWithClient(kubeClient, key, "", 2)
+	assert.NoError(t, s.write([]byte(`{"name":"a"}`)))
+	assert.NoError(t, s.write([]byte(`{"name":"b"}`)))
+	assert.NoError(t, s.write([]byte(`{"name":"c"}`)))
+	assert.NoError(t, s.flush())
+
+	configMap := &corev1.ConfigMap{}
+	assert.NoError(t, kubeClient.Get(context.TODO(), key, configMap))
+	assert.Equal(t, "{\"name\":\"b\"}\n{\"name\":\"c\"}\n", configMap.Data[configMapDataKey])
+
+	assert.NoError(t, s.write([]byte(`{"name":"d"}`)))
+	assert.NoError(t, s.flush())
+	assert.NoError(t, kubeClient.Get(context.TODO(), key, configMap))
+	assert.Equal(t, "{\"name\":\"c\"}\n{\"name\":\"d\"}\n", configMap.Data[configMapDataKey])
+}
+
+func TestConfigMapSinkKeepsExistingEntries(t *testing.T) {
+	key := types.NamespacedName{Namespace: "keda", Name: "audit"}
+	existing := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name},
+		Data:       map[string]string{configMapDataKey: "{\"name\":\"a\"}\n{\"name\":\"b\"}\n", "other": "value"},
+	}
+	kubeClient := fake.NewFakeClientWithScheme(scheme.Scheme, existing)
+
+	s := newConfigMapSinkWithClient(kubeClient, key, "", 10)
+	assert.NoError(t, s.write([]byte(`{"name":"c"}`)))
+	assert.NoError(t, s.flush())
+
+	configMap := &corev1.ConfigMap{}
+	assert.NoError(t, kubeClient.Get(context.TODO(), key, configMap))
+	assert.Equal(t, 3, len(strings.Split(strings.TrimSpace(configMap.Data[configMapDataKey]), "\n")))
+	assert.Equal(t, "value", configMap.Data["other"])
+}
+
+func TestConfigMapSinkSharedByComponents(t *testing.T) {
+	key := types.NamespacedName{Namespace: "keda", Name: "audit"}
+	kubeClient := fake.NewFakeClientWithScheme(scheme.Scheme)
+
+	operator := newConfigMapSinkWithClient(kubeClient, key, "keda-operator", 10)
+	metricsServer := newConfigMapSinkWithClient(kubeClient, key, "keda-metrics-apiserver", 10)
+	assert.NoError(t, operator.write([]byte(`{"name":"a"}`)))
+	assert.NoError(t, operator.flush())
+	assert.NoError(t, metricsServer.write([]byte(`{"name":"b"}`)))
+	assert.NoError(t, metricsServer.flush())
+
+	configMap := &corev1.ConfigMap{}
+	assert.NoError(t, kubeClient.Get(context.TODO(), key, configMap))
+	assert.Equal(t, "{\"name\":\"a\"}\n", configMap.Data["keda-operator.decisions.jsonl"])
+	assert.Equal(t, "{\"name\":\"b\"}\n", configMap.Data["keda-metrics-apiserver.decisions.jsonl"])
+	assert.NotContains(t, configMap.Data, configMapDataKey)
+
+	// the entries of a component are read back after a restart
+	restarted := newConfigMapSinkWithClient(kubeClient, key, "keda-operator", 10)
+	assert.Equal(t, []string{`{"name":"a"}`}, restarted.entries)
+}
@@ -0,0 +1,157 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	configMapDataKey       = "decisions.jsonl"
+	configMapFlushInterval = 10 * time.Second
+)
+
+// configMapDataKeyOf returns the key of the entries of the component in the ConfigMap, so the components sharing the ConfigMap
+// don't overwrite the entries of each other
+func configMapDataKeyOf(component string) string {
+	if component == "" {
+		return configMapDataKey
+	}
+	return component + "." + configMapDataKey
+}
+
+// configMapSink keeps the latest entries of the audit log in a ConfigMap,
+// the entries are buffered and the ConfigMap is updated periodically to limit the calls to the API server
+type configMapSink struct {
+	kubeClient client.Client
+	key        types.NamespacedName
+	dataKey    string
+	maxEntries int
+
+	mutex   sync.Mutex
+	entries []string
+	dirty   bool
+	stopCh  chan struct{}
+	doneCh  chan struct{}
+}
+
+func newConfigMapSink(restConfig *rest.Config, key types.NamespacedName, component string, maxEntries int) (*configMapSink, error) {
+	if restConfig == nil {
+		return nil, fmt.Errorf("no kubernetes config given for audit log ConfigMap sink")
+	}
+	kubeClient, err := client.New(restConfig, client.Options{Scheme: clientgoscheme.Scheme})
+	if err != nil {
+		return nil, fmt.Errorf("error creating kubernetes client for audit log ConfigMap sink: %s", err)
+	}
+
+	s := newConfigMapSinkWithClient(kubeClient, key, component, maxEntries)
+	go s.run()
+	return s, nil
+}
+
+func newConfigMapSinkWithClient(kubeClient client.Client, key types.NamespacedName, component string, maxEntries int) *configMapSink {
+	s := &configMapSink{
+		kubeClient: kubeClient,
+		key:        key,
+		dataKey:    configMapDataKeyOf(component),
+		maxEntries: maxEntries,
+		stopCh:     make(chan struct{}),
+		doneCh:     make(chan struct{}),
+	}
+
+	// keep the entries written before a restart
+	configMap := &corev1.ConfigMap{}
+	if err := kubeClient.Get(context.TODO(), key, configMap); err == nil {
+		if data := strings.TrimSpace(configMap.Data[s.dataKey]); data != "" {
+			s.entries = strings.Split(data, "\n")
+			s.trim()
+		}
+	} else if !errors.IsNotFound(err) {
+		auditLog.Error(err, "error reading audit log ConfigMap", "ConfigMap.Namespace", key.Namespace, "ConfigMap.Name", key.Name)
+	}
+	return s
+}
+
+func (s *configMapSink) write(line []byte) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.entries = append(s.entries, string(line))
+	s.trim()
+	s.dirty = true
+	return nil
+}
+
+func (s *configMapSink) trim() {
+	if len(s.entries) > s.maxEntries {
+		s.entries = s.entries[len(s.entries)-s.maxEntries:]
+	}
+}
+
+func (s *configMapSink) run() {
+	defer close(s.doneCh)
+	for {
+		select {
+		case <-time.After(configMapFlushInterval):
+			if err := s.flush(); err != nil {
+				auditLog.Error(err, "error updating audit log ConfigMap", "ConfigMap.Namespace", s.key.Namespace, "ConfigMap.Name", s.key.Name)
+			}
+		case <-s.stopCh:
+			return
+		}
+	}
+}
+
+func (s *configMapSink) close() error {
+	close(s.stopCh)
+	<-s.doneCh
+	return s.flush()
+}
+
+// flush writes the buffered entries to the ConfigMap, which is created if it doesn't exist
+func (s *configMapSink) flush() error {
+	s.mutex.Lock()
+	if !s.dirty {
+		s.mutex.Unlock()
+		return nil
+	}
+	data := strings.Join(s.entries, "\n") + "\n"
+	s.dirty = false
+	s.mutex.Unlock()
+
+	configMap := &corev1.ConfigMap{}
+	err := s.kubeClient.Get(context.TODO(), s.key, configMap)
+	if err != nil && errors.IsNotFound(err) {
+		configMap = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      s.key.Name,
+				Namespace: s.key.Namespace,
+			},
+			Data: map[string]string{s.dataKey: data},
+		}
+		err = s.kubeClient.Create(context.TODO(), configMap)
+	} else if err == nil {
+		if configMap.Data == nil {
+			configMap.Data = map[string]string{}
+		}
+		configMap.Data[s.dataKey] = data
+		err = s.kubeClient.Update(context.TODO(), configMap)
+	}
+
+	if err != nil {
+		// retry on the next flush
+		s.mutex.Lock()
+		s.dirty = true
+		s.mutex.Unlock()
+	}
+	return err
+}
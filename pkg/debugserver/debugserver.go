@@ -0,0 +1,145 @@
+package debugserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/go-logr/logr"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+	"github.com/kedacore/keda/pkg/scaling"
+)
+
+const (
+	// EvaluateTriggerPath is the path of the endpoint checking a trigger once
+	EvaluateTriggerPath = "/debug/v1/triggers/evaluate"
+
+	maxRequestBytes   = 1 << 20
+	evaluationTimeout = 30 * time.Second
+	shutdownTimeout   = 5 * time.Second
+)
+
+// EvaluateTriggerRequest is the body of a request to the EvaluateTriggerPath endpoint
+type EvaluateTriggerRequest struct {
+	Namespace string `json:"namespace"`
+	// ScaledObjectName is optional, the environment of the scale target of the ScaledObject is used to resolve the trigger metadata
+	ScaledObjectName string                     `json:"scaledObjectName,omitempty"`
+	Trigger          kedav1alpha1.ScaleTriggers `json:"trigger"`
+}
+
+// EvaluateTriggerResponse is the body of the response of the EvaluateTriggerPath endpoint
+type EvaluateTriggerResponse struct {
+	Evaluation *scaling.TriggerEvaluation `json:"evaluation,omitempty"`
+	Error      string                     `json:"error,omitempty"`
+}
+
+// Server serves the debug endpoints of the operator, it is added to the manager so it runs along the controllers.
+// The endpoints resolve the TriggerAuthentications of any namespace, so the server only listens on a loopback address
+// and the kubectl keda plugin reaches it through a port-forward, which needs the pods/portforward permission on the operator
+type Server struct {
+	addr         string
+	scaleHandler scaling.ScaleHandler
+	logger       logr.Logger
+}
+
+// NewServer creates a Server listening on addr, which must be a loopback address like 127.0.0.1:9666
+func NewServer(addr string, scaleHandler scaling.ScaleHandler) (*Server, error) {
+	if err := validateLoopbackAddr(addr); err != nil {
+		return nil, err
+	}
+	return &Server{
+		addr:         addr,
+		scaleHandler: scaleHandler,
+		logger:       logf.Log.WithName("debugserver"),
+	}, nil
+}
+
+// validateLoopbackAddr returns an error if the host of addr isn't a loopback address, an empty host listens on all the interfaces
+func validateLoopbackAddr(addr string) error {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return fmt.Errorf("invalid debug server address %s: %s", addr, err)
+	}
+	if host == "localhost" {
+		return nil
+	}
+	if ip := net.ParseIP(host); ip == nil || !ip.IsLoopback() {
+		return fmt.Errorf("the debug server address %s must be a loopback address, e.g. 127.0.0.1:9666", addr)
+	}
+	return nil
+}
+
+// Start serves the debug endpoints until the stop channel is closed
+func (s *Server) Start(stop <-chan struct{}) error {
+	mux := http.NewServeMux()
+	mux.Handle(EvaluateTriggerPath, s)
+	server := &http.Server{Addr: s.addr, Handler: mux}
+
+	errCh := make(chan error, 1)
+	go func() {
+		s.logger.Info("Starting debug server", "address", s.addr)
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			errCh <- err
+		}
+		close(errCh)
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-stop:
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		return server.Shutdown(ctx)
+	}
+}
+
+// NeedLeaderElection returns false, every replica of the operator serves the debug endpoints
+func (s *Server) NeedLeaderElection() bool {
+	return false
+}
+
+// ServeHTTP handles the requests to the EvaluateTriggerPath endpoint
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeResponse(w, http.StatusMethodNotAllowed, EvaluateTriggerResponse{Error: fmt.Sprintf("method %s not allowed", r.Method)})
+		return
+	}
+
+	request := EvaluateTriggerRequest{}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBytes)).Decode(&request); err != nil {
+		writeResponse(w, http.StatusBadRequest, EvaluateTriggerResponse{Error: fmt.Sprintf("error decoding request: %s", err)})
+		return
+	}
+	if request.Namespace == "" {
+		writeResponse(w, http.StatusBadRequest, EvaluateTriggerResponse{Error: "no namespace given"})
+		return
+	}
+	if request.Trigger.Type == "" {
+		writeResponse(w, http.StatusBadRequest, EvaluateTriggerResponse{Error: "no trigger type given"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), evaluationTimeout)
+	defer cancel()
+	evaluation, err := s.scaleHandler.EvaluateTrigger(ctx, request.Namespace, request.ScaledObjectName, request.Trigger)
+	if err != nil {
+		s.logger.V(1).Info("Error evaluating trigger", "namespace", request.Namespace, "type", request.Trigger.Type, "error", err.Error())
+		writeResponse(w, http.StatusUnprocessableEntity, EvaluateTriggerResponse{Error: err.Error()})
+		return
+	}
+	s.logger.V(1).Info("Evaluated trigger", "namespace", request.Namespace, "type", request.Trigger.Type, "active", evaluation.Active)
+	writeResponse(w, http.StatusOK, EvaluateTriggerResponse{Evaluation: evaluation})
+}
+
+func writeResponse(w http.ResponseWriter, status int, response EvaluateTriggerResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(response)
+}
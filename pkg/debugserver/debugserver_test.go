@@ -0,0 +1,152 @@
+package debugserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+	"github.com/kedacore/keda/pkg/scalers"
+	"github.com/kedacore/keda/pkg/scaling"
+)
+
+type fakeScaleHandler struct {
+	evaluation *scaling.TriggerEvaluation
+	err        error
+	request    EvaluateTriggerRequest
+}
+
+func (h *fakeScaleHandler) HandleScalableObject(scalableObject interface{}) error {
+	return nil
+}
+
+func (h *fakeScaleHandler) DeleteScalableObject(scalableObject interface{}) error {
+	return nil
+}
+
+func (h *fakeScaleHandler) GetScalers(scalableObject interface{}) ([]scalers.Scaler, error) {
+	return nil, nil
+}
+
+func (h *fakeScaleHandler) EvaluateTrigger(ctx context.Context, namespace, scaledObjectName string, trigger kedav1alpha1.ScaleTriggers) (*scaling.TriggerEvaluation, error) {
+	h.request = EvaluateTriggerRequest{Namespace: namespace, ScaledObjectName: scaledObjectName, Trigger: trigger}
+	return h.evaluation, h.err
+}
+
+type serveHTTPTestData struct {
+	name           string
+	method         string
+	body           string
+	evaluation     *scaling.TriggerEvaluation
+	err            error
+	expectedStatus int
+	expectedError  string
+}
+
+var serveHTTPTestDataset = []serveHTTPTestData{
+	{
+		name:           "evaluated trigger",
+		method:         http.MethodPost,
+		body:           `{"namespace":"default","scaledObjectName":"app","trigger":{"type":"cron","metadata":{"timezone":"UTC"}}}`,
+		evaluation:     &scaling.TriggerEvaluation{Type: "cron", MetricName: "cron-UTC", Threshold: 1, MetricValue: 1, Active: true},
+		expectedStatus: http.StatusOK,
+	},
+	{
+		name:           "wrong method",
+		method:         http.MethodGet,
+		expectedStatus: http.StatusMethodNotAllowed,
+		expectedError:  "method GET not allowed",
+	},
+	{
+		name:           "invalid body",
+		method:         http.MethodPost,
+		body:           `{"namespace":`,
+		expectedStatus: http.StatusBadRequest,
+		expectedError:  "error decoding request",
+	},
+	{
+		name:           "missing namespace",
+		method:         http.MethodPost,
+		body:           `{"trigger":{"type":"cron"}}`,
+		expectedStatus: http.StatusBadRequest,
+		expectedError:  "no namespace given",
+	},
+	{
+		name:           "missing trigger type",
+		method:         http.MethodPost,
+		body:           `{"namespace":"default","trigger":{"metadata":{}}}`,
+		expectedStatus: http.StatusBadRequest,
+		expectedError:  "no trigger type given",
+	},
+	{
+		name:           "scaler not built",
+		method:         http.MethodPost,
+		body:           `{"namespace":"default","trigger":{"type":"unknown"}}`,
+		err:            fmt.Errorf("no scaler found for type: unknown"),
+		expectedStatus: http.StatusUnprocessableEntity,
+		expectedError:  "no scaler found for type: unknown",
+	},
+}
+
+func TestServeHTTP(t *testing.T) {
+	for _, testData := range serveHTTPTestDataset {
+		handler := &fakeScaleHandler{evaluation: testData.evaluation, err: testData.err}
+		server, err := NewServer("127.0.0.1:0", handler)
+		if err != nil {
+			t.Fatalf("%s: unexpected error %s", testData.name, err)
+		}
+
+		recorder := httptest.NewRecorder()
+		server.ServeHTTP(recorder, httptest.NewRequest(testData.method, EvaluateTriggerPath, strings.NewReader(testData.body)))
+
+		if recorder.Code != testData.expectedStatus {
+			t.Errorf("%s: expected status %d, got %d", testData.name, testData.expectedStatus, recorder.Code)
+		}
+		response := EvaluateTriggerResponse{}
+		if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+			t.Fatalf("%s: error decoding response: %s", testData.name, err)
+		}
+		if !strings.Contains(response.Error, testData.expectedError) || (testData.expectedError == "" && response.Error != "") {
+			t.Errorf("%s: expected error %q, got %q", testData.name, testData.expectedError, response.Error)
+		}
+		if testData.evaluation != nil {
+			if !reflect.DeepEqual(response.Evaluation, testData.evaluation) {
+				t.Errorf("%s: expected evaluation %+v, got %+v", testData.name, testData.evaluation, response.Evaluation)
+			}
+			if handler.request.Namespace != "default" || handler.request.ScaledObjectName != "app" || handler.request.Trigger.Type != "cron" {
+				t.Errorf("%s: unexpected request passed to the scale handler %+v", testData.name, handler.request)
+			}
+		}
+	}
+}
+
+func TestNewServerAddr(t *testing.T) {
+	tests := []struct {
+		addr    string
+		isError bool
+	}{
+		{"127.0.0.1:9666", false},
+		{"localhost:9666", false},
+		{"[::1]:9666", false},
+		{":9666", true},
+		{"0.0.0.0:9666", true},
+		{"10.0.0.1:9666", true},
+		{"keda-operator:9666", true},
+		{"9666", true},
+	}
+
+	for _, test := range tests {
+		_, err := NewServer(test.addr, &fakeScaleHandler{})
+		if test.isError && err == nil {
+			t.Errorf("%s: expected error", test.addr)
+		}
+		if !test.isError && err != nil {
+			t.Errorf("%s: unexpected error %s", test.addr, err)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package embedded
+
+import (
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	kedav1 "github.com/kedacore/keda/api/v1"
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+	"github.com/kedacore/keda/controllers"
+	"github.com/kedacore/keda/pkg/scaling"
+)
+
+// Options selects what KEDA runs in the manager it is embedded in
+type Options struct {
+	// Scalers are the trigger types the ScaledObjects and ScaledJobs can use, all the scalers are enabled if empty
+	Scalers []string
+	// TriggerEvaluation bounds the concurrent evaluation of the triggers, the defaults are used if not set
+	TriggerEvaluation scaling.TriggerEvaluationConfig
+	// ScalerConcurrency bounds the concurrent calls of the scalers of each trigger type, the calls aren't limited if not set.
+	// With a Namespace, the leader of the manager shares the limits with the other processes through a Lease
+	ScalerConcurrency scaling.ScalerConcurrencyConfig
+	// DisableScaledJobs doesn't start the ScaledJob controller
+	DisableScaledJobs bool
+	// DisableHTTPScaledObjects doesn't start the HTTPScaledObject controller
+	DisableHTTPScaledObjects bool
+	// OrphanedHPASweep configures the sweep of the HPAs managed by KEDA whose ScaledObject is gone, the HPAs aren't swept if not set
+	OrphanedHPASweep controllers.OrphanedHPASweepConfig
+	// EnableConversionWebhook serves the conversion of the ScaledObjects between keda.sh/v1alpha1 and keda.sh/v1,
+	// and the validation of the ScaledObjects and ScaledJobs, on the webhook server of the manager
+	EnableConversionWebhook bool
+}
+
+// AddToScheme adds the KEDA types to the scheme of the manager, it must be called before the manager is created
+func AddToScheme(scheme *runtime.Scheme) error {
+	if err := kedav1alpha1.AddToScheme(scheme); err != nil {
+		return err
+	}
+	return kedav1.AddToScheme(scheme)
+}
+
+// SetupWithManager registers the KEDA controllers in a manager owned by another binary, so KEDA runs without its own deployment.
+// The scheme of the manager must contain the KEDA types, see AddToScheme. The scale loops are started with the manager.
+func SetupWithManager(mgr ctrl.Manager, options Options) error {
+	if err := options.configure(); err != nil {
+		return err
+	}
+	// the Lease is only renewed by the leader, the other replicas don't call the scalers
+	leases, err := scaling.NewScalerConcurrencyLeases(mgr.GetAPIReader(), mgr.GetClient(), options.ScalerConcurrency)
+	if err != nil {
+		return err
+	}
+	if err := mgr.Add(leases); err != nil {
+		return err
+	}
+
+	log := ctrl.Log.WithName("controllers")
+	if err := (&controllers.ScaledObjectReconciler{
+		Client: mgr.GetClient(),
+		Log:    log.WithName("ScaledObject"),
+		Scheme: mgr.GetScheme(),
+
+		OrphanedHPASweep: options.OrphanedHPASweep,
+	}).SetupWithManager(mgr); err != nil {
+		return err
+	}
+	if !options.DisableScaledJobs {
+		if err := (&controllers.ScaledJobReconciler{
+			Client: mgr.GetClient(),
+			Log:    log.WithName("ScaledJob"),
+			Scheme: mgr.GetScheme(),
+		}).SetupWithManager(mgr); err != nil {
+			return err
+		}
+	}
+	if !options.DisableHTTPScaledObjects {
+		if err := (&controllers.HTTPScaledObjectReconciler{
+			Client: mgr.GetClient(),
+			Log:    log.WithName("HTTPScaledObject"),
+			Scheme: mgr.GetScheme(),
+		}).SetupWithManager(mgr); err != nil {
+			return err
+		}
+	}
+	if options.EnableConversionWebhook {
+		if err := (&kedav1.ScaledObject{}).SetupWebhookWithManager(mgr); err != nil {
+			return err
+		}
+		if err := controllers.SetupValidatingWebhooksWithManager(mgr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// configure applies the options shared by all the scale loops
+func (o Options) configure() error {
+	if err := scaling.EnableScalers(o.Scalers); err != nil {
+		return err
+	}
+	if err := scaling.ConfigureScalerConcurrency(o.ScalerConcurrency); err != nil {
+		return err
+	}
+	if o.TriggerEvaluation != (scaling.TriggerEvaluationConfig{}) {
+		return scaling.ConfigureTriggerEvaluation(o.TriggerEvaluation)
+	}
+	return nil
+}
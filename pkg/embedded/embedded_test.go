@@ -0,0 +1,48 @@
+package embedded
+
+import (
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+
+	kedav1 "github.com/kedacore/keda/api/v1"
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+	"github.com/kedacore/keda/pkg/scaling"
+)
+
+func TestAddToScheme(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := AddToScheme(scheme); err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	if !scheme.Recognizes(kedav1alpha1.GroupVersion.WithKind("ScaledObject")) {
+		t.Error("Expected the scheme to recognize ScaledObject")
+	}
+	if !scheme.Recognizes(kedav1.GroupVersion.WithKind("ScaledObject")) {
+		t.Error("Expected the scheme to recognize the keda.sh/v1 ScaledObject")
+	}
+}
+
+func TestConfigure(t *testing.T) {
+	defer func() {
+		_ = scaling.EnableScalers(nil)
+		_ = scaling.ConfigureTriggerEvaluation(scaling.TriggerEvaluationConfig{Concurrency: 4})
+	}()
+
+	tests := []struct {
+		options Options
+		isError bool
+	}{
+		{Options{}, false},
+		{Options{Scalers: []string{"cpu", "prometheus"}}, false},
+		{Options{TriggerEvaluation: scaling.TriggerEvaluationConfig{Concurrency: 2, Timeout: time.Second}}, false},
+		{Options{Scalers: []string{""}}, true},
+		{Options{TriggerEvaluation: scaling.TriggerEvaluationConfig{Timeout: time.Second}}, true},
+	}
+	for _, test := range tests {
+		if err := test.options.configure(); (err != nil) != test.isError {
+			t.Errorf("%+v: expected error %t, got %v", test.options, test.isError, err)
+		}
+	}
+}
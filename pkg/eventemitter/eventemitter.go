@@ -0,0 +1,188 @@
+package eventemitter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+)
+
+const (
+	// ScaledObjectReadyType is emitted when a ScaledObject becomes ready for scaling
+	ScaledObjectReadyType = "keda.scaledobject.ready.v1"
+	// ScaledObjectConflictType is emitted when the scale target of a ScaledObject is already scaled by another ScaledObject or HPA
+	ScaledObjectConflictType = "keda.scaledobject.conflict.v1"
+	// ScaledObjectPolicyViolationType is emitted when a ScaledObject exceeds the limits of the global configuration, it isn't scaled anymore
+	ScaledObjectPolicyViolationType = "keda.scaledobject.policyviolation.v1"
+	// ScaledJobPolicyViolationType is emitted when a ScaledJob exceeds the limits of the global configuration, it isn't scaled anymore
+	ScaledJobPolicyViolationType = "keda.scaledjob.policyviolation.v1"
+	// ScaledObjectDryRunEvaluatedType is emitted when the evaluation of the triggers of a ScaledObject in dry-run mode changes its activity or replica count
+	ScaledObjectDryRunEvaluatedType = "keda.scaledobject.dryrun.evaluated.v1"
+	// ScaledObjectDryRunHPAType is emitted when the scale target of a ScaledObject in dry-run mode is still scaled by an HPA
+	ScaledObjectDryRunHPAType = "keda.scaledobject.dryrun.hpa.v1"
+	// ScaledObjectVPAConflictType is emitted when the pods of the scale target of a ScaledObject are updated by a VerticalPodAutoscaler
+	ScaledObjectVPAConflictType = "keda.scaledobject.vpaconflict.v1"
+	// ScalerErrorType is emitted when a scaler fails to check its trigger
+	ScalerErrorType = "keda.scaler.error.v1"
+	// ScaleTargetActivatedType is emitted when the scale target is scaled from zero
+	ScaleTargetActivatedType = "keda.scaletarget.activated.v1"
+	// ScaleTargetDeactivatedType is emitted when the scale target is scaled to zero
+	ScaleTargetDeactivatedType = "keda.scaletarget.deactivated.v1"
+	// PreScaleDownHookFailedType is emitted when the pre-scale-down hook of a pod of the scale target fails, the scale down is postponed
+	PreScaleDownHookFailedType = "keda.scaletarget.prescaledownhook.failed.v1"
+	// ScaleDownDeferredType is emitted when a scale down of the scale target is deferred as it would violate a PodDisruptionBudget
+	ScaleDownDeferredType = "keda.scaletarget.scaledowndeferred.v1"
+	// ScaleWebhookFailedType is emitted when a preActivation or postDeactivation webhook of a ScaledObject fails
+	ScaleWebhookFailedType = "keda.scaletarget.scalewebhook.failed.v1"
+	// HPADriftCorrectedType is emitted when KEDA applies back the HPA of a ScaledObject changed outside of KEDA
+	HPADriftCorrectedType = "keda.scaledobject.hpadriftcorrected.v1"
+	// OrphanedHPAFoundType is emitted in dry-run mode when the sweep finds an HPA managed by KEDA whose ScaledObject is gone
+	OrphanedHPAFoundType = "keda.scaledobject.orphanedhpa.found.v1"
+	// OrphanedHPADeletedType is emitted when the sweep deletes an HPA managed by KEDA whose ScaledObject is gone
+	OrphanedHPADeletedType = "keda.scaledobject.orphanedhpa.deleted.v1"
+
+	cloudEventsSpecVersion = "1.0"
+	cloudEventsContentType = "application/cloudevents+json"
+	sendTimeout            = 10 * time.Second
+)
+
+// EventEmitter sends the KEDA events as CloudEvents to the destinations defined by the CloudEventSources
+type EventEmitter interface {
+	Emit(kind, namespace, name, eventType, message string)
+}
+
+type eventEmitter struct {
+	client     client.Client
+	httpClient *http.Client
+	logger     logr.Logger
+}
+
+// CloudEvent is a CloudEvent in the JSON event format
+type CloudEvent struct {
+	SpecVersion     string    `json:"specversion"`
+	ID              string    `json:"id"`
+	Source          string    `json:"source"`
+	Subject         string    `json:"subject"`
+	Type            string    `json:"type"`
+	Time            time.Time `json:"time"`
+	DataContentType string    `json:"datacontenttype"`
+	Data            EventData `json:"data"`
+}
+
+// EventData is the data of the CloudEvents emitted by KEDA
+type EventData struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Message   string `json:"message"`
+}
+
+// NewEventEmitter creates an EventEmitter object
+func NewEventEmitter(client client.Client) EventEmitter {
+	return &eventEmitter{
+		client:     client,
+		httpClient: &http.Client{Timeout: sendTimeout},
+		logger:     logf.Log.WithName("eventemitter"),
+	}
+}
+
+// Emit sends the event to all the CloudEventSources in the namespace subscribed to the event type,
+// the events are sent asynchronously so the scaling is not delayed by slow destinations
+func (e *eventEmitter) Emit(kind, namespace, name, eventType, message string) {
+	cloudEventSources := &kedav1alpha1.CloudEventSourceList{}
+	if err := e.client.List(context.TODO(), cloudEventSources, client.InNamespace(namespace)); err != nil {
+		e.logger.Error(err, "Failed to list CloudEventSources", "namespace", namespace)
+		return
+	}
+
+	for i := range cloudEventSources.Items {
+		cloudEventSource := &cloudEventSources.Items[i]
+		if cloudEventSource.Spec.Destination.HTTP == nil || cloudEventSource.Spec.Destination.HTTP.URI == "" {
+			continue
+		}
+		if !isSubscribed(cloudEventSource.Spec.EventSubscription, eventType) {
+			continue
+		}
+
+		event := newCloudEvent(cloudEventSource.Spec.ClusterName, kind, namespace, name, eventType, message)
+		go func(uri, cloudEventSourceName string) {
+			if err := e.send(uri, event); err != nil {
+				e.logger.Error(err, "Failed to send CloudEvent", "CloudEventSource.Namespace", namespace, "CloudEventSource.Name", cloudEventSourceName, "type", eventType)
+			}
+		}(cloudEventSource.Spec.Destination.HTTP.URI, cloudEventSource.Name)
+	}
+}
+
+func newCloudEvent(clusterName, kind, namespace, name, eventType, message string) CloudEvent {
+	if clusterName == "" {
+		clusterName = kedav1alpha1.DefaultCloudEventSourceClusterName
+	}
+	return CloudEvent{
+		SpecVersion:     cloudEventsSpecVersion,
+		ID:              string(uuid.NewUUID()),
+		Source:          fmt.Sprintf("/%s/%s/keda", clusterName, namespace),
+		Subject:         fmt.Sprintf("/%s/%s/%s/%s", clusterName, namespace, strings.ToLower(kind), name),
+		Type:            eventType,
+		Time:            time.Now().UTC(),
+		DataContentType: "application/json",
+		Data: EventData{
+			Kind:      kind,
+			Namespace: namespace,
+			Name:      name,
+			Message:   message,
+		},
+	}
+}
+
+// isSubscribed returns true if the event type is included, or no type is included, and it isn't excluded
+func isSubscribed(subscription kedav1alpha1.EventSubscription, eventType string) bool {
+	for _, excluded := range subscription.ExcludedEventTypes {
+		if excluded == eventType {
+			return false
+		}
+	}
+	if len(subscription.IncludedEventTypes) == 0 {
+		return true
+	}
+	for _, included := range subscription.IncludedEventTypes {
+		if included == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// send posts the event to the HTTP sink in structured content mode
+func (e *eventEmitter) send(uri string, event CloudEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("POST", uri, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", cloudEventsContentType)
+
+	resp, err := e.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("destination returned %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package eventemitter
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+)
+
+type isSubscribedTestData struct {
+	subscription kedav1alpha1.EventSubscription
+	eventType    string
+	expected     bool
+}
+
+var isSubscribedTestDataset = []isSubscribedTestData{
+	{kedav1alpha1.EventSubscription{}, ScalerErrorType, true},
+	{kedav1alpha1.EventSubscription{IncludedEventTypes: []string{ScalerErrorType}}, ScalerErrorType, true},
+	{kedav1alpha1.EventSubscription{IncludedEventTypes: []string{ScaledObjectReadyType}}, ScalerErrorType, false},
+	{kedav1alpha1.EventSubscription{ExcludedEventTypes: []string{ScalerErrorType}}, ScalerErrorType, false},
+	{kedav1alpha1.EventSubscription{ExcludedEventTypes: []string{ScalerErrorType}}, ScaleTargetActivatedType, true},
+	{kedav1alpha1.EventSubscription{IncludedEventTypes: []string{ScalerErrorType}, ExcludedEventTypes: []string{ScalerErrorType}}, ScalerErrorType, false},
+}
+
+func TestIsSubscribed(t *testing.T) {
+	for _, testData := range isSubscribedTestDataset {
+		assert.Equal(t, testData.expected, isSubscribed(testData.subscription, testData.eventType), testData)
+	}
+}
+
+func TestEmit(t *testing.T) {
+	received := make(chan *http.Request, 10)
+	bodies := make(chan []byte, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		received <- r
+		bodies <- body
+	}))
+	defer server.Close()
+
+	scheme := runtime.NewScheme()
+	assert.NoError(t, kedav1alpha1.AddToScheme(scheme))
+	kubeClient := fake.NewFakeClientWithScheme(scheme,
+		&kedav1alpha1.CloudEventSource{
+			ObjectMeta: metav1.ObjectMeta{Name: "all", Namespace: "default"},
+			Spec: kedav1alpha1.CloudEventSourceSpec{
+				ClusterName: "cluster-a",
+				Destination: kedav1alpha1.Destination{HTTP: &kedav1alpha1.CloudEventHTTP{URI: server.URL + "/all"}},
+			},
+		},
+		&kedav1alpha1.CloudEventSource{
+			ObjectMeta: metav1.ObjectMeta{Name: "ready-only", Namespace: "default"},
+			Spec: kedav1alpha1.CloudEventSourceSpec{
+				Destination:       kedav1alpha1.Destination{HTTP: &kedav1alpha1.CloudEventHTTP{URI: server.URL + "/ready"}},
+				EventSubscription: kedav1alpha1.EventSubscription{IncludedEventTypes: []string{ScaledObjectReadyType}},
+			},
+		},
+		&kedav1alpha1.CloudEventSource{
+			ObjectMeta: metav1.ObjectMeta{Name: "other-namespace", Namespace: "other"},
+			Spec: kedav1alpha1.CloudEventSourceSpec{
+				Destination: kedav1alpha1.Destination{HTTP: &kedav1alpha1.CloudEventHTTP{URI: server.URL + "/other"}},
+			},
+		},
+	)
+
+	emitter := NewEventEmitter(kubeClient)
+	emitter.Emit("ScaledObject", "default", "test", ScalerErrorType, "error checking trigger")
+
+	select {
+	case r := <-received:
+		assert.Equal(t, "/all", r.URL.Path)
+		assert.Equal(t, cloudEventsContentType, r.Header.Get("Content-Type"))
+	case <-time.After(5 * time.Second):
+		t.Fatal("CloudEvent was not received")
+	}
+
+	event := CloudEvent{}
+	assert.NoError(t, json.Unmarshal(<-bodies, &event))
+	assert.Equal(t, "1.0", event.SpecVersion)
+	assert.Equal(t, ScalerErrorType, event.Type)
+	assert.Equal(t, "/cluster-a/default/keda", event.Source)
+	assert.Equal(t, "/cluster-a/default/scaledobject/test", event.Subject)
+	assert.Equal(t, "error checking trigger", event.Data.Message)
+	assert.NotEmpty(t, event.ID)
+
+	select {
+	case r := <-received:
+		t.Errorf("Unexpected CloudEvent sent to %s", r.URL.Path)
+	case <-time.After(500 * time.Millisecond):
+	}
+}
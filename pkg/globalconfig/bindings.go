@@ -0,0 +1,64 @@
+package globalconfig
+
+import (
+	"flag"
+	"strconv"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	kedautil "github.com/kedacore/keda/pkg/util"
+)
+
+// BindHTTPTimeout applies the HTTP timeout of the configuration to the requests of the scalers without their own HTTP client
+func BindHTTPTimeout() {
+	OnChange(func(config Config) {
+		kedautil.SetDefaultHTTPTimeout(config.HTTPTimeout)
+	})
+}
+
+// BindLogLevel applies the log level of the configuration to the zap logger,
+// the level set by the flags is restored if the configuration has no log level
+func BindLogLevel(level zap.AtomicLevel) {
+	initial := level.Level()
+	OnChange(func(config Config) {
+		level.SetLevel(getLogLevel(config, initial))
+	})
+}
+
+// BindKlogVerbosity applies the log level of the configuration to the verbosity of klog, used by the metrics server,
+// the debug levels map to the verbosity of the same integer and the verbosity set by the flags is restored if the configuration has no log level
+func BindKlogVerbosity() {
+	verbosityFlag := flag.Lookup("v")
+	if verbosityFlag == nil {
+		log.Info("The klog verbosity flag isn't registered, the log level of the global configuration is ignored")
+		return
+	}
+	initial := verbosityFlag.Value.String()
+	OnChange(func(config Config) {
+		verbosity := initial
+		if config.LogLevel != "" {
+			level := getLogLevel(config, zapcore.InfoLevel)
+			if level < zapcore.InfoLevel {
+				verbosity = strconv.Itoa(-int(level))
+			} else {
+				verbosity = "0"
+			}
+		}
+		if err := verbosityFlag.Value.Set(verbosity); err != nil {
+			log.Error(err, "Failed to set the klog verbosity", "verbosity", verbosity)
+		}
+	})
+}
+
+// getLogLevel returns the zap level of the configuration or the initial level if not set
+func getLogLevel(config Config, initial zapcore.Level) zapcore.Level {
+	if config.LogLevel == "" {
+		return initial
+	}
+	level, err := ParseLogLevel(config.LogLevel)
+	if err != nil {
+		return initial
+	}
+	return level
+}
@@ -0,0 +1,324 @@
+package globalconfig
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+	"k8s.io/apimachinery/pkg/labels"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+)
+
+const (
+	httpTimeoutKey           = "httpTimeout"
+	watchNamespacesKey       = "watchNamespaces"
+	watchLabelSelectorKey    = "watchLabelSelector"
+	defaultCooldownPeriodKey = "defaultCooldownPeriod"
+	logLevelKey              = "logLevel"
+	metricsCacheTTLKey       = "metricsCacheTTL"
+	scalerResultCacheTTLKey  = "scalerResultCacheTTL"
+
+	maxScaledObjectsPerNamespaceKey = "maxScaledObjectsPerNamespace"
+	maxTriggersPerObjectKey         = "maxTriggersPerObject"
+	bannedTriggerTypesKey           = "bannedTriggerTypes"
+	secretFileDirectoriesKey        = "secretFileDirectories"
+
+	commandTriggerImagesKey          = "commandTriggerImages"
+	commandTriggerServiceAccountsKey = "commandTriggerServiceAccounts"
+
+	// Default cooldown period in seconds for a ScaleTarget if no cooldownPeriod is defined on the ScaledObject
+	defaultCooldownPeriod = 5 * 60
+	// Default time the results of the scalers are shared by the checks of a trigger
+	defaultScalerResultCacheTTL = 3 * time.Second
+)
+
+// Config holds the global defaults of the operator and the metrics server, read from a ConfigMap
+// and applied without restarting the pods
+type Config struct {
+	// HTTPTimeout is the timeout of the requests of the scalers without their own HTTP client, 0 disables the timeout
+	HTTPTimeout time.Duration
+	// WatchNamespaces are the namespaces the ScaledObjects and ScaledJobs are handled in, all the namespaces if empty
+	WatchNamespaces []string
+	// WatchLabelSelector selects the ScaledObjects and ScaledJobs handled, all of them if empty,
+	// several installations can then share the namespaces, each handling the objects with its own labels
+	WatchLabelSelector string
+	// DefaultCooldownPeriod is the cooldown period in seconds of the ScaledObjects without cooldownPeriod
+	DefaultCooldownPeriod int32
+	// LogLevel is debug, info, error or an integer greater than 0 for increasing debug verbosity, the level of the flags is used if empty
+	LogLevel string
+	// MetricsCacheTTL is how long the metrics server caches the metrics of the triggers with useCachedMetrics and without metricsCacheTTL,
+	// the polling interval of the ScaledObject is used if 0
+	MetricsCacheTTL time.Duration
+	// ScalerResultCacheTTL is how long the results of IsActive and GetMetrics of a trigger are reused by the checks of the scale handler,
+	// so the checks made close together hit the upstream API once, 0 disables the cache
+	ScalerResultCacheTTL time.Duration
+	// MaxScaledObjectsPerNamespace is the number of ScaledObjects handled in a namespace, the ScaledObjects created last are rejected, 0 for no limit
+	MaxScaledObjectsPerNamespace int
+	// MaxTriggersPerObject is the number of triggers a ScaledObject or ScaledJob can have, 0 for no limit
+	MaxTriggersPerObject int
+	// BannedTriggerTypes are the trigger types the ScaledObjects and ScaledJobs can't use
+	BannedTriggerTypes []string
+	// SecretFileDirectories are the directories of the operator and metrics server pods the triggers can read their parameters from
+	// with <key>FromFile, e.g. the mount of a projected volume or of the Secrets Store CSI driver, the files can't be read if empty
+	SecretFileDirectories []string
+	// CommandTriggerImages are the images the command triggers can run, an image ending with * allows the images starting with it,
+	// the command trigger can't be used if empty
+	CommandTriggerImages []string
+	// CommandTriggerServiceAccounts are the service accounts the Jobs of the command triggers can run as, besides the default one
+	// of the namespace without a mounted token
+	CommandTriggerServiceAccounts []string
+
+	watchSelector labels.Selector
+}
+
+// DefaultConfig returns the configuration used if there is no ConfigMap
+func DefaultConfig() Config {
+	return Config{
+		DefaultCooldownPeriod: defaultCooldownPeriod,
+		ScalerResultCacheTTL:  defaultScalerResultCacheTTL,
+	}
+}
+
+var (
+	mutex     sync.RWMutex
+	current   = DefaultConfig()
+	listeners []func(Config)
+)
+
+// Get returns the current configuration
+func Get() Config {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	return current
+}
+
+// OnChange registers a function called with the new configuration each time it is applied
+func OnChange(listener func(Config)) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	listeners = append(listeners, listener)
+}
+
+// apply replaces the current configuration and notifies the listeners
+func apply(config Config) {
+	mutex.Lock()
+	current = config
+	notify := append([]func(Config){}, listeners...)
+	mutex.Unlock()
+
+	for _, listener := range notify {
+		listener(config)
+	}
+}
+
+// IsWatched returns true if the ScaledObject or ScaledJob with the labels in the namespace is handled
+func (c Config) IsWatched(namespace string, objectLabels map[string]string) bool {
+	if !c.IsNamespaceWatched(namespace) {
+		return false
+	}
+	return c.watchSelector == nil || c.watchSelector.Matches(labels.Set(objectLabels))
+}
+
+// IsNamespaceWatched returns true if the ScaledObjects and ScaledJobs of the namespace are handled
+func (c Config) IsNamespaceWatched(namespace string) bool {
+	if len(c.WatchNamespaces) == 0 {
+		return true
+	}
+	for _, watched := range c.WatchNamespaces {
+		if watched == namespace {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckTriggers returns an error if the triggers of a ScaledObject or ScaledJob exceed the limits of the configuration
+func (c Config) CheckTriggers(triggers []kedav1alpha1.ScaleTriggers) error {
+	if c.MaxTriggersPerObject > 0 && len(triggers) > c.MaxTriggersPerObject {
+		return fmt.Errorf("%d triggers exceed the limit of %d triggers per object", len(triggers), c.MaxTriggersPerObject)
+	}
+	for _, trigger := range triggers {
+		for _, banned := range c.BannedTriggerTypes {
+			if trigger.Type == banned {
+				return fmt.Errorf("the trigger type %s is banned", trigger.Type)
+			}
+		}
+	}
+	return nil
+}
+
+// CheckCommandTrigger returns an error if the command trigger can't run the image as the service account, an empty service account
+// is the default one of the namespace, its token isn't mounted
+func (c Config) CheckCommandTrigger(image, serviceAccountName string) error {
+	if len(c.CommandTriggerImages) == 0 {
+		return fmt.Errorf("the command trigger is disabled, no image is allowed by %s", commandTriggerImagesKey)
+	}
+	allowed := false
+	for _, allowedImage := range c.CommandTriggerImages {
+		if image == allowedImage || strings.HasSuffix(allowedImage, "*") && strings.HasPrefix(image, strings.TrimSuffix(allowedImage, "*")) {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return fmt.Errorf("the image %s isn't allowed by %s", image, commandTriggerImagesKey)
+	}
+	if serviceAccountName == "" {
+		return nil
+	}
+	for _, allowedServiceAccount := range c.CommandTriggerServiceAccounts {
+		if serviceAccountName == allowedServiceAccount {
+			return nil
+		}
+	}
+	return fmt.Errorf("the service account %s isn't allowed by %s", serviceAccountName, commandTriggerServiceAccountsKey)
+}
+
+// Parse returns the configuration defined by the data of the ConfigMap, the keys not set keep their default
+func Parse(data map[string]string) (Config, error) {
+	config := DefaultConfig()
+
+	if val, ok := data[httpTimeoutKey]; ok && val != "" {
+		timeout, err := time.ParseDuration(val)
+		if err != nil {
+			return config, fmt.Errorf("error parsing %s: %s", httpTimeoutKey, err)
+		}
+		if timeout < 0 {
+			return config, fmt.Errorf("%s must not be negative", httpTimeoutKey)
+		}
+		config.HTTPTimeout = timeout
+	}
+
+	if val, ok := data[watchNamespacesKey]; ok && val != "" {
+		for _, namespace := range strings.Split(val, ",") {
+			if namespace = strings.TrimSpace(namespace); namespace != "" {
+				config.WatchNamespaces = append(config.WatchNamespaces, namespace)
+			}
+		}
+	}
+
+	if val, ok := data[watchLabelSelectorKey]; ok && val != "" {
+		selector, err := labels.Parse(val)
+		if err != nil {
+			return config, fmt.Errorf("error parsing %s: %s", watchLabelSelectorKey, err)
+		}
+		config.WatchLabelSelector = val
+		config.watchSelector = selector
+	}
+
+	if val, ok := data[defaultCooldownPeriodKey]; ok && val != "" {
+		cooldownPeriod, err := strconv.ParseInt(val, 10, 32)
+		if err != nil {
+			return config, fmt.Errorf("error parsing %s: %s", defaultCooldownPeriodKey, err)
+		}
+		if cooldownPeriod < 0 {
+			return config, fmt.Errorf("%s must not be negative", defaultCooldownPeriodKey)
+		}
+		config.DefaultCooldownPeriod = int32(cooldownPeriod)
+	}
+
+	if val, ok := data[logLevelKey]; ok && val != "" {
+		if _, err := ParseLogLevel(val); err != nil {
+			return config, err
+		}
+		config.LogLevel = val
+	}
+
+	if val, ok := data[metricsCacheTTLKey]; ok && val != "" {
+		ttl, err := time.ParseDuration(val)
+		if err != nil {
+			return config, fmt.Errorf("error parsing %s: %s", metricsCacheTTLKey, err)
+		}
+		if ttl < 0 {
+			return config, fmt.Errorf("%s must not be negative", metricsCacheTTLKey)
+		}
+		config.MetricsCacheTTL = ttl
+	}
+
+	if val, ok := data[scalerResultCacheTTLKey]; ok && val != "" {
+		ttl, err := time.ParseDuration(val)
+		if err != nil {
+			return config, fmt.Errorf("error parsing %s: %s", scalerResultCacheTTLKey, err)
+		}
+		if ttl < 0 {
+			return config, fmt.Errorf("%s must not be negative", scalerResultCacheTTLKey)
+		}
+		config.ScalerResultCacheTTL = ttl
+	}
+
+	parseLimit := func(key string, value *int) error {
+		if val, ok := data[key]; ok && val != "" {
+			limit, err := strconv.Atoi(val)
+			if err != nil {
+				return fmt.Errorf("error parsing %s: %s", key, err)
+			}
+			if limit < 0 {
+				return fmt.Errorf("%s must not be negative", key)
+			}
+			*value = limit
+		}
+		return nil
+	}
+	if err := parseLimit(maxScaledObjectsPerNamespaceKey, &config.MaxScaledObjectsPerNamespace); err != nil {
+		return config, err
+	}
+	if err := parseLimit(maxTriggersPerObjectKey, &config.MaxTriggersPerObject); err != nil {
+		return config, err
+	}
+
+	if val, ok := data[bannedTriggerTypesKey]; ok && val != "" {
+		for _, triggerType := range strings.Split(val, ",") {
+			if triggerType = strings.TrimSpace(triggerType); triggerType != "" {
+				config.BannedTriggerTypes = append(config.BannedTriggerTypes, triggerType)
+			}
+		}
+	}
+
+	if val, ok := data[secretFileDirectoriesKey]; ok && val != "" {
+		for _, directory := range strings.Split(val, ",") {
+			if directory = strings.TrimSpace(directory); directory == "" {
+				continue
+			}
+			if !filepath.IsAbs(directory) {
+				return config, fmt.Errorf("the directory %s of %s must be absolute", directory, secretFileDirectoriesKey)
+			}
+			config.SecretFileDirectories = append(config.SecretFileDirectories, filepath.Clean(directory))
+		}
+	}
+
+	parseList := func(key string, values *[]string) {
+		if val, ok := data[key]; ok && val != "" {
+			for _, value := range strings.Split(val, ",") {
+				if value = strings.TrimSpace(value); value != "" {
+					*values = append(*values, value)
+				}
+			}
+		}
+	}
+	parseList(commandTriggerImagesKey, &config.CommandTriggerImages)
+	parseList(commandTriggerServiceAccountsKey, &config.CommandTriggerServiceAccounts)
+
+	return config, nil
+}
+
+// ParseLogLevel returns the zap level of a log level, the levels are the ones of the zap-log-level flag
+func ParseLogLevel(logLevel string) (zapcore.Level, error) {
+	switch strings.ToLower(logLevel) {
+	case "debug":
+		return zapcore.DebugLevel, nil
+	case "info":
+		return zapcore.InfoLevel, nil
+	case "error":
+		return zapcore.ErrorLevel, nil
+	}
+	verbosity, err := strconv.Atoi(logLevel)
+	if err != nil || verbosity <= 0 || verbosity > 127 {
+		return zapcore.InfoLevel, fmt.Errorf("invalid %s %q, must be debug, info, error or an integer greater than 0", logLevelKey, logLevel)
+	}
+	return zapcore.Level(int8(-verbosity)), nil
+}
@@ -0,0 +1,177 @@
+package globalconfig
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+)
+
+type parseGlobalConfigTestData struct {
+	data     map[string]string
+	expected Config
+	isError  bool
+}
+
+var parseGlobalConfigTestDataset = []parseGlobalConfigTestData{
+	// defaults
+	{map[string]string{}, DefaultConfig(), false},
+	// all the keys
+	{map[string]string{"httpTimeout": "3s", "watchNamespaces": "apps, jobs,", "defaultCooldownPeriod": "60", "logLevel": "debug", "metricsCacheTTL": "1m", "scalerResultCacheTTL": "10s"},
+		Config{HTTPTimeout: 3 * time.Second, WatchNamespaces: []string{"apps", "jobs"}, DefaultCooldownPeriod: 60, LogLevel: "debug", MetricsCacheTTL: time.Minute, ScalerResultCacheTTL: 10 * time.Second}, false},
+	// invalid label selector
+	{map[string]string{"watchLabelSelector": "tenant in (a"}, Config{}, true},
+	// malformed timeout
+	{map[string]string{"httpTimeout": "3"}, Config{}, true},
+	// negative timeout
+	{map[string]string{"httpTimeout": "-3s"}, Config{}, true},
+	// malformed cooldown period
+	{map[string]string{"defaultCooldownPeriod": "5m"}, Config{}, true},
+	// negative cooldown period
+	{map[string]string{"defaultCooldownPeriod": "-1"}, Config{}, true},
+	// unknown log level
+	{map[string]string{"logLevel": "verbose"}, Config{}, true},
+	// malformed metrics cache TTL
+	{map[string]string{"metricsCacheTTL": "often"}, Config{}, true},
+	// scaler result cache disabled
+	{map[string]string{"scalerResultCacheTTL": "0s"}, Config{DefaultCooldownPeriod: defaultCooldownPeriod}, false},
+	// negative scaler result cache TTL
+	{map[string]string{"scalerResultCacheTTL": "-1s"}, Config{}, true},
+	// scaling policy
+	{map[string]string{"maxScaledObjectsPerNamespace": "10", "maxTriggersPerObject": "3", "bannedTriggerTypes": "cron, external,"},
+		Config{DefaultCooldownPeriod: defaultCooldownPeriod, ScalerResultCacheTTL: defaultScalerResultCacheTTL, MaxScaledObjectsPerNamespace: 10, MaxTriggersPerObject: 3, BannedTriggerTypes: []string{"cron", "external"}}, false},
+	// malformed limit
+	{map[string]string{"maxTriggersPerObject": "three"}, Config{}, true},
+	// negative limit
+	{map[string]string{"maxScaledObjectsPerNamespace": "-1"}, Config{}, true},
+	// secret file directories
+	{map[string]string{"secretFileDirectories": "/mnt/secrets-store/, /var/run/secrets/tokens,"},
+		Config{DefaultCooldownPeriod: defaultCooldownPeriod, ScalerResultCacheTTL: defaultScalerResultCacheTTL, SecretFileDirectories: []string{"/mnt/secrets-store", "/var/run/secrets/tokens"}}, false},
+	// relative secret file directory
+	{map[string]string{"secretFileDirectories": "secrets"}, Config{}, true},
+	// command trigger
+	{map[string]string{"commandTriggerImages": "registry.local/tools:1.2, registry.local/depth/*,", "commandTriggerServiceAccounts": "depth"},
+		Config{DefaultCooldownPeriod: defaultCooldownPeriod, ScalerResultCacheTTL: defaultScalerResultCacheTTL, CommandTriggerImages: []string{"registry.local/tools:1.2", "registry.local/depth/*"}, CommandTriggerServiceAccounts: []string{"depth"}}, false},
+}
+
+func TestParse(t *testing.T) {
+	for _, testData := range parseGlobalConfigTestDataset {
+		config, err := Parse(testData.data)
+		if err != nil && !testData.isError {
+			t.Error("Expected success but got error", err)
+		}
+		if testData.isError && err == nil {
+			t.Errorf("Expected error but got success for %v", testData.data)
+		}
+		if !testData.isError && !reflect.DeepEqual(config, testData.expected) {
+			t.Errorf("Expected %+v, got %+v", testData.expected, config)
+		}
+	}
+}
+
+func TestParseLogLevel(t *testing.T) {
+	tests := []struct {
+		logLevel string
+		expected zapcore.Level
+		isError  bool
+	}{
+		{"debug", zapcore.DebugLevel, false},
+		{"INFO", zapcore.InfoLevel, false},
+		{"error", zapcore.ErrorLevel, false},
+		{"3", zapcore.Level(-3), false},
+		{"0", zapcore.InfoLevel, true},
+		{"warn", zapcore.InfoLevel, true},
+	}
+	for _, test := range tests {
+		level, err := ParseLogLevel(test.logLevel)
+		if (err != nil) != test.isError {
+			t.Errorf("%s: expected error %t, got %v", test.logLevel, test.isError, err)
+		}
+		if err == nil && level != test.expected {
+			t.Errorf("%s: expected level %s, got %s", test.logLevel, test.expected, level)
+		}
+	}
+}
+
+func TestIsWatched(t *testing.T) {
+	config, err := Parse(map[string]string{"watchNamespaces": "apps", "watchLabelSelector": "keda.sh/tenant=a"})
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	if !config.IsWatched("apps", map[string]string{"keda.sh/tenant": "a", "app": "web"}) {
+		t.Error("Expected the object of tenant a in apps to be watched")
+	}
+	if config.IsWatched("apps", map[string]string{"keda.sh/tenant": "b"}) {
+		t.Error("Expected the object of tenant b not to be watched")
+	}
+	if config.IsWatched("apps", nil) {
+		t.Error("Expected the object without labels not to be watched")
+	}
+	if config.IsWatched("default", map[string]string{"keda.sh/tenant": "a"}) {
+		t.Error("Expected the object of another namespace not to be watched")
+	}
+	if !(Config{}).IsWatched("default", nil) {
+		t.Error("Expected all the objects to be watched by default")
+	}
+}
+
+func TestIsNamespaceWatched(t *testing.T) {
+	if !(Config{}).IsNamespaceWatched("apps") {
+		t.Error("Expected all the namespaces to be watched if no namespace is given")
+	}
+	config := Config{WatchNamespaces: []string{"apps", "jobs"}}
+	if !config.IsNamespaceWatched("jobs") {
+		t.Error("Expected jobs to be watched")
+	}
+	if config.IsNamespaceWatched("default") {
+		t.Error("Expected default not to be watched")
+	}
+}
+
+func TestCheckTriggers(t *testing.T) {
+	triggers := []kedav1alpha1.ScaleTriggers{{Type: "cpu"}, {Type: "cron"}}
+	if err := (Config{}).CheckTriggers(triggers); err != nil {
+		t.Errorf("Expected no limit by default, got %s", err)
+	}
+	if err := (Config{MaxTriggersPerObject: 2}).CheckTriggers(triggers); err != nil {
+		t.Errorf("Expected the triggers within the limit to be accepted, got %s", err)
+	}
+	if err := (Config{MaxTriggersPerObject: 1}).CheckTriggers(triggers); err == nil {
+		t.Error("Expected an error for the triggers exceeding the limit")
+	}
+	if err := (Config{BannedTriggerTypes: []string{"external", "cron"}}).CheckTriggers(triggers); err == nil {
+		t.Error("Expected an error for a banned trigger type")
+	}
+}
+
+func TestCheckCommandTrigger(t *testing.T) {
+	config := Config{CommandTriggerImages: []string{"registry.local/tools:1.2", "registry.local/depth/*"}, CommandTriggerServiceAccounts: []string{"depth"}}
+	tests := []struct {
+		name               string
+		config             Config
+		image              string
+		serviceAccountName string
+		isError            bool
+	}{
+		{"disabled by default", Config{}, "registry.local/tools:1.2", "", true},
+		{"allowed image", config, "registry.local/tools:1.2", "", false},
+		{"other tag", config, "registry.local/tools:1.3", "", true},
+		{"allowed prefix", config, "registry.local/depth/orders:1.0", "", false},
+		{"other repository", config, "docker.io/busybox", "", true},
+		{"allowed service account", config, "registry.local/tools:1.2", "depth", false},
+		{"other service account", config, "registry.local/tools:1.2", "keda-operator", true},
+	}
+
+	for _, test := range tests {
+		err := test.config.CheckCommandTrigger(test.image, test.serviceAccountName)
+		if test.isError && err == nil {
+			t.Errorf("%s: expected an error", test.name)
+		}
+		if !test.isError && err != nil {
+			t.Errorf("%s: expected success, got %s", test.name, err)
+		}
+	}
+}
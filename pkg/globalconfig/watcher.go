@@ -0,0 +1,115 @@
+package globalconfig
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// interval between two reads of the ConfigMap, a change is applied at most after this interval
+const refreshInterval = 15 * time.Second
+
+var log = logf.Log.WithName("globalconfig")
+
+// watcher reads the ConfigMap periodically and applies the configuration when it changes,
+// an invalid configuration is reported and the previous one is kept
+type watcher struct {
+	kubeClient client.Client
+	key        types.NamespacedName
+	applied    *Config
+	stopCh     chan struct{}
+	doneCh     chan struct{}
+}
+
+// Start applies the configuration of the ConfigMap, given as <namespace>/<name>, and keeps it up to date with the changes of the ConfigMap.
+// The defaults are used if configMap is empty. The bindings must be registered before, so the first configuration is applied to them.
+// The returned function stops watching the ConfigMap.
+func Start(configMap string, restConfig *rest.Config) (func(), error) {
+	if configMap == "" {
+		return func() {}, nil
+	}
+
+	parts := strings.SplitN(configMap, "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return nil, fmt.Errorf("invalid global configuration ConfigMap %q, must be <namespace>/<name>", configMap)
+	}
+	kubeClient, err := client.New(restConfig, client.Options{Scheme: clientgoscheme.Scheme})
+	if err != nil {
+		return nil, fmt.Errorf("error creating kubernetes client for the global configuration: %s", err)
+	}
+
+	w := newWatcher(kubeClient, types.NamespacedName{Namespace: parts[0], Name: parts[1]})
+	// the first read is synchronous so the configuration is applied before the controllers start
+	if err := w.refresh(); err != nil {
+		log.Error(err, "Failed to read the global configuration, the defaults are used", "ConfigMap", configMap)
+	}
+	go w.run()
+	return w.stop, nil
+}
+
+func newWatcher(kubeClient client.Client, key types.NamespacedName) *watcher {
+	return &watcher{
+		kubeClient: kubeClient,
+		key:        key,
+		stopCh:     make(chan struct{}),
+		doneCh:     make(chan struct{}),
+	}
+}
+
+func (w *watcher) run() {
+	defer close(w.doneCh)
+	ticker := time.NewTicker(refreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-w.stopCh:
+			return
+		case <-ticker.C:
+			if err := w.refresh(); err != nil {
+				log.Error(err, "Failed to refresh the global configuration, the previous one is kept", "ConfigMap", w.key.String())
+			}
+		}
+	}
+}
+
+func (w *watcher) stop() {
+	close(w.stopCh)
+	<-w.doneCh
+}
+
+// refresh reads the ConfigMap and applies its configuration if it changed, the defaults are applied if the ConfigMap doesn't exist
+func (w *watcher) refresh() error {
+	configMap := &corev1.ConfigMap{}
+	err := w.kubeClient.Get(context.TODO(), w.key, configMap)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+
+	config := DefaultConfig()
+	if err == nil {
+		if config, err = Parse(configMap.Data); err != nil {
+			return err
+		}
+	}
+
+	if w.applied != nil && reflect.DeepEqual(*w.applied, config) {
+		return nil
+	}
+	w.applied = &config
+	apply(config)
+	log.Info("Applied the global configuration", "ConfigMap", w.key.String(), "httpTimeout", config.HTTPTimeout, "watchNamespaces", config.WatchNamespaces,
+		"watchLabelSelector", config.WatchLabelSelector, "defaultCooldownPeriod", config.DefaultCooldownPeriod, "logLevel", config.LogLevel, "metricsCacheTTL", config.MetricsCacheTTL,
+		"scalerResultCacheTTL", config.ScalerResultCacheTTL, "maxScaledObjectsPerNamespace", config.MaxScaledObjectsPerNamespace, "maxTriggersPerObject", config.MaxTriggersPerObject, "bannedTriggerTypes", config.BannedTriggerTypes,
+		"commandTriggerImages", config.CommandTriggerImages, "commandTriggerServiceAccounts", config.CommandTriggerServiceAccounts)
+	return nil
+}
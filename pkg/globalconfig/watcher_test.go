@@ -0,0 +1,96 @@
+package globalconfig
+
+import (
+	"context"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestWatcherRefresh(t *testing.T) {
+	defer apply(DefaultConfig())
+	defer func(previous []func(Config)) { listeners = previous }(listeners)
+
+	level := zap.NewAtomicLevelAt(zapcore.InfoLevel)
+	BindLogLevel(level)
+	notified := 0
+	OnChange(func(Config) { notified++ })
+
+	key := types.NamespacedName{Namespace: "keda", Name: "keda-config"}
+	kubeClient := fake.NewFakeClientWithScheme(scheme.Scheme)
+	w := newWatcher(kubeClient, key)
+
+	// no ConfigMap, the defaults are applied
+	if err := w.refresh(); err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	if Get().DefaultCooldownPeriod != defaultCooldownPeriod || notified != 1 {
+		t.Errorf("Expected the defaults to be applied once, got %+v notified %d times", Get(), notified)
+	}
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name},
+		Data:       map[string]string{"defaultCooldownPeriod": "60", "logLevel": "debug", "watchNamespaces": "apps"},
+	}
+	if err := kubeClient.Create(context.TODO(), configMap); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.refresh(); err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	if Get().DefaultCooldownPeriod != 60 || !Get().IsNamespaceWatched("apps") || Get().IsNamespaceWatched("default") {
+		t.Errorf("Expected the ConfigMap to be applied, got %+v", Get())
+	}
+	if level.Level() != zapcore.DebugLevel {
+		t.Errorf("Expected the debug log level, got %s", level.Level())
+	}
+
+	// unchanged ConfigMap, the listeners aren't notified
+	if err := w.refresh(); err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	if notified != 2 {
+		t.Errorf("Expected 2 notifications, got %d", notified)
+	}
+
+	// invalid ConfigMap, the previous configuration is kept
+	configMap.Data["defaultCooldownPeriod"] = "soon"
+	if err := kubeClient.Update(context.TODO(), configMap); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.refresh(); err == nil {
+		t.Error("Expected an error for an invalid ConfigMap")
+	}
+	if Get().DefaultCooldownPeriod != 60 {
+		t.Errorf("Expected the previous configuration to be kept, got %+v", Get())
+	}
+
+	// no log level, the initial level is restored
+	configMap.Data = map[string]string{}
+	if err := kubeClient.Update(context.TODO(), configMap); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.refresh(); err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	if level.Level() != zapcore.InfoLevel {
+		t.Errorf("Expected the initial log level, got %s", level.Level())
+	}
+}
+
+func TestStartInvalidConfigMap(t *testing.T) {
+	if _, err := Start("keda-config", nil); err == nil {
+		t.Error("Expected an error for a ConfigMap without namespace")
+	}
+	stop, err := Start("", nil)
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	stop()
+}
@@ -0,0 +1,107 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/label"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// The metrics of the operator are registered in the registry of controller-runtime,
+// they are served with the metrics of the controllers on the metrics-addr of the manager
+// and pushed to the OTLP collector if InitOTLP configured one
+var (
+	scalerLatency = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "keda_operator",
+			Subsystem: "scaler",
+			Name:      "latency_seconds",
+			Help:      "Latency of the calls to the scalers by the operator",
+			Buckets:   prometheus.ExponentialBuckets(0.005, 2, 14),
+		},
+		[]string{"scaler", "operation"},
+	)
+	scalerCallErrors = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "keda_operator",
+			Subsystem: "scaler",
+			Name:      "errors_total",
+			Help:      "Number of failed calls to the scalers by the operator",
+		},
+		[]string{"scaler", "operation"},
+	)
+	reconcileDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "keda_operator",
+			Subsystem: "reconcile",
+			Name:      "duration_seconds",
+			Help:      "Duration of the reconciliations of the KEDA resources",
+			Buckets:   prometheus.DefBuckets,
+		},
+		[]string{"kind"},
+	)
+	scaleLoopDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "keda_operator",
+			Subsystem: "scale_loop",
+			Name:      "duration_seconds",
+			Help:      "Duration of the evaluations of the triggers by the scale loops",
+			Buckets:   prometheus.DefBuckets,
+		},
+		[]string{"kind"},
+	)
+	resourceErrors = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "keda_operator",
+			Subsystem: "resource",
+			Name:      "errors_total",
+			Help:      "Number of failed reconciliations and trigger evaluations of a KEDA resource",
+		},
+		[]string{"kind", "namespace", "name"},
+	)
+)
+
+// Operations of the scalers measured by RecordScalerCall
+const (
+	ScalerOperationIsActive   = "is_active"
+	ScalerOperationGetMetrics = "get_metrics"
+)
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(scalerLatency, scalerCallErrors, reconcileDuration, scaleLoopDuration, resourceErrors, tokenRefreshes, tokenRefreshErrors)
+}
+
+// RecordScalerCall measures a call of the operator to the IsActive or GetMetrics function of a scaler
+func RecordScalerCall(scaler string, operation string, start time.Time, err error) {
+	duration := time.Since(start)
+	scalerLatency.WithLabelValues(scaler, operation).Observe(duration.Seconds())
+	otelScalerLatency.Record(context.Background(), milliseconds(duration), label.String("scaler", scaler), label.String("operation", operation))
+	if err != nil {
+		scalerCallErrors.WithLabelValues(scaler, operation).Inc()
+	}
+}
+
+// RecordReconcile measures a reconciliation of a ScaledObject or ScaledJob and counts it per resource if it failed
+func RecordReconcile(kind string, namespace string, name string, start time.Time, err error) {
+	duration := time.Since(start)
+	reconcileDuration.WithLabelValues(kind).Observe(duration.Seconds())
+	otelReconcileDuration.Record(context.Background(), milliseconds(duration), label.String("kind", kind))
+	if err != nil {
+		RecordResourceError(kind, namespace, name)
+	}
+}
+
+// RecordScaleLoop measures an evaluation of the triggers of a ScaledObject or ScaledJob by its scale loop
+func RecordScaleLoop(kind string, start time.Time) {
+	duration := time.Since(start)
+	scaleLoopDuration.WithLabelValues(kind).Observe(duration.Seconds())
+	otelScaleLoopDuration.Record(context.Background(), milliseconds(duration), label.String("kind", kind))
+}
+
+// RecordResourceError counts an error of a KEDA resource, a failed reconciliation or trigger evaluation
+func RecordResourceError(kind string, namespace string, name string) {
+	resourceErrors.WithLabelValues(kind, namespace, name).Inc()
+	otelResourceErrors.Add(context.Background(), 1, label.String("kind", kind), label.String("namespace", namespace), label.String("name", name))
+}
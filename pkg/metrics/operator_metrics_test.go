@@ -0,0 +1,53 @@
+package metrics
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestRecordScalerCall(t *testing.T) {
+	RecordScalerCall("testRecordScaler", ScalerOperationIsActive, time.Now(), nil)
+	RecordScalerCall("testRecordScaler", ScalerOperationIsActive, time.Now(), fmt.Errorf("timeout"))
+
+	if count := testutil.CollectAndCount(scalerLatency); count == 0 {
+		t.Error("Expected the latency of the scaler to be recorded")
+	}
+	if errors := testutil.ToFloat64(scalerCallErrors.WithLabelValues("testRecordScaler", ScalerOperationIsActive)); errors != 1 {
+		t.Errorf("Expected 1 error, got %v", errors)
+	}
+}
+
+func TestRecordReconcile(t *testing.T) {
+	RecordReconcile("ScaledObject", "default", "test", time.Now(), nil)
+	if errors := testutil.ToFloat64(resourceErrors.WithLabelValues("ScaledObject", "default", "test")); errors != 0 {
+		t.Errorf("Expected no error for a successful reconciliation, got %v", errors)
+	}
+
+	RecordReconcile("ScaledObject", "default", "test", time.Now(), fmt.Errorf("failed"))
+	RecordResourceError("ScaledObject", "default", "test")
+	if errors := testutil.ToFloat64(resourceErrors.WithLabelValues("ScaledObject", "default", "test")); errors != 2 {
+		t.Errorf("Expected 2 errors, got %v", errors)
+	}
+}
+
+func TestRecordScaleLoop(t *testing.T) {
+	RecordScaleLoop("ScaledJob", time.Now())
+	if count := testutil.CollectAndCount(scaleLoopDuration); count == 0 {
+		t.Error("Expected the duration of the scale loop to be recorded")
+	}
+}
+
+func TestRecordTokenRefresh(t *testing.T) {
+	RecordTokenRefresh("testTokenSource", TokenRefreshReasonProactive, nil)
+	RecordTokenRefresh("testTokenSource", TokenRefreshReasonProactive, fmt.Errorf("unauthorized"))
+
+	if refreshes := testutil.ToFloat64(tokenRefreshes.WithLabelValues("testTokenSource", TokenRefreshReasonProactive)); refreshes != 2 {
+		t.Errorf("Expected 2 token refreshes, got %v", refreshes)
+	}
+	if errors := testutil.ToFloat64(tokenRefreshErrors.WithLabelValues("testTokenSource", TokenRefreshReasonProactive)); errors != 1 {
+		t.Errorf("Expected 1 token refresh error, got %v", errors)
+	}
+}
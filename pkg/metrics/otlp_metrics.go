@@ -0,0 +1,133 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/otel/api/global"
+	"go.opentelemetry.io/otel/api/metric"
+	"go.opentelemetry.io/otel/api/unit"
+	"go.opentelemetry.io/otel/exporters/otlp"
+	"go.opentelemetry.io/otel/label"
+	export "go.opentelemetry.io/otel/sdk/export/metric"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/minmaxsumcount"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/sum"
+	"go.opentelemetry.io/otel/sdk/metric/controller/push"
+	"go.opentelemetry.io/otel/sdk/metric/processor/basic"
+	"go.opentelemetry.io/otel/sdk/resource"
+	"go.opentelemetry.io/otel/semconv"
+
+	"github.com/kedacore/keda/version"
+)
+
+const meterName = "github.com/kedacore/keda"
+
+// OTLPConfig holds the configuration of the OTLP exporter the metrics are pushed to
+type OTLPConfig struct {
+	// Endpoint is the address of the OTLP collector, the metrics aren't pushed if empty
+	Endpoint string
+	// Insecure disables the client transport security of the exporter
+	Insecure bool
+	// Period is the interval the metrics are pushed at
+	Period time.Duration
+	// ServiceName identifies the KEDA component in the metrics
+	ServiceName string
+}
+
+// The instruments are created on the global meter, they don't record anything until InitOTLP installs a meter provider
+var (
+	otelMeter              = metric.Must(global.Meter(meterName))
+	otelScalerMetricsValue = otelMeter.NewFloat64ValueRecorder("keda.scaler.metrics.value",
+		metric.WithDescription("Metric value of the scalers served to the HPA"))
+	otelScalerErrors = otelMeter.NewInt64Counter("keda.scaler.errors",
+		metric.WithDescription("Number of errors of the scalers"))
+	otelScalerLatency = otelMeter.NewFloat64ValueRecorder("keda.scaler.latency",
+		metric.WithDescription("Latency of the calls to the scalers"), metric.WithUnit(unit.Milliseconds))
+	otelReconcileDuration = otelMeter.NewFloat64ValueRecorder("keda.reconcile.duration",
+		metric.WithDescription("Duration of the reconciliations of the KEDA resources"), metric.WithUnit(unit.Milliseconds))
+	otelScaleLoopDuration = otelMeter.NewFloat64ValueRecorder("keda.scale_loop.duration",
+		metric.WithDescription("Duration of the evaluations of the triggers by the scale loops"), metric.WithUnit(unit.Milliseconds))
+	otelResourceErrors = otelMeter.NewInt64Counter("keda.resource.errors",
+		metric.WithDescription("Number of failed reconciliations and trigger evaluations of the KEDA resources"))
+)
+
+// InitOTLP pushes the metrics of KEDA to the OTLP collector, for the environments without Prometheus.
+// The returned function pushes the pending metrics and stops the exporter.
+// If no endpoint is configured the metrics are only served to Prometheus.
+func InitOTLP(config OTLPConfig) (func(), error) {
+	if config.Endpoint == "" {
+		return func() {}, nil
+	}
+	if config.Period <= 0 {
+		return nil, fmt.Errorf("OTLP metrics push period must be greater than 0, got %s", config.Period)
+	}
+
+	opts := []otlp.ExporterOption{otlp.WithAddress(config.Endpoint)}
+	if config.Insecure {
+		opts = append(opts, otlp.WithInsecure())
+	}
+	exporter, err := otlp.NewExporter(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("error creating OTLP exporter: %s", err)
+	}
+
+	pusher := push.New(
+		basic.New(otlpAggregatorSelector{}, exporter),
+		exporter,
+		push.WithPeriod(config.Period),
+		push.WithResource(resource.New(
+			semconv.ServiceNameKey.String(config.ServiceName),
+			semconv.ServiceVersionKey.String(version.Version),
+		)),
+	)
+	pusher.Start()
+	global.SetMeterProvider(pusher.Provider())
+
+	return func() {
+		pusher.Stop()
+		_ = exporter.Stop()
+	}, nil
+}
+
+// otlpAggregatorSelector aggregates the value recorders with min, max, sum and count and the counters with a sum,
+// the aggregations the OTLP exporter supports
+type otlpAggregatorSelector struct{}
+
+func (otlpAggregatorSelector) AggregatorFor(descriptor *metric.Descriptor, aggPtrs ...*export.Aggregator) {
+	switch descriptor.MetricKind() {
+	case metric.ValueObserverKind, metric.ValueRecorderKind:
+		aggs := minmaxsumcount.New(len(aggPtrs), descriptor)
+		for i := range aggPtrs {
+			*aggPtrs[i] = &aggs[i]
+		}
+	default:
+		aggs := sum.New(len(aggPtrs))
+		for i := range aggPtrs {
+			*aggPtrs[i] = &aggs[i]
+		}
+	}
+}
+
+func otelScalerLabels(namespace string, scaledObject string, scaler string, scalerIndex int, metric string) []label.KeyValue {
+	return []label.KeyValue{
+		label.String("namespace", namespace),
+		label.String("scaledObject", scaledObject),
+		label.String("scaler", scaler),
+		label.String("scalerIndex", strconv.Itoa(scalerIndex)),
+		label.String("metric", metric),
+	}
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+func otelRecordScalerMetric(namespace string, scaledObject string, scaler string, scalerIndex int, metric string, value int64) {
+	otelScalerMetricsValue.Record(context.Background(), float64(value), otelScalerLabels(namespace, scaledObject, scaler, scalerIndex, metric)...)
+}
+
+func otelRecordScalerError(namespace string, scaledObject string, scaler string, scalerIndex int, metric string) {
+	otelScalerErrors.Add(context.Background(), 1, otelScalerLabels(namespace, scaledObject, scaler, scalerIndex, metric)...)
+}
@@ -0,0 +1,45 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/api/metric"
+	export "go.opentelemetry.io/otel/sdk/export/metric"
+	"go.opentelemetry.io/otel/sdk/export/metric/aggregation"
+)
+
+func TestInitOTLPWithoutEndpoint(t *testing.T) {
+	shutdown, err := InitOTLP(OTLPConfig{ServiceName: "keda-test"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	shutdown()
+}
+
+func TestInitOTLPInvalidPeriod(t *testing.T) {
+	for _, period := range []time.Duration{0, -time.Second} {
+		if _, err := InitOTLP(OTLPConfig{Endpoint: "localhost:55680", Period: period}); err == nil {
+			t.Errorf("Expected an error for the period %s", period)
+		}
+	}
+}
+
+func TestOTLPAggregatorSelector(t *testing.T) {
+	tests := []struct {
+		kind     metric.Kind
+		expected aggregation.Kind
+	}{
+		{metric.ValueRecorderKind, aggregation.MinMaxSumCountKind},
+		{metric.CounterKind, aggregation.SumKind},
+	}
+
+	for _, test := range tests {
+		descriptor := metric.NewDescriptor("test", test.kind, metric.Float64NumberKind)
+		var aggregator export.Aggregator
+		otlpAggregatorSelector{}.AggregatorFor(&descriptor, &aggregator)
+		if aggregator.Aggregation().Kind() != test.expected {
+			t.Errorf("Expected %s for %s, got %s", test.expected, test.kind, aggregator.Aggregation().Kind())
+		}
+	}
+}
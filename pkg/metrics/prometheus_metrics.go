@@ -60,6 +60,8 @@ func init() {
 	registry.MustRegister(scalerMetricsValue)
 	registry.MustRegister(scalerErrors)
 	registry.MustRegister(scaledObjectErrors)
+	registry.MustRegister(tokenRefreshes)
+	registry.MustRegister(tokenRefreshErrors)
 }
 
 // NewServer creates a new http serving instance of prometheus metrics
@@ -80,6 +82,7 @@ func (metricsServer PrometheusMetricServer) NewServer(address string, pattern st
 // RecordHPAScalerMetric create a measurement of the external metric used by the HPA
 func (metricsServer PrometheusMetricServer) RecordHPAScalerMetric(namespace string, scaledObject string, scaler string, scalerIndex int, metric string, value int64) {
 	scalerMetricsValue.With(getLabels(namespace, scaledObject, scaler, scalerIndex, metric)).Set(float64(value))
+	otelRecordScalerMetric(namespace, scaledObject, scaler, scalerIndex, metric, value)
 }
 
 // RecordHPAScalerError counts the number of errors occurred in trying get an external metric used by the HPA
@@ -89,6 +92,7 @@ func (metricsServer PrometheusMetricServer) RecordHPAScalerError(namespace strin
 		// scaledObjectErrors.With(prometheus.Labels{"namespace": namespace, "scaledObject": scaledObject}).Inc()
 		metricsServer.RecordScalerObjectError(namespace, scaledObject, err)
 		scalerErrorsTotal.With(prometheus.Labels{}).Inc()
+		otelRecordScalerError(namespace, scaledObject, scaler, scalerIndex, metric)
 		return
 	}
 	// initialize metric with 0 if not already set
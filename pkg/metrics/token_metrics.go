@@ -0,0 +1,58 @@
+package metrics
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/api/metric"
+	"go.opentelemetry.io/otel/label"
+)
+
+// Reasons of the token requests counted by RecordTokenRefresh
+const (
+	// TokenRefreshReasonOnDemand is a request made by a check as there is no valid token
+	TokenRefreshReasonOnDemand = "on_demand"
+	// TokenRefreshReasonProactive is a renewal in the background before the token expires
+	TokenRefreshReasonProactive = "proactive"
+	// TokenRefreshReasonRejected is a request made as the upstream API rejected the token
+	TokenRefreshReasonRejected = "rejected"
+)
+
+// The token metrics are recorded by the scalers of both the operator and the metrics adapter,
+// they are registered in the registries of both
+var (
+	tokenRefreshes = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "keda",
+			Subsystem: "token",
+			Name:      "refreshes_total",
+			Help:      "Number of the tokens requested by the scalers from their identity provider",
+		},
+		[]string{"source", "reason"},
+	)
+	tokenRefreshErrors = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "keda",
+			Subsystem: "token",
+			Name:      "refresh_errors_total",
+			Help:      "Number of the failed token requests of the scalers",
+		},
+		[]string{"source", "reason"},
+	)
+
+	otelTokenRefreshes = otelMeter.NewInt64Counter("keda.token.refreshes",
+		metric.WithDescription("Number of the tokens requested by the scalers from their identity provider"))
+	otelTokenRefreshErrors = otelMeter.NewInt64Counter("keda.token.refresh_errors",
+		metric.WithDescription("Number of the failed token requests of the scalers"))
+)
+
+// RecordTokenRefresh counts a token request of a scaler to the identity provider named by source, and its failure if err isn't nil
+func RecordTokenRefresh(source string, reason string, err error) {
+	labels := []label.KeyValue{label.String("source", source), label.String("reason", reason)}
+	tokenRefreshes.WithLabelValues(source, reason).Inc()
+	otelTokenRefreshes.Add(context.Background(), 1, labels...)
+	if err != nil {
+		tokenRefreshErrors.WithLabelValues(source, reason).Inc()
+		otelTokenRefreshErrors.Add(context.Background(), 1, labels...)
+	}
+}
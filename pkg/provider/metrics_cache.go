@@ -0,0 +1,112 @@
+package provider
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/metrics/pkg/apis/external_metrics"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+	"github.com/kedacore/keda/pkg/globalconfig"
+)
+
+// Default polling interval of a ScaledObject, used as the cache TTL of the triggers without metricsCacheTTL
+const defaultPollingInterval = 30
+
+// metricsCache keeps the metric values served to the HPA for the triggers with useCachedMetrics,
+// so expensive queries of the scalers aren't issued on every sync of the HPA
+type metricsCache struct {
+	mutex   sync.Mutex
+	entries map[string]metricsCacheEntry
+	now     func() time.Time
+}
+
+type metricsCacheEntry struct {
+	// generation of the ScaledObject the metrics are cached for, a change of the spec invalidates the entry
+	generation int64
+	metrics    []external_metrics.ExternalMetricValue
+	expiresAt  time.Time
+}
+
+func newMetricsCache() *metricsCache {
+	return &metricsCache{
+		entries: make(map[string]metricsCacheEntry),
+		now:     time.Now,
+	}
+}
+
+func metricsCacheKey(scaledObject *kedav1alpha1.ScaledObject, metricName string) string {
+	return metricsCacheKeyPrefix(scaledObject.Namespace, scaledObject.Name) + strings.ToLower(metricName)
+}
+
+func metricsCacheKeyPrefix(namespace, name string) string {
+	return fmt.Sprintf("%s/%s/", namespace, name)
+}
+
+// get returns the cached metrics of the ScaledObject if they aren't expired
+func (c *metricsCache) get(scaledObject *kedav1alpha1.ScaledObject, metricName string) ([]external_metrics.ExternalMetricValue, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	key := metricsCacheKey(scaledObject, metricName)
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if entry.generation != scaledObject.Generation || !c.now().Before(entry.expiresAt) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return entry.metrics, true
+}
+
+// set caches the metrics of the ScaledObject for the ttl
+func (c *metricsCache) set(scaledObject *kedav1alpha1.ScaledObject, metricName string, metrics []external_metrics.ExternalMetricValue, ttl time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.entries[metricsCacheKey(scaledObject, metricName)] = metricsCacheEntry{
+		generation: scaledObject.Generation,
+		metrics:    metrics,
+		expiresAt:  c.now().Add(ttl),
+	}
+}
+
+// evict removes the cached metrics of all the metrics of the ScaledObject
+func (c *metricsCache) evict(namespace, name string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	prefix := metricsCacheKeyPrefix(namespace, name)
+	for key := range c.entries {
+		if strings.HasPrefix(key, prefix) {
+			delete(c.entries, key)
+		}
+	}
+}
+
+// getMetricsCacheTTL returns how long the metric values of the trigger are cached, 0 if the trigger doesn't use cached metrics
+func getMetricsCacheTTL(scaledObject *kedav1alpha1.ScaledObject, triggerIndex int) time.Duration {
+	if triggerIndex >= len(scaledObject.Spec.Triggers) {
+		return 0
+	}
+	trigger := scaledObject.Spec.Triggers[triggerIndex]
+	if !trigger.UseCachedMetrics {
+		return 0
+	}
+	if trigger.MetricsCacheTTL != nil {
+		if *trigger.MetricsCacheTTL <= 0 {
+			return 0
+		}
+		return time.Second * time.Duration(*trigger.MetricsCacheTTL)
+	}
+	if ttl := globalconfig.Get().MetricsCacheTTL; ttl > 0 {
+		return ttl
+	}
+	if scaledObject.Spec.PollingInterval != nil {
+		return time.Second * time.Duration(*scaledObject.Spec.PollingInterval)
+	}
+	return time.Second * time.Duration(defaultPollingInterval)
+}
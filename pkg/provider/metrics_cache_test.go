@@ -0,0 +1,110 @@
+package provider
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8scache "k8s.io/client-go/tools/cache"
+	"k8s.io/metrics/pkg/apis/external_metrics"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+)
+
+func int32Pointer(i int32) *int32 {
+	return &i
+}
+
+type metricsCacheTTLTestData struct {
+	trigger         kedav1alpha1.ScaleTriggers
+	pollingInterval *int32
+	expected        time.Duration
+}
+
+var metricsCacheTTLTestDataset = []metricsCacheTTLTestData{
+	{trigger: kedav1alpha1.ScaleTriggers{}, expected: 0},
+	{trigger: kedav1alpha1.ScaleTriggers{MetricsCacheTTL: int32Pointer(60)}, expected: 0},
+	{trigger: kedav1alpha1.ScaleTriggers{UseCachedMetrics: true}, expected: 30 * time.Second},
+	{trigger: kedav1alpha1.ScaleTriggers{UseCachedMetrics: true}, pollingInterval: int32Pointer(10), expected: 10 * time.Second},
+	{trigger: kedav1alpha1.ScaleTriggers{UseCachedMetrics: true, MetricsCacheTTL: int32Pointer(60)}, pollingInterval: int32Pointer(10), expected: 60 * time.Second},
+	{trigger: kedav1alpha1.ScaleTriggers{UseCachedMetrics: true, MetricsCacheTTL: int32Pointer(0)}, expected: 0},
+}
+
+func TestGetMetricsCacheTTL(t *testing.T) {
+	for i, testData := range metricsCacheTTLTestDataset {
+		scaledObject := &kedav1alpha1.ScaledObject{
+			Spec: kedav1alpha1.ScaledObjectSpec{
+				PollingInterval: testData.pollingInterval,
+				Triggers:        []kedav1alpha1.ScaleTriggers{testData.trigger},
+			},
+		}
+		if ttl := getMetricsCacheTTL(scaledObject, 0); ttl != testData.expected {
+			t.Errorf("Test #%d: expected TTL %s, got %s", i, testData.expected, ttl)
+		}
+	}
+
+	if ttl := getMetricsCacheTTL(&kedav1alpha1.ScaledObject{}, 1); ttl != 0 {
+		t.Errorf("Expected TTL 0 for a missing trigger, got %s", ttl)
+	}
+}
+
+func TestMetricsCache(t *testing.T) {
+	now := time.Now()
+	cache := newMetricsCache()
+	cache.now = func() time.Time { return now }
+
+	scaledObject := &kedav1alpha1.ScaledObject{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "app", Generation: 1}}
+	metrics := []external_metrics.ExternalMetricValue{{MetricName: "queueLength"}}
+
+	if _, ok := cache.get(scaledObject, "queueLength"); ok {
+		t.Error("Expected no cached metrics before any is set")
+	}
+
+	cache.set(scaledObject, "queueLength", metrics, 30*time.Second)
+	if cached, ok := cache.get(scaledObject, "QueueLength"); !ok || len(cached) != 1 {
+		t.Errorf("Expected cached metrics, got %v", cached)
+	}
+	if _, ok := cache.get(scaledObject, "otherMetric"); ok {
+		t.Error("Expected no cached metrics for another metric")
+	}
+
+	now = now.Add(30 * time.Second)
+	if _, ok := cache.get(scaledObject, "queueLength"); ok {
+		t.Error("Expected the cached metrics to be expired")
+	}
+
+	cache.set(scaledObject, "queueLength", metrics, 30*time.Second)
+	updated := scaledObject.DeepCopy()
+	updated.Generation = 2
+	if _, ok := cache.get(updated, "queueLength"); ok {
+		t.Error("Expected the cached metrics to be invalidated by a new generation")
+	}
+}
+
+func TestMetricsCacheEvict(t *testing.T) {
+	cache := newMetricsCache()
+	p := &KedaProvider{metricsCache: cache}
+
+	scaledObject := &kedav1alpha1.ScaledObject{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "app", Generation: 1}}
+	other := &kedav1alpha1.ScaledObject{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "app-2", Generation: 1}}
+	metrics := []external_metrics.ExternalMetricValue{{MetricName: "queueLength"}}
+	cache.set(scaledObject, "queueLength", metrics, time.Minute)
+	cache.set(scaledObject, "queueLength/1", metrics, time.Minute)
+	cache.set(other, "queueLength", metrics, time.Minute)
+
+	p.evictScaledObjectMetrics(scaledObject)
+	if _, ok := cache.get(scaledObject, "queueLength"); ok {
+		t.Error("Expected the cached metrics of the deleted ScaledObject to be evicted")
+	}
+	if _, ok := cache.get(scaledObject, "queueLength/1"); ok {
+		t.Error("Expected the cached metrics of all the triggers of the deleted ScaledObject to be evicted")
+	}
+	if _, ok := cache.get(other, "queueLength"); !ok {
+		t.Error("Expected the cached metrics of another ScaledObject to be kept")
+	}
+
+	p.evictScaledObjectMetrics(k8scache.DeletedFinalStateUnknown{Key: "default/app-2", Obj: other})
+	if len(cache.entries) != 0 {
+		t.Errorf("Expected the cached metrics of a ScaledObject deleted while disconnected to be evicted, got %v", cache.entries)
+	}
+}
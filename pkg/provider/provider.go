@@ -3,17 +3,27 @@ package provider
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"strings"
+	"time"
 
 	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+	"github.com/kedacore/keda/pkg/audit"
+	"github.com/kedacore/keda/pkg/globalconfig"
 	prommetrics "github.com/kedacore/keda/pkg/metrics"
+	"github.com/kedacore/keda/pkg/scalers"
 	"github.com/kedacore/keda/pkg/scaling"
+	"github.com/kedacore/keda/pkg/tracing"
+	kedautil "github.com/kedacore/keda/pkg/util"
 
 	"github.com/go-logr/logr"
 	"github.com/kubernetes-incubator/custom-metrics-apiserver/pkg/provider"
+	"go.opentelemetry.io/otel/label"
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
 	apiErrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
 	"k8s.io/metrics/pkg/apis/custom_metrics"
 	"k8s.io/metrics/pkg/apis/external_metrics"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -21,11 +31,12 @@ import (
 
 // KedaProvider implements External Metrics Provider
 type KedaProvider struct {
-	client           client.Client
-	values           map[provider.CustomMetricInfo]int64
-	externalMetrics  []externalMetric
-	scaleHandler     scaling.ScaleHandler
-	watchedNamespace string
+	client            client.Client
+	values            map[provider.CustomMetricInfo]int64
+	externalMetrics   []externalMetric
+	scaleHandler      scaling.ScaleHandler
+	watchedNamespaces []string
+	metricsCache      *metricsCache
 }
 type externalMetric struct {
 	info   provider.ExternalMetricInfo
@@ -36,20 +47,38 @@ type externalMetric struct {
 var logger logr.Logger
 var metricsServer prommetrics.PrometheusMetricServer
 
-// NewProvider returns an instance of KedaProvider
-func NewProvider(adapterLogger logr.Logger, scaleHandler scaling.ScaleHandler, client client.Client, watchedNamespace string) provider.MetricsProvider {
+// NewProvider returns an instance of KedaProvider, watchedNamespace is a comma-separated list of namespaces, all the namespaces if empty.
+// The cached metrics of a ScaledObject are evicted when the informer reports its deletion
+func NewProvider(adapterLogger logr.Logger, scaleHandler scaling.ScaleHandler, client client.Client, watchedNamespace string, scaledObjectInformer cache.SharedInformer) provider.MetricsProvider {
 	provider := &KedaProvider{
-		values:           make(map[provider.CustomMetricInfo]int64),
-		externalMetrics:  make([]externalMetric, 2, 10),
-		client:           client,
-		scaleHandler:     scaleHandler,
-		watchedNamespace: watchedNamespace,
+		values:            make(map[provider.CustomMetricInfo]int64),
+		externalMetrics:   make([]externalMetric, 2, 10),
+		client:            client,
+		scaleHandler:      scaleHandler,
+		watchedNamespaces: kedautil.ParseWatchNamespaces(watchedNamespace),
+		metricsCache:      newMetricsCache(),
 	}
+	scaledObjectInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		DeleteFunc: provider.evictScaledObjectMetrics,
+	})
 	logger = adapterLogger.WithName("provider")
 	logger.Info("starting")
 	return provider
 }
 
+// evictScaledObjectMetrics removes the cached metrics of a deleted ScaledObject, so they aren't kept for the lifetime of the adapter
+// nor served to a ScaledObject created again with the same name
+func (p *KedaProvider) evictScaledObjectMetrics(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	scaledObject, ok := obj.(*kedav1alpha1.ScaledObject)
+	if !ok {
+		return
+	}
+	p.metricsCache.evict(scaledObject.Namespace, scaledObject.Name)
+}
+
 // GetExternalMetric retrieves metrics from the scalers
 // Metric is normally identified by a name and a set of labels/tags. It is up to a specific
 // implementation how to translate metricSelector to a filter for metric values.
@@ -59,47 +88,85 @@ func (p *KedaProvider) GetExternalMetric(namespace string, metricSelector labels
 	//		metric name and namespace is used to lookup for the CRD which contains configuration to call azure
 	// 		if not found then ignored and label selector is parsed for all the metrics
 	logger.V(1).Info("Keda provider received request for external metrics", "namespace", namespace, "metric name", info.Metric, "metricSelector", metricSelector.String())
+	ctx, span := tracing.StartSpan(context.TODO(), "Provider.GetExternalMetric", label.String("keda.namespace", namespace), label.String("keda.metric", info.Metric))
+	defer span.End()
+
+	if !p.isNamespaceWatched(namespace) || !globalconfig.Get().IsNamespaceWatched(namespace) {
+		return nil, fmt.Errorf("namespace %s isn't watched by KEDA", namespace)
+	}
+
 	selector, err := labels.ConvertSelectorToLabelsMap(metricSelector.String())
 	if err != nil {
 		logger.Error(err, "Error converting Selector to Labels Map")
 		return nil, err
 	}
 
-	//get the scaled objects matching namespace and labels
-	scaledObjects := &kedav1alpha1.ScaledObjectList{}
-	opts := []client.ListOption{
-		client.InNamespace(namespace),
-		client.MatchingLabels(selector),
+	scaledObject, err := p.getScaledObject(ctx, namespace, selector)
+	if err != nil {
+		return nil, err
 	}
-	err = p.client.List(context.TODO(), scaledObjects, opts...)
+	triggerIndex, hasTriggerIndex, err := getTriggerIndex(selector)
 	if err != nil {
 		return nil, err
-	} else if len(scaledObjects.Items) != 1 {
-		return nil, fmt.Errorf("Exactly one scaled object should match label %s", metricSelector.String())
+	}
+	metricKey := info.Metric
+	if hasTriggerIndex {
+		metricKey = fmt.Sprintf("%s/%d", info.Metric, triggerIndex)
+	}
+	config := globalconfig.Get()
+	if !config.IsWatched(scaledObject.Namespace, scaledObject.Labels) {
+		return nil, fmt.Errorf("scaled object %s isn't watched by KEDA", scaledObject.Name)
+	}
+	if err := config.CheckTriggers(scaledObject.Spec.Triggers); err != nil {
+		return nil, fmt.Errorf("scaled object %s violates the scaling policy: %s", scaledObject.Name, err)
+	}
+	if metrics, ok := p.metricsCache.get(scaledObject, metricKey); ok {
+		logger.V(1).Info("Serving cached metrics", "scaledObject.Namespace", scaledObject.Namespace, "scaledObject.Name", scaledObject.Name, "metric name", info.Metric)
+		return &external_metrics.ExternalMetricValueList{
+			Items: metrics,
+		}, nil
 	}
 
-	scaledObject := &scaledObjects.Items[0]
 	matchingMetrics := []external_metrics.ExternalMetricValue{}
-	scalers, err := p.scaleHandler.GetScalers(scaledObject)
+	// the metrics are only cached if all the triggers serving the metric use cached metrics and none failed
+	cacheable := true
+	var cacheTTL time.Duration
+	scalersList, err := p.scaleHandler.GetScalers(scaledObject)
 	metricsServer.RecordScalerObjectError(scaledObject.Namespace, scaledObject.Name, err)
 	if err != nil {
 		return nil, fmt.Errorf("Error when getting scalers %s", err)
 	}
 
-	for scalerIndex, scaler := range scalers {
+	for scalerIndex, scaler := range scalersList {
+		// the metric of a trigger is only served by this trigger, the other triggers may have a metric with the same name
+		if hasTriggerIndex && scalerIndex != triggerIndex {
+			scaler.Close()
+			continue
+		}
 		metricSpecs := scaler.GetMetricSpecForScaling()
-		scalerName := strings.Replace(fmt.Sprintf("%T", scaler), "*scalers.", "", 1)
+		scalerName := scalers.ScalerName(scaler)
 
 		for _, metricSpec := range metricSpecs {
 			// Filter only the desired metric
-			if strings.EqualFold(metricSpec.External.Metric.Name, info.Metric) {
-				metrics, err := scaler.GetMetrics(context.TODO(), info.Metric, metricSelector)
+			if metricSpec.External != nil && strings.EqualFold(metricSpec.External.Metric.Name, info.Metric) {
+				ttl := getMetricsCacheTTL(scaledObject, scalerIndex)
+				if ttl == 0 {
+					cacheable = false
+				} else if cacheTTL == 0 || ttl < cacheTTL {
+					cacheTTL = ttl
+				}
+
+				metricsCtx, span := tracing.StartSpan(ctx, "Scaler.GetMetrics", tracing.ScalerAttribute(scalerName), label.String("keda.metric", info.Metric))
+				metrics, err := scaler.GetMetrics(metricsCtx, info.Metric, metricSelector)
+				tracing.EndSpan(metricsCtx, span, err)
 				if err != nil {
 					logger.Error(err, "error getting metric for scaler", "scaledObject.Namespace", scaledObject.Namespace, "scaledObject.Name", scaledObject.Name, "scaler", scaler)
+					cacheable = false
 				} else {
 					for _, metric := range metrics {
 						metricValue, _ := metric.Value.AsInt64()
 						metricsServer.RecordHPAScalerMetric(namespace, scaledObject.Name, scalerName, scalerIndex, metric.MetricName, metricValue)
+						recordMetricDecision(scaledObject, scalerIndex, metricSpec, metric.MetricName, metricValue)
 					}
 					matchingMetrics = append(matchingMetrics, metrics...)
 				}
@@ -114,35 +181,144 @@ func (p *KedaProvider) GetExternalMetric(namespace string, metricSelector labels
 		return nil, fmt.Errorf("No matching metrics found for " + info.Metric)
 	}
 
+	if cacheable {
+		p.metricsCache.set(scaledObject, metricKey, matchingMetrics, cacheTTL)
+	}
+
 	return &external_metrics.ExternalMetricValueList{
 		Items: matchingMetrics,
 	}, nil
 }
 
-// ListAllExternalMetrics returns the supported external metrics for this provider
-func (p *KedaProvider) ListAllExternalMetrics() []provider.ExternalMetricInfo {
-	externalMetricsInfo := []provider.ExternalMetricInfo{}
+// getScaledObject returns the ScaledObject identified by the labels of the metric selector. The ScaledObject is found by its name
+// and namespace, the selectors of the HPAs created before the namespace label was added are matched to the labels of the ScaledObjects
+func (p *KedaProvider) getScaledObject(ctx context.Context, namespace string, selector labels.Set) (*kedav1alpha1.ScaledObject, error) {
+	scaledObjectNamespace, hasNamespace := selector[kedav1alpha1.ScaledObjectNamespaceLabel]
+	if hasNamespace && scaledObjectNamespace != namespace {
+		return nil, fmt.Errorf("the metric selector is for the ScaledObjects of namespace %s, not %s", scaledObjectNamespace, namespace)
+	}
 
-	//get all ScaledObjects in namespace(s) watched by the operator
+	if name := selector[kedav1alpha1.ScaledObjectNameLabel]; hasNamespace && name != "" {
+		scaledObject := &kedav1alpha1.ScaledObject{}
+		if err := p.client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, scaledObject); err != nil {
+			return nil, err
+		}
+		return scaledObject, nil
+	}
+
+	//get the scaled objects matching namespace and labels
 	scaledObjects := &kedav1alpha1.ScaledObjectList{}
 	opts := []client.ListOption{
-		client.InNamespace(p.watchedNamespace),
+		client.InNamespace(namespace),
+		client.MatchingLabels(selector),
 	}
-	err := p.client.List(context.TODO(), scaledObjects, opts...)
+	err := p.client.List(ctx, scaledObjects, opts...)
 	if err != nil {
-		logger.Error(err, "Cannot get list of ScaledObjects", "WatchedNamespace", p.watchedNamespace)
-		return nil
+		return nil, err
+	} else if len(scaledObjects.Items) != 1 {
+		return nil, fmt.Errorf("Exactly one scaled object should match label %s", selector.String())
 	}
+	return &scaledObjects.Items[0], nil
+}
 
-	// get metrics from all watched ScaledObjects
-	for _, scaledObject := range scaledObjects.Items {
-		for _, metric := range scaledObject.Status.ExternalMetricNames {
-			externalMetricsInfo = append(externalMetricsInfo, provider.ExternalMetricInfo{Metric: metric})
+// getTriggerIndex returns the index of the trigger serving the metric, false if the metric selector doesn't have the trigger label
+func getTriggerIndex(selector labels.Set) (int, bool, error) {
+	val, ok := selector[kedav1alpha1.TriggerIndexLabel]
+	if !ok || val == "" {
+		return 0, false, nil
+	}
+	triggerIndex, err := strconv.Atoi(val)
+	if err != nil || triggerIndex < 0 {
+		return 0, false, fmt.Errorf("error parsing %s: %s is not a trigger index", kedav1alpha1.TriggerIndexLabel, val)
+	}
+	return triggerIndex, true, nil
+}
+
+// recordMetricDecision records the metric value served to the HPA in the audit log,
+// along with the replica count the HPA computes for the metric value and an AverageValue threshold
+func recordMetricDecision(scaledObject *kedav1alpha1.ScaledObject, scalerIndex int, metricSpec autoscalingv2beta2.MetricSpec, metricName string, metricValue int64) {
+	if !audit.Enabled() {
+		return
+	}
+
+	decision := audit.Decision{
+		Source:       audit.SourceMetricsServer,
+		Kind:         "ScaledObject",
+		Namespace:    scaledObject.Namespace,
+		Name:         scaledObject.Name,
+		TriggerIndex: &scalerIndex,
+		Metric:       metricName,
+		MetricValue:  &metricValue,
+	}
+	isAverageValue := true
+	if scalerIndex < len(scaledObject.Spec.Triggers) {
+		decision.Trigger = scaledObject.Spec.Triggers[scalerIndex].Type
+		isAverageValue = scaledObject.Spec.Triggers[scalerIndex].GetThresholdType() == autoscalingv2beta2.AverageValueMetricType
+	}
+	if metricSpec.External.Target.AverageValue != nil {
+		if threshold, ok := metricSpec.External.Target.AverageValue.AsInt64(); ok && threshold > 0 {
+			decision.Threshold = &threshold
+			// the replica count for a Value threshold depends on the current replica count, which the metrics server doesn't know
+			if isAverageValue {
+				desiredReplicas := (metricValue + threshold - 1) / threshold
+				decision.DesiredReplicas = &desiredReplicas
+			}
+		}
+	}
+	audit.Record(decision)
+}
+
+// ListAllExternalMetrics returns the supported external metrics for this provider
+func (p *KedaProvider) ListAllExternalMetrics() []provider.ExternalMetricInfo {
+	externalMetricsInfo := []provider.ExternalMetricInfo{}
+	// the ScaledObjects of different namespaces may use the same metric names
+	listed := make(map[string]bool)
+
+	//get all ScaledObjects in namespace(s) watched by the operator
+	namespaces := p.watchedNamespaces
+	if len(namespaces) == 0 {
+		// all the namespaces
+		namespaces = []string{""}
+	}
+	config := globalconfig.Get()
+	for _, namespace := range namespaces {
+		scaledObjects := &kedav1alpha1.ScaledObjectList{}
+		err := p.client.List(context.TODO(), scaledObjects, client.InNamespace(namespace))
+		if err != nil {
+			logger.Error(err, "Cannot get list of ScaledObjects", "WatchedNamespace", namespace)
+			return nil
+		}
+
+		// get metrics from all watched ScaledObjects
+		for _, scaledObject := range scaledObjects.Items {
+			if !config.IsWatched(scaledObject.Namespace, scaledObject.Labels) {
+				continue
+			}
+			for _, metric := range scaledObject.Status.ExternalMetricNames {
+				if listed[metric] {
+					continue
+				}
+				listed[metric] = true
+				externalMetricsInfo = append(externalMetricsInfo, provider.ExternalMetricInfo{Metric: metric})
+			}
 		}
 	}
 	return externalMetricsInfo
 }
 
+// isNamespaceWatched returns true if the namespace is one of the namespaces watched by the metrics server
+func (p *KedaProvider) isNamespaceWatched(namespace string) bool {
+	if len(p.watchedNamespaces) == 0 {
+		return true
+	}
+	for _, watched := range p.watchedNamespaces {
+		if watched == namespace {
+			return true
+		}
+	}
+	return false
+}
+
 // GetMetricByName fetches a particular metric for a particular object.
 // The namespace will be empty if the metric is root-scoped.
 func (p *KedaProvider) GetMetricByName(name types.NamespacedName, info provider.CustomMetricInfo, metricSelector labels.Selector) (*custom_metrics.MetricValue, error) {
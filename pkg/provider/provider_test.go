@@ -0,0 +1,78 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+)
+
+func TestGetScaledObject(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = kedav1alpha1.AddToScheme(scheme)
+	newScaledObject := func(namespace string) *kedav1alpha1.ScaledObject {
+		return &kedav1alpha1.ScaledObject{ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      "app",
+			Labels:    map[string]string{kedav1alpha1.ScaledObjectNameLabel: "app"},
+		}}
+	}
+	p := &KedaProvider{client: fake.NewFakeClientWithScheme(scheme, newScaledObject("default"), newScaledObject("other"))}
+
+	tests := []struct {
+		name      string
+		namespace string
+		selector  labels.Set
+		isError   bool
+	}{
+		{"name and namespace", "other", labels.Set{kedav1alpha1.ScaledObjectNameLabel: "app", kedav1alpha1.ScaledObjectNamespaceLabel: "other"}, false},
+		{"name only", "default", labels.Set{kedav1alpha1.ScaledObjectNameLabel: "app"}, false},
+		{"other namespace", "default", labels.Set{kedav1alpha1.ScaledObjectNameLabel: "app", kedav1alpha1.ScaledObjectNamespaceLabel: "other"}, true},
+		{"not found", "default", labels.Set{kedav1alpha1.ScaledObjectNameLabel: "web", kedav1alpha1.ScaledObjectNamespaceLabel: "default"}, true},
+		{"no match", "default", labels.Set{kedav1alpha1.ScaledObjectNameLabel: "web"}, true},
+	}
+	for _, test := range tests {
+		scaledObject, err := p.getScaledObject(context.TODO(), test.namespace, test.selector)
+		if test.isError {
+			if err == nil {
+				t.Errorf("%s: expected an error", test.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error %s", test.name, err)
+			continue
+		}
+		if scaledObject.Namespace != test.namespace || scaledObject.Name != "app" {
+			t.Errorf("%s: expected ScaledObject %s/app, got %s/%s", test.name, test.namespace, scaledObject.Namespace, scaledObject.Name)
+		}
+	}
+}
+
+func TestGetTriggerIndex(t *testing.T) {
+	tests := []struct {
+		selector        labels.Set
+		expected        int
+		hasTriggerIndex bool
+		isError         bool
+	}{
+		{labels.Set{kedav1alpha1.ScaledObjectNameLabel: "app"}, 0, false, false},
+		{labels.Set{kedav1alpha1.TriggerIndexLabel: "2"}, 2, true, false},
+		{labels.Set{kedav1alpha1.TriggerIndexLabel: "queue"}, 0, false, true},
+		{labels.Set{kedav1alpha1.TriggerIndexLabel: "-1"}, 0, false, true},
+	}
+	for _, test := range tests {
+		triggerIndex, hasTriggerIndex, err := getTriggerIndex(test.selector)
+		if test.isError != (err != nil) {
+			t.Errorf("%s: expected error %v, got %v", test.selector, test.isError, err)
+		}
+		if triggerIndex != test.expected || hasTriggerIndex != test.hasTriggerIndex {
+			t.Errorf("%s: expected %d %v, got %d %v", test.selector, test.expected, test.hasTriggerIndex, triggerIndex, hasTriggerIndex)
+		}
+	}
+}
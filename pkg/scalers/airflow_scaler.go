@@ -0,0 +1,286 @@
+package scalers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	v2beta2 "k8s.io/api/autoscaling/v2beta2"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/metrics/pkg/apis/external_metrics"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	kedautil "github.com/kedacore/keda/pkg/util"
+)
+
+const (
+	airflowTaskInstancesPath      = "/api/v1/dags/~/dagRuns/~/taskInstances"
+	airflowQueuedState            = "queued"
+	defaultAirflowQueuedTasks     = 16
+	airflowRequestTimeout         = 10 * time.Second
+	airflowTaskInstanceTable      = "task_instance"
+	airflowDefaultMetricNameGroup = "all"
+)
+
+type airflowScaler struct {
+	metadata   *airflowMetadata
+	httpClient *http.Client
+	connection *sql.DB
+}
+
+type airflowMetadata struct {
+	apiURL      string
+	username    string
+	password    string
+	driver      string
+	connection  string
+	pool        string
+	queue       string
+	queuedTasks int
+	retryPolicy kedautil.RetryPolicy
+}
+
+type airflowTaskInstanceCollection struct {
+	TotalEntries int64 `json:"total_entries"`
+}
+
+var airflowLog = logf.Log.WithName("airflow_scaler")
+
+// NewAirflowScaler creates a new scaler for the queued task instances of Airflow,
+// they are counted with the stable REST API or in the metadata database
+func NewAirflowScaler(resolvedEnv, metadata, authParams map[string]string) (Scaler, error) {
+	meta, err := parseAirflowMetadata(resolvedEnv, metadata, authParams)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing airflow metadata: %s", err)
+	}
+
+	scaler := &airflowScaler{
+		metadata:   meta,
+		httpClient: &http.Client{Timeout: airflowRequestTimeout},
+	}
+	if meta.connection != "" {
+		scaler.connection, err = newSQLConnection(&sqlMetadata{driver: meta.driver, connection: meta.connection})
+		if err != nil {
+			return nil, fmt.Errorf("error establishing airflow metadata database connection: %s", err)
+		}
+	}
+	return scaler, nil
+}
+
+func parseAirflowMetadata(resolvedEnv, metadata, authParams map[string]string) (*airflowMetadata, error) {
+	meta := airflowMetadata{
+		queuedTasks: defaultAirflowQueuedTasks,
+	}
+
+	if val, ok := metadata["apiURL"]; ok && val != "" {
+		meta.apiURL = strings.TrimSuffix(val, "/")
+	} else if val, ok := metadata["apiURLFromEnv"]; ok && val != "" {
+		meta.apiURL = strings.TrimSuffix(resolvedEnv[val], "/")
+	}
+
+	if authParams["connection"] != "" {
+		meta.connection = authParams["connection"]
+	} else if metadata["connectionFromEnv"] != "" {
+		meta.connection = resolvedEnv[metadata["connectionFromEnv"]]
+	}
+
+	switch {
+	case meta.apiURL == "" && meta.connection == "":
+		return nil, fmt.Errorf("no apiURL or connection given")
+	case meta.apiURL != "" && meta.connection != "":
+		return nil, fmt.Errorf("only one of apiURL or connection can be given")
+	case meta.connection != "":
+		switch driver := strings.ToLower(metadata["driver"]); driver {
+		case "postgres", "postgresql", "mysql":
+			meta.driver = driver
+		case "":
+			return nil, fmt.Errorf("no driver given for the metadata database connection")
+		default:
+			return nil, fmt.Errorf("driver %s not supported, supported drivers are postgres and mysql", metadata["driver"])
+		}
+	}
+
+	meta.pool = metadata["pool"]
+	meta.queue = metadata["queue"]
+
+	if val, ok := metadata["queuedTasks"]; ok && val != "" {
+		queuedTasks, err := strconv.Atoi(val)
+		if err != nil {
+			return nil, fmt.Errorf("can't parse queuedTasks: %s", err)
+		}
+		meta.queuedTasks = queuedTasks
+	}
+
+	if val, ok := authParams["username"]; ok && val != "" {
+		meta.username = val
+	}
+	if val, ok := authParams["password"]; ok && val != "" {
+		meta.password = val
+	} else if val, ok := metadata["passwordFromEnv"]; ok && val != "" {
+		meta.password = resolvedEnv[val]
+	}
+	if meta.password != "" && meta.username == "" {
+		return nil, fmt.Errorf("no username given for the password")
+	}
+
+	retryPolicy, err := parseHTTPRetryPolicy(metadata)
+	if err != nil {
+		return nil, err
+	}
+	meta.retryPolicy = retryPolicy
+
+	return &meta, nil
+}
+
+// IsActive returns true if there are queued task instances
+func (s *airflowScaler) IsActive(ctx context.Context) (bool, error) {
+	queued, err := s.getQueuedTasks(ctx)
+	if err != nil {
+		airflowLog.Error(err, "error getting queued task instances")
+		return false, err
+	}
+
+	return queued > 0, nil
+}
+
+func (s *airflowScaler) getQueuedTasks(ctx context.Context) (int64, error) {
+	if s.connection != nil {
+		return s.getQueuedTasksFromDatabase(ctx)
+	}
+	return s.getQueuedTasksFromAPI(ctx)
+}
+
+// getQueuedTasksFromAPI reads the number of the queued task instances of all the DAG runs from the stable REST API
+func (s *airflowScaler) getQueuedTasksFromAPI(ctx context.Context) (int64, error) {
+	query := url.Values{}
+	query.Set("state", airflowQueuedState)
+	if s.metadata.pool != "" {
+		query.Set("pool", s.metadata.pool)
+	}
+	if s.metadata.queue != "" {
+		query.Set("queue", s.metadata.queue)
+	}
+	// only the total is needed
+	query.Set("limit", "1")
+
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s%s?%s", s.metadata.apiURL, airflowTaskInstancesPath, query.Encode()), nil)
+	if err != nil {
+		return 0, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Accept", "application/json")
+	if s.metadata.username != "" {
+		req.SetBasicAuth(s.metadata.username, s.metadata.password)
+	}
+
+	resp, err := kedautil.DoWithRetry(s.httpClient, req, s.metadata.retryPolicy)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("airflow api returned %d", resp.StatusCode)
+	}
+
+	collection := airflowTaskInstanceCollection{}
+	if err := json.NewDecoder(resp.Body).Decode(&collection); err != nil {
+		return 0, fmt.Errorf("error decoding airflow api response: %s", err)
+	}
+	return collection.TotalEntries, nil
+}
+
+// getQueuedTasksFromDatabase counts the queued task instances in the metadata database
+func (s *airflowScaler) getQueuedTasksFromDatabase(ctx context.Context) (int64, error) {
+	query, args := s.buildQueuedTasksQuery()
+
+	var queued int64
+	if err := s.connection.QueryRowContext(ctx, query, args...).Scan(&queued); err != nil {
+		return 0, fmt.Errorf("could not query airflow metadata database: %s", err)
+	}
+	return queued, nil
+}
+
+// buildQueuedTasksQuery returns the count query of the queued task instances with the placeholders of the driver
+func (s *airflowScaler) buildQueuedTasksQuery() (string, []interface{}) {
+	conditions := []string{"state = %s"}
+	args := []interface{}{airflowQueuedState}
+	if s.metadata.pool != "" {
+		conditions = append(conditions, "pool = %s")
+		args = append(args, s.metadata.pool)
+	}
+	if s.metadata.queue != "" {
+		conditions = append(conditions, "queue = %s")
+		args = append(args, s.metadata.queue)
+	}
+
+	for i := range conditions {
+		placeholder := "?"
+		if s.metadata.driver != "mysql" {
+			placeholder = fmt.Sprintf("$%d", i+1)
+		}
+		conditions[i] = fmt.Sprintf(conditions[i], placeholder)
+	}
+	return fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE %s", airflowTaskInstanceTable, strings.Join(conditions, " AND ")), args
+}
+
+// GetMetricSpecForScaling returns the MetricSpec for the Horizontal Pod Autoscaler
+func (s *airflowScaler) GetMetricSpecForScaling() []v2beta2.MetricSpec {
+	targetQueuedTasks := resource.NewQuantity(int64(s.metadata.queuedTasks), resource.DecimalSI)
+	pool := s.metadata.pool
+	if pool == "" {
+		pool = airflowDefaultMetricNameGroup
+	}
+	queue := s.metadata.queue
+	if queue == "" {
+		queue = airflowDefaultMetricNameGroup
+	}
+	externalMetric := &v2beta2.ExternalMetricSource{
+		Metric: v2beta2.MetricIdentifier{
+			Name: kedautil.NormalizeString(fmt.Sprintf("%s-%s-%s", "airflow", pool, queue)),
+		},
+		Target: v2beta2.MetricTarget{
+			Type:         v2beta2.AverageValueMetricType,
+			AverageValue: targetQueuedTasks,
+		},
+	}
+	metricSpec := v2beta2.MetricSpec{External: externalMetric, Type: externalMetricType}
+	return []v2beta2.MetricSpec{metricSpec}
+}
+
+// GetMetrics returns value for a supported metric and an error if there is a problem getting the metric
+func (s *airflowScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
+	queued, err := s.getQueuedTasks(ctx)
+	if err != nil {
+		return []external_metrics.ExternalMetricValue{}, fmt.Errorf("error getting airflow queued task instances: %s", err)
+	}
+
+	metric := external_metrics.ExternalMetricValue{
+		MetricName: metricName,
+		Value:      *resource.NewQuantity(queued, resource.DecimalSI),
+		Timestamp:  metav1.Now(),
+	}
+
+	return append([]external_metrics.ExternalMetricValue{}, metric), nil
+}
+
+// Close disposes of the metadata database connections
+func (s *airflowScaler) Close() error {
+	if s.connection == nil {
+		return nil
+	}
+	err := s.connection.Close()
+	if err != nil {
+		airflowLog.Error(err, "Error closing airflow metadata database connection")
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,290 @@
+package scalers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	amqp10 "github.com/Azure/go-amqp"
+	v2beta2 "k8s.io/api/autoscaling/v2beta2"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/metrics/pkg/apis/external_metrics"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/kedacore/keda/pkg/scalers/authentication"
+	kedautil "github.com/kedacore/keda/pkg/util"
+)
+
+const (
+	amqpQueueLengthMetricName = "queueLength"
+	defaultAMQPQueueLength    = 20
+	defaultAMQPManagementNode = "$management"
+	defaultAMQPEntityType     = "queue"
+	defaultAMQPDepthAttribute = "messageCount"
+	amqpReadOperation         = "READ"
+	amqpRequestTimeout        = 10 * time.Second
+)
+
+type amqpScaler struct {
+	metadata *amqpMetadata
+}
+
+type amqpMetadata struct {
+	host        string
+	queueName   string
+	queueLength int64
+	// the queue is read with the READ operation of the AMQP management node, the depth is the depthAttribute of the entity
+	managementNode string
+	entityType     string
+	depthAttribute string
+
+	sasl authentication.SASLConfig
+	tls  authentication.TLSConfig
+}
+
+var amqpLog = logf.Log.WithName("amqp_scaler")
+
+// NewAMQPScaler creates a new scaler for the depth of a queue of an AMQP 1.0 broker,
+// it is read with the management operations of the broker
+func NewAMQPScaler(resolvedEnv, metadata, authParams map[string]string) (Scaler, error) {
+	meta, err := parseAMQPMetadata(resolvedEnv, metadata, authParams)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing amqp metadata: %s", err)
+	}
+
+	return &amqpScaler{
+		metadata: meta,
+	}, nil
+}
+
+func parseAMQPMetadata(resolvedEnv, metadata, authParams map[string]string) (*amqpMetadata, error) {
+	meta := amqpMetadata{
+		queueLength:    defaultAMQPQueueLength,
+		managementNode: defaultAMQPManagementNode,
+		entityType:     defaultAMQPEntityType,
+		depthAttribute: defaultAMQPDepthAttribute,
+	}
+
+	if authParams["host"] != "" {
+		meta.host = authParams["host"]
+	} else if metadata["host"] != "" {
+		meta.host = metadata["host"]
+	} else if metadata["hostFromEnv"] != "" {
+		meta.host = resolvedEnv[metadata["hostFromEnv"]]
+	} else {
+		return nil, fmt.Errorf("no host setting given")
+	}
+	if !strings.HasPrefix(meta.host, "amqp://") && !strings.HasPrefix(meta.host, "amqps://") {
+		return nil, fmt.Errorf("the host has to be an amqp:// or amqps:// address")
+	}
+
+	if val, ok := metadata["queueName"]; ok && val != "" {
+		meta.queueName = val
+	} else {
+		return nil, fmt.Errorf("no queue name given")
+	}
+
+	if val, ok := metadata[amqpQueueLengthMetricName]; ok {
+		queueLength, err := strconv.ParseInt(val, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("can't parse %s: %s", amqpQueueLengthMetricName, err)
+		}
+		meta.queueLength = queueLength
+	}
+
+	if val, ok := metadata["managementNode"]; ok && val != "" {
+		meta.managementNode = val
+	}
+	if val, ok := metadata["entityType"]; ok && val != "" {
+		meta.entityType = val
+	}
+	if val, ok := metadata["depthAttribute"]; ok && val != "" {
+		meta.depthAttribute = val
+	}
+
+	sasl, err := authentication.ParseSASLConfig(authParams)
+	if err != nil {
+		return nil, err
+	}
+	if sasl.Type != authentication.SASLTypeNone && sasl.Type != authentication.SASLTypePlaintext {
+		return nil, fmt.Errorf("SASL mode %s not supported, supported modes are none and plaintext", sasl.Type)
+	}
+	meta.sasl = sasl
+
+	tlsConfig, err := authentication.ParseTLSConfig(authParams)
+	if err != nil {
+		return nil, err
+	}
+	meta.tls = tlsConfig
+
+	return &meta, nil
+}
+
+// Close has nothing to dispose, a connection is opened for each query
+func (s *amqpScaler) Close() error {
+	return nil
+}
+
+// IsActive returns true if there are messages in the queue
+func (s *amqpScaler) IsActive(ctx context.Context) (bool, error) {
+	depth, err := s.getQueueDepth(ctx)
+	if err != nil {
+		return false, fmt.Errorf("error inspecting amqp queue: %s", err)
+	}
+
+	return depth > 0, nil
+}
+
+func (s *amqpScaler) connOptions() ([]amqp10.ConnOption, error) {
+	options := []amqp10.ConnOption{amqp10.ConnConnectTimeout(amqpRequestTimeout)}
+	if s.metadata.sasl.Type == authentication.SASLTypePlaintext {
+		options = append(options, amqp10.ConnSASLPlain(s.metadata.sasl.Username, s.metadata.sasl.Password))
+	} else {
+		options = append(options, amqp10.ConnSASLAnonymous())
+	}
+
+	if s.metadata.tls.Enabled {
+		tlsConfig, err := s.metadata.tls.NewTLSConfig()
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, amqp10.ConnTLS(true), amqp10.ConnTLSConfig(tlsConfig))
+	}
+	return options, nil
+}
+
+// getQueueDepth sends the READ request of the queue to the management node of the broker and returns the depth in its response
+func (s *amqpScaler) getQueueDepth(ctx context.Context) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, amqpRequestTimeout)
+	defer cancel()
+
+	options, err := s.connOptions()
+	if err != nil {
+		return -1, err
+	}
+	client, err := amqp10.Dial(s.metadata.host, options...)
+	if err != nil {
+		return -1, fmt.Errorf("error establishing amqp connection: %s", err)
+	}
+	defer client.Close()
+
+	session, err := client.NewSession()
+	if err != nil {
+		return -1, fmt.Errorf("error creating amqp session: %s", err)
+	}
+
+	// the management node sends its response to the reply address of the request
+	replyTo := fmt.Sprintf("keda-%s", rand.String(8))
+	receiver, err := session.NewReceiver(amqp10.LinkSourceAddress(s.metadata.managementNode), amqp10.LinkTargetAddress(replyTo))
+	if err != nil {
+		return -1, fmt.Errorf("error creating the amqp management receiver: %s", err)
+	}
+	sender, err := session.NewSender(amqp10.LinkTargetAddress(s.metadata.managementNode))
+	if err != nil {
+		return -1, fmt.Errorf("error creating the amqp management sender: %s", err)
+	}
+
+	request := &amqp10.Message{
+		Properties: &amqp10.MessageProperties{MessageID: replyTo, ReplyTo: replyTo},
+		ApplicationProperties: map[string]interface{}{
+			"operation": amqpReadOperation,
+			"type":      s.metadata.entityType,
+			"name":      s.metadata.queueName,
+		},
+	}
+	if err := sender.Send(ctx, request); err != nil {
+		return -1, fmt.Errorf("error sending the amqp management request: %s", err)
+	}
+
+	response, err := receiver.Receive(ctx)
+	if err != nil {
+		return -1, fmt.Errorf("error receiving the amqp management response: %s", err)
+	}
+	if err := response.Accept(ctx); err != nil {
+		amqpLog.V(1).Info("Failed to accept the amqp management response", "error", err.Error())
+	}
+
+	return s.getQueueDepthFromResponse(response)
+}
+
+// getQueueDepthFromResponse returns the depth attribute of the queue in the body of the READ response
+func (s *amqpScaler) getQueueDepthFromResponse(response *amqp10.Message) (int64, error) {
+	statusCode := int64(-1)
+	for _, key := range []string{"statusCode", "status-code"} {
+		if code, ok := amqpInt64(response.ApplicationProperties[key]); ok {
+			statusCode = code
+		}
+	}
+	if statusCode != 200 {
+		return -1, fmt.Errorf("amqp management READ of %s returned status %d: %v", s.metadata.queueName, statusCode, response.ApplicationProperties["statusDescription"])
+	}
+
+	attributes, ok := response.Value.(map[string]interface{})
+	if !ok {
+		return -1, fmt.Errorf("amqp management READ of %s returned no attributes", s.metadata.queueName)
+	}
+	depth, ok := amqpInt64(attributes[s.metadata.depthAttribute])
+	if !ok {
+		return -1, fmt.Errorf("attribute %s of %s not found in the amqp management response", s.metadata.depthAttribute, s.metadata.queueName)
+	}
+	return depth, nil
+}
+
+func amqpInt64(value interface{}) (int64, bool) {
+	switch v := value.(type) {
+	case int64:
+		return v, true
+	case int32:
+		return int64(v), true
+	case int:
+		return int64(v), true
+	case uint64:
+		return int64(v), true
+	case uint32:
+		return int64(v), true
+	case string:
+		i, err := strconv.ParseInt(v, 10, 64)
+		return i, err == nil
+	default:
+		return 0, false
+	}
+}
+
+// GetMetricSpecForScaling returns the MetricSpec for the Horizontal Pod Autoscaler
+func (s *amqpScaler) GetMetricSpecForScaling() []v2beta2.MetricSpec {
+	targetMetricValue := resource.NewQuantity(s.metadata.queueLength, resource.DecimalSI)
+	externalMetric := &v2beta2.ExternalMetricSource{
+		Metric: v2beta2.MetricIdentifier{
+			Name: kedautil.NormalizeString(fmt.Sprintf("%s-%s", "amqp", s.metadata.queueName)),
+		},
+		Target: v2beta2.MetricTarget{
+			Type:         v2beta2.AverageValueMetricType,
+			AverageValue: targetMetricValue,
+		},
+	}
+	metricSpec := v2beta2.MetricSpec{
+		External: externalMetric, Type: externalMetricType,
+	}
+	return []v2beta2.MetricSpec{metricSpec}
+}
+
+// GetMetrics returns the depth of the queue
+func (s *amqpScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
+	depth, err := s.getQueueDepth(ctx)
+	if err != nil {
+		return []external_metrics.ExternalMetricValue{}, fmt.Errorf("error inspecting amqp queue: %s", err)
+	}
+
+	metric := external_metrics.ExternalMetricValue{
+		MetricName: metricName,
+		Value:      *resource.NewQuantity(depth, resource.DecimalSI),
+		Timestamp:  metav1.Now(),
+	}
+
+	return append([]external_metrics.ExternalMetricValue{}, metric), nil
+}
@@ -0,0 +1,100 @@
+package scalers
+
+import (
+	"testing"
+
+	amqp10 "github.com/Azure/go-amqp"
+)
+
+type parseAMQPMetadataTestData struct {
+	metadata   map[string]string
+	authParams map[string]string
+	isError    bool
+}
+
+type amqpMetricIdentifier struct {
+	metadataTestData *parseAMQPMetadataTestData
+	name             string
+}
+
+var testAMQPMetadata = []parseAMQPMetadataTestData{
+	// nothing passed
+	{map[string]string{}, map[string]string{}, true},
+	// properly formed
+	{map[string]string{"host": "amqp://broker:5672", "queueName": "orders", "queueLength": "10"}, map[string]string{}, false},
+	// host in the auth params with SASL and TLS
+	{map[string]string{"queueName": "orders"}, map[string]string{"host": "amqps://broker:5671", "sasl": "plaintext", "username": "admin", "password": "admin", "tls": "enable", "ca": "caaa", "cert": "ceert", "key": "keey"}, false},
+	// host from env
+	{map[string]string{"hostFromEnv": "AMQP_HOST", "queueName": "orders", "entityType": "org.apache.qpid.Queue", "depthAttribute": "queueDepthMessages"}, map[string]string{}, false},
+	// not an amqp host
+	{map[string]string{"host": "http://broker:8161", "queueName": "orders"}, map[string]string{}, true},
+	// missing queue name
+	{map[string]string{"host": "amqp://broker:5672"}, map[string]string{}, true},
+	// malformed queue length
+	{map[string]string{"host": "amqp://broker:5672", "queueName": "orders", "queueLength": "ten"}, map[string]string{}, true},
+	// unsupported SASL mode
+	{map[string]string{"host": "amqp://broker:5672", "queueName": "orders"}, map[string]string{"sasl": "scram_sha256", "username": "admin", "password": "admin"}, true},
+}
+
+var amqpMetricIdentifiers = []amqpMetricIdentifier{
+	{&testAMQPMetadata[1], "amqp-orders"},
+}
+
+func TestAMQPParseMetadata(t *testing.T) {
+	for _, testData := range testAMQPMetadata {
+		_, err := parseAMQPMetadata(map[string]string{"AMQP_HOST": "amqp://broker:5672"}, testData.metadata, testData.authParams)
+		if err != nil && !testData.isError {
+			t.Error("Expected success but got error", err)
+		}
+		if testData.isError && err == nil {
+			t.Error("Expected error but got success")
+		}
+	}
+}
+
+func TestAMQPGetMetricSpecForScaling(t *testing.T) {
+	for _, testData := range amqpMetricIdentifiers {
+		meta, err := parseAMQPMetadata(nil, testData.metadataTestData.metadata, testData.metadataTestData.authParams)
+		if err != nil {
+			t.Fatal("Could not parse metadata:", err)
+		}
+		mockAMQPScaler := amqpScaler{meta}
+
+		metricSpec := mockAMQPScaler.GetMetricSpecForScaling()
+		metricName := metricSpec[0].External.Metric.Name
+		if metricName != testData.name {
+			t.Error("Wrong External metric source name:", metricName)
+		}
+	}
+}
+
+func TestAMQPGetQueueDepthFromResponse(t *testing.T) {
+	meta, err := parseAMQPMetadata(nil, map[string]string{"host": "amqp://broker:5672", "queueName": "orders"}, map[string]string{})
+	if err != nil {
+		t.Fatal("Could not parse metadata:", err)
+	}
+	scaler := amqpScaler{meta}
+
+	tests := []struct {
+		name     string
+		response *amqp10.Message
+		depth    int64
+		isError  bool
+	}{
+		{"depth", &amqp10.Message{ApplicationProperties: map[string]interface{}{"statusCode": int32(200)}, Value: map[string]interface{}{"name": "orders", "messageCount": int64(42)}}, 42, false},
+		{"status code of Service Bus", &amqp10.Message{ApplicationProperties: map[string]interface{}{"status-code": int32(200)}, Value: map[string]interface{}{"messageCount": uint32(7)}}, 7, false},
+		{"queue not found", &amqp10.Message{ApplicationProperties: map[string]interface{}{"statusCode": int32(404), "statusDescription": "not found"}}, -1, true},
+		{"no depth attribute", &amqp10.Message{ApplicationProperties: map[string]interface{}{"statusCode": int32(200)}, Value: map[string]interface{}{"name": "orders"}}, -1, true},
+		{"no attributes", &amqp10.Message{ApplicationProperties: map[string]interface{}{"statusCode": int32(200)}}, -1, true},
+	}
+
+	for _, test := range tests {
+		depth, err := scaler.getQueueDepthFromResponse(test.response)
+		if test.isError != (err != nil) {
+			t.Errorf("%s: expected error %t but got %v", test.name, test.isError, err)
+		}
+		if depth != test.depth {
+			t.Errorf("%s: expected a depth of %d but got %d", test.name, test.depth, depth)
+		}
+	}
+}
@@ -34,6 +34,7 @@ type artemisMetadata struct {
 	password           string
 	restAPITemplate    string
 	queueLength        int
+	retryPolicy        kedautil.RetryPolicy
 }
 
 //revive:enable:var-naming
@@ -136,12 +137,19 @@ func parseArtemisMetadata(resolvedEnv, metadata, authParams map[string]string) (
 	if meta.password == "" {
 		return nil, fmt.Errorf("password cannot be empty")
 	}
+
+	retryPolicy, err := parseHTTPRetryPolicy(metadata)
+	if err != nil {
+		return nil, err
+	}
+	meta.retryPolicy = retryPolicy
+
 	return &meta, nil
 }
 
 // IsActive determines if we need to scale from zero
 func (s *artemisScaler) IsActive(ctx context.Context) (bool, error) {
-	messages, err := s.getQueueMessageCount()
+	messages, err := s.getQueueMessageCount(ctx)
 	if err != nil {
 		artemisLog.Error(err, "Unable to access the artemis management endpoint", "managementEndpoint", s.metadata.managementEndpoint)
 		return false, err
@@ -161,7 +169,7 @@ func (s *artemisScaler) getMonitoringEndpoint() string {
 	return monitoringEndpoint
 }
 
-func (s *artemisScaler) getQueueMessageCount() (int, error) {
+func (s *artemisScaler) getQueueMessageCount(ctx context.Context) (int, error) {
 	var messageCount int
 	var monitoringInfo *artemisMonitoring
 	messageCount = 0
@@ -171,14 +179,13 @@ func (s *artemisScaler) getQueueMessageCount() (int, error) {
 	}
 	url := s.getMonitoringEndpoint()
 
-	req, err := http.NewRequest("GET", url, nil)
-
-	req.SetBasicAuth(s.metadata.username, s.metadata.password)
-
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return -1, err
 	}
-	resp, err := client.Do(req)
+	req.SetBasicAuth(s.metadata.username, s.metadata.password)
+
+	resp, err := kedautil.DoWithRetry(client, req, s.metadata.retryPolicy)
 	if err != nil {
 		return -1, err
 	}
@@ -214,7 +221,7 @@ func (s *artemisScaler) GetMetricSpecForScaling() []v2beta2.MetricSpec {
 
 //GetMetrics returns value for a supported metric and an error if there is a problem getting the metric
 func (s *artemisScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
-	messages, err := s.getQueueMessageCount()
+	messages, err := s.getQueueMessageCount(ctx)
 
 	if err != nil {
 		artemisLog.Error(err, "Unable to access the artemis management endpoint", "managementEndpoint", s.metadata.managementEndpoint)
@@ -0,0 +1,307 @@
+package scalers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	v2beta2 "k8s.io/api/autoscaling/v2beta2"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/metrics/pkg/apis/external_metrics"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	kedautil "github.com/kedacore/keda/pkg/util"
+)
+
+const (
+	artifactoryRepositoryManager = "artifactory"
+	nexusRepositoryManager       = "nexus"
+
+	defaultArtifactRepositoryQueueLength = 10
+	defaultArtifactoryQueuePath          = "/api/replication/queue"
+	nexusTasksPath                       = "/service/rest/v1/tasks"
+	artifactRepositoryRequestTimeout     = 10 * time.Second
+)
+
+// nexusPendingTaskStates are the states of the Nexus tasks counted in the queue
+var nexusPendingTaskStates = map[string]bool{
+	"WAITING": true,
+	"RUNNING": true,
+}
+
+type artifactRepositoryScaler struct {
+	metadata   *artifactRepositoryMetadata
+	httpClient *http.Client
+}
+
+type artifactRepositoryMetadata struct {
+	repositoryManager string
+	url               string
+	queuePath         string
+	repoKey           string
+	taskType          string
+	username          string
+	password          string
+	accessToken       string
+	queueLength       int
+	retryPolicy       kedautil.RetryPolicy
+}
+
+type artifactoryQueueItem struct {
+	RepoKey string `json:"repoKey"`
+}
+
+type nexusTaskList struct {
+	Items []struct {
+		Type         string `json:"type"`
+		CurrentState string `json:"currentState"`
+	} `json:"items"`
+	ContinuationToken string `json:"continuationToken"`
+}
+
+var artifactRepositoryLog = logf.Log.WithName("artifact_repository_scaler")
+
+// NewArtifactRepositoryScaler creates a new scaler for the replication or event queue of Artifactory and the task queue of Nexus
+func NewArtifactRepositoryScaler(resolvedEnv, metadata, authParams map[string]string) (Scaler, error) {
+	meta, err := parseArtifactRepositoryMetadata(resolvedEnv, metadata, authParams)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing artifact repository metadata: %s", err)
+	}
+
+	return &artifactRepositoryScaler{
+		metadata:   meta,
+		httpClient: &http.Client{Timeout: artifactRepositoryRequestTimeout},
+	}, nil
+}
+
+func parseArtifactRepositoryMetadata(resolvedEnv, metadata, authParams map[string]string) (*artifactRepositoryMetadata, error) {
+	meta := artifactRepositoryMetadata{}
+
+	switch repositoryManager := strings.ToLower(metadata["repositoryManager"]); repositoryManager {
+	case artifactoryRepositoryManager, nexusRepositoryManager:
+		meta.repositoryManager = repositoryManager
+	case "":
+		return nil, fmt.Errorf("no repositoryManager given")
+	default:
+		return nil, fmt.Errorf("repositoryManager %s not supported, supported repository managers are %s, %s", metadata["repositoryManager"], artifactoryRepositoryManager, nexusRepositoryManager)
+	}
+
+	if val, ok := metadata["url"]; ok && val != "" {
+		meta.url = strings.TrimSuffix(val, "/")
+	} else if val, ok := metadata["urlFromEnv"]; ok && val != "" {
+		meta.url = strings.TrimSuffix(resolvedEnv[val], "/")
+	}
+	if meta.url == "" {
+		return nil, fmt.Errorf("no url given")
+	}
+
+	meta.queuePath = defaultArtifactoryQueuePath
+	if val, ok := metadata["queuePath"]; ok && val != "" {
+		if meta.repositoryManager != artifactoryRepositoryManager {
+			return nil, fmt.Errorf("queuePath is only supported for %s", artifactoryRepositoryManager)
+		}
+		meta.queuePath = "/" + strings.TrimPrefix(val, "/")
+	}
+
+	meta.repoKey = metadata["repoKey"]
+	meta.taskType = metadata["taskType"]
+	if meta.repositoryManager == artifactoryRepositoryManager && meta.taskType != "" {
+		return nil, fmt.Errorf("taskType is only supported for %s", nexusRepositoryManager)
+	}
+	if meta.repositoryManager == nexusRepositoryManager && meta.repoKey != "" {
+		return nil, fmt.Errorf("repoKey is only supported for %s", artifactoryRepositoryManager)
+	}
+
+	meta.queueLength = defaultArtifactRepositoryQueueLength
+	if val, ok := metadata["queueLength"]; ok && val != "" {
+		queueLength, err := strconv.Atoi(val)
+		if err != nil {
+			return nil, fmt.Errorf("can't parse queueLength: %s", err)
+		}
+		meta.queueLength = queueLength
+	}
+
+	meta.username = getArtifactRepositoryParameter("username", resolvedEnv, metadata, authParams)
+	meta.password = getArtifactRepositoryParameter("password", resolvedEnv, metadata, authParams)
+	meta.accessToken = getArtifactRepositoryParameter("accessToken", resolvedEnv, metadata, authParams)
+	if meta.accessToken != "" && meta.username != "" {
+		return nil, fmt.Errorf("only one of accessToken or username and password can be given")
+	}
+	if meta.username != "" && meta.password == "" {
+		return nil, fmt.Errorf("no password given for username %s", meta.username)
+	}
+	if meta.accessToken != "" && meta.repositoryManager != artifactoryRepositoryManager {
+		return nil, fmt.Errorf("accessToken is only supported for %s", artifactoryRepositoryManager)
+	}
+
+	retryPolicy, err := parseHTTPRetryPolicy(metadata)
+	if err != nil {
+		return nil, err
+	}
+	meta.retryPolicy = retryPolicy
+
+	return &meta, nil
+}
+
+// getArtifactRepositoryParameter returns the parameter from the TriggerAuthentication,
+// or from the environment variable of the scale target named by the <name>FromEnv metadata
+func getArtifactRepositoryParameter(name string, resolvedEnv, metadata, authParams map[string]string) string {
+	if val, ok := authParams[name]; ok && val != "" {
+		return val
+	}
+	if val, ok := metadata[name+"FromEnv"]; ok && val != "" {
+		return resolvedEnv[val]
+	}
+	return ""
+}
+
+// IsActive returns true if there are items in the queue
+func (s *artifactRepositoryScaler) IsActive(ctx context.Context) (bool, error) {
+	queueSize, err := s.getQueueSize(ctx)
+	if err != nil {
+		artifactRepositoryLog.Error(err, "error getting queue size", "repositoryManager", s.metadata.repositoryManager, "url", s.metadata.url)
+		return false, err
+	}
+
+	return queueSize > 0, nil
+}
+
+func (s *artifactRepositoryScaler) getQueueSize(ctx context.Context) (int64, error) {
+	if s.metadata.repositoryManager == nexusRepositoryManager {
+		return s.getNexusQueueSize(ctx)
+	}
+	return s.getArtifactoryQueueSize(ctx)
+}
+
+// getArtifactoryQueueSize returns the number of the items in the queue, filtered by the repository if a repoKey is given
+func (s *artifactRepositoryScaler) getArtifactoryQueueSize(ctx context.Context) (int64, error) {
+	var items []artifactoryQueueItem
+	if err := s.getJSON(ctx, s.metadata.url+s.metadata.queuePath, &items); err != nil {
+		return 0, err
+	}
+
+	if s.metadata.repoKey == "" {
+		return int64(len(items)), nil
+	}
+	var queueSize int64
+	for _, item := range items {
+		if item.RepoKey == s.metadata.repoKey {
+			queueSize++
+		}
+	}
+	return queueSize, nil
+}
+
+// getNexusQueueSize returns the number of the waiting and running tasks, filtered by type if a taskType is given
+func (s *artifactRepositoryScaler) getNexusQueueSize(ctx context.Context) (int64, error) {
+	var queueSize int64
+	continuationToken := ""
+	for {
+		query := url.Values{}
+		if s.metadata.taskType != "" {
+			query.Set("type", s.metadata.taskType)
+		}
+		if continuationToken != "" {
+			query.Set("continuationToken", continuationToken)
+		}
+		tasksURL := s.metadata.url + nexusTasksPath
+		if len(query) > 0 {
+			tasksURL += "?" + query.Encode()
+		}
+
+		tasks := nexusTaskList{}
+		if err := s.getJSON(ctx, tasksURL, &tasks); err != nil {
+			return 0, err
+		}
+		for _, task := range tasks.Items {
+			if nexusPendingTaskStates[strings.ToUpper(task.CurrentState)] {
+				queueSize++
+			}
+		}
+
+		if tasks.ContinuationToken == "" {
+			return queueSize, nil
+		}
+		continuationToken = tasks.ContinuationToken
+	}
+}
+
+func (s *artifactRepositoryScaler) getJSON(ctx context.Context, requestURL string, v interface{}) error {
+	req, err := http.NewRequest(http.MethodGet, requestURL, nil)
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Accept", "application/json")
+	switch {
+	case s.metadata.accessToken != "":
+		req.Header.Set("Authorization", "Bearer "+s.metadata.accessToken)
+	case s.metadata.username != "":
+		req.SetBasicAuth(s.metadata.username, s.metadata.password)
+	}
+
+	resp, err := kedautil.DoWithRetry(s.httpClient, req, s.metadata.retryPolicy)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s api returned %d", s.metadata.repositoryManager, resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("error decoding %s api response: %s", s.metadata.repositoryManager, err)
+	}
+	return nil
+}
+
+// GetMetricSpecForScaling returns the MetricSpec for the Horizontal Pod Autoscaler
+func (s *artifactRepositoryScaler) GetMetricSpecForScaling() []v2beta2.MetricSpec {
+	targetQueueLength := resource.NewQuantity(int64(s.metadata.queueLength), resource.DecimalSI)
+	queueName := s.metadata.repoKey
+	if s.metadata.repositoryManager == nexusRepositoryManager {
+		queueName = s.metadata.taskType
+	}
+	if queueName == "" {
+		queueName = "all"
+	}
+	externalMetric := &v2beta2.ExternalMetricSource{
+		Metric: v2beta2.MetricIdentifier{
+			Name: kedautil.NormalizeString(fmt.Sprintf("%s-%s", s.metadata.repositoryManager, queueName)),
+		},
+		Target: v2beta2.MetricTarget{
+			Type:         v2beta2.AverageValueMetricType,
+			AverageValue: targetQueueLength,
+		},
+	}
+	metricSpec := v2beta2.MetricSpec{External: externalMetric, Type: externalMetricType}
+	return []v2beta2.MetricSpec{metricSpec}
+}
+
+// GetMetrics returns value for a supported metric and an error if there is a problem getting the metric
+func (s *artifactRepositoryScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
+	queueSize, err := s.getQueueSize(ctx)
+	if err != nil {
+		return []external_metrics.ExternalMetricValue{}, fmt.Errorf("error getting %s queue size: %s", s.metadata.repositoryManager, err)
+	}
+
+	metric := external_metrics.ExternalMetricValue{
+		MetricName: metricName,
+		Value:      *resource.NewQuantity(queueSize, resource.DecimalSI),
+		Timestamp:  metav1.Now(),
+	}
+
+	return append([]external_metrics.ExternalMetricValue{}, metric), nil
+}
+
+// Close does nothing in case of artifactRepositoryScaler
+func (s *artifactRepositoryScaler) Close() error {
+	return nil
+}
@@ -0,0 +1,172 @@
+package scalers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type parseArtifactRepositoryMetadataTestData struct {
+	metadata   map[string]string
+	authParams map[string]string
+	isError    bool
+}
+
+type artifactRepositoryMetricIdentifier struct {
+	metadataTestData *parseArtifactRepositoryMetadataTestData
+	name             string
+}
+
+var testArtifactRepositoryResolvedEnv = map[string]string{
+	"ARTIFACTORY_URL":   "http://artifactory:8081/artifactory",
+	"ARTIFACTORY_TOKEN": "token",
+}
+
+var testArtifactRepositoryMetadata = []parseArtifactRepositoryMetadataTestData{
+	// nothing passed
+	{map[string]string{}, map[string]string{}, true},
+	// properly formed artifactory
+	{map[string]string{"repositoryManager": "artifactory", "url": "http://artifactory:8081/artifactory", "repoKey": "libs-release", "queueLength": "5"}, map[string]string{}, false},
+	// properly formed nexus
+	{map[string]string{"repositoryManager": "Nexus", "url": "http://nexus:8081", "taskType": "repository.cleanup"}, map[string]string{"username": "admin", "password": "admin"}, false},
+	// url and accessToken from env
+	{map[string]string{"repositoryManager": "artifactory", "urlFromEnv": "ARTIFACTORY_URL", "accessTokenFromEnv": "ARTIFACTORY_TOKEN"}, map[string]string{}, false},
+	// unknown repository manager
+	{map[string]string{"repositoryManager": "harbor", "url": "http://harbor"}, map[string]string{}, true},
+	// missing url
+	{map[string]string{"repositoryManager": "nexus"}, map[string]string{}, true},
+	// malformed queueLength
+	{map[string]string{"repositoryManager": "nexus", "url": "http://nexus:8081", "queueLength": "many"}, map[string]string{}, true},
+	// repoKey with nexus
+	{map[string]string{"repositoryManager": "nexus", "url": "http://nexus:8081", "repoKey": "libs-release"}, map[string]string{}, true},
+	// taskType with artifactory
+	{map[string]string{"repositoryManager": "artifactory", "url": "http://artifactory", "taskType": "repository.cleanup"}, map[string]string{}, true},
+	// queuePath with nexus
+	{map[string]string{"repositoryManager": "nexus", "url": "http://nexus:8081", "queuePath": "/api/queue"}, map[string]string{}, true},
+	// username without password
+	{map[string]string{"repositoryManager": "nexus", "url": "http://nexus:8081"}, map[string]string{"username": "admin"}, true},
+	// accessToken with nexus
+	{map[string]string{"repositoryManager": "nexus", "url": "http://nexus:8081"}, map[string]string{"accessToken": "token"}, true},
+	// accessToken and username
+	{map[string]string{"repositoryManager": "artifactory", "url": "http://artifactory"}, map[string]string{"accessToken": "token", "username": "admin", "password": "admin"}, true},
+}
+
+var artifactRepositoryMetricIdentifiers = []artifactRepositoryMetricIdentifier{
+	{&testArtifactRepositoryMetadata[1], "artifactory-libs-release"},
+	{&testArtifactRepositoryMetadata[2], "nexus-repository-cleanup"},
+	{&testArtifactRepositoryMetadata[3], "artifactory-all"},
+}
+
+func TestArtifactRepositoryParseMetadata(t *testing.T) {
+	for _, testData := range testArtifactRepositoryMetadata {
+		_, err := parseArtifactRepositoryMetadata(testArtifactRepositoryResolvedEnv, testData.metadata, testData.authParams)
+		if err != nil && !testData.isError {
+			t.Error("Expected success but got error", err)
+		}
+		if testData.isError && err == nil {
+			t.Errorf("Expected error but got success for %v", testData.metadata)
+		}
+	}
+}
+
+func TestArtifactRepositoryGetMetricSpecForScaling(t *testing.T) {
+	for _, testData := range artifactRepositoryMetricIdentifiers {
+		meta, err := parseArtifactRepositoryMetadata(testArtifactRepositoryResolvedEnv, testData.metadataTestData.metadata, testData.metadataTestData.authParams)
+		if err != nil {
+			t.Fatal("Could not parse metadata:", err)
+		}
+		mockArtifactRepositoryScaler := artifactRepositoryScaler{meta, http.DefaultClient}
+
+		metricSpec := mockArtifactRepositoryScaler.GetMetricSpecForScaling()
+		metricName := metricSpec[0].External.Metric.Name
+		if metricName != testData.name {
+			t.Error("Wrong External metric source name:", metricName)
+		}
+	}
+}
+
+func TestArtifactoryGetQueueSize(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/artifactory/api/replication/queue", r.URL.Path)
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		w.Write([]byte(`[{"repoKey": "libs-release"}, {"repoKey": "libs-snapshot"}, {"repoKey": "libs-release"}]`))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		repoKey  string
+		expected int64
+	}{
+		{"libs-release", 2},
+		{"", 3},
+		{"docker", 0},
+	}
+
+	for _, test := range tests {
+		meta, err := parseArtifactRepositoryMetadata(nil,
+			map[string]string{"repositoryManager": "artifactory", "url": server.URL + "/artifactory/", "repoKey": test.repoKey},
+			map[string]string{"accessToken": "token"})
+		if err != nil {
+			t.Fatal("Could not parse metadata:", err)
+		}
+		scaler := artifactRepositoryScaler{meta, http.DefaultClient}
+
+		queueSize, err := scaler.getQueueSize(context.TODO())
+		assert.NoError(t, err)
+		assert.Equal(t, test.expected, queueSize)
+	}
+}
+
+func TestNexusGetQueueSize(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/service/rest/v1/tasks", r.URL.Path)
+		assert.Equal(t, "repository.cleanup", r.URL.Query().Get("type"))
+		username, password, _ := r.BasicAuth()
+		assert.Equal(t, "admin", username)
+		assert.Equal(t, "secret", password)
+
+		if r.URL.Query().Get("continuationToken") == "" {
+			w.Write([]byte(`{"items": [{"currentState": "WAITING"}, {"currentState": "DONE"}], "continuationToken": "next"}`))
+			return
+		}
+		w.Write([]byte(`{"items": [{"currentState": "RUNNING"}, {"currentState": "WAITING"}], "continuationToken": null}`))
+	}))
+	defer server.Close()
+
+	meta, err := parseArtifactRepositoryMetadata(nil,
+		map[string]string{"repositoryManager": "nexus", "url": server.URL, "taskType": "repository.cleanup"},
+		map[string]string{"username": "admin", "password": "secret"})
+	if err != nil {
+		t.Fatal("Could not parse metadata:", err)
+	}
+	scaler := artifactRepositoryScaler{meta, http.DefaultClient}
+
+	queueSize, err := scaler.getQueueSize(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), queueSize)
+
+	active, err := scaler.IsActive(context.TODO())
+	assert.NoError(t, err)
+	assert.True(t, active)
+}
+
+func TestArtifactRepositoryGetQueueSizeError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	meta, err := parseArtifactRepositoryMetadata(nil,
+		map[string]string{"repositoryManager": "artifactory", "url": server.URL},
+		map[string]string{})
+	if err != nil {
+		t.Fatal("Could not parse metadata:", err)
+	}
+	scaler := artifactRepositoryScaler{meta, http.DefaultClient}
+
+	_, err = scaler.GetMetrics(context.TODO(), "artifactory-all", nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,147 @@
+package authentication
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	prommetrics "github.com/kedacore/keda/pkg/metrics"
+)
+
+const (
+	// tokens are renewed this long before their expiry
+	tokenExpiryDelta = 30 * time.Second
+	// defaultTokenLifetime is the lifetime of the tokens whose response has no expires_in
+	defaultTokenLifetime = 5 * time.Minute
+	// tokenSourceIdleTimeout is how long the token source of credentials no scaler uses anymore is kept
+	tokenSourceIdleTimeout = time.Hour
+	// clientCredentialsTokenSource names the tokens of the client credentials grant in the token metrics
+	clientCredentialsTokenSource = "oauth-client-credentials"
+)
+
+// ClientCredentialsTokenSource requests the OAuth access tokens of the OAUTHBEARER SASL mechanism with the
+// client credentials grant, a token is reused until it expires
+type ClientCredentialsTokenSource struct {
+	config     SASLConfig
+	httpClient *http.Client
+
+	lock     sync.Mutex
+	token    string
+	expiry   time.Time
+	lastUsed time.Time
+}
+
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+// the scalers are built on every poll, the token sources are shared per credentials so that their tokens are reused
+var (
+	tokenSourcesLock sync.Mutex
+	tokenSources     = map[string]*ClientCredentialsTokenSource{}
+)
+
+// NewClientCredentialsTokenSource returns the token source for the client credentials of the SASL config
+func NewClientCredentialsTokenSource(config SASLConfig, httpClient *http.Client) *ClientCredentialsTokenSource {
+	return &ClientCredentialsTokenSource{config: config, httpClient: httpClient}
+}
+
+// GetClientCredentialsTokenSource returns the token source shared by the triggers with the client credentials of the SASL config,
+// the token sources unused for tokenSourceIdleTimeout are released
+func GetClientCredentialsTokenSource(config SASLConfig, httpClient *http.Client) *ClientCredentialsTokenSource {
+	key := tokenSourceKey(config)
+	now := time.Now()
+
+	tokenSourcesLock.Lock()
+	defer tokenSourcesLock.Unlock()
+	for k, source := range tokenSources {
+		if now.Sub(source.lastUsed) > tokenSourceIdleTimeout {
+			delete(tokenSources, k)
+		}
+	}
+	source, ok := tokenSources[key]
+	if !ok {
+		source = NewClientCredentialsTokenSource(config, httpClient)
+		tokenSources[key] = source
+	}
+	source.lastUsed = now
+	return source
+}
+
+// tokenSourceKey identifies the client credentials of the SASL config, the password is hashed not to be kept in the keys
+func tokenSourceKey(config SASLConfig) string {
+	password := sha256.Sum256([]byte(config.Password))
+	return fmt.Sprintf("%s\x00%s\x00%x\x00%s", config.TokenEndpointURI, config.Username, password, strings.Join(config.Scopes, " "))
+}
+
+// Token returns a valid access token, requesting a new one from the token endpoint if needed
+func (s *ClientCredentialsTokenSource) Token() (string, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.token != "" && time.Now().Before(s.expiry) {
+		return s.token, nil
+	}
+
+	token, err := s.requestToken()
+	prommetrics.RecordTokenRefresh(clientCredentialsTokenSource, prommetrics.TokenRefreshReasonOnDemand, err)
+	if err != nil {
+		return "", err
+	}
+
+	s.token = token.AccessToken
+	s.expiry = time.Now().Add(tokenLifetime(token.ExpiresIn))
+	return s.token, nil
+}
+
+// tokenLifetime returns how long a token expiring in expiresIn seconds is used, defaultTokenLifetime if the expiry isn't given.
+// The tokens are renewed tokenExpiryDelta before their expiry, or halfway through their lifetime for the short-lived ones
+func tokenLifetime(expiresIn int64) time.Duration {
+	lifetime := time.Duration(expiresIn) * time.Second
+	if lifetime <= 0 {
+		lifetime = defaultTokenLifetime
+	}
+	if lifetime <= 2*tokenExpiryDelta {
+		return lifetime / 2
+	}
+	return lifetime - tokenExpiryDelta
+}
+
+// requestToken requests a new access token from the token endpoint
+func (s *ClientCredentialsTokenSource) requestToken() (tokenResponse, error) {
+	form := url.Values{}
+	form.Set("grant_type", "client_credentials")
+	if len(s.config.Scopes) > 0 {
+		form.Set("scope", strings.Join(s.config.Scopes, " "))
+	}
+	req, err := http.NewRequest(http.MethodPost, s.config.TokenEndpointURI, strings.NewReader(form.Encode()))
+	if err != nil {
+		return tokenResponse{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(url.QueryEscape(s.config.Username), url.QueryEscape(s.config.Password))
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return tokenResponse{}, fmt.Errorf("error requesting oauth token: %s", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return tokenResponse{}, fmt.Errorf("oauth token endpoint returned %d", resp.StatusCode)
+	}
+	token := tokenResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return tokenResponse{}, fmt.Errorf("error decoding oauth token response: %s", err)
+	}
+	if token.AccessToken == "" {
+		return tokenResponse{}, fmt.Errorf("no access token in oauth token response")
+	}
+	return token, nil
+}
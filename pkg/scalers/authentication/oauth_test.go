@@ -0,0 +1,100 @@
+package authentication
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestClientCredentialsTokenSource(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if username, password, ok := r.BasicAuth(); !ok || username != "client" || password != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.FormValue("grant_type") != "client_credentials" || r.FormValue("scope") != "kafka metrics" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write([]byte(`{"access_token": "token", "token_type": "bearer", "expires_in": 3600}`))
+	}))
+	defer server.Close()
+
+	source := NewClientCredentialsTokenSource(SASLConfig{Type: SASLTypeOAuthBearer, Username: "client", Password: "secret", TokenEndpointURI: server.URL, Scopes: []string{"kafka", "metrics"}}, server.Client())
+	for i := 0; i < 2; i++ {
+		token, err := source.Token()
+		if err != nil {
+			t.Fatal("Could not get the token:", err)
+		}
+		if token != "token" {
+			t.Errorf("Expected the token of the endpoint but got %s", token)
+		}
+	}
+	// the token is reused until it expires
+	if requests != 1 {
+		t.Errorf("Expected a single token request but got %d", requests)
+	}
+
+	source = NewClientCredentialsTokenSource(SASLConfig{Type: SASLTypeOAuthBearer, Username: "client", Password: "wrong", TokenEndpointURI: server.URL}, server.Client())
+	if _, err := source.Token(); err == nil {
+		t.Error("Expected an error with wrong client credentials")
+	}
+}
+
+func TestClientCredentialsTokenSourceWithoutExpiry(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		_, _ = w.Write([]byte(`{"access_token": "token", "token_type": "bearer"}`))
+	}))
+	defer server.Close()
+
+	source := NewClientCredentialsTokenSource(SASLConfig{Type: SASLTypeOAuthBearer, Username: "client", Password: "secret", TokenEndpointURI: server.URL}, server.Client())
+	for i := 0; i < 2; i++ {
+		if _, err := source.Token(); err != nil {
+			t.Fatal("Could not get the token:", err)
+		}
+	}
+	// the tokens without expires_in are used for defaultTokenLifetime
+	if requests != 1 {
+		t.Errorf("Expected a single token request but got %d", requests)
+	}
+}
+
+func TestTokenLifetime(t *testing.T) {
+	for expiresIn, expected := range map[int64]time.Duration{
+		3600: time.Hour - tokenExpiryDelta,
+		0:    defaultTokenLifetime - tokenExpiryDelta,
+		-1:   defaultTokenLifetime - tokenExpiryDelta,
+		20:   10 * time.Second,
+	} {
+		if lifetime := tokenLifetime(expiresIn); lifetime != expected {
+			t.Errorf("Expected a lifetime of %s for expires_in %d but got %s", expected, expiresIn, lifetime)
+		}
+	}
+}
+
+func TestGetClientCredentialsTokenSource(t *testing.T) {
+	config := SASLConfig{Type: SASLTypeOAuthBearer, Username: "client", Password: "secret", TokenEndpointURI: "https://auth.example.com/token", Scopes: []string{"kafka"}}
+	source := GetClientCredentialsTokenSource(config, http.DefaultClient)
+	if GetClientCredentialsTokenSource(config, http.DefaultClient) != source {
+		t.Error("Expected the triggers with the same credentials to share the token source")
+	}
+
+	rotated := config
+	rotated.Password = "rotated"
+	if GetClientCredentialsTokenSource(rotated, http.DefaultClient) == source {
+		t.Error("Expected a token source of its own for other credentials")
+	}
+
+	// the token sources no trigger used for tokenSourceIdleTimeout are released
+	tokenSourcesLock.Lock()
+	source.lastUsed = time.Now().Add(-2 * tokenSourceIdleTimeout)
+	tokenSourcesLock.Unlock()
+	if GetClientCredentialsTokenSource(rotated, http.DefaultClient); tokenSources[tokenSourceKey(config)] != nil {
+		t.Error("Expected the idle token source to be released")
+	}
+}
@@ -0,0 +1,75 @@
+package authentication
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// SASLType is the SASL mechanism of a messaging scaler
+type SASLType string
+
+// supported SASL types
+const (
+	SASLTypeNone        SASLType = "none"
+	SASLTypePlaintext   SASLType = "plaintext"
+	SASLTypeSCRAMSHA256 SASLType = "scram_sha256"
+	SASLTypeSCRAMSHA512 SASLType = "scram_sha512"
+	SASLTypeOAuthBearer SASLType = "oauthbearer"
+)
+
+// SASLConfig is the SASL authentication of a messaging scaler, with OAUTHBEARER the username and password
+// are the client credentials exchanged for a token at the token endpoint
+type SASLConfig struct {
+	Type     SASLType
+	Username string
+	Password string
+
+	// OAUTHBEARER
+	TokenEndpointURI string
+	Scopes           []string
+}
+
+// ParseSASLConfig parses the sasl, username and password auth params, and oauthTokenEndpointUri and scopes with OAUTHBEARER,
+// the SASL type is none if no sasl auth param is given
+func ParseSASLConfig(authParams map[string]string) (SASLConfig, error) {
+	config := SASLConfig{Type: SASLTypeNone}
+
+	val, ok := authParams["sasl"]
+	if !ok {
+		return config, nil
+	}
+	mode := SASLType(strings.TrimSpace(val))
+
+	switch mode {
+	case SASLTypePlaintext, SASLTypeSCRAMSHA256, SASLTypeSCRAMSHA512, SASLTypeOAuthBearer:
+	default:
+		return config, fmt.Errorf("err SASL mode %s given", mode)
+	}
+
+	if authParams["username"] == "" {
+		return config, errors.New("no username given")
+	}
+	config.Username = strings.TrimSpace(authParams["username"])
+
+	if authParams["password"] == "" {
+		return config, errors.New("no password given")
+	}
+	config.Password = strings.TrimSpace(authParams["password"])
+
+	if mode == SASLTypeOAuthBearer {
+		if authParams["oauthTokenEndpointUri"] == "" {
+			return config, errors.New("no oauthTokenEndpointUri given")
+		}
+		config.TokenEndpointURI = strings.TrimSpace(authParams["oauthTokenEndpointUri"])
+
+		for _, scope := range strings.Split(authParams["scopes"], ",") {
+			if scope = strings.TrimSpace(scope); scope != "" {
+				config.Scopes = append(config.Scopes, scope)
+			}
+		}
+	}
+
+	config.Type = mode
+	return config, nil
+}
@@ -0,0 +1,35 @@
+package authentication
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseSASLConfig(t *testing.T) {
+	tests := []struct {
+		name       string
+		authParams map[string]string
+		config     SASLConfig
+		isError    bool
+	}{
+		{"no sasl", map[string]string{}, SASLConfig{Type: SASLTypeNone}, false},
+		{"plaintext", map[string]string{"sasl": "plaintext", "username": "admin", "password": "admin"}, SASLConfig{Type: SASLTypePlaintext, Username: "admin", Password: "admin"}, false},
+		{"scram_sha512", map[string]string{"sasl": " scram_sha512 ", "username": " admin ", "password": "admin"}, SASLConfig{Type: SASLTypeSCRAMSHA512, Username: "admin", Password: "admin"}, false},
+		{"missing username", map[string]string{"sasl": "scram_sha256", "password": "admin"}, SASLConfig{}, true},
+		{"missing password", map[string]string{"sasl": "scram_sha256", "username": "admin"}, SASLConfig{}, true},
+		{"wrong mode", map[string]string{"sasl": "gssapi", "username": "admin", "password": "admin"}, SASLConfig{}, true},
+		{"oauthbearer", map[string]string{"sasl": "oauthbearer", "username": "client", "password": "secret", "oauthTokenEndpointUri": "https://idp/token", "scopes": "kafka, metrics"},
+			SASLConfig{Type: SASLTypeOAuthBearer, Username: "client", Password: "secret", TokenEndpointURI: "https://idp/token", Scopes: []string{"kafka", "metrics"}}, false},
+		{"oauthbearer without token endpoint", map[string]string{"sasl": "oauthbearer", "username": "client", "password": "secret"}, SASLConfig{}, true},
+	}
+
+	for _, test := range tests {
+		config, err := ParseSASLConfig(test.authParams)
+		if test.isError != (err != nil) {
+			t.Errorf("%s: expected error %t but got %v", test.name, test.isError, err)
+		}
+		if err == nil && !reflect.DeepEqual(config, test.config) {
+			t.Errorf("%s: expected %+v but got %+v", test.name, test.config, config)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package authentication
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// TLSConfig is the TLS configuration of a messaging scaler, the certificates are PEM encoded
+type TLSConfig struct {
+	Enabled bool
+	CA      string
+	Cert    string
+	Key     string
+}
+
+// ParseTLSConfig parses the tls, ca, cert and key auth params, TLS is enabled with tls set to enable
+func ParseTLSConfig(authParams map[string]string) (TLSConfig, error) {
+	config := TLSConfig{}
+
+	val, ok := authParams["tls"]
+	if !ok {
+		return config, nil
+	}
+	val = strings.TrimSpace(val)
+	if val != "enable" {
+		return config, fmt.Errorf("err incorrect value for TLS given: %s", val)
+	}
+
+	if authParams["ca"] == "" {
+		return config, errors.New("no ca given")
+	}
+	config.CA = authParams["ca"]
+
+	if authParams["cert"] == "" {
+		return config, errors.New("no cert given")
+	}
+	config.Cert = authParams["cert"]
+
+	if authParams["key"] == "" {
+		return config, errors.New("no key given")
+	}
+	config.Key = authParams["key"]
+	config.Enabled = true
+	return config, nil
+}
+
+// NewTLSConfig returns a *tls.Config using the client cert, client key and CA certificate.
+// If none are appropriate, a nil *tls.Config is returned.
+func (c TLSConfig) NewTLSConfig() (*tls.Config, error) {
+	valid := false
+
+	config := &tls.Config{}
+
+	if c.Cert != "" && c.Key != "" {
+		cert, err := tls.X509KeyPair([]byte(c.Cert), []byte(c.Key))
+		if err != nil {
+			return nil, fmt.Errorf("error parse X509KeyPair: %s", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+		valid = true
+	}
+
+	if c.CA != "" {
+		caCertPool := x509.NewCertPool()
+		caCertPool.AppendCertsFromPEM([]byte(c.CA))
+		config.RootCAs = caCertPool
+		config.InsecureSkipVerify = true
+		valid = true
+	}
+
+	if !valid {
+		config = nil
+	}
+
+	return config, nil
+}
@@ -0,0 +1,47 @@
+package authentication
+
+import (
+	"testing"
+)
+
+func TestParseTLSConfig(t *testing.T) {
+	tests := []struct {
+		name       string
+		authParams map[string]string
+		enabled    bool
+		isError    bool
+	}{
+		{"no tls", map[string]string{}, false, false},
+		{"tls", map[string]string{"tls": "enable", "ca": "caaa", "cert": "ceert", "key": "keey"}, true, false},
+		{"wrong value", map[string]string{"tls": "foo", "ca": "caaa", "cert": "ceert", "key": "keey"}, false, true},
+		{"missing ca", map[string]string{"tls": "enable", "cert": "ceert", "key": "keey"}, false, true},
+		{"missing cert", map[string]string{"tls": "enable", "ca": "caaa", "key": "keey"}, false, true},
+		{"missing key", map[string]string{"tls": "enable", "ca": "caaa", "cert": "ceert"}, false, true},
+	}
+
+	for _, test := range tests {
+		config, err := ParseTLSConfig(test.authParams)
+		if test.isError != (err != nil) {
+			t.Errorf("%s: expected error %t but got %v", test.name, test.isError, err)
+		}
+		if config.Enabled != test.enabled {
+			t.Errorf("%s: expected TLS enabled %t but got %t", test.name, test.enabled, config.Enabled)
+		}
+	}
+}
+
+func TestNewTLSConfig(t *testing.T) {
+	config, err := TLSConfig{}.NewTLSConfig()
+	if err != nil || config != nil {
+		t.Errorf("Expected no TLS config without certificates, got %v, %v", config, err)
+	}
+
+	if _, err := (TLSConfig{Cert: "ceert", Key: "keey"}).NewTLSConfig(); err == nil {
+		t.Error("Expected an error for an invalid client certificate")
+	}
+
+	config, err = TLSConfig{CA: "caaa"}.NewTLSConfig()
+	if err != nil || config == nil || config.RootCAs == nil {
+		t.Errorf("Expected a TLS config with the CA, got %v, %v", config, err)
+	}
+}
@@ -157,7 +157,7 @@ func parseAwsCloudwatchMetadata(metadata, resolvedEnv, authParams map[string]str
 }
 
 func (c *awsCloudwatchScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
-	metricValue, err := c.GetCloudwatchMetrics()
+	metricValue, err := c.GetCloudwatchMetrics(ctx)
 
 	if err != nil {
 		cloudwatchLog.Error(err, "Error getting metric value")
@@ -189,7 +189,7 @@ func (c *awsCloudwatchScaler) GetMetricSpecForScaling() []v2beta2.MetricSpec {
 }
 
 func (c *awsCloudwatchScaler) IsActive(ctx context.Context) (bool, error) {
-	val, err := c.GetCloudwatchMetrics()
+	val, err := c.GetCloudwatchMetrics(ctx)
 
 	if err != nil {
 		return false, err
@@ -202,7 +202,7 @@ func (c *awsCloudwatchScaler) Close() error {
 	return nil
 }
 
-func (c *awsCloudwatchScaler) GetCloudwatchMetrics() (float64, error) {
+func (c *awsCloudwatchScaler) GetCloudwatchMetrics(ctx context.Context) (float64, error) {
 	sess := session.Must(session.NewSession(&aws.Config{
 		Region: aws.String(c.metadata.awsRegion),
 	}))
@@ -250,7 +250,7 @@ func (c *awsCloudwatchScaler) GetCloudwatchMetrics() (float64, error) {
 		},
 	}
 
-	output, err := cloudwatchClient.GetMetricData(&input)
+	output, err := cloudwatchClient.GetMetricDataWithContext(ctx, &input)
 
 	if err != nil {
 		cloudwatchLog.Error(err, "Failed to get output")
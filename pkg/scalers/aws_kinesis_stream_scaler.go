@@ -88,7 +88,7 @@ func parseAwsKinesisStreamMetadata(metadata, resolvedEnv, authParams map[string]
 
 // IsActive determines if we need to scale from zero
 func (s *awsKinesisStreamScaler) IsActive(ctx context.Context) (bool, error) {
-	count, err := s.GetAwsKinesisOpenShardCount()
+	count, err := s.GetAwsKinesisOpenShardCount(ctx)
 
 	if err != nil {
 		return false, err
@@ -97,6 +97,11 @@ func (s *awsKinesisStreamScaler) IsActive(ctx context.Context) (bool, error) {
 	return count > 0, nil
 }
 
+// PartitionCount returns the number of open shards of the stream
+func (s *awsKinesisStreamScaler) PartitionCount(ctx context.Context) (int64, error) {
+	return s.GetAwsKinesisOpenShardCount(ctx)
+}
+
 func (s *awsKinesisStreamScaler) Close() error {
 	return nil
 }
@@ -118,7 +123,7 @@ func (s *awsKinesisStreamScaler) GetMetricSpecForScaling() []v2beta2.MetricSpec
 
 //GetMetrics returns value for a supported metric and an error if there is a problem getting the metric
 func (s *awsKinesisStreamScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
-	shardCount, err := s.GetAwsKinesisOpenShardCount()
+	shardCount, err := s.GetAwsKinesisOpenShardCount(ctx)
 
 	if err != nil {
 		kinesisStreamLog.Error(err, "Error getting shard count")
@@ -135,7 +140,7 @@ func (s *awsKinesisStreamScaler) GetMetrics(ctx context.Context, metricName stri
 }
 
 // Get Kinesis open shard count
-func (s *awsKinesisStreamScaler) GetAwsKinesisOpenShardCount() (int64, error) {
+func (s *awsKinesisStreamScaler) GetAwsKinesisOpenShardCount(ctx context.Context) (int64, error) {
 	input := &kinesis.DescribeStreamSummaryInput{
 		StreamName: &s.metadata.streamName,
 	}
@@ -162,7 +167,7 @@ func (s *awsKinesisStreamScaler) GetAwsKinesisOpenShardCount() (int64, error) {
 		})
 	}
 
-	output, err := kinesisClinent.DescribeStreamSummary(input)
+	output, err := kinesisClinent.DescribeStreamSummaryWithContext(ctx, input)
 	if err != nil {
 		return -1, err
 	}
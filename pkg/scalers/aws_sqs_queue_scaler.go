@@ -6,12 +6,14 @@ import (
 	"net/url"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
 	"github.com/aws/aws-sdk-go/service/sqs"
 	v2beta2 "k8s.io/api/autoscaling/v2beta2"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -26,16 +28,33 @@ import (
 const (
 	awsSqsQueueMetricName    = "ApproximateNumberOfMessages"
 	targetQueueLengthDefault = 5
+
+	awsSqsModeQueueLength                   = "QueueLength"
+	awsSqsModeApproximateAgeOfOldestMessage = "ApproximateAgeOfOldestMessage"
+	// the age of the oldest message is only published to CloudWatch, every minute
+	awsSqsCloudwatchNamespace      = "AWS/SQS"
+	awsSqsAgeMetricStatPeriod      = 60
+	awsSqsAgeMetricCollectionTime  = 300
+	awsSqsAgeMetricStat            = "Maximum"
+	awsSqsQueueNameDimensionName   = "QueueName"
+	awsSqsQueueURLHostPrefix       = "sqs."
+	awsSqsLegacyQueueURLHostSuffix = ".queue.amazonaws.com"
 )
 
 type awsSqsQueueScaler struct {
 	metadata *awsSqsQueueMetadata
+	// queueURL is resolved from the queue name with GetQueueUrl on the first query if no queueURL is given
+	queueURL string
 }
 
 type awsSqsQueueMetadata struct {
+	// mode is the metric of the queue the scaler scales on, its length or the age in seconds of its oldest message,
+	// targetQueueLength is the target of the metric
+	mode              string
 	targetQueueLength int
 	queueURL          string
 	queueName         string
+	queueOwnerAccount string
 	awsRegion         string
 	awsAuthorization  awsAuthorizationMetadata
 }
@@ -51,6 +70,7 @@ func NewAwsSqsQueueScaler(resolvedEnv, metadata map[string]string, authParams ma
 
 	return &awsSqsQueueScaler{
 		metadata: meta,
+		queueURL: meta.queueURL,
 	}, nil
 }
 
@@ -68,27 +88,61 @@ func parseAwsSqsQueueMetadata(metadata, resolvedEnv, authParams map[string]strin
 		}
 	}
 
-	if val, ok := metadata["queueURL"]; ok && val != "" {
-		meta.queueURL = val
-	} else {
-		return nil, fmt.Errorf("no queueURL given")
+	meta.mode = awsSqsModeQueueLength
+	if val, ok := metadata["mode"]; ok && val != "" {
+		switch {
+		case strings.EqualFold(val, awsSqsModeQueueLength):
+			meta.mode = awsSqsModeQueueLength
+		case strings.EqualFold(val, awsSqsModeApproximateAgeOfOldestMessage):
+			meta.mode = awsSqsModeApproximateAgeOfOldestMessage
+		default:
+			return nil, fmt.Errorf("mode %s not supported, supported modes are %s and %s", val, awsSqsModeQueueLength, awsSqsModeApproximateAgeOfOldestMessage)
+		}
 	}
 
-	queueURL, err := url.ParseRequestURI(meta.queueURL)
-	if err != nil {
-		return nil, fmt.Errorf("queueURL is not a valid URL")
+	if val, ok := metadata["value"]; ok && val != "" {
+		if _, ok := metadata["queueLength"]; ok {
+			return nil, fmt.Errorf("only one of queueLength or value can be given")
+		}
+		value, err := strconv.Atoi(val)
+		if err != nil {
+			return nil, fmt.Errorf("value parsing error %s", err.Error())
+		}
+		meta.targetQueueLength = value
 	}
 
-	queueURLPath := queueURL.Path
-	queueURLPathParts := strings.Split(queueURLPath, "/")
-	if len(queueURLPathParts) != 3 || len(queueURLPathParts[2]) <= 0 {
-		return nil, fmt.Errorf("cannot get queueName from queueURL")
-	}
+	queueURLRegion := ""
+	if val, ok := metadata["queueURL"]; ok && val != "" {
+		if metadata["queueName"] != "" {
+			return nil, fmt.Errorf("only one of queueURL or queueName can be given")
+		}
+		meta.queueURL = val
+
+		queueURL, err := url.ParseRequestURI(meta.queueURL)
+		if err != nil {
+			return nil, fmt.Errorf("queueURL is not a valid URL")
+		}
+
+		queueURLPath := queueURL.Path
+		queueURLPathParts := strings.Split(queueURLPath, "/")
+		if len(queueURLPathParts) != 3 || len(queueURLPathParts[2]) <= 0 {
+			return nil, fmt.Errorf("cannot get queueName from queueURL")
+		}
 
-	meta.queueName = queueURLPathParts[2]
+		meta.queueName = queueURLPathParts[2]
+		queueURLRegion = getAwsSqsQueueURLRegion(queueURL.Hostname())
+	} else if val, ok := metadata["queueName"]; ok && val != "" {
+		// the URL of the queue is resolved with GetQueueUrl, in the account of the credentials unless the owner account is given
+		meta.queueName = val
+		meta.queueOwnerAccount = metadata["queueOwnerAccountId"]
+	} else {
+		return nil, fmt.Errorf("no queueURL or queueName given")
+	}
 
 	if val, ok := metadata["awsRegion"]; ok && val != "" {
 		meta.awsRegion = val
+	} else if queueURLRegion != "" {
+		meta.awsRegion = queueURLRegion
 	} else {
 		return nil, fmt.Errorf("no awsRegion given")
 	}
@@ -103,15 +157,31 @@ func parseAwsSqsQueueMetadata(metadata, resolvedEnv, authParams map[string]strin
 	return &meta, nil
 }
 
+// getAwsSqsQueueURLRegion returns the region of the host of a queue URL, sqs.<region>.amazonaws.com or the legacy
+// <region>.queue.amazonaws.com, empty if the host isn't an SQS endpoint
+func getAwsSqsQueueURLRegion(host string) string {
+	if strings.HasSuffix(host, awsSqsLegacyQueueURLHostSuffix) {
+		return strings.TrimSuffix(host, awsSqsLegacyQueueURLHostSuffix)
+	}
+	if !strings.HasPrefix(host, awsSqsQueueURLHostPrefix) {
+		return ""
+	}
+	parts := strings.Split(strings.TrimPrefix(host, awsSqsQueueURLHostPrefix), ".")
+	if len(parts) < 3 || parts[1] != "amazonaws" {
+		return ""
+	}
+	return parts[0]
+}
+
 // IsActive determines if we need to scale from zero
 func (s *awsSqsQueueScaler) IsActive(ctx context.Context) (bool, error) {
-	length, err := s.GetAwsSqsQueueLength()
+	value, err := s.getMetricValue(ctx)
 
 	if err != nil {
 		return false, err
 	}
 
-	return length > 0, nil
+	return value > 0, nil
 }
 
 func (s *awsSqsQueueScaler) Close() error {
@@ -120,9 +190,14 @@ func (s *awsSqsQueueScaler) Close() error {
 
 func (s *awsSqsQueueScaler) GetMetricSpecForScaling() []v2beta2.MetricSpec {
 	targetQueueLengthQty := resource.NewQuantity(int64(s.metadata.targetQueueLength), resource.DecimalSI)
+	// the metric name of the queue length is kept for the existing HPAs
+	metricName := fmt.Sprintf("%s-%s", "AWS-SQS-Queue", s.metadata.queueName)
+	if s.metadata.mode == awsSqsModeApproximateAgeOfOldestMessage {
+		metricName = fmt.Sprintf("%s-%s-%s", "AWS-SQS-Queue", s.metadata.mode, s.metadata.queueName)
+	}
 	externalMetric := &v2beta2.ExternalMetricSource{
 		Metric: v2beta2.MetricIdentifier{
-			Name: kedautil.NormalizeString(fmt.Sprintf("%s-%s", "AWS-SQS-Queue", s.metadata.queueName)),
+			Name: kedautil.NormalizeString(metricName),
 		},
 		Target: v2beta2.MetricTarget{
 			Type:         v2beta2.AverageValueMetricType,
@@ -135,10 +210,10 @@ func (s *awsSqsQueueScaler) GetMetricSpecForScaling() []v2beta2.MetricSpec {
 
 //GetMetrics returns value for a supported metric and an error if there is a problem getting the metric
 func (s *awsSqsQueueScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
-	queuelen, err := s.GetAwsSqsQueueLength()
+	queuelen, err := s.getMetricValue(ctx)
 
 	if err != nil {
-		sqsQueueLog.Error(err, "Error getting queue length")
+		sqsQueueLog.Error(err, "Error getting queue metric", "mode", s.metadata.mode)
 		return []external_metrics.ExternalMetricValue{}, err
 	}
 
@@ -151,36 +226,67 @@ func (s *awsSqsQueueScaler) GetMetrics(ctx context.Context, metricName string, m
 	return append([]external_metrics.ExternalMetricValue{}, metric), nil
 }
 
-// Get SQS Queue Length
-func (s *awsSqsQueueScaler) GetAwsSqsQueueLength() (int32, error) {
-	input := &sqs.GetQueueAttributesInput{
-		AttributeNames: aws.StringSlice([]string{awsSqsQueueMetricName}),
-		QueueUrl:       aws.String(s.metadata.queueURL),
+func (s *awsSqsQueueScaler) getMetricValue(ctx context.Context) (int32, error) {
+	if s.metadata.mode == awsSqsModeApproximateAgeOfOldestMessage {
+		return s.GetAwsSqsQueueAgeOfOldestMessage(ctx)
 	}
+	return s.GetAwsSqsQueueLength(ctx)
+}
 
-	sess := session.Must(session.NewSession(&aws.Config{
+// getAwsConfig returns the config of the clients of the queue, with the credentials of the trigger if KEDA isn't the identity owner
+func (s *awsSqsQueueScaler) getAwsConfig(sess *session.Session) *aws.Config {
+	config := &aws.Config{
 		Region: aws.String(s.metadata.awsRegion),
-	}))
-
-	var sqsClient *sqs.SQS
+	}
 	if s.metadata.awsAuthorization.podIdentityOwner {
 		creds := credentials.NewStaticCredentials(s.metadata.awsAuthorization.awsAccessKeyID, s.metadata.awsAuthorization.awsSecretAccessKey, "")
 
 		if s.metadata.awsAuthorization.awsRoleArn != "" {
 			creds = stscreds.NewCredentials(sess, s.metadata.awsAuthorization.awsRoleArn)
 		}
+		config.Credentials = creds
+	}
+	return config
+}
 
-		sqsClient = sqs.New(sess, &aws.Config{
-			Region:      aws.String(s.metadata.awsRegion),
-			Credentials: creds,
-		})
-	} else {
-		sqsClient = sqs.New(sess, &aws.Config{
-			Region: aws.String(s.metadata.awsRegion),
-		})
+// getQueueURL returns the URL of the queue, resolved once from the queue name if no queueURL is given
+func (s *awsSqsQueueScaler) getQueueURL(ctx context.Context, sqsClient *sqs.SQS) (string, error) {
+	if s.queueURL != "" {
+		return s.queueURL, nil
+	}
+
+	input := &sqs.GetQueueUrlInput{
+		QueueName: aws.String(s.metadata.queueName),
 	}
+	if s.metadata.queueOwnerAccount != "" {
+		input.QueueOwnerAWSAccountId = aws.String(s.metadata.queueOwnerAccount)
+	}
+	output, err := sqsClient.GetQueueUrlWithContext(ctx, input)
+	if err != nil {
+		return "", fmt.Errorf("error resolving the URL of queue %s: %s", s.metadata.queueName, err)
+	}
+	s.queueURL = aws.StringValue(output.QueueUrl)
+	return s.queueURL, nil
+}
+
+// Get SQS Queue Length
+func (s *awsSqsQueueScaler) GetAwsSqsQueueLength(ctx context.Context) (int32, error) {
+	sess := session.Must(session.NewSession(&aws.Config{
+		Region: aws.String(s.metadata.awsRegion),
+	}))
+	sqsClient := sqs.New(sess, s.getAwsConfig(sess))
 
-	output, err := sqsClient.GetQueueAttributes(input)
+	queueURL, err := s.getQueueURL(ctx, sqsClient)
+	if err != nil {
+		return -1, err
+	}
+
+	input := &sqs.GetQueueAttributesInput{
+		AttributeNames: aws.StringSlice([]string{awsSqsQueueMetricName}),
+		QueueUrl:       aws.String(queueURL),
+	}
+
+	output, err := sqsClient.GetQueueAttributesWithContext(ctx, input)
 	if err != nil {
 		return -1, err
 	}
@@ -192,3 +298,53 @@ func (s *awsSqsQueueScaler) GetAwsSqsQueueLength() (int32, error) {
 
 	return int32(approximateNumberOfMessages), nil
 }
+
+// GetAwsSqsQueueAgeOfOldestMessage gets the age in seconds of the oldest message of the queue from its
+// ApproximateAgeOfOldestMessage CloudWatch metric, 0 if no datapoint was published as the queue is idle
+func (s *awsSqsQueueScaler) GetAwsSqsQueueAgeOfOldestMessage(ctx context.Context) (int32, error) {
+	sess := session.Must(session.NewSession(&aws.Config{
+		Region: aws.String(s.metadata.awsRegion),
+	}))
+	cloudwatchClient := cloudwatch.New(sess, s.getAwsConfig(sess))
+
+	input := cloudwatch.GetMetricDataInput{
+		StartTime: aws.Time(time.Now().Add(-awsSqsAgeMetricCollectionTime * time.Second)),
+		EndTime:   aws.Time(time.Now()),
+		ScanBy:    aws.String(cloudwatch.ScanByTimestampDescending),
+		MetricDataQueries: []*cloudwatch.MetricDataQuery{
+			{
+				Id: aws.String("age"),
+				MetricStat: &cloudwatch.MetricStat{
+					Metric: &cloudwatch.Metric{
+						Namespace: aws.String(awsSqsCloudwatchNamespace),
+						Dimensions: []*cloudwatch.Dimension{
+							{
+								Name:  aws.String(awsSqsQueueNameDimensionName),
+								Value: aws.String(s.metadata.queueName),
+							},
+						},
+						MetricName: aws.String(awsSqsModeApproximateAgeOfOldestMessage),
+					},
+					Period: aws.Int64(awsSqsAgeMetricStatPeriod),
+					Stat:   aws.String(awsSqsAgeMetricStat),
+				},
+				ReturnData: aws.Bool(true),
+			},
+		},
+	}
+
+	output, err := cloudwatchClient.GetMetricDataWithContext(ctx, &input)
+	if err != nil {
+		return -1, err
+	}
+
+	return getAwsSqsLatestMetricValue(output), nil
+}
+
+// getAwsSqsLatestMetricValue returns the latest value of the metric data, the values are sorted by descending timestamp
+func getAwsSqsLatestMetricValue(output *cloudwatch.GetMetricDataOutput) int32 {
+	if len(output.MetricDataResults) == 0 || len(output.MetricDataResults[0].Values) == 0 {
+		return 0
+	}
+	return int32(aws.Float64Value(output.MetricDataResults[0].Values[0]))
+}
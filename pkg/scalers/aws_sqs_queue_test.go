@@ -2,6 +2,9 @@ package scalers
 
 import (
 	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
 )
 
 const (
@@ -62,8 +65,8 @@ var testAWSSQSMetadata = []parseAWSSQSMetadataTestData{
 		"queueLength": "1",
 		"awsRegion":   ""},
 		testAWSSQSAuthentication,
-		true,
-		"properly formed queue, empty region"},
+		false,
+		"properly formed queue, empty region resolved from the queue URL"},
 	{map[string]string{
 		"queueURL":    testAWSSQSProperQueueURL,
 		"queueLength": "1",
@@ -128,10 +131,64 @@ var testAWSSQSMetadata = []parseAWSSQSMetadataTestData{
 		},
 		false,
 		"with AWS Role assigned on KEDA operator itself"},
+	{map[string]string{
+		"queueURL":    "http://localhost:4566/000000000000/DeleteArtifactQ",
+		"queueLength": "1"},
+		testAWSSQSAuthentication,
+		true,
+		"queue URL of a custom endpoint without region"},
+	{map[string]string{
+		"queueName":           "DeleteArtifactQ",
+		"queueOwnerAccountId": "123456789012",
+		"awsRegion":           "eu-west-1"},
+		testAWSSQSAuthentication,
+		false,
+		"queue name with owner account"},
+	{map[string]string{
+		"queueName": "DeleteArtifactQ"},
+		testAWSSQSAuthentication,
+		true,
+		"queue name without region"},
+	{map[string]string{
+		"queueURL":  testAWSSQSProperQueueURL,
+		"queueName": "DeleteArtifactQ",
+		"awsRegion": "eu-west-1"},
+		testAWSSQSAuthentication,
+		true,
+		"both queue URL and queue name"},
+	{map[string]string{
+		"queueURL": testAWSSQSProperQueueURL,
+		"mode":     "ApproximateAgeOfOldestMessage",
+		"value":    "60"},
+		testAWSSQSAuthentication,
+		false,
+		"age of the oldest message"},
+	{map[string]string{
+		"queueURL": testAWSSQSProperQueueURL,
+		"mode":     "MessagesInFlight"},
+		testAWSSQSAuthentication,
+		true,
+		"unknown mode"},
+	{map[string]string{
+		"queueURL":    testAWSSQSProperQueueURL,
+		"queueLength": "1",
+		"value":       "1"},
+		testAWSSQSAuthentication,
+		true,
+		"both queueLength and value"},
+	{map[string]string{
+		"queueURL": testAWSSQSProperQueueURL,
+		"value":    "a"},
+		testAWSSQSAuthentication,
+		true,
+		"invalid value"},
 }
 
 var awsSQSMetricIdentifiers = []awsSQSMetricIdentifier{
 	{&testAWSSQSMetadata[1], "AWS-SQS-Queue-DeleteArtifactQ"},
+	{&testAWSSQSMetadata[4], "AWS-SQS-Queue-DeleteArtifactQ"},
+	{&testAWSSQSMetadata[13], "AWS-SQS-Queue-DeleteArtifactQ"},
+	{&testAWSSQSMetadata[16], "AWS-SQS-Queue-ApproximateAgeOfOldestMessage-DeleteArtifactQ"},
 }
 
 func TestSQSParseMetadata(t *testing.T) {
@@ -152,7 +209,7 @@ func TestAWSSQSGetMetricSpecForScaling(t *testing.T) {
 		if err != nil {
 			t.Fatal("Could not parse metadata:", err)
 		}
-		mockAWSSQSScaler := awsSqsQueueScaler{meta}
+		mockAWSSQSScaler := awsSqsQueueScaler{metadata: meta}
 
 		metricSpec := mockAWSSQSScaler.GetMetricSpecForScaling()
 		metricName := metricSpec[0].External.Metric.Name
@@ -161,3 +218,32 @@ func TestAWSSQSGetMetricSpecForScaling(t *testing.T) {
 		}
 	}
 }
+
+func TestGetAwsSqsQueueURLRegion(t *testing.T) {
+	tests := map[string]string{
+		"sqs.eu-west-1.amazonaws.com":        "eu-west-1",
+		"sqs.cn-north-1.amazonaws.com.cn":    "cn-north-1",
+		"us-east-2.queue.amazonaws.com":      "us-east-2",
+		"localhost":                          "",
+		"sqs.internal.example.com":           "",
+		"vpce-1a2b3c4d.sqs.eu-west-1.vpce.x": "",
+	}
+
+	for host, region := range tests {
+		if actual := getAwsSqsQueueURLRegion(host); actual != region {
+			t.Errorf("Expected region %q for %s, got %q", region, host, actual)
+		}
+	}
+}
+
+func TestGetAwsSqsLatestMetricValue(t *testing.T) {
+	output := &cloudwatch.GetMetricDataOutput{}
+	if value := getAwsSqsLatestMetricValue(output); value != 0 {
+		t.Errorf("Expected 0 without metric data, got %d", value)
+	}
+
+	output.MetricDataResults = []*cloudwatch.MetricDataResult{{Values: aws.Float64Slice([]float64{120, 60})}}
+	if value := getAwsSqsLatestMetricValue(output); value != 120 {
+		t.Errorf("Expected the latest value 120, got %d", value)
+	}
+}
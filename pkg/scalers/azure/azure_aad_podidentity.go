@@ -1,23 +1,41 @@
 package azure
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/url"
+
+	"go.opentelemetry.io/otel/label"
+
+	prommetrics "github.com/kedacore/keda/pkg/metrics"
+	"github.com/kedacore/keda/pkg/tracing"
+	kedautil "github.com/kedacore/keda/pkg/util"
 )
 
 const (
 	msiURL = "http://169.254.169.254/metadata/identity/oauth2/token?api-version=2018-02-01&resource=%s"
+	// podIdentityTokenSource names the tokens of the identity endpoint in the token metrics
+	podIdentityTokenSource = "azure-ad-pod-identity"
 )
 
 // GetAzureADPodIdentityToken returns the AADToken for resource
-func GetAzureADPodIdentityToken(audience string) (AADToken, error) {
-	var token AADToken
+func GetAzureADPodIdentityToken(audience string) (token AADToken, err error) {
+	ctx, span := tracing.StartSpan(context.TODO(), "Azure.GetAzureADPodIdentityToken", label.String("azure.audience", audience))
+	defer func() {
+		tracing.EndSpan(ctx, span, err)
+		prommetrics.RecordTokenRefresh(podIdentityTokenSource, prommetrics.TokenRefreshReasonOnDemand, err)
+	}()
 
-	resp, err := http.Get(fmt.Sprintf(msiURL, url.QueryEscape(audience)))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf(msiURL, url.QueryEscape(audience)), nil)
+	if err != nil {
+		return token, err
+	}
+	// the identity endpoint is throttled, the token requests are retried with the default policy
+	resp, err := kedautil.DoWithRetry(nil, req, kedautil.DefaultRetryPolicy())
 	if err != nil {
 		return token, err
 	}
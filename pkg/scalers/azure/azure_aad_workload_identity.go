@@ -0,0 +1,107 @@
+package azure
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/label"
+
+	prommetrics "github.com/kedacore/keda/pkg/metrics"
+	"github.com/kedacore/keda/pkg/tracing"
+	kedautil "github.com/kedacore/keda/pkg/util"
+)
+
+// the environment variables injected by the Azure AD workload identity webhook in the pods of a service account bound to an identity
+const (
+	azureAuthorityHostEnv      = "AZURE_AUTHORITY_HOST"
+	azureClientIDEnv           = "AZURE_CLIENT_ID"
+	azureTenantIDEnv           = "AZURE_TENANT_ID"
+	azureFederatedTokenFileEnv = "AZURE_FEDERATED_TOKEN_FILE"
+	defaultAzureAuthorityHost  = "https://login.microsoftonline.com/"
+)
+
+// workloadIdentityTokenSource names the tokens exchanged for the federated token in the token metrics
+const workloadIdentityTokenSource = "azure-ad-workload-identity"
+
+// workloadIdentityTokenResponse is the token response of the v2 endpoint of Azure AD, its expiry is a duration in seconds
+type workloadIdentityTokenResponse struct {
+	AccessToken string `json:"access_token"`
+	ExpiresIn   int64  `json:"expires_in"`
+	TokenType   string `json:"token_type"`
+}
+
+// GetAzureADWorkloadIdentityToken returns the AADToken for resource, the federated service account token of KEDA
+// is exchanged for a token of the identity bound to its service account
+func GetAzureADWorkloadIdentityToken(audience string) (token AADToken, err error) {
+	ctx, span := tracing.StartSpan(context.TODO(), "Azure.GetAzureADWorkloadIdentityToken", label.String("azure.audience", audience))
+	defer func() {
+		tracing.EndSpan(ctx, span, err)
+		prommetrics.RecordTokenRefresh(workloadIdentityTokenSource, prommetrics.TokenRefreshReasonOnDemand, err)
+	}()
+
+	clientID, tenantID, tokenFile := os.Getenv(azureClientIDEnv), os.Getenv(azureTenantIDEnv), os.Getenv(azureFederatedTokenFileEnv)
+	if clientID == "" || tenantID == "" || tokenFile == "" {
+		return token, fmt.Errorf("%s, %s and %s are required for the azure-workload pod identity, the service account of KEDA has to be bound to an identity",
+			azureClientIDEnv, azureTenantIDEnv, azureFederatedTokenFileEnv)
+	}
+	authorityHost := os.Getenv(azureAuthorityHostEnv)
+	if authorityHost == "" {
+		authorityHost = defaultAzureAuthorityHost
+	}
+
+	// the service account token is rotated by the kubelet, it is read for each request
+	assertion, err := ioutil.ReadFile(tokenFile)
+	if err != nil {
+		return token, fmt.Errorf("error reading the federated token: %s", err)
+	}
+
+	form := url.Values{}
+	form.Set("client_id", clientID)
+	form.Set("grant_type", "client_credentials")
+	form.Set("scope", strings.TrimSuffix(audience, "/")+"/.default")
+	form.Set("client_assertion_type", "urn:ietf:params:oauth:client-assertion-type:jwt-bearer")
+	form.Set("client_assertion", strings.TrimSpace(string(assertion)))
+
+	tokenURL := fmt.Sprintf("%s/%s/oauth2/v2.0/token", strings.TrimSuffix(authorityHost, "/"), url.PathEscape(tenantID))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return token, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := kedautil.DoWithRetry(nil, req, kedautil.DefaultRetryPolicy())
+	if err != nil {
+		return token, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return token, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return token, fmt.Errorf("azure ad returned %d for the workload identity token: %s", resp.StatusCode, string(body))
+	}
+
+	tokenResponse := workloadIdentityTokenResponse{}
+	if err := json.Unmarshal(body, &tokenResponse); err != nil {
+		return token, fmt.Errorf("error decoding the workload identity token: %s", err)
+	}
+
+	expiresOn := time.Now().Add(time.Duration(tokenResponse.ExpiresIn) * time.Second).Unix()
+	return AADToken{
+		AccessToken: tokenResponse.AccessToken,
+		ExpiresIn:   strconv.FormatInt(tokenResponse.ExpiresIn, 10),
+		ExpiresOn:   strconv.FormatInt(expiresOn, 10),
+		Resource:    audience,
+		TokenType:   tokenResponse.TokenType,
+	}, nil
+}
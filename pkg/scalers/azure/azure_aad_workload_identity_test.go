@@ -0,0 +1,80 @@
+package azure
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func setWorkloadIdentityEnv(t *testing.T, env map[string]string) {
+	for key, value := range env {
+		previous, ok := os.LookupEnv(key)
+		os.Setenv(key, value)
+		key := key
+		t.Cleanup(func() {
+			if ok {
+				os.Setenv(key, previous)
+			} else {
+				os.Unsetenv(key)
+			}
+		})
+	}
+}
+
+func TestGetAzureADWorkloadIdentityToken(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	if err := ioutil.WriteFile(tokenFile, []byte("federated-token\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/tenant/oauth2/v2.0/token" {
+			t.Errorf("Unexpected token path %s", r.URL.Path)
+		}
+		if err := r.ParseForm(); err != nil {
+			t.Fatal(err)
+		}
+		expected := map[string]string{
+			"client_id":        "client",
+			"grant_type":       "client_credentials",
+			"scope":            "https://servicebus.azure.net/.default",
+			"client_assertion": "federated-token",
+		}
+		for key, value := range expected {
+			if r.PostForm.Get(key) != value {
+				t.Errorf("Expected %s %s, got %s", key, value, r.PostForm.Get(key))
+			}
+		}
+		_, _ = w.Write([]byte(`{"access_token":"access","expires_in":3600,"token_type":"Bearer"}`))
+	}))
+	defer server.Close()
+
+	// the identity isn't bound without the environment of the webhook
+	setWorkloadIdentityEnv(t, map[string]string{azureClientIDEnv: "", azureTenantIDEnv: "", azureFederatedTokenFileEnv: ""})
+	if _, err := GetAzureADWorkloadIdentityToken("https://servicebus.azure.net"); err == nil {
+		t.Error("Expected error without the workload identity environment but got success")
+	}
+
+	setWorkloadIdentityEnv(t, map[string]string{
+		azureAuthorityHostEnv:      server.URL + "/",
+		azureClientIDEnv:           "client",
+		azureTenantIDEnv:           "tenant",
+		azureFederatedTokenFileEnv: tokenFile,
+	})
+	token, err := GetAzureADWorkloadIdentityToken("https://servicebus.azure.net")
+	if err != nil {
+		t.Fatal("Expected success but got error", err)
+	}
+	if token.AccessToken != "access" {
+		t.Errorf("Expected access token access, got %s", token.AccessToken)
+	}
+	expiresOn, err := strconv.ParseInt(token.ExpiresOn, 10, 64)
+	if err != nil || expiresOn < time.Now().Add(59*time.Minute).Unix() {
+		t.Errorf("Expected the token to expire in an hour, got %s", token.ExpiresOn)
+	}
+}
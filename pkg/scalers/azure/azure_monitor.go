@@ -24,8 +24,10 @@ type azureExternalMetricRequest struct {
 	ResourceType              string
 	Aggregation               string
 	Timespan                  string
+	Interval                  *string
 	Filter                    string
 	ResourceGroup             string
+	MetricNamespace           string
 }
 
 // MonitorInfo to create metric request
@@ -35,8 +37,10 @@ type MonitorInfo struct {
 	SubscriptionID      string
 	ResourceGroupName   string
 	Name                string
+	Namespace           string
 	Filter              string
 	AggregationInterval string
+	Granularity         string
 	AggregationType     string
 	ClientID            string
 	ClientPassword      string
@@ -75,11 +79,12 @@ func createMetricsClient(info MonitorInfo, podIdentityEnabled bool) insights.Met
 
 func createMetricsRequest(info MonitorInfo) (*azureExternalMetricRequest, error) {
 	metricRequest := azureExternalMetricRequest{
-		MetricName:     info.Name,
-		SubscriptionID: info.SubscriptionID,
-		Aggregation:    info.AggregationType,
-		Filter:         info.Filter,
-		ResourceGroup:  info.ResourceGroupName,
+		MetricName:      info.Name,
+		SubscriptionID:  info.SubscriptionID,
+		Aggregation:     info.AggregationType,
+		Filter:          info.Filter,
+		ResourceGroup:   info.ResourceGroupName,
+		MetricNamespace: info.Namespace,
 	}
 
 	resourceInfo := strings.Split(info.ResourceURI, "/")
@@ -95,6 +100,14 @@ func createMetricsRequest(info MonitorInfo) (*azureExternalMetricRequest, error)
 
 	metricRequest.Timespan = timespan
 
+	if info.Granularity != "" {
+		interval, err := formatInterval(info.Granularity)
+		if err != nil {
+			return nil, err
+		}
+		metricRequest.Interval = &interval
+	}
+
 	return &metricRequest, nil
 }
 
@@ -120,9 +133,9 @@ func getAzureMetric(ctx context.Context, client insights.MetricsClient, azMetric
 	klog.V(2).Infof("resource uri: %s", metricResourceURI)
 
 	metricResult, err := client.List(ctx, metricResourceURI,
-		azMetricRequest.Timespan, nil,
+		azMetricRequest.Timespan, azMetricRequest.Interval,
 		azMetricRequest.MetricName, azMetricRequest.Aggregation, nil,
-		"", azMetricRequest.Filter, "", "")
+		"", azMetricRequest.Filter, "", azMetricRequest.MetricNamespace)
 	if err != nil {
 		return -1, err
 	}
@@ -203,6 +216,24 @@ func formatTimeSpan(timeSpan string) (string, error) {
 	return fmt.Sprintf("%s/%s", starttime, endtime), nil
 }
 
+// formatInterval converts a hh:mm:ss time grain to the ISO 8601 duration expected by Azure Monitor
+func formatInterval(interval string) (string, error) {
+	parts := strings.Split(interval, ":")
+	if len(parts) != 3 {
+		return "", fmt.Errorf("metricGranularity not in the correct format. Should be hh:mm:ss")
+	}
+	hours, herr := strconv.Atoi(parts[0])
+	minutes, merr := strconv.Atoi(parts[1])
+	seconds, serr := strconv.Atoi(parts[2])
+	if herr != nil || merr != nil || serr != nil {
+		return "", fmt.Errorf("Errors parsing metricGranularity: %v, %v, %v", herr, merr, serr)
+	}
+	if hours < 0 || minutes < 0 || seconds < 0 || hours+minutes+seconds == 0 {
+		return "", fmt.Errorf("metricGranularity must be a positive duration")
+	}
+	return fmt.Sprintf("PT%dH%dM%dS", hours, minutes, seconds), nil
+}
+
 func verifyAggregationTypeIsSupported(aggregationType string, data []insights.MetricValue) (*float64, error) {
 	var valuePtr *float64
 	if strings.EqualFold(string(insights.Average), aggregationType) && data[len(data)-1].Average != nil {
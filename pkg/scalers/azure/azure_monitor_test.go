@@ -45,3 +45,28 @@ func TestAzMonitorextractValue(t *testing.T) {
 		}
 	}
 }
+
+func TestAzMonitorCreateMetricsRequest(t *testing.T) {
+	request, err := createMetricsRequest(MonitorInfo{ResourceURI: "Microsoft.Compute/virtualMachines/vm-1", Namespace: "Azure.VM.Windows.GuestMetrics", Granularity: "0:5:0"})
+	if err != nil {
+		t.Fatal("Could not create metrics request:", err)
+	}
+	if request.MetricNamespace != "Azure.VM.Windows.GuestMetrics" {
+		t.Errorf("Expected metric namespace Azure.VM.Windows.GuestMetrics but got %s", request.MetricNamespace)
+	}
+	if request.Interval == nil || *request.Interval != "PT0H5M0S" {
+		t.Errorf("Expected interval PT0H5M0S but got %v", request.Interval)
+	}
+
+	request, err = createMetricsRequest(MonitorInfo{ResourceURI: "Microsoft.Compute/virtualMachines/vm-1"})
+	if err != nil {
+		t.Fatal("Could not create metrics request:", err)
+	}
+	if request.Interval != nil {
+		t.Errorf("Expected no interval but got %s", *request.Interval)
+	}
+
+	if _, err = createMetricsRequest(MonitorInfo{ResourceURI: "Microsoft.Compute/virtualMachines/vm-1", Granularity: "0:0:0"}); err == nil {
+		t.Error("Expected error for empty granularity but got success")
+	}
+}
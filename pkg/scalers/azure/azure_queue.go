@@ -2,12 +2,42 @@ package azure
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/Azure/azure-storage-queue-go/azqueue"
 )
 
+// QueueLengthStrategy is the way the length of a queue is counted
+type QueueLengthStrategy string
+
+const (
+	// QueueLengthStrategyDefault counts the visible messages, and the approximate count of all the messages
+	// once the peeked messages reach maxPeekedMessages
+	QueueLengthStrategyDefault QueueLengthStrategy = "default"
+	// QueueLengthStrategyAll counts the approximate count of the messages, the invisible ones included
+	QueueLengthStrategyAll QueueLengthStrategy = "all"
+	// QueueLengthStrategyVisibleOnly counts the visible messages only, at most maxPeekedMessages
+	QueueLengthStrategyVisibleOnly QueueLengthStrategy = "visibleOnly"
+)
+
+// maxPeekedMessages is the maximum number of messages a peek returns
+const maxPeekedMessages = 32
+
+// ParseQueueLengthStrategy parses the strategy, the default strategy if it's empty
+func ParseQueueLengthStrategy(strategy string) (QueueLengthStrategy, error) {
+	switch QueueLengthStrategy(strategy) {
+	case "", QueueLengthStrategyDefault:
+		return QueueLengthStrategyDefault, nil
+	case QueueLengthStrategyAll, QueueLengthStrategyVisibleOnly:
+		return QueueLengthStrategy(strategy), nil
+	default:
+		return "", fmt.Errorf("queue length strategy %s not supported, supported strategies are %s, %s and %s",
+			strategy, QueueLengthStrategyDefault, QueueLengthStrategyAll, QueueLengthStrategyVisibleOnly)
+	}
+}
+
 // GetAzureQueueLength returns the length of a queue in int
-func GetAzureQueueLength(ctx context.Context, podIdentity string, connectionString, queueName string, accountName string) (int32, error) {
+func GetAzureQueueLength(ctx context.Context, podIdentity string, connectionString, queueName string, accountName string, strategy QueueLengthStrategy) (int32, error) {
 	credential, endpoint, err := ParseAzureStorageQueueConnection(podIdentity, connectionString, accountName)
 	if err != nil {
 		return -1, err
@@ -16,27 +46,26 @@ func GetAzureQueueLength(ctx context.Context, podIdentity string, connectionStri
 	p := azqueue.NewPipeline(credential, azqueue.PipelineOptions{})
 	serviceURL := azqueue.NewServiceURL(*endpoint, p)
 	queueURL := serviceURL.NewQueueURL(queueName)
-	props, err := queueURL.GetProperties(ctx)
-	if err != nil {
-		return -1, err
+
+	if strategy != QueueLengthStrategyAll {
+		visibleMessageCount, err := getVisibleCount(ctx, &queueURL, maxPeekedMessages)
+		if err != nil {
+			return -1, err
+		}
+		if visibleMessageCount < maxPeekedMessages || strategy == QueueLengthStrategyVisibleOnly {
+			return visibleMessageCount, nil
+		}
 	}
 
-	visibleMessageCount, err := getVisibleCount(&queueURL, 32)
+	props, err := queueURL.GetProperties(ctx)
 	if err != nil {
 		return -1, err
 	}
-	approximateMessageCount := props.ApproximateMessagesCount()
-
-	if visibleMessageCount == 32 {
-		return approximateMessageCount, nil
-	}
-
-	return visibleMessageCount, nil
+	return props.ApproximateMessagesCount(), nil
 }
 
-func getVisibleCount(queueURL *azqueue.QueueURL, maxCount int32) (int32, error) {
+func getVisibleCount(ctx context.Context, queueURL *azqueue.QueueURL, maxCount int32) (int32, error) {
 	messagesURL := queueURL.NewMessagesURL()
-	ctx := context.Background()
 	queue, err := messagesURL.Peek(ctx, maxCount)
 	if err != nil {
 		return 0, err
@@ -7,7 +7,7 @@ import (
 )
 
 func TestGetQueueLength(t *testing.T) {
-	length, err := GetAzureQueueLength(context.TODO(), "", "", "queueName", "")
+	length, err := GetAzureQueueLength(context.TODO(), "", "", "queueName", "", QueueLengthStrategyDefault)
 	if length != -1 {
 		t.Error("Expected length to be -1, but got", length)
 	}
@@ -20,7 +20,7 @@ func TestGetQueueLength(t *testing.T) {
 		t.Error("Expected error to contain parsing error message, but got", err.Error())
 	}
 
-	length, err = GetAzureQueueLength(context.TODO(), "", "DefaultEndpointsProtocol=https;AccountName=name;AccountKey=key==;EndpointSuffix=core.windows.net", "queueName", "")
+	length, err = GetAzureQueueLength(context.TODO(), "", "DefaultEndpointsProtocol=https;AccountName=name;AccountKey=key==;EndpointSuffix=core.windows.net", "queueName", "", QueueLengthStrategyDefault)
 
 	if length != -1 {
 		t.Error("Expected length to be -1, but got", length)
@@ -34,3 +34,30 @@ func TestGetQueueLength(t *testing.T) {
 		t.Error("Expected error to contain base64 error message, but got", err.Error())
 	}
 }
+
+func TestParseQueueLengthStrategy(t *testing.T) {
+	tests := []struct {
+		strategy string
+		expected QueueLengthStrategy
+		isError  bool
+	}{
+		{"", QueueLengthStrategyDefault, false},
+		{"default", QueueLengthStrategyDefault, false},
+		{"all", QueueLengthStrategyAll, false},
+		{"visibleOnly", QueueLengthStrategyVisibleOnly, false},
+		{"invisibleOnly", "", true},
+	}
+
+	for _, test := range tests {
+		strategy, err := ParseQueueLengthStrategy(test.strategy)
+		if err != nil && !test.isError {
+			t.Errorf("Expected success for %q but got error %s", test.strategy, err)
+		}
+		if test.isError && err == nil {
+			t.Errorf("Expected error for %q but got success", test.strategy)
+		}
+		if strategy != test.expected {
+			t.Errorf("Expected strategy %q for %q, got %q", test.expected, test.strategy, strategy)
+		}
+	}
+}
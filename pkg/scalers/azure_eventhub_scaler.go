@@ -268,6 +268,15 @@ func getTotalLagRelatedToPartitionAmount(unprocessedEventsCount int64, partition
 	return unprocessedEventsCount
 }
 
+// PartitionCount returns the number of partitions of the event hub
+func (scaler *azureEventHubScaler) PartitionCount(ctx context.Context) (int64, error) {
+	runtimeInfo, err := scaler.client.GetRuntimeInformation(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("unable to get runtimeInfo for the partition count: %s", err)
+	}
+	return int64(len(runtimeInfo.PartitionIDs)), nil
+}
+
 // Close closes Azure Event Hub Scaler
 func (scaler *azureEventHubScaler) Close() error {
 	if scaler.client != nil {
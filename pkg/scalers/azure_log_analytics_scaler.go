@@ -6,8 +6,10 @@ import (
 	"crypto/sha1"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -22,6 +24,8 @@ import (
 	"k8s.io/metrics/pkg/apis/external_metrics"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
+	prommetrics "github.com/kedacore/keda/pkg/metrics"
+	"github.com/kedacore/keda/pkg/tracing"
 	kedautil "github.com/kedacore/keda/pkg/util"
 )
 
@@ -29,6 +33,23 @@ const (
 	miEndpoint       = "http://169.254.169.254/metadata/identity/oauth2/token?api-version=2018-02-01&resource=https%3A%2F%2Fapi.loganalytics.io%2F"
 	aadTokenEndpoint = "https://login.microsoftonline.com/%s/oauth2/token"
 	laQueryEndpoint  = "https://api.loganalytics.io/v1/workspaces/%s/query"
+	laBatchEndpoint  = "https://api.loganalytics.io/v1/$batch"
+
+	// laMaxBatchSize is the number of queries sent in a single batch request
+	laMaxBatchSize = 10
+
+	laAggregationSum = "sum"
+	laAggregationMax = "max"
+	laAggregationAvg = "avg"
+
+	// laTokenSource names the AAD tokens of the scaler in the token metrics
+	laTokenSource = "azure-log-analytics"
+	// laTokenRenewBefore is how long before its expiry a token is renewed in the background,
+	// the checks only request a token themselves if the renewal failed
+	laTokenRenewBefore = 5 * time.Minute
+	// laTokenRenewalRetryInterval is the delay before a failed renewal is retried, until the token expires
+	laTokenRenewalRetryInterval = 30 * time.Second
+	laTokenRenewalTimeout       = time.Minute
 )
 
 type azureLogAnalyticsScaler struct {
@@ -42,10 +63,23 @@ type azureLogAnalyticsMetadata struct {
 	tenantID     string
 	clientID     string
 	clientSecret string
-	workspaceID  string
-	podIdentity  string
-	query        string
-	threshold    int64
+	workspaceIDs []string
+	// crossWorkspaceQuery runs the query once on the first workspace with the other workspaces in its scope,
+	// otherwise the query runs on every workspace and the results are aggregated
+	crossWorkspaceQuery bool
+	aggregation         string
+	podIdentity         string
+	query               string
+	threshold           int64
+	// valueColumnName and thresholdColumnName select the columns of the query result by name instead of position
+	valueColumnName     string
+	thresholdColumnName string
+	// queryTimeout is the timeout of the query request and the server side timeout of the query, 0 for no timeout
+	queryTimeout time.Duration
+	retryPolicy  kedautil.RetryPolicy
+	// batchWindow is the time the queries sent with the same credentials are collected for, so they are sent in a single batch request,
+	// 0 to send every query on its own
+	batchWindow time.Duration
 }
 
 type sessionCache struct {
@@ -79,13 +113,52 @@ type queryResult struct {
 	} `json:"tables"`
 }
 
+// cachedToken is a token of the cache, with the renewal scheduled before its expiry
+type cachedToken struct {
+	tokenData
+	// used is set once the token is read from the cache, a token unused since its last renewal isn't renewed again
+	used    bool
+	renewal *time.Timer
+}
+
 var tokenCache = struct {
-	sync.RWMutex
-	m map[string]tokenData
-}{m: make(map[string]tokenData)}
+	sync.Mutex
+	m map[string]*cachedToken
+}{m: make(map[string]*cachedToken)}
 
 var logAnalyticsLog = logf.Log.WithName("azure_log_analytics_scaler")
 
+// logAnalyticsBatcher sends the queries of the triggers using the same credentials with the batch API
+var logAnalyticsBatcher = newQueryBatcher(laMaxBatchSize)
+
+// logAnalyticsQuery is a query of a trigger sent in a batch request
+type logAnalyticsQuery struct {
+	workspaceID            string
+	additionalWorkspaceIDs []string
+	query                  string
+}
+
+type logAnalyticsBatchRequest struct {
+	Requests []logAnalyticsBatchItem `json:"requests"`
+}
+
+type logAnalyticsBatchItem struct {
+	ID        string                 `json:"id"`
+	Headers   map[string]string      `json:"headers"`
+	Body      map[string]interface{} `json:"body"`
+	Method    string                 `json:"method"`
+	Path      string                 `json:"path"`
+	Workspace string                 `json:"workspace"`
+}
+
+type logAnalyticsBatchResponse struct {
+	Responses []struct {
+		ID     string          `json:"id"`
+		Status int             `json:"status"`
+		Body   json.RawMessage `json:"body"`
+	} `json:"responses"`
+}
+
 // NewAzureLogAnalyticsScaler creates a new Azure Log Analytics Scaler
 func NewAzureLogAnalyticsScaler(resolvedSecrets, metadata, authParams map[string]string, podIdentity string, name string, namespace string) (Scaler, error) {
 	azureLogAnalyticsMetadata, err := parseAzureLogAnalyticsMetadata(resolvedSecrets, metadata, authParams, podIdentity)
@@ -145,17 +218,47 @@ func parseAzureLogAnalyticsMetadata(resolvedEnv, metadata, authParams map[string
 		return nil, fmt.Errorf("Error parsing metadata. Details: Log Analytics Scaler doesn't support pod identity %s", podIdentity)
 	}
 
-	//Getting workspaceId
+	//Getting workspaceId, a comma separated list for the telemetry split across several workspaces
+	workspaceIDs := ""
 	if val, ok := authParams["workspaceId"]; ok && val != "" {
-		meta.workspaceID = val
+		workspaceIDs = val
 	} else if val, ok := metadata["workspaceId"]; ok && val != "" {
-		meta.workspaceID = val
+		workspaceIDs = val
 	} else if val, ok := metadata["workspaceIdFromEnv"]; ok && val != "" {
-		meta.workspaceID = resolvedEnv[metadata["workspaceIdFromEnv"]]
-	} else {
+		workspaceIDs = resolvedEnv[metadata["workspaceIdFromEnv"]]
+	}
+	for _, workspaceID := range strings.Split(workspaceIDs, ",") {
+		if workspaceID = strings.TrimSpace(workspaceID); workspaceID != "" {
+			meta.workspaceIDs = append(meta.workspaceIDs, workspaceID)
+		}
+	}
+	if len(meta.workspaceIDs) == 0 {
 		return nil, fmt.Errorf("Error parsing metadata. Details: workspaceId was not found in metadata. Check your ScaledObject configuration")
 	}
 
+	//Getting crossWorkspaceQuery
+	if val, ok := metadata["crossWorkspaceQuery"]; ok && val != "" {
+		crossWorkspaceQuery, err := strconv.ParseBool(val)
+		if err != nil {
+			return nil, fmt.Errorf("Error parsing metadata. Details: can't parse crossWorkspaceQuery. Inner Error: %v", err)
+		}
+		meta.crossWorkspaceQuery = crossWorkspaceQuery
+	}
+
+	//Getting aggregation of the results of the workspaces
+	meta.aggregation = laAggregationSum
+	if val, ok := metadata["aggregation"]; ok && val != "" {
+		switch aggregation := strings.ToLower(val); aggregation {
+		case laAggregationSum, laAggregationMax, laAggregationAvg:
+			meta.aggregation = aggregation
+		default:
+			return nil, fmt.Errorf("Error parsing metadata. Details: aggregation %s is not supported, supported aggregations are sum, max and avg", val)
+		}
+		if meta.crossWorkspaceQuery {
+			return nil, fmt.Errorf("Error parsing metadata. Details: aggregation can't be used with crossWorkspaceQuery, the query aggregates the results itself")
+		}
+	}
+
 	//Getting query
 	if val, ok := metadata["query"]; ok && val != "" {
 		meta.query = val
@@ -182,12 +285,44 @@ func parseAzureLogAnalyticsMetadata(resolvedEnv, metadata, authParams map[string
 		return nil, fmt.Errorf("Error parsing metadata. Details: threshold was not found in metadata. Check your ScaledObject configuration")
 	}
 
+	//Getting the names of the value and threshold columns
+	if val, ok := metadata["valueColumnName"]; ok && val != "" {
+		meta.valueColumnName = val
+	}
+	if val, ok := metadata["thresholdColumnName"]; ok && val != "" {
+		meta.thresholdColumnName = val
+	}
+
+	//Getting queryTimeout in seconds
+	if val, ok := metadata["queryTimeout"]; ok && val != "" {
+		queryTimeout, err := strconv.Atoi(val)
+		if err != nil {
+			return nil, fmt.Errorf("Error parsing metadata. Details: can't parse queryTimeout. Inner Error: %v", err)
+		}
+		if queryTimeout <= 0 {
+			return nil, fmt.Errorf("Error parsing metadata. Details: queryTimeout should be greater than 0 seconds, but received %d", queryTimeout)
+		}
+		meta.queryTimeout = time.Duration(queryTimeout) * time.Second
+	}
+
+	retryPolicy, err := parseHTTPRetryPolicy(metadata)
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing metadata. Details: %v", err)
+	}
+	meta.retryPolicy = retryPolicy
+
+	batchWindow, err := parseQueryBatchWindow(metadata)
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing metadata. Details: %v", err)
+	}
+	meta.batchWindow = batchWindow
+
 	return &meta, nil
 }
 
 // IsActive determines if we need to scale from zero
 func (s *azureLogAnalyticsScaler) IsActive(ctx context.Context) (bool, error) {
-	err := s.updateCache()
+	err := s.updateCache(ctx)
 
 	if err != nil {
 		return false, fmt.Errorf("Failed to execute IsActive function. Scaled object: %s. Namespace: %s. Inner Error: %v", s.name, s.namespace, err)
@@ -197,7 +332,7 @@ func (s *azureLogAnalyticsScaler) IsActive(ctx context.Context) (bool, error) {
 }
 
 func (s *azureLogAnalyticsScaler) GetMetricSpecForScaling() []v2beta2.MetricSpec {
-	err := s.updateCache()
+	err := s.updateCache(context.TODO())
 
 	if err != nil {
 		logAnalyticsLog.V(1).Info("Failed to get metric spec.", "Scaled object", s.name, "Namespace", s.namespace, "Inner Error", err)
@@ -206,7 +341,7 @@ func (s *azureLogAnalyticsScaler) GetMetricSpecForScaling() []v2beta2.MetricSpec
 
 	externalMetric := &v2beta2.ExternalMetricSource{
 		Metric: v2beta2.MetricIdentifier{
-			Name: kedautil.NormalizeString(fmt.Sprintf("%s-%s", "azure-log-analytics", s.metadata.workspaceID)),
+			Name: kedautil.NormalizeString(fmt.Sprintf("%s-%s", "azure-log-analytics", strings.Join(s.metadata.workspaceIDs, "-"))),
 		},
 		Target: v2beta2.MetricTarget{
 			Type:         v2beta2.AverageValueMetricType,
@@ -219,7 +354,7 @@ func (s *azureLogAnalyticsScaler) GetMetricSpecForScaling() []v2beta2.MetricSpec
 
 //GetMetrics returns value for a supported metric and an error if there is a problem getting the metric
 func (s *azureLogAnalyticsScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
-	receivedMetric, err := s.getMetricData()
+	receivedMetric, err := s.getMetricData(ctx)
 
 	if err != nil {
 		return []external_metrics.ExternalMetricValue{}, fmt.Errorf("Failed to get metrics. Scaled object: %s. Namespace: %s. Inner Error: %v", s.name, s.namespace, err)
@@ -238,9 +373,9 @@ func (s *azureLogAnalyticsScaler) Close() error {
 	return nil
 }
 
-func (s *azureLogAnalyticsScaler) updateCache() error {
+func (s *azureLogAnalyticsScaler) updateCache(ctx context.Context) error {
 	if s.cache.metricValue < 0 {
-		receivedMetric, err := s.getMetricData()
+		receivedMetric, err := s.getMetricData(ctx)
 
 		if err != nil {
 			return err
@@ -258,15 +393,28 @@ func (s *azureLogAnalyticsScaler) updateCache() error {
 	return nil
 }
 
-func (s *azureLogAnalyticsScaler) getMetricData() (metricsData, error) {
-	tokenInfo, err := s.getAccessToken()
+func (s *azureLogAnalyticsScaler) getMetricData(ctx context.Context) (metricsData, error) {
+	tokenInfo, err := s.getAccessToken(ctx)
 	if err != nil {
 		return metricsData{}, err
 	}
 
-	metricsInfo, err := s.executeQuery(s.metadata.query, tokenInfo)
-	if err != nil {
-		return metricsData{}, err
+	var metricsInfo metricsData
+	if s.metadata.crossWorkspaceQuery || len(s.metadata.workspaceIDs) == 1 {
+		metricsInfo, err = s.executeQuery(ctx, s.metadata.workspaceIDs[0], s.metadata.workspaceIDs[1:], s.metadata.query, tokenInfo)
+		if err != nil {
+			return metricsData{}, err
+		}
+	} else {
+		results := make([]metricsData, 0, len(s.metadata.workspaceIDs))
+		for _, workspaceID := range s.metadata.workspaceIDs {
+			workspaceMetricsInfo, err := s.executeQuery(ctx, workspaceID, nil, s.metadata.query, tokenInfo)
+			if err != nil {
+				return metricsData{}, fmt.Errorf("Error querying workspace %s. Inner Error: %v", workspaceID, err)
+			}
+			results = append(results, workspaceMetricsInfo)
+		}
+		metricsInfo = aggregateLogAnalyticsMetrics(results, s.metadata.aggregation)
 	}
 
 	logAnalyticsLog.V(1).Info("Providing metric value", "metrics value", metricsInfo.value, "scaler name", s.name, "namespace", s.namespace)
@@ -274,58 +422,146 @@ func (s *azureLogAnalyticsScaler) getMetricData() (metricsData, error) {
 	return metricsInfo, nil
 }
 
-func (s *azureLogAnalyticsScaler) getAccessToken() (tokenData, error) {
+func (s *azureLogAnalyticsScaler) getAccessToken(ctx context.Context) (tokenData, error) {
 	//if there is no token yet or it will be expired in less, that 30 secs
 	currentTimeSec := time.Now().Unix()
-	tokenInfo := tokenData{}
+	tokenInfo, _ := s.getTokenFromCache()
+
+	if currentTimeSec+30 > tokenInfo.ExpiresOn {
+		return s.renewAccessToken(ctx, prommetrics.TokenRefreshReasonOnDemand)
+	}
+	return tokenInfo, nil
+}
+
+// renewAccessToken requests a new token for the credentials of the trigger and caches it, its renewal is scheduled before its expiry
+func (s *azureLogAnalyticsScaler) renewAccessToken(ctx context.Context, reason string) (tokenData, error) {
+	tokenInfo, err := s.refreshAccessToken(ctx)
+	prommetrics.RecordTokenRefresh(laTokenSource, reason, err)
+	if err != nil {
+		return tokenData{}, err
+	}
 
 	if s.metadata.podIdentity == "" {
-		tokenInfo, _ = getTokenFromCache(s.metadata.clientID, s.metadata.clientSecret)
+		logAnalyticsLog.V(1).Info("Token for Service Principal has been refreshed", "clientID", s.metadata.clientID, "reason", reason, "scaler name", s.name, "namespace", s.namespace)
 	} else {
-		tokenInfo, _ = getTokenFromCache(s.metadata.podIdentity, s.metadata.podIdentity)
+		logAnalyticsLog.V(1).Info("Token for Pod Identity has been refreshed", "type", s.metadata.podIdentity, "reason", reason, "scaler name", s.name, "namespace", s.namespace)
 	}
+	// the token requested by a check is used right away, a token renewed in the background has to be used before its next renewal
+	_ = s.setTokenInCache(tokenInfo, reason != prommetrics.TokenRefreshReasonProactive)
+	return tokenInfo, nil
+}
 
-	if currentTimeSec+30 > tokenInfo.ExpiresOn {
-		newTokenInfo, err := s.refreshAccessToken()
+// renewAccessTokenInBackground renews the cached token of the trigger before its expiry, so the checks don't wait for the token requests.
+// A token that no check used since its previous renewal is dropped, its triggers are gone.
+func (s *azureLogAnalyticsScaler) renewAccessTokenInBackground(key string) {
+	tokenCache.Lock()
+	cached, ok := tokenCache.m[key]
+	if ok && !cached.used {
+		delete(tokenCache.m, key)
+	}
+	tokenCache.Unlock()
+	if !ok || !cached.used {
+		logAnalyticsLog.V(1).Info("Token unused since its renewal isn't renewed anymore", "scaler name", s.name, "namespace", s.namespace)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), laTokenRenewalTimeout)
+	defer cancel()
+	if _, err := s.renewAccessToken(ctx, prommetrics.TokenRefreshReasonProactive); err != nil {
+		logAnalyticsLog.Error(err, "Failed to renew the token in the background", "scaler name", s.name, "namespace", s.namespace)
+
+		// the renewal is retried until the token expires, the checks request a new token themselves afterwards
+		tokenCache.Lock()
+		defer tokenCache.Unlock()
+		if tokenCache.m[key] == cached && time.Now().Add(laTokenRenewalRetryInterval).Unix() < cached.ExpiresOn {
+			cached.renewal = time.AfterFunc(laTokenRenewalRetryInterval, func() { s.renewAccessTokenInBackground(key) })
+		}
+	}
+}
+
+// getColumnIndexes returns the indexes of the value and threshold columns of the query result, -1 if there is no such column.
+// The columns are selected by name if valueColumnName or thresholdColumnName are set, otherwise the value is the first column
+// and the threshold the second one.
+func (m *azureLogAnalyticsMetadata) getColumnIndexes(columnNames []string, rowLength int) (int, int, error) {
+	if m.valueColumnName == "" && m.thresholdColumnName == "" {
+		valueIndex, thresholdIndex := -1, -1
+		if rowLength > 0 {
+			valueIndex = 0
+		}
+		if rowLength > 1 {
+			thresholdIndex = 1
+		}
+		return valueIndex, thresholdIndex, nil
+	}
+
+	findColumn := func(name string) (int, error) {
+		for i, columnName := range columnNames {
+			if columnName == name && i < rowLength {
+				return i, nil
+			}
+		}
+		return -1, fmt.Errorf("column %s not found in query result, columns are %s", name, strings.Join(columnNames, ", "))
+	}
+
+	valueIndex := -1
+	if m.valueColumnName == "" && rowLength > 0 {
+		valueIndex = 0
+	} else if m.valueColumnName != "" {
+		index, err := findColumn(m.valueColumnName)
 		if err != nil {
-			return tokenData{}, err
+			return -1, -1, err
 		}
+		valueIndex = index
+	}
 
-		if s.metadata.podIdentity == "" {
-			logAnalyticsLog.V(1).Info("Token for Service Principal has been refreshed", "clientID", s.metadata.clientID, "scaler name", s.name, "namespace", s.namespace)
-			_ = setTokenInCache(s.metadata.clientID, s.metadata.clientSecret, newTokenInfo)
-		} else {
-			logAnalyticsLog.V(1).Info("Token for Pod Identity has been refreshed", "type", s.metadata.podIdentity, "scaler name", s.name, "namespace", s.namespace)
-			_ = setTokenInCache(s.metadata.podIdentity, s.metadata.podIdentity, newTokenInfo)
+	// the threshold isn't taken positionally once the columns are selected by name
+	thresholdIndex := -1
+	if m.thresholdColumnName != "" {
+		index, err := findColumn(m.thresholdColumnName)
+		if err != nil {
+			return -1, -1, err
 		}
+		thresholdIndex = index
+	}
+
+	return valueIndex, thresholdIndex, nil
+}
 
-		return newTokenInfo, nil
+// aggregateLogAnalyticsMetrics aggregates the results of the query on several workspaces,
+// the threshold is the highest threshold returned by the query, -1 if the query returns none
+func aggregateLogAnalyticsMetrics(results []metricsData, aggregation string) metricsData {
+	aggregated := metricsData{threshold: -1}
+	for _, result := range results {
+		switch aggregation {
+		case laAggregationMax:
+			if result.value > aggregated.value {
+				aggregated.value = result.value
+			}
+		default:
+			aggregated.value += result.value
+		}
+		if result.threshold > aggregated.threshold {
+			aggregated.threshold = result.threshold
+		}
 	}
-	return tokenInfo, nil
+	if aggregation == laAggregationAvg && len(results) > 0 {
+		aggregated.value /= int64(len(results))
+	}
+	return aggregated
 }
 
-func (s *azureLogAnalyticsScaler) executeQuery(query string, tokenInfo tokenData) (metricsData, error) {
+func (s *azureLogAnalyticsScaler) executeQuery(ctx context.Context, workspaceID string, additionalWorkspaceIDs []string, query string, tokenInfo tokenData) (metricsData, error) {
 	queryData := queryResult{}
 
-	body, statusCode, err := s.executeLogAnalyticsREST(query, tokenInfo)
+	body, statusCode, err := s.queryLogAnalytics(ctx, workspaceID, additionalWorkspaceIDs, query, tokenInfo)
 
 	//Handle expired token
 	if statusCode == 403 || (len(body) > 0 && strings.Contains(string(body), "TokenExpired")) {
-		tokenInfo, err := s.refreshAccessToken()
-
-		if s.metadata.podIdentity == "" {
-			logAnalyticsLog.V(1).Info("Token for Service Principal has been refreshed", "clientID", s.metadata.clientID, "scaler name", s.name, "namespace", s.namespace)
-			_ = setTokenInCache(s.metadata.clientID, s.metadata.clientSecret, tokenInfo)
-		} else {
-			logAnalyticsLog.V(1).Info("Token for Pod Identity has been refreshed", "type", s.metadata.podIdentity, "scaler name", s.name, "namespace", s.namespace)
-			_ = setTokenInCache(s.metadata.podIdentity, s.metadata.podIdentity, tokenInfo)
-		}
-
-		if err == nil {
-			body, statusCode, err = s.executeLogAnalyticsREST(query, tokenInfo)
-		} else {
-			return metricsData{}, err
+		tokenInfo, renewErr := s.renewAccessToken(ctx, prommetrics.TokenRefreshReasonRejected)
+		if renewErr != nil {
+			return metricsData{}, renewErr
 		}
+		body, statusCode, err = s.queryLogAnalytics(ctx, workspaceID, additionalWorkspaceIDs, query, tokenInfo)
 	}
 
 	if statusCode != 200 && statusCode != 0 {
@@ -359,9 +595,18 @@ func (s *azureLogAnalyticsScaler) executeQuery(query string, tokenInfo tokenData
 			return metricsData{}, fmt.Errorf("Error validating Log Analytics request. Details: too many rows in query result: %d, expected: 1. HTTP code: %d. Body: %s", len(queryData.Tables[0].Rows), statusCode, string(body))
 		}
 
-		if len(queryData.Tables[0].Rows[0]) > 0 {
-			metricDataType := queryData.Tables[0].Columns[0].Type
-			metricVal := queryData.Tables[0].Rows[0][0]
+		columnNames := make([]string, len(queryData.Tables[0].Columns))
+		for i, column := range queryData.Tables[0].Columns {
+			columnNames[i] = column.Name
+		}
+		valueIndex, thresholdIndex, err := s.metadata.getColumnIndexes(columnNames, len(queryData.Tables[0].Rows[0]))
+		if err != nil {
+			return metricsData{}, fmt.Errorf("Error validating Log Analytics request. Details: %v. HTTP code: %d. Body: %s", err, statusCode, string(body))
+		}
+
+		if valueIndex >= 0 {
+			metricDataType := queryData.Tables[0].Columns[valueIndex].Type
+			metricVal := queryData.Tables[0].Rows[0][valueIndex]
 
 			if metricVal != nil {
 				//type can be: real, int, long
@@ -380,9 +625,9 @@ func (s *azureLogAnalyticsScaler) executeQuery(query string, tokenInfo tokenData
 			}
 		}
 
-		if len(queryData.Tables[0].Rows[0]) > 1 {
-			thresholdDataType := queryData.Tables[0].Columns[1].Type
-			thresholdVal := queryData.Tables[0].Rows[0][1]
+		if thresholdIndex >= 0 {
+			thresholdDataType := queryData.Tables[0].Columns[thresholdIndex].Type
+			thresholdVal := queryData.Tables[0].Rows[0][thresholdIndex]
 
 			if thresholdVal != nil {
 				//type can be: real, int, long
@@ -411,8 +656,11 @@ func (s *azureLogAnalyticsScaler) executeQuery(query string, tokenInfo tokenData
 	return metricsData{}, fmt.Errorf("Error processing Log Analytics request. Details: unknown error. HTTP code: %d. Body: %s", statusCode, string(body))
 }
 
-func (s *azureLogAnalyticsScaler) refreshAccessToken() (tokenData, error) {
-	tokenInfo, err := s.getAuthorizationToken()
+func (s *azureLogAnalyticsScaler) refreshAccessToken(ctx context.Context) (_ tokenData, err error) {
+	ctx, span := tracing.StartSpan(ctx, "AzureLogAnalytics.refreshAccessToken", tracing.ObjectAttributes("ScaledObject", s.namespace, s.name)...)
+	defer func() { tracing.EndSpan(ctx, span, err) }()
+
+	tokenInfo, err := s.getAuthorizationToken(ctx)
 
 	if err != nil {
 		return tokenData{}, err
@@ -424,7 +672,11 @@ func (s *azureLogAnalyticsScaler) refreshAccessToken() (tokenData, error) {
 		if currentTimeSec < tokenInfo.NotBefore+10 {
 			sleepDurationSec := int(tokenInfo.NotBefore - currentTimeSec + 1)
 			logAnalyticsLog.V(1).Info("AAD token not ready", "delay (seconds)", sleepDurationSec, "scaler name", s.name, "namespace", s.namespace)
-			time.Sleep(time.Duration(sleepDurationSec) * time.Second)
+			select {
+			case <-time.After(time.Duration(sleepDurationSec) * time.Second):
+			case <-ctx.Done():
+				return tokenData{}, fmt.Errorf("Error getting access token. Details: AAD token not ready before the context was done. Inner Error: %v", ctx.Err())
+			}
 		} else {
 			return tokenData{}, fmt.Errorf("Error getting access token. Details: AAD token has been received, but start date begins in %d seconds, so current operation will be skipped", tokenInfo.NotBefore-currentTimeSec)
 		}
@@ -433,12 +685,12 @@ func (s *azureLogAnalyticsScaler) refreshAccessToken() (tokenData, error) {
 	return tokenInfo, nil
 }
 
-func (s *azureLogAnalyticsScaler) getAuthorizationToken() (tokenData, error) {
+func (s *azureLogAnalyticsScaler) getAuthorizationToken(ctx context.Context) (tokenData, error) {
 	body, statusCode, err, tokenInfo := []byte{}, 0, *new(error), tokenData{}
 	if s.metadata.podIdentity == "" {
-		body, statusCode, err = s.executeAADApicall()
+		body, statusCode, err = s.executeAADApicall(ctx)
 	} else {
-		body, statusCode, err = s.executeIMDSApicall()
+		body, statusCode, err = s.executeIMDSApicall(ctx)
 	}
 
 	if err != nil {
@@ -459,15 +711,19 @@ func (s *azureLogAnalyticsScaler) getAuthorizationToken() (tokenData, error) {
 	return tokenData{}, fmt.Errorf("Error getting access token. Details: unknown error. HTTP code: %d. Body: %s", statusCode, string(body))
 }
 
-func (s *azureLogAnalyticsScaler) executeLogAnalyticsREST(query string, tokenInfo tokenData) ([]byte, int, error) {
+func (s *azureLogAnalyticsScaler) executeLogAnalyticsREST(ctx context.Context, workspaceID string, additionalWorkspaceIDs []string, query string, tokenInfo tokenData) ([]byte, int, error) {
 	m := map[string]interface{}{"query": query}
+	if len(additionalWorkspaceIDs) > 0 {
+		// the query can reference the additional workspaces, e.g. with a union
+		m["workspaces"] = additionalWorkspaceIDs
+	}
 
 	jsonBytes, err := json.Marshal(m)
 	if err != nil {
 		return nil, 0, fmt.Errorf("Can't construct JSON for request to Log Analytics API. Inner Error: %v", err)
 	}
 
-	request, err := http.NewRequest(http.MethodPost, fmt.Sprintf(laQueryEndpoint, s.metadata.workspaceID), bytes.NewBuffer(jsonBytes)) // URL-encoded payload
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf(laQueryEndpoint, workspaceID), bytes.NewBuffer(jsonBytes)) // URL-encoded payload
 	if err != nil {
 		return nil, 0, fmt.Errorf("Can't construct HTTP request to Log Analytics API. Inner Error: %v", err)
 	}
@@ -475,11 +731,136 @@ func (s *azureLogAnalyticsScaler) executeLogAnalyticsREST(query string, tokenInf
 	request.Header.Add("Content-Type", "application/json")
 	request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", tokenInfo.AccessToken))
 	request.Header.Add("Content-Length", fmt.Sprintf("%d", len(jsonBytes)))
+	if s.metadata.queryTimeout > 0 {
+		// the server stops the query after the timeout instead of running it after the request gave up
+		request.Header.Add("Prefer", fmt.Sprintf("wait=%d", int(s.metadata.queryTimeout.Seconds())))
+	}
+
+	body, statusCode, err := s.runHTTPWithTimeout(request, "Log Analytics REST api", s.metadata.queryTimeout)
+	if err != nil && isTimeoutError(err) {
+		return nil, 0, fmt.Errorf("Log Analytics query timed out after %s, increase queryTimeout or optimize the query. Inner Error: %v", s.metadata.queryTimeout, err)
+	}
+	return body, statusCode, err
+}
+
+// queryLogAnalytics returns the body and the status code of the response to the query. With a batch window the queries
+// of the triggers using the same token and timeout within the window are sent in a single request to the batch API.
+func (s *azureLogAnalyticsScaler) queryLogAnalytics(ctx context.Context, workspaceID string, additionalWorkspaceIDs []string, query string, tokenInfo tokenData) ([]byte, int, error) {
+	if s.metadata.batchWindow == 0 {
+		return s.executeLogAnalyticsREST(ctx, workspaceID, additionalWorkspaceIDs, query, tokenInfo)
+	}
+
+	tokenHash, err := getHash(tokenInfo.AccessToken, "")
+	if err != nil {
+		return nil, 0, fmt.Errorf("Error calculating sha1 hash. Inner Error: %v", err)
+	}
+	endpoint := fmt.Sprintf("%s|%s|%v", tokenHash, s.metadata.queryTimeout, s.metadata.retryPolicy)
+	batched := batchedQuery{
+		key:     fmt.Sprintf("%s|%s|%s", workspaceID, strings.Join(additionalWorkspaceIDs, ","), query),
+		payload: logAnalyticsQuery{workspaceID: workspaceID, additionalWorkspaceIDs: additionalWorkspaceIDs, query: query},
+	}
+	response := logAnalyticsBatcher.do(ctx, endpoint, s.metadata.batchWindow, batched, func(queries []batchedQuery) []batchedResponse {
+		return s.executeLogAnalyticsBatch(queries, tokenInfo)
+	})
+	return response.body, response.statusCode, response.err
+}
+
+// executeLogAnalyticsBatch sends the queries in a single request to the batch API, the response of each query
+// has the status code and the body it would have if it was sent on its own. The batch is shared by the triggers,
+// it is bound to the queryTimeout instead of the context of one of them
+func (s *azureLogAnalyticsScaler) executeLogAnalyticsBatch(queries []batchedQuery, tokenInfo tokenData) []batchedResponse {
+	responses := make([]batchedResponse, len(queries))
+	failAll := func(statusCode int, err error) []batchedResponse {
+		for i := range responses {
+			responses[i] = batchedResponse{statusCode: statusCode, err: err}
+		}
+		return responses
+	}
+
+	jsonBytes, err := buildLogAnalyticsBatchRequest(queries, s.metadata.queryTimeout)
+	if err != nil {
+		return failAll(0, fmt.Errorf("Can't construct JSON for batch request to Log Analytics API. Inner Error: %v", err))
+	}
+
+	request, err := http.NewRequest(http.MethodPost, laBatchEndpoint, bytes.NewBuffer(jsonBytes))
+	if err != nil {
+		return failAll(0, fmt.Errorf("Can't construct HTTP batch request to Log Analytics API. Inner Error: %v", err))
+	}
+	request.Header.Add("Content-Type", "application/json")
+	request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", tokenInfo.AccessToken))
+	request.Header.Add("Content-Length", fmt.Sprintf("%d", len(jsonBytes)))
+
+	body, statusCode, err := s.runHTTPWithTimeout(request, "Log Analytics batch api", s.metadata.queryTimeout)
+	if err != nil {
+		if isTimeoutError(err) {
+			err = fmt.Errorf("Log Analytics batch query timed out after %s, increase queryTimeout or optimize the query. Inner Error: %v", s.metadata.queryTimeout, err)
+		}
+		return failAll(0, err)
+	}
+	if statusCode != 200 {
+		// the batch failed as a whole, e.g. with an expired token, every query gets the response
+		for i := range responses {
+			responses[i] = batchedResponse{body: body, statusCode: statusCode}
+		}
+		return responses
+	}
+	return parseLogAnalyticsBatchResponse(body, len(queries))
+}
 
-	return s.runHTTP(request, "Log Analytics REST api")
+// buildLogAnalyticsBatchRequest returns the body of the batch request of the queries, the id of a query is its index
+func buildLogAnalyticsBatchRequest(queries []batchedQuery, queryTimeout time.Duration) ([]byte, error) {
+	batch := logAnalyticsBatchRequest{Requests: make([]logAnalyticsBatchItem, len(queries))}
+	for i, batched := range queries {
+		query := batched.payload.(logAnalyticsQuery)
+		item := logAnalyticsBatchItem{
+			ID:        strconv.Itoa(i),
+			Headers:   map[string]string{"Content-Type": "application/json"},
+			Body:      map[string]interface{}{"query": query.query},
+			Method:    http.MethodPost,
+			Path:      "/query",
+			Workspace: query.workspaceID,
+		}
+		if len(query.additionalWorkspaceIDs) > 0 {
+			item.Body["workspaces"] = query.additionalWorkspaceIDs
+		}
+		if queryTimeout > 0 {
+			item.Headers["Prefer"] = fmt.Sprintf("wait=%d", int(queryTimeout.Seconds()))
+		}
+		batch.Requests[i] = item
+	}
+	return json.Marshal(batch)
 }
 
-func (s *azureLogAnalyticsScaler) executeAADApicall() ([]byte, int, error) {
+// parseLogAnalyticsBatchResponse returns the responses of the queries of a batch from the body of the response to the batch request
+func parseLogAnalyticsBatchResponse(body []byte, queries int) []batchedResponse {
+	responses := make([]batchedResponse, queries)
+	batch := logAnalyticsBatchResponse{}
+	if err := json.Unmarshal(body, &batch); err != nil {
+		err = fmt.Errorf("Error processing Log Analytics batch request. Details: can't decode response body to JSON. Inner Error: %v. Body: %s", err, string(body))
+		for i := range responses {
+			responses[i].err = err
+		}
+		return responses
+	}
+
+	received := make([]bool, queries)
+	for _, response := range batch.Responses {
+		i, err := strconv.Atoi(response.ID)
+		if err != nil || i < 0 || i >= queries {
+			continue
+		}
+		responses[i] = batchedResponse{body: response.Body, statusCode: response.Status}
+		received[i] = true
+	}
+	for i := range responses {
+		if !received[i] {
+			responses[i].err = fmt.Errorf("Error processing Log Analytics batch request. Details: no response to the query %d. Body: %s", i, string(body))
+		}
+	}
+	return responses
+}
+
+func (s *azureLogAnalyticsScaler) executeAADApicall(ctx context.Context) ([]byte, int, error) {
 	data := url.Values{
 		"grant_type":    {"client_credentials"},
 		"client_id":     {s.metadata.clientID},
@@ -488,7 +869,7 @@ func (s *azureLogAnalyticsScaler) executeAADApicall() ([]byte, int, error) {
 		"client_secret": {s.metadata.clientSecret},
 	}
 
-	request, err := http.NewRequest(http.MethodPost, fmt.Sprintf(aadTokenEndpoint, s.metadata.tenantID), strings.NewReader(data.Encode())) // URL-encoded payload
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf(aadTokenEndpoint, s.metadata.tenantID), strings.NewReader(data.Encode())) // URL-encoded payload
 	if err != nil {
 		return nil, 0, fmt.Errorf("Can't construct HTTP request to Azure Active Directory. Inner Error: %v", err)
 	}
@@ -499,8 +880,8 @@ func (s *azureLogAnalyticsScaler) executeAADApicall() ([]byte, int, error) {
 	return s.runHTTP(request, "AAD")
 }
 
-func (s *azureLogAnalyticsScaler) executeIMDSApicall() ([]byte, int, error) {
-	request, err := http.NewRequest(http.MethodGet, miEndpoint, nil)
+func (s *azureLogAnalyticsScaler) executeIMDSApicall(ctx context.Context) ([]byte, int, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, miEndpoint, nil)
 	if err != nil {
 		return nil, 0, fmt.Errorf("Can't construct HTTP request to Azure Instance Metadata service. Inner Error: %v", err)
 	}
@@ -511,14 +892,24 @@ func (s *azureLogAnalyticsScaler) executeIMDSApicall() ([]byte, int, error) {
 }
 
 func (s *azureLogAnalyticsScaler) runHTTP(request *http.Request, caller string) ([]byte, int, error) {
+	return s.runHTTPWithTimeout(request, caller, 0)
+}
+
+// runHTTPWithTimeout sends the request and reads its response within the timeout, the timeout bounds all the attempts of the retry policy
+func (s *azureLogAnalyticsScaler) runHTTPWithTimeout(request *http.Request, caller string, timeout time.Duration) ([]byte, int, error) {
 	request.Header.Add("Cache-Control", "no-cache")
 	request.Header.Add("User-Agent", "keda/2.0.0")
 
+	if timeout > 0 {
+		ctx, cancel := context.WithTimeout(request.Context(), timeout)
+		defer cancel()
+		request = request.WithContext(ctx)
+	}
 	httpClient := &http.Client{}
 
-	resp, err := httpClient.Do(request)
+	resp, err := kedautil.DoWithRetry(httpClient, request, s.metadata.retryPolicy)
 	if err != nil {
-		return nil, resp.StatusCode, fmt.Errorf("Error calling %s. Inner Error: %v", caller, err)
+		return nil, 0, fmt.Errorf("Error calling %s. Inner Error: %w", caller, err)
 	}
 
 	defer resp.Body.Close()
@@ -526,38 +917,63 @@ func (s *azureLogAnalyticsScaler) runHTTP(request *http.Request, caller string)
 
 	body, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return nil, resp.StatusCode, fmt.Errorf("Error reading %s response body: Inner Error: %v", caller, err)
+		return nil, resp.StatusCode, fmt.Errorf("Error reading %s response body: Inner Error: %w", caller, err)
 	}
 
 	return body, resp.StatusCode, nil
 }
 
-func getTokenFromCache(clientID string, clientSecret string) (tokenData, error) {
-	key, err := getHash(clientID, clientSecret)
+// isTimeoutError returns true if the request failed because its timeout expired
+func isTimeoutError(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// tokenCacheKey returns the key of the token of the credentials of the trigger in the token cache
+func (s *azureLogAnalyticsScaler) tokenCacheKey() (string, error) {
+	if s.metadata.podIdentity == "" {
+		return getHash(s.metadata.clientID, s.metadata.clientSecret)
+	}
+	return getHash(s.metadata.podIdentity, s.metadata.podIdentity)
+}
+
+func (s *azureLogAnalyticsScaler) getTokenFromCache() (tokenData, error) {
+	key, err := s.tokenCacheKey()
 	if err != nil {
 		return tokenData{}, fmt.Errorf("Error calculating sha1 hash. Inner Error: %v", err)
 	}
 
-	tokenCache.RLock()
+	tokenCache.Lock()
+	defer tokenCache.Unlock()
 
 	if val, ok := tokenCache.m[key]; ok && val.AccessToken != "" {
-		tokenCache.RUnlock()
-		return val, nil
+		val.used = true
+		return val.tokenData, nil
 	}
 
-	tokenCache.RUnlock()
 	return tokenData{}, fmt.Errorf("Error getting value from token cache. Details: unknown error")
 }
 
-func setTokenInCache(clientID string, clientSecret string, tokenInfo tokenData) error {
-	key, err := getHash(clientID, clientSecret)
+// setTokenInCache caches the token of the trigger and schedules its renewal laTokenRenewBefore its expiry,
+// replacing the renewal of the previous token
+func (s *azureLogAnalyticsScaler) setTokenInCache(tokenInfo tokenData, used bool) error {
+	key, err := s.tokenCacheKey()
 	if err != nil {
 		return err
 	}
 
 	tokenCache.Lock()
-	tokenCache.m[key] = tokenInfo
-	tokenCache.Unlock()
+	defer tokenCache.Unlock()
+
+	if previous, ok := tokenCache.m[key]; ok && previous.renewal != nil {
+		previous.renewal.Stop()
+	}
+	cached := &cachedToken{tokenData: tokenInfo, used: used}
+	// a token expiring sooner than that is only renewed on demand
+	if renewIn := time.Until(time.Unix(tokenInfo.ExpiresOn, 0).Add(-laTokenRenewBefore)); renewIn > 0 {
+		cached.renewal = time.AfterFunc(renewIn, func() { s.renewAccessTokenInBackground(key) })
+	}
+	tokenCache.m[key] = cached
 
 	return nil
 }
@@ -1,7 +1,13 @@
 package scalers
 
 import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
 	"testing"
+	"time"
+
+	kedautil "github.com/kedacore/keda/pkg/util"
 )
 
 const (
@@ -68,10 +74,31 @@ var testLogAnalyticsMetadata = []parseLogAnalyticsMetadataTestData{
 	{map[string]string{"tenantId": "d248da64-0e1e-4f79-b8c6-72ab7aa055eb", "clientId": "41826dd4-9e0a-4357-a5bd-a88ad771ea7d", "clientSecret": "U6DtAX5r6RPZxd~l12Ri3X8J9urt5Q-xs", "workspaceId": "074dd9f8-c368-4220-9400-acb6e80fc325", "query": query, "threshold": "1900000000"}, false},
 	//All parameters set, should succeed
 	{map[string]string{"tenantIdFromEnv": "d248da64-0e1e-4f79-b8c6-72ab7aa055eb", "clientIdFromEnv": "41826dd4-9e0a-4357-a5bd-a88ad771ea7d", "clientSecretFromEnv": "U6DtAX5r6RPZxd~l12Ri3X8J9urt5Q-xs", "workspaceIdFromEnv": "074dd9f8-c368-4220-9400-acb6e80fc325", "query": query, "threshold": "1900000000"}, false},
+	//Several workspaces with an aggregation, should succeed
+	{map[string]string{"tenantId": "d248da64-0e1e-4f79-b8c6-72ab7aa055eb", "clientId": "41826dd4-9e0a-4357-a5bd-a88ad771ea7d", "clientSecret": "U6DtAX5r6RPZxd~l12Ri3X8J9urt5Q-xs", "workspaceId": "074dd9f8-c368-4220-9400-acb6e80fc325, 6a3a9bbf-2f0d-4e6c-b5a4-9c8f0b0f3e21", "aggregation": "Max", "query": query, "threshold": "1900000000"}, false},
+	//Several workspaces with a cross workspace query, should succeed
+	{map[string]string{"tenantId": "d248da64-0e1e-4f79-b8c6-72ab7aa055eb", "clientId": "41826dd4-9e0a-4357-a5bd-a88ad771ea7d", "clientSecret": "U6DtAX5r6RPZxd~l12Ri3X8J9urt5Q-xs", "workspaceId": "074dd9f8-c368-4220-9400-acb6e80fc325,6a3a9bbf-2f0d-4e6c-b5a4-9c8f0b0f3e21", "crossWorkspaceQuery": "true", "query": query, "threshold": "1900000000"}, false},
+	//Unsupported aggregation, should fail
+	{map[string]string{"tenantId": "d248da64-0e1e-4f79-b8c6-72ab7aa055eb", "clientId": "41826dd4-9e0a-4357-a5bd-a88ad771ea7d", "clientSecret": "U6DtAX5r6RPZxd~l12Ri3X8J9urt5Q-xs", "workspaceId": "074dd9f8-c368-4220-9400-acb6e80fc325,6a3a9bbf-2f0d-4e6c-b5a4-9c8f0b0f3e21", "aggregation": "median", "query": query, "threshold": "1900000000"}, true},
+	//Aggregation with a cross workspace query, should fail
+	{map[string]string{"tenantId": "d248da64-0e1e-4f79-b8c6-72ab7aa055eb", "clientId": "41826dd4-9e0a-4357-a5bd-a88ad771ea7d", "clientSecret": "U6DtAX5r6RPZxd~l12Ri3X8J9urt5Q-xs", "workspaceId": "074dd9f8-c368-4220-9400-acb6e80fc325,6a3a9bbf-2f0d-4e6c-b5a4-9c8f0b0f3e21", "crossWorkspaceQuery": "true", "aggregation": "sum", "query": query, "threshold": "1900000000"}, true},
+	//Malformed crossWorkspaceQuery, should fail
+	{map[string]string{"tenantId": "d248da64-0e1e-4f79-b8c6-72ab7aa055eb", "clientId": "41826dd4-9e0a-4357-a5bd-a88ad771ea7d", "clientSecret": "U6DtAX5r6RPZxd~l12Ri3X8J9urt5Q-xs", "workspaceId": "074dd9f8-c368-4220-9400-acb6e80fc325", "crossWorkspaceQuery": "yes please", "query": query, "threshold": "1900000000"}, true},
+	//queryTimeout set, should succeed
+	{map[string]string{"tenantId": "d248da64-0e1e-4f79-b8c6-72ab7aa055eb", "clientId": "41826dd4-9e0a-4357-a5bd-a88ad771ea7d", "clientSecret": "U6DtAX5r6RPZxd~l12Ri3X8J9urt5Q-xs", "workspaceId": "074dd9f8-c368-4220-9400-acb6e80fc325", "queryTimeout": "60", "query": query, "threshold": "1900000000"}, false},
+	//Malformed queryTimeout, should fail
+	{map[string]string{"tenantId": "d248da64-0e1e-4f79-b8c6-72ab7aa055eb", "clientId": "41826dd4-9e0a-4357-a5bd-a88ad771ea7d", "clientSecret": "U6DtAX5r6RPZxd~l12Ri3X8J9urt5Q-xs", "workspaceId": "074dd9f8-c368-4220-9400-acb6e80fc325", "queryTimeout": "1m", "query": query, "threshold": "1900000000"}, true},
+	//Zero queryTimeout, should fail
+	{map[string]string{"tenantId": "d248da64-0e1e-4f79-b8c6-72ab7aa055eb", "clientId": "41826dd4-9e0a-4357-a5bd-a88ad771ea7d", "clientSecret": "U6DtAX5r6RPZxd~l12Ri3X8J9urt5Q-xs", "workspaceId": "074dd9f8-c368-4220-9400-acb6e80fc325", "queryTimeout": "0", "query": query, "threshold": "1900000000"}, true},
+	//batchWindow set, should succeed
+	{map[string]string{"tenantId": "d248da64-0e1e-4f79-b8c6-72ab7aa055eb", "clientId": "41826dd4-9e0a-4357-a5bd-a88ad771ea7d", "clientSecret": "U6DtAX5r6RPZxd~l12Ri3X8J9urt5Q-xs", "workspaceId": "074dd9f8-c368-4220-9400-acb6e80fc325", "batchWindow": "500ms", "query": query, "threshold": "1900000000"}, false},
+	//Malformed batchWindow, should fail
+	{map[string]string{"tenantId": "d248da64-0e1e-4f79-b8c6-72ab7aa055eb", "clientId": "41826dd4-9e0a-4357-a5bd-a88ad771ea7d", "clientSecret": "U6DtAX5r6RPZxd~l12Ri3X8J9urt5Q-xs", "workspaceId": "074dd9f8-c368-4220-9400-acb6e80fc325", "batchWindow": "500", "query": query, "threshold": "1900000000"}, true},
 }
 
 var LogAnalyticsMetricIdentifiers = []LogAnalyticsMetricIdentifier{
 	{&testLogAnalyticsMetadata[7], "azure-log-analytics-074dd9f8-c368-4220-9400-acb6e80fc325"},
+	{&testLogAnalyticsMetadata[9], "azure-log-analytics-074dd9f8-c368-4220-9400-acb6e80fc325-6a3a9bbf-2f0d-4e6c-b5a4-9c8f0b0f3e21"},
 }
 
 var testLogAnalyticsMetadataWithEmptyAuthParams = []parseLogAnalyticsMetadataTestData{
@@ -154,3 +181,205 @@ func TestLogAnalyticsGetMetricSpecForScaling(t *testing.T) {
 		}
 	}
 }
+
+func TestAggregateLogAnalyticsMetrics(t *testing.T) {
+	results := []metricsData{{value: 10, threshold: -1}, {value: 4, threshold: 5}, {value: 1, threshold: 2}}
+
+	tests := []struct {
+		aggregation string
+		expected    metricsData
+	}{
+		{laAggregationSum, metricsData{value: 15, threshold: 5}},
+		{laAggregationMax, metricsData{value: 10, threshold: 5}},
+		{laAggregationAvg, metricsData{value: 5, threshold: 5}},
+	}
+
+	for _, test := range tests {
+		aggregated := aggregateLogAnalyticsMetrics(results, test.aggregation)
+		if aggregated != test.expected {
+			t.Errorf("Expected %+v for %s, got %+v", test.expected, test.aggregation, aggregated)
+		}
+	}
+
+	if aggregated := aggregateLogAnalyticsMetrics([]metricsData{{value: 3, threshold: -1}}, laAggregationSum); aggregated.threshold != -1 {
+		t.Errorf("Expected no threshold if the query returns none, got %d", aggregated.threshold)
+	}
+}
+
+func TestLogAnalyticsRunHTTPWithTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+
+	scaler := azureLogAnalyticsScaler{metadata: &azureLogAnalyticsMetadata{retryPolicy: kedautil.RetryPolicy{MaxAttempts: 1}}}
+
+	request, _ := http.NewRequest(http.MethodPost, server.URL, nil)
+	if _, _, err := scaler.runHTTPWithTimeout(request, "Log Analytics REST api", 50*time.Millisecond); err == nil || !isTimeoutError(err) {
+		t.Error("Expected a timeout error but got", err)
+	}
+
+	request, _ = http.NewRequest(http.MethodPost, server.URL, nil)
+	body, statusCode, err := scaler.runHTTPWithTimeout(request, "Log Analytics REST api", time.Second)
+	if err != nil || statusCode != http.StatusOK || string(body) != "{}" {
+		t.Errorf("Expected a response before the timeout, got %d %s %v", statusCode, body, err)
+	}
+}
+
+func TestLogAnalyticsRunHTTPWithTimeoutBoundsTheRetries(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		time.Sleep(100 * time.Millisecond)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	retryPolicy := kedautil.RetryPolicy{MaxAttempts: 5, Backoff: 50 * time.Millisecond, MaxBackoff: 50 * time.Millisecond, RetryOnStatus: []int{http.StatusServiceUnavailable}}
+	scaler := azureLogAnalyticsScaler{metadata: &azureLogAnalyticsMetadata{retryPolicy: retryPolicy}}
+
+	start := time.Now()
+	request, _ := http.NewRequest(http.MethodPost, server.URL, nil)
+	if _, _, err := scaler.runHTTPWithTimeout(request, "Log Analytics REST api", 250*time.Millisecond); err == nil || !isTimeoutError(err) {
+		t.Error("Expected a timeout error but got", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Expected the retries to stop at the timeout, returned after %s", elapsed)
+	}
+	if attempts := atomic.LoadInt32(&attempts); int(attempts) >= retryPolicy.MaxAttempts {
+		t.Errorf("Expected the timeout to stop the retries, got %d attempts", attempts)
+	}
+}
+
+func TestLogAnalyticsGetColumnIndexes(t *testing.T) {
+	columnNames := []string{"Region", "MetricValue", "Threshold"}
+
+	tests := []struct {
+		valueColumnName        string
+		thresholdColumnName    string
+		rowLength              int
+		expectedValueIndex     int
+		expectedThresholdIndex int
+		isError                bool
+	}{
+		// positional
+		{"", "", 3, 0, 1, false},
+		{"", "", 1, 0, -1, false},
+		{"", "", 0, -1, -1, false},
+		// by name
+		{"MetricValue", "Threshold", 3, 1, 2, false},
+		// by name without threshold column
+		{"MetricValue", "", 3, 1, -1, false},
+		// threshold by name, value positional
+		{"", "Threshold", 3, 0, 2, false},
+		// missing column
+		{"QueueLength", "", 3, -1, -1, true},
+		{"MetricValue", "Limit", 3, -1, -1, true},
+		// column without value in the row
+		{"Threshold", "", 2, -1, -1, true},
+	}
+
+	for _, test := range tests {
+		meta := azureLogAnalyticsMetadata{valueColumnName: test.valueColumnName, thresholdColumnName: test.thresholdColumnName}
+		valueIndex, thresholdIndex, err := meta.getColumnIndexes(columnNames, test.rowLength)
+		if test.isError {
+			if err == nil {
+				t.Errorf("Expected error for %+v but got success", test)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Expected success for %+v but got error %s", test, err)
+			continue
+		}
+		if valueIndex != test.expectedValueIndex || thresholdIndex != test.expectedThresholdIndex {
+			t.Errorf("Expected indexes %d, %d for %+v, got %d, %d", test.expectedValueIndex, test.expectedThresholdIndex, test, valueIndex, thresholdIndex)
+		}
+	}
+}
+
+func TestLogAnalyticsBatchRequest(t *testing.T) {
+	queries := []batchedQuery{
+		{key: "1", payload: logAnalyticsQuery{workspaceID: "w1", query: "Q1"}},
+		{key: "2", payload: logAnalyticsQuery{workspaceID: "w2", additionalWorkspaceIDs: []string{"w3"}, query: "Q2"}},
+	}
+	body, err := buildLogAnalyticsBatchRequest(queries, 30*time.Second)
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	expected := `{"requests":[` +
+		`{"id":"0","headers":{"Content-Type":"application/json","Prefer":"wait=30"},"body":{"query":"Q1"},"method":"POST","path":"/query","workspace":"w1"},` +
+		`{"id":"1","headers":{"Content-Type":"application/json","Prefer":"wait=30"},"body":{"query":"Q2","workspaces":["w3"]},"method":"POST","path":"/query","workspace":"w2"}]}`
+	if string(body) != expected {
+		t.Errorf("Expected %s, got %s", expected, body)
+	}
+
+	responses := parseLogAnalyticsBatchResponse([]byte(`{"responses":[{"id":"1","status":403,"body":{"error":"TokenExpired"}},{"id":"0","status":200,"body":{"tables":[]}}]}`), 3)
+	if responses[0].statusCode != 200 || string(responses[0].body) != `{"tables":[]}` || responses[0].err != nil {
+		t.Errorf("Unexpected response of the first query %+v", responses[0])
+	}
+	if responses[1].statusCode != 403 || string(responses[1].body) != `{"error":"TokenExpired"}` {
+		t.Errorf("Unexpected response of the second query %+v", responses[1])
+	}
+	if responses[2].err == nil {
+		t.Error("Expected an error for the query without response")
+	}
+
+	for _, response := range parseLogAnalyticsBatchResponse([]byte("not json"), 2) {
+		if response.err == nil {
+			t.Error("Expected an error for a malformed batch response")
+		}
+	}
+}
+
+func TestLogAnalyticsTokenCache(t *testing.T) {
+	s := &azureLogAnalyticsScaler{metadata: &azureLogAnalyticsMetadata{clientID: clientID, clientSecret: "token-cache-test"}}
+	key, err := s.tokenCacheKey()
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	defer func() {
+		tokenCache.Lock()
+		delete(tokenCache.m, key)
+		tokenCache.Unlock()
+	}()
+
+	// the renewal of a token is scheduled before its expiry
+	expiresOn := time.Now().Add(time.Hour).Unix()
+	if err := s.setTokenInCache(tokenData{AccessToken: "token", ExpiresOn: expiresOn}, false); err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	tokenCache.Lock()
+	cached := tokenCache.m[key]
+	tokenCache.Unlock()
+	if cached.renewal == nil || cached.used {
+		t.Fatal("Expected the renewal of the unused token to be scheduled")
+	}
+	cached.renewal.Stop()
+
+	if tokenInfo, err := s.getTokenFromCache(); err != nil || tokenInfo.AccessToken != "token" {
+		t.Errorf("Expected the cached token, got %v and %v", tokenInfo, err)
+	}
+	if !cached.used {
+		t.Error("Expected the token read from the cache to be marked as used")
+	}
+
+	// a token unused since its renewal is dropped instead of being renewed
+	cached.used = false
+	s.renewAccessTokenInBackground(key)
+	if _, err := s.getTokenFromCache(); err == nil {
+		t.Error("Expected the unused token to be dropped from the cache")
+	}
+
+	// a token expiring within laTokenRenewBefore is only renewed on demand
+	if err := s.setTokenInCache(tokenData{AccessToken: "token", ExpiresOn: time.Now().Add(time.Minute).Unix()}, true); err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	tokenCache.Lock()
+	cached = tokenCache.m[key]
+	tokenCache.Unlock()
+	if cached.renewal != nil {
+		t.Error("Expected no renewal for a token expiring soon")
+	}
+}
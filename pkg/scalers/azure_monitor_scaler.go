@@ -92,10 +92,26 @@ func parseAzureMonitorMetadata(metadata, resolvedEnv, authParams map[string]stri
 		return nil, fmt.Errorf("no metricAggregationType given")
 	}
 
+	if val, ok := metadata["metricNamespace"]; ok && val != "" {
+		meta.azureMonitorInfo.Namespace = val
+	}
+
 	if val, ok := metadata["metricFilter"]; ok && val != "" {
 		meta.azureMonitorInfo.Filter = val
 	}
 
+	if val, ok := metadata["metricDimensions"]; ok && val != "" {
+		dimensionsFilter, err := parseAzureMonitorDimensions(val)
+		if err != nil {
+			return nil, err
+		}
+		if meta.azureMonitorInfo.Filter != "" {
+			meta.azureMonitorInfo.Filter = fmt.Sprintf("(%s) and %s", meta.azureMonitorInfo.Filter, dimensionsFilter)
+		} else {
+			meta.azureMonitorInfo.Filter = dimensionsFilter
+		}
+	}
+
 	if val, ok := metadata["metricAggregationInterval"]; ok && val != "" {
 		aggregationInterval := strings.Split(val, ":")
 		if len(aggregationInterval) != 3 {
@@ -104,6 +120,14 @@ func parseAzureMonitorMetadata(metadata, resolvedEnv, authParams map[string]stri
 		meta.azureMonitorInfo.AggregationInterval = val
 	}
 
+	if val, ok := metadata["metricGranularity"]; ok && val != "" {
+		granularity := strings.Split(val, ":")
+		if len(granularity) != 3 {
+			return nil, fmt.Errorf("metricGranularity not in the correct format. Should be hh:mm:ss")
+		}
+		meta.azureMonitorInfo.Granularity = val
+	}
+
 	// Required authentication parameters below
 
 	if val, ok := metadata["subscriptionId"]; ok && val != "" {
@@ -147,6 +171,21 @@ func parseAzureMonitorMetadata(metadata, resolvedEnv, authParams map[string]stri
 	return &meta, nil
 }
 
+// parseAzureMonitorDimensions converts a comma separated list of dimension filters like Instance=vm-1,Disk=C:
+// to the OData filter of the metric request, the dimensions are combined with and
+func parseAzureMonitorDimensions(dimensions string) (string, error) {
+	var filters []string
+	for _, dimension := range strings.Split(dimensions, ",") {
+		parts := strings.SplitN(dimension, "=", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" || strings.TrimSpace(parts[1]) == "" {
+			return "", fmt.Errorf("metricDimensions not in the correct format. Should be name=value,name=value")
+		}
+		value := strings.ReplaceAll(strings.TrimSpace(parts[1]), "'", "''")
+		filters = append(filters, fmt.Sprintf("%s eq '%s'", strings.TrimSpace(parts[0]), value))
+	}
+	return strings.Join(filters, " and "), nil
+}
+
 // Returns true if the Azure Monitor metric value is greater than zero
 func (s *azureMonitorScaler) IsActive(ctx context.Context) (bool, error) {
 	val, err := azure.GetAzureMetricValue(ctx, s.metadata.azureMonitorInfo, s.podIdentity)
@@ -57,6 +57,12 @@ var testParseAzMonitorMetadata = []parseAzMonitorMetadataTestData{
 	{map[string]string{"resourceURI": "test/resource/uri", "tenantId": "123", "subscriptionId": "456", "resourceGroupName": "test", "metricName": "metric", "metricAggregationInterval": "0:15:0", "metricAggregationType": "Average", "targetValue": "5"}, false, map[string]string{}, map[string]string{}, "azure"},
 	// wrong podIdentity
 	{map[string]string{"resourceURI": "test/resource/uri", "tenantId": "123", "subscriptionId": "456", "resourceGroupName": "test", "metricName": "metric", "metricAggregationInterval": "0:15:0", "metricAggregationType": "Average", "targetValue": "5"}, true, map[string]string{}, map[string]string{}, "notAzure"},
+	// custom metric namespace with dimensions and granularity
+	{map[string]string{"resourceURI": "test/resource/uri", "tenantId": "123", "subscriptionId": "456", "resourceGroupName": "test", "metricName": "metric", "metricAggregationType": "Average", "targetValue": "5", "metricNamespace": "Azure.VM.Windows.GuestMetrics", "metricDimensions": "Instance=vm-1, Disk=C:", "metricGranularity": "0:1:0"}, false, map[string]string{}, map[string]string{}, "azure"},
+	// improperly formatted metricDimensions
+	{map[string]string{"resourceURI": "test/resource/uri", "tenantId": "123", "subscriptionId": "456", "resourceGroupName": "test", "metricName": "metric", "metricAggregationType": "Average", "targetValue": "5", "metricDimensions": "Instance"}, true, map[string]string{}, map[string]string{}, "azure"},
+	// improperly formatted metricGranularity
+	{map[string]string{"resourceURI": "test/resource/uri", "tenantId": "123", "subscriptionId": "456", "resourceGroupName": "test", "metricName": "metric", "metricAggregationType": "Average", "targetValue": "5", "metricGranularity": "1m"}, true, map[string]string{}, map[string]string{}, "azure"},
 }
 
 var azMonitorMetricIdentifiers = []azMonitorMetricIdentifier{
@@ -90,3 +96,15 @@ func TestAzMonitorGetMetricSpecForScaling(t *testing.T) {
 		}
 	}
 }
+
+func TestAzMonitorParseDimensions(t *testing.T) {
+	meta, err := parseAzureMonitorMetadata(map[string]string{"resourceURI": "test/resource/uri", "tenantId": "123", "subscriptionId": "456", "resourceGroupName": "test", "metricName": "metric", "metricAggregationType": "Average", "targetValue": "5",
+		"metricFilter": "namespace eq 'default'", "metricDimensions": "Instance=vm-1,Path=C:\\'s"}, map[string]string{}, map[string]string{}, "azure")
+	if err != nil {
+		t.Fatal("Could not parse metadata:", err)
+	}
+	expected := "(namespace eq 'default') and Instance eq 'vm-1' and Path eq 'C:\\''s'"
+	if meta.azureMonitorInfo.Filter != expected {
+		t.Errorf("Expected filter %s but got %s", expected, meta.azureMonitorInfo.Filter)
+	}
+}
@@ -34,6 +34,8 @@ type azureQueueMetadata struct {
 	connection        string
 	useAAdPodIdentity bool
 	accountName       string
+	// queueLengthStrategy counts the visible messages only or all the messages, the invisible ones included
+	queueLengthStrategy azure.QueueLengthStrategy
 }
 
 var azureQueueLog = logf.Log.WithName("azure_queue_scaler")
@@ -67,10 +69,19 @@ func parseAzureQueueMetadata(metadata, resolvedEnv, authParams map[string]string
 
 	if val, ok := metadata["queueName"]; ok && val != "" {
 		meta.queueName = val
-	} else {
+	} else if val, ok := metadata["queueNameFromEnv"]; ok && val != "" {
+		meta.queueName = resolvedEnv[val]
+	}
+	if meta.queueName == "" {
 		return nil, "", fmt.Errorf("no queueName given")
 	}
 
+	strategy, err := azure.ParseQueueLengthStrategy(metadata["queueLengthStrategy"])
+	if err != nil {
+		return nil, "", err
+	}
+	meta.queueLengthStrategy = strategy
+
 	// before triggerAuthentication CRD, pod identity was configured using this property
 	if val, ok := metadata["useAAdPodIdentity"]; ok && podAuth == "" {
 		if val == "true" {
@@ -95,9 +106,14 @@ func parseAzureQueueMetadata(metadata, resolvedEnv, authParams map[string]string
 		}
 	} else if podAuth == "azure" {
 		// If the Use AAD Pod Identity is present then check account name
-		if val, ok := metadata["accountName"]; ok && val != "" {
+		if authParams["accountName"] != "" {
+			meta.accountName = authParams["accountName"]
+		} else if val, ok := metadata["accountName"]; ok && val != "" {
 			meta.accountName = val
-		} else {
+		} else if val, ok := metadata["accountNameFromEnv"]; ok && val != "" {
+			meta.accountName = resolvedEnv[val]
+		}
+		if meta.accountName == "" {
 			return nil, "", fmt.Errorf("no accountName given")
 		}
 	} else {
@@ -115,6 +131,7 @@ func (s *azureQueueScaler) IsActive(ctx context.Context) (bool, error) {
 		s.metadata.connection,
 		s.metadata.queueName,
 		s.metadata.accountName,
+		s.metadata.queueLengthStrategy,
 	)
 
 	if err != nil {
@@ -152,6 +169,7 @@ func (s *azureQueueScaler) GetMetrics(ctx context.Context, metricName string, me
 		s.metadata.connection,
 		s.metadata.queueName,
 		s.metadata.accountName,
+		s.metadata.queueLengthStrategy,
 	)
 
 	if err != nil {
@@ -3,7 +3,9 @@ package scalers
 import "testing"
 
 var testAzQueueResolvedEnv = map[string]string{
-	"CONNECTION": "SAMPLE",
+	"CONNECTION":   "SAMPLE",
+	"QUEUE_NAME":   "sample_queue",
+	"ACCOUNT_NAME": "sample_acc",
 }
 
 type parseAzQueueMetadataTestData struct {
@@ -42,11 +44,26 @@ var testAzQueueMetadata = []parseAzQueueMetadataTestData{
 	{map[string]string{"accountName": "sample_acc", "queueName": ""}, true, testAzQueueResolvedEnv, map[string]string{}, "azure"},
 	// connection from authParams
 	{map[string]string{"queueName": "sample", "queueLength": "5"}, false, testAzQueueResolvedEnv, map[string]string{"connection": "value"}, "none"},
+	// queueName from env
+	{map[string]string{"connectionFromEnv": "CONNECTION", "queueNameFromEnv": "QUEUE_NAME"}, false, testAzQueueResolvedEnv, map[string]string{}, ""},
+	// queueName from env not set
+	{map[string]string{"connectionFromEnv": "CONNECTION", "queueNameFromEnv": "MISSING"}, true, testAzQueueResolvedEnv, map[string]string{}, ""},
+	// podIdentity = azure with account name from env
+	{map[string]string{"accountNameFromEnv": "ACCOUNT_NAME", "queueName": "sample_queue"}, false, testAzQueueResolvedEnv, map[string]string{}, "azure"},
+	// podIdentity = azure with account name from authParams
+	{map[string]string{"queueName": "sample_queue"}, false, testAzQueueResolvedEnv, map[string]string{"accountName": "sample_acc"}, "azure"},
+	// visible messages only
+	{map[string]string{"connectionFromEnv": "CONNECTION", "queueName": "sample", "queueLengthStrategy": "visibleOnly"}, false, testAzQueueResolvedEnv, map[string]string{}, ""},
+	// all messages
+	{map[string]string{"connectionFromEnv": "CONNECTION", "queueName": "sample", "queueLengthStrategy": "all"}, false, testAzQueueResolvedEnv, map[string]string{}, ""},
+	// unknown queueLengthStrategy
+	{map[string]string{"connectionFromEnv": "CONNECTION", "queueName": "sample", "queueLengthStrategy": "invisibleOnly"}, true, testAzQueueResolvedEnv, map[string]string{}, ""},
 }
 
 var azQueueMetricIdentifiers = []azQueueMetricIdentifier{
 	{&testAzQueueMetadata[1], "azure-queue-sample"},
 	{&testAzQueueMetadata[4], "azure-queue-sample_queue"},
+	{&testAzQueueMetadata[11], "azure-queue-sample_queue"},
 }
 
 func TestAzQueueParseMetadata(t *testing.T) {
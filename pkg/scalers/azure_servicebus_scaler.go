@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/Azure/azure-amqp-common-go/v3/auth"
 	servicebus "github.com/Azure/azure-service-bus-go"
@@ -42,7 +43,10 @@ type azureServiceBusMetadata struct {
 	subscriptionName string
 	connection       string
 	entityType       entityType
-	namespace        string
+	// namespace is the name of the namespace used with the pod identities, suffix is its endpoint suffix
+	// if a fully qualified namespace is given
+	namespace string
+	suffix    string
 }
 
 // NewAzureServiceBusScaler creates a new AzureServiceBusScaler
@@ -98,6 +102,15 @@ func parseAzureServiceBusMetadata(resolvedEnv, metadata, authParams map[string]s
 		}
 	}
 
+	if val, ok := metadata["entityPath"]; ok && val != "" {
+		if meta.entityType != none {
+			return nil, fmt.Errorf("entityPath provided with queue or topic name")
+		}
+		if err := parseServiceBusEntityPath(&meta, val); err != nil {
+			return nil, err
+		}
+	}
+
 	if meta.entityType == none {
 		return nil, fmt.Errorf("No service bus entity type set")
 	}
@@ -113,9 +126,9 @@ func parseAzureServiceBusMetadata(resolvedEnv, metadata, authParams map[string]s
 		if len(meta.connection) == 0 {
 			return nil, fmt.Errorf("no connection setting given")
 		}
-	} else if podIdentity == "azure" {
-		if val, ok := metadata["namespace"]; ok {
-			meta.namespace = val
+	} else if podIdentity == "azure" || podIdentity == "azure-workload" {
+		if val, ok := metadata["namespace"]; ok && val != "" {
+			meta.namespace, meta.suffix = parseServiceBusNamespace(val)
 		} else {
 			return nil, fmt.Errorf("namespace is required when using pod identity")
 		}
@@ -126,6 +139,34 @@ func parseAzureServiceBusMetadata(resolvedEnv, metadata, authParams map[string]s
 	return &meta, nil
 }
 
+// parseServiceBusEntityPath sets the entity of the path of a queue, or of a subscription as <topic>/subscriptions/<subscription>
+func parseServiceBusEntityPath(meta *azureServiceBusMetadata, entityPath string) error {
+	parts := strings.Split(strings.Trim(entityPath, "/"), "/")
+	switch {
+	case len(parts) == 1:
+		meta.queueName = parts[0]
+		meta.entityType = queue
+	case len(parts) == 3 && strings.EqualFold(parts[1], "subscriptions") && parts[0] != "" && parts[2] != "":
+		meta.topicName = parts[0]
+		meta.subscriptionName = parts[2]
+		meta.entityType = subscription
+	default:
+		return fmt.Errorf("entityPath %s is neither a queue nor a <topic>/subscriptions/<subscription> path", entityPath)
+	}
+	return nil
+}
+
+// parseServiceBusNamespace returns the name and the endpoint suffix of a namespace given by its name,
+// its fully qualified name or its sb:// endpoint, the suffix is empty for a name
+func parseServiceBusNamespace(namespace string) (string, string) {
+	namespace = strings.TrimPrefix(namespace, "sb://")
+	namespace = strings.TrimSuffix(namespace, "/")
+	if i := strings.Index(namespace, "."); i >= 0 {
+		return namespace[:i], namespace[i+1:]
+	}
+	return namespace, ""
+}
+
 // Returns true if the scaler's queue has messages in it, false otherwise
 func (s *azureServiceBusScaler) IsActive(ctx context.Context) (bool, error) {
 	length, err := s.GetAzureServiceBusLength(ctx)
@@ -182,12 +223,18 @@ func (s *azureServiceBusScaler) GetMetrics(ctx context.Context, metricName strin
 	return append([]external_metrics.ExternalMetricValue{}, metric), nil
 }
 
+// azureTokenProvider gets the tokens of the namespace from the pod identity, Azure AD pod identity or workload identity
 type azureTokenProvider struct {
+	podIdentity string
 }
 
 // GetToken implements TokenProvider interface for azureTokenProvider
-func (azureTokenProvider) GetToken(uri string) (*auth.Token, error) {
-	token, err := azure.GetAzureADPodIdentityToken("https://servicebus.azure.net")
+func (p azureTokenProvider) GetToken(uri string) (*auth.Token, error) {
+	getToken := azure.GetAzureADPodIdentityToken
+	if p.podIdentity == "azure-workload" {
+		getToken = azure.GetAzureADWorkloadIdentityToken
+	}
+	token, err := getToken("https://servicebus.azure.net")
 	if err != nil {
 		return nil, err
 	}
@@ -209,13 +256,14 @@ func (s *azureServiceBusScaler) GetAzureServiceBusLength(ctx context.Context) (i
 		if err != nil {
 			return -1, err
 		}
-	} else if s.podIdentity == "azure" {
+	} else if s.podIdentity == "azure" || s.podIdentity == "azure-workload" {
 		namespace, err = servicebus.NewNamespace()
 		if err != nil {
 			return -1, err
 		}
-		namespace.TokenProvider = azureTokenProvider{}
+		namespace.TokenProvider = azureTokenProvider{podIdentity: s.podIdentity}
 		namespace.Name = s.metadata.namespace
+		namespace.Suffix = s.metadata.suffix
 	}
 
 	// switch case for queue vs topic here
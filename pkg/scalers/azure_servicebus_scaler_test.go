@@ -59,11 +59,24 @@ var parseServiceBusMetadataDataset = []parseServiceBusMetadataTestData{
 	{map[string]string{"queueName": queueName}, true, queue, map[string]string{}, "azure"},
 	// correct pod identity
 	{map[string]string{"queueName": queueName, "namespace": namespaceName}, false, queue, map[string]string{}, "azure"},
+	// workload identity with a fully qualified namespace
+	{map[string]string{"queueName": queueName, "namespace": namespaceName + ".servicebus.windows.net"}, false, queue, map[string]string{}, "azure-workload"},
+	// workload identity but missing namespace
+	{map[string]string{"queueName": queueName}, true, queue, map[string]string{}, "azure-workload"},
+	// entity path of a queue
+	{map[string]string{"entityPath": queueName, "namespace": namespaceName}, false, queue, map[string]string{}, "azure"},
+	// entity path of a subscription
+	{map[string]string{"entityPath": topicName + "/Subscriptions/" + subscriptionName, "connectionFromEnv": connectionSetting}, false, subscription, map[string]string{}, ""},
+	// entity path neither a queue nor a subscription
+	{map[string]string{"entityPath": topicName + "/" + subscriptionName, "connectionFromEnv": connectionSetting}, true, none, map[string]string{}, ""},
+	// entity path and queue name specified
+	{map[string]string{"entityPath": queueName, "queueName": queueName, "connectionFromEnv": connectionSetting}, true, none, map[string]string{}, ""},
 }
 
 var azServiceBusMetricIdentifiers = []azServiceBusMetricIdentifier{
 	{&parseServiceBusMetadataDataset[1], "azure-servicebus-testqueue"},
 	{&parseServiceBusMetadataDataset[3], "azure-servicebus-testtopic-testsubscription"},
+	{&parseServiceBusMetadataDataset[16], "azure-servicebus-testtopic-testsubscription"},
 }
 
 var getServiceBusLengthTestScalers = []azureServiceBusScaler{
@@ -147,3 +160,22 @@ func TestAzServiceBusGetMetricSpecForScaling(t *testing.T) {
 		}
 	}
 }
+
+func TestParseServiceBusNamespace(t *testing.T) {
+	tests := []struct {
+		namespace string
+		name      string
+		suffix    string
+	}{
+		{"ns", "ns", ""},
+		{"ns.servicebus.windows.net", "ns", "servicebus.windows.net"},
+		{"sb://ns.servicebus.chinacloudapi.cn/", "ns", "servicebus.chinacloudapi.cn"},
+	}
+
+	for _, test := range tests {
+		name, suffix := parseServiceBusNamespace(test.namespace)
+		if name != test.name || suffix != test.suffix {
+			t.Errorf("Expected %s and %s for %s, got %s and %s", test.name, test.suffix, test.namespace, name, suffix)
+		}
+	}
+}
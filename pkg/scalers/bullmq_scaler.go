@@ -0,0 +1,216 @@
+package scalers
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/go-redis/redis"
+	v2beta2 "k8s.io/api/autoscaling/v2beta2"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/metrics/pkg/apis/external_metrics"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	kedautil "github.com/kedacore/keda/pkg/util"
+)
+
+const (
+	defaultBullMQPrefix      = "bull"
+	defaultBullMQQueueLength = 5
+)
+
+// bullMQStateKeyTypes are the job states a BullMQ queue keeps in Redis with the type of their key
+var bullMQStateKeyTypes = map[string]string{
+	"wait":        "list",
+	"paused":      "list",
+	"active":      "list",
+	"delayed":     "zset",
+	"prioritized": "zset",
+}
+
+// defaultBullMQStates are the states of the pending jobs
+var defaultBullMQStates = []string{"wait", "paused", "delayed", "prioritized"}
+
+type bullMQScaler struct {
+	metadata *bullMQMetadata
+	client   *redis.Client
+}
+
+type bullMQMetadata struct {
+	queueName         string
+	prefix            string
+	states            []string
+	targetQueueLength int
+	databaseIndex     int
+	connectionInfo    redisConnectionInfo
+}
+
+var bullMQLog = logf.Log.WithName("bullmq_scaler")
+
+// NewBullMQScaler creates a new scaler for the pending jobs of a BullMQ queue
+func NewBullMQScaler(resolvedEnv, metadata, authParams map[string]string) (Scaler, error) {
+	meta, err := parseBullMQMetadata(metadata, resolvedEnv, authParams)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing bullmq metadata: %s", err)
+	}
+	options := &redis.Options{
+		Addr:     meta.connectionInfo.address,
+		Password: meta.connectionInfo.password,
+		DB:       meta.databaseIndex,
+	}
+
+	if meta.connectionInfo.enableTLS {
+		options.TLSConfig = &tls.Config{
+			InsecureSkipVerify: meta.connectionInfo.enableTLS,
+		}
+	}
+
+	return &bullMQScaler{
+		metadata: meta,
+		client:   redis.NewClient(options),
+	}, nil
+}
+
+func parseBullMQMetadata(metadata, resolvedEnv, authParams map[string]string) (*bullMQMetadata, error) {
+	connInfo, err := parseRedisAddress(metadata, resolvedEnv, authParams)
+	if err != nil {
+		return nil, err
+	}
+	meta := bullMQMetadata{
+		connectionInfo:    connInfo,
+		prefix:            defaultBullMQPrefix,
+		states:            defaultBullMQStates,
+		targetQueueLength: defaultBullMQQueueLength,
+		databaseIndex:     defaultDbIdx,
+	}
+
+	if val, ok := metadata["queueName"]; ok && val != "" {
+		meta.queueName = val
+	} else {
+		return nil, fmt.Errorf("no queue name given")
+	}
+
+	if val, ok := metadata["prefix"]; ok && val != "" {
+		meta.prefix = val
+	}
+
+	if val, ok := metadata["states"]; ok && val != "" {
+		meta.states = nil
+		for _, state := range strings.Split(val, ",") {
+			state = strings.TrimSpace(state)
+			if _, ok := bullMQStateKeyTypes[state]; !ok {
+				return nil, fmt.Errorf("state %s not supported, supported states are wait, paused, active, delayed and prioritized", state)
+			}
+			meta.states = append(meta.states, state)
+		}
+	}
+
+	if val, ok := metadata["queueLength"]; ok && val != "" {
+		queueLength, err := strconv.Atoi(val)
+		if err != nil {
+			return nil, fmt.Errorf("queueLength parsing error %s", err.Error())
+		}
+		meta.targetQueueLength = queueLength
+	}
+
+	if val, ok := metadata["databaseIndex"]; ok && val != "" {
+		dbIndex, err := strconv.ParseInt(val, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("databaseIndex: parsing error %s", err.Error())
+		}
+		meta.databaseIndex = int(dbIndex)
+	}
+
+	return &meta, nil
+}
+
+// stateKey returns the Redis key of the jobs of the queue in the state, e.g. bull:emails:wait
+func (s *bullMQScaler) stateKey(state string) string {
+	return fmt.Sprintf("%s:%s:%s", s.metadata.prefix, s.metadata.queueName, state)
+}
+
+// getQueueLength sums the number of the jobs of the queue in the configured states
+func (s *bullMQScaler) getQueueLength(ctx context.Context) (int64, error) {
+	pipeline := s.client.WithContext(ctx).Pipeline()
+	defer pipeline.Close()
+
+	cmds := make([]*redis.IntCmd, len(s.metadata.states))
+	for i, state := range s.metadata.states {
+		if bullMQStateKeyTypes[state] == "zset" {
+			cmds[i] = pipeline.ZCard(s.stateKey(state))
+		} else {
+			cmds[i] = pipeline.LLen(s.stateKey(state))
+		}
+	}
+	if _, err := pipeline.Exec(); err != nil {
+		return -1, err
+	}
+
+	var length int64
+	for _, cmd := range cmds {
+		length += cmd.Val()
+	}
+	return length, nil
+}
+
+// IsActive checks if there is any pending job in the queue
+func (s *bullMQScaler) IsActive(ctx context.Context) (bool, error) {
+	length, err := s.getQueueLength(ctx)
+	if err != nil {
+		bullMQLog.Error(err, "error getting queue length")
+		return false, err
+	}
+
+	return length > 0, nil
+}
+
+func (s *bullMQScaler) Close() error {
+	if s.client != nil {
+		err := s.client.Close()
+		if err != nil {
+			bullMQLog.Error(err, "error closing redis client")
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetMetricSpecForScaling returns the metric spec for the HPA
+func (s *bullMQScaler) GetMetricSpecForScaling() []v2beta2.MetricSpec {
+	targetQueueLengthQty := resource.NewQuantity(int64(s.metadata.targetQueueLength), resource.DecimalSI)
+	externalMetric := &v2beta2.ExternalMetricSource{
+		Metric: v2beta2.MetricIdentifier{
+			Name: kedautil.NormalizeString(fmt.Sprintf("%s-%s-%s", "bullmq", s.metadata.prefix, s.metadata.queueName)),
+		},
+		Target: v2beta2.MetricTarget{
+			Type:         v2beta2.AverageValueMetricType,
+			AverageValue: targetQueueLengthQty,
+		},
+	}
+	metricSpec := v2beta2.MetricSpec{
+		External: externalMetric, Type: externalMetricType,
+	}
+	return []v2beta2.MetricSpec{metricSpec}
+}
+
+// GetMetrics connects to Redis and sums the number of the pending jobs of the queue
+func (s *bullMQScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
+	length, err := s.getQueueLength(ctx)
+	if err != nil {
+		bullMQLog.Error(err, "error getting queue length")
+		return []external_metrics.ExternalMetricValue{}, err
+	}
+
+	metric := external_metrics.ExternalMetricValue{
+		MetricName: metricName,
+		Value:      *resource.NewQuantity(length, resource.DecimalSI),
+		Timestamp:  metav1.Now(),
+	}
+
+	return append([]external_metrics.ExternalMetricValue{}, metric), nil
+}
@@ -0,0 +1,77 @@
+package scalers
+
+import (
+	"testing"
+)
+
+type parseBullMQMetadataTestData struct {
+	metadata map[string]string
+	isError  bool
+}
+
+type bullMQMetricIdentifier struct {
+	metadataTestData *parseBullMQMetadataTestData
+	name             string
+}
+
+var testBullMQMetadata = []parseBullMQMetadataTestData{
+	// nothing passed
+	{map[string]string{}, true},
+	// properly formed
+	{map[string]string{"address": "redis:6379", "queueName": "emails", "queueLength": "20"}, false},
+	// custom prefix and states
+	{map[string]string{"address": "redis:6379", "queueName": "emails", "prefix": "app", "states": "wait, active"}, false},
+	// missing queueName
+	{map[string]string{"address": "redis:6379"}, true},
+	// missing address
+	{map[string]string{"queueName": "emails"}, true},
+	// unsupported state
+	{map[string]string{"address": "redis:6379", "queueName": "emails", "states": "wait,completed"}, true},
+	// malformed queueLength
+	{map[string]string{"address": "redis:6379", "queueName": "emails", "queueLength": "many"}, true},
+}
+
+var bullMQMetricIdentifiers = []bullMQMetricIdentifier{
+	{&testBullMQMetadata[1], "bullmq-bull-emails"},
+	{&testBullMQMetadata[2], "bullmq-app-emails"},
+}
+
+func TestBullMQParseMetadata(t *testing.T) {
+	for _, testData := range testBullMQMetadata {
+		_, err := parseBullMQMetadata(testData.metadata, nil, nil)
+		if err != nil && !testData.isError {
+			t.Error("Expected success but got error", err)
+		}
+		if testData.isError && err == nil {
+			t.Errorf("Expected error but got success for %v", testData.metadata)
+		}
+	}
+}
+
+func TestBullMQGetMetricSpecForScaling(t *testing.T) {
+	for _, testData := range bullMQMetricIdentifiers {
+		meta, err := parseBullMQMetadata(testData.metadataTestData.metadata, nil, nil)
+		if err != nil {
+			t.Fatal("Could not parse metadata:", err)
+		}
+		mockBullMQScaler := bullMQScaler{meta, nil}
+
+		metricSpec := mockBullMQScaler.GetMetricSpecForScaling()
+		metricName := metricSpec[0].External.Metric.Name
+		if metricName != testData.name {
+			t.Error("Wrong External metric source name:", metricName)
+		}
+	}
+}
+
+func TestBullMQStateKey(t *testing.T) {
+	meta, err := parseBullMQMetadata(map[string]string{"address": "redis:6379", "queueName": "emails", "prefix": "app"}, nil, nil)
+	if err != nil {
+		t.Fatal("Could not parse metadata:", err)
+	}
+	scaler := bullMQScaler{meta, nil}
+
+	if key := scaler.stateKey("delayed"); key != "app:emails:delayed" {
+		t.Error("Wrong state key:", key)
+	}
+}
@@ -0,0 +1,125 @@
+package scalers
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/metrics/pkg/apis/external_metrics"
+)
+
+const isActiveResultKey = "isActive"
+
+// ScalerResultCache keeps the results of the checks of the scalers of a trigger for a short time, so the checks made close
+// together by the scale loop, the HPA metric reads and the dry-run or trigger evaluations are served by a single upstream call.
+// The errors aren't cached.
+type ScalerResultCache struct {
+	mutex   sync.Mutex
+	ttl     time.Duration
+	results map[string]scalerResult
+	now     func() time.Time
+}
+
+type scalerResult struct {
+	isActive bool
+	metrics  []external_metrics.ExternalMetricValue
+	expires  time.Time
+}
+
+// NewScalerResultCache returns a cache keeping the results for the ttl
+func NewScalerResultCache(ttl time.Duration) *ScalerResultCache {
+	return &ScalerResultCache{
+		ttl:     ttl,
+		results: make(map[string]scalerResult),
+		now:     time.Now,
+	}
+}
+
+// TTL returns how long the results are kept
+func (c *ScalerResultCache) TTL() time.Duration {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.ttl
+}
+
+// SetTTL changes how long the results are kept, the results already cached expire with their previous ttl
+func (c *ScalerResultCache) SetTTL(ttl time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.ttl = ttl
+}
+
+func (c *ScalerResultCache) get(key string) (scalerResult, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	result, ok := c.results[key]
+	if !ok {
+		return result, false
+	}
+	if !c.now().Before(result.expires) {
+		delete(c.results, key)
+		return result, false
+	}
+	return result, true
+}
+
+func (c *ScalerResultCache) set(key string, result scalerResult) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	result.expires = c.now().Add(c.ttl)
+	c.results[key] = result
+}
+
+// cachedScaler serves the checks of the wrapped scaler from the result cache of its trigger
+type cachedScaler struct {
+	Scaler
+	cache *ScalerResultCache
+}
+
+// NewCachedScaler wraps the scaler so its checks are served from the cache while the results are fresh,
+// the cache is shared by the instances of the scaler created for the same trigger
+func NewCachedScaler(scaler Scaler, cache *ScalerResultCache) Scaler {
+	return &cachedScaler{
+		Scaler: scaler,
+		cache:  cache,
+	}
+}
+
+func (s *cachedScaler) IsActive(ctx context.Context) (bool, error) {
+	if result, ok := s.cache.get(isActiveResultKey); ok {
+		return result.isActive, nil
+	}
+	isActive, err := s.Scaler.IsActive(ctx)
+	if err == nil {
+		s.cache.set(isActiveResultKey, scalerResult{isActive: isActive})
+	}
+	return isActive, err
+}
+
+func (s *cachedScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
+	key := "metrics/" + metricName
+	if metricSelector != nil {
+		key += "/" + metricSelector.String()
+	}
+	if result, ok := s.cache.get(key); ok {
+		return copyMetricValues(result.metrics), nil
+	}
+	metrics, err := s.Scaler.GetMetrics(ctx, metricName, metricSelector)
+	if err == nil {
+		// the callers may modify the values returned, e.g. to apply a value expression
+		s.cache.set(key, scalerResult{metrics: copyMetricValues(metrics)})
+	}
+	return metrics, err
+}
+
+func copyMetricValues(metrics []external_metrics.ExternalMetricValue) []external_metrics.ExternalMetricValue {
+	if metrics == nil {
+		return nil
+	}
+	copied := make([]external_metrics.ExternalMetricValue, len(metrics))
+	for i := range metrics {
+		metrics[i].DeepCopyInto(&copied[i])
+	}
+	return copied
+}
@@ -0,0 +1,88 @@
+package scalers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"k8s.io/api/autoscaling/v2beta2"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/metrics/pkg/apis/external_metrics"
+)
+
+// failingScaler counts its calls and fails them while err is set
+type failingScaler struct {
+	calls int
+	err   error
+}
+
+func (s *failingScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
+	s.calls++
+	if s.err != nil {
+		return nil, s.err
+	}
+	return []external_metrics.ExternalMetricValue{{MetricName: metricName, Value: *resource.NewQuantity(10, resource.DecimalSI)}}, nil
+}
+
+func (s *failingScaler) GetMetricSpecForScaling() []v2beta2.MetricSpec {
+	return nil
+}
+
+func (s *failingScaler) IsActive(ctx context.Context) (bool, error) {
+	s.calls++
+	return s.err == nil, s.err
+}
+
+func (s *failingScaler) Close() error {
+	return nil
+}
+
+func TestCachedScaler(t *testing.T) {
+	now := time.Now()
+	cache := NewScalerResultCache(5 * time.Second)
+	cache.now = func() time.Time { return now }
+
+	wrapped := &failingScaler{}
+	scaler := NewCachedScaler(wrapped, cache)
+	if name := ScalerName(scaler); name != "failingScaler" {
+		t.Errorf("Expected the name of the wrapped scaler, got %s", name)
+	}
+
+	// the second check of each kind is served from the cache
+	for i := 0; i < 2; i++ {
+		if isActive, err := scaler.IsActive(context.TODO()); err != nil || !isActive {
+			t.Errorf("Expected active without error, got %t and %v", isActive, err)
+		}
+		metrics, err := scaler.GetMetrics(context.TODO(), "queueLength", nil)
+		if err != nil || len(metrics) != 1 || metrics[0].Value.Value() != 10 {
+			t.Errorf("Expected the metric value 10 without error, got %v and %v", metrics, err)
+		}
+		// the cached values aren't changed by the callers
+		metrics[0].Value = *resource.NewQuantity(99, resource.DecimalSI)
+	}
+	if wrapped.calls != 2 {
+		t.Errorf("Expected 2 calls of the wrapped scaler, got %d", wrapped.calls)
+	}
+
+	// another metric isn't cached yet
+	if _, err := scaler.GetMetrics(context.TODO(), "other", nil); err != nil {
+		t.Errorf("Expected no error, got %s", err)
+	}
+	if wrapped.calls != 3 {
+		t.Errorf("Expected 3 calls of the wrapped scaler, got %d", wrapped.calls)
+	}
+
+	// the results expire after the ttl and the errors aren't cached
+	now = now.Add(5 * time.Second)
+	wrapped.err = errors.New("upstream error")
+	for i := 0; i < 2; i++ {
+		if _, err := scaler.IsActive(context.TODO()); err == nil {
+			t.Error("Expected the error of the wrapped scaler")
+		}
+	}
+	if wrapped.calls != 5 {
+		t.Errorf("Expected 5 calls of the wrapped scaler, got %d", wrapped.calls)
+	}
+}
@@ -0,0 +1,236 @@
+package scalers
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-redis/redis"
+	v2beta2 "k8s.io/api/autoscaling/v2beta2"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/metrics/pkg/apis/external_metrics"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	kedautil "github.com/kedacore/keda/pkg/util"
+)
+
+const (
+	// celeryPrioritySeparator is the separator kombu puts between the queue name and the priority of a sub queue
+	celeryPrioritySeparator  = "\x06\x16"
+	defaultCeleryQueueLength = 5
+)
+
+// defaultCeleryPrioritySteps are the default priority_steps of the kombu Redis transport
+var defaultCeleryPrioritySteps = []int{0, 3, 6, 9}
+
+type celeryScaler struct {
+	metadata *celeryMetadata
+	client   *redis.Client
+}
+
+type celeryMetadata struct {
+	queueNames        []string
+	prioritySteps     []int
+	keyPrefix         string
+	targetQueueLength int
+	databaseIndex     int
+	connectionInfo    redisConnectionInfo
+}
+
+var celeryLog = logf.Log.WithName("celery_scaler")
+
+// NewCeleryScaler creates a new scaler for the backlog of Celery queues with a Redis broker
+func NewCeleryScaler(resolvedEnv, metadata, authParams map[string]string) (Scaler, error) {
+	meta, err := parseCeleryMetadata(metadata, resolvedEnv, authParams)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing celery metadata: %s", err)
+	}
+	options := &redis.Options{
+		Addr:     meta.connectionInfo.address,
+		Password: meta.connectionInfo.password,
+		DB:       meta.databaseIndex,
+	}
+
+	if meta.connectionInfo.enableTLS {
+		options.TLSConfig = &tls.Config{
+			InsecureSkipVerify: meta.connectionInfo.enableTLS,
+		}
+	}
+
+	return &celeryScaler{
+		metadata: meta,
+		client:   redis.NewClient(options),
+	}, nil
+}
+
+func parseCeleryMetadata(metadata, resolvedEnv, authParams map[string]string) (*celeryMetadata, error) {
+	connInfo, err := parseRedisAddress(metadata, resolvedEnv, authParams)
+	if err != nil {
+		return nil, err
+	}
+	meta := celeryMetadata{
+		connectionInfo:    connInfo,
+		prioritySteps:     defaultCeleryPrioritySteps,
+		targetQueueLength: defaultCeleryQueueLength,
+		databaseIndex:     defaultDbIdx,
+	}
+
+	if val, ok := metadata["queueName"]; ok && val != "" {
+		for _, queueName := range strings.Split(val, ",") {
+			if queueName = strings.TrimSpace(queueName); queueName != "" {
+				meta.queueNames = append(meta.queueNames, queueName)
+			}
+		}
+	}
+	if len(meta.queueNames) == 0 {
+		return nil, fmt.Errorf("no queue name given")
+	}
+
+	if val, ok := metadata["queueLength"]; ok && val != "" {
+		queueLength, err := strconv.Atoi(val)
+		if err != nil {
+			return nil, fmt.Errorf("queueLength parsing error %s", err.Error())
+		}
+		meta.targetQueueLength = queueLength
+	}
+
+	if val, ok := metadata["prioritySteps"]; ok && val != "" {
+		prioritySteps, err := parseCeleryPrioritySteps(val)
+		if err != nil {
+			return nil, err
+		}
+		meta.prioritySteps = prioritySteps
+	}
+
+	if val, ok := metadata["keyPrefix"]; ok {
+		meta.keyPrefix = val
+	}
+
+	if val, ok := metadata["databaseIndex"]; ok && val != "" {
+		dbIndex, err := strconv.ParseInt(val, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("databaseIndex: parsing error %s", err.Error())
+		}
+		meta.databaseIndex = int(dbIndex)
+	}
+
+	return &meta, nil
+}
+
+// parseCeleryPrioritySteps parses a comma separated list of the priority_steps of the broker transport options
+func parseCeleryPrioritySteps(val string) ([]int, error) {
+	seen := map[int]bool{}
+	var prioritySteps []int
+	for _, s := range strings.Split(val, ",") {
+		step, err := strconv.Atoi(strings.TrimSpace(s))
+		if err != nil || step < 0 || step > 255 {
+			return nil, fmt.Errorf("invalid priority step %q in prioritySteps", s)
+		}
+		if !seen[step] {
+			seen[step] = true
+			prioritySteps = append(prioritySteps, step)
+		}
+	}
+	sort.Ints(prioritySteps)
+	return prioritySteps, nil
+}
+
+// queueKeys returns the Redis lists of the queues, kombu keeps the tasks of priority step 0 in the list named after the queue
+// and the tasks of each other step in a list with the step appended after the priority separator
+func (s *celeryScaler) queueKeys() []string {
+	var keys []string
+	for _, queueName := range s.metadata.queueNames {
+		for _, step := range s.metadata.prioritySteps {
+			key := s.metadata.keyPrefix + queueName
+			if step != 0 {
+				key = fmt.Sprintf("%s%s%d", key, celeryPrioritySeparator, step)
+			}
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// getQueueLength sums the lengths of the priority sub queues of all the queues
+func (s *celeryScaler) getQueueLength(ctx context.Context) (int64, error) {
+	keys := s.queueKeys()
+	pipeline := s.client.WithContext(ctx).Pipeline()
+	defer pipeline.Close()
+
+	cmds := make([]*redis.IntCmd, len(keys))
+	for i, key := range keys {
+		cmds[i] = pipeline.LLen(key)
+	}
+	if _, err := pipeline.Exec(); err != nil {
+		return -1, err
+	}
+
+	var length int64
+	for _, cmd := range cmds {
+		length += cmd.Val()
+	}
+	return length, nil
+}
+
+// IsActive checks if there is any task in the queues
+func (s *celeryScaler) IsActive(ctx context.Context) (bool, error) {
+	length, err := s.getQueueLength(ctx)
+	if err != nil {
+		celeryLog.Error(err, "error getting queue length")
+		return false, err
+	}
+
+	return length > 0, nil
+}
+
+func (s *celeryScaler) Close() error {
+	if s.client != nil {
+		err := s.client.Close()
+		if err != nil {
+			celeryLog.Error(err, "error closing redis client")
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetMetricSpecForScaling returns the metric spec for the HPA
+func (s *celeryScaler) GetMetricSpecForScaling() []v2beta2.MetricSpec {
+	targetQueueLengthQty := resource.NewQuantity(int64(s.metadata.targetQueueLength), resource.DecimalSI)
+	externalMetric := &v2beta2.ExternalMetricSource{
+		Metric: v2beta2.MetricIdentifier{
+			Name: kedautil.NormalizeString(fmt.Sprintf("%s-%s", "celery", strings.Join(s.metadata.queueNames, "-"))),
+		},
+		Target: v2beta2.MetricTarget{
+			Type:         v2beta2.AverageValueMetricType,
+			AverageValue: targetQueueLengthQty,
+		},
+	}
+	metricSpec := v2beta2.MetricSpec{
+		External: externalMetric, Type: externalMetricType,
+	}
+	return []v2beta2.MetricSpec{metricSpec}
+}
+
+// GetMetrics connects to Redis and sums the lengths of the queues
+func (s *celeryScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
+	length, err := s.getQueueLength(ctx)
+	if err != nil {
+		celeryLog.Error(err, "error getting queue length")
+		return []external_metrics.ExternalMetricValue{}, err
+	}
+
+	metric := external_metrics.ExternalMetricValue{
+		MetricName: metricName,
+		Value:      *resource.NewQuantity(length, resource.DecimalSI),
+		Timestamp:  metav1.Now(),
+	}
+
+	return append([]external_metrics.ExternalMetricValue{}, metric), nil
+}
@@ -0,0 +1,95 @@
+package scalers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type parseCeleryMetadataTestData struct {
+	metadata   map[string]string
+	authParams map[string]string
+	isError    bool
+}
+
+type celeryMetricIdentifier struct {
+	metadataTestData *parseCeleryMetadataTestData
+	name             string
+}
+
+var testCeleryMetadata = []parseCeleryMetadataTestData{
+	// nothing passed
+	{map[string]string{}, map[string]string{}, true},
+	// properly formed
+	{map[string]string{"address": "redis:6379", "queueName": "celery", "queueLength": "10"}, map[string]string{}, false},
+	// multiple queues with custom priority steps
+	{map[string]string{"address": "redis:6379", "queueName": "high, low", "prioritySteps": "0,1,2,3,4,5,6,7,8,9", "databaseIndex": "1"}, map[string]string{"password": "secret"}, false},
+	// missing queueName
+	{map[string]string{"address": "redis:6379"}, map[string]string{}, true},
+	// missing address
+	{map[string]string{"queueName": "celery"}, map[string]string{}, true},
+	// malformed queueLength
+	{map[string]string{"address": "redis:6379", "queueName": "celery", "queueLength": "many"}, map[string]string{}, true},
+	// malformed prioritySteps
+	{map[string]string{"address": "redis:6379", "queueName": "celery", "prioritySteps": "0,high"}, map[string]string{}, true},
+	// out of range prioritySteps
+	{map[string]string{"address": "redis:6379", "queueName": "celery", "prioritySteps": "0,-3"}, map[string]string{}, true},
+}
+
+var celeryMetricIdentifiers = []celeryMetricIdentifier{
+	{&testCeleryMetadata[1], "celery-celery"},
+	{&testCeleryMetadata[2], "celery-high-low"},
+}
+
+func TestCeleryParseMetadata(t *testing.T) {
+	for _, testData := range testCeleryMetadata {
+		_, err := parseCeleryMetadata(testData.metadata, nil, testData.authParams)
+		if err != nil && !testData.isError {
+			t.Error("Expected success but got error", err)
+		}
+		if testData.isError && err == nil {
+			t.Errorf("Expected error but got success for %v", testData.metadata)
+		}
+	}
+}
+
+func TestCeleryGetMetricSpecForScaling(t *testing.T) {
+	for _, testData := range celeryMetricIdentifiers {
+		meta, err := parseCeleryMetadata(testData.metadataTestData.metadata, nil, testData.metadataTestData.authParams)
+		if err != nil {
+			t.Fatal("Could not parse metadata:", err)
+		}
+		mockCeleryScaler := celeryScaler{meta, nil}
+
+		metricSpec := mockCeleryScaler.GetMetricSpecForScaling()
+		metricName := metricSpec[0].External.Metric.Name
+		if metricName != testData.name {
+			t.Error("Wrong External metric source name:", metricName)
+		}
+	}
+}
+
+func TestCeleryQueueKeys(t *testing.T) {
+	tests := []struct {
+		metadata map[string]string
+		expected []string
+	}{
+		{
+			map[string]string{"address": "redis:6379", "queueName": "celery"},
+			[]string{"celery", "celery\x06\x163", "celery\x06\x166", "celery\x06\x169"},
+		},
+		{
+			map[string]string{"address": "redis:6379", "queueName": "high,low", "prioritySteps": "5, 0, 5", "keyPrefix": "app:"},
+			[]string{"app:high", "app:high\x06\x165", "app:low", "app:low\x06\x165"},
+		},
+	}
+
+	for _, test := range tests {
+		meta, err := parseCeleryMetadata(test.metadata, nil, nil)
+		if err != nil {
+			t.Fatal("Could not parse metadata:", err)
+		}
+		scaler := celeryScaler{meta, nil}
+		assert.Equal(t, test.expected, scaler.queueKeys())
+	}
+}
@@ -0,0 +1,378 @@
+package scalers
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	v2beta2 "k8s.io/api/autoscaling/v2beta2"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/metrics/pkg/apis/external_metrics"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/kedacore/keda/pkg/globalconfig"
+	kedautil "github.com/kedacore/keda/pkg/util"
+)
+
+const (
+	commandTargetValueName = "targetValue"
+	defaultCommandTimeout  = 60 * time.Second
+	// the value is read from the termination message of the container, the stdout of the command is redirected to it
+	commandTerminationMessagePath = "/dev/termination-log"
+	commandContainerName          = "command"
+	// commandTriggerLabel is the label of the Jobs of the trigger, its value is the name of the ScaledObject
+	commandTriggerLabel = "keda.sh/command-trigger"
+	// commandMetricLabel tells apart the Jobs of the triggers of a ScaledObject, its value is a hash of the metric name of the trigger
+	commandMetricLabel = "keda.sh/command-metric"
+	// the last Job of a trigger is kept for GetMetrics until the next one returned, it is deleted by the TTL controller
+	// once the ScaledObject is gone if the cluster runs it
+	commandJobTTL = 24 * time.Hour
+)
+
+// commandPollInterval is the interval the pods of the Job are checked at until the command returns
+var commandPollInterval = time.Second
+
+type commandScaler struct {
+	metadata   *commandMetadata
+	kubeClient client.Client
+}
+
+type commandMetadata struct {
+	// the image is run in a Job in the namespace of the ScaledObject, with the shell command line if given
+	// or its entrypoint writing the value to the termination message otherwise
+	image              string
+	command            string
+	serviceAccountName string
+	imagePullSecret    string
+	targetValue        float64
+	activationValue    float64
+	timeout            time.Duration
+	metricName         string
+	name               string
+	namespace          string
+	// the checks within the polling interval of the ScaledObject reuse the value of the last Job, a Job is run at each check if 0
+	pollingInterval time.Duration
+}
+
+var commandLog = logf.Log.WithName("command_scaler")
+
+// NewCommandScaler creates a new scaler for the numeric value returned by a command, run as a Job in the namespace of the ScaledObject.
+// The image and the service account must be allowed by the global configuration, the trigger is disabled by default
+func NewCommandScaler(kubeClient client.Client, name, namespace string, metadata map[string]string) (Scaler, error) {
+	meta, err := parseCommandMetadata(name, namespace, metadata)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing command metadata: %s", err)
+	}
+	if err := globalconfig.Get().CheckCommandTrigger(meta.image, meta.serviceAccountName); err != nil {
+		return nil, err
+	}
+
+	return &commandScaler{
+		metadata:   meta,
+		kubeClient: kubeClient,
+	}, nil
+}
+
+func parseCommandMetadata(name, namespace string, metadata map[string]string) (*commandMetadata, error) {
+	meta := commandMetadata{
+		timeout:   defaultCommandTimeout,
+		name:      name,
+		namespace: namespace,
+	}
+
+	if val, ok := metadata["image"]; ok && val != "" {
+		meta.image = val
+	} else {
+		return nil, fmt.Errorf("no image given")
+	}
+	meta.command = metadata["command"]
+	meta.serviceAccountName = metadata["serviceAccountName"]
+	meta.imagePullSecret = metadata["imagePullSecret"]
+
+	if val, ok := metadata[commandTargetValueName]; ok && val != "" {
+		targetValue, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			return nil, fmt.Errorf("can't parse %s: %s", commandTargetValueName, err)
+		}
+		meta.targetValue = targetValue
+	} else {
+		return nil, fmt.Errorf("no %s given", commandTargetValueName)
+	}
+
+	if val, ok := metadata["activationValue"]; ok && val != "" {
+		activationValue, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			return nil, fmt.Errorf("can't parse activationValue: %s", err)
+		}
+		meta.activationValue = activationValue
+	}
+
+	if val, ok := metadata["timeout"]; ok && val != "" {
+		timeout, err := strconv.Atoi(val)
+		if err != nil || timeout <= 0 {
+			return nil, fmt.Errorf("timeout %s is not a positive number of seconds", val)
+		}
+		meta.timeout = time.Duration(timeout) * time.Second
+	}
+
+	meta.metricName = metadata["metricName"]
+	if meta.metricName == "" {
+		meta.metricName = name
+	}
+
+	return &meta, nil
+}
+
+// SetPollingInterval sets the polling interval of the ScaledObject, a Job is run at most once per interval
+func (s *commandScaler) SetPollingInterval(interval time.Duration) {
+	s.metadata.pollingInterval = interval
+}
+
+// jobLabels returns the labels of the Jobs of the trigger and of their pods
+func (s *commandScaler) jobLabels() map[string]string {
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(s.metadata.metricName))
+	return map[string]string{commandTriggerLabel: s.metadata.name, commandMetricLabel: fmt.Sprintf("%08x", hash.Sum32())}
+}
+
+// newJob returns the Job running the command once, it is kept until the Job of the next check returned its value
+func (s *commandScaler) newJob() *batchv1.Job {
+	backoffLimit := int32(0)
+	activeDeadlineSeconds := int64(s.metadata.timeout / time.Second)
+	ttlSecondsAfterFinished := int32(commandJobTTL / time.Second)
+	automountToken := s.metadata.serviceAccountName != ""
+	jobLabels := s.jobLabels()
+	// the name of the Job is a label of its pods, it can't be longer than 63 characters
+	prefix := s.metadata.name
+	if len(prefix) > 48 {
+		prefix = strings.TrimRight(prefix[:48], "-.")
+	}
+
+	container := corev1.Container{
+		Name:                     commandContainerName,
+		Image:                    s.metadata.image,
+		TerminationMessagePath:   commandTerminationMessagePath,
+		TerminationMessagePolicy: corev1.TerminationMessageReadFile,
+	}
+	if s.metadata.command != "" {
+		container.Command = []string{"/bin/sh", "-c", fmt.Sprintf("(%s) > %s", s.metadata.command, commandTerminationMessagePath)}
+	}
+
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-command-%s", prefix, rand.String(5)),
+			Namespace: s.metadata.namespace,
+			Labels:    jobLabels,
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit:            &backoffLimit,
+			ActiveDeadlineSeconds:   &activeDeadlineSeconds,
+			TTLSecondsAfterFinished: &ttlSecondsAfterFinished,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: jobLabels},
+				Spec: corev1.PodSpec{
+					RestartPolicy:                corev1.RestartPolicyNever,
+					ServiceAccountName:           s.metadata.serviceAccountName,
+					AutomountServiceAccountToken: &automountToken,
+					Containers:                   []corev1.Container{container},
+				},
+			},
+		},
+	}
+	if s.metadata.imagePullSecret != "" {
+		job.Spec.Template.Spec.ImagePullSecrets = []corev1.LocalObjectReference{{Name: s.metadata.imagePullSecret}}
+	}
+	return job
+}
+
+// getValue returns the value of the last Job of the trigger if it was created within the polling interval, the command is run
+// in a new Job otherwise. The new Job is deleted with its pod if the command failed or timed out, the previous Jobs once it returned its value
+func (s *commandScaler) getValue(ctx context.Context) (float64, error) {
+	if s.metadata.pollingInterval > 0 {
+		// the check of the next interval may start slightly before the interval elapsed since the last Job was created
+		value, created, err := s.getLastValue(ctx)
+		if err == nil && time.Since(created) < s.metadata.pollingInterval-s.metadata.pollingInterval/10 {
+			return value, nil
+		}
+	}
+
+	job := s.newJob()
+	if err := s.kubeClient.Create(ctx, job); err != nil {
+		return 0, fmt.Errorf("error creating the job of the command: %s", err)
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, s.metadata.timeout)
+	defer cancel()
+	value, err := s.waitForValue(waitCtx, job.Name)
+	if err != nil {
+		s.deleteJob(job)
+		return 0, err
+	}
+	s.deletePreviousJobs(ctx, job.Name)
+	return value, nil
+}
+
+// getLastValue returns the value of the last pod of the trigger whose command returned, with the creation time of the pod
+func (s *commandScaler) getLastValue(ctx context.Context) (float64, time.Time, error) {
+	pods := &corev1.PodList{}
+	if err := s.kubeClient.List(ctx, pods, client.InNamespace(s.metadata.namespace), client.MatchingLabels(s.jobLabels())); err != nil {
+		return 0, time.Time{}, err
+	}
+	var last *corev1.Pod
+	var lastTerminated *corev1.ContainerStateTerminated
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.DeletionTimestamp != nil {
+			continue
+		}
+		for _, status := range pod.Status.ContainerStatuses {
+			if status.Name != commandContainerName || status.State.Terminated == nil {
+				continue
+			}
+			if last == nil || lastTerminated.FinishedAt.Before(&status.State.Terminated.FinishedAt) {
+				last = pod
+				lastTerminated = status.State.Terminated
+			}
+		}
+	}
+	if last == nil {
+		return 0, time.Time{}, fmt.Errorf("no job of the command returned a value yet")
+	}
+	value, err := parseCommandOutput(lastTerminated)
+	return value, last.CreationTimestamp.Time, err
+}
+
+// deletePreviousJobs deletes the Jobs of the trigger but the last one, its value is served by GetMetrics
+func (s *commandScaler) deletePreviousJobs(ctx context.Context, lastJobName string) {
+	jobs := &batchv1.JobList{}
+	if err := s.kubeClient.List(ctx, jobs, client.InNamespace(s.metadata.namespace), client.MatchingLabels(s.jobLabels())); err != nil {
+		commandLog.Error(err, "error listing the jobs of the command")
+		return
+	}
+	for i := range jobs.Items {
+		if jobs.Items[i].Name != lastJobName {
+			s.deleteJob(&jobs.Items[i])
+		}
+	}
+}
+
+func (s *commandScaler) deleteJob(job *batchv1.Job) {
+	if err := s.kubeClient.Delete(context.TODO(), job, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil && !errors.IsNotFound(err) {
+		commandLog.Error(err, "error deleting the job of the command", "job", job.Name)
+	}
+}
+
+// waitForValue checks the pods of the Job until one of them returned the value of the command
+func (s *commandScaler) waitForValue(ctx context.Context, jobName string) (float64, error) {
+	ticker := time.NewTicker(commandPollInterval)
+	defer ticker.Stop()
+	for {
+		value, done, err := s.getJobValue(ctx, jobName)
+		if done {
+			return value, err
+		}
+		if err != nil {
+			commandLog.V(1).Info("error checking the pods of the command", "job", jobName, "error", err.Error())
+		}
+
+		select {
+		case <-ctx.Done():
+			return 0, fmt.Errorf("the command of job %s didn't return within %s", jobName, s.metadata.timeout)
+		case <-ticker.C:
+		}
+	}
+}
+
+// getJobValue returns the value of the command once the container of a pod of the Job terminated, done is false until then
+func (s *commandScaler) getJobValue(ctx context.Context, jobName string) (float64, bool, error) {
+	pods := &corev1.PodList{}
+	if err := s.kubeClient.List(ctx, pods, client.InNamespace(s.metadata.namespace), client.MatchingLabels{"job-name": jobName}); err != nil {
+		return 0, false, err
+	}
+	for _, pod := range pods.Items {
+		for _, status := range pod.Status.ContainerStatuses {
+			if status.Name != commandContainerName || status.State.Terminated == nil {
+				continue
+			}
+			value, err := parseCommandOutput(status.State.Terminated)
+			return value, true, err
+		}
+	}
+	return 0, false, nil
+}
+
+// parseCommandOutput returns the value of the last line of the termination message of the command, it must have exited with 0
+func parseCommandOutput(terminated *corev1.ContainerStateTerminated) (float64, error) {
+	output := strings.TrimSpace(terminated.Message)
+	if terminated.ExitCode != 0 {
+		return 0, fmt.Errorf("the command exited with %d: %s", terminated.ExitCode, output)
+	}
+	if i := strings.LastIndex(output, "\n"); i >= 0 {
+		output = strings.TrimSpace(output[i+1:])
+	}
+	value, err := strconv.ParseFloat(output, 64)
+	if err != nil {
+		return 0, fmt.Errorf("the command returned %q, not a number", output)
+	}
+	return value, nil
+}
+
+// IsActive returns true if the value of the command is above the activation value, the command is run at most once per polling interval
+func (s *commandScaler) IsActive(ctx context.Context) (bool, error) {
+	value, err := s.getValue(ctx)
+	if err != nil {
+		commandLog.Error(err, "error getting command value", "image", s.metadata.image)
+		return false, err
+	}
+
+	return value > s.metadata.activationValue, nil
+}
+
+// Close does nothing in case of commandScaler
+func (s *commandScaler) Close() error {
+	return nil
+}
+
+// GetMetricSpecForScaling returns the MetricSpec for the Horizontal Pod Autoscaler
+func (s *commandScaler) GetMetricSpecForScaling() []v2beta2.MetricSpec {
+	targetValue := resource.NewMilliQuantity(int64(math.Round(s.metadata.targetValue*1000)), resource.DecimalSI)
+	externalMetric := &v2beta2.ExternalMetricSource{
+		Metric: v2beta2.MetricIdentifier{
+			Name: kedautil.NormalizeString(fmt.Sprintf("%s-%s", "command", s.metadata.metricName)),
+		},
+		Target: v2beta2.MetricTarget{
+			Type:         v2beta2.AverageValueMetricType,
+			AverageValue: targetValue,
+		},
+	}
+	metricSpec := v2beta2.MetricSpec{External: externalMetric, Type: externalMetricType}
+	return []v2beta2.MetricSpec{metricSpec}
+}
+
+// GetMetrics returns the value returned by the last Job of the trigger, the Jobs are only run by the checks of the operator
+// so the metrics server doesn't wait for the command
+func (s *commandScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
+	value, _, err := s.getLastValue(ctx)
+	if err != nil {
+		return []external_metrics.ExternalMetricValue{}, fmt.Errorf("error getting command value: %s", err)
+	}
+
+	metric := external_metrics.ExternalMetricValue{
+		MetricName: metricName,
+		Value:      *resource.NewMilliQuantity(int64(math.Round(value*1000)), resource.DecimalSI),
+		Timestamp:  metav1.Now(),
+	}
+
+	return append([]external_metrics.ExternalMetricValue{}, metric), nil
+}
@@ -0,0 +1,211 @@
+package scalers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+type parseCommandMetadataTestData struct {
+	metadata map[string]string
+	isError  bool
+}
+
+type commandMetricIdentifier struct {
+	metadataTestData *parseCommandMetadataTestData
+	name             string
+}
+
+var testCommandMetadata = []parseCommandMetadataTestData{
+	// nothing passed
+	{map[string]string{}, true},
+	// shell command line
+	{map[string]string{"image": "registry.local/tools:1.2", "command": "queue-depth --queue orders", "targetValue": "10"}, false},
+	// entrypoint of the image with all the options
+	{map[string]string{"image": "registry.local/depth:1.0", "targetValue": "2.5", "activationValue": "1", "timeout": "30", "serviceAccountName": "depth", "imagePullSecret": "registry", "metricName": "orders"}, false},
+	// missing image
+	{map[string]string{"command": "echo 1", "targetValue": "10"}, true},
+	// missing targetValue
+	{map[string]string{"image": "busybox", "command": "echo 1"}, true},
+	// malformed targetValue
+	{map[string]string{"image": "busybox", "targetValue": "ten"}, true},
+	// malformed activationValue
+	{map[string]string{"image": "busybox", "targetValue": "10", "activationValue": "one"}, true},
+	// timeout is zero
+	{map[string]string{"image": "busybox", "targetValue": "10", "timeout": "0"}, true},
+}
+
+var commandMetricIdentifiers = []commandMetricIdentifier{
+	{&testCommandMetadata[1], "command-worker"},
+	{&testCommandMetadata[2], "command-orders"},
+}
+
+func TestCommandParseMetadata(t *testing.T) {
+	for _, testData := range testCommandMetadata {
+		_, err := parseCommandMetadata("worker", "default", testData.metadata)
+		if err != nil && !testData.isError {
+			t.Error("Expected success but got error", err)
+		}
+		if testData.isError && err == nil {
+			t.Error("Expected error but got success")
+		}
+	}
+}
+
+func TestCommandGetMetricSpecForScaling(t *testing.T) {
+	for _, testData := range commandMetricIdentifiers {
+		meta, err := parseCommandMetadata("worker", "default", testData.metadataTestData.metadata)
+		if err != nil {
+			t.Fatal("Could not parse metadata:", err)
+		}
+		mockCommandScaler := commandScaler{metadata: meta}
+
+		metricSpec := mockCommandScaler.GetMetricSpecForScaling()
+		metricName := metricSpec[0].External.Metric.Name
+		if metricName != testData.name {
+			t.Error("Wrong External metric source name:", metricName)
+		}
+	}
+}
+
+func TestCommandNewJob(t *testing.T) {
+	meta, _ := parseCommandMetadata("worker", "default", testCommandMetadata[1].metadata)
+	job := (&commandScaler{metadata: meta}).newJob()
+	assert.Equal(t, "default", job.Namespace)
+	assert.Equal(t, "worker", job.Labels[commandTriggerLabel])
+	assert.Equal(t, job.Labels, job.Spec.Template.Labels)
+	assert.Equal(t, int32(24*60*60), *job.Spec.TTLSecondsAfterFinished)
+	assert.Equal(t, int32(0), *job.Spec.BackoffLimit)
+	assert.Equal(t, int64(60), *job.Spec.ActiveDeadlineSeconds)
+
+	podSpec := job.Spec.Template.Spec
+	assert.Equal(t, corev1.RestartPolicyNever, podSpec.RestartPolicy)
+	assert.False(t, *podSpec.AutomountServiceAccountToken)
+	assert.Equal(t, []string{"/bin/sh", "-c", "(queue-depth --queue orders) > /dev/termination-log"}, podSpec.Containers[0].Command)
+
+	// the entrypoint of the image writes the value itself
+	meta, _ = parseCommandMetadata("worker", "default", testCommandMetadata[2].metadata)
+	podSpec = (&commandScaler{metadata: meta}).newJob().Spec.Template.Spec
+	assert.Nil(t, podSpec.Containers[0].Command)
+	assert.Equal(t, "depth", podSpec.ServiceAccountName)
+	assert.True(t, *podSpec.AutomountServiceAccountToken)
+	assert.Equal(t, []corev1.LocalObjectReference{{Name: "registry"}}, podSpec.ImagePullSecrets)
+}
+
+func TestNewCommandScalerDisabled(t *testing.T) {
+	// no image is allowed by the default global configuration
+	_, err := NewCommandScaler(nil, "worker", "default", testCommandMetadata[1].metadata)
+	assert.Error(t, err)
+}
+
+func TestCommandParseOutput(t *testing.T) {
+	tests := []struct {
+		name       string
+		terminated corev1.ContainerStateTerminated
+		value      float64
+		isError    bool
+	}{
+		{"value", corev1.ContainerStateTerminated{Message: "42\n"}, 42, false},
+		{"last line", corev1.ContainerStateTerminated{Message: "connecting\n 12.5 \n"}, 12.5, false},
+		{"not a number", corev1.ContainerStateTerminated{Message: "done"}, 0, true},
+		{"failed", corev1.ContainerStateTerminated{ExitCode: 1, Message: "42"}, 0, true},
+	}
+
+	for _, test := range tests {
+		value, err := parseCommandOutput(&test.terminated)
+		if test.isError {
+			assert.Error(t, err, test.name)
+			continue
+		}
+		assert.NoError(t, err, test.name)
+		assert.Equal(t, test.value, value, test.name)
+	}
+}
+
+func TestCommandGetValue(t *testing.T) {
+	commandPollInterval = 10 * time.Millisecond
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "worker-command-abcde-x1", Labels: map[string]string{"job-name": "worker-command-abcde"}},
+		Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{
+			Name:  commandContainerName,
+			State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Message: "7"}},
+		}}},
+	}
+	kubeClient := fake.NewFakeClientWithScheme(scheme.Scheme, pod)
+	meta, _ := parseCommandMetadata("worker", "default", testCommandMetadata[1].metadata)
+	meta.timeout = 100 * time.Millisecond
+	scaler := &commandScaler{metadata: meta, kubeClient: kubeClient}
+
+	value, err := scaler.waitForValue(context.TODO(), "worker-command-abcde")
+	assert.NoError(t, err)
+	assert.Equal(t, float64(7), value)
+
+	// the command of the new Job doesn't return, the Job is deleted once it timed out
+	_, err = scaler.getValue(context.TODO())
+	assert.Error(t, err)
+	jobs := &batchv1.JobList{}
+	assert.NoError(t, kubeClient.List(context.TODO(), jobs, client.InNamespace("default")))
+	assert.Empty(t, jobs.Items)
+}
+
+func TestCommandLastValue(t *testing.T) {
+	meta, _ := parseCommandMetadata("worker", "default", testCommandMetadata[1].metadata)
+	meta.pollingInterval = 30 * time.Second
+	scaler := &commandScaler{metadata: meta, kubeClient: fake.NewFakeClientWithScheme(scheme.Scheme)}
+
+	// no Job returned a value yet
+	_, err := scaler.GetMetrics(context.TODO(), "command-worker", nil)
+	assert.Error(t, err)
+
+	newPod := func(name string, created, finished time.Time, message string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name, Labels: scaler.jobLabels(), CreationTimestamp: metav1.NewTime(created)},
+			Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{
+				Name:  commandContainerName,
+				State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Message: message, FinishedAt: metav1.NewTime(finished)}},
+			}}},
+		}
+	}
+	now := time.Now()
+	scaler.kubeClient = fake.NewFakeClientWithScheme(scheme.Scheme,
+		newPod("worker-command-old-x1", now.Add(-time.Minute), now.Add(-time.Minute), "3"),
+		newPod("worker-command-new-x1", now.Add(-time.Second), now, "5"))
+
+	// the metrics are the value of the last Job
+	metrics, err := scaler.GetMetrics(context.TODO(), "command-worker", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), metrics[0].Value.Value())
+
+	// the last Job was created within the polling interval, no Job is created
+	value, err := scaler.getValue(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, float64(5), value)
+	jobs := &batchv1.JobList{}
+	assert.NoError(t, scaler.kubeClient.List(context.TODO(), jobs, client.InNamespace("default")))
+	assert.Empty(t, jobs.Items)
+}
+
+func TestCommandDeletePreviousJobs(t *testing.T) {
+	meta, _ := parseCommandMetadata("worker", "default", testCommandMetadata[1].metadata)
+	scaler := &commandScaler{metadata: meta}
+	other, _ := parseCommandMetadata("worker", "default", testCommandMetadata[2].metadata)
+	previous, last, otherTrigger := scaler.newJob(), scaler.newJob(), (&commandScaler{metadata: other}).newJob()
+	scaler.kubeClient = fake.NewFakeClientWithScheme(scheme.Scheme, previous, last, otherTrigger)
+
+	scaler.deletePreviousJobs(context.TODO(), last.Name)
+	jobs := &batchv1.JobList{}
+	assert.NoError(t, scaler.kubeClient.List(context.TODO(), jobs, client.InNamespace("default")))
+	var names []string
+	for _, job := range jobs.Items {
+		names = append(names, job.Name)
+	}
+	assert.ElementsMatch(t, []string{last.Name, otherTrigger.Name}, names)
+}
@@ -0,0 +1,119 @@
+package scalers
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/metrics/pkg/apis/external_metrics"
+)
+
+// ConcurrencySlots are the slots shared by the scalers of a trigger type, their number can be changed while they are held
+type ConcurrencySlots struct {
+	lock  sync.Mutex
+	limit int
+	held  int
+	// changed is closed once a slot is released or the limit is changed
+	changed chan struct{}
+}
+
+// NewConcurrencySlots returns the given number of slots
+func NewConcurrencySlots(limit int) *ConcurrencySlots {
+	return &ConcurrencySlots{limit: limit, changed: make(chan struct{})}
+}
+
+// SetLimit changes the number of slots, the slots held over a lower limit are kept until they are released
+func (s *ConcurrencySlots) SetLimit(limit int) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.limit != limit {
+		s.limit = limit
+		s.notify()
+	}
+}
+
+// Limit returns the number of slots
+func (s *ConcurrencySlots) Limit() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.limit
+}
+
+// Held returns the number of slots held
+func (s *ConcurrencySlots) Held() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.held
+}
+
+func (s *ConcurrencySlots) acquire(ctx context.Context) error {
+	for {
+		s.lock.Lock()
+		if s.held < s.limit {
+			s.held++
+			s.lock.Unlock()
+			return nil
+		}
+		changed := s.changed
+		s.lock.Unlock()
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return fmt.Errorf("error waiting for the concurrency limit: %s", ctx.Err())
+		}
+	}
+}
+
+func (s *ConcurrencySlots) release() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.held--
+	s.notify()
+}
+
+func (s *ConcurrencySlots) notify() {
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
+// concurrencyLimitedScaler holds one of the shared slots during the checks of the wrapped scaler,
+// the checks wait for a free slot once all of them are held
+type concurrencyLimitedScaler struct {
+	Scaler
+	slots *ConcurrencySlots
+}
+
+// NewConcurrencyLimitedScaler wraps the scaler so its checks hold one of the slots, the number of slots is the
+// number of checks made at the same time. The slots are shared by the scalers of the same trigger type.
+func NewConcurrencyLimitedScaler(scaler Scaler, slots *ConcurrencySlots) Scaler {
+	return &concurrencyLimitedScaler{
+		Scaler: scaler,
+		slots:  slots,
+	}
+}
+
+func (s *concurrencyLimitedScaler) acquire(ctx context.Context) error {
+	return s.slots.acquire(ctx)
+}
+
+func (s *concurrencyLimitedScaler) release() {
+	s.slots.release()
+}
+
+func (s *concurrencyLimitedScaler) IsActive(ctx context.Context) (bool, error) {
+	if err := s.acquire(ctx); err != nil {
+		return false, err
+	}
+	defer s.release()
+	return s.Scaler.IsActive(ctx)
+}
+
+func (s *concurrencyLimitedScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
+	if err := s.acquire(ctx); err != nil {
+		return nil, err
+	}
+	defer s.release()
+	return s.Scaler.GetMetrics(ctx, metricName, metricSelector)
+}
@@ -0,0 +1,71 @@
+package scalers
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestConcurrencyLimitedScaler(t *testing.T) {
+	slots := NewConcurrencySlots(1)
+	wrapped := &hangingScaler{release: make(chan struct{})}
+	scaler := NewConcurrencyLimitedScaler(wrapped, slots)
+
+	if name := ScalerName(scaler); name != "hangingScaler" {
+		t.Errorf("Expected the name of the wrapped scaler, got %s", name)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if _, err := scaler.IsActive(context.TODO()); err != nil {
+			t.Errorf("Expected the first check to hold the slot, got error %s", err)
+		}
+	}()
+	for slots.Held() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	// the slot is held by the hanging check, so the other scaler of the trigger type waits until its context is done
+	other := NewConcurrencyLimitedScaler(&countingScaler{}, slots)
+	ctx, cancel := context.WithTimeout(context.TODO(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := other.GetMetrics(ctx, "metric", nil); err == nil {
+		t.Error("Expected the check over the limit to fail once the context is done")
+	}
+
+	close(wrapped.release)
+	<-done
+	if slots.Held() != 0 {
+		t.Errorf("Expected the slot to be released, %d held", slots.Held())
+	}
+	if _, err := other.IsActive(context.TODO()); err != nil {
+		t.Errorf("Expected the check to get the released slot, got error %s", err)
+	}
+}
+
+func TestConcurrencySlotsSetLimit(t *testing.T) {
+	slots := NewConcurrencySlots(0)
+	wrapped := &hangingScaler{release: make(chan struct{})}
+	close(wrapped.release)
+	scaler := NewConcurrencyLimitedScaler(wrapped, slots)
+
+	// the check waiting for a slot gets it once the limit is raised
+	done := make(chan error)
+	go func() {
+		_, err := scaler.IsActive(context.TODO())
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		t.Fatalf("Expected the check to wait for a slot, got %v", err)
+	case <-time.After(20 * time.Millisecond):
+	}
+	slots.SetLimit(1)
+	if err := <-done; err != nil {
+		t.Errorf("Expected the check to get the added slot, got error %s", err)
+	}
+	if slots.Limit() != 1 || slots.Held() != 0 {
+		t.Errorf("Expected 1 slot released, got %d slots and %d held", slots.Limit(), slots.Held())
+	}
+}
@@ -0,0 +1,102 @@
+package scalers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	v2beta2 "k8s.io/api/autoscaling/v2beta2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/metrics/pkg/apis/external_metrics"
+)
+
+// cpuMemoryScaler maps a cpu or memory trigger to a Resource metric of the HPA,
+// the metric is served by the Kubernetes metrics server and not by KEDA
+type cpuMemoryScaler struct {
+	metadata     *cpuMemoryMetadata
+	resourceName corev1.ResourceName
+}
+
+type cpuMemoryMetadata struct {
+	targetType         v2beta2.MetricTargetType
+	averageValue       *resource.Quantity
+	averageUtilization *int32
+}
+
+// NewCPUMemoryScaler creates a new scaler for the cpu or memory utilization of the pods of the ScaleTarget
+func NewCPUMemoryScaler(resourceName corev1.ResourceName, metadata map[string]string) (Scaler, error) {
+	meta, err := parseCPUMemoryMetadata(metadata)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing %s metadata: %s", resourceName, err)
+	}
+
+	return &cpuMemoryScaler{
+		metadata:     meta,
+		resourceName: resourceName,
+	}, nil
+}
+
+func parseCPUMemoryMetadata(metadata map[string]string) (*cpuMemoryMetadata, error) {
+	meta := cpuMemoryMetadata{}
+
+	val, ok := metadata["value"]
+	if !ok || val == "" {
+		return nil, fmt.Errorf("no value given")
+	}
+
+	switch metadata["type"] {
+	case "", string(v2beta2.UtilizationMetricType):
+		utilization, err := strconv.Atoi(val)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing value: %s", err)
+		}
+		if utilization <= 0 {
+			return nil, fmt.Errorf("value must be greater than 0")
+		}
+		averageUtilization := int32(utilization)
+		meta.targetType = v2beta2.UtilizationMetricType
+		meta.averageUtilization = &averageUtilization
+	case string(v2beta2.AverageValueMetricType):
+		averageValue, err := resource.ParseQuantity(val)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing value: %s", err)
+		}
+		meta.targetType = v2beta2.AverageValueMetricType
+		meta.averageValue = &averageValue
+	default:
+		return nil, fmt.Errorf("type %s not supported, supported types are Utilization and AverageValue", metadata["type"])
+	}
+
+	return &meta, nil
+}
+
+// IsActive returns true as the resource metrics can't tell if the ScaleTarget can be scaled to zero
+func (s *cpuMemoryScaler) IsActive(ctx context.Context) (bool, error) {
+	return true, nil
+}
+
+// Close does nothing in case of cpuMemoryScaler
+func (s *cpuMemoryScaler) Close() error {
+	return nil
+}
+
+// GetMetricSpecForScaling returns the Resource metric spec for the HPA
+func (s *cpuMemoryScaler) GetMetricSpecForScaling() []v2beta2.MetricSpec {
+	resourceMetric := &v2beta2.ResourceMetricSource{
+		Name: s.resourceName,
+		Target: v2beta2.MetricTarget{
+			Type:               s.metadata.targetType,
+			AverageValue:       s.metadata.averageValue,
+			AverageUtilization: s.metadata.averageUtilization,
+		},
+	}
+	metricSpec := v2beta2.MetricSpec{Resource: resourceMetric, Type: v2beta2.ResourceMetricSourceType}
+	return []v2beta2.MetricSpec{metricSpec}
+}
+
+// GetMetrics returns no external metric, the HPA gets the resource metrics from the Kubernetes metrics server
+func (s *cpuMemoryScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
+	return []external_metrics.ExternalMetricValue{}, nil
+}
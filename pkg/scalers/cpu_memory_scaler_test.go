@@ -0,0 +1,66 @@
+package scalers
+
+import (
+	"testing"
+
+	v2beta2 "k8s.io/api/autoscaling/v2beta2"
+	corev1 "k8s.io/api/core/v1"
+)
+
+type parseCPUMemoryMetadataTestData struct {
+	metadata map[string]string
+	isError  bool
+}
+
+var testCPUMemoryMetadata = []parseCPUMemoryMetadataTestData{
+	// nothing passed
+	{map[string]string{}, true},
+	// utilization by default
+	{map[string]string{"value": "50"}, false},
+	// utilization
+	{map[string]string{"type": "Utilization", "value": "75"}, false},
+	// average value
+	{map[string]string{"type": "AverageValue", "value": "512Mi"}, false},
+	// unsupported type
+	{map[string]string{"type": "Value", "value": "50"}, true},
+	// malformed utilization
+	{map[string]string{"type": "Utilization", "value": "50%"}, true},
+	// utilization is zero
+	{map[string]string{"value": "0"}, true},
+	// malformed average value
+	{map[string]string{"type": "AverageValue", "value": "a lot"}, true},
+}
+
+func TestCPUMemoryParseMetadata(t *testing.T) {
+	for _, testData := range testCPUMemoryMetadata {
+		_, err := parseCPUMemoryMetadata(testData.metadata)
+		if err != nil && !testData.isError {
+			t.Error("Expected success but got error", err)
+		}
+		if testData.isError && err == nil {
+			t.Errorf("Expected error but got success for %v", testData.metadata)
+		}
+	}
+}
+
+func TestCPUMemoryGetMetricSpecForScaling(t *testing.T) {
+	scaler, err := NewCPUMemoryScaler(corev1.ResourceCPU, map[string]string{"type": "Utilization", "value": "50"})
+	if err != nil {
+		t.Fatal("Could not create scaler:", err)
+	}
+	metricSpec := scaler.GetMetricSpecForScaling()[0]
+	if metricSpec.Type != v2beta2.ResourceMetricSourceType || metricSpec.External != nil || metricSpec.Resource.Name != corev1.ResourceCPU ||
+		metricSpec.Resource.Target.Type != v2beta2.UtilizationMetricType || *metricSpec.Resource.Target.AverageUtilization != 50 {
+		t.Errorf("Expected a cpu Resource metric with a 50%% utilization target, got %+v", metricSpec)
+	}
+
+	scaler, err = NewCPUMemoryScaler(corev1.ResourceMemory, map[string]string{"type": "AverageValue", "value": "512Mi"})
+	if err != nil {
+		t.Fatal("Could not create scaler:", err)
+	}
+	metricSpec = scaler.GetMetricSpecForScaling()[0]
+	if metricSpec.Resource.Name != corev1.ResourceMemory || metricSpec.Resource.Target.Type != v2beta2.AverageValueMetricType ||
+		metricSpec.Resource.Target.AverageValue.String() != "512Mi" || metricSpec.Resource.Target.AverageUtilization != nil {
+		t.Errorf("Expected a memory Resource metric with a 512Mi average value target, got %+v", metricSpec)
+	}
+}
@@ -28,6 +28,12 @@ type cronScaler struct {
 }
 
 type cronMetadata struct {
+	timezone string
+	windows  []cronWindow
+}
+
+// cronWindow is a single start/end schedule with the replicas desired while it is active
+type cronWindow struct {
 	start           string
 	end             string
 	timezone        string
@@ -69,47 +75,143 @@ func parseCronMetadata(metadata map[string]string) (*cronMetadata, error) {
 
 	meta := cronMetadata{}
 	if val, ok := metadata["timezone"]; ok && val != "" {
+		if _, err := time.LoadLocation(val); err != nil {
+			return nil, fmt.Errorf("Unable to load timezone %s. Error: %s", val, err)
+		}
 		meta.timezone = val
 	} else {
 		return nil, fmt.Errorf("No timezone specified. %s", metadata)
 	}
-	if val, ok := metadata["start"]; ok && val != "" {
-		meta.start = val
-	} else {
-		return nil, fmt.Errorf("No start schedule specified. %s", metadata)
+
+	_, hasStart := metadata["start"]
+	_, hasEnd := metadata["end"]
+	windows, hasWindows := metadata["windows"]
+	if hasStart || hasEnd || !hasWindows {
+		window := cronWindow{timezone: meta.timezone}
+		if val, ok := metadata["start"]; ok && val != "" {
+			window.start = val
+		} else {
+			return nil, fmt.Errorf("No start schedule specified. %s", metadata)
+		}
+		if val, ok := metadata["end"]; ok && val != "" {
+			window.end = val
+		} else {
+			return nil, fmt.Errorf("No end schedule specified. %s", metadata)
+		}
+		if val, ok := metadata["desiredReplicas"]; ok && val != "" {
+			metadataDesiredReplicas, err := strconv.Atoi(val)
+			if err != nil {
+				return nil, fmt.Errorf("Error parsing desiredReplicas metadata. %s", metadata)
+			}
+
+			window.desiredReplicas = int64(metadataDesiredReplicas)
+		} else {
+			return nil, fmt.Errorf("No DesiredReplicas specified. %s", metadata)
+		}
+		meta.windows = append(meta.windows, window)
 	}
-	if val, ok := metadata["end"]; ok && val != "" {
-		meta.end = val
-	} else {
-		return nil, fmt.Errorf("No end schedule specified. %s", metadata)
+
+	if hasWindows {
+		parsed, err := parseCronWindows(windows, meta.timezone)
+		if err != nil {
+			return nil, err
+		}
+		meta.windows = append(meta.windows, parsed...)
 	}
-	if val, ok := metadata["desiredReplicas"]; ok && val != "" {
-		metadataDesiredReplicas, err := strconv.Atoi(val)
+
+	return &meta, nil
+}
+
+// parseCronWindows parses windows in the format "start|end|desiredReplicas[|timezone];..."
+// the timezone of the trigger is used for the windows that don't specify their own
+func parseCronWindows(windows, timezone string) ([]cronWindow, error) {
+	var result []cronWindow
+	for _, value := range strings.Split(windows, ";") {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+
+		fields := strings.Split(value, "|")
+		if len(fields) != 3 && len(fields) != 4 {
+			return nil, fmt.Errorf("Invalid window %s, expected start|end|desiredReplicas[|timezone]", value)
+		}
+
+		window := cronWindow{
+			start:    strings.TrimSpace(fields[0]),
+			end:      strings.TrimSpace(fields[1]),
+			timezone: timezone,
+		}
+		if window.start == "" || window.end == "" {
+			return nil, fmt.Errorf("No start or end schedule specified in window %s", value)
+		}
+
+		desiredReplicas, err := strconv.Atoi(strings.TrimSpace(fields[2]))
 		if err != nil {
-			return nil, fmt.Errorf("Error parsing desiredReplicas metadata. %s", metadata)
+			return nil, fmt.Errorf("Error parsing desiredReplicas in window %s", value)
 		}
+		window.desiredReplicas = int64(desiredReplicas)
 
-		meta.desiredReplicas = int64(metadataDesiredReplicas)
-	} else {
-		return nil, fmt.Errorf("No DesiredReplicas specified. %s", metadata)
+		if len(fields) == 4 && strings.TrimSpace(fields[3]) != "" {
+			window.timezone = strings.TrimSpace(fields[3])
+			if _, err := time.LoadLocation(window.timezone); err != nil {
+				return nil, fmt.Errorf("Unable to load timezone %s of window %s. Error: %s", window.timezone, value, err)
+			}
+		}
+
+		result = append(result, window)
 	}
 
-	return &meta, nil
+	if len(result) == 0 {
+		return nil, fmt.Errorf("No windows specified. %s", windows)
+	}
+	return result, nil
 }
 
-// IsActive checks if the startTime or endTime has reached
+// IsActive checks if the startTime or endTime has reached for any of the windows
 func (s *cronScaler) IsActive(ctx context.Context) (bool, error) {
-	location, err := time.LoadLocation(s.metadata.timezone)
+	for _, window := range s.metadata.windows {
+		isActive, err := window.isActive()
+		if err != nil {
+			return false, err
+		}
+		if isActive {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// getDesiredReplicas returns the highest desiredReplicas of the active windows, or defaultDesiredReplicas if none is active
+func (s *cronScaler) getDesiredReplicas() (int64, error) {
+	desiredReplicas := int64(-1)
+	for _, window := range s.metadata.windows {
+		isActive, err := window.isActive()
+		if err != nil {
+			return 0, err
+		}
+		if isActive && window.desiredReplicas > desiredReplicas {
+			desiredReplicas = window.desiredReplicas
+		}
+	}
+	if desiredReplicas < 0 {
+		return defaultDesiredReplicas, nil
+	}
+	return desiredReplicas, nil
+}
+
+func (w *cronWindow) isActive() (bool, error) {
+	location, err := time.LoadLocation(w.timezone)
 	if err != nil {
 		return false, fmt.Errorf("Unable to load timezone. Error: %s", err)
 	}
 
-	nextStartTime, startTimecronErr := getCronTime(location, s.metadata.start)
+	nextStartTime, startTimecronErr := getCronTime(location, w.start)
 	if startTimecronErr != nil {
 		return false, fmt.Errorf("error initializing start cron: %s", startTimecronErr)
 	}
 
-	nextEndTime, endTimecronErr := getCronTime(location, s.metadata.end)
+	nextEndTime, endTimecronErr := getCronTime(location, w.end)
 	if endTimecronErr != nil {
 		return false, fmt.Errorf("error intializing end cron: %s", endTimecronErr)
 	}
@@ -137,13 +239,24 @@ func parseCronTimeFormat(s string) string {
 	return s
 }
 
+func (s *cronScaler) getMetricName() string {
+	name := fmt.Sprintf("%s-%s", "cron", s.metadata.timezone)
+	for _, window := range s.metadata.windows {
+		name = fmt.Sprintf("%s-%s-%s", name, parseCronTimeFormat(window.start), parseCronTimeFormat(window.end))
+		if window.timezone != s.metadata.timezone {
+			name = fmt.Sprintf("%s-%s", name, window.timezone)
+		}
+	}
+	return name
+}
+
 // GetMetricSpecForScaling returns the metric spec for the HPA
 func (s *cronScaler) GetMetricSpecForScaling() []v2beta2.MetricSpec {
 	specReplicas := 1
 	targetMetricValue := resource.NewQuantity(int64(specReplicas), resource.DecimalSI)
 	externalMetric := &v2beta2.ExternalMetricSource{
 		Metric: v2beta2.MetricIdentifier{
-			Name: kedautil.NormalizeString(fmt.Sprintf("%s-%s-%s-%s", "cron", s.metadata.timezone, parseCronTimeFormat(s.metadata.start), parseCronTimeFormat(s.metadata.end))),
+			Name: kedautil.NormalizeString(s.getMetricName()),
 		},
 		Target: v2beta2.MetricTarget{
 			Type:         v2beta2.AverageValueMetricType,
@@ -156,15 +269,11 @@ func (s *cronScaler) GetMetricSpecForScaling() []v2beta2.MetricSpec {
 
 // GetMetrics finds the current value of the metric
 func (s *cronScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
-	var currentReplicas = int64(defaultDesiredReplicas)
-	isActive, err := s.IsActive(ctx)
+	currentReplicas, err := s.getDesiredReplicas()
 	if err != nil {
 		cronLog.Error(err, "error")
 		return []external_metrics.ExternalMetricValue{}, err
 	}
-	if isActive {
-		currentReplicas = s.metadata.desiredReplicas
-	}
 
 	/*******************************************************************************/
 	metric := external_metrics.ExternalMetricValue{
@@ -31,10 +31,27 @@ var testCronMetadata = []parseCronMetadataTestData{
 	{validCronMetadata, false},
 	{map[string]string{"timezone": "Asia/Kolkata", "start": "30 * * * *", "end": "45 * * * *"}, true},
 	{map[string]string{"start": "30 * * * *", "end": "45 * * * *", "desiredReplicas": "10"}, true},
+	// multiple windows
+	{map[string]string{"timezone": "Etc/UTC", "windows": "0 8 * * Mon-Fri|0 18 * * Mon-Fri|5; 0 10 * * Sat|0 14 * * Sat|2|Europe/Prague"}, false},
+	// window missing desiredReplicas
+	{map[string]string{"timezone": "Etc/UTC", "windows": "0 8 * * *|0 18 * * *"}, true},
+	// window with unknown timezone
+	{map[string]string{"timezone": "Etc/UTC", "windows": "0 8 * * *|0 18 * * *|3|Mars/Olympus"}, true},
+	// unknown timezone
+	{map[string]string{"timezone": "Mars/Olympus", "start": "30 * * * *", "end": "45 * * * *", "desiredReplicas": "10"}, true},
+	// empty windows
+	{map[string]string{"timezone": "Etc/UTC", "windows": " ; "}, true},
 }
 
 var cronMetricIdentifiers = []cronMetricIdentifier{
 	{&testCronMetadata[1], "cron-Etc-UTC-00xxThu-5923xxThu"},
+	{&testCronMetadata[4], "cron-Etc-UTC-08xxMon-Fri-018xxMon-Fri-010xxSat-014xxSat-Europe-Prague"},
+}
+
+// A window active all the time and one active only on Thursdays
+var validCronWindowsMetadata = map[string]string{
+	"timezone": "Etc/UTC",
+	"windows":  "* * * * *|* * * * *|3; 0 0 * * Thu|59 23 * * Thu|10",
 }
 
 var tz, _ = time.LoadLocation(validCronMetadata["timezone"])
@@ -73,6 +90,19 @@ func TestGetMetrics(t *testing.T) {
 	}
 }
 
+func TestGetMetricsWithWindows(t *testing.T) {
+	scaler, _ := NewCronScaler(map[string]string{}, validCronWindowsMetadata)
+	isActive, _ := scaler.IsActive(context.TODO())
+	assert.Equal(t, isActive, true)
+
+	metrics, _ := scaler.GetMetrics(context.TODO(), "ReplicaCount", nil)
+	if currentDay == "Thursday" {
+		assert.Equal(t, metrics[0].Value.Value(), int64(10))
+	} else {
+		assert.Equal(t, metrics[0].Value.Value(), int64(3))
+	}
+}
+
 func TestCronGetMetricSpecForScaling(t *testing.T) {
 	for _, testData := range cronMetricIdentifiers {
 		meta, err := parseCronMetadata(testData.metadataTestData.metadata)
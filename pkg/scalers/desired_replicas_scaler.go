@@ -0,0 +1,78 @@
+package scalers
+
+import (
+	"context"
+	"fmt"
+
+	v2beta2 "k8s.io/api/autoscaling/v2beta2"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/metrics/pkg/apis/external_metrics"
+)
+
+// ReplicasStep maps the metric values from From, in thousandths, to a replica count
+type ReplicasStep struct {
+	FromMilli int64
+	Replicas  int64
+}
+
+// desiredReplicasScaler reports the replica count mapped from the metric value of the wrapped scaler, with a target
+// average value of 1 the HPA scales to that replica count, e.g. 0-100 messages give 2 pods and 100-1000 messages 5 pods
+type desiredReplicasScaler struct {
+	Scaler
+	steps []ReplicasStep
+}
+
+// NewDesiredReplicasScaler wraps the scaler so it reports the replicas of the step of its metric value, the steps must be sorted by From
+func NewDesiredReplicasScaler(scaler Scaler, steps []ReplicasStep) (Scaler, error) {
+	if _, ok := scaler.(PushScaler); ok {
+		return nil, fmt.Errorf("desiredReplicas isn't supported by the push scalers")
+	}
+	for i, step := range steps {
+		if step.Replicas < 0 {
+			return nil, fmt.Errorf("the replicas of the step #%d of desiredReplicas must not be negative", i)
+		}
+		if i > 0 && step.FromMilli <= steps[i-1].FromMilli {
+			return nil, fmt.Errorf("the steps of desiredReplicas must be sorted by increasing from, step #%d isn't", i)
+		}
+	}
+	return &desiredReplicasScaler{Scaler: scaler, steps: steps}, nil
+}
+
+// GetMetricSpecForScaling returns the metric specs of the wrapped scaler with a target average value of 1
+func (s *desiredReplicasScaler) GetMetricSpecForScaling() []v2beta2.MetricSpec {
+	metricSpecs := s.Scaler.GetMetricSpecForScaling()
+	for _, metricSpec := range metricSpecs {
+		if metricSpec.External == nil {
+			continue
+		}
+		metricSpec.External.Target = v2beta2.MetricTarget{
+			Type:         v2beta2.AverageValueMetricType,
+			AverageValue: resource.NewQuantity(1, resource.DecimalSI),
+		}
+	}
+	return metricSpecs
+}
+
+// GetMetrics returns the replicas of the step of each metric value of the wrapped scaler
+func (s *desiredReplicasScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
+	metrics, err := s.Scaler.GetMetrics(ctx, metricName, metricSelector)
+	if err != nil {
+		return metrics, err
+	}
+	for i, metric := range metrics {
+		metrics[i].Value = *resource.NewQuantity(s.replicasFor(metric.Value.MilliValue()), resource.DecimalSI)
+	}
+	return metrics, nil
+}
+
+func (s *desiredReplicasScaler) replicasFor(valueMilli int64) int64 {
+	var replicas int64
+	for _, step := range s.steps {
+		if valueMilli < step.FromMilli {
+			break
+		}
+		replicas = step.Replicas
+	}
+	return replicas
+}
@@ -0,0 +1,57 @@
+package scalers
+
+import (
+	"context"
+	"testing"
+
+	v2beta2 "k8s.io/api/autoscaling/v2beta2"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+type metricSpecScaler struct {
+	fixedValueScaler
+}
+
+func (s *metricSpecScaler) GetMetricSpecForScaling() []v2beta2.MetricSpec {
+	externalMetric := &v2beta2.ExternalMetricSource{
+		Metric: v2beta2.MetricIdentifier{Name: "queue"},
+		Target: v2beta2.MetricTarget{Type: v2beta2.AverageValueMetricType, AverageValue: resource.NewQuantity(100, resource.DecimalSI)},
+	}
+	return []v2beta2.MetricSpec{{External: externalMetric, Type: externalMetricType}}
+}
+
+func TestDesiredReplicasScaler(t *testing.T) {
+	steps := []ReplicasStep{{FromMilli: 0, Replicas: 2}, {FromMilli: 100000, Replicas: 5}, {FromMilli: 1000000, Replicas: 10}}
+	scaler, err := NewDesiredReplicasScaler(&metricSpecScaler{fixedValueScaler{values: []int64{0, 99, 100, 999, 5000}}}, steps)
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+
+	target := scaler.GetMetricSpecForScaling()[0].External.Target
+	if target.Type != v2beta2.AverageValueMetricType || target.AverageValue.Value() != 1 {
+		t.Errorf("Expected a target average value of 1, got %+v", target)
+	}
+
+	metrics, err := scaler.GetMetrics(context.TODO(), "queue", nil)
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	expected := []int64{2, 2, 5, 5, 10}
+	for i, metric := range metrics {
+		if metric.Value.Value() != expected[i] {
+			t.Errorf("Expected %d replicas for metric #%d, got %d", expected[i], i, metric.Value.Value())
+		}
+	}
+
+	below, _ := NewDesiredReplicasScaler(&metricSpecScaler{fixedValueScaler{values: []int64{5}}}, []ReplicasStep{{FromMilli: 10000, Replicas: 3}})
+	if metrics, _ := below.GetMetrics(context.TODO(), "queue", nil); metrics[0].Value.Value() != 0 {
+		t.Errorf("Expected 0 replicas below the first step, got %d", metrics[0].Value.Value())
+	}
+
+	if _, err := NewDesiredReplicasScaler(&fixedValueScaler{}, []ReplicasStep{{FromMilli: 100, Replicas: 2}, {FromMilli: 100, Replicas: 5}}); err == nil {
+		t.Error("Expected an error for unsorted steps")
+	}
+	if _, err := NewDesiredReplicasScaler(&fixedValueScaler{}, []ReplicasStep{{FromMilli: 0, Replicas: -1}}); err == nil {
+		t.Error("Expected an error for negative replicas")
+	}
+}
@@ -0,0 +1,38 @@
+package scalers
+
+import (
+	"context"
+
+	v2beta2 "k8s.io/api/autoscaling/v2beta2"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/metrics/pkg/apis/external_metrics"
+)
+
+// disabledScaler stands in for a trigger disabled in the spec, so the scalers keep the indexes of the triggers.
+// It is never active and has no metric, the HPA doesn't get a metric for the trigger.
+type disabledScaler struct{}
+
+// NewDisabledScaler creates the scaler of a disabled trigger
+func NewDisabledScaler() Scaler {
+	return &disabledScaler{}
+}
+
+// IsActive returns false as a disabled trigger doesn't activate the ScaleTarget
+func (s *disabledScaler) IsActive(ctx context.Context) (bool, error) {
+	return false, nil
+}
+
+// GetMetricSpecForScaling returns no metric spec for the HPA
+func (s *disabledScaler) GetMetricSpecForScaling() []v2beta2.MetricSpec {
+	return []v2beta2.MetricSpec{}
+}
+
+// GetMetrics returns no metric value
+func (s *disabledScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
+	return []external_metrics.ExternalMetricValue{}, nil
+}
+
+// Close does nothing in case of disabledScaler
+func (s *disabledScaler) Close() error {
+	return nil
+}
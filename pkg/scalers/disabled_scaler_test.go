@@ -0,0 +1,21 @@
+package scalers
+
+import (
+	"context"
+	"testing"
+)
+
+func TestDisabledScaler(t *testing.T) {
+	scaler := NewDisabledScaler()
+	isActive, err := scaler.IsActive(context.TODO())
+	if err != nil || isActive {
+		t.Errorf("Expected a disabled scaler to be inactive, got %v, %v", isActive, err)
+	}
+	if metricSpecs := scaler.GetMetricSpecForScaling(); len(metricSpecs) != 0 {
+		t.Error("Expected no metric spec, got", metricSpecs)
+	}
+	metrics, err := scaler.GetMetrics(context.TODO(), "metric", nil)
+	if err != nil || len(metrics) != 0 {
+		t.Errorf("Expected no metric, got %v, %v", metrics, err)
+	}
+}
@@ -0,0 +1,220 @@
+package scalers
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	v2beta2 "k8s.io/api/autoscaling/v2beta2"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/metrics/pkg/apis/external_metrics"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	kedautil "github.com/kedacore/keda/pkg/util"
+)
+
+const (
+	envoyStatsPath           = "/stats"
+	defaultEnvoyStat         = "upstream_rq_active"
+	defaultEnvoyTargetActive = 100
+	envoyRequestTimeout      = 3 * time.Second
+)
+
+// envoySupportedStats are the cluster gauges of the in-flight requests and connections
+var envoySupportedStats = map[string]bool{
+	"upstream_rq_active":         true,
+	"upstream_rq_pending_active": true,
+	"upstream_cx_active":         true,
+}
+
+type envoyScaler struct {
+	metadata   *envoyMetadata
+	httpClient *http.Client
+}
+
+type envoyMetadata struct {
+	adminURLs   []string
+	clusterName string
+	stat        string
+	targetValue int
+	retryPolicy kedautil.RetryPolicy
+}
+
+var envoyLog = logf.Log.WithName("envoy_scaler")
+
+// NewEnvoyScaler creates a new scaler for the in-flight requests of an upstream cluster read from the Envoy admin stats
+func NewEnvoyScaler(resolvedEnv, metadata map[string]string) (Scaler, error) {
+	meta, err := parseEnvoyMetadata(resolvedEnv, metadata)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing envoy metadata: %s", err)
+	}
+
+	return &envoyScaler{
+		metadata:   meta,
+		httpClient: &http.Client{Timeout: envoyRequestTimeout},
+	}, nil
+}
+
+func parseEnvoyMetadata(resolvedEnv, metadata map[string]string) (*envoyMetadata, error) {
+	meta := envoyMetadata{
+		stat:        defaultEnvoyStat,
+		targetValue: defaultEnvoyTargetActive,
+	}
+
+	adminURLs := metadata["adminURL"]
+	if adminURLs == "" && metadata["adminURLFromEnv"] != "" {
+		adminURLs = resolvedEnv[metadata["adminURLFromEnv"]]
+	}
+	for _, adminURL := range strings.Split(adminURLs, ",") {
+		if adminURL = strings.TrimSpace(adminURL); adminURL != "" {
+			meta.adminURLs = append(meta.adminURLs, strings.TrimSuffix(adminURL, "/"))
+		}
+	}
+	if len(meta.adminURLs) == 0 {
+		return nil, fmt.Errorf("no adminURL given")
+	}
+
+	if val, ok := metadata["clusterName"]; ok && val != "" {
+		meta.clusterName = val
+	} else {
+		return nil, fmt.Errorf("no clusterName given")
+	}
+
+	if val, ok := metadata["stat"]; ok && val != "" {
+		if !envoySupportedStats[val] {
+			return nil, fmt.Errorf("stat %s not supported, supported stats are upstream_rq_active, upstream_rq_pending_active and upstream_cx_active", val)
+		}
+		meta.stat = val
+	}
+
+	if val, ok := metadata["targetValue"]; ok && val != "" {
+		targetValue, err := strconv.Atoi(val)
+		if err != nil {
+			return nil, fmt.Errorf("targetValue parsing error %s", err.Error())
+		}
+		meta.targetValue = targetValue
+	}
+
+	retryPolicy, err := parseHTTPRetryPolicy(metadata)
+	if err != nil {
+		return nil, err
+	}
+	meta.retryPolicy = retryPolicy
+
+	return &meta, nil
+}
+
+// statName returns the full name of the gauge, e.g. cluster.outbound|8080||api.default.svc.cluster.local.upstream_rq_active
+func (s *envoyScaler) statName() string {
+	return fmt.Sprintf("cluster.%s.%s", s.metadata.clusterName, s.metadata.stat)
+}
+
+// getValue sums the gauge of the cluster over all the admin endpoints
+func (s *envoyScaler) getValue(ctx context.Context) (int64, error) {
+	var sum int64
+	for _, adminURL := range s.metadata.adminURLs {
+		value, err := s.getAdminValue(ctx, adminURL)
+		if err != nil {
+			return 0, err
+		}
+		sum += value
+	}
+	return sum, nil
+}
+
+func (s *envoyScaler) getAdminValue(ctx context.Context, adminURL string) (int64, error) {
+	statName := s.statName()
+	query := url.Values{}
+	query.Set("filter", "^"+regexp.QuoteMeta(statName)+"$")
+
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s%s?%s", adminURL, envoyStatsPath, query.Encode()), nil)
+	if err != nil {
+		return 0, err
+	}
+	req = req.WithContext(ctx)
+
+	resp, err := kedautil.DoWithRetry(s.httpClient, req, s.metadata.retryPolicy)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("envoy admin %s returned %d", adminURL, resp.StatusCode)
+	}
+
+	// the stats are in the text format, a name: value line per stat
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		parts := strings.SplitN(scanner.Text(), ": ", 2)
+		if len(parts) != 2 || parts[0] != statName {
+			continue
+		}
+		value, err := strconv.ParseInt(strings.TrimSpace(parts[1]), 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("error parsing %s: %s", statName, err)
+		}
+		return value, nil
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, fmt.Errorf("error reading envoy stats: %s", err)
+	}
+	return 0, fmt.Errorf("stat %s not found on envoy admin %s", statName, adminURL)
+}
+
+// IsActive returns true if there are requests in flight
+func (s *envoyScaler) IsActive(ctx context.Context) (bool, error) {
+	value, err := s.getValue(ctx)
+	if err != nil {
+		envoyLog.Error(err, "error getting envoy stats", "clusterName", s.metadata.clusterName)
+		return false, err
+	}
+
+	return value > 0, nil
+}
+
+// GetMetricSpecForScaling returns the MetricSpec for the Horizontal Pod Autoscaler
+func (s *envoyScaler) GetMetricSpecForScaling() []v2beta2.MetricSpec {
+	targetValue := resource.NewQuantity(int64(s.metadata.targetValue), resource.DecimalSI)
+	externalMetric := &v2beta2.ExternalMetricSource{
+		Metric: v2beta2.MetricIdentifier{
+			// the istio cluster names like outbound|8080||api.default.svc.cluster.local hold pipes
+			Name: kedautil.NormalizeString(fmt.Sprintf("%s-%s-%s", "envoy", strings.ReplaceAll(s.metadata.clusterName, "|", "-"), s.metadata.stat)),
+		},
+		Target: v2beta2.MetricTarget{
+			Type:         v2beta2.AverageValueMetricType,
+			AverageValue: targetValue,
+		},
+	}
+	metricSpec := v2beta2.MetricSpec{External: externalMetric, Type: externalMetricType}
+	return []v2beta2.MetricSpec{metricSpec}
+}
+
+// GetMetrics returns value for a supported metric and an error if there is a problem getting the metric
+func (s *envoyScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
+	value, err := s.getValue(ctx)
+	if err != nil {
+		return []external_metrics.ExternalMetricValue{}, fmt.Errorf("error getting envoy stats: %s", err)
+	}
+
+	metric := external_metrics.ExternalMetricValue{
+		MetricName: metricName,
+		Value:      *resource.NewQuantity(value, resource.DecimalSI),
+		Timestamp:  metav1.Now(),
+	}
+
+	return append([]external_metrics.ExternalMetricValue{}, metric), nil
+}
+
+// Close does nothing in case of envoyScaler
+func (s *envoyScaler) Close() error {
+	return nil
+}
@@ -0,0 +1,109 @@
+package scalers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type parseEnvoyMetadataTestData struct {
+	metadata map[string]string
+	isError  bool
+}
+
+type envoyMetricIdentifier struct {
+	metadataTestData *parseEnvoyMetadataTestData
+	name             string
+}
+
+var testEnvoyResolvedEnv = map[string]string{
+	"ENVOY_ADMIN": "http://localhost:15000",
+}
+
+var testEnvoyMetadata = []parseEnvoyMetadataTestData{
+	// nothing passed
+	{map[string]string{}, true},
+	// properly formed
+	{map[string]string{"adminURL": "http://envoy:9901", "clusterName": "api", "targetValue": "20"}, false},
+	// istio cluster with admin url from env and pending requests
+	{map[string]string{"adminURLFromEnv": "ENVOY_ADMIN", "clusterName": "outbound|8080||api.default.svc.cluster.local", "stat": "upstream_rq_pending_active"}, false},
+	// multiple admin urls
+	{map[string]string{"adminURL": "http://envoy-0:9901, http://envoy-1:9901", "clusterName": "api"}, false},
+	// missing adminURL
+	{map[string]string{"clusterName": "api"}, true},
+	// missing clusterName
+	{map[string]string{"adminURL": "http://envoy:9901"}, true},
+	// unsupported stat
+	{map[string]string{"adminURL": "http://envoy:9901", "clusterName": "api", "stat": "upstream_rq_total"}, true},
+	// malformed targetValue
+	{map[string]string{"adminURL": "http://envoy:9901", "clusterName": "api", "targetValue": "lots"}, true},
+}
+
+var envoyMetricIdentifiers = []envoyMetricIdentifier{
+	{&testEnvoyMetadata[1], "envoy-api-upstream_rq_active"},
+	{&testEnvoyMetadata[2], "envoy-outbound-8080--api-default-svc-cluster-local-upstream_rq_pending_active"},
+}
+
+func TestEnvoyParseMetadata(t *testing.T) {
+	for _, testData := range testEnvoyMetadata {
+		_, err := parseEnvoyMetadata(testEnvoyResolvedEnv, testData.metadata)
+		if err != nil && !testData.isError {
+			t.Error("Expected success but got error", err)
+		}
+		if testData.isError && err == nil {
+			t.Errorf("Expected error but got success for %v", testData.metadata)
+		}
+	}
+}
+
+func TestEnvoyGetMetricSpecForScaling(t *testing.T) {
+	for _, testData := range envoyMetricIdentifiers {
+		meta, err := parseEnvoyMetadata(testEnvoyResolvedEnv, testData.metadataTestData.metadata)
+		if err != nil {
+			t.Fatal("Could not parse metadata:", err)
+		}
+		mockEnvoyScaler := envoyScaler{meta, http.DefaultClient}
+
+		metricSpec := mockEnvoyScaler.GetMetricSpecForScaling()
+		metricName := metricSpec[0].External.Metric.Name
+		if metricName != testData.name {
+			t.Error("Wrong External metric source name:", metricName)
+		}
+	}
+}
+
+func TestEnvoyGetValue(t *testing.T) {
+	stats := map[string]string{
+		"/0": "cluster.outbound|8080||api.default.svc.cluster.local.upstream_rq_active: 12\n",
+		"/1": "cluster.outbound|8080||api.default.svc.cluster.local.upstream_rq_active: 5\n",
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		filter := r.URL.Query().Get("filter")
+		matcher, err := regexp.Compile(filter)
+		assert.NoError(t, err)
+		for path, stat := range stats {
+			if r.URL.Path == path+"/stats" && matcher.MatchString("cluster.outbound|8080||api.default.svc.cluster.local.upstream_rq_active") {
+				w.Write([]byte(stat))
+			}
+		}
+	}))
+	defer server.Close()
+
+	meta, err := parseEnvoyMetadata(nil, map[string]string{"adminURL": server.URL + "/0," + server.URL + "/1/", "clusterName": "outbound|8080||api.default.svc.cluster.local"})
+	if err != nil {
+		t.Fatal("Could not parse metadata:", err)
+	}
+	scaler := envoyScaler{meta, http.DefaultClient}
+
+	value, err := scaler.getValue(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(17), value)
+
+	meta.clusterName = "missing"
+	_, err = scaler.GetMetrics(context.TODO(), "envoy-missing-upstream_rq_active", nil)
+	assert.Error(t, err)
+}
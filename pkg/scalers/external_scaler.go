@@ -2,13 +2,16 @@ package scalers
 
 import (
 	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
 	"fmt"
+	"io/ioutil"
 	"strings"
 	"sync"
 	"time"
 
-	"github.com/mitchellh/hashstructure"
-
 	pb "github.com/kedacore/keda/pkg/scalers/externalscaler"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
@@ -23,6 +26,12 @@ import (
 type externalScaler struct {
 	metadata        externalScalerMetadata
 	scaledObjectRef pb.ScaledObjectRef
+	// the state of the connection after the last call, reported in the ScaledObject status
+	connectionState   string
+	connectionMessage string
+	// the pooled connection of the scaler, taken on the first call and released by Close()
+	grpcConnection *grpc.ClientConn
+	done           func()
 }
 
 type externalPushScaler struct {
@@ -32,12 +41,24 @@ type externalPushScaler struct {
 type externalScalerMetadata struct {
 	scalerAddress    string
 	tlsCertFile      string
+	caCert           string
+	clientCert       string
+	clientKey        string
+	serverName       string
 	originalMetadata map[string]string
+	authParams       map[string]string
+	connectionPolicy grpcConnectionPolicy
 }
 
+// connectionIdleTimeout is how long a connection no scaler uses anymore is kept in the pool, the scalers are built
+// again on each poll so the connection is reused by the next polls instead of being dialed again
+const connectionIdleTimeout = 5 * time.Minute
+
 type connectionGroup struct {
 	grpcConnection *grpc.ClientConn
-	waitGroup      *sync.WaitGroup
+	// users is the number of scalers using the connection, it is closed once it has no user for connectionIdleTimeout
+	users     int
+	idleTimer *time.Timer
 }
 
 // a pool of connectionGroup per metadata hash
@@ -47,8 +68,8 @@ var externalLog = logf.Log.WithName("external_scaler")
 
 // NewExternalScaler creates a new external scaler - calls the GRPC interface
 // to create a new scaler
-func NewExternalScaler(name, namespace string, metadata, resolvedEnv map[string]string) (Scaler, error) {
-	meta, err := parseExternalScalerMetadata(metadata, resolvedEnv)
+func NewExternalScaler(name, namespace string, metadata, resolvedEnv, authParams map[string]string) (Scaler, error) {
+	meta, err := parseExternalScalerMetadata(metadata, resolvedEnv, authParams)
 	if err != nil {
 		return nil, fmt.Errorf("error parsing external scaler metadata: %s", err)
 	}
@@ -59,13 +80,14 @@ func NewExternalScaler(name, namespace string, metadata, resolvedEnv map[string]
 			Name:           name,
 			Namespace:      namespace,
 			ScalerMetadata: meta.originalMetadata,
+			AuthParams:     meta.authParams,
 		},
 	}, nil
 }
 
 // NewExternalPushScaler creates a new externalPushScaler push scaler
-func NewExternalPushScaler(name, namespace string, metadata, resolvedEnv map[string]string) (PushScaler, error) {
-	meta, err := parseExternalScalerMetadata(metadata, resolvedEnv)
+func NewExternalPushScaler(name, namespace string, metadata, resolvedEnv, authParams map[string]string) (PushScaler, error) {
+	meta, err := parseExternalScalerMetadata(metadata, resolvedEnv, authParams)
 	if err != nil {
 		return nil, fmt.Errorf("error parsing external scaler metadata: %s", err)
 	}
@@ -77,12 +99,13 @@ func NewExternalPushScaler(name, namespace string, metadata, resolvedEnv map[str
 				Name:           name,
 				Namespace:      namespace,
 				ScalerMetadata: meta.originalMetadata,
+				AuthParams:     meta.authParams,
 			},
 		},
 	}, nil
 }
 
-func parseExternalScalerMetadata(metadata, resolvedEnv map[string]string) (externalScalerMetadata, error) {
+func parseExternalScalerMetadata(metadata, resolvedEnv, authParams map[string]string) (externalScalerMetadata, error) {
 	meta := externalScalerMetadata{
 		originalMetadata: metadata,
 	}
@@ -98,6 +121,24 @@ func parseExternalScalerMetadata(metadata, resolvedEnv map[string]string) (exter
 		meta.tlsCertFile = val
 	}
 
+	// TLS and mTLS to the external scaler are configured through TriggerAuthentication
+	meta.caCert = authParams["ca"]
+	meta.clientCert = authParams["cert"]
+	meta.clientKey = authParams["key"]
+	meta.serverName = authParams["serverName"]
+	if (meta.clientCert == "") != (meta.clientKey == "") {
+		return meta, fmt.Errorf("cert and key must both be given for mTLS")
+	}
+	if meta.tlsCertFile != "" && meta.caCert != "" {
+		return meta, fmt.Errorf("only one of tlsCertFile or ca can be given")
+	}
+
+	connectionPolicy, err := parseGRPCConnectionPolicy(metadata)
+	if err != nil {
+		return meta, err
+	}
+	meta.connectionPolicy = connectionPolicy
+
 	meta.originalMetadata = make(map[string]string)
 
 	// Add elements to metadata
@@ -112,18 +153,35 @@ func parseExternalScalerMetadata(metadata, resolvedEnv map[string]string) (exter
 		}
 	}
 
+	// Only the resolved authParams listed in authParamsAllowlist are sent to the external scaler
+	meta.authParams = make(map[string]string)
+	if val, ok := metadata["authParamsAllowlist"]; ok && val != "" {
+		for _, key := range strings.Split(val, ",") {
+			key = strings.TrimSpace(key)
+			if key == "" {
+				continue
+			}
+			authParam, ok := authParams[key]
+			if !ok {
+				return meta, fmt.Errorf("authParam %s is in authParamsAllowlist but not resolved", key)
+			}
+			meta.authParams[key] = authParam
+		}
+	}
+
 	return meta, nil
 }
 
 // IsActive checks if there are any messages in the subscription
 func (s *externalScaler) IsActive(ctx context.Context) (bool, error) {
-	grpcClient, done, err := getClientForConnectionPool(s.metadata)
+	conn, err := s.getConnection()
 	if err != nil {
+		s.recordConnectionState(nil, err)
 		return false, err
 	}
-	defer done()
 
-	response, err := grpcClient.IsActive(ctx, &s.scaledObjectRef)
+	response, err := pb.NewExternalScalerClient(conn).IsActive(ctx, &s.scaledObjectRef)
+	s.recordConnectionState(conn, err)
 	if err != nil {
 		externalLog.Error(err, "error calling IsActive on external scaler")
 		return false, err
@@ -132,22 +190,40 @@ func (s *externalScaler) IsActive(ctx context.Context) (bool, error) {
 	return response.Result, nil
 }
 
+// Close releases the pooled connection of the scaler
 func (s *externalScaler) Close() error {
+	if s.done != nil {
+		s.done()
+		s.grpcConnection, s.done = nil, nil
+	}
 	return nil
 }
 
+// getConnection returns the pooled connection of the scaler, it is taken from the pool on the first call
+func (s *externalScaler) getConnection() (*grpc.ClientConn, error) {
+	if s.grpcConnection == nil {
+		conn, done, err := getClientForConnectionPool(s.metadata)
+		if err != nil {
+			return nil, err
+		}
+		s.grpcConnection, s.done = conn, done
+	}
+	return s.grpcConnection, nil
+}
+
 // GetMetricSpecForScaling returns the metric spec for the HPA
 func (s *externalScaler) GetMetricSpecForScaling() []v2beta2.MetricSpec {
 	var result []v2beta2.MetricSpec
 
-	grpcClient, done, err := getClientForConnectionPool(s.metadata)
+	conn, err := s.getConnection()
 	if err != nil {
+		s.recordConnectionState(nil, err)
 		externalLog.Error(err, "error building grpc connection")
 		return result
 	}
-	defer done()
 
-	response, err := grpcClient.GetMetricSpec(context.TODO(), &s.scaledObjectRef)
+	response, err := pb.NewExternalScalerClient(conn).GetMetricSpec(context.TODO(), &s.scaledObjectRef)
+	s.recordConnectionState(conn, err)
 	if err != nil {
 		externalLog.Error(err, "error")
 		return nil
@@ -182,18 +258,19 @@ func (s *externalScaler) GetMetricSpecForScaling() []v2beta2.MetricSpec {
 // GetMetrics connects calls the gRPC interface to get the metrics with a specific name
 func (s *externalScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
 	var metrics []external_metrics.ExternalMetricValue
-	grpcClient, done, err := getClientForConnectionPool(s.metadata)
+	conn, err := s.getConnection()
 	if err != nil {
+		s.recordConnectionState(nil, err)
 		return metrics, err
 	}
-	defer done()
 
 	request := &pb.GetMetricsRequest{
 		MetricName:      metricName,
 		ScaledObjectRef: &s.scaledObjectRef,
 	}
 
-	response, err := grpcClient.GetMetrics(ctx, request)
+	response, err := pb.NewExternalScalerClient(conn).GetMetrics(ctx, request)
+	s.recordConnectionState(conn, err)
 	if err != nil {
 		externalLog.Error(err, "error")
 		return []external_metrics.ExternalMetricValue{}, err
@@ -215,26 +292,30 @@ func (s *externalScaler) GetMetrics(ctx context.Context, metricName string, metr
 // handleIsActiveStream is the only writer to the active channel and will close it on return.
 func (s *externalPushScaler) Run(ctx context.Context, active chan<- bool) {
 	defer close(active)
+	// retry on error from runWithLog() starting by 2 sec backing off * 2 with a max of grpcReconnectMaxBackoff
+	retryDuration := time.Second * 2
+	retryBackoff := func() <-chan time.Time {
+		ch := time.After(retryDuration)
+		retryDuration = s.metadata.connectionPolicy.nextBackoff(retryDuration)
+		return ch
+	}
+
 	// It's possible for the connection to get terminated anytime, we need to run this in a retry loop
 	runWithLog := func() {
-		grpcClient, done, err := getClientForConnectionPool(s.metadata)
+		conn, done, err := getClientForConnectionPool(s.metadata)
 		if err != nil {
 			externalLog.Error(err, "error running internalRun")
 			return
 		}
-		handleIsActiveStream(ctx, s.scaledObjectRef, grpcClient, active)
-		done()
-	}
-
-	// retry on error from runWithLog() starting by 2 sec backing off * 2 with a max of 1 minute
-	retryDuration := time.Second * 2
-	retryBackoff := func() <-chan time.Time {
-		ch := time.After(retryDuration)
-		retryDuration *= time.Second * 2
-		if retryDuration > time.Minute*1 {
-			retryDuration = time.Minute * 1
+		received, err := handleIsActiveStream(ctx, s.scaledObjectRef, pb.NewExternalScalerClient(conn), active)
+		if received {
+			// the stream was established, the next reconnect starts again with the shortest backoff
+			retryDuration = time.Second * 2
 		}
-		return ch
+		if err != nil && ctx.Err() == nil {
+			externalLog.V(1).Info("IsActive stream of the external scaler terminated", "scalerAddress", s.metadata.scalerAddress, "error", err.Error())
+		}
+		done()
 	}
 
 	// start the first run without delay
@@ -251,83 +332,159 @@ func (s *externalPushScaler) Run(ctx context.Context, active chan<- bool) {
 }
 
 // handleIsActiveStream calls blocks on a stream call from the GRPC server. It'll only terminate on error, stream completion, or ctx cancellation.
-func handleIsActiveStream(ctx context.Context, scaledObjectRef pb.ScaledObjectRef, grpcClient pb.ExternalScalerClient, active chan<- bool) error {
+// It returns whether any response was received on the stream.
+func handleIsActiveStream(ctx context.Context, scaledObjectRef pb.ScaledObjectRef, grpcClient pb.ExternalScalerClient, active chan<- bool) (bool, error) {
 	stream, err := grpcClient.StreamIsActive(ctx, &scaledObjectRef)
 	if err != nil {
-		return err
+		return false, err
 	}
 
+	received := false
 	for {
 		resp, err := stream.Recv()
 		if err != nil {
-			return err
+			return received, err
 		}
+		received = true
 
 		active <- resp.Result
 	}
 }
 
+// ConnectionState returns the address of the external scaler and the state of the connection after the last call
+func (s *externalScaler) ConnectionState() (string, string, string) {
+	return s.metadata.scalerAddress, s.connectionState, s.connectionMessage
+}
+
+func (s *externalScaler) recordConnectionState(conn *grpc.ClientConn, err error) {
+	s.connectionState = grpcConnectionState(conn, err)
+	s.connectionMessage = ""
+	if err != nil {
+		s.connectionMessage = err.Error()
+	}
+}
+
 var connectionPoolMutex sync.Mutex
 
-// getClientForConnectionPool returns a grpcClient and a done() Func. The done() function must be called once the client is no longer
-// in use to clean up the shared grpc.ClientConn
-func getClientForConnectionPool(metadata externalScalerMetadata) (pb.ExternalScalerClient, func(), error) {
+// getClientForConnectionPool returns a grpc.ClientConn and a done() Func. The done() function must be called once the connection is no longer
+// in use, the shared grpc.ClientConn is closed once it has no user for connectionIdleTimeout
+func getClientForConnectionPool(metadata externalScalerMetadata) (*grpc.ClientConn, func(), error) {
 	connectionPoolMutex.Lock()
 	defer connectionPoolMutex.Unlock()
 
 	buildGRPCConnection := func(metadata externalScalerMetadata) (*grpc.ClientConn, error) {
-		if metadata.tlsCertFile != "" {
-			creds, err := credentials.NewClientTLSFromFile(metadata.tlsCertFile, "")
+		options := metadata.connectionPolicy.dialOptions()
+
+		if metadata.tlsCertFile != "" && metadata.clientCert == "" {
+			creds, err := credentials.NewClientTLSFromFile(metadata.tlsCertFile, metadata.serverName)
 			if err != nil {
 				return nil, err
 			}
-			return grpc.Dial(metadata.scalerAddress, grpc.WithTransportCredentials(creds))
+			return grpc.Dial(metadata.scalerAddress, append(options, grpc.WithTransportCredentials(creds))...)
 		}
 
-		return grpc.Dial(metadata.scalerAddress, grpc.WithInsecure())
-	}
+		if metadata.tlsCertFile != "" || metadata.caCert != "" || metadata.clientCert != "" {
+			tlsConfig, err := newExternalScalerTLSConfig(metadata)
+			if err != nil {
+				return nil, err
+			}
+			return grpc.Dial(metadata.scalerAddress, append(options, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))...)
+		}
 
-	// create a unique key per-metadata. If scaledObjects share the same connection properties
-	// in the metadata, they will share the same grpc.ClientConn
-	key, err := hashstructure.Hash(metadata, nil)
-	if err != nil {
-		return nil, nil, err
+		return grpc.Dial(metadata.scalerAddress, append(options, grpc.WithInsecure())...)
 	}
 
+	// If scaledObjects share the same connection properties in the metadata, they will share the same grpc.ClientConn
+	key := connectionPoolKey(metadata)
+
+	var connGroup *connectionGroup
 	if i, ok := connectionPool.Load(key); ok {
-		if connGroup, ok := i.(*connectionGroup); ok {
-			connGroup.waitGroup.Add(1)
-			return pb.NewExternalScalerClient(connGroup.grpcConnection), func() {
-				connGroup.waitGroup.Done()
-			}, nil
+		connGroup = i.(*connectionGroup)
+	} else {
+		conn, err := buildGRPCConnection(metadata)
+		if err != nil {
+			return nil, nil, err
 		}
+		connGroup = &connectionGroup{grpcConnection: conn}
+		connectionPool.Store(key, connGroup)
+	}
+	if connGroup.idleTimer != nil {
+		connGroup.idleTimer.Stop()
+		connGroup.idleTimer = nil
 	}
+	connGroup.users++
+
+	var once sync.Once
+	return connGroup.grpcConnection, func() {
+		once.Do(func() {
+			// once the last scaler is done, the connection is kept idle for the next polls
+			connectionPoolMutex.Lock()
+			defer connectionPoolMutex.Unlock()
+			connGroup.users--
+			if connGroup.users == 0 {
+				connGroup.idleTimer = time.AfterFunc(connectionIdleTimeout, func() {
+					closeIdleConnection(key, connGroup)
+				})
+			}
+		})
+	}, nil
+}
 
-	conn, err := buildGRPCConnection(metadata)
-	if err != nil {
-		return nil, nil, err
+// closeIdleConnection removes the connection from the pool and Close() grpc.ClientConn unless a scaler took it again
+func closeIdleConnection(key string, connGroup *connectionGroup) {
+	connectionPoolMutex.Lock()
+	defer connectionPoolMutex.Unlock()
+	if connGroup.users > 0 {
+		return
 	}
+	if i, ok := connectionPool.Load(key); ok && i.(*connectionGroup) == connGroup {
+		connectionPool.Delete(key)
+	}
+	connGroup.grpcConnection.Close()
+}
 
-	waitGroup := &sync.WaitGroup{}
-	waitGroup.Add(1)
-	connGroup := connectionGroup{
-		grpcConnection: conn,
-		waitGroup:      waitGroup,
+// connectionPoolKey returns the key of the connection of the metadata in the pool, the triggers with the same address,
+// TLS material and connection policy share the connection
+func connectionPoolKey(metadata externalScalerMetadata) string {
+	hash := sha256.New()
+	for _, field := range []string{metadata.scalerAddress, metadata.tlsCertFile, metadata.caCert, metadata.clientCert, metadata.clientKey,
+		metadata.serverName, fmt.Sprintf("%+v", metadata.connectionPolicy)} {
+		_, _ = hash.Write([]byte(field))
+		_, _ = hash.Write([]byte{0})
 	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
 
-	connectionPool.Store(key, connGroup)
+// newExternalScalerTLSConfig returns the *tls.Config for the connection to the external scaler,
+// verifying the server against the given CA and presenting the client certificate for mTLS if any
+func newExternalScalerTLSConfig(metadata externalScalerMetadata) (*tls.Config, error) {
+	config := &tls.Config{
+		ServerName: metadata.serverName,
+	}
 
-	go func() {
-		// clean up goroutine.
-		// once all waitGroup is done, remove the connection from the pool and Close() grpc.ClientConn
-		connGroup.waitGroup.Wait()
-		connectionPoolMutex.Lock()
-		defer connectionPoolMutex.Unlock()
-		connectionPool.Delete(key)
-		connGroup.grpcConnection.Close()
-	}()
+	caCert := []byte(metadata.caCert)
+	if metadata.tlsCertFile != "" {
+		var err error
+		caCert, err = ioutil.ReadFile(metadata.tlsCertFile)
+		if err != nil {
+			return nil, fmt.Errorf("error reading tlsCertFile: %s", err)
+		}
+	}
+	if len(caCert) > 0 {
+		caCertPool := x509.NewCertPool()
+		if !caCertPool.AppendCertsFromPEM(caCert) {
+			return nil, fmt.Errorf("error parsing ca: no certificate found")
+		}
+		config.RootCAs = caCertPool
+	}
 
-	return pb.NewExternalScalerClient(connGroup.grpcConnection), func() {
-		connGroup.waitGroup.Done()
-	}, nil
+	if metadata.clientCert != "" {
+		cert, err := tls.X509KeyPair([]byte(metadata.clientCert), []byte(metadata.clientKey))
+		if err != nil {
+			return nil, fmt.Errorf("error parsing X509KeyPair: %s", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	return config, nil
 }
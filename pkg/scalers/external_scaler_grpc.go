@@ -0,0 +1,179 @@
+package scalers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/backoff"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	grpcKeepaliveTimeMetadata       = "grpcKeepaliveTime"
+	grpcKeepaliveTimeoutMetadata    = "grpcKeepaliveTimeout"
+	grpcRetryMaxAttemptsMetadata    = "grpcRetryMaxAttempts"
+	grpcRetryBackoffMetadata        = "grpcRetryBackoff"
+	grpcReconnectMaxBackoffMetadata = "grpcReconnectMaxBackoff"
+
+	// defaultGRPCKeepaliveTime is the minimum interval of the pings accepted by default by the grpc servers
+	defaultGRPCKeepaliveTime       = 5 * time.Minute
+	defaultGRPCKeepaliveTimeout    = 20 * time.Second
+	defaultGRPCRetryMaxAttempts    = 3
+	defaultGRPCRetryBackoff        = 500 * time.Millisecond
+	defaultGRPCReconnectMaxBackoff = time.Minute
+	grpcReconnectBaseBackoff       = time.Second
+	grpcMinConnectTimeout          = 20 * time.Second
+	// the client raises lower keepalive intervals to this minimum
+	minGRPCKeepaliveTime = 10 * time.Second
+)
+
+// grpcConnectionPolicy defines the keepalive of the connection to an external scaler,
+// how its unary calls are retried and how the connection and the IsActive stream are re-established
+type grpcConnectionPolicy struct {
+	// KeepaliveTime is the interval of the keepalive pings, 0 disables them
+	KeepaliveTime time.Duration
+	// KeepaliveTimeout is the wait for the ack of a keepalive ping before the connection is closed
+	KeepaliveTimeout time.Duration
+	// RetryMaxAttempts is the number of times an unavailable unary call is sent, including the first one
+	RetryMaxAttempts int
+	// RetryBackoff is the wait before the first retry, it is doubled on each retry up to ReconnectMaxBackoff
+	RetryBackoff time.Duration
+	// ReconnectMaxBackoff is the longest wait before a reconnect or a retry
+	ReconnectMaxBackoff time.Duration
+}
+
+// parseGRPCConnectionPolicy returns the connection policy of an external scaler, the defaults are overridden by the trigger metadata:
+// grpcKeepaliveTime and grpcKeepaliveTimeout are the keepalive ping interval and ack timeout as durations, e.g. 30s, 0s disables the pings,
+// grpcRetryMaxAttempts is the number of attempts of a call, 1 disables the retries, grpcRetryBackoff is the wait before the first retry
+// and grpcReconnectMaxBackoff is the longest wait before a reconnect
+func parseGRPCConnectionPolicy(metadata map[string]string) (grpcConnectionPolicy, error) {
+	policy := grpcConnectionPolicy{
+		KeepaliveTime:       defaultGRPCKeepaliveTime,
+		KeepaliveTimeout:    defaultGRPCKeepaliveTimeout,
+		RetryMaxAttempts:    defaultGRPCRetryMaxAttempts,
+		RetryBackoff:        defaultGRPCRetryBackoff,
+		ReconnectMaxBackoff: defaultGRPCReconnectMaxBackoff,
+	}
+
+	parseDuration := func(key string, value *time.Duration) error {
+		if val, ok := metadata[key]; ok && val != "" {
+			duration, err := time.ParseDuration(val)
+			if err != nil {
+				return fmt.Errorf("error parsing %s: %s", key, err)
+			}
+			if duration < 0 {
+				return fmt.Errorf("%s must not be negative", key)
+			}
+			*value = duration
+		}
+		return nil
+	}
+
+	if err := parseDuration(grpcKeepaliveTimeMetadata, &policy.KeepaliveTime); err != nil {
+		return policy, err
+	}
+	if policy.KeepaliveTime > 0 && policy.KeepaliveTime < minGRPCKeepaliveTime {
+		return policy, fmt.Errorf("%s must be at least %s", grpcKeepaliveTimeMetadata, minGRPCKeepaliveTime)
+	}
+	if err := parseDuration(grpcKeepaliveTimeoutMetadata, &policy.KeepaliveTimeout); err != nil {
+		return policy, err
+	}
+	if err := parseDuration(grpcRetryBackoffMetadata, &policy.RetryBackoff); err != nil {
+		return policy, err
+	}
+	if err := parseDuration(grpcReconnectMaxBackoffMetadata, &policy.ReconnectMaxBackoff); err != nil {
+		return policy, err
+	}
+	if policy.ReconnectMaxBackoff < grpcReconnectBaseBackoff {
+		return policy, fmt.Errorf("%s must be at least %s", grpcReconnectMaxBackoffMetadata, grpcReconnectBaseBackoff)
+	}
+	if policy.RetryBackoff > policy.ReconnectMaxBackoff {
+		policy.RetryBackoff = policy.ReconnectMaxBackoff
+	}
+
+	if val, ok := metadata[grpcRetryMaxAttemptsMetadata]; ok && val != "" {
+		maxAttempts, err := strconv.Atoi(val)
+		if err != nil {
+			return policy, fmt.Errorf("error parsing %s: %s", grpcRetryMaxAttemptsMetadata, err)
+		}
+		if maxAttempts < 1 {
+			return policy, fmt.Errorf("%s must be at least 1", grpcRetryMaxAttemptsMetadata)
+		}
+		policy.RetryMaxAttempts = maxAttempts
+	}
+
+	return policy, nil
+}
+
+// dialOptions returns the keepalive, reconnect backoff and retry options of the connection
+func (p grpcConnectionPolicy) dialOptions() []grpc.DialOption {
+	options := []grpc.DialOption{
+		grpc.WithConnectParams(grpc.ConnectParams{
+			Backoff: backoff.Config{
+				BaseDelay:  grpcReconnectBaseBackoff,
+				Multiplier: backoff.DefaultConfig.Multiplier,
+				Jitter:     backoff.DefaultConfig.Jitter,
+				MaxDelay:   p.ReconnectMaxBackoff,
+			},
+			MinConnectTimeout: grpcMinConnectTimeout,
+		}),
+		grpc.WithUnaryInterceptor(p.retryUnaryInterceptor),
+	}
+
+	if p.KeepaliveTime > 0 {
+		options = append(options, grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:    p.KeepaliveTime,
+			Timeout: p.KeepaliveTimeout,
+			// the IsActive stream of a push scaler is idle between the activity changes
+			PermitWithoutStream: true,
+		}))
+	}
+
+	return options
+}
+
+// retryUnaryInterceptor retries the calls failing because the external scaler is unavailable
+func (p grpcConnectionPolicy) retryUnaryInterceptor(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	wait := p.RetryBackoff
+	for attempt := 1; ; attempt++ {
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		if attempt >= p.RetryMaxAttempts || status.Code(err) != codes.Unavailable {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(wait):
+		}
+		wait = p.nextBackoff(wait)
+	}
+}
+
+// nextBackoff doubles the wait up to ReconnectMaxBackoff
+func (p grpcConnectionPolicy) nextBackoff(wait time.Duration) time.Duration {
+	wait *= 2
+	if wait > p.ReconnectMaxBackoff {
+		wait = p.ReconnectMaxBackoff
+	}
+	return wait
+}
+
+// grpcConnectionState returns the state of the connection after a call, if the call failed with an error
+// the connection may still be reported as ready by grpc so it is then reported as failing
+func grpcConnectionState(conn *grpc.ClientConn, err error) string {
+	if conn == nil {
+		return connectivity.TransientFailure.String()
+	}
+	state := conn.GetState()
+	if err != nil && status.Code(err) == codes.Unavailable && state == connectivity.Ready {
+		state = connectivity.TransientFailure
+	}
+	return state.String()
+}
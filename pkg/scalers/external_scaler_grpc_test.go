@@ -0,0 +1,98 @@
+package scalers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type parseGRPCConnectionPolicyTestData struct {
+	metadata map[string]string
+	isError  bool
+}
+
+var testGRPCConnectionPolicyMetadata = []parseGRPCConnectionPolicyTestData{
+	// defaults
+	{map[string]string{}, false},
+	// keepalive and retries
+	{map[string]string{"grpcKeepaliveTime": "30s", "grpcKeepaliveTimeout": "5s", "grpcRetryMaxAttempts": "5", "grpcRetryBackoff": "1s", "grpcReconnectMaxBackoff": "2m"}, false},
+	// keepalive below the minimum
+	{map[string]string{"grpcKeepaliveTime": "1s"}, true},
+	// malformed keepalive timeout
+	{map[string]string{"grpcKeepaliveTimeout": "soon"}, true},
+	// no attempt
+	{map[string]string{"grpcRetryMaxAttempts": "0"}, true},
+	// malformed attempts
+	{map[string]string{"grpcRetryMaxAttempts": "many"}, true},
+	// negative backoff
+	{map[string]string{"grpcRetryBackoff": "-1s"}, true},
+	// reconnect backoff below the base backoff
+	{map[string]string{"grpcReconnectMaxBackoff": "100ms"}, true},
+}
+
+func TestParseGRPCConnectionPolicy(t *testing.T) {
+	for _, testData := range testGRPCConnectionPolicyMetadata {
+		_, err := parseGRPCConnectionPolicy(testData.metadata)
+		if err != nil && !testData.isError {
+			t.Error("Expected success but got error", err)
+		}
+		if testData.isError && err == nil {
+			t.Errorf("Expected error but got success for %v", testData.metadata)
+		}
+	}
+
+	policy, _ := parseGRPCConnectionPolicy(map[string]string{"grpcRetryBackoff": "5m"})
+	if policy.RetryBackoff != defaultGRPCReconnectMaxBackoff {
+		t.Errorf("Expected the retry backoff to be capped to %s, got %s", defaultGRPCReconnectMaxBackoff, policy.RetryBackoff)
+	}
+}
+
+func TestGRPCRetryUnaryInterceptor(t *testing.T) {
+	policy := grpcConnectionPolicy{RetryMaxAttempts: 3, RetryBackoff: time.Millisecond, ReconnectMaxBackoff: time.Second}
+
+	tests := []struct {
+		errs             []error
+		expectedAttempts int
+		expectedCode     codes.Code
+	}{
+		// success on the first attempt
+		{[]error{nil}, 1, codes.OK},
+		// unavailable then success
+		{[]error{status.Error(codes.Unavailable, "down"), nil}, 2, codes.OK},
+		// unavailable on all the attempts
+		{[]error{status.Error(codes.Unavailable, "down"), status.Error(codes.Unavailable, "down"), status.Error(codes.Unavailable, "down"), nil}, 3, codes.Unavailable},
+		// other errors aren't retried
+		{[]error{status.Error(codes.InvalidArgument, "bad metadata"), nil}, 1, codes.InvalidArgument},
+	}
+
+	for _, test := range tests {
+		attempts := 0
+		invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			err := test.errs[attempts]
+			attempts++
+			return err
+		}
+
+		err := policy.retryUnaryInterceptor(context.TODO(), "/externalscaler.ExternalScaler/IsActive", nil, nil, nil, invoker)
+		if attempts != test.expectedAttempts {
+			t.Errorf("Expected %d attempts, got %d", test.expectedAttempts, attempts)
+		}
+		if status.Code(err) != test.expectedCode {
+			t.Errorf("Expected code %s, got %s", test.expectedCode, status.Code(err))
+		}
+	}
+}
+
+func TestGRPCConnectionPolicyNextBackoff(t *testing.T) {
+	policy := grpcConnectionPolicy{ReconnectMaxBackoff: 5 * time.Second}
+	if wait := policy.nextBackoff(2 * time.Second); wait != 4*time.Second {
+		t.Errorf("Expected 4s, got %s", wait)
+	}
+	if wait := policy.nextBackoff(4 * time.Second); wait != 5*time.Second {
+		t.Errorf("Expected 5s, got %s", wait)
+	}
+}
@@ -2,7 +2,14 @@ package scalers
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
 	"fmt"
+	"math/big"
 	"net"
 	"sync/atomic"
 	"testing"
@@ -15,21 +22,32 @@ import (
 )
 
 type parseExternalScalerMetadataTestData struct {
-	metadata map[string]string
-	isError  bool
+	metadata   map[string]string
+	authParams map[string]string
+	isError    bool
 }
 
 var testExternalScalerMetadata = []parseExternalScalerMetadataTestData{
-	{map[string]string{}, true},
+	{map[string]string{}, map[string]string{}, true},
 	// all properly formed
-	{map[string]string{"scalerAddress": "myservice", "test1": "7", "test2": "SAMPLE_CREDS"}, false},
+	{map[string]string{"scalerAddress": "myservice", "test1": "7", "test2": "SAMPLE_CREDS"}, map[string]string{}, false},
 	// missing scalerAddress
-	{map[string]string{"test1": "1", "test2": "SAMPLE_CREDS"}, true},
+	{map[string]string{"test1": "1", "test2": "SAMPLE_CREDS"}, map[string]string{}, true},
+	// allowlisted authParams
+	{map[string]string{"scalerAddress": "myservice", "authParamsAllowlist": "username, password"}, map[string]string{"username": "keda", "password": "secret", "token": "hidden"}, false},
+	// allowlisted authParam not resolved
+	{map[string]string{"scalerAddress": "myservice", "authParamsAllowlist": "token"}, map[string]string{"username": "keda"}, true},
+	// mTLS from TriggerAuthentication
+	{map[string]string{"scalerAddress": "myservice"}, map[string]string{"ca": "caCert", "cert": "clientCert", "key": "clientKey", "serverName": "myservice.keda"}, false},
+	// cert without key
+	{map[string]string{"scalerAddress": "myservice"}, map[string]string{"ca": "caCert", "cert": "clientCert"}, true},
+	// both tlsCertFile and ca
+	{map[string]string{"scalerAddress": "myservice", "tlsCertFile": "/certs/ca.crt"}, map[string]string{"ca": "caCert"}, true},
 }
 
 func TestExternalScalerParseMetadata(t *testing.T) {
 	for _, testData := range testExternalScalerMetadata {
-		_, err := parseExternalScalerMetadata(testData.metadata, map[string]string{})
+		_, err := parseExternalScalerMetadata(testData.metadata, map[string]string{}, testData.authParams)
 		if err != nil && !testData.isError {
 			t.Error("Expected success but got error", err)
 		}
@@ -39,6 +57,184 @@ func TestExternalScalerParseMetadata(t *testing.T) {
 	}
 }
 
+func TestExternalScalerAuthParamsAllowlist(t *testing.T) {
+	authParams := map[string]string{"username": "keda", "password": "secret", "token": "hidden"}
+
+	scaler, err := NewExternalScaler("app", "namespace", map[string]string{"scalerAddress": "myservice"}, map[string]string{}, authParams)
+	if err != nil {
+		t.Fatal("Could not create scaler:", err)
+	}
+	if authParamsSent := scaler.(*externalScaler).scaledObjectRef.AuthParams; len(authParamsSent) != 0 {
+		t.Error("Expected no authParams without an allowlist but got", authParamsSent)
+	}
+
+	scaler, err = NewExternalScaler("app", "namespace", map[string]string{"scalerAddress": "myservice", "authParamsAllowlist": "username,password"}, map[string]string{}, authParams)
+	if err != nil {
+		t.Fatal("Could not create scaler:", err)
+	}
+	authParamsSent := scaler.(*externalScaler).scaledObjectRef.AuthParams
+	if len(authParamsSent) != 2 || authParamsSent["username"] != "keda" || authParamsSent["password"] != "secret" {
+		t.Error("Expected only the allowlisted authParams but got", authParamsSent)
+	}
+}
+
+func TestExternalScalerTLSConfig(t *testing.T) {
+	certPEM, keyPEM := createTestCertificate(t)
+
+	config, err := newExternalScalerTLSConfig(externalScalerMetadata{caCert: certPEM, clientCert: certPEM, clientKey: keyPEM, serverName: "myservice.keda"})
+	if err != nil {
+		t.Fatal("Could not build tls config:", err)
+	}
+	if config.RootCAs == nil || len(config.Certificates) != 1 || config.ServerName != "myservice.keda" || config.InsecureSkipVerify {
+		t.Error("Expected a verifying tls config with a client certificate but got", config)
+	}
+
+	if _, err := newExternalScalerTLSConfig(externalScalerMetadata{caCert: "not a certificate"}); err == nil {
+		t.Error("Expected error for a malformed ca but got success")
+	}
+	if _, err := newExternalScalerTLSConfig(externalScalerMetadata{clientCert: certPEM, clientKey: "not a key"}); err == nil {
+		t.Error("Expected error for a malformed key but got success")
+	}
+}
+
+func createTestCertificate(t *testing.T) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "myservice.keda"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+		IsCA:         true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer})
+	return string(certPEM), string(keyPEM)
+}
+
+func TestExternalScalerConnectionPool(t *testing.T) {
+	orders := externalScalerMetadata{scalerAddress: "orders-scaler:6000", originalMetadata: map[string]string{"queue": "orders"}}
+	invoices := externalScalerMetadata{scalerAddress: "orders-scaler:6000", originalMetadata: map[string]string{"queue": "invoices"}}
+	other := externalScalerMetadata{scalerAddress: "other-scaler:6000"}
+	withTLS := externalScalerMetadata{scalerAddress: "orders-scaler:6000", caCert: "ca"}
+	withPolicy := externalScalerMetadata{scalerAddress: "orders-scaler:6000", connectionPolicy: grpcConnectionPolicy{RetryMaxAttempts: 1}}
+
+	if connectionPoolKey(orders) != connectionPoolKey(invoices) {
+		t.Error("Expected the triggers of the same scaler to share the connection")
+	}
+	for _, metadata := range []externalScalerMetadata{other, withTLS, withPolicy} {
+		if connectionPoolKey(orders) == connectionPoolKey(metadata) {
+			t.Errorf("Expected the connection of %+v not to be shared", metadata)
+		}
+	}
+
+	conn, done, err := getClientForConnectionPool(orders)
+	if err != nil {
+		t.Fatal(err)
+	}
+	shared, sharedDone, err := getClientForConnectionPool(invoices)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if conn != shared {
+		t.Error("Expected the connection to be shared")
+	}
+	done()
+	done()
+	if _, ok := connectionPool.Load(connectionPoolKey(orders)); !ok {
+		t.Error("Expected the connection to be kept until its last user is done")
+	}
+	sharedDone()
+	i, ok := connectionPool.Load(connectionPoolKey(orders))
+	if !ok {
+		t.Fatal("Expected the connection to be kept idle once its users are done")
+	}
+	connGroup := i.(*connectionGroup)
+	if connGroup.idleTimer == nil {
+		t.Error("Expected the idle connection to expire")
+	}
+
+	// a scaler taking the idle connection again keeps it from expiring
+	again, againDone, err := getClientForConnectionPool(orders)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again != conn || connGroup.idleTimer != nil {
+		t.Error("Expected the idle connection to be reused")
+	}
+	closeIdleConnection(connectionPoolKey(orders), connGroup)
+	if _, ok := connectionPool.Load(connectionPoolKey(orders)); !ok {
+		t.Error("Expected the connection in use not to be closed")
+	}
+	againDone()
+	connGroup.idleTimer.Stop()
+	closeIdleConnection(connectionPoolKey(orders), connGroup)
+	if _, ok := connectionPool.Load(connectionPoolKey(orders)); ok {
+		t.Error("Expected the idle connection to be removed from the pool once it expires")
+	}
+}
+
+type countingListener struct {
+	net.Listener
+	accepted int64
+}
+
+func (l *countingListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err == nil {
+		atomic.AddInt64(&l.accepted, 1)
+	}
+	return conn, err
+}
+
+func TestExternalScalerPollsShareConnection(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	listener := &countingListener{Listener: lis}
+	grpcServer := grpc.NewServer()
+	pb.RegisterExternalScalerServer(grpcServer, &testExternalScaler{t: t})
+	go func() {
+		_ = grpcServer.Serve(listener)
+	}()
+	defer grpcServer.Stop()
+
+	metadata := map[string]string{"scalerAddress": lis.Addr().String()}
+	// the scalers are built and closed on each poll
+	for poll := 0; poll < 2; poll++ {
+		scaler, err := NewExternalScaler("app", "namespace", metadata, map[string]string{}, map[string]string{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := scaler.IsActive(context.TODO()); status.Code(err) != codes.Unimplemented {
+			t.Fatalf("Expected the call to reach the external scaler, got %v", err)
+		}
+		scaler.Close()
+	}
+
+	if accepted := atomic.LoadInt64(&listener.accepted); accepted != 1 {
+		t.Errorf("Expected the polls to share a single connection, got %d connections", accepted)
+	}
+
+	meta, _ := parseExternalScalerMetadata(metadata, map[string]string{}, map[string]string{})
+	if i, ok := connectionPool.Load(connectionPoolKey(meta)); ok {
+		connGroup := i.(*connectionGroup)
+		connGroup.idleTimer.Stop()
+		closeIdleConnection(connectionPoolKey(meta), connGroup)
+	}
+}
+
 func TestExternalPushScaler_Run(t *testing.T) {
 	const serverCount = 5
 	const iterationCount = 500
@@ -52,7 +248,7 @@ func TestExternalPushScaler_Run(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	for i := 0; i < serverCount*iterationCount; i++ {
 		id := i % serverCount
-		pushScaler, _ := NewExternalPushScaler("app", "namespace", map[string]string{"scalerAddress": servers[id].address}, map[string]string{})
+		pushScaler, _ := NewExternalPushScaler("app", "namespace", map[string]string{"scalerAddress": servers[id].address}, map[string]string{}, map[string]string{})
 		go pushScaler.Run(ctx, replyCh[i])
 	}
 
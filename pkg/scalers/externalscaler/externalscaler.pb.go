@@ -28,6 +28,7 @@ type ScaledObjectRef struct {
 	Name                 string            `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
 	Namespace            string            `protobuf:"bytes,2,opt,name=namespace,proto3" json:"namespace,omitempty"`
 	ScalerMetadata       map[string]string `protobuf:"bytes,3,rep,name=scalerMetadata,proto3" json:"scalerMetadata,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	AuthParams           map[string]string `protobuf:"bytes,4,rep,name=authParams,proto3" json:"authParams,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
 	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
 	XXX_unrecognized     []byte            `json:"-"`
 	XXX_sizecache        int32             `json:"-"`
@@ -79,6 +80,13 @@ func (m *ScaledObjectRef) GetScalerMetadata() map[string]string {
 	return nil
 }
 
+func (m *ScaledObjectRef) GetAuthParams() map[string]string {
+	if m != nil {
+		return m.AuthParams
+	}
+	return nil
+}
+
 type IsActiveResponse struct {
 	Result               bool     `protobuf:"varint,1,opt,name=result,proto3" json:"result,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -339,6 +347,7 @@ func (m *MetricValue) GetMetricValue() int64 {
 
 func init() {
 	proto.RegisterType((*ScaledObjectRef)(nil), "externalscaler.ScaledObjectRef")
+	proto.RegisterMapType((map[string]string)(nil), "externalscaler.ScaledObjectRef.AuthParamsEntry")
 	proto.RegisterMapType((map[string]string)(nil), "externalscaler.ScaledObjectRef.ScalerMetadataEntry")
 	proto.RegisterType((*IsActiveResponse)(nil), "externalscaler.IsActiveResponse")
 	proto.RegisterType((*GetMetricSpecResponse)(nil), "externalscaler.GetMetricSpecResponse")
@@ -351,35 +360,37 @@ func init() {
 func init() { proto.RegisterFile("externalscaler.proto", fileDescriptor_3d382708546499d1) }
 
 var fileDescriptor_3d382708546499d1 = []byte{
-	// 442 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x54, 0xdf, 0x6b, 0xd4, 0x40,
-	0x10, 0x6e, 0x12, 0x2d, 0xed, 0x44, 0xd3, 0x73, 0xac, 0x12, 0xa2, 0x68, 0x5c, 0x10, 0x8a, 0x0f,
-	0x87, 0x5c, 0x5f, 0x44, 0x05, 0xa9, 0x50, 0xa4, 0x60, 0x3d, 0xd8, 0x70, 0x15, 0xf5, 0x69, 0x9b,
-	0x8e, 0x72, 0x9a, 0xcb, 0xc5, 0xdd, 0xbd, 0xe2, 0xf9, 0xe0, 0x3f, 0xeb, 0xab, 0x7f, 0x84, 0xe4,
-	0x77, 0xb2, 0x9c, 0xe6, 0xa5, 0x4f, 0xd9, 0x9d, 0xf9, 0xe6, 0xdb, 0x99, 0x6f, 0x3e, 0x02, 0xfb,
-	0xf4, 0x43, 0x93, 0x4c, 0x45, 0xa2, 0x62, 0x91, 0x90, 0x1c, 0x67, 0x72, 0xa9, 0x97, 0xe8, 0xf5,
-	0xa3, 0xec, 0xb7, 0x05, 0x7b, 0x51, 0x7e, 0xbc, 0x98, 0x9e, 0x7f, 0xa5, 0x58, 0x73, 0xfa, 0x8c,
-	0x08, 0xd7, 0x52, 0xb1, 0x20, 0xdf, 0x0a, 0xad, 0x83, 0x5d, 0x5e, 0x9c, 0xf1, 0x3e, 0xec, 0xe6,
-	0x5f, 0x95, 0x89, 0x98, 0x7c, 0xbb, 0x48, 0xb4, 0x01, 0xfc, 0x04, 0x5e, 0xc9, 0x77, 0x4a, 0x5a,
-	0x5c, 0x08, 0x2d, 0x7c, 0x27, 0x74, 0x0e, 0xdc, 0xc9, 0xe1, 0xd8, 0x68, 0xc2, 0x78, 0xaa, 0xbc,
-	0x37, 0x55, 0xc7, 0xa9, 0x96, 0x6b, 0x6e, 0x50, 0x05, 0x47, 0x70, 0x7b, 0x03, 0x0c, 0x47, 0xe0,
-	0x7c, 0xa3, 0x75, 0xd5, 0x64, 0x7e, 0xc4, 0x7d, 0xb8, 0x7e, 0x29, 0x92, 0x55, 0xdd, 0x5f, 0x79,
-	0x79, 0x6e, 0x3f, 0xb3, 0xd8, 0x13, 0x18, 0x9d, 0xa8, 0xa3, 0x58, 0xcf, 0x2f, 0x89, 0x93, 0xca,
-	0x96, 0xa9, 0x22, 0xbc, 0x0b, 0xdb, 0x92, 0xd4, 0x2a, 0xd1, 0x05, 0xc5, 0x0e, 0xaf, 0x6e, 0x6c,
-	0x06, 0x77, 0xde, 0x90, 0x3e, 0x25, 0x2d, 0xe7, 0x71, 0x94, 0x51, 0xdc, 0x14, 0xbc, 0x04, 0x77,
-	0xd1, 0x44, 0x95, 0x6f, 0x15, 0x13, 0x06, 0xe6, 0x84, 0x9d, 0xc2, 0x2e, 0x9c, 0xbd, 0x05, 0x68,
-	0x53, 0xf8, 0x00, 0xa0, 0x4c, 0xbe, 0x6b, 0x85, 0xee, 0x44, 0xf2, 0xbc, 0x16, 0xf2, 0x0b, 0xe9,
-	0x68, 0xfe, 0xb3, 0x9c, 0xc7, 0xe1, 0x9d, 0x08, 0xfb, 0x05, 0xb7, 0x9a, 0x26, 0x15, 0xa7, 0xef,
-	0x2b, 0x52, 0x1a, 0x4f, 0x60, 0x4f, 0xf5, 0xf5, 0x2d, 0x98, 0xdd, 0xc9, 0xc3, 0x81, 0x35, 0x70,
-	0xb3, 0xce, 0xe8, 0xcf, 0x36, 0xfb, 0x63, 0x33, 0xc0, 0xee, 0xfb, 0x95, 0x42, 0xaf, 0xe0, 0x46,
-	0x89, 0x39, 0xcb, 0x95, 0xaf, 0x25, 0xba, 0xb7, 0x59, 0xa2, 0x02, 0xc3, 0x7b, 0x05, 0x6c, 0x0a,
-	0x6e, 0x27, 0x39, 0xa8, 0x52, 0x58, 0x6f, 0xe4, 0xac, 0x59, 0xbb, 0xc3, 0xbb, 0xa1, 0xc9, 0x1f,
-	0x1b, 0xbc, 0xe3, 0xea, 0xf5, 0xd2, 0x44, 0x38, 0x85, 0x9d, 0xda, 0x0b, 0x38, 0x24, 0x4c, 0x10,
-	0x9a, 0x00, 0xd3, 0x46, 0x6c, 0x0b, 0xdf, 0x83, 0x17, 0x69, 0x49, 0x62, 0x71, 0xa5, 0xb4, 0x4f,
-	0x2d, 0xfc, 0x00, 0x37, 0x7b, 0x4e, 0x1c, 0xe6, 0x7d, 0x6c, 0x02, 0x36, 0x3a, 0x99, 0x6d, 0xe1,
-	0x0c, 0xa0, 0xdd, 0x1f, 0x3e, 0xfa, 0x67, 0x59, 0xed, 0xad, 0x80, 0xfd, 0x0f, 0x52, 0xd3, 0xbe,
-	0xc6, 0x8f, 0xa3, 0xf1, 0x8b, 0x3e, 0xf0, 0x7c, 0xbb, 0xf8, 0xf1, 0x1c, 0xfe, 0x0d, 0x00, 0x00,
-	0xff, 0xff, 0xdc, 0xf1, 0x73, 0xce, 0x90, 0x04, 0x00, 0x00,
+	// 472 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x54, 0x4d, 0x6b, 0x14, 0x41,
+	0x10, 0xcd, 0xcc, 0xc4, 0x90, 0xd4, 0xea, 0xee, 0x5a, 0x46, 0x19, 0x46, 0xd1, 0xb5, 0x41, 0x08,
+	0x1e, 0x56, 0xd9, 0x5c, 0xc4, 0x0f, 0x64, 0x85, 0x20, 0x01, 0xe3, 0x4a, 0x0f, 0x1b, 0x51, 0x4f,
+	0x9d, 0x49, 0xa9, 0xab, 0xfb, 0x65, 0x77, 0x6f, 0x30, 0x1e, 0xfc, 0xc5, 0xde, 0xfc, 0x03, 0x32,
+	0x3d, 0x5f, 0x3d, 0xcd, 0xea, 0x20, 0xe4, 0x34, 0xdd, 0x55, 0xef, 0xbd, 0xaa, 0x7e, 0x55, 0x0c,
+	0xec, 0xd2, 0x77, 0x4d, 0x72, 0x2e, 0xa6, 0x2a, 0x11, 0x53, 0x92, 0xfd, 0xa5, 0x5c, 0xe8, 0x05,
+	0xb6, 0xeb, 0x51, 0xf6, 0xdb, 0x87, 0x4e, 0x9c, 0x1e, 0x4f, 0x47, 0x27, 0x5f, 0x28, 0xd1, 0x9c,
+	0x3e, 0x22, 0xc2, 0xe6, 0x5c, 0xcc, 0x28, 0xf4, 0x7a, 0xde, 0xde, 0x0e, 0x37, 0x67, 0xbc, 0x05,
+	0x3b, 0xe9, 0x57, 0x2d, 0x45, 0x42, 0xa1, 0x6f, 0x12, 0x55, 0x00, 0x3f, 0x40, 0x3b, 0xd3, 0x3b,
+	0x22, 0x2d, 0x4e, 0x85, 0x16, 0x61, 0xd0, 0x0b, 0xf6, 0x5a, 0x83, 0xfd, 0xbe, 0xd3, 0x84, 0x53,
+	0xaa, 0x1f, 0xd7, 0x58, 0x07, 0x73, 0x2d, 0xcf, 0xb9, 0x23, 0x85, 0x23, 0x00, 0xb1, 0xd2, 0x9f,
+	0xdf, 0x08, 0x29, 0x66, 0x2a, 0xdc, 0x34, 0xc2, 0x0f, 0x9a, 0x84, 0x87, 0x25, 0x23, 0x13, 0xb5,
+	0x24, 0xa2, 0x21, 0x5c, 0x5b, 0x53, 0x17, 0xbb, 0x10, 0x7c, 0xa5, 0xf3, 0xfc, 0xd5, 0xe9, 0x11,
+	0x77, 0xe1, 0xd2, 0x99, 0x98, 0xae, 0x8a, 0x07, 0x67, 0x97, 0xc7, 0xfe, 0x23, 0x2f, 0x7a, 0x06,
+	0x1d, 0xa7, 0xc2, 0xff, 0xd0, 0xd9, 0x7d, 0xe8, 0x1e, 0xaa, 0x61, 0xa2, 0x27, 0x67, 0xc4, 0x49,
+	0x2d, 0x17, 0x73, 0x45, 0x78, 0x03, 0xb6, 0x24, 0xa9, 0xd5, 0x54, 0x1b, 0x89, 0x6d, 0x9e, 0xdf,
+	0xd8, 0x18, 0xae, 0xbf, 0x24, 0x7d, 0x44, 0x5a, 0x4e, 0x92, 0x78, 0x49, 0x49, 0x49, 0x78, 0x0a,
+	0xad, 0x59, 0x19, 0x55, 0xa1, 0x67, 0x8c, 0x89, 0x5c, 0x63, 0x2c, 0xa2, 0x0d, 0x67, 0xaf, 0x00,
+	0xaa, 0x14, 0xde, 0x06, 0xc8, 0x92, 0xaf, 0xab, 0xc1, 0x5b, 0x91, 0x34, 0xaf, 0x85, 0xfc, 0x44,
+	0x3a, 0x9e, 0xfc, 0xc8, 0xde, 0x13, 0x70, 0x2b, 0xc2, 0x7e, 0xc2, 0xd5, 0xb2, 0x49, 0xc5, 0xe9,
+	0xdb, 0x8a, 0x94, 0xc6, 0x43, 0xe8, 0xa8, 0xfa, 0x58, 0x8c, 0x72, 0x6b, 0x70, 0xa7, 0x61, 0x7a,
+	0xdc, 0xe5, 0x39, 0xfd, 0xf9, 0x6e, 0x7f, 0x6c, 0x0c, 0x68, 0xd7, 0xcf, 0x1d, 0x7a, 0x0e, 0x97,
+	0x33, 0xcc, 0x71, 0xea, 0x7c, 0x61, 0xd1, 0xcd, 0xf5, 0x16, 0x19, 0x0c, 0xaf, 0x11, 0xd8, 0x08,
+	0x5a, 0x56, 0xb2, 0xd1, 0xa5, 0x5e, 0x31, 0x91, 0xe3, 0x72, 0xec, 0x01, 0xb7, 0x43, 0x83, 0x5f,
+	0x3e, 0xb4, 0x0f, 0xf2, 0xea, 0xd9, 0x0e, 0xe2, 0x08, 0xb6, 0x8b, 0x5d, 0xc0, 0x26, 0x63, 0xa2,
+	0x9e, 0x0b, 0x70, 0xd7, 0x88, 0x6d, 0xe0, 0x5b, 0x68, 0xc7, 0x5a, 0x92, 0x98, 0x5d, 0xa8, 0xec,
+	0x43, 0x0f, 0xdf, 0xc1, 0x95, 0xda, 0x26, 0x36, 0xeb, 0xde, 0x73, 0x01, 0x6b, 0x37, 0x99, 0x6d,
+	0xe0, 0x18, 0xa0, 0x9a, 0x1f, 0xde, 0xfd, 0x2b, 0xad, 0xd8, 0xad, 0x88, 0xfd, 0x0b, 0x52, 0xc8,
+	0xbe, 0xc0, 0xf7, 0xdd, 0xfe, 0x93, 0x3a, 0xf0, 0x64, 0xcb, 0xfc, 0x08, 0xf7, 0xff, 0x0c, 0x00,
+	0xc4, 0x4d, 0x28, 0x53, 0x20, 0x05, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -0,0 +1,560 @@
+package scalers
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	v2beta2 "k8s.io/api/autoscaling/v2beta2"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/metrics/pkg/apis/external_metrics"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	kedautil "github.com/kedacore/keda/pkg/util"
+)
+
+const (
+	forecastScalerType       = "scalerType"
+	forecastLookAhead        = "lookAheadMinutes"
+	forecastHistorySize      = "historySize"
+	forecastModel            = "model"
+	forecastAlpha            = "alpha"
+	forecastBeta             = "beta"
+	forecastGamma            = "gamma"
+	forecastSeasonLength     = "seasonLength"
+	forecastHistoryPath      = "historyPath"
+	forecastModelLinear      = "linearRegression"
+	forecastModelHoltWinters = "holtWinters"
+
+	defaultForecastLookAhead   = 5
+	defaultForecastHistorySize = 100
+	defaultForecastAlpha       = 0.5
+	defaultForecastBeta        = 0.3
+	defaultForecastGamma       = 0.1
+
+	// size of a single sample persisted in the on-disk store: unix nanoseconds + float64 value
+	forecastSampleSize = 16
+
+	// forecastStoreIdleTimeout is the time after which the store of a metric no scaler used anymore is deleted,
+	// e.g. the store of a deleted ScaledObject. The history of the file stores is kept on disk.
+	forecastStoreIdleTimeout = time.Hour
+)
+
+// forecastMetadataKeys are consumed by the forecast scaler, all the other keys are passed to the wrapped scaler
+var forecastMetadataKeys = []string{forecastScalerType, forecastLookAhead, forecastHistorySize, forecastModel,
+	forecastAlpha, forecastBeta, forecastGamma, forecastSeasonLength, forecastHistoryPath}
+
+// ScalerBuilder builds a scaler of the given trigger type with the passed metadata
+type ScalerBuilder func(triggerType string, metadata map[string]string) (Scaler, error)
+
+type forecastScaler struct {
+	metadata *forecastMetadata
+	inner    Scaler
+	store    forecastStore
+	// storeKey is the key of the store in forecastStores
+	storeKey string
+}
+
+type forecastMetadata struct {
+	scalerType    string
+	lookAhead     time.Duration
+	historySize   int
+	model         string
+	alpha         float64
+	beta          float64
+	gamma         float64
+	seasonLength  int
+	historyPath   string
+	innerMetadata map[string]string
+}
+
+type forecastSample struct {
+	timestamp time.Time
+	value     float64
+}
+
+// forecastStore keeps the time series of the values reported by the wrapped scaler.
+// Scalers are rebuilt on every poll, so the stores are shared through forecastStores.
+type forecastStore interface {
+	Append(sample forecastSample) error
+	Samples() ([]forecastSample, error)
+}
+
+// a pool of forecastStoreEntry per ScaledObject and wrapped metric
+var forecastStores sync.Map
+
+// forecastStoreEntry is a store of forecastStores and the time it was last used by a scaler, in unix nanoseconds
+type forecastStoreEntry struct {
+	store    forecastStore
+	lastUsed int64
+}
+
+func (e *forecastStoreEntry) touch() {
+	atomic.StoreInt64(&e.lastUsed, time.Now().UnixNano())
+}
+
+var forecastLog = logf.Log.WithName("forecast_scaler")
+
+// NewForecastScaler creates a new forecastScaler wrapping the scaler built by buildScaler
+func NewForecastScaler(name, namespace string, metadata map[string]string, buildScaler ScalerBuilder) (Scaler, error) {
+	meta, err := parseForecastMetadata(metadata)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing forecast metadata: %s", err)
+	}
+
+	inner, err := buildScaler(meta.scalerType, meta.innerMetadata)
+	if err != nil {
+		return nil, fmt.Errorf("error creating %s scaler for forecast: %s", meta.scalerType, err)
+	}
+
+	key := fmt.Sprintf("%s/%s/%s", namespace, name, getInnerMetricName(inner))
+	store, key := getForecastStore(key, meta)
+	return &forecastScaler{
+		metadata: meta,
+		inner:    inner,
+		store:    store,
+		storeKey: key,
+	}, nil
+}
+
+func parseForecastMetadata(metadata map[string]string) (*forecastMetadata, error) {
+	meta := forecastMetadata{
+		lookAhead:   defaultForecastLookAhead * time.Minute,
+		historySize: defaultForecastHistorySize,
+		model:       forecastModelLinear,
+		alpha:       defaultForecastAlpha,
+		beta:        defaultForecastBeta,
+		gamma:       defaultForecastGamma,
+	}
+
+	if val, ok := metadata[forecastScalerType]; ok && val != "" {
+		if val == "forecast" {
+			return nil, fmt.Errorf("%s can't be forecast", forecastScalerType)
+		}
+		meta.scalerType = val
+	} else {
+		return nil, fmt.Errorf("no %s given", forecastScalerType)
+	}
+
+	if val, ok := metadata[forecastLookAhead]; ok && val != "" {
+		lookAhead, err := strconv.Atoi(val)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing %s: %s", forecastLookAhead, err)
+		}
+		if lookAhead < 0 {
+			return nil, fmt.Errorf("%s can't be negative", forecastLookAhead)
+		}
+		meta.lookAhead = time.Duration(lookAhead) * time.Minute
+	}
+
+	if val, ok := metadata[forecastHistorySize]; ok && val != "" {
+		historySize, err := strconv.Atoi(val)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing %s: %s", forecastHistorySize, err)
+		}
+		if historySize < 2 {
+			return nil, fmt.Errorf("%s must be at least 2", forecastHistorySize)
+		}
+		meta.historySize = historySize
+	}
+
+	if val, ok := metadata[forecastModel]; ok && val != "" {
+		switch val {
+		case forecastModelLinear, forecastModelHoltWinters:
+			meta.model = val
+		default:
+			return nil, fmt.Errorf("unknown %s %s, supported are %s and %s", forecastModel, val, forecastModelLinear, forecastModelHoltWinters)
+		}
+	}
+
+	for key, target := range map[string]*float64{forecastAlpha: &meta.alpha, forecastBeta: &meta.beta, forecastGamma: &meta.gamma} {
+		if val, ok := metadata[key]; ok && val != "" {
+			factor, err := strconv.ParseFloat(val, 64)
+			if err != nil {
+				return nil, fmt.Errorf("error parsing %s: %s", key, err)
+			}
+			if factor < 0 || factor > 1 {
+				return nil, fmt.Errorf("%s must be between 0 and 1", key)
+			}
+			*target = factor
+		}
+	}
+
+	if val, ok := metadata[forecastSeasonLength]; ok && val != "" {
+		seasonLength, err := strconv.Atoi(val)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing %s: %s", forecastSeasonLength, err)
+		}
+		if seasonLength < 0 {
+			return nil, fmt.Errorf("%s can't be negative", forecastSeasonLength)
+		}
+		meta.seasonLength = seasonLength
+	}
+
+	if val, ok := metadata[forecastHistoryPath]; ok && val != "" {
+		meta.historyPath = val
+	}
+
+	meta.innerMetadata = make(map[string]string)
+	for key, val := range metadata {
+		meta.innerMetadata[key] = val
+	}
+	for _, key := range forecastMetadataKeys {
+		delete(meta.innerMetadata, key)
+	}
+
+	return &meta, nil
+}
+
+func getInnerMetricName(inner Scaler) string {
+	metricSpecs := inner.GetMetricSpecForScaling()
+	if len(metricSpecs) == 0 || metricSpecs[0].External == nil {
+		return ""
+	}
+	return metricSpecs[0].External.Metric.Name
+}
+
+// getForecastStore returns the store of the key and the key of the store in forecastStores
+func getForecastStore(key string, meta *forecastMetadata) (forecastStore, string) {
+	var store forecastStore
+	if meta.historyPath != "" {
+		key = meta.historyPath + ":" + key
+		store = &fileForecastStore{
+			path: filepath.Join(meta.historyPath, kedautil.NormalizeString(key)+".bin"),
+			size: meta.historySize,
+		}
+	} else {
+		store = &memoryForecastStore{size: meta.historySize}
+	}
+
+	value, _ := forecastStores.LoadOrStore(key, &forecastStoreEntry{store: store})
+	entry := value.(*forecastStoreEntry)
+	entry.touch()
+	return entry.store, key
+}
+
+// releaseForecastStores deletes the stores of forecastStores not used for forecastStoreIdleTimeout
+func releaseForecastStores() {
+	idleSince := time.Now().Add(-forecastStoreIdleTimeout).UnixNano()
+	forecastStores.Range(func(key, value interface{}) bool {
+		if atomic.LoadInt64(&value.(*forecastStoreEntry).lastUsed) < idleSince {
+			forecastStores.Delete(key)
+		}
+		return true
+	})
+}
+
+// IsActive returns true if the wrapped scaler is active or if the forecasted value is greater than 0
+func (s *forecastScaler) IsActive(ctx context.Context) (bool, error) {
+	isActive, err := s.inner.IsActive(ctx)
+	if err != nil {
+		return false, err
+	}
+	if isActive {
+		return true, nil
+	}
+
+	forecast, err := s.forecast()
+	if err != nil {
+		forecastLog.Error(err, "error computing forecast")
+		return false, err
+	}
+
+	return forecast > 0, nil
+}
+
+// Close closes the wrapped scaler. The scalers are rebuilt on every poll so the store of the scaler is kept,
+// the stores not used anymore are deleted from forecastStores.
+func (s *forecastScaler) Close() error {
+	if value, ok := forecastStores.Load(s.storeKey); ok {
+		value.(*forecastStoreEntry).touch()
+	}
+	releaseForecastStores()
+	return s.inner.Close()
+}
+
+// GetMetricSpecForScaling returns the MetricSpec of the wrapped scaler under a forecast specific name
+func (s *forecastScaler) GetMetricSpecForScaling() []v2beta2.MetricSpec {
+	metricSpecs := s.inner.GetMetricSpecForScaling()
+	for _, metricSpec := range metricSpecs {
+		if metricSpec.External != nil {
+			metricSpec.External.Metric.Name = forecastMetricName(metricSpec.External.Metric.Name)
+		}
+	}
+	return metricSpecs
+}
+
+// GetMetrics records the current value of the wrapped scaler and returns the greater of the current and the forecasted value,
+// the wrapped scaler is queried with the name of its own metric
+func (s *forecastScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
+	metrics, err := s.inner.GetMetrics(ctx, s.innerMetricName(metricName), metricSelector)
+	if err != nil {
+		return []external_metrics.ExternalMetricValue{}, err
+	}
+
+	var current float64
+	for _, metric := range metrics {
+		current += float64(metric.Value.MilliValue()) / 1000
+	}
+
+	if err := s.store.Append(forecastSample{timestamp: time.Now(), value: current}); err != nil {
+		forecastLog.Error(err, "error recording metric value", "metricName", metricName)
+	}
+
+	forecast, err := s.forecast()
+	if err != nil {
+		forecastLog.Error(err, "error computing forecast, using current value", "metricName", metricName)
+		forecast = current
+	}
+
+	metric := external_metrics.ExternalMetricValue{
+		MetricName: metricName,
+		Value:      *resource.NewQuantity(int64(math.Ceil(math.Max(current, forecast))), resource.DecimalSI),
+		Timestamp:  metav1.Now(),
+	}
+
+	return append([]external_metrics.ExternalMetricValue{}, metric), nil
+}
+
+// innerMetricName returns the name of the metric of the wrapped scaler served under the forecast specific name,
+// the name itself if the wrapped scaler has no such metric
+func (s *forecastScaler) innerMetricName(metricName string) string {
+	for _, metricSpec := range s.inner.GetMetricSpecForScaling() {
+		if metricSpec.External != nil && strings.EqualFold(forecastMetricName(metricSpec.External.Metric.Name), metricName) {
+			return metricSpec.External.Metric.Name
+		}
+	}
+	return metricName
+}
+
+func forecastMetricName(innerMetricName string) string {
+	return kedautil.NormalizeString(fmt.Sprintf("%s-%s", "forecast", innerMetricName))
+}
+
+// forecast returns the value expected lookAhead from now, based on the recorded history
+func (s *forecastScaler) forecast() (float64, error) {
+	samples, err := s.store.Samples()
+	if err != nil {
+		return 0, err
+	}
+
+	var forecast float64
+	switch s.metadata.model {
+	case forecastModelHoltWinters:
+		forecast = holtWintersForecast(samples, s.metadata.seasonLength, s.metadata.alpha, s.metadata.beta, s.metadata.gamma, s.metadata.lookAhead)
+	default:
+		forecast = linearRegressionForecast(samples, time.Now().Add(s.metadata.lookAhead))
+	}
+
+	return math.Max(forecast, 0), nil
+}
+
+// linearRegressionForecast fits a least squares line through the samples and evaluates it at the given time
+func linearRegressionForecast(samples []forecastSample, at time.Time) float64 {
+	if len(samples) == 0 {
+		return 0
+	}
+	if len(samples) == 1 {
+		return samples[0].value
+	}
+
+	origin := samples[0].timestamp
+	n := float64(len(samples))
+	var sumX, sumY, sumXY, sumXX float64
+	for _, sample := range samples {
+		x := sample.timestamp.Sub(origin).Seconds()
+		sumX += x
+		sumY += sample.value
+		sumXY += x * sample.value
+		sumXX += x * x
+	}
+
+	denominator := n*sumXX - sumX*sumX
+	if denominator == 0 {
+		return sumY / n
+	}
+	slope := (n*sumXY - sumX*sumY) / denominator
+	intercept := (sumY - slope*sumX) / n
+
+	return intercept + slope*at.Sub(origin).Seconds()
+}
+
+// holtWintersForecast applies additive Holt-Winters smoothing to the samples, which are treated as evenly spaced,
+// and returns the value lookAhead after the last sample. Without a season (or with less than two seasons of
+// history) it falls back to Holt's double exponential smoothing.
+func holtWintersForecast(samples []forecastSample, seasonLength int, alpha, beta, gamma float64, lookAhead time.Duration) float64 {
+	if len(samples) == 0 {
+		return 0
+	}
+	if len(samples) == 1 {
+		return samples[0].value
+	}
+
+	step := samples[len(samples)-1].timestamp.Sub(samples[0].timestamp) / time.Duration(len(samples)-1)
+	steps := 1
+	if step > 0 {
+		steps = int(math.Ceil(float64(lookAhead) / float64(step)))
+	}
+
+	series := make([]float64, len(samples))
+	for i, sample := range samples {
+		series[i] = sample.value
+	}
+
+	if seasonLength < 2 || len(series) < 2*seasonLength {
+		level := series[0]
+		trend := series[1] - series[0]
+		for i := 1; i < len(series); i++ {
+			lastLevel := level
+			level = alpha*series[i] + (1-alpha)*(level+trend)
+			trend = beta*(level-lastLevel) + (1-beta)*trend
+		}
+		return level + float64(steps)*trend
+	}
+
+	seasonals := initialSeasonalComponents(series, seasonLength)
+	var trend float64
+	for i := 0; i < seasonLength; i++ {
+		trend += (series[seasonLength+i] - series[i]) / float64(seasonLength)
+	}
+	trend /= float64(seasonLength)
+
+	level := series[0]
+	for i := 1; i < len(series); i++ {
+		lastLevel := level
+		season := i % seasonLength
+		level = alpha*(series[i]-seasonals[season]) + (1-alpha)*(level+trend)
+		trend = beta*(level-lastLevel) + (1-beta)*trend
+		seasonals[season] = gamma*(series[i]-level) + (1-gamma)*seasonals[season]
+	}
+
+	return level + float64(steps)*trend + seasonals[(len(series)-1+steps)%seasonLength]
+}
+
+func initialSeasonalComponents(series []float64, seasonLength int) []float64 {
+	seasons := len(series) / seasonLength
+	averages := make([]float64, seasons)
+	for j := 0; j < seasons; j++ {
+		var sum float64
+		for i := 0; i < seasonLength; i++ {
+			sum += series[j*seasonLength+i]
+		}
+		averages[j] = sum / float64(seasonLength)
+	}
+
+	seasonals := make([]float64, seasonLength)
+	for i := 0; i < seasonLength; i++ {
+		var sum float64
+		for j := 0; j < seasons; j++ {
+			sum += series[j*seasonLength+i] - averages[j]
+		}
+		seasonals[i] = sum / float64(seasons)
+	}
+	return seasonals
+}
+
+// memoryForecastStore keeps the last size samples in memory
+type memoryForecastStore struct {
+	mutex   sync.Mutex
+	size    int
+	samples []forecastSample
+}
+
+func (m *memoryForecastStore) Append(sample forecastSample) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.samples = append(m.samples, sample)
+	if len(m.samples) > m.size {
+		m.samples = m.samples[len(m.samples)-m.size:]
+	}
+	return nil
+}
+
+func (m *memoryForecastStore) Samples() ([]forecastSample, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return append([]forecastSample{}, m.samples...), nil
+}
+
+// fileForecastStore appends samples to a file as fixed size binary records,
+// the file is compacted to the last size samples once it holds twice as many
+type fileForecastStore struct {
+	mutex sync.Mutex
+	path  string
+	size  int
+}
+
+func (f *fileForecastStore) Append(sample forecastSample) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	samples, err := f.read()
+	if err != nil {
+		return err
+	}
+	if len(samples) >= 2*f.size {
+		samples = append(samples[len(samples)-f.size+1:], sample)
+		return ioutil.WriteFile(f.path, encodeForecastSamples(samples), 0600)
+	}
+
+	file, err := os.OpenFile(f.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = file.Write(encodeForecastSamples([]forecastSample{sample}))
+	return err
+}
+
+func (f *fileForecastStore) Samples() ([]forecastSample, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	samples, err := f.read()
+	if err != nil {
+		return nil, err
+	}
+	if len(samples) > f.size {
+		samples = samples[len(samples)-f.size:]
+	}
+	return samples, nil
+}
+
+func (f *fileForecastStore) read() ([]forecastSample, error) {
+	data, err := ioutil.ReadFile(f.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	samples := make([]forecastSample, 0, len(data)/forecastSampleSize)
+	for i := 0; i+forecastSampleSize <= len(data); i += forecastSampleSize {
+		samples = append(samples, forecastSample{
+			timestamp: time.Unix(0, int64(binary.LittleEndian.Uint64(data[i:]))),
+			value:     math.Float64frombits(binary.LittleEndian.Uint64(data[i+8:])),
+		})
+	}
+	return samples, nil
+}
+
+func encodeForecastSamples(samples []forecastSample) []byte {
+	data := make([]byte, len(samples)*forecastSampleSize)
+	for i, sample := range samples {
+		binary.LittleEndian.PutUint64(data[i*forecastSampleSize:], uint64(sample.timestamp.UnixNano()))
+		binary.LittleEndian.PutUint64(data[i*forecastSampleSize+8:], math.Float64bits(sample.value))
+	}
+	return data
+}
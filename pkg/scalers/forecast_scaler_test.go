@@ -0,0 +1,196 @@
+package scalers
+
+import (
+	"context"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/metrics/pkg/apis/external_metrics"
+)
+
+type parseForecastMetadataTestData struct {
+	metadata map[string]string
+	isError  bool
+}
+
+var testForecastMetadata = []parseForecastMetadataTestData{
+	// nothing passed
+	{map[string]string{}, true},
+	// properly formed
+	{map[string]string{"scalerType": "cron", "lookAheadMinutes": "10", "historySize": "50", "timezone": "Etc/UTC"}, false},
+	// holtWinters with season
+	{map[string]string{"scalerType": "cron", "model": "holtWinters", "seasonLength": "12", "alpha": "0.2", "beta": "0.1", "gamma": "0.3"}, false},
+	// forecast wrapping forecast
+	{map[string]string{"scalerType": "forecast"}, true},
+	// unknown model
+	{map[string]string{"scalerType": "cron", "model": "arima"}, true},
+	// malformed lookAheadMinutes
+	{map[string]string{"scalerType": "cron", "lookAheadMinutes": "five"}, true},
+	// historySize too small
+	{map[string]string{"scalerType": "cron", "historySize": "1"}, true},
+	// smoothing factor out of range
+	{map[string]string{"scalerType": "cron", "alpha": "1.5"}, true},
+}
+
+func TestForecastParseMetadata(t *testing.T) {
+	for _, testData := range testForecastMetadata {
+		_, err := parseForecastMetadata(testData.metadata)
+		if err != nil && !testData.isError {
+			t.Error("Expected success but got error", err)
+		}
+		if testData.isError && err == nil {
+			t.Error("Expected error but got success")
+		}
+	}
+}
+
+func TestForecastInnerMetadata(t *testing.T) {
+	meta, err := parseForecastMetadata(testForecastMetadata[1].metadata)
+	if err != nil {
+		t.Fatal("Could not parse metadata:", err)
+	}
+	assert.Equal(t, map[string]string{"timezone": "Etc/UTC"}, meta.innerMetadata)
+	assert.Equal(t, 10*time.Minute, meta.lookAhead)
+}
+
+func TestLinearRegressionForecast(t *testing.T) {
+	origin := time.Now()
+	samples := make([]forecastSample, 10)
+	for i := range samples {
+		samples[i] = forecastSample{timestamp: origin.Add(time.Duration(i) * time.Minute), value: float64(2 * i)}
+	}
+
+	forecast := linearRegressionForecast(samples, origin.Add(14*time.Minute))
+	assert.InDelta(t, 28, forecast, 0.0001)
+}
+
+func TestHoltWintersForecastWithoutSeason(t *testing.T) {
+	origin := time.Now()
+	samples := make([]forecastSample, 10)
+	for i := range samples {
+		samples[i] = forecastSample{timestamp: origin.Add(time.Duration(i) * time.Minute), value: float64(i)}
+	}
+
+	forecast := holtWintersForecast(samples, 0, 0.5, 0.5, 0.1, 5*time.Minute)
+	assert.InDelta(t, 14, forecast, 0.0001)
+}
+
+func TestHoltWintersForecastWithSeason(t *testing.T) {
+	origin := time.Now()
+	season := []float64{10, 20, 30, 20}
+	samples := make([]forecastSample, 4*len(season))
+	for i := range samples {
+		samples[i] = forecastSample{timestamp: origin.Add(time.Duration(i) * time.Minute), value: season[i%len(season)]}
+	}
+
+	// the next sample starts a new season
+	forecast := holtWintersForecast(samples, len(season), 0.5, 0.1, 0.5, time.Minute)
+	assert.InDelta(t, 10, forecast, 1)
+}
+
+func TestForecastScalerGetMetrics(t *testing.T) {
+	inner, err := NewCronScaler(map[string]string{}, validCronMetadata)
+	if err != nil {
+		t.Fatal("Could not create inner scaler:", err)
+	}
+
+	s := &forecastScaler{
+		metadata: &forecastMetadata{model: forecastModelLinear, lookAhead: time.Minute, historySize: 10},
+		inner:    inner,
+		store:    &memoryForecastStore{size: 10},
+	}
+
+	metrics, err := s.GetMetrics(context.TODO(), "ReplicaCount", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "ReplicaCount", metrics[0].MetricName)
+
+	samples, _ := s.store.Samples()
+	assert.Equal(t, 1, len(samples))
+
+	metricName := s.GetMetricSpecForScaling()[0].External.Metric.Name
+	assert.Equal(t, "forecast-cron-Etc-UTC-00xxThu-5923xxThu", metricName)
+}
+
+type metricNameRecordingScaler struct {
+	metricSpecScaler
+	metricNames []string
+}
+
+func (s *metricNameRecordingScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
+	s.metricNames = append(s.metricNames, metricName)
+	return s.metricSpecScaler.GetMetrics(ctx, metricName, metricSelector)
+}
+
+func TestForecastScalerQueriesTheInnerMetric(t *testing.T) {
+	inner := &metricNameRecordingScaler{metricSpecScaler: metricSpecScaler{fixedValueScaler{values: []int64{7}}}}
+	s := &forecastScaler{
+		metadata: &forecastMetadata{model: forecastModelLinear, lookAhead: time.Minute, historySize: 10},
+		inner:    inner,
+		store:    &memoryForecastStore{size: 10},
+	}
+
+	metricName := s.GetMetricSpecForScaling()[0].External.Metric.Name
+	assert.Equal(t, "forecast-queue", metricName)
+	metrics, err := s.GetMetrics(context.TODO(), metricName, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"queue"}, inner.metricNames)
+	assert.Equal(t, metricName, metrics[0].MetricName)
+	assert.Equal(t, int64(7), metrics[0].Value.Value())
+}
+
+func TestForecastScalerCloseReleasesIdleStores(t *testing.T) {
+	meta := &forecastMetadata{historySize: 10}
+	usedStore, usedKey := getForecastStore("default/used/queue", meta)
+	_, idleKey := getForecastStore("default/deleted/queue", meta)
+	defer forecastStores.Delete(usedKey)
+	defer forecastStores.Delete(idleKey)
+
+	value, _ := forecastStores.Load(idleKey)
+	atomic.StoreInt64(&value.(*forecastStoreEntry).lastUsed, time.Now().Add(-2*forecastStoreIdleTimeout).UnixNano())
+	value, _ = forecastStores.Load(usedKey)
+	atomic.StoreInt64(&value.(*forecastStoreEntry).lastUsed, time.Now().Add(-2*forecastStoreIdleTimeout).UnixNano())
+
+	s := &forecastScaler{metadata: meta, inner: &metricSpecScaler{}, store: usedStore, storeKey: usedKey}
+	assert.NoError(t, s.Close())
+	if _, ok := forecastStores.Load(idleKey); ok {
+		t.Error("Expected the idle store to be deleted")
+	}
+	if _, ok := forecastStores.Load(usedKey); !ok {
+		t.Error("Expected the store of the closed scaler to be kept")
+	}
+}
+
+func TestForecastStoresKeepHistorySize(t *testing.T) {
+	dir, err := ioutil.TempDir("", "forecast")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	stores := []forecastStore{
+		&memoryForecastStore{size: 3},
+		&fileForecastStore{path: filepath.Join(dir, "history.bin"), size: 3},
+	}
+
+	origin := time.Unix(1600000000, 0)
+	for _, store := range stores {
+		for i := 0; i < 10; i++ {
+			err := store.Append(forecastSample{timestamp: origin.Add(time.Duration(i) * time.Second), value: float64(i) + 0.5})
+			assert.NoError(t, err)
+		}
+
+		samples, err := store.Samples()
+		assert.NoError(t, err)
+		assert.Equal(t, 3, len(samples))
+		assert.Equal(t, 9.5, samples[2].value)
+		assert.True(t, samples[2].timestamp.Equal(origin.Add(9*time.Second)))
+		assert.False(t, math.IsNaN(samples[0].value))
+	}
+}
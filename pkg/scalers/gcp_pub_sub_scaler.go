@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"strings"
 
 	v2beta2 "k8s.io/api/autoscaling/v2beta2"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -16,8 +17,12 @@ import (
 )
 
 const (
-	defaultTargetSubscriptionSize = 5
-	pubSubStackDriverMetricName   = "pubsub.googleapis.com/subscription/num_undelivered_messages"
+	defaultTargetSubscriptionSize  = 5
+	pubSubStackDriverMetricName    = "pubsub.googleapis.com/subscription/num_undelivered_messages"
+	pubSubStackDriverAgeMetricName = "pubsub.googleapis.com/subscription/oldest_unacked_message_age"
+
+	pubSubModeSubscriptionSize        = "SubscriptionSize"
+	pubSubModeOldestUnackedMessageAge = "OldestUnackedMessageAge"
 )
 
 type pubsubScaler struct {
@@ -26,9 +31,12 @@ type pubsubScaler struct {
 }
 
 type pubsubMetadata struct {
-	targetSubscriptionSize int
-	subscriptionName       string
-	credentials            string
+	// mode is the metric of the subscription the scaler scales on, its size or the age in seconds of its oldest unacked message,
+	// value is the target of the metric
+	mode             string
+	value            int
+	subscriptionName string
+	credentials      string
 }
 
 var gcpPubSubLog = logf.Log.WithName("gcp_pub_sub_scaler")
@@ -47,15 +55,42 @@ func NewPubSubScaler(resolvedEnv, metadata map[string]string) (Scaler, error) {
 
 func parsePubSubMetadata(metadata, resolvedEnv map[string]string) (*pubsubMetadata, error) {
 	meta := pubsubMetadata{}
-	meta.targetSubscriptionSize = defaultTargetSubscriptionSize
+	meta.mode = pubSubModeSubscriptionSize
+	meta.value = defaultTargetSubscriptionSize
+
+	if val, ok := metadata["mode"]; ok && val != "" {
+		switch {
+		case strings.EqualFold(val, pubSubModeSubscriptionSize):
+			meta.mode = pubSubModeSubscriptionSize
+		case strings.EqualFold(val, pubSubModeOldestUnackedMessageAge):
+			meta.mode = pubSubModeOldestUnackedMessageAge
+		default:
+			return nil, fmt.Errorf("mode %s not supported, supported modes are %s and %s", val, pubSubModeSubscriptionSize, pubSubModeOldestUnackedMessageAge)
+		}
+	}
 
 	if val, ok := metadata["subscriptionSize"]; ok {
+		if meta.mode != pubSubModeSubscriptionSize {
+			return nil, fmt.Errorf("subscriptionSize is only supported with mode %s, value is the target of mode %s", pubSubModeSubscriptionSize, meta.mode)
+		}
+		if _, ok := metadata["value"]; ok {
+			return nil, fmt.Errorf("only one of subscriptionSize or value can be given")
+		}
 		subscriptionSize, err := strconv.Atoi(val)
 		if err != nil {
 			return nil, fmt.Errorf("Subscription Size parsing error %s", err.Error())
 		}
 
-		meta.targetSubscriptionSize = subscriptionSize
+		meta.value = subscriptionSize
+	}
+
+	if val, ok := metadata["value"]; ok {
+		value, err := strconv.Atoi(val)
+		if err != nil {
+			return nil, fmt.Errorf("value parsing error %s", err.Error())
+		}
+
+		meta.value = value
 	}
 
 	if val, ok := metadata["subscriptionName"]; ok {
@@ -79,16 +114,16 @@ func parsePubSubMetadata(metadata, resolvedEnv map[string]string) (*pubsubMetada
 	return &meta, nil
 }
 
-// IsActive checks if there are any messages in the subscription
+// IsActive checks if there are any messages in the subscription, or unacked messages older than 0 seconds
 func (s *pubsubScaler) IsActive(ctx context.Context) (bool, error) {
-	size, err := s.GetSubscriptionSize(ctx)
+	value, err := s.getMetricValue(ctx)
 
 	if err != nil {
 		gcpPubSubLog.Error(err, "error getting Active Status")
 		return false, err
 	}
 
-	return size > 0, nil
+	return value > 0, nil
 }
 
 func (s *pubsubScaler) Close() error {
@@ -104,16 +139,22 @@ func (s *pubsubScaler) Close() error {
 
 // GetMetricSpecForScaling returns the metric spec for the HPA
 func (s *pubsubScaler) GetMetricSpecForScaling() []v2beta2.MetricSpec {
-	// Construct the target subscription size as a quantity
-	targetSubscriptionSizeQty := resource.NewQuantity(int64(s.metadata.targetSubscriptionSize), resource.DecimalSI)
+	// Construct the target value of the mode as a quantity
+	targetValueQty := resource.NewQuantity(int64(s.metadata.value), resource.DecimalSI)
+
+	// the metric name of the subscription size is kept for the existing HPAs
+	metricName := fmt.Sprintf("%s-%s", "gcp", s.metadata.subscriptionName)
+	if s.metadata.mode == pubSubModeOldestUnackedMessageAge {
+		metricName = fmt.Sprintf("%s-%s-%s", "gcp", strings.ToLower(s.metadata.mode), s.metadata.subscriptionName)
+	}
 
 	externalMetric := &v2beta2.ExternalMetricSource{
 		Metric: v2beta2.MetricIdentifier{
-			Name: kedautil.NormalizeString(fmt.Sprintf("%s-%s", "gcp", s.metadata.subscriptionName)),
+			Name: kedautil.NormalizeString(metricName),
 		},
 		Target: v2beta2.MetricTarget{
 			Type:         v2beta2.AverageValueMetricType,
-			AverageValue: targetSubscriptionSizeQty,
+			AverageValue: targetValueQty,
 		},
 	}
 
@@ -126,34 +167,53 @@ func (s *pubsubScaler) GetMetricSpecForScaling() []v2beta2.MetricSpec {
 	return []v2beta2.MetricSpec{metricSpec}
 }
 
-// GetMetrics connects to Stack Driver and finds the size of the pub sub subscription
+// GetMetrics connects to Stack Driver and finds the size of the pub sub subscription or the age of its oldest unacked message
 func (s *pubsubScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
-	size, err := s.GetSubscriptionSize(ctx)
+	value, err := s.getMetricValue(ctx)
 
 	if err != nil {
-		gcpPubSubLog.Error(err, "error getting subscription size")
+		gcpPubSubLog.Error(err, "error getting subscription metric", "mode", s.metadata.mode)
 		return []external_metrics.ExternalMetricValue{}, err
 	}
 
 	metric := external_metrics.ExternalMetricValue{
 		MetricName: metricName,
-		Value:      *resource.NewQuantity(size, resource.DecimalSI),
+		Value:      *resource.NewQuantity(value, resource.DecimalSI),
 		Timestamp:  metav1.Now(),
 	}
 
 	return append([]external_metrics.ExternalMetricValue{}, metric), nil
 }
 
+func (s *pubsubScaler) getMetricValue(ctx context.Context) (int64, error) {
+	if s.metadata.mode == pubSubModeOldestUnackedMessageAge {
+		return s.GetOldestUnackedMessageAge(ctx)
+	}
+	return s.GetSubscriptionSize(ctx)
+}
+
 // GetSubscriptionSize gets the number of messages in a subscription by calling the
 // Stackdriver api
 func (s *pubsubScaler) GetSubscriptionSize(ctx context.Context) (int64, error) {
+	return s.getSubscriptionMetric(ctx, pubSubStackDriverMetricName)
+}
+
+// GetOldestUnackedMessageAge gets the age in seconds of the oldest unacked message of a subscription by calling the
+// Stackdriver api
+func (s *pubsubScaler) GetOldestUnackedMessageAge(ctx context.Context) (int64, error) {
+	return s.getSubscriptionMetric(ctx, pubSubStackDriverAgeMetricName)
+}
+
+func (s *pubsubScaler) getSubscriptionMetric(ctx context.Context, metricType string) (int64, error) {
 	client, err := NewStackDriverClient(ctx, s.metadata.credentials)
 	if err != nil {
 		return -1, err
 	}
 	s.client = client
 
-	filter := `metric.type="` + pubSubStackDriverMetricName + `" AND resource.labels.subscription_id="` + s.metadata.subscriptionName + `"`
+	return client.GetMetrics(ctx, pubSubMetricFilter(metricType, s.metadata.subscriptionName))
+}
 
-	return client.GetMetrics(ctx, filter)
+func pubSubMetricFilter(metricType, subscriptionName string) string {
+	return `metric.type="` + metricType + `" AND resource.labels.subscription_id="` + subscriptionName + `"`
 }
@@ -30,10 +30,24 @@ var testPubSubMetadata = []parsePubSubMetadataTestData{
 	{map[string]string{"subscriptionName": "mysubscription", "subscriptionSize": "7", "credentialsFromEnv": "WRONG_CREDS"}, true},
 	// malformed subscriptionSize
 	{map[string]string{"subscriptionName": "mysubscription", "subscriptionSize": "AA", "credentialsFromEnv": "SAMPLE_CREDS"}, true},
+	// oldest unacked message age
+	{map[string]string{"subscriptionName": "mysubscription", "mode": "OldestUnackedMessageAge", "value": "30", "credentialsFromEnv": "SAMPLE_CREDS"}, false},
+	// subscription size with value
+	{map[string]string{"subscriptionName": "mysubscription", "mode": "subscriptionsize", "value": "7", "credentialsFromEnv": "SAMPLE_CREDS"}, false},
+	// unknown mode
+	{map[string]string{"subscriptionName": "mysubscription", "mode": "Throughput", "credentialsFromEnv": "SAMPLE_CREDS"}, true},
+	// subscriptionSize with oldest unacked message age
+	{map[string]string{"subscriptionName": "mysubscription", "mode": "OldestUnackedMessageAge", "subscriptionSize": "7", "credentialsFromEnv": "SAMPLE_CREDS"}, true},
+	// both subscriptionSize and value
+	{map[string]string{"subscriptionName": "mysubscription", "subscriptionSize": "7", "value": "7", "credentialsFromEnv": "SAMPLE_CREDS"}, true},
+	// malformed value
+	{map[string]string{"subscriptionName": "mysubscription", "mode": "OldestUnackedMessageAge", "value": "AA", "credentialsFromEnv": "SAMPLE_CREDS"}, true},
 }
 
 var gcpPubSubMetricIdentifiers = []gcpPubSubMetricIdentifier{
 	{&testPubSubMetadata[1], "gcp-mysubscription"},
+	{&testPubSubMetadata[6], "gcp-oldestunackedmessageage-mysubscription"},
+	{&testPubSubMetadata[7], "gcp-mysubscription"},
 }
 
 func TestPubSubParseMetadata(t *testing.T) {
@@ -63,3 +77,10 @@ func TestGcpPubSubGetMetricSpecForScaling(t *testing.T) {
 		}
 	}
 }
+
+func TestPubSubMetricFilter(t *testing.T) {
+	expected := `metric.type="pubsub.googleapis.com/subscription/oldest_unacked_message_age" AND resource.labels.subscription_id="mysubscription"`
+	if filter := pubSubMetricFilter(pubSubStackDriverAgeMetricName, "mysubscription"); filter != expected {
+		t.Error("Wrong filter:", filter)
+	}
+}
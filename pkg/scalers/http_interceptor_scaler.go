@@ -0,0 +1,174 @@
+package scalers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	v2beta2 "k8s.io/api/autoscaling/v2beta2"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/metrics/pkg/apis/external_metrics"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	kedautil "github.com/kedacore/keda/pkg/util"
+)
+
+const (
+	httpInterceptorHost                  = "host"
+	httpInterceptorAddress               = "interceptorAddress"
+	httpInterceptorTargetPendingRequests = "targetPendingRequests"
+	httpInterceptorQueuePath             = "/queue"
+	defaultHTTPTargetPendingRequests     = 100
+	httpInterceptorTimeout               = 5 * time.Second
+)
+
+type httpInterceptorScaler struct {
+	metadata   *httpInterceptorMetadata
+	httpClient *http.Client
+}
+
+type httpInterceptorMetadata struct {
+	host                  string
+	interceptorAddress    string
+	targetPendingRequests int
+	retryPolicy           kedautil.RetryPolicy
+}
+
+var httpInterceptorLog = logf.Log.WithName("http_interceptor_scaler")
+
+// NewHTTPInterceptorScaler creates a new httpInterceptorScaler
+func NewHTTPInterceptorScaler(metadata map[string]string) (Scaler, error) {
+	meta, err := parseHTTPInterceptorMetadata(metadata)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing http interceptor metadata: %s", err)
+	}
+
+	return &httpInterceptorScaler{
+		metadata:   meta,
+		httpClient: &http.Client{Timeout: httpInterceptorTimeout},
+	}, nil
+}
+
+func parseHTTPInterceptorMetadata(metadata map[string]string) (*httpInterceptorMetadata, error) {
+	meta := httpInterceptorMetadata{
+		targetPendingRequests: defaultHTTPTargetPendingRequests,
+	}
+
+	if val, ok := metadata[httpInterceptorHost]; ok && val != "" {
+		meta.host = val
+	} else {
+		return nil, fmt.Errorf("no %s given", httpInterceptorHost)
+	}
+
+	if val, ok := metadata[httpInterceptorAddress]; ok && val != "" {
+		meta.interceptorAddress = strings.TrimSuffix(val, "/")
+	} else {
+		return nil, fmt.Errorf("no %s given", httpInterceptorAddress)
+	}
+
+	if val, ok := metadata[httpInterceptorTargetPendingRequests]; ok && val != "" {
+		targetPendingRequests, err := strconv.Atoi(val)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing %s: %s", httpInterceptorTargetPendingRequests, err)
+		}
+		if targetPendingRequests <= 0 {
+			return nil, fmt.Errorf("%s must be greater than 0", httpInterceptorTargetPendingRequests)
+		}
+		meta.targetPendingRequests = targetPendingRequests
+	}
+
+	retryPolicy, err := parseHTTPRetryPolicy(metadata)
+	if err != nil {
+		return nil, err
+	}
+	meta.retryPolicy = retryPolicy
+
+	return &meta, nil
+}
+
+// IsActive returns true if there are requests pending in the interceptor for the host
+func (s *httpInterceptorScaler) IsActive(ctx context.Context) (bool, error) {
+	pendingRequests, err := s.getPendingRequests(ctx)
+	if err != nil {
+		httpInterceptorLog.Error(err, "error getting pending requests from interceptor")
+		return false, err
+	}
+
+	return pendingRequests > 0, nil
+}
+
+// Close does nothing in case of httpInterceptorScaler
+func (s *httpInterceptorScaler) Close() error {
+	return nil
+}
+
+// GetMetricSpecForScaling returns the metric spec for the HPA
+func (s *httpInterceptorScaler) GetMetricSpecForScaling() []v2beta2.MetricSpec {
+	targetMetricValue := resource.NewQuantity(int64(s.metadata.targetPendingRequests), resource.DecimalSI)
+	externalMetric := &v2beta2.ExternalMetricSource{
+		Metric: v2beta2.MetricIdentifier{
+			Name: kedautil.NormalizeString(fmt.Sprintf("%s-%s", "http", s.metadata.host)),
+		},
+		Target: v2beta2.MetricTarget{
+			Type:         v2beta2.AverageValueMetricType,
+			AverageValue: targetMetricValue,
+		},
+	}
+	metricSpec := v2beta2.MetricSpec{External: externalMetric, Type: externalMetricType}
+	return []v2beta2.MetricSpec{metricSpec}
+}
+
+// GetMetrics returns the number of requests pending in the interceptor for the host
+func (s *httpInterceptorScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
+	pendingRequests, err := s.getPendingRequests(ctx)
+	if err != nil {
+		httpInterceptorLog.Error(err, "error getting pending requests from interceptor")
+		return []external_metrics.ExternalMetricValue{}, err
+	}
+
+	metric := external_metrics.ExternalMetricValue{
+		MetricName: metricName,
+		Value:      *resource.NewQuantity(pendingRequests, resource.DecimalSI),
+		Timestamp:  metav1.Now(),
+	}
+
+	return append([]external_metrics.ExternalMetricValue{}, metric), nil
+}
+
+// getPendingRequests queries the interceptor admin endpoint, which returns the pending request counts per host
+func (s *httpInterceptorScaler) getPendingRequests(ctx context.Context) (int64, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", s.metadata.interceptorAddress+httpInterceptorQueuePath, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	r, err := kedautil.DoWithRetry(s.httpClient, req, s.metadata.retryPolicy)
+	if err != nil {
+		return 0, err
+	}
+	defer r.Body.Close()
+
+	if r.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("interceptor returned %d", r.StatusCode)
+	}
+
+	b, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return 0, err
+	}
+
+	var counts map[string]int64
+	if err = json.Unmarshal(b, &counts); err != nil {
+		return 0, fmt.Errorf("error parsing interceptor response: %s", err)
+	}
+
+	// hosts without pending requests are not reported
+	return counts[s.metadata.host], nil
+}
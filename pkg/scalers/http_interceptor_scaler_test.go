@@ -0,0 +1,102 @@
+package scalers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type parseHTTPInterceptorMetadataTestData struct {
+	metadata map[string]string
+	isError  bool
+}
+
+type httpInterceptorMetricIdentifier struct {
+	metadataTestData *parseHTTPInterceptorMetadataTestData
+	name             string
+}
+
+var testHTTPInterceptorMetadata = []parseHTTPInterceptorMetadataTestData{
+	// nothing passed
+	{map[string]string{}, true},
+	// properly formed
+	{map[string]string{"host": "example.com", "interceptorAddress": "http://interceptor:9090", "targetPendingRequests": "50"}, false},
+	// default targetPendingRequests
+	{map[string]string{"host": "example.com", "interceptorAddress": "http://interceptor:9090"}, false},
+	// missing host
+	{map[string]string{"interceptorAddress": "http://interceptor:9090"}, true},
+	// missing interceptorAddress
+	{map[string]string{"host": "example.com"}, true},
+	// malformed targetPendingRequests
+	{map[string]string{"host": "example.com", "interceptorAddress": "http://interceptor:9090", "targetPendingRequests": "many"}, true},
+	// zero targetPendingRequests
+	{map[string]string{"host": "example.com", "interceptorAddress": "http://interceptor:9090", "targetPendingRequests": "0"}, true},
+	// negative targetPendingRequests
+	{map[string]string{"host": "example.com", "interceptorAddress": "http://interceptor:9090", "targetPendingRequests": "-5"}, true},
+}
+
+var httpInterceptorMetricIdentifiers = []httpInterceptorMetricIdentifier{
+	{&testHTTPInterceptorMetadata[1], "http-example-com"},
+}
+
+func TestHTTPInterceptorParseMetadata(t *testing.T) {
+	for _, testData := range testHTTPInterceptorMetadata {
+		_, err := parseHTTPInterceptorMetadata(testData.metadata)
+		if err != nil && !testData.isError {
+			t.Error("Expected success but got error", err)
+		}
+		if testData.isError && err == nil {
+			t.Error("Expected error but got success")
+		}
+	}
+}
+
+func TestHTTPInterceptorGetMetricSpecForScaling(t *testing.T) {
+	for _, testData := range httpInterceptorMetricIdentifiers {
+		meta, err := parseHTTPInterceptorMetadata(testData.metadataTestData.metadata)
+		if err != nil {
+			t.Fatal("Could not parse metadata:", err)
+		}
+		mockHTTPInterceptorScaler := httpInterceptorScaler{meta, http.DefaultClient}
+
+		metricSpec := mockHTTPInterceptorScaler.GetMetricSpecForScaling()
+		metricName := metricSpec[0].External.Metric.Name
+		if metricName != testData.name {
+			t.Error("Wrong External metric source name:", metricName)
+		}
+	}
+}
+
+func TestHTTPInterceptorGetMetrics(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/queue", r.URL.Path)
+		w.Write([]byte(`{"example.com": 42, "other.com": 3}`))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		host     string
+		expected int64
+	}{
+		{"example.com", 42},
+		{"idle.com", 0},
+	}
+
+	for _, test := range tests {
+		s, err := NewHTTPInterceptorScaler(map[string]string{"host": test.host, "interceptorAddress": server.URL + "/"})
+		if err != nil {
+			t.Fatal("Could not create scaler:", err)
+		}
+
+		metrics, err := s.GetMetrics(context.TODO(), "http", nil)
+		assert.NoError(t, err)
+		assert.Equal(t, test.expected, metrics[0].Value.Value())
+
+		isActive, err := s.IsActive(context.TODO())
+		assert.NoError(t, err)
+		assert.Equal(t, test.expected > 0, isActive)
+	}
+}
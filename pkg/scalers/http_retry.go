@@ -0,0 +1,19 @@
+package scalers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/kedacore/keda/pkg/scalersdk"
+	kedautil "github.com/kedacore/keda/pkg/util"
+)
+
+// parseHTTPRetryPolicy returns the retry policy of the HTTP requests of a scaler, see scalersdk.ParseHTTPRetryPolicy
+func parseHTTPRetryPolicy(metadata map[string]string) (kedautil.RetryPolicy, error) {
+	return scalersdk.ParseHTTPRetryPolicy(metadata)
+}
+
+// doHTTPGetWithRetry sends a GET request to the url with the context, it is retried according to the policy until the context is done
+func doHTTPGetWithRetry(ctx context.Context, client *http.Client, url string, policy kedautil.RetryPolicy) (*http.Response, error) {
+	return scalersdk.DoHTTPGetWithRetryContext(ctx, client, url, policy)
+}
@@ -0,0 +1,54 @@
+package scalers
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	kedautil "github.com/kedacore/keda/pkg/util"
+)
+
+type parseHTTPRetryPolicyTestData struct {
+	metadata map[string]string
+	expected kedautil.RetryPolicy
+	isError  bool
+}
+
+func TestParseHTTPRetryPolicy(t *testing.T) {
+	defaults := kedautil.DefaultRetryPolicy()
+	noRetries := kedautil.DefaultRetryPolicy()
+	noRetries.MaxAttempts = 1
+	longBackoff := kedautil.DefaultRetryPolicy()
+	longBackoff.Backoff = time.Minute
+	longBackoff.MaxBackoff = time.Minute
+	customStatus := kedautil.DefaultRetryPolicy()
+	customStatus.RetryOnStatus = []int{429, 500}
+
+	testDataset := []parseHTTPRetryPolicyTestData{
+		{map[string]string{}, defaults, false},
+		{map[string]string{"httpRetryMaxAttempts": "1"}, noRetries, false},
+		{map[string]string{"httpRetryBackoff": "1m"}, longBackoff, false},
+		{map[string]string{"httpRetryOnStatus": "429, 500"}, customStatus, false},
+		{map[string]string{"httpRetryMaxAttempts": "0"}, kedautil.RetryPolicy{}, true},
+		{map[string]string{"httpRetryMaxAttempts": "many"}, kedautil.RetryPolicy{}, true},
+		{map[string]string{"httpRetryBackoff": "500"}, kedautil.RetryPolicy{}, true},
+		{map[string]string{"httpRetryOnStatus": "429,unavailable"}, kedautil.RetryPolicy{}, true},
+		{map[string]string{"httpRetryOnStatus": "1000"}, kedautil.RetryPolicy{}, true},
+	}
+
+	for _, testData := range testDataset {
+		policy, err := parseHTTPRetryPolicy(testData.metadata)
+		if err != nil && !testData.isError {
+			t.Errorf("Expected success for %v but got error %s", testData.metadata, err)
+		}
+		if testData.isError {
+			if err == nil {
+				t.Errorf("Expected error for %v but got success", testData.metadata)
+			}
+			continue
+		}
+		if !reflect.DeepEqual(policy, testData.expected) {
+			t.Errorf("Expected %+v for %v, got %+v", testData.expected, testData.metadata, policy)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package scalers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	kedautil "github.com/kedacore/keda/pkg/util"
+)
+
+const (
+	burrowConsumerLagPath        = "/v3/kafka/%s/consumer/%s/lag"
+	lagExporterConsumerLagMetric = "kafka_consumergroup_group_lag"
+)
+
+type burrowConsumerGroupLag struct {
+	Error   bool   `json:"error"`
+	Message string `json:"message"`
+	Status  struct {
+		Partitions []struct {
+			Topic      string `json:"topic"`
+			Partition  int32  `json:"partition"`
+			CurrentLag int64  `json:"current_lag"`
+		} `json:"partitions"`
+	} `json:"status"`
+}
+
+// getLags returns the lag of the consumer group for each partition of the topics, read from the lag source of the trigger
+func (s *kafkaScaler) getLags(ctx context.Context) (map[string]map[int32]int64, error) {
+	switch s.metadata.lagSource {
+	case kafkaLagSourceBurrow:
+		return s.getBurrowLags(ctx)
+	case kafkaLagSourceLagExporter:
+		return s.getLagExporterLags(ctx)
+	default:
+		return s.getBrokerLags()
+	}
+}
+
+// matchesTopic returns true if the topic is one of the topics of the trigger
+func (s *kafkaScaler) matchesTopic(topic string) bool {
+	if s.metadata.topicPattern != nil {
+		return s.metadata.topicPattern.MatchString(topic)
+	}
+	for _, t := range s.metadata.topics {
+		if t == topic {
+			return true
+		}
+	}
+	return false
+}
+
+// getBurrowLags reads the lag of the consumer group from the consumer lag endpoint of Burrow,
+// it only reports the partitions the consumer group has committed offsets for
+func (s *kafkaScaler) getBurrowLags(ctx context.Context) (map[string]map[int32]int64, error) {
+	path := fmt.Sprintf(burrowConsumerLagPath, url.PathEscape(s.metadata.burrowCluster), url.PathEscape(s.metadata.group))
+	body, err := s.getLagSourceResponse(ctx, s.metadata.burrowURL+path)
+	if err != nil {
+		return nil, err
+	}
+
+	groupLag := burrowConsumerGroupLag{}
+	if err := json.Unmarshal(body, &groupLag); err != nil {
+		return nil, fmt.Errorf("error decoding burrow response: %s", err)
+	}
+	if groupLag.Error {
+		return nil, fmt.Errorf("burrow returned an error for consumer group %s: %s", s.metadata.group, groupLag.Message)
+	}
+
+	lags := map[string]map[int32]int64{}
+	for _, partition := range groupLag.Status.Partitions {
+		if !s.matchesTopic(partition.Topic) {
+			continue
+		}
+		if lags[partition.Topic] == nil {
+			lags[partition.Topic] = map[int32]int64{}
+		}
+		lags[partition.Topic][partition.Partition] = partition.CurrentLag
+	}
+	if len(lags) == 0 {
+		return nil, fmt.Errorf("no lag found in burrow for the topics of consumer group %s", s.metadata.group)
+	}
+	return lags, nil
+}
+
+// getLagExporterLags reads the lag of the consumer group from the Prometheus metrics of Kafka Lag Exporter
+func (s *kafkaScaler) getLagExporterLags(ctx context.Context) (map[string]map[int32]int64, error) {
+	body, err := s.getLagSourceResponse(ctx, s.metadata.lagExporterURL)
+	if err != nil {
+		return nil, err
+	}
+
+	matchers := map[string]string{"group": s.metadata.group}
+	if s.metadata.lagExporterCluster != "" {
+		matchers["cluster_name"] = s.metadata.lagExporterCluster
+	}
+
+	samples, err := parsePrometheusSamples(body)
+	if err != nil {
+		return nil, err
+	}
+	lags := map[string]map[int32]int64{}
+	for _, sample := range samples {
+		seriesLabels := sample.labels
+		if sample.name != lagExporterConsumerLagMetric || !matchesPrometheusLabels(seriesLabels, matchers) || !s.matchesTopic(seriesLabels["topic"]) {
+			continue
+		}
+
+		partition, err := strconv.ParseInt(seriesLabels["partition"], 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing partition of %s in topic %s: %s", seriesLabels["partition"], seriesLabels["topic"], err)
+		}
+		lag := sample.value
+		// the lag exporter reports NaN until it has read the offsets of the partition
+		if math.IsNaN(lag) {
+			lag = 0
+		}
+
+		topic := seriesLabels["topic"]
+		if lags[topic] == nil {
+			lags[topic] = map[int32]int64{}
+		}
+		lags[topic][int32(partition)] = int64(lag)
+	}
+	if len(lags) == 0 {
+		return nil, fmt.Errorf("no %s series found for the topics of consumer group %s", lagExporterConsumerLagMetric, s.metadata.group)
+	}
+	return lags, nil
+}
+
+func (s *kafkaScaler) getLagSourceResponse(ctx context.Context, sourceURL string) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, sourceURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+
+	resp, err := kedautil.DoWithRetry(s.httpClient, req, s.metadata.retryPolicy)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s lag source %s returned %d", s.metadata.lagSource, sourceURL, resp.StatusCode)
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s lag source response: %s", s.metadata.lagSource, err)
+	}
+	return body, nil
+}
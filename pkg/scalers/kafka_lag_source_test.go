@@ -0,0 +1,137 @@
+package scalers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+const testBurrowConsumerLag = `{
+	"error": false,
+	"message": "consumer status returned",
+	"status": {
+		"cluster": "local",
+		"group": "my-group",
+		"partitions": [
+			{"topic": "my-topic", "partition": 0, "current_lag": 40},
+			{"topic": "my-topic", "partition": 1, "current_lag": 0},
+			{"topic": "other-topic", "partition": 0, "current_lag": 100}
+		]
+	}
+}`
+
+const testLagExporterMetrics = `# HELP kafka_consumergroup_group_lag Group offset lag of a partition
+# TYPE kafka_consumergroup_group_lag gauge
+kafka_consumergroup_group_lag{cluster_name="local",group="my-group",topic="my-topic",partition="0",member_host="10.0.0.1",consumer_id="c1",client_id="c1"} 40.0
+kafka_consumergroup_group_lag{cluster_name="local",group="my-group",topic="my-topic",partition="1",member_host="10.0.0.1",consumer_id="c1",client_id="c1"} NaN
+kafka_consumergroup_group_lag{cluster_name="local",group="my-group",topic="other-topic",partition="0",member_host="10.0.0.1",consumer_id="c1",client_id="c1"} 100.0
+kafka_consumergroup_group_lag{cluster_name="remote",group="my-group",topic="my-topic",partition="0",member_host="10.0.0.2",consumer_id="c2",client_id="c2"} 500.0
+kafka_consumergroup_group_lag{cluster_name="local",group="other-group",topic="my-topic",partition="0",member_host="10.0.0.3",consumer_id="c3",client_id="c3"} 700.0
+kafka_consumergroup_group_max_lag{cluster_name="local",group="my-group"} 100.0
+`
+
+func newTestKafkaLagSourceScaler(t *testing.T, metadata map[string]string) *kafkaScaler {
+	scaler, err := NewKafkaScaler(map[string]string{}, metadata, map[string]string{})
+	if err != nil {
+		t.Fatal("Could not create the kafka scaler:", err)
+	}
+	return scaler.(*kafkaScaler)
+}
+
+func TestKafkaBurrowLags(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v3/kafka/local/consumer/my-group/lag" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(testBurrowConsumerLag))
+	}))
+	defer server.Close()
+
+	scaler := newTestKafkaLagSourceScaler(t, map[string]string{"lagSource": "burrow", "burrowURL": server.URL + "/", "burrowCluster": "local", "consumerGroup": "my-group", "topic": "my-topic", "lagThreshold": "10"})
+	defer scaler.Close()
+
+	lags, err := scaler.getLags(context.TODO())
+	if err != nil {
+		t.Fatal("Could not read the lags:", err)
+	}
+	if expected := map[string]map[int32]int64{"my-topic": {0: 40, 1: 0}}; !reflect.DeepEqual(lags, expected) {
+		t.Errorf("Expected lags %v but got %v", expected, lags)
+	}
+
+	metrics, err := scaler.GetMetrics(context.TODO(), "lag", nil)
+	if err != nil {
+		t.Fatal("Could not get the metrics:", err)
+	}
+	// the lag is capped at the threshold of each of the 2 partitions
+	if value := metrics[0].Value.Value(); value != 20 {
+		t.Errorf("Expected a lag of 20 but got %d", value)
+	}
+
+	scaler = newTestKafkaLagSourceScaler(t, map[string]string{"lagSource": "burrow", "burrowURL": server.URL, "burrowCluster": "remote", "consumerGroup": "my-group", "topic": "my-topic"})
+	if _, err := scaler.getLags(context.TODO()); err == nil {
+		t.Error("Expected an error for an unknown burrow cluster")
+	}
+}
+
+func TestKafkaLagExporterLags(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(testLagExporterMetrics))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		metadata map[string]string
+		lags     map[string]map[int32]int64
+		isError  bool
+	}{
+		{"topic", map[string]string{"topic": "other-topic"}, map[string]map[int32]int64{"other-topic": {0: 100}}, false},
+		{"topic of a cluster", map[string]string{"topic": "my-topic", "lagExporterCluster": "local"}, map[string]map[int32]int64{"my-topic": {0: 40, 1: 0}}, false},
+		{"topic of another cluster", map[string]string{"topic": "my-topic", "lagExporterCluster": "remote"}, map[string]map[int32]int64{"my-topic": {0: 500}}, false},
+		{"topic pattern", map[string]string{"topicPattern": ".*-topic", "lagExporterCluster": "local"}, map[string]map[int32]int64{"my-topic": {0: 40, 1: 0}, "other-topic": {0: 100}}, false},
+		{"unknown topic", map[string]string{"topic": "unknown-topic"}, nil, true},
+	}
+
+	for _, test := range tests {
+		metadata := map[string]string{"lagSource": "lagExporter", "lagExporterURL": server.URL + "/metrics", "consumerGroup": "my-group"}
+		for k, v := range test.metadata {
+			metadata[k] = v
+		}
+		scaler := newTestKafkaLagSourceScaler(t, metadata)
+
+		lags, err := scaler.getLags(context.TODO())
+		if test.isError != (err != nil) {
+			t.Errorf("%s: expected error %t but got %v", test.name, test.isError, err)
+		}
+		if err == nil && !reflect.DeepEqual(lags, test.lags) {
+			t.Errorf("%s: expected lags %v but got %v", test.name, test.lags, lags)
+		}
+	}
+}
+
+func TestKafkaLagSourceIsActive(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(testLagExporterMetrics))
+	}))
+	defer server.Close()
+
+	scaler := newTestKafkaLagSourceScaler(t, map[string]string{"lagSource": "lagExporter", "lagExporterURL": server.URL, "consumerGroup": "my-group", "topic": "my-topic", "lagExporterCluster": "local"})
+	isActive, err := scaler.IsActive(context.TODO())
+	if err != nil {
+		t.Fatal("Could not get the activity:", err)
+	}
+	if !isActive {
+		t.Error("Expected the scaler to be active with a lag")
+	}
+
+	count, err := scaler.PartitionCount(context.TODO())
+	if err != nil {
+		t.Fatal("Could not get the partition count:", err)
+	}
+	if count != 2 {
+		t.Errorf("Expected 2 partitions but got %d", count)
+	}
+}
@@ -2,12 +2,14 @@ package scalers
 
 import (
 	"context"
-	"crypto/tls"
-	"crypto/x509"
 	"errors"
 	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/Shopify/sarama"
 	v2beta2 "k8s.io/api/autoscaling/v2beta2"
@@ -17,32 +19,36 @@ import (
 	"k8s.io/metrics/pkg/apis/external_metrics"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
+	"github.com/kedacore/keda/pkg/scalers/authentication"
 	kedautil "github.com/kedacore/keda/pkg/util"
 )
 
 type kafkaScaler struct {
-	metadata kafkaMetadata
-	client   sarama.Client
-	admin    sarama.ClusterAdmin
+	metadata   kafkaMetadata
+	client     sarama.Client
+	admin      sarama.ClusterAdmin
+	httpClient *http.Client
 }
 
 type kafkaMetadata struct {
 	bootstrapServers  []string
 	group             string
 	topic             string
+	topics            []string
+	topicPattern      *regexp.Regexp
 	lagThreshold      int64
 	offsetResetPolicy offsetResetPolicy
 
-	// SASL
-	saslType kafkaSaslType
-	username string
-	password string
+	// lag source, the Burrow and Kafka Lag Exporter sources don't need admin ACLs on the brokers
+	lagSource          kafkaLagSource
+	burrowURL          string
+	burrowCluster      string
+	lagExporterURL     string
+	lagExporterCluster string
+	retryPolicy        kedautil.RetryPolicy
 
-	// TLS
-	enableTLS bool
-	cert      string
-	key       string
-	ca        string
+	sasl authentication.SASLConfig
+	tls  authentication.TLSConfig
 }
 
 type offsetResetPolicy string
@@ -52,14 +58,13 @@ const (
 	earliest offsetResetPolicy = "earliest"
 )
 
-type kafkaSaslType string
+type kafkaLagSource string
 
-// supported SASL types
+// supported lag sources
 const (
-	KafkaSASLTypeNone        kafkaSaslType = "none"
-	KafkaSASLTypePlaintext   kafkaSaslType = "plaintext"
-	KafkaSASLTypeSCRAMSHA256 kafkaSaslType = "scram_sha256"
-	KafkaSASLTypeSCRAMSHA512 kafkaSaslType = "scram_sha512"
+	kafkaLagSourceBroker      kafkaLagSource = "broker"
+	kafkaLagSourceBurrow      kafkaLagSource = "burrow"
+	kafkaLagSourceLagExporter kafkaLagSource = "lagExporter"
 )
 
 const (
@@ -68,10 +73,13 @@ const (
 	defaultKafkaLagThreshold = 10
 	defaultOffsetResetPolicy = latest
 	invalidOffset            = -1
+	kafkaLagRequestTimeout   = 10 * time.Second
 )
 
 var kafkaLog = logf.Log.WithName("kafka_scaler")
 
+var kafkaMetricTopicUnsupportedChars = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
+
 // NewKafkaScaler creates a new kafkaScaler
 func NewKafkaScaler(resolvedEnv, metadata, authParams map[string]string) (Scaler, error) {
 	kafkaMetadata, err := parseKafkaMetadata(metadata, authParams)
@@ -79,6 +87,13 @@ func NewKafkaScaler(resolvedEnv, metadata, authParams map[string]string) (Scaler
 		return nil, fmt.Errorf("error parsing kafka metadata: %s", err)
 	}
 
+	if kafkaMetadata.lagSource != kafkaLagSourceBroker {
+		return &kafkaScaler{
+			metadata:   kafkaMetadata,
+			httpClient: &http.Client{Timeout: kafkaLagRequestTimeout},
+		}, nil
+	}
+
 	client, admin, err := getKafkaClients(kafkaMetadata)
 	if err != nil {
 		return nil, err
@@ -94,9 +109,41 @@ func NewKafkaScaler(resolvedEnv, metadata, authParams map[string]string) (Scaler
 func parseKafkaMetadata(metadata, authParams map[string]string) (kafkaMetadata, error) {
 	meta := kafkaMetadata{}
 
-	if metadata["bootstrapServers"] == "" {
-		return meta, errors.New("no bootstrapServers given")
+	meta.lagSource = kafkaLagSourceBroker
+	if metadata["lagSource"] != "" {
+		meta.lagSource = kafkaLagSource(metadata["lagSource"])
 	}
+	switch meta.lagSource {
+	case kafkaLagSourceBroker:
+		if metadata["bootstrapServers"] == "" {
+			return meta, errors.New("no bootstrapServers given")
+		}
+	case kafkaLagSourceBurrow:
+		if metadata["burrowURL"] == "" {
+			return meta, errors.New("no burrowURL given")
+		}
+		meta.burrowURL = strings.TrimSuffix(metadata["burrowURL"], "/")
+		if metadata["burrowCluster"] == "" {
+			return meta, errors.New("no burrowCluster given")
+		}
+		meta.burrowCluster = metadata["burrowCluster"]
+	case kafkaLagSourceLagExporter:
+		if metadata["lagExporterURL"] == "" {
+			return meta, errors.New("no lagExporterURL given")
+		}
+		meta.lagExporterURL = metadata["lagExporterURL"]
+		meta.lagExporterCluster = metadata["lagExporterCluster"]
+	default:
+		return meta, fmt.Errorf("err lagSource %s given", meta.lagSource)
+	}
+	if meta.lagSource != kafkaLagSourceBroker {
+		retryPolicy, err := parseHTTPRetryPolicy(metadata)
+		if err != nil {
+			return meta, err
+		}
+		meta.retryPolicy = retryPolicy
+	}
+
 	if metadata["bootstrapServers"] != "" {
 		meta.bootstrapServers = strings.Split(metadata["bootstrapServers"], ",")
 	}
@@ -106,10 +153,29 @@ func parseKafkaMetadata(metadata, authParams map[string]string) (kafkaMetadata,
 	}
 	meta.group = metadata["consumerGroup"]
 
-	if metadata["topic"] == "" {
+	switch {
+	case metadata["topic"] != "" && metadata["topicPattern"] != "":
+		return meta, errors.New("topic and topicPattern can't be given both")
+	case metadata["topic"] != "":
+		meta.topic = metadata["topic"]
+		for _, topic := range strings.Split(meta.topic, ",") {
+			if topic = strings.TrimSpace(topic); topic != "" {
+				meta.topics = append(meta.topics, topic)
+			}
+		}
+		if len(meta.topics) == 0 {
+			return meta, errors.New("no topic given")
+		}
+	case metadata["topicPattern"] != "":
+		// the pattern matches whole topic names, the anchors only add unsupported chars to the metric name
+		pattern, err := regexp.Compile("^(?:" + metadata["topicPattern"] + ")$")
+		if err != nil {
+			return meta, fmt.Errorf("error parsing topicPattern: %s", err)
+		}
+		meta.topicPattern = pattern
+	default:
 		return meta, errors.New("no topic given")
 	}
-	meta.topic = metadata["topic"]
 
 	meta.offsetResetPolicy = defaultOffsetResetPolicy
 
@@ -131,77 +197,39 @@ func parseKafkaMetadata(metadata, authParams map[string]string) (kafkaMetadata,
 		meta.lagThreshold = t
 	}
 
-	meta.saslType = KafkaSASLTypeNone
-	if val, ok := authParams["sasl"]; ok {
-		val = strings.TrimSpace(val)
-		mode := kafkaSaslType(val)
-
-		if mode == KafkaSASLTypePlaintext || mode == KafkaSASLTypeSCRAMSHA256 || mode == KafkaSASLTypeSCRAMSHA512 {
-			if authParams["username"] == "" {
-				return meta, errors.New("no username given")
-			}
-			meta.username = strings.TrimSpace(authParams["username"])
-
-			if authParams["password"] == "" {
-				return meta, errors.New("no password given")
-			}
-			meta.password = strings.TrimSpace(authParams["password"])
-			meta.saslType = mode
-		} else {
-			return meta, fmt.Errorf("err SASL mode %s given", mode)
-		}
+	sasl, err := authentication.ParseSASLConfig(authParams)
+	if err != nil {
+		return meta, err
 	}
+	meta.sasl = sasl
 
-	meta.enableTLS = false
-	if val, ok := authParams["tls"]; ok {
-		val = strings.TrimSpace(val)
-
-		if val == "enable" {
-			if authParams["ca"] == "" {
-				return meta, errors.New("no ca given")
-			}
-			meta.ca = authParams["ca"]
-
-			if authParams["cert"] == "" {
-				return meta, errors.New("no cert given")
-			}
-			meta.cert = authParams["cert"]
-
-			if authParams["key"] == "" {
-				return meta, errors.New("no key given")
-			}
-			meta.key = authParams["key"]
-			meta.enableTLS = true
-		} else {
-			return meta, fmt.Errorf("err incorrect value for TLS given: %s", val)
-		}
+	tlsConfig, err := authentication.ParseTLSConfig(authParams)
+	if err != nil {
+		return meta, err
 	}
+	meta.tls = tlsConfig
 
 	return meta, nil
 }
 
 // IsActive determines if we need to scale from zero
 func (s *kafkaScaler) IsActive(ctx context.Context) (bool, error) {
-	partitions, err := s.getPartitions()
+	lags, err := s.getLags(ctx)
 	if err != nil {
 		return false, err
 	}
 
-	offsets, err := s.getOffsets(partitions)
-	if err != nil {
-		return false, err
-	}
-
-	for _, partition := range partitions {
-		lag, err := s.getLagForPartition(partition, offsets)
-		if err != nil && lag == invalidOffset {
-			return true, nil
-		}
-		kafkaLog.V(1).Info(fmt.Sprintf("Group %s has a lag of %d for topic %s and partition %d\n", s.metadata.group, lag, s.metadata.topic, partition))
+	for topic, partitionLags := range lags {
+		for partition, lag := range partitionLags {
+			if lag == invalidOffset {
+				return true, nil
+			}
+			kafkaLog.V(1).Info(fmt.Sprintf("Group %s has a lag of %d for topic %s and partition %d\n", s.metadata.group, lag, topic, partition))
 
-		// Return as soon as a lag was detected for any partition
-		if lag > 0 {
-			return true, nil
+			// Return as soon as a lag was detected for any partition
+			if lag > 0 {
+				return true, nil
+			}
 		}
 	}
 
@@ -212,33 +240,34 @@ func getKafkaClients(metadata kafkaMetadata) (sarama.Client, sarama.ClusterAdmin
 	config := sarama.NewConfig()
 	config.Version = sarama.V1_0_0_0
 
-	if metadata.saslType != KafkaSASLTypeNone {
+	if metadata.sasl.Type != authentication.SASLTypeNone {
 		config.Net.SASL.Enable = true
-		config.Net.SASL.User = metadata.username
-		config.Net.SASL.Password = metadata.password
+		config.Net.SASL.User = metadata.sasl.Username
+		config.Net.SASL.Password = metadata.sasl.Password
 	}
 
-	if metadata.enableTLS {
+	if metadata.tls.Enabled {
 		config.Net.TLS.Enable = true
-		tlsConfig, err := newTLSConfig(metadata.cert, metadata.key, metadata.ca)
+		tlsConfig, err := metadata.tls.NewTLSConfig()
 		if err != nil {
 			return nil, nil, err
 		}
 		config.Net.TLS.Config = tlsConfig
 	}
 
-	if metadata.saslType == KafkaSASLTypePlaintext {
+	switch metadata.sasl.Type {
+	case authentication.SASLTypePlaintext:
 		config.Net.SASL.Mechanism = sarama.SASLTypePlaintext
-	}
-
-	if metadata.saslType == KafkaSASLTypeSCRAMSHA256 {
+	case authentication.SASLTypeSCRAMSHA256:
 		config.Net.SASL.SCRAMClientGeneratorFunc = func() sarama.SCRAMClient { return &XDGSCRAMClient{HashGeneratorFcn: SHA256} }
 		config.Net.SASL.Mechanism = sarama.SASLTypeSCRAMSHA256
-	}
-
-	if metadata.saslType == KafkaSASLTypeSCRAMSHA512 {
+	case authentication.SASLTypeSCRAMSHA512:
 		config.Net.SASL.SCRAMClientGeneratorFunc = func() sarama.SCRAMClient { return &XDGSCRAMClient{HashGeneratorFcn: SHA512} }
 		config.Net.SASL.Mechanism = sarama.SASLTypeSCRAMSHA512
+	case authentication.SASLTypeOAuthBearer:
+		tokenSource := authentication.GetClientCredentialsTokenSource(metadata.sasl, &http.Client{Timeout: kafkaLagRequestTimeout})
+		config.Net.SASL.TokenProvider = &kafkaOAuthTokenProvider{tokenSource: tokenSource}
+		config.Net.SASL.Mechanism = sarama.SASLTypeOAuth
 	}
 
 	client, err := sarama.NewClient(metadata.bootstrapServers, config)
@@ -257,59 +286,102 @@ func getKafkaClients(metadata kafkaMetadata) (sarama.Client, sarama.ClusterAdmin
 	return client, admin, nil
 }
 
-// newTLSConfig returns a *tls.Config using the given ceClient cert, ceClient key,
-// and CA certificate. If none are appropriate, a nil *tls.Config is returned.
-func newTLSConfig(clientCert, clientKey, caCert string) (*tls.Config, error) {
-	valid := false
+// kafkaOAuthTokenProvider provides sarama with the OAuth access tokens of the OAUTHBEARER SASL mechanism
+type kafkaOAuthTokenProvider struct {
+	tokenSource *authentication.ClientCredentialsTokenSource
+}
 
-	config := &tls.Config{}
+// Token returns the access token of the client credentials
+func (p *kafkaOAuthTokenProvider) Token() (*sarama.AccessToken, error) {
+	token, err := p.tokenSource.Token()
+	if err != nil {
+		return nil, err
+	}
+	return &sarama.AccessToken{Token: token}, nil
+}
 
-	if clientCert != "" && clientKey != "" {
-		cert, err := tls.X509KeyPair([]byte(clientCert), []byte(clientKey))
-		if err != nil {
-			return nil, fmt.Errorf("error parse X509KeyPair: %s", err)
+// getTopics returns the topics of the trigger, the topics matching the topicPattern are listed from the cluster
+func (s *kafkaScaler) getTopics() ([]string, error) {
+	if s.metadata.topicPattern == nil {
+		return s.metadata.topics, nil
+	}
+
+	clusterTopics, err := s.admin.ListTopics()
+	if err != nil {
+		return nil, fmt.Errorf("error listing topics: %s", err)
+	}
+	topics := []string{}
+	for topic := range clusterTopics {
+		if s.metadata.topicPattern.MatchString(topic) {
+			topics = append(topics, topic)
 		}
-		config.Certificates = []tls.Certificate{cert}
-		valid = true
+	}
+	sort.Strings(topics)
+	return topics, nil
+}
+
+// getTopicPartitions returns the partitions of each topic of the trigger
+func (s *kafkaScaler) getTopicPartitions() (map[string][]int32, error) {
+	topics, err := s.getTopics()
+	if err != nil {
+		return nil, err
+	}
+	if len(topics) == 0 {
+		return nil, fmt.Errorf("no topic matching %s found", s.metadata.topicPattern)
 	}
 
-	if caCert != "" {
-		caCertPool := x509.NewCertPool()
-		caCertPool.AppendCertsFromPEM([]byte(caCert))
-		config.RootCAs = caCertPool
-		config.InsecureSkipVerify = true
-		valid = true
+	topicsMetadata, err := s.admin.DescribeTopics(topics)
+	if err != nil {
+		return nil, fmt.Errorf("error describing topics: %s", err)
+	}
+	if len(topicsMetadata) != len(topics) {
+		return nil, fmt.Errorf("expected %d topic metadata, got %d", len(topics), len(topicsMetadata))
 	}
 
-	if !valid {
-		config = nil
+	topicPartitions := make(map[string][]int32, len(topicsMetadata))
+	for _, topicMetadata := range topicsMetadata {
+		if topicMetadata.Err != sarama.ErrNoError {
+			return nil, fmt.Errorf("error describing topic %s: %s", topicMetadata.Name, topicMetadata.Err)
+		}
+		partitions := make([]int32, len(topicMetadata.Partitions))
+		for i, p := range topicMetadata.Partitions {
+			partitions[i] = p.ID
+		}
+		topicPartitions[topicMetadata.Name] = partitions
 	}
 
-	return config, nil
+	return topicPartitions, nil
 }
 
-func (s *kafkaScaler) getPartitions() ([]int32, error) {
-	topicsMetadata, err := s.admin.DescribeTopics([]string{s.metadata.topic})
+// getBrokerLags reads the lag of the consumer group from the offsets committed on the brokers, with the latest reset policy
+// a partition with no committed offset has the invalid offset as its lag
+func (s *kafkaScaler) getBrokerLags() (map[string]map[int32]int64, error) {
+	topicPartitions, err := s.getTopicPartitions()
 	if err != nil {
-		return nil, fmt.Errorf("error describing topics: %s", err)
-	}
-	if len(topicsMetadata) != 1 {
-		return nil, fmt.Errorf("expected only 1 topic metadata, got %d", len(topicsMetadata))
+		return nil, err
 	}
 
-	partitionMetadata := topicsMetadata[0].Partitions
-	partitions := make([]int32, len(partitionMetadata))
-	for i, p := range partitionMetadata {
-		partitions[i] = p.ID
+	offsets, err := s.getOffsets(topicPartitions)
+	if err != nil {
+		return nil, err
 	}
 
-	return partitions, nil
+	lags := make(map[string]map[int32]int64, len(topicPartitions))
+	for topic, partitions := range topicPartitions {
+		lags[topic] = make(map[int32]int64, len(partitions))
+		for _, partition := range partitions {
+			lag, err := s.getLagForPartition(topic, partition, offsets)
+			if err != nil && lag != invalidOffset {
+				lag = 0
+			}
+			lags[topic][partition] = lag
+		}
+	}
+	return lags, nil
 }
 
-func (s *kafkaScaler) getOffsets(partitions []int32) (*sarama.OffsetFetchResponse, error) {
-	offsets, err := s.admin.ListConsumerGroupOffsets(s.metadata.group, map[string][]int32{
-		s.metadata.topic: partitions,
-	})
+func (s *kafkaScaler) getOffsets(topicPartitions map[string][]int32) (*sarama.OffsetFetchResponse, error) {
+	offsets, err := s.admin.ListConsumerGroupOffsets(s.metadata.group, topicPartitions)
 
 	if err != nil {
 		return nil, fmt.Errorf("error listing consumer group offsets: %s", err)
@@ -318,31 +390,71 @@ func (s *kafkaScaler) getOffsets(partitions []int32) (*sarama.OffsetFetchRespons
 	return offsets, nil
 }
 
-func (s *kafkaScaler) getLagForPartition(partition int32, offsets *sarama.OffsetFetchResponse) (int64, error) {
-	block := offsets.GetBlock(s.metadata.topic, partition)
+func (s *kafkaScaler) getLagForPartition(topic string, partition int32, offsets *sarama.OffsetFetchResponse) (int64, error) {
+	block := offsets.GetBlock(topic, partition)
 	if block == nil {
-		kafkaLog.Error(fmt.Errorf("error finding offset block for topic %s and partition %d", s.metadata.topic, partition), "")
-		return 0, fmt.Errorf("error finding offset block for topic %s and partition %d", s.metadata.topic, partition)
+		kafkaLog.Error(fmt.Errorf("error finding offset block for topic %s and partition %d", topic, partition), "")
+		return 0, fmt.Errorf("error finding offset block for topic %s and partition %d", topic, partition)
 	}
 	consumerOffset := block.Offset
-	latestOffset, err := s.client.GetOffset(s.metadata.topic, partition, sarama.OffsetNewest)
+	latestOffset, err := s.client.GetOffset(topic, partition, sarama.OffsetNewest)
 	if err != nil {
-		kafkaLog.Error(err, fmt.Sprintf("error finding latest offset for topic %s and partition %d\n", s.metadata.topic, partition))
-		return 0, fmt.Errorf("error finding latest offset for topic %s and partition %d", s.metadata.topic, partition)
+		kafkaLog.Error(err, fmt.Sprintf("error finding latest offset for topic %s and partition %d\n", topic, partition))
+		return 0, fmt.Errorf("error finding latest offset for topic %s and partition %d", topic, partition)
 	}
 
 	if consumerOffset == invalidOffset {
 		if s.metadata.offsetResetPolicy == latest {
-			kafkaLog.V(0).Info(fmt.Sprintf("invalid offset found for topic %s in group %s and partition %d, probably no offset is committed yet", s.metadata.topic, s.metadata.group, partition))
-			return invalidOffset, fmt.Errorf("invalid offset found for topic %s in group %s and partition %d, probably no offset is committed yet", s.metadata.topic, s.metadata.group, partition)
+			kafkaLog.V(0).Info(fmt.Sprintf("invalid offset found for topic %s in group %s and partition %d, probably no offset is committed yet", topic, s.metadata.group, partition))
+			return invalidOffset, fmt.Errorf("invalid offset found for topic %s in group %s and partition %d, probably no offset is committed yet", topic, s.metadata.group, partition)
+		}
+		// the consumer group starts reading the partition from its oldest offset still retained, the messages
+		// already removed by the retention of the topic aren't part of the lag
+		oldestOffset, err := s.client.GetOffset(topic, partition, sarama.OffsetOldest)
+		if err != nil {
+			kafkaLog.Error(err, fmt.Sprintf("error finding oldest offset for topic %s and partition %d\n", topic, partition))
+			return 0, fmt.Errorf("error finding oldest offset for topic %s and partition %d", topic, partition)
 		}
-		return latestOffset, nil
+		return (latestOffset - oldestOffset), nil
 	}
 	return (latestOffset - consumerOffset), nil
 }
 
+// PartitionCount returns the number of partitions of the topics
+func (s *kafkaScaler) PartitionCount(ctx context.Context) (int64, error) {
+	if s.metadata.lagSource != kafkaLagSourceBroker {
+		lags, err := s.getLags(ctx)
+		if err != nil {
+			return 0, err
+		}
+		return countPartitions(lags), nil
+	}
+
+	topicPartitions, err := s.getTopicPartitions()
+	if err != nil {
+		return 0, err
+	}
+	count := int64(0)
+	for _, partitions := range topicPartitions {
+		count += int64(len(partitions))
+	}
+	return count, nil
+}
+
+func countPartitions(lags map[string]map[int32]int64) int64 {
+	count := int64(0)
+	for _, partitionLags := range lags {
+		count += int64(len(partitionLags))
+	}
+	return count
+}
+
 // Close closes the kafka admin and client
 func (s *kafkaScaler) Close() error {
+	if s.admin == nil {
+		return nil
+	}
+
 	// underlying client will also be closed on admin's Close() call
 	err := s.admin.Close()
 	if err != nil {
@@ -352,11 +464,20 @@ func (s *kafkaScaler) Close() error {
 	return nil
 }
 
+// metricTopicName returns the topics of the trigger as they are named in its metric
+func (s *kafkaScaler) metricTopicName() string {
+	topic := s.metadata.topic
+	if s.metadata.topicPattern != nil {
+		topic = s.metadata.topicPattern.String()
+	}
+	return strings.Trim(kafkaMetricTopicUnsupportedChars.ReplaceAllString(topic, "-"), "-")
+}
+
 func (s *kafkaScaler) GetMetricSpecForScaling() []v2beta2.MetricSpec {
 	targetMetricValue := resource.NewQuantity(s.metadata.lagThreshold, resource.DecimalSI)
 	externalMetric := &v2beta2.ExternalMetricSource{
 		Metric: v2beta2.MetricIdentifier{
-			Name: kedautil.NormalizeString(fmt.Sprintf("%s-%s-%s", "kafka", s.metadata.topic, s.metadata.group)),
+			Name: kedautil.NormalizeString(fmt.Sprintf("%s-%s-%s", "kafka", s.metricTopicName(), s.metadata.group)),
 		},
 		Target: v2beta2.MetricTarget{
 			Type:         v2beta2.AverageValueMetricType,
@@ -369,28 +490,27 @@ func (s *kafkaScaler) GetMetricSpecForScaling() []v2beta2.MetricSpec {
 
 //GetMetrics returns value for a supported metric and an error if there is a problem getting the metric
 func (s *kafkaScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
-	partitions, err := s.getPartitions()
-	if err != nil {
-		return []external_metrics.ExternalMetricValue{}, err
-	}
-
-	offsets, err := s.getOffsets(partitions)
+	lags, err := s.getLags(ctx)
 	if err != nil {
 		return []external_metrics.ExternalMetricValue{}, err
 	}
 
 	totalLag := int64(0)
-	for _, partition := range partitions {
-		lag, _ := s.getLagForPartition(partition, offsets)
-
-		totalLag += lag
+	for _, partitionLags := range lags {
+		for _, lag := range partitionLags {
+			// with the latest reset policy, a partition with no committed offset is read from its latest offset and has no lag
+			if lag > 0 {
+				totalLag += lag
+			}
+		}
 	}
+	partitionCount := countPartitions(lags)
 
-	kafkaLog.V(1).Info(fmt.Sprintf("Kafka scaler: Providing metrics based on totalLag %v, partitions %v, threshold %v", totalLag, len(partitions), s.metadata.lagThreshold))
+	kafkaLog.V(1).Info(fmt.Sprintf("Kafka scaler: Providing metrics based on totalLag %v, topics %v, partitions %v, threshold %v", totalLag, len(lags), partitionCount, s.metadata.lagThreshold))
 
 	// don't scale out beyond the number of partitions
-	if (totalLag / s.metadata.lagThreshold) > int64(len(partitions)) {
-		totalLag = int64(len(partitions)) * s.metadata.lagThreshold
+	if (totalLag / s.metadata.lagThreshold) > partitionCount {
+		totalLag = partitionCount * s.metadata.lagThreshold
 	}
 
 	metric := external_metrics.ExternalMetricValue{
@@ -3,6 +3,8 @@ package scalers
 import (
 	"reflect"
 	"testing"
+
+	"github.com/Shopify/sarama"
 )
 
 type parseKafkaMetadataTestData struct {
@@ -60,6 +62,26 @@ var parseKafkaMetadataTestDataset = []parseKafkaMetadataTestData{
 	{map[string]string{"bootstrapServers": "foo:9092,bar:9092", "consumerGroup": "my-group", "topic": "my-topic", "offsetResetPolicy": "foo"}, true, 2, []string{"foo:9092", "bar:9092"}, "my-group", "my-topic", ""},
 	// success, offsetResetPolicy policy earliest
 	{map[string]string{"bootstrapServers": "foo:9092,bar:9092", "consumerGroup": "my-group", "topic": "my-topic", "offsetResetPolicy": "earliest"}, false, 2, []string{"foo:9092", "bar:9092"}, "my-group", "my-topic", offsetResetPolicy("earliest")},
+	// success, topic list
+	{map[string]string{"bootstrapServers": "foobar:9092", "consumerGroup": "my-group", "topic": "my-topic, other-topic"}, false, 1, []string{"foobar:9092"}, "my-group", "my-topic, other-topic", offsetResetPolicy("latest")},
+	// success, topic pattern
+	{map[string]string{"bootstrapServers": "foobar:9092", "consumerGroup": "my-group", "topicPattern": "my-topic\\..*"}, false, 1, []string{"foobar:9092"}, "my-group", "", offsetResetPolicy("latest")},
+	// failure, topic pattern invalid
+	{map[string]string{"bootstrapServers": "foobar:9092", "consumerGroup": "my-group", "topicPattern": "my-topic-("}, true, 1, []string{"foobar:9092"}, "my-group", "", ""},
+	// failure, topic and topic pattern
+	{map[string]string{"bootstrapServers": "foobar:9092", "consumerGroup": "my-group", "topic": "my-topic", "topicPattern": "my-topic-.*"}, true, 1, []string{"foobar:9092"}, "my-group", "", ""},
+	// failure, empty topic list
+	{map[string]string{"bootstrapServers": "foobar:9092", "consumerGroup": "my-group", "topic": " , "}, true, 1, []string{"foobar:9092"}, "my-group", " , ", ""},
+	// success, burrow lag source without bootstrapServers
+	{map[string]string{"lagSource": "burrow", "burrowURL": "http://burrow:8000", "burrowCluster": "local", "consumerGroup": "my-group", "topic": "my-topic"}, false, 0, nil, "my-group", "my-topic", offsetResetPolicy("latest")},
+	// failure, burrow lag source without cluster
+	{map[string]string{"lagSource": "burrow", "burrowURL": "http://burrow:8000", "consumerGroup": "my-group", "topic": "my-topic"}, true, 0, nil, "", "", ""},
+	// success, lag exporter lag source
+	{map[string]string{"lagSource": "lagExporter", "lagExporterURL": "http://lag-exporter:8000/metrics", "consumerGroup": "my-group", "topic": "my-topic"}, false, 0, nil, "my-group", "my-topic", offsetResetPolicy("latest")},
+	// failure, lag exporter lag source without url
+	{map[string]string{"lagSource": "lagExporter", "consumerGroup": "my-group", "topic": "my-topic"}, true, 0, nil, "", "", ""},
+	// failure, lag source wrong
+	{map[string]string{"lagSource": "foo", "bootstrapServers": "foobar:9092", "consumerGroup": "my-group", "topic": "my-topic"}, true, 0, nil, "", "", ""},
 }
 
 var parseKafkaAuthParamsTestDataset = []parseKafkaAuthParamsTestData{
@@ -105,6 +127,8 @@ var parseKafkaAuthParamsTestDataset = []parseKafkaAuthParamsTestData{
 
 var kafkaMetricIdentifiers = []kafkaMetricIdentifier{
 	{&parseKafkaMetadataTestDataset[4], "kafka-my-topic-my-group"},
+	{&parseKafkaMetadataTestDataset[8], "kafka-my-topic-other-topic-my-group"},
+	{&parseKafkaMetadataTestDataset[9], "kafka-my-topic-my-group"},
 }
 
 func TestGetBrokers(t *testing.T) {
@@ -159,6 +183,26 @@ func TestGetBrokers(t *testing.T) {
 	}
 }
 
+func TestKafkaTopics(t *testing.T) {
+	meta, err := parseKafkaMetadata(parseKafkaMetadataTestDataset[8].metadata, validWithoutAuthParams)
+	if err != nil {
+		t.Fatal("Could not parse metadata:", err)
+	}
+	if !reflect.DeepEqual(meta.topics, []string{"my-topic", "other-topic"}) {
+		t.Errorf("Expected topics [my-topic other-topic] but got %v\n", meta.topics)
+	}
+
+	meta, err = parseKafkaMetadata(parseKafkaMetadataTestDataset[9].metadata, validWithoutAuthParams)
+	if err != nil {
+		t.Fatal("Could not parse metadata:", err)
+	}
+	for topic, matches := range map[string]bool{"my-topic.orders": true, "my-topic.payments": true, "my-topic": false, "other-topic.orders": false, "my-topic.orders.dlq": true, "other-my-topic.orders": false} {
+		if meta.topicPattern.MatchString(topic) != matches {
+			t.Errorf("Expected topicPattern to match %s: %t\n", topic, matches)
+		}
+	}
+}
+
 func TestKafkaAuthParams(t *testing.T) {
 	for _, testData := range parseKafkaAuthParamsTestDataset {
 		meta, err := parseKafkaMetadata(validKafkaMetadata, testData.authParams)
@@ -169,8 +213,8 @@ func TestKafkaAuthParams(t *testing.T) {
 		if testData.isError && err == nil {
 			t.Error("Expected error but got success")
 		}
-		if meta.enableTLS != testData.enableTLS {
-			t.Errorf("Expected enableTLS to be set to %v but got %v\n", testData.enableTLS, meta.enableTLS)
+		if meta.tls.Enabled != testData.enableTLS {
+			t.Errorf("Expected enableTLS to be set to %v but got %v\n", testData.enableTLS, meta.tls.Enabled)
 		}
 	}
 }
@@ -180,7 +224,7 @@ func TestKafkaGetMetricSpecForScaling(t *testing.T) {
 		if err != nil {
 			t.Fatal("Could not parse metadata:", err)
 		}
-		mockKafkaScaler := kafkaScaler{meta, nil, nil}
+		mockKafkaScaler := kafkaScaler{metadata: meta}
 
 		metricSpec := mockKafkaScaler.GetMetricSpecForScaling()
 		metricName := metricSpec[0].External.Metric.Name
@@ -189,3 +233,48 @@ func TestKafkaGetMetricSpecForScaling(t *testing.T) {
 		}
 	}
 }
+
+func TestKafkaGetLagForPartition(t *testing.T) {
+	broker := sarama.NewMockBroker(t, 1)
+	defer broker.Close()
+	broker.SetHandlerByMap(map[string]sarama.MockResponse{
+		"MetadataRequest": sarama.NewMockMetadataResponse(t).
+			SetBroker(broker.Addr(), broker.BrokerID()).
+			SetLeader("my-topic", 0, broker.BrokerID()),
+		"OffsetRequest": sarama.NewMockOffsetResponse(t).
+			SetOffset("my-topic", 0, sarama.OffsetOldest, 400).
+			SetOffset("my-topic", 0, sarama.OffsetNewest, 1000),
+	})
+
+	client, err := sarama.NewClient([]string{broker.Addr()}, sarama.NewConfig())
+	if err != nil {
+		t.Fatal("Could not create the kafka client:", err)
+	}
+	defer client.Close()
+
+	tests := []struct {
+		name              string
+		consumerOffset    int64
+		offsetResetPolicy offsetResetPolicy
+		lag               int64
+		isError           bool
+	}{
+		{"committed offset", 900, latest, 100, false},
+		{"no committed offset with latest policy", invalidOffset, latest, invalidOffset, true},
+		{"no committed offset with earliest policy", invalidOffset, earliest, 600, false},
+	}
+
+	for _, test := range tests {
+		offsets := &sarama.OffsetFetchResponse{}
+		offsets.AddBlock("my-topic", 0, &sarama.OffsetFetchResponseBlock{Offset: test.consumerOffset})
+		scaler := kafkaScaler{metadata: kafkaMetadata{topic: "my-topic", topics: []string{"my-topic"}, group: "my-group", offsetResetPolicy: test.offsetResetPolicy}, client: client}
+
+		lag, err := scaler.getLagForPartition("my-topic", 0, offsets)
+		if test.isError != (err != nil) {
+			t.Errorf("%s: expected error %t but got %v", test.name, test.isError, err)
+		}
+		if lag != test.lag {
+			t.Errorf("%s: expected a lag of %d but got %d", test.name, test.lag, lag)
+		}
+	}
+}
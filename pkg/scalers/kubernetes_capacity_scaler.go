@@ -0,0 +1,260 @@
+package scalers
+
+import (
+	"context"
+	"fmt"
+
+	v2beta2 "k8s.io/api/autoscaling/v2beta2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/metrics/pkg/apis/external_metrics"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	kedautil "github.com/kedacore/keda/pkg/util"
+)
+
+const (
+	capacityNodeSelector      = "nodeSelector"
+	capacityResource          = "resource"
+	capacityTargetHeadroom    = "targetHeadroom"
+	capacityValue             = "value"
+	capacityBufferPodSelector = "bufferPodSelector"
+	defaultCapacityResource   = corev1.ResourceCPU
+)
+
+type kubernetesCapacityScaler struct {
+	metadata   *kubernetesCapacityMetadata
+	kubeClient client.Client
+}
+
+type kubernetesCapacityMetadata struct {
+	nodeSelector labels.Selector
+	resource     corev1.ResourceName
+	// the metric is the capacity missing to the target headroom, each replica reserves the capacity of the value
+	targetHeadroom resource.Quantity
+	value          resource.Quantity
+	// the pods of the buffer are preempted by the workloads, their requests are counted as headroom
+	bufferPodSelector labels.Selector
+	namespace         string
+}
+
+var kubernetesCapacityLog = logf.Log.WithName("kubernetes_capacity_scaler")
+
+// NewKubernetesCapacityScaler creates a new scaler for the capacity headroom of the nodes of the cluster
+func NewKubernetesCapacityScaler(kubeClient client.Client, namespace string, metadata map[string]string) (Scaler, error) {
+	meta, err := parseKubernetesCapacityMetadata(namespace, metadata)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing kubernetes capacity metadata: %s", err)
+	}
+
+	return &kubernetesCapacityScaler{
+		metadata:   meta,
+		kubeClient: kubeClient,
+	}, nil
+}
+
+func parseKubernetesCapacityMetadata(namespace string, metadata map[string]string) (*kubernetesCapacityMetadata, error) {
+	meta := kubernetesCapacityMetadata{
+		nodeSelector: labels.Everything(),
+		resource:     defaultCapacityResource,
+		namespace:    namespace,
+	}
+
+	if val, ok := metadata[capacityNodeSelector]; ok && val != "" {
+		selector, err := labels.Parse(val)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing %s: %s", capacityNodeSelector, err)
+		}
+		meta.nodeSelector = selector
+	}
+
+	if val, ok := metadata[capacityResource]; ok && val != "" {
+		switch corev1.ResourceName(val) {
+		case corev1.ResourceCPU, corev1.ResourceMemory:
+			meta.resource = corev1.ResourceName(val)
+		default:
+			return nil, fmt.Errorf("%s %s not supported, supported resources are cpu and memory", capacityResource, val)
+		}
+	}
+
+	targetHeadroom, err := parseCapacityQuantity(metadata, capacityTargetHeadroom)
+	if err != nil {
+		return nil, err
+	}
+	meta.targetHeadroom = targetHeadroom
+
+	value, err := parseCapacityQuantity(metadata, capacityValue)
+	if err != nil {
+		return nil, err
+	}
+	meta.value = value
+
+	if val, ok := metadata[capacityBufferPodSelector]; ok && val != "" {
+		selector, err := labels.Parse(val)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing %s: %s", capacityBufferPodSelector, err)
+		}
+		meta.bufferPodSelector = selector
+	}
+
+	return &meta, nil
+}
+
+func parseCapacityQuantity(metadata map[string]string, name string) (resource.Quantity, error) {
+	val, ok := metadata[name]
+	if !ok || val == "" {
+		return resource.Quantity{}, fmt.Errorf("no %s given", name)
+	}
+	quantity, err := resource.ParseQuantity(val)
+	if err != nil {
+		return resource.Quantity{}, fmt.Errorf("error parsing %s: %s", name, err)
+	}
+	if quantity.Sign() <= 0 {
+		return resource.Quantity{}, fmt.Errorf("%s must be greater than 0", name)
+	}
+	return quantity, nil
+}
+
+// IsActive returns true if the headroom of the nodes is below the target headroom
+func (s *kubernetesCapacityScaler) IsActive(ctx context.Context) (bool, error) {
+	missing, err := s.getMissingHeadroom(ctx)
+	if err != nil {
+		kubernetesCapacityLog.Error(err, "error getting capacity headroom")
+		return false, err
+	}
+
+	return missing > 0, nil
+}
+
+// Close does nothing in case of kubernetesCapacityScaler
+func (s *kubernetesCapacityScaler) Close() error {
+	return nil
+}
+
+// GetMetricSpecForScaling returns the metric spec for the HPA
+func (s *kubernetesCapacityScaler) GetMetricSpecForScaling() []v2beta2.MetricSpec {
+	targetMetricValue := resource.NewMilliQuantity(s.metadata.value.MilliValue(), resource.DecimalSI)
+	name := fmt.Sprintf("%s-%s", "capacity", s.metadata.resource)
+	if !s.metadata.nodeSelector.Empty() {
+		name = fmt.Sprintf("%s-%s", name, parseWorkloadSelectorFormat(s.metadata.nodeSelector.String()))
+	}
+	externalMetric := &v2beta2.ExternalMetricSource{
+		Metric: v2beta2.MetricIdentifier{
+			Name: kedautil.NormalizeString(name),
+		},
+		Target: v2beta2.MetricTarget{
+			Type:         v2beta2.AverageValueMetricType,
+			AverageValue: targetMetricValue,
+		},
+	}
+	metricSpec := v2beta2.MetricSpec{External: externalMetric, Type: externalMetricType}
+	return []v2beta2.MetricSpec{metricSpec}
+}
+
+// GetMetrics returns the capacity missing to the target headroom
+func (s *kubernetesCapacityScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
+	missing, err := s.getMissingHeadroom(ctx)
+	if err != nil {
+		kubernetesCapacityLog.Error(err, "error getting capacity headroom")
+		return []external_metrics.ExternalMetricValue{}, err
+	}
+
+	metric := external_metrics.ExternalMetricValue{
+		MetricName: metricName,
+		Value:      *resource.NewMilliQuantity(missing, resource.DecimalSI),
+		Timestamp:  metav1.Now(),
+	}
+
+	return append([]external_metrics.ExternalMetricValue{}, metric), nil
+}
+
+// getMissingHeadroom returns the capacity in milli units missing to the target headroom, 0 if the headroom is reached
+func (s *kubernetesCapacityScaler) getMissingHeadroom(ctx context.Context) (int64, error) {
+	headroom, err := s.getHeadroom(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if missing := s.metadata.targetHeadroom.MilliValue() - headroom; missing > 0 {
+		return missing, nil
+	}
+	return 0, nil
+}
+
+// getHeadroom returns the allocatable minus the requested capacity in milli units over the ready and schedulable nodes
+// matching the node selector, the headroom of a node is never below 0
+func (s *kubernetesCapacityScaler) getHeadroom(ctx context.Context) (int64, error) {
+	nodes := &corev1.NodeList{}
+	if err := s.kubeClient.List(ctx, nodes, client.MatchingLabelsSelector{Selector: s.metadata.nodeSelector}); err != nil {
+		return 0, err
+	}
+
+	allocatable := map[string]int64{}
+	for _, node := range nodes.Items {
+		if node.Spec.Unschedulable || !isNodeReady(&node) {
+			continue
+		}
+		quantity := node.Status.Allocatable[s.metadata.resource]
+		allocatable[node.Name] = quantity.MilliValue()
+	}
+	if len(allocatable) == 0 {
+		return 0, nil
+	}
+
+	pods := &corev1.PodList{}
+	if err := s.kubeClient.List(ctx, pods); err != nil {
+		return 0, err
+	}
+	requested := map[string]int64{}
+	for _, pod := range pods.Items {
+		if _, ok := allocatable[pod.Spec.NodeName]; !ok || s.isBufferPod(&pod) {
+			continue
+		}
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		requested[pod.Spec.NodeName] += podRequest(&pod, s.metadata.resource)
+	}
+
+	var headroom int64
+	for node, capacity := range allocatable {
+		if free := capacity - requested[node]; free > 0 {
+			headroom += free
+		}
+	}
+	return headroom, nil
+}
+
+func (s *kubernetesCapacityScaler) isBufferPod(pod *corev1.Pod) bool {
+	return s.metadata.bufferPodSelector != nil && pod.Namespace == s.metadata.namespace && s.metadata.bufferPodSelector.Matches(labels.Set(pod.Labels))
+}
+
+// podRequest returns the request of the pod in milli units as the scheduler computes it, the highest of the sum of
+// its containers and of its init containers, plus its overhead
+func podRequest(pod *corev1.Pod, resourceName corev1.ResourceName) int64 {
+	var request int64
+	for _, container := range pod.Spec.Containers {
+		quantity := container.Resources.Requests[resourceName]
+		request += quantity.MilliValue()
+	}
+	for _, container := range pod.Spec.InitContainers {
+		if quantity := container.Resources.Requests[resourceName]; quantity.MilliValue() > request {
+			request = quantity.MilliValue()
+		}
+	}
+	if quantity, ok := pod.Spec.Overhead[resourceName]; ok {
+		request += quantity.MilliValue()
+	}
+	return request
+}
+
+func isNodeReady(node *corev1.Node) bool {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
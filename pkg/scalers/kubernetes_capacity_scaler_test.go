@@ -0,0 +1,175 @@
+package scalers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+type parseKubernetesCapacityMetadataTestData struct {
+	metadata map[string]string
+	isError  bool
+}
+
+type kubernetesCapacityMetricIdentifier struct {
+	metadataTestData *parseKubernetesCapacityMetadataTestData
+	name             string
+}
+
+var testKubernetesCapacityMetadata = []parseKubernetesCapacityMetadataTestData{
+	// nothing passed
+	{map[string]string{}, true},
+	// cpu headroom of all nodes
+	{map[string]string{"targetHeadroom": "4", "value": "500m"}, false},
+	// memory headroom of a node pool with a buffer
+	{map[string]string{"nodeSelector": "pool=workers", "resource": "memory", "targetHeadroom": "16Gi", "value": "1Gi", "bufferPodSelector": "app=buffer"}, false},
+	// missing targetHeadroom
+	{map[string]string{"value": "1"}, true},
+	// missing value
+	{map[string]string{"targetHeadroom": "4"}, true},
+	// malformed targetHeadroom
+	{map[string]string{"targetHeadroom": "four", "value": "1"}, true},
+	// value is zero
+	{map[string]string{"targetHeadroom": "4", "value": "0"}, true},
+	// unsupported resource
+	{map[string]string{"resource": "ephemeral-storage", "targetHeadroom": "4", "value": "1"}, true},
+	// malformed nodeSelector
+	{map[string]string{"nodeSelector": "pool===", "targetHeadroom": "4", "value": "1"}, true},
+	// malformed bufferPodSelector
+	{map[string]string{"bufferPodSelector": "app===", "targetHeadroom": "4", "value": "1"}, true},
+}
+
+var kubernetesCapacityMetricIdentifiers = []kubernetesCapacityMetricIdentifier{
+	{&testKubernetesCapacityMetadata[1], "capacity-cpu"},
+	{&testKubernetesCapacityMetadata[2], "capacity-memory-pool-workers"},
+}
+
+func TestKubernetesCapacityParseMetadata(t *testing.T) {
+	for _, testData := range testKubernetesCapacityMetadata {
+		_, err := parseKubernetesCapacityMetadata("default", testData.metadata)
+		if err != nil && !testData.isError {
+			t.Error("Expected success but got error", err)
+		}
+		if testData.isError && err == nil {
+			t.Error("Expected error but got success")
+		}
+	}
+}
+
+func TestKubernetesCapacityGetMetricSpecForScaling(t *testing.T) {
+	for _, testData := range kubernetesCapacityMetricIdentifiers {
+		meta, err := parseKubernetesCapacityMetadata("default", testData.metadataTestData.metadata)
+		if err != nil {
+			t.Fatal("Could not parse metadata:", err)
+		}
+		mockKubernetesCapacityScaler := kubernetesCapacityScaler{meta, nil}
+
+		metricSpec := mockKubernetesCapacityScaler.GetMetricSpecForScaling()
+		metricName := metricSpec[0].External.Metric.Name
+		if metricName != testData.name {
+			t.Error("Wrong External metric source name:", metricName)
+		}
+	}
+}
+
+func TestKubernetesCapacityGetMetrics(t *testing.T) {
+	existing := []runtime.Object{
+		createCapacityNode("worker-1", "workers", "4", true, false),
+		createCapacityNode("worker-2", "workers", "4", true, false),
+		createCapacityNode("worker-3", "workers", "4", false, false),
+		createCapacityNode("worker-4", "workers", "4", true, true),
+		createCapacityNode("system-1", "system", "2", true, false),
+		createCapacityPod("api-1", "default", "api", "worker-1", "1500m", corev1.PodRunning),
+		createCapacityPod("api-2", "default", "api", "worker-1", "3", corev1.PodRunning),
+		createCapacityPod("batch-1", "batch", "batch", "worker-2", "2", corev1.PodSucceeded),
+		createCapacityPod("buffer-1", "default", "buffer", "worker-2", "1", corev1.PodRunning),
+		createCapacityPod("buffer-2", "other", "buffer", "worker-2", "1", corev1.PodRunning),
+		createCapacityPod("pending-1", "default", "api", "", "1", corev1.PodPending),
+		createCapacityPod("system-1", "kube-system", "dns", "system-1", "500m", corev1.PodRunning),
+	}
+
+	tests := []struct {
+		metadata map[string]string
+		missing  int64
+	}{
+		// worker-1 is overcommitted, worker-2 has 2 cores of headroom, worker-3 isn't ready and worker-4 is cordoned
+		{map[string]string{"nodeSelector": "pool=workers", "targetHeadroom": "5", "value": "1"}, 3000},
+		// the buffer pods of the namespace are counted as headroom
+		{map[string]string{"nodeSelector": "pool=workers", "targetHeadroom": "5", "value": "1", "bufferPodSelector": "app=buffer"}, 2000},
+		// all nodes
+		{map[string]string{"targetHeadroom": "5", "value": "1"}, 1500},
+		// the headroom is reached
+		{map[string]string{"targetHeadroom": "2", "value": "1"}, 0},
+	}
+
+	for _, test := range tests {
+		s, err := NewKubernetesCapacityScaler(fake.NewFakeClientWithScheme(scheme.Scheme, existing...), "default", test.metadata)
+		if err != nil {
+			t.Fatal("Could not create scaler:", err)
+		}
+
+		metrics, err := s.GetMetrics(context.TODO(), "capacity", nil)
+		assert.NoError(t, err)
+		assert.Equal(t, test.missing, metrics[0].Value.MilliValue(), "metadata %v", test.metadata)
+
+		isActive, err := s.IsActive(context.TODO())
+		assert.NoError(t, err)
+		assert.Equal(t, test.missing > 0, isActive)
+	}
+}
+
+func TestPodRequest(t *testing.T) {
+	pod := &corev1.Pod{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("250m")}}},
+				{Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("250m")}}},
+			},
+			InitContainers: []corev1.Container{
+				{Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")}}},
+			},
+			Overhead: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("50m")},
+		},
+	}
+	assert.Equal(t, int64(550), podRequest(pod, corev1.ResourceCPU))
+
+	// an init container requesting more than the containers sets the request of the pod
+	pod.Spec.InitContainers[0].Resources.Requests[corev1.ResourceCPU] = resource.MustParse("1")
+	assert.Equal(t, int64(1050), podRequest(pod, corev1.ResourceCPU))
+	assert.Equal(t, int64(0), podRequest(pod, corev1.ResourceMemory))
+}
+
+func createCapacityNode(name, pool, cpu string, ready, unschedulable bool) *corev1.Node {
+	status := corev1.ConditionFalse
+	if ready {
+		status = corev1.ConditionTrue
+	}
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{"pool": pool}},
+		Spec:       corev1.NodeSpec{Unschedulable: unschedulable},
+		Status: corev1.NodeStatus{
+			Allocatable: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpu)},
+			Conditions:  []corev1.NodeCondition{{Type: corev1.NodeReady, Status: status}},
+		},
+	}
+}
+
+func createCapacityPod(name, namespace, app, nodeName, cpu string, phase corev1.PodPhase) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: map[string]string{"app": app}},
+		Spec: corev1.PodSpec{
+			NodeName: nodeName,
+			Containers: []corev1.Container{
+				{Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpu)}}},
+			},
+		},
+		Status: corev1.PodStatus{Phase: phase},
+	}
+}
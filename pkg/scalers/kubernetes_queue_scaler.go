@@ -0,0 +1,225 @@
+package scalers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	v2beta2 "k8s.io/api/autoscaling/v2beta2"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/metrics/pkg/apis/external_metrics"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	kedautil "github.com/kedacore/keda/pkg/util"
+)
+
+const (
+	queueResourcePods = "pods"
+	queueResourceJobs = "jobs"
+
+	queueSelector          = "selector"
+	queueResource          = "resource"
+	queueNamespace         = "namespace"
+	queueOnlyUnschedulable = "onlyUnschedulable"
+	queueIncludeActive     = "includeActive"
+	queueValue             = "value"
+	defaultQueueValue      = 1
+)
+
+type kubernetesQueueScaler struct {
+	metadata   *kubernetesQueueMetadata
+	kubeClient client.Client
+}
+
+type kubernetesQueueMetadata struct {
+	resource          string
+	selector          labels.Selector
+	namespace         string
+	onlyUnschedulable bool
+	includeActive     bool
+	value             int
+}
+
+var kubernetesQueueLog = logf.Log.WithName("kubernetes_queue_scaler")
+
+// NewKubernetesQueueScaler creates a new scaler for the pending pods or the queued jobs matching a selector
+func NewKubernetesQueueScaler(kubeClient client.Client, namespace string, metadata map[string]string) (Scaler, error) {
+	meta, err := parseKubernetesQueueMetadata(namespace, metadata)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing kubernetes queue metadata: %s", err)
+	}
+
+	return &kubernetesQueueScaler{
+		metadata:   meta,
+		kubeClient: kubeClient,
+	}, nil
+}
+
+func parseKubernetesQueueMetadata(namespace string, metadata map[string]string) (*kubernetesQueueMetadata, error) {
+	meta := kubernetesQueueMetadata{
+		resource:  queueResourcePods,
+		namespace: namespace,
+		value:     defaultQueueValue,
+	}
+
+	if val, ok := metadata[queueResource]; ok && val != "" {
+		switch queueResourceName := strings.ToLower(val); queueResourceName {
+		case queueResourcePods, queueResourceJobs:
+			meta.resource = queueResourceName
+		default:
+			return nil, fmt.Errorf("%s %s not supported, supported resources are %s, %s", queueResource, val, queueResourcePods, queueResourceJobs)
+		}
+	}
+
+	if val, ok := metadata[queueSelector]; ok && val != "" {
+		selector, err := labels.Parse(val)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing %s: %s", queueSelector, err)
+		}
+		meta.selector = selector
+	} else {
+		return nil, fmt.Errorf("no %s given", queueSelector)
+	}
+
+	if val, ok := metadata[queueNamespace]; ok && val != "" {
+		meta.namespace = val
+	}
+
+	if val, ok := metadata[queueOnlyUnschedulable]; ok && val != "" {
+		if meta.resource != queueResourcePods {
+			return nil, fmt.Errorf("%s is only supported for %s", queueOnlyUnschedulable, queueResourcePods)
+		}
+		onlyUnschedulable, err := strconv.ParseBool(val)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing %s: %s", queueOnlyUnschedulable, err)
+		}
+		meta.onlyUnschedulable = onlyUnschedulable
+	}
+
+	if val, ok := metadata[queueIncludeActive]; ok && val != "" {
+		if meta.resource != queueResourceJobs {
+			return nil, fmt.Errorf("%s is only supported for %s", queueIncludeActive, queueResourceJobs)
+		}
+		includeActive, err := strconv.ParseBool(val)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing %s: %s", queueIncludeActive, err)
+		}
+		meta.includeActive = includeActive
+	}
+
+	if val, ok := metadata[queueValue]; ok && val != "" {
+		value, err := strconv.Atoi(val)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing %s: %s", queueValue, err)
+		}
+		if value <= 0 {
+			return nil, fmt.Errorf("%s must be greater than 0", queueValue)
+		}
+		meta.value = value
+	}
+
+	return &meta, nil
+}
+
+// IsActive returns true if there are pending pods or queued jobs
+func (s *kubernetesQueueScaler) IsActive(ctx context.Context) (bool, error) {
+	count, err := s.getQueueLength(ctx)
+	if err != nil {
+		kubernetesQueueLog.Error(err, "error getting queue length")
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// Close does nothing in case of kubernetesQueueScaler
+func (s *kubernetesQueueScaler) Close() error {
+	return nil
+}
+
+// GetMetricSpecForScaling returns the metric spec for the HPA
+func (s *kubernetesQueueScaler) GetMetricSpecForScaling() []v2beta2.MetricSpec {
+	targetMetricValue := resource.NewQuantity(int64(s.metadata.value), resource.DecimalSI)
+	externalMetric := &v2beta2.ExternalMetricSource{
+		Metric: v2beta2.MetricIdentifier{
+			Name: kedautil.NormalizeString(fmt.Sprintf("%s-%s-%s", "queue", s.metadata.resource, parseWorkloadSelectorFormat(s.metadata.selector.String()))),
+		},
+		Target: v2beta2.MetricTarget{
+			Type:         v2beta2.AverageValueMetricType,
+			AverageValue: targetMetricValue,
+		},
+	}
+	metricSpec := v2beta2.MetricSpec{External: externalMetric, Type: externalMetricType}
+	return []v2beta2.MetricSpec{metricSpec}
+}
+
+// GetMetrics returns the number of pending pods or queued jobs matching the selector
+func (s *kubernetesQueueScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
+	count, err := s.getQueueLength(ctx)
+	if err != nil {
+		kubernetesQueueLog.Error(err, "error getting queue length")
+		return []external_metrics.ExternalMetricValue{}, err
+	}
+
+	metric := external_metrics.ExternalMetricValue{
+		MetricName: metricName,
+		Value:      *resource.NewQuantity(count, resource.DecimalSI),
+		Timestamp:  metav1.Now(),
+	}
+
+	return append([]external_metrics.ExternalMetricValue{}, metric), nil
+}
+
+func (s *kubernetesQueueScaler) getQueueLength(ctx context.Context) (int64, error) {
+	listOptions := []client.ListOption{client.InNamespace(s.metadata.namespace), client.MatchingLabelsSelector{Selector: s.metadata.selector}}
+
+	var count int64
+	if s.metadata.resource == queueResourceJobs {
+		jobs := &batchv1.JobList{}
+		if err := s.kubeClient.List(ctx, jobs, listOptions...); err != nil {
+			return 0, err
+		}
+		for i := range jobs.Items {
+			if isJobQueued(&jobs.Items[i], s.metadata.includeActive) {
+				count++
+			}
+		}
+		return count, nil
+	}
+
+	pods := &corev1.PodList{}
+	if err := s.kubeClient.List(ctx, pods, listOptions...); err != nil {
+		return 0, err
+	}
+	for i := range pods.Items {
+		if pods.Items[i].Status.Phase == corev1.PodPending && (!s.metadata.onlyUnschedulable || isPodUnschedulable(&pods.Items[i])) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// isJobQueued returns true if the job isn't finished and has no active pod, unless the active jobs are included
+func isJobQueued(job *batchv1.Job, includeActive bool) bool {
+	for _, condition := range job.Status.Conditions {
+		if (condition.Type == batchv1.JobComplete || condition.Type == batchv1.JobFailed) && condition.Status == corev1.ConditionTrue {
+			return false
+		}
+	}
+	return includeActive || job.Status.Active == 0
+}
+
+func isPodUnschedulable(pod *corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodScheduled {
+			return condition.Status == corev1.ConditionFalse && condition.Reason == corev1.PodReasonUnschedulable
+		}
+	}
+	return false
+}
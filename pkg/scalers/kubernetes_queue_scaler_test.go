@@ -0,0 +1,143 @@
+package scalers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+type parseKubernetesQueueMetadataTestData struct {
+	metadata map[string]string
+	isError  bool
+}
+
+type kubernetesQueueMetricIdentifier struct {
+	metadataTestData *parseKubernetesQueueMetadataTestData
+	name             string
+}
+
+var testKubernetesQueueMetadata = []parseKubernetesQueueMetadataTestData{
+	// nothing passed
+	{map[string]string{}, true},
+	// properly formed pods
+	{map[string]string{"selector": "app=batch", "onlyUnschedulable": "true", "value": "5"}, false},
+	// properly formed jobs in another namespace
+	{map[string]string{"resource": "Jobs", "selector": "queue=render", "namespace": "batch", "includeActive": "false"}, false},
+	// unsupported resource
+	{map[string]string{"resource": "deployments", "selector": "app=batch"}, true},
+	// malformed selector
+	{map[string]string{"selector": "app==="}, true},
+	// onlyUnschedulable for jobs
+	{map[string]string{"resource": "jobs", "selector": "app=batch", "onlyUnschedulable": "true"}, true},
+	// includeActive for pods
+	{map[string]string{"selector": "app=batch", "includeActive": "true"}, true},
+	// malformed onlyUnschedulable
+	{map[string]string{"selector": "app=batch", "onlyUnschedulable": "maybe"}, true},
+	// value is zero
+	{map[string]string{"selector": "app=batch", "value": "0"}, true},
+}
+
+var kubernetesQueueMetricIdentifiers = []kubernetesQueueMetricIdentifier{
+	{&testKubernetesQueueMetadata[1], "queue-pods-app-batch"},
+	{&testKubernetesQueueMetadata[2], "queue-jobs-queue-render"},
+}
+
+func TestKubernetesQueueParseMetadata(t *testing.T) {
+	for _, testData := range testKubernetesQueueMetadata {
+		_, err := parseKubernetesQueueMetadata("default", testData.metadata)
+		if err != nil && !testData.isError {
+			t.Error("Expected success but got error", err)
+		}
+		if testData.isError && err == nil {
+			t.Errorf("Expected error but got success for %v", testData.metadata)
+		}
+	}
+}
+
+func TestKubernetesQueueGetMetricSpecForScaling(t *testing.T) {
+	for _, testData := range kubernetesQueueMetricIdentifiers {
+		meta, err := parseKubernetesQueueMetadata("default", testData.metadataTestData.metadata)
+		if err != nil {
+			t.Fatal("Could not parse metadata:", err)
+		}
+		mockKubernetesQueueScaler := kubernetesQueueScaler{meta, nil}
+
+		metricSpec := mockKubernetesQueueScaler.GetMetricSpecForScaling()
+		metricName := metricSpec[0].External.Metric.Name
+		if metricName != testData.name {
+			t.Error("Wrong External metric source name:", metricName)
+		}
+	}
+}
+
+func TestKubernetesQueueGetMetrics(t *testing.T) {
+	existing := []runtime.Object{
+		createQueuePod("batch-1", "default", corev1.PodPending, corev1.ConditionFalse),
+		createQueuePod("batch-2", "default", corev1.PodPending, corev1.ConditionUnknown),
+		createQueuePod("batch-3", "default", corev1.PodRunning, corev1.ConditionTrue),
+		createQueuePod("batch-4", "other", corev1.PodPending, corev1.ConditionFalse),
+		createQueueJob("render-1", "default", 0, ""),
+		createQueueJob("render-2", "default", 1, ""),
+		createQueueJob("render-3", "default", 0, batchv1.JobComplete),
+		createQueueJob("render-4", "default", 0, batchv1.JobFailed),
+		createQueueJob("render-5", "other", 0, ""),
+	}
+
+	tests := []struct {
+		metadata map[string]string
+		expected int64
+	}{
+		{map[string]string{"selector": "app=batch"}, 2},
+		{map[string]string{"selector": "app=batch", "onlyUnschedulable": "true"}, 1},
+		{map[string]string{"selector": "app=batch", "namespace": "other"}, 1},
+		{map[string]string{"resource": "jobs", "selector": "app=batch"}, 1},
+		{map[string]string{"resource": "jobs", "selector": "app=batch", "includeActive": "true"}, 2},
+	}
+
+	for _, test := range tests {
+		s, err := NewKubernetesQueueScaler(fake.NewFakeClientWithScheme(scheme.Scheme, existing...), "default", test.metadata)
+		if err != nil {
+			t.Fatal("Could not create scaler:", err)
+		}
+
+		metrics, err := s.GetMetrics(context.TODO(), "queue", nil)
+		assert.NoError(t, err)
+		assert.Equal(t, test.expected, metrics[0].Value.Value(), "metadata %v", test.metadata)
+
+		isActive, err := s.IsActive(context.TODO())
+		assert.NoError(t, err)
+		assert.True(t, isActive)
+	}
+}
+
+func createQueuePod(name, namespace string, phase corev1.PodPhase, scheduled corev1.ConditionStatus) *corev1.Pod {
+	reason := ""
+	if scheduled == corev1.ConditionFalse {
+		reason = corev1.PodReasonUnschedulable
+	}
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: map[string]string{"app": "batch"}},
+		Status: corev1.PodStatus{
+			Phase:      phase,
+			Conditions: []corev1.PodCondition{{Type: corev1.PodScheduled, Status: scheduled, Reason: reason}},
+		},
+	}
+}
+
+func createQueueJob(name, namespace string, active int32, finished batchv1.JobConditionType) *batchv1.Job {
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: map[string]string{"app": "batch"}},
+		Status:     batchv1.JobStatus{Active: active},
+	}
+	if finished != "" {
+		job.Status.Conditions = []batchv1.JobCondition{{Type: finished, Status: corev1.ConditionTrue}}
+	}
+	return job
+}
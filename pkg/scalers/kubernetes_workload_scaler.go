@@ -0,0 +1,217 @@
+package scalers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v2beta2 "k8s.io/api/autoscaling/v2beta2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/metrics/pkg/apis/external_metrics"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	kedautil "github.com/kedacore/keda/pkg/util"
+)
+
+const (
+	workloadPodSelector    = "podSelector"
+	workloadDeploymentName = "deploymentName"
+	workloadOnlyReady      = "onlyReady"
+	workloadValue          = "value"
+	defaultWorkloadValue   = 1
+)
+
+type kubernetesWorkloadScaler struct {
+	metadata   *kubernetesWorkloadMetadata
+	kubeClient client.Client
+}
+
+type kubernetesWorkloadMetadata struct {
+	podSelector    labels.Selector
+	deploymentName string
+	onlyReady      bool
+	value          int
+	namespace      string
+}
+
+var kubernetesWorkloadLog = logf.Log.WithName("kubernetes_workload_scaler")
+
+// NewKubernetesWorkloadScaler creates a new kubernetesWorkloadScaler
+func NewKubernetesWorkloadScaler(kubeClient client.Client, namespace string, metadata map[string]string) (Scaler, error) {
+	meta, err := parseKubernetesWorkloadMetadata(namespace, metadata)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing kubernetes workload metadata: %s", err)
+	}
+
+	return &kubernetesWorkloadScaler{
+		metadata:   meta,
+		kubeClient: kubeClient,
+	}, nil
+}
+
+func parseKubernetesWorkloadMetadata(namespace string, metadata map[string]string) (*kubernetesWorkloadMetadata, error) {
+	meta := kubernetesWorkloadMetadata{
+		namespace: namespace,
+		value:     defaultWorkloadValue,
+	}
+
+	podSelector, hasPodSelector := metadata[workloadPodSelector]
+	deploymentName, hasDeploymentName := metadata[workloadDeploymentName]
+	switch {
+	case hasPodSelector && hasDeploymentName:
+		return nil, fmt.Errorf("only one of %s or %s can be given", workloadPodSelector, workloadDeploymentName)
+	case hasPodSelector && podSelector != "":
+		selector, err := labels.Parse(podSelector)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing %s: %s", workloadPodSelector, err)
+		}
+		meta.podSelector = selector
+	case hasDeploymentName && deploymentName != "":
+		meta.deploymentName = deploymentName
+	default:
+		return nil, fmt.Errorf("no %s or %s given", workloadPodSelector, workloadDeploymentName)
+	}
+
+	if val, ok := metadata[workloadOnlyReady]; ok && val != "" {
+		onlyReady, err := strconv.ParseBool(val)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing %s: %s", workloadOnlyReady, err)
+		}
+		meta.onlyReady = onlyReady
+	}
+
+	if val, ok := metadata[workloadValue]; ok && val != "" {
+		value, err := strconv.Atoi(val)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing %s: %s", workloadValue, err)
+		}
+		if value <= 0 {
+			return nil, fmt.Errorf("%s must be greater than 0", workloadValue)
+		}
+		meta.value = value
+	}
+
+	return &meta, nil
+}
+
+// IsActive returns true if there are pods matching the selector that have not completed or ready replicas of the deployment
+func (s *kubernetesWorkloadScaler) IsActive(ctx context.Context) (bool, error) {
+	count, err := s.getWorkloadCount(ctx)
+	if err != nil {
+		kubernetesWorkloadLog.Error(err, "error getting workload count")
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// Close does nothing in case of kubernetesWorkloadScaler
+func (s *kubernetesWorkloadScaler) Close() error {
+	return nil
+}
+
+// GetMetricSpecForScaling returns the metric spec for the HPA
+func (s *kubernetesWorkloadScaler) GetMetricSpecForScaling() []v2beta2.MetricSpec {
+	targetMetricValue := resource.NewQuantity(int64(s.metadata.value), resource.DecimalSI)
+	externalMetric := &v2beta2.ExternalMetricSource{
+		Metric: v2beta2.MetricIdentifier{
+			Name: kedautil.NormalizeString(fmt.Sprintf("%s-%s", "workload", s.workloadName())),
+		},
+		Target: v2beta2.MetricTarget{
+			Type:         v2beta2.AverageValueMetricType,
+			AverageValue: targetMetricValue,
+		},
+	}
+	metricSpec := v2beta2.MetricSpec{External: externalMetric, Type: externalMetricType}
+	return []v2beta2.MetricSpec{metricSpec}
+}
+
+// GetMetrics returns the number of pods matching the selector that have not completed or ready replicas of the deployment
+func (s *kubernetesWorkloadScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
+	count, err := s.getWorkloadCount(ctx)
+	if err != nil {
+		kubernetesWorkloadLog.Error(err, "error getting workload count")
+		return []external_metrics.ExternalMetricValue{}, err
+	}
+
+	metric := external_metrics.ExternalMetricValue{
+		MetricName: metricName,
+		Value:      *resource.NewQuantity(count, resource.DecimalSI),
+		Timestamp:  metav1.Now(),
+	}
+
+	return append([]external_metrics.ExternalMetricValue{}, metric), nil
+}
+
+func (s *kubernetesWorkloadScaler) workloadName() string {
+	if s.metadata.deploymentName != "" {
+		return s.metadata.deploymentName
+	}
+	return parseWorkloadSelectorFormat(s.metadata.podSelector.String())
+}
+
+// parseWorkloadSelectorFormat removes the characters of a label selector that are not allowed in a metric name
+func parseWorkloadSelectorFormat(s string) string {
+	s = strings.ReplaceAll(s, " ", "")
+	s = strings.ReplaceAll(s, "!=", "-ne-")
+	s = strings.ReplaceAll(s, "==", "-")
+	s = strings.ReplaceAll(s, "=", "-")
+	s = strings.ReplaceAll(s, "!", "not-")
+	s = strings.ReplaceAll(s, ",", "-")
+	s = strings.ReplaceAll(s, "(", "")
+	s = strings.ReplaceAll(s, ")", "")
+	return s
+}
+
+func (s *kubernetesWorkloadScaler) getWorkloadCount(ctx context.Context) (int64, error) {
+	if s.metadata.deploymentName != "" {
+		deployment := &appsv1.Deployment{}
+		err := s.kubeClient.Get(ctx, types.NamespacedName{Name: s.metadata.deploymentName, Namespace: s.metadata.namespace}, deployment)
+		if err != nil {
+			return 0, err
+		}
+		if s.metadata.onlyReady {
+			return int64(deployment.Status.ReadyReplicas), nil
+		}
+		return int64(deployment.Status.Replicas), nil
+	}
+
+	pods := &corev1.PodList{}
+	err := s.kubeClient.List(ctx, pods, client.InNamespace(s.metadata.namespace), client.MatchingLabelsSelector{Selector: s.metadata.podSelector})
+	if err != nil {
+		return 0, err
+	}
+
+	var count int64
+	for _, pod := range pods.Items {
+		if isPodCompleted(&pod) {
+			continue
+		}
+		if !s.metadata.onlyReady || isPodReady(&pod) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// isPodCompleted returns true if the containers of the pod have terminated, such pods are kept until they are deleted
+// but don't run the workload anymore
+func isPodCompleted(pod *corev1.Pod) bool {
+	return pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed
+}
+
+func isPodReady(pod *corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
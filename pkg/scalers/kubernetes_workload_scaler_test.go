@@ -0,0 +1,159 @@
+package scalers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+type parseKubernetesWorkloadMetadataTestData struct {
+	metadata map[string]string
+	isError  bool
+}
+
+type kubernetesWorkloadMetricIdentifier struct {
+	metadataTestData *parseKubernetesWorkloadMetadataTestData
+	name             string
+}
+
+var testKubernetesWorkloadMetadata = []parseKubernetesWorkloadMetadataTestData{
+	// nothing passed
+	{map[string]string{}, true},
+	// properly formed podSelector
+	{map[string]string{"podSelector": "app=frontend", "value": "5"}, false},
+	// properly formed deploymentName
+	{map[string]string{"deploymentName": "frontend", "onlyReady": "true"}, false},
+	// both podSelector and deploymentName
+	{map[string]string{"podSelector": "app=frontend", "deploymentName": "frontend"}, true},
+	// malformed podSelector
+	{map[string]string{"podSelector": "app==="}, true},
+	// malformed value
+	{map[string]string{"podSelector": "app=frontend", "value": "five"}, true},
+	// value is zero
+	{map[string]string{"podSelector": "app=frontend", "value": "0"}, true},
+	// malformed onlyReady
+	{map[string]string{"podSelector": "app=frontend", "onlyReady": "maybe"}, true},
+}
+
+var kubernetesWorkloadMetricIdentifiers = []kubernetesWorkloadMetricIdentifier{
+	{&testKubernetesWorkloadMetadata[1], "workload-app-frontend"},
+	{&testKubernetesWorkloadMetadata[2], "workload-frontend"},
+}
+
+func TestKubernetesWorkloadParseMetadata(t *testing.T) {
+	for _, testData := range testKubernetesWorkloadMetadata {
+		_, err := parseKubernetesWorkloadMetadata("default", testData.metadata)
+		if err != nil && !testData.isError {
+			t.Error("Expected success but got error", err)
+		}
+		if testData.isError && err == nil {
+			t.Error("Expected error but got success")
+		}
+	}
+}
+
+func TestKubernetesWorkloadGetMetricSpecForScaling(t *testing.T) {
+	for _, testData := range kubernetesWorkloadMetricIdentifiers {
+		meta, err := parseKubernetesWorkloadMetadata("default", testData.metadataTestData.metadata)
+		if err != nil {
+			t.Fatal("Could not parse metadata:", err)
+		}
+		mockKubernetesWorkloadScaler := kubernetesWorkloadScaler{meta, nil}
+
+		metricSpec := mockKubernetesWorkloadScaler.GetMetricSpecForScaling()
+		metricName := metricSpec[0].External.Metric.Name
+		if metricName != testData.name {
+			t.Error("Wrong External metric source name:", metricName)
+		}
+	}
+}
+
+func TestKubernetesWorkloadGetMetrics(t *testing.T) {
+	existing := []runtime.Object{
+		createWorkloadPod("frontend-1", "default", "frontend", true),
+		createWorkloadPod("frontend-2", "default", "frontend", false),
+		createWorkloadPod("frontend-3", "other", "frontend", true),
+		createWorkloadPod("backend-1", "default", "backend", true),
+		createCompletedWorkloadPod("frontend-4", "default", "frontend", corev1.PodSucceeded),
+		createCompletedWorkloadPod("frontend-5", "default", "frontend", corev1.PodFailed),
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "frontend", Namespace: "default"},
+			Status:     appsv1.DeploymentStatus{Replicas: 4, ReadyReplicas: 3},
+		},
+	}
+
+	tests := []struct {
+		metadata map[string]string
+		expected int64
+	}{
+		{map[string]string{"podSelector": "app=frontend"}, 2},
+		{map[string]string{"podSelector": "app=frontend", "onlyReady": "true"}, 1},
+		{map[string]string{"podSelector": "app in (frontend, backend)"}, 3},
+		{map[string]string{"deploymentName": "frontend"}, 4},
+		{map[string]string{"deploymentName": "frontend", "onlyReady": "true"}, 3},
+	}
+
+	for _, test := range tests {
+		s, err := NewKubernetesWorkloadScaler(fake.NewFakeClientWithScheme(scheme.Scheme, existing...), "default", test.metadata)
+		if err != nil {
+			t.Fatal("Could not create scaler:", err)
+		}
+
+		metrics, err := s.GetMetrics(context.TODO(), "workload", nil)
+		assert.NoError(t, err)
+		assert.Equal(t, test.expected, metrics[0].Value.Value(), "metadata %v", test.metadata)
+
+		isActive, err := s.IsActive(context.TODO())
+		assert.NoError(t, err)
+		assert.True(t, isActive)
+	}
+}
+
+func TestKubernetesWorkloadCompletedPodsNotActive(t *testing.T) {
+	existing := []runtime.Object{
+		createCompletedWorkloadPod("job-1", "default", "job", corev1.PodSucceeded),
+		createCompletedWorkloadPod("job-2", "default", "job", corev1.PodFailed),
+	}
+
+	s, err := NewKubernetesWorkloadScaler(fake.NewFakeClientWithScheme(scheme.Scheme, existing...), "default", map[string]string{"podSelector": "app=job"})
+	if err != nil {
+		t.Fatal("Could not create scaler:", err)
+	}
+
+	metrics, err := s.GetMetrics(context.TODO(), "workload", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), metrics[0].Value.Value())
+
+	isActive, err := s.IsActive(context.TODO())
+	assert.NoError(t, err)
+	assert.False(t, isActive)
+}
+
+func createWorkloadPod(name, namespace, app string, ready bool) *corev1.Pod {
+	status := corev1.ConditionFalse
+	if ready {
+		status = corev1.ConditionTrue
+	}
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: map[string]string{"app": app}},
+		Status: corev1.PodStatus{
+			Phase:      corev1.PodRunning,
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: status}},
+		},
+	}
+}
+
+// createCompletedWorkloadPod returns a pod whose containers have terminated, its Ready condition is left true
+// as the kubelet may not have updated it yet
+func createCompletedWorkloadPod(name, namespace, app string, phase corev1.PodPhase) *corev1.Pod {
+	pod := createWorkloadPod(name, namespace, app, true)
+	pod.Status.Phase = phase
+	return pod
+}
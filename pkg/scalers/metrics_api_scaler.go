@@ -1,13 +1,19 @@
 package scalers
 
 import (
+	"bytes"
 	"context"
+	"encoding/xml"
 	"errors"
 	"fmt"
 	"io/ioutil"
+	"math"
 	"net/http"
 	"strconv"
+	"strings"
 
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
 	"github.com/tidwall/gjson"
 	"k8s.io/api/autoscaling/v2beta2"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -26,9 +32,19 @@ type metricsAPIScaler struct {
 type metricsAPIScalerMetadata struct {
 	targetValue   int
 	url           string
+	format        string
 	valueLocation string
+	retryPolicy   kedautil.RetryPolicy
 }
 
+const (
+	jsonFormat       = "json"
+	xmlFormat        = "xml"
+	prometheusFormat = "prometheus"
+)
+
+var supportedFormats = []string{jsonFormat, xmlFormat, prometheusFormat}
+
 var httpLog = logf.Log.WithName("metrics_api_scaler")
 
 // NewMetricsAPIScaler creates a new HTTP scaler
@@ -59,12 +75,28 @@ func metricsAPIMetadata(metadata map[string]string) (*metricsAPIScalerMetadata,
 		return nil, fmt.Errorf("no url given in metadata")
 	}
 
+	meta.format = jsonFormat
+	if val, ok := metadata["format"]; ok && val != "" {
+		switch format := strings.ToLower(val); format {
+		case jsonFormat, xmlFormat, prometheusFormat:
+			meta.format = format
+		default:
+			return nil, fmt.Errorf("format %s not supported, supported formats are %s", val, strings.Join(supportedFormats, ", "))
+		}
+	}
+
 	if val, ok := metadata["valueLocation"]; ok {
 		meta.valueLocation = val
 	} else {
 		return nil, fmt.Errorf("no valueLocation given in metadata")
 	}
 
+	retryPolicy, err := parseHTTPRetryPolicy(metadata)
+	if err != nil {
+		return nil, err
+	}
+	meta.retryPolicy = retryPolicy
+
 	return &meta, nil
 }
 
@@ -78,8 +110,68 @@ func GetValueFromResponse(body []byte, valueLocation string) (int64, error) {
 	return int64(r.Num), nil
 }
 
-func (s *metricsAPIScaler) getMetricValue() (int64, error) {
-	r, err := http.Get(s.metadata.url)
+// getValueFromXMLResponse uses provided valueLocation, a path of elements like /root/items/item[2]/@count,
+// to access the numeric value in provided XML body. The path can start with // to match the first element at any depth.
+func getValueFromXMLResponse(body []byte, valueLocation string) (int64, error) {
+	root, err := parseXMLNode(body)
+	if err != nil {
+		return 0, err
+	}
+
+	text, err := root.query(valueLocation)
+	if err != nil {
+		return 0, err
+	}
+
+	v, err := strconv.ParseFloat(strings.TrimSpace(text), 64)
+	if err != nil {
+		return 0, fmt.Errorf("valueLocation must point to value of type number got: %s", text)
+	}
+	return int64(v), nil
+}
+
+// getValueFromPrometheusResponse uses provided valueLocation, a metric name with optional label matchers like
+// queue_length{queue="orders"}, to access the value in provided Prometheus text format body.
+// The values of all the matching series are summed.
+func getValueFromPrometheusResponse(body []byte, valueLocation string) (int64, error) {
+	name, matchers, err := parsePrometheusSeries(valueLocation)
+	if err != nil {
+		return 0, fmt.Errorf("error parsing valueLocation: %s", err)
+	}
+	samples, err := parsePrometheusSamples(body)
+	if err != nil {
+		return 0, err
+	}
+
+	found := false
+	var sum float64
+	for _, sample := range samples {
+		if sample.name != name || !matchesPrometheusLabels(sample.labels, matchers) {
+			continue
+		}
+		sum += sample.value
+		found = true
+	}
+
+	if !found {
+		return 0, fmt.Errorf("no series matching %s found", valueLocation)
+	}
+	return int64(sum), nil
+}
+
+func (s *metricsAPIScaler) getValue(body []byte) (int64, error) {
+	switch s.metadata.format {
+	case xmlFormat:
+		return getValueFromXMLResponse(body, s.metadata.valueLocation)
+	case prometheusFormat:
+		return getValueFromPrometheusResponse(body, s.metadata.valueLocation)
+	default:
+		return GetValueFromResponse(body, s.metadata.valueLocation)
+	}
+}
+
+func (s *metricsAPIScaler) getMetricValue(ctx context.Context) (int64, error) {
+	r, err := doHTTPGetWithRetry(ctx, nil, s.metadata.url, s.metadata.retryPolicy)
 	if err != nil {
 		return 0, err
 	}
@@ -94,7 +186,7 @@ func (s *metricsAPIScaler) getMetricValue() (int64, error) {
 	if err != nil {
 		return 0, err
 	}
-	v, err := GetValueFromResponse(b, s.metadata.valueLocation)
+	v, err := s.getValue(b)
 	if err != nil {
 		return 0, err
 	}
@@ -108,7 +200,7 @@ func (s *metricsAPIScaler) Close() error {
 
 // IsActive returns true if there are pending messages to be processed
 func (s *metricsAPIScaler) IsActive(ctx context.Context) (bool, error) {
-	v, err := s.getMetricValue()
+	v, err := s.getMetricValue(ctx)
 	if err != nil {
 		httpLog.Error(err, fmt.Sprintf("Error when checking metric value: %s", err))
 		return false, err
@@ -138,7 +230,7 @@ func (s *metricsAPIScaler) GetMetricSpecForScaling() []v2beta2.MetricSpec {
 
 // GetMetrics returns value for a supported metric and an error if there is a problem getting the metric
 func (s *metricsAPIScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
-	v, err := s.getMetricValue()
+	v, err := s.getMetricValue(ctx)
 	if err != nil {
 		return []external_metrics.ExternalMetricValue{}, fmt.Errorf("error requesting metrics endpoint: %s", err)
 	}
@@ -151,3 +243,223 @@ func (s *metricsAPIScaler) GetMetrics(ctx context.Context, metricName string, me
 
 	return append([]external_metrics.ExternalMetricValue{}, metric), nil
 }
+
+// xmlNode is a generic XML element used to evaluate the valueLocation of xml format
+type xmlNode struct {
+	XMLName  xml.Name
+	Attrs    []xml.Attr `xml:",any,attr"`
+	Content  string     `xml:",chardata"`
+	Children []xmlNode  `xml:",any"`
+}
+
+func parseXMLNode(body []byte) (*xmlNode, error) {
+	root := xmlNode{}
+	if err := xml.Unmarshal(body, &root); err != nil {
+		return nil, fmt.Errorf("error parsing xml response: %s", err)
+	}
+	return &root, nil
+}
+
+// query evaluates a path like /root/item[2]/@count starting at the node, which is the document root
+func (n *xmlNode) query(path string) (string, error) {
+	descendant := strings.HasPrefix(path, "//")
+	segments := strings.Split(strings.TrimLeft(path, "/"), "/")
+
+	attr := ""
+	if last := segments[len(segments)-1]; strings.HasPrefix(last, "@") {
+		attr = strings.TrimPrefix(last, "@")
+		segments = segments[:len(segments)-1]
+	}
+	if len(segments) == 0 || segments[0] == "" {
+		return "", fmt.Errorf("valueLocation %s doesn't select an element", path)
+	}
+
+	name, index, err := parseXMLPathSegment(segments[0])
+	if err != nil {
+		return "", err
+	}
+	var node *xmlNode
+	if descendant {
+		node = n.findDescendant(name, index)
+	} else if index == 1 && n.matches(name) {
+		node = n
+	}
+
+	for _, segment := range segments[1:] {
+		if node == nil {
+			break
+		}
+		name, index, err := parseXMLPathSegment(segment)
+		if err != nil {
+			return "", err
+		}
+		node = node.child(name, index)
+	}
+	if node == nil {
+		return "", fmt.Errorf("valueLocation %s doesn't match any element", path)
+	}
+
+	if attr == "" {
+		return node.Content, nil
+	}
+	for _, a := range node.Attrs {
+		if a.Name.Local == attr {
+			return a.Value, nil
+		}
+	}
+	return "", fmt.Errorf("valueLocation %s doesn't match any attribute", path)
+}
+
+func (n *xmlNode) matches(name string) bool {
+	return name == "*" || n.XMLName.Local == name
+}
+
+// child returns the index-th (starting at 1) child element with the name
+func (n *xmlNode) child(name string, index int) *xmlNode {
+	for i := range n.Children {
+		if n.Children[i].matches(name) {
+			index--
+			if index == 0 {
+				return &n.Children[i]
+			}
+		}
+	}
+	return nil
+}
+
+// findDescendant returns the index-th (starting at 1) element with the name in document order
+func (n *xmlNode) findDescendant(name string, index int) *xmlNode {
+	var found *xmlNode
+	var walk func(node *xmlNode)
+	walk = func(node *xmlNode) {
+		if found != nil {
+			return
+		}
+		if node.matches(name) {
+			index--
+			if index == 0 {
+				found = node
+				return
+			}
+		}
+		for i := range node.Children {
+			walk(&node.Children[i])
+		}
+	}
+	walk(n)
+	return found
+}
+
+// parseXMLPathSegment splits a segment like item[2] into the element name and its position
+func parseXMLPathSegment(segment string) (string, int, error) {
+	start := strings.Index(segment, "[")
+	if start < 0 {
+		return segment, 1, nil
+	}
+	if !strings.HasSuffix(segment, "]") {
+		return "", 0, fmt.Errorf("invalid path segment %s", segment)
+	}
+	index, err := strconv.Atoi(segment[start+1 : len(segment)-1])
+	if err != nil || index < 1 {
+		return "", 0, fmt.Errorf("invalid position in path segment %s", segment)
+	}
+	return segment[:start], index, nil
+}
+
+// prometheusSample is a sample of the Prometheus text format, the series of the summaries and histograms
+// are named as in the text format, e.g. the buckets of a histogram are the name_bucket series with a le label
+type prometheusSample struct {
+	name   string
+	labels map[string]string
+	value  float64
+}
+
+// parsePrometheusSamples parses the samples of a body in the Prometheus text format
+func parsePrometheusSamples(body []byte) ([]prometheusSample, error) {
+	var parser expfmt.TextParser
+	families, err := parser.TextToMetricFamilies(bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("error parsing prometheus response: %s", err)
+	}
+
+	var samples []prometheusSample
+	for name, family := range families {
+		for _, metric := range family.GetMetric() {
+			labels := make(map[string]string, len(metric.GetLabel()))
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			withLabel := func(name, value string) map[string]string {
+				series := make(map[string]string, len(labels)+1)
+				for k, v := range labels {
+					series[k] = v
+				}
+				series[name] = value
+				return series
+			}
+
+			switch family.GetType() {
+			case dto.MetricType_COUNTER:
+				samples = append(samples, prometheusSample{name: name, labels: labels, value: metric.GetCounter().GetValue()})
+			case dto.MetricType_GAUGE:
+				samples = append(samples, prometheusSample{name: name, labels: labels, value: metric.GetGauge().GetValue()})
+			case dto.MetricType_SUMMARY:
+				summary := metric.GetSummary()
+				for _, quantile := range summary.GetQuantile() {
+					samples = append(samples, prometheusSample{name: name, labels: withLabel("quantile", formatPrometheusFloat(quantile.GetQuantile())), value: quantile.GetValue()})
+				}
+				samples = append(samples, prometheusSample{name: name + "_sum", labels: labels, value: summary.GetSampleSum()},
+					prometheusSample{name: name + "_count", labels: labels, value: float64(summary.GetSampleCount())})
+			case dto.MetricType_HISTOGRAM:
+				histogram := metric.GetHistogram()
+				for _, bucket := range histogram.GetBucket() {
+					samples = append(samples, prometheusSample{name: name + "_bucket", labels: withLabel("le", formatPrometheusFloat(bucket.GetUpperBound())), value: float64(bucket.GetCumulativeCount())})
+				}
+				samples = append(samples, prometheusSample{name: name + "_sum", labels: labels, value: histogram.GetSampleSum()},
+					prometheusSample{name: name + "_count", labels: labels, value: float64(histogram.GetSampleCount())})
+			default:
+				samples = append(samples, prometheusSample{name: name, labels: labels, value: metric.GetUntyped().GetValue()})
+			}
+		}
+	}
+	return samples, nil
+}
+
+// parsePrometheusSeries parses a series like name{label="value"} into the metric name and its labels,
+// the series is parsed as a sample of the text format
+func parsePrometheusSeries(series string) (string, map[string]string, error) {
+	var parser expfmt.TextParser
+	families, err := parser.TextToMetricFamilies(strings.NewReader(strings.TrimSpace(series) + " 0\n"))
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid series %s: %s", series, err)
+	}
+	for name, family := range families {
+		labels := map[string]string{}
+		for _, label := range family.GetMetric()[0].GetLabel() {
+			labels[label.GetName()] = label.GetValue()
+		}
+		return name, labels, nil
+	}
+	return "", nil, fmt.Errorf("invalid series %s", series)
+}
+
+// formatPrometheusFloat formats the quantiles and the bucket bounds as the Prometheus text format does
+func formatPrometheusFloat(f float64) string {
+	switch {
+	case math.IsInf(f, 1):
+		return "+Inf"
+	case math.IsInf(f, -1):
+		return "-Inf"
+	default:
+		return strconv.FormatFloat(f, 'g', -1, 64)
+	}
+}
+
+func matchesPrometheusLabels(seriesLabels, matchers map[string]string) bool {
+	for k, v := range matchers {
+		if seriesLabels[k] != v {
+			return false
+		}
+	}
+	return true
+}
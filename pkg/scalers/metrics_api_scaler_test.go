@@ -22,6 +22,12 @@ var testMetricsAPIMetadata = []metricsAPIMetadataTestData{
 	{metadata: map[string]string{"valueLocation": "metric", "targetValue": "aa"}, raisesError: true},
 	// Missing targetValue
 	{metadata: map[string]string{"url": "http://dummy:1230/api/v1/", "valueLocation": "metric"}, raisesError: true},
+	// OK with xml format
+	{metadata: map[string]string{"url": "http://dummy:1230/api/v1/", "valueLocation": "/stats/tasks", "targetValue": "42", "format": "xml"}, raisesError: false},
+	// OK with prometheus format
+	{metadata: map[string]string{"url": "http://dummy:1230/metrics", "valueLocation": "queue_length", "targetValue": "42", "format": "Prometheus"}, raisesError: false},
+	// Unsupported format
+	{metadata: map[string]string{"url": "http://dummy:1230/api/v1/", "valueLocation": "metric", "targetValue": "42", "format": "yaml"}, raisesError: true},
 }
 
 func TestParseMetricsAPIMetadata(t *testing.T) {
@@ -54,3 +60,88 @@ func TestGetValueFromResponse(t *testing.T) {
 		t.Errorf("Expected %d got %d", 2, v)
 	}
 }
+
+func TestGetValueFromXMLResponse(t *testing.T) {
+	d := []byte(`<stats><queue name="orders" pending="12"><tasks>32</tasks></queue><queue name="emails" pending="3"><tasks>7.8</tasks></queue></stats>`)
+	tests := []struct {
+		valueLocation string
+		expected      int64
+		isError       bool
+	}{
+		{"/stats/queue/tasks", 32, false},
+		{"/stats/queue[2]/tasks", 7, false},
+		{"/stats/queue[2]/@pending", 3, false},
+		{"//tasks", 32, false},
+		{"//queue[2]/@pending", 3, false},
+		{"/stats/queue/@name", 0, true},
+		{"/stats/queue[3]/tasks", 0, true},
+		{"/other/queue/tasks", 0, true},
+		{"/stats/queue/@missing", 0, true},
+	}
+
+	for _, test := range tests {
+		v, err := getValueFromXMLResponse(d, test.valueLocation)
+		if err != nil && !test.isError {
+			t.Errorf("Expected success for %s but got error %s", test.valueLocation, err)
+		}
+		if err == nil && test.isError {
+			t.Errorf("Expected error for %s but got success", test.valueLocation)
+		}
+		if v != test.expected {
+			t.Errorf("Expected %d for %s got %d", test.expected, test.valueLocation, v)
+		}
+	}
+}
+
+func TestGetValueFromPrometheusResponse(t *testing.T) {
+	d := []byte(`# HELP queue_length Number of pending messages
+# TYPE queue_length gauge
+queue_length{queue="orders",region="eu"} 12
+queue_length{queue="orders",region="us"} 30 1610000000000
+queue_length{queue="emails, \"bulk\"",region="eu"} 4
+workers 3.7
+# TYPE request_duration_seconds histogram
+request_duration_seconds_bucket{le="0.5"} 8
+request_duration_seconds_bucket{le="+Inf"} 10
+request_duration_seconds_sum 4.2
+request_duration_seconds_count 10
+# TYPE rpc_seconds summary
+rpc_seconds{quantile="0.99"} 2.5
+rpc_seconds_sum 17
+rpc_seconds_count 9
+`)
+	tests := []struct {
+		valueLocation string
+		expected      int64
+		isError       bool
+	}{
+		{`queue_length{queue="orders",region="eu"}`, 12, false},
+		{`queue_length{queue="orders"}`, 42, false},
+		{`queue_length`, 46, false},
+		{`queue_length{queue="emails, \"bulk\""}`, 4, false},
+		{`workers`, 3, false},
+		{`queue_length{queue="missing"}`, 0, true},
+		{`queue_length{queue=orders}`, 0, true},
+		{`request_duration_seconds_bucket{le="+Inf"}`, 10, false},
+		{`request_duration_seconds_count`, 10, false},
+		{`rpc_seconds{quantile="0.99"}`, 2, false},
+		{`rpc_seconds_sum`, 17, false},
+	}
+
+	if _, err := getValueFromPrometheusResponse([]byte("queue_length{queue=\"orders\" 12\n"), "queue_length"); err == nil {
+		t.Error("Expected an error for a malformed response")
+	}
+
+	for _, test := range tests {
+		v, err := getValueFromPrometheusResponse(d, test.valueLocation)
+		if err != nil && !test.isError {
+			t.Errorf("Expected success for %s but got error %s", test.valueLocation, err)
+		}
+		if err == nil && test.isError {
+			t.Errorf("Expected error for %s but got success", test.valueLocation)
+		}
+		if v != test.expected {
+			t.Errorf("Expected %d for %s got %d", test.expected, test.valueLocation, v)
+		}
+	}
+}
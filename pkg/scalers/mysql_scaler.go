@@ -1,27 +1,14 @@
 package scalers
 
 import (
-	"context"
-	"database/sql"
 	"fmt"
 	"strconv"
 
 	"github.com/go-sql-driver/mysql"
-	"k8s.io/api/autoscaling/v2beta2"
-	"k8s.io/apimachinery/pkg/api/resource"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/labels"
-	"k8s.io/metrics/pkg/apis/external_metrics"
-	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
 	kedautil "github.com/kedacore/keda/pkg/util"
 )
 
-type mySQLScaler struct {
-	metadata   *mySQLMetadata
-	connection *sql.DB
-}
-
 type mySQLMetadata struct {
 	connectionString string // Database connection string
 	username         string
@@ -33,8 +20,6 @@ type mySQLMetadata struct {
 	queryValue       int
 }
 
-var mySQLLog = logf.Log.WithName("mysql_scaler")
-
 // NewMySQLScaler creates a new MySQL scaler
 func NewMySQLScaler(resolvedEnv, metadata, authParams map[string]string) (Scaler, error) {
 	meta, err := parseMySQLMetadata(resolvedEnv, metadata, authParams)
@@ -42,14 +27,7 @@ func NewMySQLScaler(resolvedEnv, metadata, authParams map[string]string) (Scaler
 		return nil, fmt.Errorf("error parsing MySQL metadata: %s", err)
 	}
 
-	conn, err := newMySQLConnection(meta)
-	if err != nil {
-		return nil, fmt.Errorf("error establishing MySQL connection: %s", err)
-	}
-	return &mySQLScaler{
-		metadata:   meta,
-		connection: conn,
-	}, nil
+	return newSQLScaler(meta.toSQLMetadata())
 }
 
 func parseMySQLMetadata(resolvedEnv, metadata, authParams map[string]string) (*mySQLMetadata, error) {
@@ -132,89 +110,17 @@ func metadataToConnectionStr(meta *mySQLMetadata) string {
 	return connStr
 }
 
-// newMySQLConnection creates MySQL db connection
-func newMySQLConnection(meta *mySQLMetadata) (*sql.DB, error) {
-	connStr := metadataToConnectionStr(meta)
-	db, err := sql.Open("mysql", connStr)
-	if err != nil {
-		mySQLLog.Error(err, fmt.Sprintf("Found error when opening connection: %s", err))
-		return nil, err
-	}
-	err = db.Ping()
-	if err != nil {
-		mySQLLog.Error(err, fmt.Sprintf("Found error when pinging database: %s", err))
-		return nil, err
-	}
-	return db, nil
-}
-
-// Close disposes of MySQL connections
-func (s *mySQLScaler) Close() error {
-	err := s.connection.Close()
-	if err != nil {
-		mySQLLog.Error(err, "Error closing MySQL connection")
-		return err
-	}
-	return nil
-}
-
-// IsActive returns true if there are pending messages to be processed
-func (s *mySQLScaler) IsActive(ctx context.Context) (bool, error) {
-	messages, err := s.getQueryResult()
-	if err != nil {
-		mySQLLog.Error(err, fmt.Sprintf("Error inspecting MySQL: %s", err))
-		return false, err
-	}
-	return messages > 0, nil
-}
-
-// getQueryResult returns result of the scaler query
-func (s *mySQLScaler) getQueryResult() (int, error) {
-	var value int
-	err := s.connection.QueryRow(s.metadata.query).Scan(&value)
-	if err != nil {
-		mySQLLog.Error(err, fmt.Sprintf("Could not query MySQL database: %s", err))
-		return 0, err
-	}
-	return value, nil
-}
-
-// GetMetricSpecForScaling returns the MetricSpec for the Horizontal Pod Autoscaler
-func (s *mySQLScaler) GetMetricSpecForScaling() []v2beta2.MetricSpec {
-	targetQueryValue := resource.NewQuantity(int64(s.metadata.queryValue), resource.DecimalSI)
-	metricName := "mysql"
-	if s.metadata.connectionString != "" {
-		metricName = kedautil.NormalizeString(fmt.Sprintf("%s-%s", metricName, s.metadata.connectionString))
-	} else {
-		metricName = kedautil.NormalizeString(fmt.Sprintf("%s-%s", metricName, s.metadata.dbName))
-	}
-	externalMetric := &v2beta2.ExternalMetricSource{
-		Metric: v2beta2.MetricIdentifier{
-			Name: metricName,
-		},
-		Target: v2beta2.MetricTarget{
-			Type:         v2beta2.AverageValueMetricType,
-			AverageValue: targetQueryValue,
-		},
-	}
-	metricSpec := v2beta2.MetricSpec{
-		External: externalMetric, Type: externalMetricType,
-	}
-	return []v2beta2.MetricSpec{metricSpec}
-}
-
-// GetMetrics returns value for a supported metric and an error if there is a problem getting the metric
-func (s *mySQLScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
-	num, err := s.getQueryResult()
-	if err != nil {
-		return []external_metrics.ExternalMetricValue{}, fmt.Errorf("error inspecting MySQL: %s", err)
+// toSQLMetadata returns the metadata of the sql scaler running the query, the metric name is kept for the existing HPAs
+func (meta *mySQLMetadata) toSQLMetadata() *sqlMetadata {
+	metricName := kedautil.NormalizeString(fmt.Sprintf("mysql-%s", meta.connectionString))
+	if meta.connectionString == "" {
+		metricName = kedautil.NormalizeString(fmt.Sprintf("mysql-%s", meta.dbName))
 	}
-
-	metric := external_metrics.ExternalMetricValue{
-		MetricName: metricName,
-		Value:      *resource.NewQuantity(int64(num), resource.DecimalSI),
-		Timestamp:  metav1.Now(),
+	return &sqlMetadata{
+		driver:           "mysql",
+		connection:       metadataToConnectionStr(meta),
+		query:            meta.query,
+		targetQueryValue: meta.queryValue,
+		metricName:       metricName,
 	}
-
-	return append([]external_metrics.ExternalMetricValue{}, metric), nil
 }
@@ -73,7 +73,7 @@ func TestMySQLGetMetricSpecForScaling(t *testing.T) {
 		if err != nil {
 			t.Fatal("Could not parse metadata:", err)
 		}
-		mockMySQLScaler := mySQLScaler{meta, nil}
+		mockMySQLScaler := sqlScaler{meta.toSQLMetadata(), nil}
 
 		metricSpec := mockMySQLScaler.GetMetricSpecForScaling()
 		metricName := metricSpec[0].External.Metric.Name
@@ -0,0 +1,340 @@
+package scalers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	v2beta2 "k8s.io/api/autoscaling/v2beta2"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/metrics/pkg/apis/external_metrics"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	kedautil "github.com/kedacore/keda/pkg/util"
+)
+
+const (
+	nsqLookupPath         = "/lookup"
+	nsqStatsPath          = "/stats"
+	defaultNSQDepth       = 10
+	nsqRequestTimeout     = 10 * time.Second
+	nsqDepthThresholdName = "depthThreshold"
+)
+
+type nsqScaler struct {
+	metadata   *nsqMetadata
+	httpClient *http.Client
+}
+
+type nsqMetadata struct {
+	// the nsqd nodes of the topic are looked up on the nsqlookupd nodes, unless the nsqd nodes are given
+	lookupdHTTPAddresses []string
+	nsqdHTTPAddresses    []string
+	topic                string
+	channel              string
+	depthThreshold       int64
+	retryPolicy          kedautil.RetryPolicy
+}
+
+// nsqLookupResponse is the response of nsqlookupd to the lookup of a topic, the versions before 1.0 wrap it in data
+type nsqLookupResponse struct {
+	Producers []nsqProducer `json:"producers"`
+	Data      struct {
+		Producers []nsqProducer `json:"producers"`
+	} `json:"data"`
+}
+
+type nsqProducer struct {
+	BroadcastAddress string `json:"broadcast_address"`
+	HTTPPort         int    `json:"http_port"`
+}
+
+// nsqStatsResponse is the part of the stats of nsqd used by the scaler, the versions before 1.0 wrap it in data
+type nsqStatsResponse struct {
+	Topics []nsqTopicStats `json:"topics"`
+	Data   struct {
+		Topics []nsqTopicStats `json:"topics"`
+	} `json:"data"`
+}
+
+type nsqTopicStats struct {
+	TopicName string `json:"topic_name"`
+	Depth     int64  `json:"depth"`
+	Channels  []struct {
+		ChannelName string `json:"channel_name"`
+		Depth       int64  `json:"depth"`
+	} `json:"channels"`
+}
+
+var nsqLog = logf.Log.WithName("nsq_scaler")
+
+// NewNSQScaler creates a new scaler for the depth of a topic or a channel of NSQ
+func NewNSQScaler(resolvedEnv, metadata map[string]string) (Scaler, error) {
+	meta, err := parseNSQMetadata(metadata)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing nsq metadata: %s", err)
+	}
+
+	return &nsqScaler{
+		metadata:   meta,
+		httpClient: &http.Client{Timeout: nsqRequestTimeout},
+	}, nil
+}
+
+func parseNSQMetadata(metadata map[string]string) (*nsqMetadata, error) {
+	meta := nsqMetadata{
+		depthThreshold: defaultNSQDepth,
+	}
+
+	useHTTPS := false
+	if val, ok := metadata["useHttps"]; ok && val != "" {
+		b, err := strconv.ParseBool(val)
+		if err != nil {
+			return nil, fmt.Errorf("can't parse useHttps: %s", err)
+		}
+		useHTTPS = b
+	}
+
+	meta.lookupdHTTPAddresses = parseNSQAddresses(metadata["nsqLookupdHTTPAddresses"], useHTTPS)
+	meta.nsqdHTTPAddresses = parseNSQAddresses(metadata["nsqdHTTPAddresses"], useHTTPS)
+	switch {
+	case len(meta.lookupdHTTPAddresses) == 0 && len(meta.nsqdHTTPAddresses) == 0:
+		return nil, fmt.Errorf("no nsqLookupdHTTPAddresses or nsqdHTTPAddresses given")
+	case len(meta.lookupdHTTPAddresses) != 0 && len(meta.nsqdHTTPAddresses) != 0:
+		return nil, fmt.Errorf("only one of nsqLookupdHTTPAddresses or nsqdHTTPAddresses can be given")
+	}
+
+	if val, ok := metadata["topic"]; ok && val != "" {
+		meta.topic = val
+	} else {
+		return nil, fmt.Errorf("no topic given")
+	}
+	meta.channel = metadata["channel"]
+
+	if val, ok := metadata[nsqDepthThresholdName]; ok && val != "" {
+		depthThreshold, err := strconv.ParseInt(val, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("can't parse %s: %s", nsqDepthThresholdName, err)
+		}
+		meta.depthThreshold = depthThreshold
+	}
+
+	retryPolicy, err := parseHTTPRetryPolicy(metadata)
+	if err != nil {
+		return nil, err
+	}
+	meta.retryPolicy = retryPolicy
+
+	return &meta, nil
+}
+
+// parseNSQAddresses parses a comma separated list of HTTP addresses, the addresses without a scheme get the http or https one
+func parseNSQAddresses(addresses string, useHTTPS bool) []string {
+	scheme := "http://"
+	if useHTTPS {
+		scheme = "https://"
+	}
+
+	var result []string
+	for _, address := range strings.Split(addresses, ",") {
+		address = strings.TrimSuffix(strings.TrimSpace(address), "/")
+		if address == "" {
+			continue
+		}
+		if !strings.HasPrefix(address, "http://") && !strings.HasPrefix(address, "https://") {
+			address = scheme + address
+		}
+		result = append(result, address)
+	}
+	return result
+}
+
+// IsActive returns true if there are messages in the topic or the channel
+func (s *nsqScaler) IsActive(ctx context.Context) (bool, error) {
+	depth, err := s.getDepth(ctx)
+	if err != nil {
+		nsqLog.Error(err, "error getting nsq depth", "topic", s.metadata.topic, "channel", s.metadata.channel)
+		return false, err
+	}
+
+	return depth > 0, nil
+}
+
+// getDepth sums the depth of the channel, or of the topic without channel, over the nsqd nodes of the topic
+func (s *nsqScaler) getDepth(ctx context.Context) (int64, error) {
+	nsqdAddresses := s.metadata.nsqdHTTPAddresses
+	if len(s.metadata.lookupdHTTPAddresses) != 0 {
+		addresses, err := s.lookupNSQDAddresses(ctx)
+		if err != nil {
+			return 0, err
+		}
+		nsqdAddresses = addresses
+	}
+
+	var depth int64
+	for _, address := range nsqdAddresses {
+		nodeDepth, err := s.getNSQDDepth(ctx, address)
+		if err != nil {
+			return 0, err
+		}
+		depth += nodeDepth
+	}
+	return depth, nil
+}
+
+// lookupNSQDAddresses returns the HTTP addresses of the nsqd nodes producing the topic, they are merged over the nsqlookupd nodes
+func (s *nsqScaler) lookupNSQDAddresses(ctx context.Context) ([]string, error) {
+	found := map[string]bool{}
+	var addresses []string
+	var lastErr error
+	reached := false
+	for _, lookupdAddress := range s.metadata.lookupdHTTPAddresses {
+		lookup := nsqLookupResponse{}
+		status, err := s.getJSON(ctx, fmt.Sprintf("%s%s?topic=%s", lookupdAddress, nsqLookupPath, url.QueryEscape(s.metadata.topic)), &lookup)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		reached = true
+		// the topic isn't produced on any nsqd node known by this nsqlookupd
+		if status == http.StatusNotFound {
+			continue
+		}
+
+		producers := lookup.Producers
+		if len(producers) == 0 {
+			producers = lookup.Data.Producers
+		}
+		scheme := lookupdAddress[:strings.Index(lookupdAddress, "://")+3]
+		for _, producer := range producers {
+			address := scheme + net.JoinHostPort(producer.BroadcastAddress, strconv.Itoa(producer.HTTPPort))
+			if !found[address] {
+				found[address] = true
+				addresses = append(addresses, address)
+			}
+		}
+	}
+
+	// the nsqd nodes are only unknown if no nsqlookupd could be reached
+	if !reached {
+		return nil, lastErr
+	}
+	return addresses, nil
+}
+
+func (s *nsqScaler) getNSQDDepth(ctx context.Context, nsqdAddress string) (int64, error) {
+	query := url.Values{}
+	query.Set("format", "json")
+	query.Set("topic", s.metadata.topic)
+
+	stats := nsqStatsResponse{}
+	status, err := s.getJSON(ctx, fmt.Sprintf("%s%s?%s", nsqdAddress, nsqStatsPath, query.Encode()), &stats)
+	if err != nil {
+		return 0, err
+	}
+	if status == http.StatusNotFound {
+		return 0, nil
+	}
+
+	topics := stats.Topics
+	if len(topics) == 0 {
+		topics = stats.Data.Topics
+	}
+	for _, topic := range topics {
+		if topic.TopicName != s.metadata.topic {
+			continue
+		}
+		if s.metadata.channel == "" {
+			return topic.Depth, nil
+		}
+		for _, channel := range topic.Channels {
+			if channel.ChannelName == s.metadata.channel {
+				return channel.Depth, nil
+			}
+		}
+		// a topic without channel keeps its messages until a consumer creates the first channel, which gets them
+		if len(topic.Channels) == 0 {
+			return topic.Depth, nil
+		}
+		return 0, nil
+	}
+	return 0, nil
+}
+
+// getJSON decodes the JSON response of the url into the target, a not found response isn't decoded
+func (s *nsqScaler) getJSON(ctx context.Context, url string, target interface{}) (int, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return 0, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Accept", "application/vnd.nsq; version=1.0")
+
+	resp, err := kedautil.DoWithRetry(s.httpClient, req, s.metadata.retryPolicy)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return resp.StatusCode, nil
+	default:
+		return resp.StatusCode, fmt.Errorf("nsq %s returned %d", url, resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(target); err != nil {
+		return resp.StatusCode, fmt.Errorf("error decoding nsq response: %s", err)
+	}
+	return resp.StatusCode, nil
+}
+
+// GetMetricSpecForScaling returns the MetricSpec for the Horizontal Pod Autoscaler
+func (s *nsqScaler) GetMetricSpecForScaling() []v2beta2.MetricSpec {
+	targetDepth := resource.NewQuantity(s.metadata.depthThreshold, resource.DecimalSI)
+	name := fmt.Sprintf("%s-%s", "nsq", s.metadata.topic)
+	if s.metadata.channel != "" {
+		name = fmt.Sprintf("%s-%s", name, s.metadata.channel)
+	}
+	externalMetric := &v2beta2.ExternalMetricSource{
+		Metric: v2beta2.MetricIdentifier{
+			Name: kedautil.NormalizeString(name),
+		},
+		Target: v2beta2.MetricTarget{
+			Type:         v2beta2.AverageValueMetricType,
+			AverageValue: targetDepth,
+		},
+	}
+	metricSpec := v2beta2.MetricSpec{External: externalMetric, Type: externalMetricType}
+	return []v2beta2.MetricSpec{metricSpec}
+}
+
+// GetMetrics returns value for a supported metric and an error if there is a problem getting the metric
+func (s *nsqScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
+	depth, err := s.getDepth(ctx)
+	if err != nil {
+		return []external_metrics.ExternalMetricValue{}, fmt.Errorf("error getting nsq depth: %s", err)
+	}
+
+	metric := external_metrics.ExternalMetricValue{
+		MetricName: metricName,
+		Value:      *resource.NewQuantity(depth, resource.DecimalSI),
+		Timestamp:  metav1.Now(),
+	}
+
+	return append([]external_metrics.ExternalMetricValue{}, metric), nil
+}
+
+// Close does nothing in case of nsqScaler
+func (s *nsqScaler) Close() error {
+	return nil
+}
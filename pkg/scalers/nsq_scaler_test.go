@@ -0,0 +1,148 @@
+package scalers
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type parseNSQMetadataTestData struct {
+	metadata map[string]string
+	isError  bool
+}
+
+type nsqMetricIdentifier struct {
+	metadataTestData *parseNSQMetadataTestData
+	name             string
+}
+
+var testNSQMetadata = []parseNSQMetadataTestData{
+	// nothing passed
+	{map[string]string{}, true},
+	// nsqlookupd nodes
+	{map[string]string{"nsqLookupdHTTPAddresses": "nsqlookupd-0:4161,nsqlookupd-1:4161", "topic": "orders", "channel": "billing", "depthThreshold": "100"}, false},
+	// nsqd nodes
+	{map[string]string{"nsqdHTTPAddresses": "https://nsqd-0:4152", "topic": "orders"}, false},
+	// both nsqlookupd and nsqd nodes
+	{map[string]string{"nsqLookupdHTTPAddresses": "nsqlookupd-0:4161", "nsqdHTTPAddresses": "nsqd-0:4151", "topic": "orders"}, true},
+	// missing topic
+	{map[string]string{"nsqLookupdHTTPAddresses": "nsqlookupd-0:4161"}, true},
+	// malformed depth threshold
+	{map[string]string{"nsqLookupdHTTPAddresses": "nsqlookupd-0:4161", "topic": "orders", "depthThreshold": "many"}, true},
+	// malformed useHttps
+	{map[string]string{"nsqLookupdHTTPAddresses": "nsqlookupd-0:4161", "topic": "orders", "useHttps": "maybe"}, true},
+}
+
+var nsqMetricIdentifiers = []nsqMetricIdentifier{
+	{&testNSQMetadata[1], "nsq-orders-billing"},
+	{&testNSQMetadata[2], "nsq-orders"},
+}
+
+func TestNSQParseMetadata(t *testing.T) {
+	for _, testData := range testNSQMetadata {
+		_, err := parseNSQMetadata(testData.metadata)
+		if err != nil && !testData.isError {
+			t.Error("Expected success but got error", err)
+		}
+		if testData.isError && err == nil {
+			t.Error("Expected error but got success")
+		}
+	}
+}
+
+func TestNSQParseAddresses(t *testing.T) {
+	assert.Equal(t, []string{"http://nsqd-0:4151", "https://nsqd-1:4152"}, parseNSQAddresses(" nsqd-0:4151, https://nsqd-1:4152/,", false))
+	assert.Equal(t, []string{"https://nsqd-0:4151"}, parseNSQAddresses("nsqd-0:4151", true))
+}
+
+func TestNSQGetMetricSpecForScaling(t *testing.T) {
+	for _, testData := range nsqMetricIdentifiers {
+		meta, err := parseNSQMetadata(testData.metadataTestData.metadata)
+		if err != nil {
+			t.Fatal("Could not parse metadata:", err)
+		}
+		mockNSQScaler := nsqScaler{meta, http.DefaultClient}
+
+		metricSpec := mockNSQScaler.GetMetricSpecForScaling()
+		metricName := metricSpec[0].External.Metric.Name
+		if metricName != testData.name {
+			t.Error("Wrong External metric source name:", metricName)
+		}
+	}
+}
+
+func newTestNSQD(t *testing.T, stats string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/stats", r.URL.Path)
+		assert.Equal(t, "json", r.URL.Query().Get("format"))
+		_, _ = w.Write([]byte(stats))
+	}))
+}
+
+func TestNSQGetDepth(t *testing.T) {
+	// nsqd 1.x
+	nsqd0 := newTestNSQD(t, `{"version":"1.2.0","topics":[{"topic_name":"orders","depth":5,"channels":[{"channel_name":"billing","depth":30},{"channel_name":"shipping","depth":3}]}]}`)
+	defer nsqd0.Close()
+	// nsqd 0.3
+	nsqd1 := newTestNSQD(t, `{"status_code":200,"data":{"topics":[{"topic_name":"orders","depth":2,"channels":[{"channel_name":"billing","depth":12}]}]}}`)
+	defer nsqd1.Close()
+	// no channel created yet
+	nsqd2 := newTestNSQD(t, `{"version":"1.2.0","topics":[{"topic_name":"orders","depth":7,"channels":[]}]}`)
+	defer nsqd2.Close()
+
+	producers := ""
+	for i, nsqd := range []*httptest.Server{nsqd0, nsqd1, nsqd2} {
+		u, _ := url.Parse(nsqd.URL)
+		host, port, _ := net.SplitHostPort(u.Host)
+		if i > 0 {
+			producers += ","
+		}
+		producers += fmt.Sprintf(`{"broadcast_address":"%s","http_port":%s}`, host, port)
+	}
+	lookupd := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/lookup", r.URL.Path)
+		if r.URL.Query().Get("topic") != "orders" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{"channels":["billing","shipping"],"producers":[` + producers + `]}`))
+	}))
+	defer lookupd.Close()
+
+	tests := []struct {
+		name     string
+		metadata map[string]string
+		depth    int64
+	}{
+		{"channel", map[string]string{"nsqLookupdHTTPAddresses": lookupd.URL, "topic": "orders", "channel": "billing"}, 49},
+		{"topic", map[string]string{"nsqLookupdHTTPAddresses": lookupd.URL, "topic": "orders"}, 14},
+		{"topic not produced", map[string]string{"nsqLookupdHTTPAddresses": lookupd.URL, "topic": "payments"}, 0},
+		{"nsqd nodes", map[string]string{"nsqdHTTPAddresses": nsqd0.URL + "," + nsqd1.URL, "topic": "orders", "channel": "shipping"}, 3},
+		{"unreachable nsqlookupd", map[string]string{"nsqLookupdHTTPAddresses": "127.0.0.1:1," + lookupd.URL, "topic": "orders", "channel": "billing", "httpRetryMaxAttempts": "1"}, 49},
+	}
+
+	for _, test := range tests {
+		meta, err := parseNSQMetadata(test.metadata)
+		if err != nil {
+			t.Fatal("Could not parse metadata:", err)
+		}
+		scaler := nsqScaler{meta, http.DefaultClient}
+
+		depth, err := scaler.getDepth(context.TODO())
+		assert.NoError(t, err, test.name)
+		assert.Equal(t, test.depth, depth, test.name)
+	}
+
+	meta, err := parseNSQMetadata(map[string]string{"nsqLookupdHTTPAddresses": "127.0.0.1:1", "topic": "orders", "httpRetryMaxAttempts": "1"})
+	if err != nil {
+		t.Fatal("Could not parse metadata:", err)
+	}
+	_, err = (&nsqScaler{meta, http.DefaultClient}).getDepth(context.TODO())
+	assert.Error(t, err, "no nsqlookupd reached")
+}
@@ -0,0 +1,339 @@
+package scalers
+
+import (
+	"context"
+	"crypto/rsa"
+	"crypto/tls"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gopcua/opcua"
+	"github.com/gopcua/opcua/ua"
+	v2beta2 "k8s.io/api/autoscaling/v2beta2"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/metrics/pkg/apis/external_metrics"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	defaultOPCUASecurityPolicy = "None"
+	defaultOPCUASecurityMode   = "None"
+	defaultOPCUATimeout        = 10 * time.Second
+	opcuaTargetValueName       = "targetValue"
+)
+
+var opcuaMetricNameUnsupportedChars = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
+
+var opcuaSecurityModes = map[string]ua.MessageSecurityMode{
+	"None":           ua.MessageSecurityModeNone,
+	"Sign":           ua.MessageSecurityModeSign,
+	"SignAndEncrypt": ua.MessageSecurityModeSignAndEncrypt,
+}
+
+type opcuaScaler struct {
+	metadata *opcuaMetadata
+}
+
+type opcuaMetadata struct {
+	endpoint        string
+	nodeID          *ua.NodeID
+	targetValue     float64
+	activationValue float64
+	timeout         time.Duration
+
+	// the secure channel is signed or encrypted with the client certificate, the session is anonymous without username
+	securityPolicy string
+	securityMode   ua.MessageSecurityMode
+	certificate    []byte
+	privateKey     *rsa.PrivateKey
+	username       string
+	password       string
+}
+
+var opcuaLog = logf.Log.WithName("opcua_scaler")
+
+// NewOPCUAScaler creates a new scaler for the numeric value of a node of an OPC UA server
+func NewOPCUAScaler(resolvedEnv, metadata, authParams map[string]string) (Scaler, error) {
+	meta, err := parseOPCUAMetadata(resolvedEnv, metadata, authParams)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing opcua metadata: %s", err)
+	}
+
+	return &opcuaScaler{
+		metadata: meta,
+	}, nil
+}
+
+func parseOPCUAMetadata(resolvedEnv, metadata, authParams map[string]string) (*opcuaMetadata, error) {
+	meta := opcuaMetadata{
+		timeout:        defaultOPCUATimeout,
+		securityPolicy: defaultOPCUASecurityPolicy,
+		securityMode:   opcuaSecurityModes[defaultOPCUASecurityMode],
+	}
+
+	if val, ok := metadata["endpoint"]; ok && val != "" {
+		meta.endpoint = val
+	} else if val, ok := metadata["endpointFromEnv"]; ok && val != "" {
+		meta.endpoint = resolvedEnv[val]
+	}
+	if meta.endpoint == "" {
+		return nil, fmt.Errorf("no endpoint given")
+	}
+	if !strings.HasPrefix(meta.endpoint, "opc.tcp://") {
+		return nil, fmt.Errorf("the endpoint has to be an opc.tcp:// address")
+	}
+
+	if val, ok := metadata["nodeId"]; ok && val != "" {
+		nodeID, err := ua.ParseNodeID(val)
+		if err != nil {
+			return nil, fmt.Errorf("can't parse nodeId: %s", err)
+		}
+		meta.nodeID = nodeID
+	} else {
+		return nil, fmt.Errorf("no nodeId given")
+	}
+
+	if val, ok := metadata[opcuaTargetValueName]; ok && val != "" {
+		targetValue, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			return nil, fmt.Errorf("can't parse %s: %s", opcuaTargetValueName, err)
+		}
+		meta.targetValue = targetValue
+	} else {
+		return nil, fmt.Errorf("no %s given", opcuaTargetValueName)
+	}
+
+	if val, ok := metadata["activationValue"]; ok && val != "" {
+		activationValue, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			return nil, fmt.Errorf("can't parse activationValue: %s", err)
+		}
+		meta.activationValue = activationValue
+	}
+
+	if val, ok := metadata["timeout"]; ok && val != "" {
+		timeout, err := strconv.Atoi(val)
+		if err != nil || timeout <= 0 {
+			return nil, fmt.Errorf("timeout %s is not a positive number of seconds", val)
+		}
+		meta.timeout = time.Duration(timeout) * time.Second
+	}
+
+	if err := parseOPCUASecurity(&meta, metadata, authParams); err != nil {
+		return nil, err
+	}
+
+	return &meta, nil
+}
+
+// parseOPCUASecurity parses the security of the secure channel and the user of the session,
+// a secured channel needs the PEM encoded cert and key of the client
+func parseOPCUASecurity(meta *opcuaMetadata, metadata, authParams map[string]string) error {
+	if val, ok := metadata["securityPolicy"]; ok && val != "" {
+		if _, ok := ua.SecurityPolicyURIs[val]; !ok {
+			return fmt.Errorf("securityPolicy %s not supported", val)
+		}
+		meta.securityPolicy = val
+	}
+	if val, ok := metadata["securityMode"]; ok && val != "" {
+		mode, ok := opcuaSecurityModes[val]
+		if !ok {
+			return fmt.Errorf("securityMode %s not supported, supported modes are None, Sign and SignAndEncrypt", val)
+		}
+		meta.securityMode = mode
+	}
+	if (meta.securityPolicy == defaultOPCUASecurityPolicy) != (meta.securityMode == ua.MessageSecurityModeNone) {
+		return fmt.Errorf("securityPolicy %s can't be used with securityMode %s", meta.securityPolicy, meta.securityMode)
+	}
+
+	if meta.securityPolicy != defaultOPCUASecurityPolicy {
+		if authParams["cert"] == "" || authParams["key"] == "" {
+			return fmt.Errorf("cert and key have to be given for securityPolicy %s", meta.securityPolicy)
+		}
+		keyPair, err := tls.X509KeyPair([]byte(authParams["cert"]), []byte(authParams["key"]))
+		if err != nil {
+			return fmt.Errorf("error parsing cert and key: %s", err)
+		}
+		privateKey, ok := keyPair.PrivateKey.(*rsa.PrivateKey)
+		if !ok {
+			return fmt.Errorf("key has to be an RSA private key")
+		}
+		meta.certificate = keyPair.Certificate[0]
+		meta.privateKey = privateKey
+	}
+
+	meta.username = authParams["username"]
+	meta.password = authParams["password"]
+	if meta.username == "" && meta.password != "" {
+		return fmt.Errorf("password can't be given without username")
+	}
+	return nil
+}
+
+// clientOptions returns the options of the client for the endpoint of the server matching the security of the trigger
+func (s *opcuaScaler) clientOptions(endpoint *ua.EndpointDescription) []opcua.Option {
+	options := []opcua.Option{
+		opcua.SecurityPolicy(s.metadata.securityPolicy),
+		opcua.SecurityMode(s.metadata.securityMode),
+		opcua.RequestTimeout(s.metadata.timeout),
+	}
+	if s.metadata.privateKey != nil {
+		options = append(options, opcua.PrivateKey(s.metadata.privateKey), opcua.Certificate(s.metadata.certificate))
+	}
+
+	authType := ua.UserTokenTypeAnonymous
+	if s.metadata.username != "" {
+		authType = ua.UserTokenTypeUserName
+		options = append(options, opcua.AuthUsername(s.metadata.username, s.metadata.password))
+	} else {
+		options = append(options, opcua.AuthAnonymous())
+	}
+	// the endpoint sets the server certificate and the policy of the user token, it comes after the authentication
+	return append(options, opcua.SecurityFromEndpoint(endpoint, authType))
+}
+
+// getEndpoint returns the endpoint of the server with the security policy and mode of the trigger
+func (s *opcuaScaler) getEndpoint(ctx context.Context) (*ua.EndpointDescription, error) {
+	client := opcua.NewClient(s.metadata.endpoint, opcua.RequestTimeout(s.metadata.timeout))
+	if err := client.Dial(ctx); err != nil {
+		return nil, fmt.Errorf("error connecting to opcua server %s: %s", s.metadata.endpoint, err)
+	}
+	defer client.Close()
+
+	response, err := client.GetEndpoints()
+	if err != nil {
+		return nil, fmt.Errorf("error getting the endpoints of opcua server %s: %s", s.metadata.endpoint, err)
+	}
+	endpoint := opcua.SelectEndpoint(response.Endpoints, s.metadata.securityPolicy, s.metadata.securityMode)
+	if endpoint == nil {
+		return nil, fmt.Errorf("opcua server %s has no endpoint with securityPolicy %s and securityMode %s", s.metadata.endpoint, s.metadata.securityPolicy, s.metadata.securityMode)
+	}
+	return endpoint, nil
+}
+
+// getValue reads the value of the node, a session is opened for each query. The configured endpoint is dialed
+// rather than the URL advertised by the server, which often isn't reachable from the cluster
+func (s *opcuaScaler) getValue(ctx context.Context) (float64, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.metadata.timeout)
+	defer cancel()
+
+	endpoint, err := s.getEndpoint(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	client := opcua.NewClient(s.metadata.endpoint, s.clientOptions(endpoint)...)
+	if err := client.Connect(ctx); err != nil {
+		return 0, fmt.Errorf("error opening opcua session on %s: %s", s.metadata.endpoint, err)
+	}
+	defer client.Close()
+
+	response, err := client.Read(&ua.ReadRequest{
+		NodesToRead: []*ua.ReadValueID{
+			{NodeID: s.metadata.nodeID, AttributeID: ua.AttributeIDValue},
+		},
+		TimestampsToReturn: ua.TimestampsToReturnNeither,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("error reading node %s: %s", s.metadata.nodeID, err)
+	}
+	if len(response.Results) == 0 {
+		return 0, fmt.Errorf("opcua server returned no value for node %s", s.metadata.nodeID)
+	}
+	return opcuaDataValue(s.metadata.nodeID, response.Results[0])
+}
+
+// opcuaDataValue returns the number of a data value holding a numeric or boolean scalar
+func opcuaDataValue(nodeID *ua.NodeID, dataValue *ua.DataValue) (float64, error) {
+	if dataValue.Status != ua.StatusOK {
+		return 0, fmt.Errorf("opcua server returned status %s for node %s", dataValue.Status, nodeID)
+	}
+	if dataValue.Value == nil {
+		return 0, fmt.Errorf("opcua server returned no value for node %s", nodeID)
+	}
+
+	switch v := dataValue.Value.Value().(type) {
+	case bool:
+		if v {
+			return 1, nil
+		}
+		return 0, nil
+	case int8:
+		return float64(v), nil
+	case byte:
+		return float64(v), nil
+	case int16:
+		return float64(v), nil
+	case uint16:
+		return float64(v), nil
+	case int32:
+		return float64(v), nil
+	case uint32:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	case uint64:
+		return float64(v), nil
+	case float32:
+		return float64(v), nil
+	case float64:
+		return v, nil
+	default:
+		return 0, fmt.Errorf("value of node %s has type %T, which is not a number", nodeID, v)
+	}
+}
+
+// IsActive returns true if the value of the node is greater than the activation value
+func (s *opcuaScaler) IsActive(ctx context.Context) (bool, error) {
+	value, err := s.getValue(ctx)
+	if err != nil {
+		opcuaLog.Error(err, "error getting opcua value", "nodeId", s.metadata.nodeID.String())
+		return false, err
+	}
+
+	return value > s.metadata.activationValue, nil
+}
+
+// Close has nothing to dispose, a session is opened for each query
+func (s *opcuaScaler) Close() error {
+	return nil
+}
+
+// GetMetricSpecForScaling returns the MetricSpec for the Horizontal Pod Autoscaler
+func (s *opcuaScaler) GetMetricSpecForScaling() []v2beta2.MetricSpec {
+	targetValue := resource.NewMilliQuantity(int64(math.Round(s.metadata.targetValue*1000)), resource.DecimalSI)
+	name := strings.Trim(opcuaMetricNameUnsupportedChars.ReplaceAllString(s.metadata.nodeID.String(), "-"), "-")
+	externalMetric := &v2beta2.ExternalMetricSource{
+		Metric: v2beta2.MetricIdentifier{
+			Name: fmt.Sprintf("%s-%s", "opcua", name),
+		},
+		Target: v2beta2.MetricTarget{
+			Type:         v2beta2.AverageValueMetricType,
+			AverageValue: targetValue,
+		},
+	}
+	metricSpec := v2beta2.MetricSpec{External: externalMetric, Type: externalMetricType}
+	return []v2beta2.MetricSpec{metricSpec}
+}
+
+// GetMetrics returns the value of the node
+func (s *opcuaScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
+	value, err := s.getValue(ctx)
+	if err != nil {
+		return []external_metrics.ExternalMetricValue{}, fmt.Errorf("error getting opcua value: %s", err)
+	}
+
+	metric := external_metrics.ExternalMetricValue{
+		MetricName: metricName,
+		Value:      *resource.NewMilliQuantity(int64(math.Round(value*1000)), resource.DecimalSI),
+		Timestamp:  metav1.Now(),
+	}
+
+	return append([]external_metrics.ExternalMetricValue{}, metric), nil
+}
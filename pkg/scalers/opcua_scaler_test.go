@@ -0,0 +1,167 @@
+package scalers
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/gopcua/opcua/ua"
+	"github.com/stretchr/testify/assert"
+)
+
+type parseOPCUAMetadataTestData struct {
+	metadata   map[string]string
+	authParams map[string]string
+	isError    bool
+}
+
+type opcuaMetricIdentifier struct {
+	metadataTestData *parseOPCUAMetadataTestData
+	name             string
+}
+
+var testOPCUAMetadata = []parseOPCUAMetadataTestData{
+	// nothing passed
+	{map[string]string{}, map[string]string{}, true},
+	// anonymous session without security
+	{map[string]string{"endpoint": "opc.tcp://gateway:4840", "nodeId": "ns=2;s=Line1.QueueDepth", "targetValue": "20"}, map[string]string{}, false},
+	// numeric node with username
+	{map[string]string{"endpoint": "opc.tcp://gateway:4840", "nodeId": "ns=3;i=1001", "targetValue": "2.5", "activationValue": "0.5", "timeout": "5"}, map[string]string{"username": "keda", "password": "secret"}, false},
+	// endpoint from env
+	{map[string]string{"endpointFromEnv": "OPCUA_ENDPOINT", "nodeId": "i=2258", "targetValue": "20"}, map[string]string{}, false},
+	// endpoint without opc.tcp scheme
+	{map[string]string{"endpoint": "http://gateway:4840", "nodeId": "i=2258", "targetValue": "20"}, map[string]string{}, true},
+	// missing nodeId
+	{map[string]string{"endpoint": "opc.tcp://gateway:4840", "targetValue": "20"}, map[string]string{}, true},
+	// malformed nodeId
+	{map[string]string{"endpoint": "opc.tcp://gateway:4840", "nodeId": "ns=two;s=Depth", "targetValue": "20"}, map[string]string{}, true},
+	// missing targetValue
+	{map[string]string{"endpoint": "opc.tcp://gateway:4840", "nodeId": "i=2258"}, map[string]string{}, true},
+	// malformed targetValue
+	{map[string]string{"endpoint": "opc.tcp://gateway:4840", "nodeId": "i=2258", "targetValue": "many"}, map[string]string{}, true},
+	// malformed activationValue
+	{map[string]string{"endpoint": "opc.tcp://gateway:4840", "nodeId": "i=2258", "targetValue": "20", "activationValue": "few"}, map[string]string{}, true},
+	// malformed timeout
+	{map[string]string{"endpoint": "opc.tcp://gateway:4840", "nodeId": "i=2258", "targetValue": "20", "timeout": "-1"}, map[string]string{}, true},
+	// unsupported securityPolicy
+	{map[string]string{"endpoint": "opc.tcp://gateway:4840", "nodeId": "i=2258", "targetValue": "20", "securityPolicy": "Basic512", "securityMode": "Sign"}, map[string]string{}, true},
+	// unsupported securityMode
+	{map[string]string{"endpoint": "opc.tcp://gateway:4840", "nodeId": "i=2258", "targetValue": "20", "securityPolicy": "Basic256Sha256", "securityMode": "Encrypt"}, map[string]string{}, true},
+	// securityPolicy without securityMode
+	{map[string]string{"endpoint": "opc.tcp://gateway:4840", "nodeId": "i=2258", "targetValue": "20", "securityPolicy": "Basic256Sha256"}, map[string]string{}, true},
+	// securityPolicy without cert and key
+	{map[string]string{"endpoint": "opc.tcp://gateway:4840", "nodeId": "i=2258", "targetValue": "20", "securityPolicy": "Basic256Sha256", "securityMode": "SignAndEncrypt"}, map[string]string{}, true},
+	// password without username
+	{map[string]string{"endpoint": "opc.tcp://gateway:4840", "nodeId": "i=2258", "targetValue": "20"}, map[string]string{"password": "secret"}, true},
+}
+
+var opcuaMetricIdentifiers = []opcuaMetricIdentifier{
+	{&testOPCUAMetadata[1], "opcua-ns-2-s-Line1-QueueDepth"},
+	{&testOPCUAMetadata[2], "opcua-ns-3-i-1001"},
+	{&testOPCUAMetadata[3], "opcua-i-2258"},
+}
+
+func TestOPCUAParseMetadata(t *testing.T) {
+	for _, testData := range testOPCUAMetadata {
+		_, err := parseOPCUAMetadata(map[string]string{"OPCUA_ENDPOINT": "opc.tcp://gateway:4840"}, testData.metadata, testData.authParams)
+		if err != nil && !testData.isError {
+			t.Error("Expected success but got error", err)
+		}
+		if testData.isError && err == nil {
+			t.Error("Expected error but got success")
+		}
+	}
+}
+
+func TestOPCUAParseSecurity(t *testing.T) {
+	metadata := map[string]string{"endpoint": "opc.tcp://gateway:4840", "nodeId": "i=2258", "targetValue": "20", "securityPolicy": "Basic256Sha256", "securityMode": "SignAndEncrypt"}
+
+	certPEM, keyPEM := createTestRSACertificate(t)
+	meta, err := parseOPCUAMetadata(map[string]string{}, metadata, map[string]string{"cert": certPEM, "key": keyPEM})
+	assert.NoError(t, err)
+	assert.Equal(t, ua.MessageSecurityModeSignAndEncrypt, meta.securityMode)
+	assert.NotNil(t, meta.privateKey)
+	assert.NotEmpty(t, meta.certificate)
+
+	_, err = parseOPCUAMetadata(map[string]string{}, metadata, map[string]string{"cert": certPEM, "key": "not a key"})
+	assert.Error(t, err)
+
+	// the secure channel only supports RSA keys
+	ecCertPEM, ecKeyPEM := createTestCertificate(t)
+	_, err = parseOPCUAMetadata(map[string]string{}, metadata, map[string]string{"cert": ecCertPEM, "key": ecKeyPEM})
+	assert.Error(t, err)
+}
+
+func TestOPCUAGetMetricSpecForScaling(t *testing.T) {
+	for _, testData := range opcuaMetricIdentifiers {
+		meta, err := parseOPCUAMetadata(map[string]string{"OPCUA_ENDPOINT": "opc.tcp://gateway:4840"}, testData.metadataTestData.metadata, testData.metadataTestData.authParams)
+		if err != nil {
+			t.Fatal("Could not parse metadata:", err)
+		}
+		mockOPCUAScaler := opcuaScaler{meta}
+
+		metricSpec := mockOPCUAScaler.GetMetricSpecForScaling()
+		metricName := metricSpec[0].External.Metric.Name
+		if metricName != testData.name {
+			t.Error("Wrong External metric source name:", metricName)
+		}
+	}
+
+	meta, _ := parseOPCUAMetadata(map[string]string{}, testOPCUAMetadata[2].metadata, testOPCUAMetadata[2].authParams)
+	metricSpec := (&opcuaScaler{meta}).GetMetricSpecForScaling()
+	assert.Equal(t, int64(2500), metricSpec[0].External.Target.AverageValue.MilliValue())
+}
+
+func TestOPCUADataValue(t *testing.T) {
+	nodeID := ua.NewStringNodeID(2, "Line1.QueueDepth")
+	tests := []struct {
+		dataValue *ua.DataValue
+		value     float64
+		isError   bool
+	}{
+		{&ua.DataValue{Value: ua.MustVariant(int32(-4))}, -4, false},
+		{&ua.DataValue{Value: ua.MustVariant(uint16(12))}, 12, false},
+		{&ua.DataValue{Value: ua.MustVariant(uint64(1 << 40))}, 1 << 40, false},
+		{&ua.DataValue{Value: ua.MustVariant(float32(1.5))}, 1.5, false},
+		{&ua.DataValue{Value: ua.MustVariant(2.25)}, 2.25, false},
+		{&ua.DataValue{Value: ua.MustVariant(true)}, 1, false},
+		{&ua.DataValue{Value: ua.MustVariant("busy")}, 0, true},
+		{&ua.DataValue{}, 0, true},
+		{&ua.DataValue{Value: ua.MustVariant(int32(3)), Status: ua.StatusBadNodeIDUnknown}, 0, true},
+	}
+
+	for _, test := range tests {
+		value, err := opcuaDataValue(nodeID, test.dataValue)
+		if test.isError {
+			assert.Error(t, err)
+			continue
+		}
+		assert.NoError(t, err)
+		assert.Equal(t, test.value, value)
+	}
+}
+
+func createTestRSACertificate(t *testing.T) (string, string) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "keda-opcua-client"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	return string(certPEM), string(keyPEM)
+}
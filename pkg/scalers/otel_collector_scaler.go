@@ -0,0 +1,282 @@
+package scalers
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	v2beta2 "k8s.io/api/autoscaling/v2beta2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/metrics/pkg/apis/external_metrics"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	kedautil "github.com/kedacore/keda/pkg/util"
+)
+
+const (
+	otelCollectorQueueSizeMetric  = "otelcol_exporter_queue_size"
+	otelCollectorQueueSizeName    = "queueSize"
+	defaultOTelCollectorQueueSize = 100
+	defaultOTelCollectorPort      = 8888
+	defaultOTelCollectorPath      = "/metrics"
+	otelCollectorRequestTimeout   = 5 * time.Second
+)
+
+type otelCollectorScaler struct {
+	metadata   *otelCollectorMetadata
+	kubeClient client.Client
+	httpClient *http.Client
+}
+
+type otelCollectorMetadata struct {
+	// the telemetry endpoints of the collectors are the metricsPort and metricsPath of the pods matching podSelector,
+	// unless their URLs are given in collectorURLs
+	podSelector   labels.Selector
+	collectorURLs []string
+	metricsPort   int
+	metricsPath   string
+	namespace     string
+	// exporter filters the queues summed to the queues of one exporter
+	exporter    string
+	queueSize   int64
+	retryPolicy kedautil.RetryPolicy
+}
+
+var otelCollectorLog = logf.Log.WithName("otel_collector_scaler")
+
+// NewOTelCollectorScaler creates a new scaler for the size of the sending queues of the exporters of OpenTelemetry Collectors
+func NewOTelCollectorScaler(kubeClient client.Client, namespace string, metadata map[string]string) (Scaler, error) {
+	meta, err := parseOTelCollectorMetadata(namespace, metadata)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing otel collector metadata: %s", err)
+	}
+
+	return &otelCollectorScaler{
+		metadata:   meta,
+		kubeClient: kubeClient,
+		httpClient: &http.Client{Timeout: otelCollectorRequestTimeout},
+	}, nil
+}
+
+func parseOTelCollectorMetadata(namespace string, metadata map[string]string) (*otelCollectorMetadata, error) {
+	meta := otelCollectorMetadata{
+		metricsPort: defaultOTelCollectorPort,
+		metricsPath: defaultOTelCollectorPath,
+		namespace:   namespace,
+		queueSize:   defaultOTelCollectorQueueSize,
+	}
+
+	podSelector := metadata["podSelector"]
+	collectorURLs := metadata["collectorURLs"]
+	switch {
+	case podSelector != "" && collectorURLs != "":
+		return nil, fmt.Errorf("only one of podSelector or collectorURLs can be given")
+	case podSelector != "":
+		selector, err := labels.Parse(podSelector)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing podSelector: %s", err)
+		}
+		meta.podSelector = selector
+	case collectorURLs != "":
+		for _, collectorURL := range strings.Split(collectorURLs, ",") {
+			if collectorURL = strings.TrimSpace(collectorURL); collectorURL != "" {
+				meta.collectorURLs = append(meta.collectorURLs, collectorURL)
+			}
+		}
+	}
+	if meta.podSelector == nil && len(meta.collectorURLs) == 0 {
+		return nil, fmt.Errorf("no podSelector or collectorURLs given")
+	}
+
+	if val, ok := metadata["metricsPort"]; ok && val != "" {
+		port, err := strconv.Atoi(val)
+		if err != nil || port <= 0 || port > 65535 {
+			return nil, fmt.Errorf("metricsPort %s is not a valid port", val)
+		}
+		meta.metricsPort = port
+	}
+	if val, ok := metadata["metricsPath"]; ok && val != "" {
+		meta.metricsPath = "/" + strings.TrimPrefix(val, "/")
+	}
+
+	meta.exporter = metadata["exporter"]
+
+	if val, ok := metadata[otelCollectorQueueSizeName]; ok && val != "" {
+		queueSize, err := strconv.ParseInt(val, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("can't parse %s: %s", otelCollectorQueueSizeName, err)
+		}
+		meta.queueSize = queueSize
+	}
+
+	retryPolicy, err := parseHTTPRetryPolicy(metadata)
+	if err != nil {
+		return nil, err
+	}
+	meta.retryPolicy = retryPolicy
+
+	return &meta, nil
+}
+
+// IsActive returns true if there are spans, metrics or logs queued in the exporters of the collectors
+func (s *otelCollectorScaler) IsActive(ctx context.Context) (bool, error) {
+	queueSize, err := s.getQueueSize(ctx)
+	if err != nil {
+		otelCollectorLog.Error(err, "error getting otel collector queue size")
+		return false, err
+	}
+
+	return queueSize > 0, nil
+}
+
+// Close does nothing in case of otelCollectorScaler
+func (s *otelCollectorScaler) Close() error {
+	return nil
+}
+
+// getCollectorURLs returns the URLs of the telemetry endpoints of the collectors, the running pods of the selector
+// are scraped on their IP
+func (s *otelCollectorScaler) getCollectorURLs(ctx context.Context) ([]string, error) {
+	if len(s.metadata.collectorURLs) != 0 {
+		return s.metadata.collectorURLs, nil
+	}
+
+	pods := &corev1.PodList{}
+	err := s.kubeClient.List(ctx, pods, client.InNamespace(s.metadata.namespace), client.MatchingLabelsSelector{Selector: s.metadata.podSelector})
+	if err != nil {
+		return nil, err
+	}
+
+	var collectorURLs []string
+	for _, pod := range pods.Items {
+		if pod.Status.Phase != corev1.PodRunning || pod.Status.PodIP == "" || pod.DeletionTimestamp != nil {
+			continue
+		}
+		host := net.JoinHostPort(pod.Status.PodIP, strconv.Itoa(s.metadata.metricsPort))
+		collectorURLs = append(collectorURLs, fmt.Sprintf("http://%s%s", host, s.metadata.metricsPath))
+	}
+	return collectorURLs, nil
+}
+
+// getQueueSize sums the sizes of the exporter queues over the collectors, a collector that can't be scraped is skipped
+// as long as one collector is scraped, a collector starting or stopping has no telemetry endpoint
+func (s *otelCollectorScaler) getQueueSize(ctx context.Context) (int64, error) {
+	collectorURLs, err := s.getCollectorURLs(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if len(collectorURLs) == 0 {
+		return 0, nil
+	}
+
+	var queueSize int64
+	var lastErr error
+	scraped := false
+	for _, collectorURL := range collectorURLs {
+		collectorQueueSize, err := s.getCollectorQueueSize(ctx, collectorURL)
+		if err != nil {
+			otelCollectorLog.V(1).Info("Failed to scrape otel collector", "url", collectorURL, "error", err.Error())
+			lastErr = err
+			continue
+		}
+		scraped = true
+		queueSize += collectorQueueSize
+	}
+	if !scraped {
+		return 0, lastErr
+	}
+	return queueSize, nil
+}
+
+func (s *otelCollectorScaler) getCollectorQueueSize(ctx context.Context, collectorURL string) (int64, error) {
+	req, err := http.NewRequest(http.MethodGet, collectorURL, nil)
+	if err != nil {
+		return 0, err
+	}
+	req = req.WithContext(ctx)
+
+	resp, err := kedautil.DoWithRetry(s.httpClient, req, s.metadata.retryPolicy)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("otel collector %s returned %d", collectorURL, resp.StatusCode)
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return 0, err
+	}
+
+	return s.sumQueueSizes(string(body))
+}
+
+// sumQueueSizes sums the otelcol_exporter_queue_size series of the exporter, or of all the exporters, in the telemetry of a collector
+func (s *otelCollectorScaler) sumQueueSizes(telemetry string) (int64, error) {
+	matchers := map[string]string{}
+	if s.metadata.exporter != "" {
+		matchers["exporter"] = s.metadata.exporter
+	}
+
+	samples, err := parsePrometheusSamples([]byte(telemetry))
+	if err != nil {
+		return 0, err
+	}
+	var queueSize float64
+	for _, sample := range samples {
+		if sample.name != otelCollectorQueueSizeMetric || !matchesPrometheusLabels(sample.labels, matchers) {
+			continue
+		}
+		if !math.IsNaN(sample.value) {
+			queueSize += sample.value
+		}
+	}
+	return int64(queueSize), nil
+}
+
+// GetMetricSpecForScaling returns the MetricSpec for the Horizontal Pod Autoscaler
+func (s *otelCollectorScaler) GetMetricSpecForScaling() []v2beta2.MetricSpec {
+	targetQueueSize := resource.NewQuantity(s.metadata.queueSize, resource.DecimalSI)
+	name := "otel-collector"
+	if s.metadata.exporter != "" {
+		name = fmt.Sprintf("%s-%s", name, s.metadata.exporter)
+	}
+	externalMetric := &v2beta2.ExternalMetricSource{
+		Metric: v2beta2.MetricIdentifier{
+			Name: kedautil.NormalizeString(name),
+		},
+		Target: v2beta2.MetricTarget{
+			Type:         v2beta2.AverageValueMetricType,
+			AverageValue: targetQueueSize,
+		},
+	}
+	metricSpec := v2beta2.MetricSpec{External: externalMetric, Type: externalMetricType}
+	return []v2beta2.MetricSpec{metricSpec}
+}
+
+// GetMetrics returns the size of the exporter queues summed over the collectors
+func (s *otelCollectorScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
+	queueSize, err := s.getQueueSize(ctx)
+	if err != nil {
+		return []external_metrics.ExternalMetricValue{}, fmt.Errorf("error getting otel collector queue size: %s", err)
+	}
+
+	metric := external_metrics.ExternalMetricValue{
+		MetricName: metricName,
+		Value:      *resource.NewQuantity(queueSize, resource.DecimalSI),
+		Timestamp:  metav1.Now(),
+	}
+
+	return append([]external_metrics.ExternalMetricValue{}, metric), nil
+}
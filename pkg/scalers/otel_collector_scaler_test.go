@@ -0,0 +1,156 @@
+package scalers
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+type parseOTelCollectorMetadataTestData struct {
+	metadata map[string]string
+	isError  bool
+}
+
+type otelCollectorMetricIdentifier struct {
+	metadataTestData *parseOTelCollectorMetadataTestData
+	name             string
+}
+
+var testOTelCollectorMetadata = []parseOTelCollectorMetadataTestData{
+	// nothing passed
+	{map[string]string{}, true},
+	// properly formed podSelector
+	{map[string]string{"podSelector": "app=otel-collector", "queueSize": "500", "exporter": "otlp/tempo"}, false},
+	// properly formed collectorURLs
+	{map[string]string{"collectorURLs": "http://otel-collector-0:8888/metrics, http://otel-collector-1:8888/metrics"}, false},
+	// both podSelector and collectorURLs
+	{map[string]string{"podSelector": "app=otel-collector", "collectorURLs": "http://otel-collector:8888/metrics"}, true},
+	// malformed podSelector
+	{map[string]string{"podSelector": "app==="}, true},
+	// malformed metricsPort
+	{map[string]string{"podSelector": "app=otel-collector", "metricsPort": "88888"}, true},
+	// malformed queueSize
+	{map[string]string{"podSelector": "app=otel-collector", "queueSize": "many"}, true},
+}
+
+var otelCollectorMetricIdentifiers = []otelCollectorMetricIdentifier{
+	{&testOTelCollectorMetadata[1], "otel-collector-otlp-tempo"},
+	{&testOTelCollectorMetadata[2], "otel-collector"},
+}
+
+const testOTelCollectorTelemetry = `# HELP otelcol_exporter_queue_capacity Fixed capacity of the retry queue (in batches)
+# TYPE otelcol_exporter_queue_capacity gauge
+otelcol_exporter_queue_capacity{exporter="otlp/tempo",service_instance_id="0"} 5000
+# HELP otelcol_exporter_queue_size Current size of the retry queue (in batches)
+# TYPE otelcol_exporter_queue_size gauge
+otelcol_exporter_queue_size{exporter="otlp/tempo",service_instance_id="0"} 120
+otelcol_exporter_queue_size{exporter="prometheusremotewrite",service_instance_id="0"} 30
+`
+
+func TestOTelCollectorParseMetadata(t *testing.T) {
+	for _, testData := range testOTelCollectorMetadata {
+		_, err := parseOTelCollectorMetadata("default", testData.metadata)
+		if err != nil && !testData.isError {
+			t.Error("Expected success but got error", err)
+		}
+		if testData.isError && err == nil {
+			t.Error("Expected error but got success")
+		}
+	}
+}
+
+func TestOTelCollectorGetMetricSpecForScaling(t *testing.T) {
+	for _, testData := range otelCollectorMetricIdentifiers {
+		meta, err := parseOTelCollectorMetadata("default", testData.metadataTestData.metadata)
+		if err != nil {
+			t.Fatal("Could not parse metadata:", err)
+		}
+		mockOTelCollectorScaler := otelCollectorScaler{metadata: meta}
+
+		metricSpec := mockOTelCollectorScaler.GetMetricSpecForScaling()
+		metricName := metricSpec[0].External.Metric.Name
+		if metricName != testData.name {
+			t.Error("Wrong External metric source name:", metricName)
+		}
+	}
+}
+
+func TestOTelCollectorSumQueueSizes(t *testing.T) {
+	tests := []struct {
+		exporter  string
+		queueSize int64
+	}{
+		{"", 150},
+		{"otlp/tempo", 120},
+		{"logging", 0},
+	}
+
+	for _, test := range tests {
+		s := otelCollectorScaler{metadata: &otelCollectorMetadata{exporter: test.exporter}}
+		queueSize, err := s.sumQueueSizes(testOTelCollectorTelemetry)
+		assert.NoError(t, err)
+		assert.Equal(t, test.queueSize, queueSize, test.exporter)
+	}
+}
+
+func newOTelCollectorPod(name, ip string, phase corev1.PodPhase) runtime.Object {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: map[string]string{"app": "otel-collector"}},
+		Status:     corev1.PodStatus{Phase: phase, PodIP: ip},
+	}
+}
+
+func TestOTelCollectorGetQueueSize(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/metrics", r.URL.Path)
+		_, _ = w.Write([]byte(testOTelCollectorTelemetry))
+	}))
+	defer server.Close()
+	serverURL, _ := url.Parse(server.URL)
+	host, port, _ := net.SplitHostPort(serverURL.Host)
+
+	pods := []runtime.Object{
+		newOTelCollectorPod("otel-collector-0", host, corev1.PodRunning),
+		newOTelCollectorPod("otel-collector-1", host, corev1.PodRunning),
+		// not scraped
+		newOTelCollectorPod("otel-collector-2", host, corev1.PodPending),
+	}
+
+	tests := []struct {
+		name      string
+		metadata  map[string]string
+		queueSize int64
+		isError   bool
+	}{
+		{"pods", map[string]string{"podSelector": "app=otel-collector", "metricsPort": port, "exporter": "otlp/tempo"}, 240, false},
+		{"no pods", map[string]string{"podSelector": "app=gateway", "metricsPort": port}, 0, false},
+		{"collector URLs", map[string]string{"collectorURLs": server.URL + "/metrics"}, 150, false},
+		{"one collector unreachable", map[string]string{"collectorURLs": "http://127.0.0.1:1/metrics," + server.URL + "/metrics", "httpRetryMaxAttempts": "1"}, 150, false},
+		{"no collector reachable", map[string]string{"collectorURLs": "http://127.0.0.1:1/metrics", "httpRetryMaxAttempts": "1"}, 0, true},
+	}
+
+	for _, test := range tests {
+		s, err := NewOTelCollectorScaler(fake.NewFakeClientWithScheme(scheme.Scheme, pods...), "default", test.metadata)
+		if err != nil {
+			t.Fatal("Could not create scaler:", err)
+		}
+
+		queueSize, err := s.(*otelCollectorScaler).getQueueSize(context.TODO())
+		if test.isError {
+			assert.Error(t, err, test.name)
+			continue
+		}
+		assert.NoError(t, err, test.name)
+		assert.Equal(t, test.queueSize, queueSize, test.name)
+	}
+}
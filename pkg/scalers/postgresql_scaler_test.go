@@ -32,7 +32,7 @@ func TestPosgresSQLGetMetricSpecForScaling(t *testing.T) {
 		if err != nil {
 			t.Fatal("Could not parse metadata:", err)
 		}
-		mockPostgresSQLScaler := postgreSQLScaler{meta, nil}
+		mockPostgresSQLScaler := sqlScaler{meta.toSQLMetadata(), nil}
 
 		metricSpec := mockPostgresSQLScaler.GetMetricSpecForScaling()
 		metricName := metricSpec[0].External.Metric.Name
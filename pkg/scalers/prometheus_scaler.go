@@ -5,9 +5,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"math"
 	"net/http"
 	url_pkg "net/url"
+	"sort"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	v2beta2 "k8s.io/api/autoscaling/v2beta2"
@@ -25,6 +29,23 @@ const (
 	promMetricName    = "metricName"
 	promQuery         = "query"
 	promThreshold     = "threshold"
+	promQueryRange    = "queryRange"
+	promQueryStep     = "queryStep"
+	promAggregation   = "aggregation"
+	promCustomHeaders = "customHeaders"
+	promPartialResp   = "partialResponse"
+	promDeduplicate   = "deduplicate"
+	promIgnoreNull    = "ignoreNullValues"
+
+	defaultPromQueryStep = 30 * time.Second
+)
+
+// supported aggregations of the values of a range query
+const (
+	promAggregationAvg = "avg"
+	promAggregationMax = "max"
+	promAggregationMin = "min"
+	promAggregationP95 = "p95"
 )
 
 type prometheusScaler struct {
@@ -36,6 +57,27 @@ type prometheusMetadata struct {
 	metricName    string
 	query         string
 	threshold     int
+	retryPolicy   kedautil.RetryPolicy
+	// batchWindow is the time the identical queries sent to the server are collected for, so they are sent once, 0 to send every query
+	batchWindow time.Duration
+	// queryRange is the range of the query aggregated with the aggregation function, 0 for an instant query
+	queryRange  time.Duration
+	queryStep   time.Duration
+	aggregation string
+	// customHeaders are sent with every query, e.g. the X-Scope-OrgID tenant header of Cortex and Mimir
+	customHeaders map[string]string
+	// partialResponse and deduplicate are the partial_response and dedup parameters of the Thanos queries, nil to use the default of the server
+	partialResponse *bool
+	deduplicate     *bool
+	// ignoreNullValues makes an empty result or a null value 0, the query returns an error otherwise
+	ignoreNullValues bool
+}
+
+// promRequest is a query sent to the Prometheus HTTP API, an instant query without range
+type promRequest struct {
+	query      string
+	queryRange time.Duration
+	queryStep  time.Duration
 }
 
 type promQueryResult struct {
@@ -45,13 +87,17 @@ type promQueryResult struct {
 		Result     []struct {
 			Metric struct {
 			} `json:"metric"`
-			Value []interface{} `json:"value"`
+			Value  []interface{}   `json:"value"`
+			Values [][]interface{} `json:"values"`
 		} `json:"result"`
 	} `json:"data"`
 }
 
 var prometheusLog = logf.Log.WithName("prometheus_scaler")
 
+// prometheusBatcher multiplexes the identical queries of the triggers sent to the same Prometheus server
+var prometheusBatcher = newQueryBatcher(0)
+
 // NewPrometheusScaler creates a new prometheusScaler
 func NewPrometheusScaler(resolvedEnv, metadata map[string]string) (Scaler, error) {
 	meta, err := parsePrometheusMetadata(metadata)
@@ -65,7 +111,9 @@ func NewPrometheusScaler(resolvedEnv, metadata map[string]string) (Scaler, error
 }
 
 func parsePrometheusMetadata(metadata map[string]string) (*prometheusMetadata, error) {
-	meta := prometheusMetadata{}
+	meta := prometheusMetadata{
+		ignoreNullValues: true,
+	}
 
 	if val, ok := metadata[promServerAddress]; ok && val != "" {
 		meta.serverAddress = val
@@ -94,11 +142,91 @@ func parsePrometheusMetadata(metadata map[string]string) (*prometheusMetadata, e
 		meta.threshold = t
 	}
 
+	retryPolicy, err := parseHTTPRetryPolicy(metadata)
+	if err != nil {
+		return nil, err
+	}
+	meta.retryPolicy = retryPolicy
+
+	batchWindow, err := parseQueryBatchWindow(metadata)
+	if err != nil {
+		return nil, err
+	}
+	meta.batchWindow = batchWindow
+
+	if val, ok := metadata[promQueryRange]; ok && val != "" {
+		queryRange, err := time.ParseDuration(val)
+		if err != nil || queryRange <= 0 {
+			return nil, fmt.Errorf("error parsing %s: %s must be a positive duration", promQueryRange, val)
+		}
+		meta.queryRange = queryRange
+
+		meta.queryStep = defaultPromQueryStep
+		if val, ok := metadata[promQueryStep]; ok && val != "" {
+			queryStep, err := time.ParseDuration(val)
+			if err != nil || queryStep <= 0 {
+				return nil, fmt.Errorf("error parsing %s: %s must be a positive duration", promQueryStep, val)
+			}
+			meta.queryStep = queryStep
+		}
+
+		meta.aggregation = promAggregationAvg
+		if val, ok := metadata[promAggregation]; ok && val != "" {
+			switch val {
+			case promAggregationAvg, promAggregationMax, promAggregationMin, promAggregationP95:
+				meta.aggregation = val
+			default:
+				return nil, fmt.Errorf("%s %s not supported, supported aggregations are avg, max, min and p95", promAggregation, val)
+			}
+		}
+	} else if metadata[promAggregation] != "" || metadata[promQueryStep] != "" {
+		return nil, fmt.Errorf("%s and %s can only be given with %s", promAggregation, promQueryStep, promQueryRange)
+	}
+
+	if val, ok := metadata[promCustomHeaders]; ok && val != "" {
+		customHeaders, err := parsePrometheusCustomHeaders(val)
+		if err != nil {
+			return nil, err
+		}
+		meta.customHeaders = customHeaders
+	}
+
+	if val, ok := metadata[promIgnoreNull]; ok && val != "" {
+		ignoreNullValues, err := strconv.ParseBool(val)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing %s: %s", promIgnoreNull, err)
+		}
+		meta.ignoreNullValues = ignoreNullValues
+	}
+
+	for key, flag := range map[string]**bool{promPartialResp: &meta.partialResponse, promDeduplicate: &meta.deduplicate} {
+		if val, ok := metadata[key]; ok && val != "" {
+			b, err := strconv.ParseBool(val)
+			if err != nil {
+				return nil, fmt.Errorf("error parsing %s: %s", key, err)
+			}
+			*flag = &b
+		}
+	}
+
 	return &meta, nil
 }
 
+// parsePrometheusCustomHeaders parses a comma separated list of headers like X-Scope-OrgID=tenant-1,X-Team=payments
+func parsePrometheusCustomHeaders(headers string) (map[string]string, error) {
+	customHeaders := map[string]string{}
+	for _, header := range strings.Split(headers, ",") {
+		parts := strings.SplitN(header, "=", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" || strings.TrimSpace(parts[1]) == "" {
+			return nil, fmt.Errorf("%s not in the correct format. Should be name=value,name=value", promCustomHeaders)
+		}
+		customHeaders[http.CanonicalHeaderKey(strings.TrimSpace(parts[0]))] = strings.TrimSpace(parts[1])
+	}
+	return customHeaders, nil
+}
+
 func (s *prometheusScaler) IsActive(ctx context.Context) (bool, error) {
-	val, err := s.ExecutePromQuery()
+	val, err := s.ExecutePromQuery(ctx)
 	if err != nil {
 		prometheusLog.Error(err, "error executing prometheus query")
 		return false, err
@@ -128,20 +256,90 @@ func (s *prometheusScaler) GetMetricSpecForScaling() []v2beta2.MetricSpec {
 	return []v2beta2.MetricSpec{metricSpec}
 }
 
-func (s *prometheusScaler) ExecutePromQuery() (float64, error) {
-	t := time.Now().UTC().Format(time.RFC3339)
-	queryEscaped := url_pkg.QueryEscape(s.metadata.query)
+// queryPrometheus returns the body of the response to the query of the trigger. With a batch window, the Prometheus HTTP API
+// having no batch endpoint, the identical queries of the triggers sent to the same server within the window are sent once.
+func (s *prometheusScaler) queryPrometheus(ctx context.Context) ([]byte, error) {
+	request := promRequest{query: s.metadata.query, queryRange: s.metadata.queryRange, queryStep: s.metadata.queryStep}
+	if s.metadata.batchWindow == 0 {
+		return s.executePromRequest(ctx, request)
+	}
+
+	endpoint := fmt.Sprintf("%s|%v|%s", s.metadata.serverAddress, s.metadata.retryPolicy, s.requestSettings())
+	query := batchedQuery{key: fmt.Sprintf("%s|%s|%s", request.query, request.queryRange, request.queryStep), payload: request}
+	response := prometheusBatcher.do(ctx, endpoint, s.metadata.batchWindow, query, func(queries []batchedQuery) []batchedResponse {
+		responses := make([]batchedResponse, len(queries))
+		var wg sync.WaitGroup
+		for i, query := range queries {
+			wg.Add(1)
+			go func(i int, request promRequest) {
+				defer wg.Done()
+				// the batch is shared by the triggers, it isn't bound to the context of one of them
+				responses[i].body, responses[i].err = s.executePromRequest(context.Background(), request)
+			}(i, query.payload.(promRequest))
+		}
+		wg.Wait()
+		return responses
+	})
+	return response.body, response.err
+}
+
+// requestSettings returns the headers and the Thanos parameters of the queries, the queries of the triggers with
+// different settings, e.g. of different tenants, aren't batched together
+func (s *prometheusScaler) requestSettings() string {
+	settings := make([]string, 0, len(s.metadata.customHeaders)+2)
+	for name, value := range s.metadata.customHeaders {
+		settings = append(settings, name+"="+value)
+	}
+	sort.Strings(settings)
+	return fmt.Sprintf("%v|%v|%v", settings, promFlagString(s.metadata.partialResponse), promFlagString(s.metadata.deduplicate))
+}
+
+func promFlagString(flag *bool) string {
+	if flag == nil {
+		return ""
+	}
+	return strconv.FormatBool(*flag)
+}
+
+func (s *prometheusScaler) executePromRequest(ctx context.Context, request promRequest) ([]byte, error) {
+	now := time.Now().UTC()
+	t := now.Format(time.RFC3339)
+	queryEscaped := url_pkg.QueryEscape(request.query)
 	url := fmt.Sprintf("%s/api/v1/query?query=%s&time=%s", s.metadata.serverAddress, queryEscaped, t)
-	r, err := http.Get(url)
+	if request.queryRange > 0 {
+		start := now.Add(-request.queryRange).Format(time.RFC3339)
+		url = fmt.Sprintf("%s/api/v1/query_range?query=%s&start=%s&end=%s&step=%s", s.metadata.serverAddress, queryEscaped, start, t,
+			strconv.FormatFloat(request.queryStep.Seconds(), 'f', -1, 64))
+	}
+	if s.metadata.partialResponse != nil {
+		url += "&partial_response=" + strconv.FormatBool(*s.metadata.partialResponse)
+	}
+	if s.metadata.deduplicate != nil {
+		url += "&dedup=" + strconv.FormatBool(*s.metadata.deduplicate)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
-		return -1, err
+		return nil, err
+	}
+	for name, value := range s.metadata.customHeaders {
+		req.Header.Set(name, value)
 	}
 
-	b, err := ioutil.ReadAll(r.Body)
+	r, err := kedautil.DoWithRetry(nil, req, s.metadata.retryPolicy)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Body.Close()
+
+	return ioutil.ReadAll(r.Body)
+}
+
+func (s *prometheusScaler) ExecutePromQuery(ctx context.Context) (float64, error) {
+	b, err := s.queryPrometheus(ctx)
 	if err != nil {
 		return -1, err
 	}
-	r.Body.Close()
 
 	var result promQueryResult
 	err = json.Unmarshal(b, &result)
@@ -149,30 +347,94 @@ func (s *prometheusScaler) ExecutePromQuery() (float64, error) {
 		return -1, err
 	}
 
-	var v float64 = -1
-
 	// allow for zero element or single element result sets
 	if len(result.Data.Result) == 0 {
-		return 0, nil
+		return s.nullValue("an empty result")
 	} else if len(result.Data.Result) > 1 {
 		return -1, fmt.Errorf("Prometheus query %s returned multiple elements", s.metadata.query)
 	}
 
-	val := result.Data.Result[0].Value[1]
-	if val != nil {
-		s := val.(string)
-		v, err = strconv.ParseFloat(s, 64)
+	if s.metadata.queryRange > 0 {
+		return s.aggregateRangeValues(result.Data.Result[0].Values)
+	}
+
+	if len(result.Data.Result[0].Value) != 2 || result.Data.Result[0].Value[1] == nil {
+		return s.nullValue("a null value")
+	}
+	val, ok := result.Data.Result[0].Value[1].(string)
+	if !ok {
+		return -1, fmt.Errorf("Prometheus query %s returned an invalid value", s.metadata.query)
+	}
+	v, err := strconv.ParseFloat(val, 64)
+	if err != nil {
+		prometheusLog.Error(err, "Error converting prometheus value", "prometheus_value", val)
+		return -1, err
+	}
+	if math.IsNaN(v) {
+		return s.nullValue("a NaN value")
+	}
+
+	return v, nil
+}
+
+// nullValue returns 0 for an empty result or a null value of the query if they are ignored, an error otherwise
+func (s *prometheusScaler) nullValue(result string) (float64, error) {
+	if s.metadata.ignoreNullValues {
+		return 0, nil
+	}
+	return -1, fmt.Errorf("Prometheus query %s returned %s", s.metadata.query, result)
+}
+
+// aggregateRangeValues returns the values of the series of a range query aggregated with the aggregation function,
+// the NaN values are skipped
+func (s *prometheusScaler) aggregateRangeValues(samples [][]interface{}) (float64, error) {
+	values := make([]float64, 0, len(samples))
+	for _, sample := range samples {
+		if len(sample) != 2 {
+			return -1, fmt.Errorf("Prometheus query %s returned an invalid sample", s.metadata.query)
+		}
+		str, ok := sample[1].(string)
+		if !ok {
+			return -1, fmt.Errorf("Prometheus query %s returned an invalid sample", s.metadata.query)
+		}
+		v, err := strconv.ParseFloat(str, 64)
 		if err != nil {
-			prometheusLog.Error(err, "Error converting prometheus value", "prometheus_value", s)
+			prometheusLog.Error(err, "Error converting prometheus value", "prometheus_value", str)
 			return -1, err
 		}
+		if !math.IsNaN(v) {
+			values = append(values, v)
+		}
+	}
+	if len(values) == 0 {
+		return s.nullValue("no value in the range")
 	}
 
-	return v, nil
+	return aggregatePromValues(values, s.metadata.aggregation), nil
+}
+
+func aggregatePromValues(values []float64, aggregation string) float64 {
+	sort.Float64s(values)
+	switch aggregation {
+	case promAggregationMax:
+		return values[len(values)-1]
+	case promAggregationMin:
+		return values[0]
+	case promAggregationP95:
+		// nearest-rank percentile
+		rank := int(math.Ceil(0.95 * float64(len(values))))
+		return values[rank-1]
+	default:
+		var sum float64
+		for _, v := range values {
+			sum += v
+		}
+		return sum / float64(len(values))
+	}
 }
 
 func (s *prometheusScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
-	val, err := s.ExecutePromQuery()
+	val, err := s.ExecutePromQuery(ctx)
 	if err != nil {
 		prometheusLog.Error(err, "error executing prometheus query")
 		return []external_metrics.ExternalMetricValue{}, err
@@ -1,6 +1,12 @@
 package scalers
 
 import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
 	"testing"
 )
 
@@ -28,6 +34,30 @@ var testPromMetadata = []parsePrometheusMetadataTestData{
 	{map[string]string{"serverAddress": "http://localhost:9090", "metricName": "http_requests_total", "threshold": "100", "query": "", "disableScaleToZero": "true"}, true},
 	// all properly formed, default disableScaleToZero
 	{map[string]string{"serverAddress": "http://localhost:9090", "metricName": "http_requests_total", "threshold": "100", "query": "up"}, false},
+	// batched queries
+	{map[string]string{"serverAddress": "http://localhost:9090", "metricName": "http_requests_total", "threshold": "100", "query": "up", "batchWindow": "200ms"}, false},
+	// malformed batch window
+	{map[string]string{"serverAddress": "http://localhost:9090", "metricName": "http_requests_total", "threshold": "100", "query": "up", "batchWindow": "200"}, true},
+	// range query with the default aggregation
+	{map[string]string{"serverAddress": "http://localhost:9090", "metricName": "http_requests_total", "threshold": "100", "query": "up", "queryRange": "5m"}, false},
+	// range query with an aggregation and a step
+	{map[string]string{"serverAddress": "http://localhost:9090", "metricName": "http_requests_total", "threshold": "100", "query": "up", "queryRange": "5m", "queryStep": "15s", "aggregation": "p95"}, false},
+	// unsupported aggregation
+	{map[string]string{"serverAddress": "http://localhost:9090", "metricName": "http_requests_total", "threshold": "100", "query": "up", "queryRange": "5m", "aggregation": "median"}, true},
+	// malformed query range
+	{map[string]string{"serverAddress": "http://localhost:9090", "metricName": "http_requests_total", "threshold": "100", "query": "up", "queryRange": "5"}, true},
+	// aggregation without query range
+	{map[string]string{"serverAddress": "http://localhost:9090", "metricName": "http_requests_total", "threshold": "100", "query": "up", "aggregation": "max"}, true},
+	// tenant header and Thanos parameters
+	{map[string]string{"serverAddress": "http://localhost:9090", "metricName": "http_requests_total", "threshold": "100", "query": "up", "customHeaders": "X-Scope-OrgID=tenant-1", "partialResponse": "false", "deduplicate": "true"}, false},
+	// malformed custom headers
+	{map[string]string{"serverAddress": "http://localhost:9090", "metricName": "http_requests_total", "threshold": "100", "query": "up", "customHeaders": "X-Scope-OrgID"}, true},
+	// malformed partial response
+	{map[string]string{"serverAddress": "http://localhost:9090", "metricName": "http_requests_total", "threshold": "100", "query": "up", "partialResponse": "maybe"}, true},
+	// error on null values
+	{map[string]string{"serverAddress": "http://localhost:9090", "metricName": "http_requests_total", "threshold": "100", "query": "up", "ignoreNullValues": "false"}, false},
+	// malformed ignore null values
+	{map[string]string{"serverAddress": "http://localhost:9090", "metricName": "http_requests_total", "threshold": "100", "query": "up", "ignoreNullValues": "no way"}, true},
 }
 
 var prometheusMetricIdentifiers = []prometheusMetricIdentifier{
@@ -61,3 +91,165 @@ func TestPrometheusGetMetricSpecForScaling(t *testing.T) {
 		}
 	}
 }
+
+func TestPrometheusBatchedQueries(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		fmt.Fprintf(w, `{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[0,"%d"]}]}}`, len(r.URL.Query().Get("query")))
+	}))
+	defer server.Close()
+
+	var wg sync.WaitGroup
+	values := make([]int64, 3)
+	for i, query := range []string{"up", "up", "down_total"} {
+		meta, err := parsePrometheusMetadata(map[string]string{"serverAddress": server.URL, "metricName": "m", "query": query, "batchWindow": "100ms"})
+		if err != nil {
+			t.Fatalf("Expected no error, got %s", err)
+		}
+		wg.Add(1)
+		go func(i int, scaler *prometheusScaler) {
+			defer wg.Done()
+			metrics, err := scaler.GetMetrics(context.TODO(), "m", nil)
+			if err != nil {
+				t.Errorf("Expected no error, got %s", err)
+				return
+			}
+			values[i] = metrics[0].Value.Value()
+		}(i, &prometheusScaler{metadata: meta})
+	}
+	wg.Wait()
+
+	// the identical queries within the window are sent once
+	if requests != 2 {
+		t.Errorf("Expected 2 requests, got %d", requests)
+	}
+	if values[0] != 2 || values[1] != 2 || values[2] != 10 {
+		t.Errorf("Expected the values of the queries 2, 2 and 10, got %v", values)
+	}
+}
+
+func TestPrometheusRangeQuery(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/query_range" || r.URL.Query().Get("step") != "15" || r.URL.Query().Get("start") == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		fmt.Fprint(w, `{"status":"success","data":{"resultType":"matrix","result":[{"metric":{},"values":[[0,"10"],[15,"NaN"],[30,"40"],[45,"20"],[60,"30"]]}]}}`)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		aggregation string
+		value       int64
+	}{
+		{"", 25},
+		{"avg", 25},
+		{"max", 40},
+		{"min", 10},
+		{"p95", 40},
+	}
+
+	for _, test := range tests {
+		meta, err := parsePrometheusMetadata(map[string]string{"serverAddress": server.URL, "metricName": "m", "query": "up", "queryRange": "1m", "queryStep": "15s", "aggregation": test.aggregation})
+		if err != nil {
+			t.Fatalf("Expected no error, got %s", err)
+		}
+		metrics, err := (&prometheusScaler{metadata: meta}).GetMetrics(context.TODO(), "m", nil)
+		if err != nil {
+			t.Errorf("%s: expected no error, got %s", test.aggregation, err)
+			continue
+		}
+		if value := metrics[0].Value.Value(); value != test.value {
+			t.Errorf("%s: expected %d, got %d", test.aggregation, test.value, value)
+		}
+	}
+}
+
+func TestAggregatePromValues(t *testing.T) {
+	values := make([]float64, 100)
+	for i := range values {
+		values[i] = float64(100 - i)
+	}
+	if p95 := aggregatePromValues(values, promAggregationP95); p95 != 95 {
+		t.Errorf("Expected a p95 of 95, got %v", p95)
+	}
+}
+
+func TestPrometheusTenantQueries(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if r.URL.Query().Get("partial_response") != "false" || r.URL.Query().Get("dedup") != "" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		fmt.Fprintf(w, `{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[0,"%d"]}]}}`, len(r.Header.Get("X-Scope-OrgID")))
+	}))
+	defer server.Close()
+
+	// the identical queries of different tenants aren't batched together
+	var wg sync.WaitGroup
+	values := make([]int64, 3)
+	for i, tenant := range []string{"a", "a", "tenant-b"} {
+		meta, err := parsePrometheusMetadata(map[string]string{"serverAddress": server.URL, "metricName": "m", "query": "up", "batchWindow": "100ms",
+			"customHeaders": "x-scope-orgid=" + tenant, "partialResponse": "false"})
+		if err != nil {
+			t.Fatalf("Expected no error, got %s", err)
+		}
+		wg.Add(1)
+		go func(i int, scaler *prometheusScaler) {
+			defer wg.Done()
+			metrics, err := scaler.GetMetrics(context.TODO(), "m", nil)
+			if err != nil {
+				t.Errorf("Expected no error, got %s", err)
+				return
+			}
+			values[i] = metrics[0].Value.Value()
+		}(i, &prometheusScaler{metadata: meta})
+	}
+	wg.Wait()
+
+	if requests != 2 {
+		t.Errorf("Expected 2 requests, got %d", requests)
+	}
+	if values[0] != 1 || values[1] != 1 || values[2] != 8 {
+		t.Errorf("Expected the values of the tenants 1, 1 and 8, got %v", values)
+	}
+}
+
+func TestPrometheusNullValues(t *testing.T) {
+	tests := []struct {
+		name             string
+		response         string
+		ignoreNullValues string
+		value            float64
+		isError          bool
+	}{
+		{"empty result ignored", `{"status":"success","data":{"resultType":"vector","result":[]}}`, "", 0, false},
+		{"empty result", `{"status":"success","data":{"resultType":"vector","result":[]}}`, "false", -1, true},
+		{"NaN value ignored", `{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[0,"NaN"]}]}}`, "true", 0, false},
+		{"NaN value", `{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[0,"NaN"]}]}}`, "false", -1, true},
+		{"value", `{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[0,"12"]}]}}`, "false", 12, false},
+	}
+
+	for _, test := range tests {
+		response := test.response
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, response)
+		}))
+
+		meta, err := parsePrometheusMetadata(map[string]string{"serverAddress": server.URL, "metricName": "m", "query": "up", "ignoreNullValues": test.ignoreNullValues})
+		if err != nil {
+			t.Fatalf("Expected no error, got %s", err)
+		}
+		value, err := (&prometheusScaler{metadata: meta}).ExecutePromQuery(context.TODO())
+		if test.isError != (err != nil) {
+			t.Errorf("%s: expected error %t, got %v", test.name, test.isError, err)
+		}
+		if value != test.value {
+			t.Errorf("%s: expected %v, got %v", test.name, test.value, value)
+		}
+		server.Close()
+	}
+}
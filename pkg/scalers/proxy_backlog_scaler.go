@@ -0,0 +1,291 @@
+package scalers
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	v2beta2 "k8s.io/api/autoscaling/v2beta2"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/metrics/pkg/apis/external_metrics"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	kedautil "github.com/kedacore/keda/pkg/util"
+)
+
+const (
+	haproxyProxyType   = "haproxy"
+	nginxPlusProxyType = "nginx-plus"
+
+	proxyQueueMetric       = "queue"
+	proxyConnectionsMetric = "connections"
+
+	// haproxyBackendSvName is the service name of the aggregated row of a backend in the HAProxy stats
+	haproxyBackendSvName    = "BACKEND"
+	defaultProxyTargetValue = 10
+	proxyRequestTimeout     = 5 * time.Second
+)
+
+type proxyBacklogScaler struct {
+	metadata   *proxyBacklogMetadata
+	httpClient *http.Client
+}
+
+type proxyBacklogMetadata struct {
+	proxyType   string
+	statsURL    string
+	backend     string
+	metric      string
+	targetValue int
+	username    string
+	password    string
+	retryPolicy kedautil.RetryPolicy
+}
+
+// nginxPlusUpstream is the part of an upstream of the NGINX Plus API used by the scaler
+type nginxPlusUpstream struct {
+	Peers []struct {
+		Active int64 `json:"active"`
+	} `json:"peers"`
+	Queue *struct {
+		Size int64 `json:"size"`
+	} `json:"queue"`
+}
+
+var proxyBacklogLog = logf.Log.WithName("proxy_backlog_scaler")
+
+// NewProxyBacklogScaler creates a new scaler for the queued requests or the active connections of a backend
+// read from the HAProxy CSV stats or the NGINX Plus API
+func NewProxyBacklogScaler(resolvedEnv, metadata, authParams map[string]string) (Scaler, error) {
+	meta, err := parseProxyBacklogMetadata(resolvedEnv, metadata, authParams)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing proxy backlog metadata: %s", err)
+	}
+
+	return &proxyBacklogScaler{
+		metadata:   meta,
+		httpClient: &http.Client{Timeout: proxyRequestTimeout},
+	}, nil
+}
+
+func parseProxyBacklogMetadata(resolvedEnv, metadata, authParams map[string]string) (*proxyBacklogMetadata, error) {
+	meta := proxyBacklogMetadata{
+		metric:      proxyQueueMetric,
+		targetValue: defaultProxyTargetValue,
+	}
+
+	switch proxyType := strings.ToLower(metadata["proxyType"]); proxyType {
+	case haproxyProxyType, nginxPlusProxyType:
+		meta.proxyType = proxyType
+	case "":
+		return nil, fmt.Errorf("no proxyType given")
+	default:
+		return nil, fmt.Errorf("proxyType %s not supported, supported proxy types are %s, %s", metadata["proxyType"], haproxyProxyType, nginxPlusProxyType)
+	}
+
+	if val, ok := metadata["statsURL"]; ok && val != "" {
+		meta.statsURL = val
+	} else if val, ok := metadata["statsURLFromEnv"]; ok && val != "" {
+		meta.statsURL = resolvedEnv[val]
+	}
+	if meta.statsURL == "" {
+		return nil, fmt.Errorf("no statsURL given")
+	}
+
+	if val, ok := metadata["backend"]; ok && val != "" {
+		meta.backend = val
+	} else {
+		return nil, fmt.Errorf("no backend given")
+	}
+
+	if val, ok := metadata["metric"]; ok && val != "" {
+		switch metric := strings.ToLower(val); metric {
+		case proxyQueueMetric, proxyConnectionsMetric:
+			meta.metric = metric
+		default:
+			return nil, fmt.Errorf("metric %s not supported, supported metrics are %s, %s", val, proxyQueueMetric, proxyConnectionsMetric)
+		}
+	}
+
+	if val, ok := metadata["targetValue"]; ok && val != "" {
+		targetValue, err := strconv.Atoi(val)
+		if err != nil {
+			return nil, fmt.Errorf("targetValue parsing error %s", err.Error())
+		}
+		meta.targetValue = targetValue
+	}
+
+	if val, ok := authParams["username"]; ok && val != "" {
+		meta.username = val
+	}
+	if val, ok := authParams["password"]; ok && val != "" {
+		meta.password = val
+	} else if val, ok := metadata["passwordFromEnv"]; ok && val != "" {
+		meta.password = resolvedEnv[val]
+	}
+	if meta.password != "" && meta.username == "" {
+		return nil, fmt.Errorf("no username given for the password")
+	}
+
+	retryPolicy, err := parseHTTPRetryPolicy(metadata)
+	if err != nil {
+		return nil, err
+	}
+	meta.retryPolicy = retryPolicy
+
+	return &meta, nil
+}
+
+// IsActive returns true if the backend has queued requests or active connections
+func (s *proxyBacklogScaler) IsActive(ctx context.Context) (bool, error) {
+	value, err := s.getValue(ctx)
+	if err != nil {
+		proxyBacklogLog.Error(err, "error getting backend stats", "proxyType", s.metadata.proxyType, "backend", s.metadata.backend)
+		return false, err
+	}
+
+	return value > 0, nil
+}
+
+func (s *proxyBacklogScaler) getValue(ctx context.Context) (int64, error) {
+	req, err := http.NewRequest(http.MethodGet, s.metadata.statsURL, nil)
+	if err != nil {
+		return 0, err
+	}
+	req = req.WithContext(ctx)
+	if s.metadata.username != "" {
+		req.SetBasicAuth(s.metadata.username, s.metadata.password)
+	}
+
+	resp, err := kedautil.DoWithRetry(s.httpClient, req, s.metadata.retryPolicy)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("%s stats returned %d", s.metadata.proxyType, resp.StatusCode)
+	}
+
+	if s.metadata.proxyType == nginxPlusProxyType {
+		return s.getNginxPlusValue(resp.Body)
+	}
+	return s.getHAProxyValue(resp.Body)
+}
+
+// getHAProxyValue reads the current queued requests (qcur) or sessions (scur) of the backend from the HAProxy CSV stats
+func (s *proxyBacklogScaler) getHAProxyValue(body io.Reader) (int64, error) {
+	reader := csv.NewReader(body)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return 0, fmt.Errorf("error reading haproxy stats: %s", err)
+	}
+	column := "qcur"
+	if s.metadata.metric == proxyConnectionsMetric {
+		column = "scur"
+	}
+	index := map[string]int{}
+	for i, name := range header {
+		index[strings.TrimSpace(strings.TrimPrefix(name, "# "))] = i
+	}
+	pxname, ok1 := index["pxname"]
+	svname, ok2 := index["svname"]
+	valueIndex, ok3 := index[column]
+	if !ok1 || !ok2 || !ok3 {
+		return 0, fmt.Errorf("haproxy stats don't have the pxname, svname and %s columns", column)
+	}
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return 0, fmt.Errorf("error reading haproxy stats: %s", err)
+		}
+		if len(record) <= valueIndex || record[pxname] != s.metadata.backend || record[svname] != haproxyBackendSvName {
+			continue
+		}
+		if record[valueIndex] == "" {
+			return 0, nil
+		}
+		value, err := strconv.ParseInt(record[valueIndex], 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("error parsing %s of backend %s: %s", column, s.metadata.backend, err)
+		}
+		return value, nil
+	}
+	return 0, fmt.Errorf("backend %s not found in haproxy stats", s.metadata.backend)
+}
+
+// getNginxPlusValue reads the queue size or the sum of the active connections of the peers of the upstream
+// from the response of the NGINX Plus API, the statsURL is expected to be the upstream endpoint like /api/6/http/upstreams
+func (s *proxyBacklogScaler) getNginxPlusValue(body io.Reader) (int64, error) {
+	upstreams := map[string]nginxPlusUpstream{}
+	if err := json.NewDecoder(body).Decode(&upstreams); err != nil {
+		return 0, fmt.Errorf("error decoding nginx plus api response: %s", err)
+	}
+	upstream, ok := upstreams[s.metadata.backend]
+	if !ok {
+		return 0, fmt.Errorf("upstream %s not found in nginx plus api response", s.metadata.backend)
+	}
+
+	if s.metadata.metric == proxyQueueMetric {
+		if upstream.Queue == nil {
+			return 0, nil
+		}
+		return upstream.Queue.Size, nil
+	}
+	var active int64
+	for _, peer := range upstream.Peers {
+		active += peer.Active
+	}
+	return active, nil
+}
+
+// GetMetricSpecForScaling returns the MetricSpec for the Horizontal Pod Autoscaler
+func (s *proxyBacklogScaler) GetMetricSpecForScaling() []v2beta2.MetricSpec {
+	targetValue := resource.NewQuantity(int64(s.metadata.targetValue), resource.DecimalSI)
+	externalMetric := &v2beta2.ExternalMetricSource{
+		Metric: v2beta2.MetricIdentifier{
+			Name: kedautil.NormalizeString(fmt.Sprintf("%s-%s-%s", s.metadata.proxyType, s.metadata.backend, s.metadata.metric)),
+		},
+		Target: v2beta2.MetricTarget{
+			Type:         v2beta2.AverageValueMetricType,
+			AverageValue: targetValue,
+		},
+	}
+	metricSpec := v2beta2.MetricSpec{External: externalMetric, Type: externalMetricType}
+	return []v2beta2.MetricSpec{metricSpec}
+}
+
+// GetMetrics returns value for a supported metric and an error if there is a problem getting the metric
+func (s *proxyBacklogScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
+	value, err := s.getValue(ctx)
+	if err != nil {
+		return []external_metrics.ExternalMetricValue{}, fmt.Errorf("error getting %s backend stats: %s", s.metadata.proxyType, err)
+	}
+
+	metric := external_metrics.ExternalMetricValue{
+		MetricName: metricName,
+		Value:      *resource.NewQuantity(value, resource.DecimalSI),
+		Timestamp:  metav1.Now(),
+	}
+
+	return append([]external_metrics.ExternalMetricValue{}, metric), nil
+}
+
+// Close does nothing in case of proxyBacklogScaler
+func (s *proxyBacklogScaler) Close() error {
+	return nil
+}
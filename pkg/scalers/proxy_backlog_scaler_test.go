@@ -0,0 +1,134 @@
+package scalers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type parseProxyBacklogMetadataTestData struct {
+	metadata   map[string]string
+	authParams map[string]string
+	isError    bool
+}
+
+type proxyBacklogMetricIdentifier struct {
+	metadataTestData *parseProxyBacklogMetadataTestData
+	name             string
+}
+
+var testProxyBacklogMetadata = []parseProxyBacklogMetadataTestData{
+	// nothing passed
+	{map[string]string{}, map[string]string{}, true},
+	// properly formed haproxy
+	{map[string]string{"proxyType": "haproxy", "statsURL": "http://haproxy:8404/stats;csv", "backend": "web"}, map[string]string{"username": "admin", "password": "admin"}, false},
+	// properly formed nginx plus
+	{map[string]string{"proxyType": "NGINX-Plus", "statsURL": "http://nginx:8080/api/6/http/upstreams", "backend": "api", "metric": "connections", "targetValue": "50"}, map[string]string{}, false},
+	// unsupported proxyType
+	{map[string]string{"proxyType": "traefik", "statsURL": "http://traefik", "backend": "web"}, map[string]string{}, true},
+	// missing statsURL
+	{map[string]string{"proxyType": "haproxy", "backend": "web"}, map[string]string{}, true},
+	// missing backend
+	{map[string]string{"proxyType": "haproxy", "statsURL": "http://haproxy:8404/stats;csv"}, map[string]string{}, true},
+	// unsupported metric
+	{map[string]string{"proxyType": "haproxy", "statsURL": "http://haproxy:8404/stats;csv", "backend": "web", "metric": "rate"}, map[string]string{}, true},
+	// malformed targetValue
+	{map[string]string{"proxyType": "haproxy", "statsURL": "http://haproxy:8404/stats;csv", "backend": "web", "targetValue": "ten"}, map[string]string{}, true},
+}
+
+var proxyBacklogMetricIdentifiers = []proxyBacklogMetricIdentifier{
+	{&testProxyBacklogMetadata[1], "haproxy-web-queue"},
+	{&testProxyBacklogMetadata[2], "nginx-plus-api-connections"},
+}
+
+const testHAProxyStats = `# pxname,svname,qcur,qmax,scur,smax,slim
+stats,FRONTEND,,,1,2,262000
+web,web1,3,10,20,30,
+web,web2,4,8,22,30,
+web,BACKEND,7,12,42,60,26200
+api,BACKEND,,0,5,9,26200
+`
+
+const testNginxPlusUpstreams = `{
+	"api": {"peers": [{"id": 0, "active": 12}, {"id": 1, "active": 8}], "queue": {"size": 4, "max_size": 100, "overflows": 0}},
+	"web": {"peers": [{"id": 0, "active": 3}]}
+}`
+
+func TestProxyBacklogParseMetadata(t *testing.T) {
+	for _, testData := range testProxyBacklogMetadata {
+		_, err := parseProxyBacklogMetadata(nil, testData.metadata, testData.authParams)
+		if err != nil && !testData.isError {
+			t.Error("Expected success but got error", err)
+		}
+		if testData.isError && err == nil {
+			t.Errorf("Expected error but got success for %v", testData.metadata)
+		}
+	}
+}
+
+func TestProxyBacklogGetMetricSpecForScaling(t *testing.T) {
+	for _, testData := range proxyBacklogMetricIdentifiers {
+		meta, err := parseProxyBacklogMetadata(nil, testData.metadataTestData.metadata, testData.metadataTestData.authParams)
+		if err != nil {
+			t.Fatal("Could not parse metadata:", err)
+		}
+		mockProxyBacklogScaler := proxyBacklogScaler{meta, http.DefaultClient}
+
+		metricSpec := mockProxyBacklogScaler.GetMetricSpecForScaling()
+		metricName := metricSpec[0].External.Metric.Name
+		if metricName != testData.name {
+			t.Error("Wrong External metric source name:", metricName)
+		}
+	}
+}
+
+func TestProxyBacklogGetValue(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/stats" {
+			w.Write([]byte(testHAProxyStats))
+			return
+		}
+		w.Write([]byte(testNginxPlusUpstreams))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		proxyType string
+		backend   string
+		metric    string
+		expected  int64
+		isError   bool
+	}{
+		{"haproxy", "web", "queue", 7, false},
+		{"haproxy", "web", "connections", 42, false},
+		{"haproxy", "api", "queue", 0, false},
+		{"haproxy", "missing", "queue", 0, true},
+		{"nginx-plus", "api", "queue", 4, false},
+		{"nginx-plus", "api", "connections", 20, false},
+		{"nginx-plus", "web", "queue", 0, false},
+		{"nginx-plus", "missing", "connections", 0, true},
+	}
+
+	for _, test := range tests {
+		statsURL := server.URL + "/api/6/http/upstreams"
+		if test.proxyType == haproxyProxyType {
+			statsURL = server.URL + "/stats"
+		}
+		meta, err := parseProxyBacklogMetadata(nil, map[string]string{"proxyType": test.proxyType, "statsURL": statsURL, "backend": test.backend, "metric": test.metric}, nil)
+		if err != nil {
+			t.Fatal("Could not parse metadata:", err)
+		}
+		scaler := proxyBacklogScaler{meta, http.DefaultClient}
+
+		value, err := scaler.getValue(context.TODO())
+		if test.isError {
+			assert.Error(t, err)
+			continue
+		}
+		assert.NoError(t, err)
+		assert.Equal(t, test.expected, value, "%s %s %s", test.proxyType, test.backend, test.metric)
+	}
+}
@@ -0,0 +1,111 @@
+package scalers
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// QueryBatchWindowMetadata is the trigger metadata key of the time the queries of the scalers sent to the same endpoint are
+// collected for before they are sent together, the queries aren't batched if it isn't set
+const QueryBatchWindowMetadata = "batchWindow"
+
+// batchedQuery is a query of a scaler, the queries with the same key are sent once per batch
+type batchedQuery struct {
+	key     string
+	payload interface{}
+}
+
+// batchedResponse is the response of the upstream API to a query
+type batchedResponse struct {
+	body       []byte
+	statusCode int
+	err        error
+}
+
+// batchRunner sends the queries of a batch to the endpoint, it returns a response per query in the same order
+type batchRunner func(queries []batchedQuery) []batchedResponse
+
+type queryBatch struct {
+	queries   []batchedQuery
+	indexes   map[string]int
+	responses []batchedResponse
+	done      chan struct{}
+}
+
+// queryBatcher is the dispatcher of the queries of the scalers sent to the same endpoint, so the scalers of many triggers
+// polled at the same time use a single upstream call instead of one each
+type queryBatcher struct {
+	mutex   sync.Mutex
+	batches map[string]*queryBatch
+	// maxBatchSize is the number of distinct queries of a batch, a new batch is started once a batch is full, 0 for no limit
+	maxBatchSize int
+}
+
+func newQueryBatcher(maxBatchSize int) *queryBatcher {
+	return &queryBatcher{
+		batches:      make(map[string]*queryBatch),
+		maxBatchSize: maxBatchSize,
+	}
+}
+
+// do adds the query to the batch of the endpoint and waits for its response, the batch is sent by run once the window
+// of its first query elapsed. The queries batched together must only differ by their payload, so the endpoint must
+// identify every setting of the upstream call shared by the batch, e.g. the server, the credentials and the timeout.
+// The wait is stopped once the context is done, the batch is still sent for the other queries.
+func (b *queryBatcher) do(ctx context.Context, endpoint string, window time.Duration, query batchedQuery, run batchRunner) batchedResponse {
+	b.mutex.Lock()
+	batch, ok := b.batches[endpoint]
+	if !ok {
+		batch = &queryBatch{indexes: make(map[string]int), done: make(chan struct{})}
+		b.batches[endpoint] = batch
+		time.AfterFunc(window, func() {
+			b.mutex.Lock()
+			if b.batches[endpoint] == batch {
+				delete(b.batches, endpoint)
+			}
+			b.mutex.Unlock()
+
+			batch.responses = run(batch.queries)
+			close(batch.done)
+		})
+	}
+	index, ok := batch.indexes[query.key]
+	if !ok {
+		index = len(batch.queries)
+		batch.indexes[query.key] = index
+		batch.queries = append(batch.queries, query)
+		if b.maxBatchSize > 0 && len(batch.queries) >= b.maxBatchSize {
+			// the full batch is still sent when its window elapses, the next queries start a new batch
+			delete(b.batches, endpoint)
+		}
+	}
+	b.mutex.Unlock()
+
+	select {
+	case <-batch.done:
+	case <-ctx.Done():
+		return batchedResponse{err: fmt.Errorf("error waiting for the response to the query in the batch: %s", ctx.Err())}
+	}
+	if index >= len(batch.responses) {
+		return batchedResponse{err: fmt.Errorf("no response to the query in the batch")}
+	}
+	return batch.responses[index]
+}
+
+// parseQueryBatchWindow returns the batch window of the trigger metadata, 0 if the queries aren't batched
+func parseQueryBatchWindow(metadata map[string]string) (time.Duration, error) {
+	val, ok := metadata[QueryBatchWindowMetadata]
+	if !ok || val == "" {
+		return 0, nil
+	}
+	window, err := time.ParseDuration(val)
+	if err != nil {
+		return 0, fmt.Errorf("error parsing %s: %s", QueryBatchWindowMetadata, err)
+	}
+	if window <= 0 {
+		return 0, fmt.Errorf("%s must be greater than 0", QueryBatchWindowMetadata)
+	}
+	return window, nil
+}
@@ -0,0 +1,74 @@
+package scalers
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestQueryBatcher(t *testing.T) {
+	batcher := newQueryBatcher(3)
+
+	var mutex sync.Mutex
+	var batches [][]string
+	run := func(queries []batchedQuery) []batchedResponse {
+		mutex.Lock()
+		defer mutex.Unlock()
+		var keys []string
+		responses := make([]batchedResponse, len(queries))
+		for i, query := range queries {
+			keys = append(keys, query.key)
+			responses[i].body = []byte(fmt.Sprintf("result of %s", query.payload))
+		}
+		batches = append(batches, keys)
+		return responses
+	}
+
+	queries := []string{"a", "a", "b", "c", "d"}
+	results := make([]string, len(queries))
+	var wg sync.WaitGroup
+	for i, query := range queries {
+		wg.Add(1)
+		go func(i int, query string) {
+			defer wg.Done()
+			results[i] = string(batcher.do(context.TODO(), "endpoint", 100*time.Millisecond, batchedQuery{key: query, payload: query}, run).body)
+		}(i, query)
+		// the queries are added in order
+		time.Sleep(5 * time.Millisecond)
+	}
+	wg.Wait()
+
+	for i, query := range queries {
+		if expected := "result of " + query; results[i] != expected {
+			t.Errorf("Expected %q, got %q", expected, results[i])
+		}
+	}
+	// a batch holds 3 distinct queries, the identical ones are sent once
+	if len(batches) != 2 || len(batches[0]) != 3 || len(batches[1]) != 1 {
+		t.Errorf("Expected the batches [a b c] and [d], got %v", batches)
+	}
+	if len(batcher.batches) != 0 {
+		t.Errorf("Expected no pending batch, got %d", len(batcher.batches))
+	}
+}
+
+func TestParseQueryBatchWindow(t *testing.T) {
+	tests := []struct {
+		metadata map[string]string
+		expected time.Duration
+		isError  bool
+	}{
+		{map[string]string{}, 0, false},
+		{map[string]string{"batchWindow": "250ms"}, 250 * time.Millisecond, false},
+		{map[string]string{"batchWindow": "soon"}, 0, true},
+		{map[string]string{"batchWindow": "-1s"}, 0, true},
+	}
+	for _, test := range tests {
+		window, err := parseQueryBatchWindow(test.metadata)
+		if (err != nil) != test.isError || window != test.expected {
+			t.Errorf("Expected %s and error %t for %v, got %s and %v", test.expected, test.isError, test.metadata, window, err)
+		}
+	}
+}
@@ -44,6 +44,7 @@ type rabbitMQMetadata struct {
 	queueLength int
 	host        string // connection string for either HTTP or AMQP protocol
 	protocol    string // either http or amqp protocol
+	retryPolicy kedautil.RetryPolicy
 }
 
 type queueInfo struct {
@@ -120,6 +121,12 @@ func parseRabbitMQMetadata(resolvedEnv, metadata, authParams map[string]string)
 		meta.queueLength = defaultRabbitMQQueueLength
 	}
 
+	retryPolicy, err := parseHTTPRetryPolicy(metadata)
+	if err != nil {
+		return nil, err
+	}
+	meta.retryPolicy = retryPolicy
+
 	return &meta, nil
 }
 
@@ -151,7 +158,7 @@ func (s *rabbitMQScaler) Close() error {
 
 // IsActive returns true if there are pending messages to be processed
 func (s *rabbitMQScaler) IsActive(ctx context.Context) (bool, error) {
-	messages, err := s.getQueueMessages()
+	messages, err := s.getQueueMessages(ctx)
 	if err != nil {
 		return false, fmt.Errorf("error inspecting rabbitMQ: %s", err)
 	}
@@ -159,9 +166,11 @@ func (s *rabbitMQScaler) IsActive(ctx context.Context) (bool, error) {
 	return messages > 0, nil
 }
 
-func (s *rabbitMQScaler) getQueueMessages() (int, error) {
+// getQueueMessages returns the message count of the queue, the AMQP client doesn't support a context so only the
+// requests to the management API are bound to it
+func (s *rabbitMQScaler) getQueueMessages(ctx context.Context) (int, error) {
 	if s.metadata.protocol == httpProtocol {
-		info, err := s.getQueueInfoViaHTTP()
+		info, err := s.getQueueInfoViaHTTP(ctx)
 		if err != nil {
 			return -1, err
 		}
@@ -178,9 +187,9 @@ func (s *rabbitMQScaler) getQueueMessages() (int, error) {
 	return items.Messages, nil
 }
 
-func getJSON(url string, target interface{}) error {
+func getJSON(ctx context.Context, url string, target interface{}, retryPolicy kedautil.RetryPolicy) error {
 	var client = &http.Client{Timeout: 5 * time.Second}
-	r, err := client.Get(url)
+	r, err := doHTTPGetWithRetry(ctx, client, url, retryPolicy)
 	if err != nil {
 		return err
 	}
@@ -194,7 +203,7 @@ func getJSON(url string, target interface{}) error {
 	return fmt.Errorf("error requesting rabbitMQ API status: %s, response: %s, from: %s", r.Status, body, url)
 }
 
-func (s *rabbitMQScaler) getQueueInfoViaHTTP() (*queueInfo, error) {
+func (s *rabbitMQScaler) getQueueInfoViaHTTP(ctx context.Context) (*queueInfo, error) {
 	parsedURL, err := url.Parse(s.metadata.host)
 
 	if err != nil {
@@ -212,7 +221,7 @@ func (s *rabbitMQScaler) getQueueInfoViaHTTP() (*queueInfo, error) {
 	getQueueInfoManagementURI := fmt.Sprintf("%s/%s%s/%s", parsedURL.String(), "api/queues", vhost, s.metadata.queueName)
 
 	info := queueInfo{}
-	err = getJSON(getQueueInfoManagementURI, &info)
+	err = getJSON(ctx, getQueueInfoManagementURI, &info, s.metadata.retryPolicy)
 
 	if err != nil {
 		return nil, err
@@ -241,7 +250,7 @@ func (s *rabbitMQScaler) GetMetricSpecForScaling() []v2beta2.MetricSpec {
 
 // GetMetrics returns value for a supported metric and an error if there is a problem getting the metric
 func (s *rabbitMQScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
-	messages, err := s.getQueueMessages()
+	messages, err := s.getQueueMessages(ctx)
 	if err != nil {
 		return []external_metrics.ExternalMetricValue{}, fmt.Errorf("error inspecting rabbitMQ: %s", err)
 	}
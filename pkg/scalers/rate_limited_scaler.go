@@ -0,0 +1,107 @@
+package scalers
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"golang.org/x/time/rate"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/metrics/pkg/apis/external_metrics"
+)
+
+const (
+	// RateLimitQPSMetadata is the trigger metadata key of the rate of the calls of a scaler to the upstream API
+	RateLimitQPSMetadata = "rateLimitQPS"
+	// RateLimitBurstMetadata is the trigger metadata key of the number of calls of a scaler allowed at once
+	RateLimitBurstMetadata = "rateLimitBurst"
+)
+
+// rateLimitedScaler waits on a token bucket before the checks of the wrapped scaler
+type rateLimitedScaler struct {
+	Scaler
+	limiter *rate.Limiter
+}
+
+// NewRateLimitedScaler wraps the scaler so its checks are limited by the limiter,
+// the limiter is shared by the instances of the scaler created for the same trigger
+func NewRateLimitedScaler(scaler Scaler, limiter *rate.Limiter) Scaler {
+	return &rateLimitedScaler{
+		Scaler:  scaler,
+		limiter: limiter,
+	}
+}
+
+// ParseRateLimit returns the QPS and burst of the rate limit in the trigger metadata,
+// the burst defaults to the QPS rounded up. The returned QPS is 0 if no rate limit is set.
+func ParseRateLimit(metadata map[string]string) (rate.Limit, int, error) {
+	val, ok := metadata[RateLimitQPSMetadata]
+	if !ok || val == "" {
+		return 0, 0, nil
+	}
+	qps, err := strconv.ParseFloat(val, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("error parsing %s: %s", RateLimitQPSMetadata, err)
+	}
+	if qps <= 0 {
+		return 0, 0, fmt.Errorf("%s must be greater than 0", RateLimitQPSMetadata)
+	}
+
+	burst := int(math.Ceil(qps))
+	if val, ok := metadata[RateLimitBurstMetadata]; ok && val != "" {
+		burst, err = strconv.Atoi(val)
+		if err != nil {
+			return 0, 0, fmt.Errorf("error parsing %s: %s", RateLimitBurstMetadata, err)
+		}
+		if burst <= 0 {
+			return 0, 0, fmt.Errorf("%s must be greater than 0", RateLimitBurstMetadata)
+		}
+	}
+	return rate.Limit(qps), burst, nil
+}
+
+// UnwrapScaler returns the scaler wrapped for a timeout, a concurrency limit, a rate limit, a result cache, a value expression or desired replicas,
+// the scaler itself if it isn't wrapped
+func UnwrapScaler(scaler Scaler) Scaler {
+	for {
+		switch s := scaler.(type) {
+		case *timeoutScaler:
+			scaler = s.Scaler
+		case *concurrencyLimitedScaler:
+			scaler = s.Scaler
+		case *rateLimitedScaler:
+			scaler = s.Scaler
+		case *cachedScaler:
+			scaler = s.Scaler
+		case *valueExpressionScaler:
+			scaler = s.Scaler
+		case *valueExpressionPushScaler:
+			scaler = s.Scaler
+		case *desiredReplicasScaler:
+			scaler = s.Scaler
+		default:
+			return scaler
+		}
+	}
+}
+
+// ScalerName returns the type name of the scaler, the wrapped scalers are named after the scaler they wrap
+func ScalerName(scaler Scaler) string {
+	return strings.TrimPrefix(fmt.Sprintf("%T", UnwrapScaler(scaler)), "*scalers.")
+}
+
+func (s *rateLimitedScaler) IsActive(ctx context.Context) (bool, error) {
+	if err := s.limiter.Wait(ctx); err != nil {
+		return false, fmt.Errorf("error waiting for the rate limit: %s", err)
+	}
+	return s.Scaler.IsActive(ctx)
+}
+
+func (s *rateLimitedScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
+	if err := s.limiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("error waiting for the rate limit: %s", err)
+	}
+	return s.Scaler.GetMetrics(ctx, metricName, metricSelector)
+}
@@ -0,0 +1,100 @@
+package scalers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"golang.org/x/time/rate"
+	v2beta2 "k8s.io/api/autoscaling/v2beta2"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/metrics/pkg/apis/external_metrics"
+)
+
+type parseRateLimitTestData struct {
+	metadata      map[string]string
+	expectedQPS   rate.Limit
+	expectedBurst int
+	isError       bool
+}
+
+var parseRateLimitTestDataset = []parseRateLimitTestData{
+	// no rate limit
+	{map[string]string{}, 0, 0, false},
+	// burst without qps is ignored
+	{map[string]string{"rateLimitBurst": "5"}, 0, 0, false},
+	// burst defaults to the qps rounded up
+	{map[string]string{"rateLimitQPS": "0.5"}, 0.5, 1, false},
+	{map[string]string{"rateLimitQPS": "2.5"}, 2.5, 3, false},
+	// qps and burst
+	{map[string]string{"rateLimitQPS": "1", "rateLimitBurst": "10"}, 1, 10, false},
+	// invalid qps
+	{map[string]string{"rateLimitQPS": "fast"}, 0, 0, true},
+	{map[string]string{"rateLimitQPS": "0"}, 0, 0, true},
+	// invalid burst
+	{map[string]string{"rateLimitQPS": "1", "rateLimitBurst": "1.5"}, 0, 0, true},
+	{map[string]string{"rateLimitQPS": "1", "rateLimitBurst": "-1"}, 0, 0, true},
+}
+
+func TestParseRateLimit(t *testing.T) {
+	for _, testData := range parseRateLimitTestDataset {
+		qps, burst, err := ParseRateLimit(testData.metadata)
+		if err != nil && !testData.isError {
+			t.Errorf("Expected success for %v but got error %s", testData.metadata, err)
+		}
+		if testData.isError && err == nil {
+			t.Errorf("Expected error for %v but got success", testData.metadata)
+		}
+		if qps != testData.expectedQPS || burst != testData.expectedBurst {
+			t.Errorf("Expected qps %v and burst %d for %v, got %v and %d", testData.expectedQPS, testData.expectedBurst, testData.metadata, qps, burst)
+		}
+	}
+}
+
+type countingScaler struct {
+	calls int
+}
+
+func (s *countingScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
+	s.calls++
+	return []external_metrics.ExternalMetricValue{}, nil
+}
+
+func (s *countingScaler) GetMetricSpecForScaling() []v2beta2.MetricSpec {
+	return nil
+}
+
+func (s *countingScaler) IsActive(ctx context.Context) (bool, error) {
+	s.calls++
+	return true, nil
+}
+
+func (s *countingScaler) Close() error {
+	return nil
+}
+
+func TestRateLimitedScaler(t *testing.T) {
+	wrapped := &countingScaler{}
+	scaler := NewRateLimitedScaler(wrapped, rate.NewLimiter(rate.Every(time.Hour), 2))
+
+	if name := ScalerName(scaler); name != "countingScaler" {
+		t.Errorf("Expected the name of the wrapped scaler, got %s", name)
+	}
+
+	if _, err := scaler.IsActive(context.TODO()); err != nil {
+		t.Errorf("Expected the first call within the burst, got error %s", err)
+	}
+	if _, err := scaler.GetMetrics(context.TODO(), "metric", nil); err != nil {
+		t.Errorf("Expected the second call within the burst, got error %s", err)
+	}
+
+	// the bucket is empty, so the third call waits until the context is done
+	ctx, cancel := context.WithTimeout(context.TODO(), 100*time.Millisecond)
+	defer cancel()
+	if _, err := scaler.IsActive(ctx); err == nil {
+		t.Error("Expected the call over the burst to fail once the context is done")
+	}
+	if wrapped.calls != 2 {
+		t.Errorf("Expected 2 calls of the wrapped scaler, got %d", wrapped.calls)
+	}
+}
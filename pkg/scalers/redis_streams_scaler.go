@@ -134,7 +134,7 @@ func parseRedisStreamsMetadata(metadata, resolvedEnv, authParams map[string]stri
 
 // IsActive checks if there are pending entries in the 'Pending Entries List' for consumer group of a stream
 func (s *redisStreamsScaler) IsActive(ctx context.Context) (bool, error) {
-	count, err := s.getPendingEntriesCount()
+	count, err := s.getPendingEntriesCount(ctx)
 
 	if err != nil {
 		redisStreamsLog.Error(err, "error")
@@ -166,7 +166,7 @@ func (s *redisStreamsScaler) GetMetricSpecForScaling() []v2beta2.MetricSpec {
 
 // GetMetrics fetches the number of pending entries for a consumer group in a stream
 func (s *redisStreamsScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
-	pendingEntriesCount, err := s.getPendingEntriesCount()
+	pendingEntriesCount, err := s.getPendingEntriesCount(ctx)
 
 	if err != nil {
 		redisStreamsLog.Error(err, "error fetching pending entries count")
@@ -181,8 +181,8 @@ func (s *redisStreamsScaler) GetMetrics(ctx context.Context, metricName string,
 	return append([]external_metrics.ExternalMetricValue{}, metric), nil
 }
 
-func (s *redisStreamsScaler) getPendingEntriesCount() (int64, error) {
-	pendingEntries, err := s.conn.XPending(s.metadata.streamName, s.metadata.consumerGroupName).Result()
+func (s *redisStreamsScaler) getPendingEntriesCount(ctx context.Context) (int64, error) {
+	pendingEntries, err := s.conn.WithContext(ctx).XPending(s.metadata.streamName, s.metadata.consumerGroupName).Result()
 	if err != nil {
 		return -1, err
 	}
@@ -2,32 +2,40 @@ package scalers
 
 import (
 	"context"
+	"time"
 
-	v2beta2 "k8s.io/api/autoscaling/v2beta2"
-	"k8s.io/apimachinery/pkg/labels"
-	"k8s.io/metrics/pkg/apis/external_metrics"
+	"github.com/kedacore/keda/pkg/scalersdk"
 )
 
-// Scaler interface
-type Scaler interface {
+// Scaler interface, it is defined by the scaler SDK so the out-of-tree scalers implement the same interface
+type Scaler = scalersdk.Scaler
 
-	// The scaler returns the metric values for a metric Name and criteria matching the selector
-	GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error)
+// PushScaler interface
+type PushScaler = scalersdk.PushScaler
+
+// ConnectionStateScaler interface is implemented by the scalers connected to a remote scaler, the state of the connection is surfaced in the ScaledObject status
+type ConnectionStateScaler interface {
+	Scaler
 
-	// Returns the metrics based on which this scaler determines that the ScaleTarget scales. This is used to construct the HPA spec that is created for
-	// this scaled object. The labels used should match the selectors used in GetMetrics
-	GetMetricSpecForScaling() []v2beta2.MetricSpec
+	// ConnectionState returns the address of the remote scaler, the state of the connection after the last call, empty if there was no call,
+	// and the error of the last call if any
+	ConnectionState() (address string, state string, message string)
+}
 
-	IsActive(ctx context.Context) (bool, error)
+// PartitionCountScaler interface is implemented by the scalers of partitioned streams, each partition or shard is read by a single consumer
+// so the replicas beyond the partition count are idle
+type PartitionCountScaler interface {
+	Scaler
 
-	// Close any resources that need disposing when scaler is no longer used or destroyed
-	Close() error
+	// PartitionCount returns the number of partitions or shards read by the trigger
+	PartitionCount(ctx context.Context) (int64, error)
 }
 
-// PushScaler interface
-type PushScaler interface {
+// PollingIntervalScaler interface is implemented by the scalers running a workload to get their value, the checks within the polling interval
+// of the ScaledObject or ScaledJob reuse the value of the last run
+type PollingIntervalScaler interface {
 	Scaler
 
-	// Run is the only writer to the active channel and must close it once done.
-	Run(ctx context.Context, active chan<- bool)
+	// SetPollingInterval sets the polling interval of the ScaledObject or ScaledJob of the trigger
+	SetPollingInterval(interval time.Duration)
 }
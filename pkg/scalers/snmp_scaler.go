@@ -0,0 +1,341 @@
+package scalers
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gosnmp/gosnmp"
+	v2beta2 "k8s.io/api/autoscaling/v2beta2"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/metrics/pkg/apis/external_metrics"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	kedautil "github.com/kedacore/keda/pkg/util"
+)
+
+const (
+	snmpVersion2c        = "v2c"
+	snmpVersion3         = "v3"
+	defaultSNMPPort      = 161
+	defaultSNMPTimeout   = 5 * time.Second
+	defaultSNMPRetries   = 1
+	defaultSNMPCommunity = "public"
+	snmpTargetValueName  = "targetValue"
+)
+
+var snmpAuthProtocols = map[string]gosnmp.SnmpV3AuthProtocol{
+	"MD5":    gosnmp.MD5,
+	"SHA":    gosnmp.SHA,
+	"SHA224": gosnmp.SHA224,
+	"SHA256": gosnmp.SHA256,
+	"SHA384": gosnmp.SHA384,
+	"SHA512": gosnmp.SHA512,
+}
+
+var snmpPrivProtocols = map[string]gosnmp.SnmpV3PrivProtocol{
+	"DES":     gosnmp.DES,
+	"AES":     gosnmp.AES,
+	"AES192":  gosnmp.AES192,
+	"AES256":  gosnmp.AES256,
+	"AES192C": gosnmp.AES192C,
+	"AES256C": gosnmp.AES256C,
+}
+
+type snmpScaler struct {
+	metadata *snmpMetadata
+}
+
+type snmpMetadata struct {
+	host        string
+	port        uint16
+	oid         string
+	version     string
+	targetValue int64
+	// the trigger is active when the value is greater than the activation value
+	activationValue int64
+	timeout         time.Duration
+	retries         int
+
+	// community is the community of v2c, the other settings are the user based security of v3
+	community      string
+	username       string
+	authProtocol   gosnmp.SnmpV3AuthProtocol
+	authPassphrase string
+	privProtocol   gosnmp.SnmpV3PrivProtocol
+	privPassphrase string
+	contextName    string
+}
+
+var snmpLog = logf.Log.WithName("snmp_scaler")
+
+// NewSNMPScaler creates a new scaler for the integer value of an SNMP object
+func NewSNMPScaler(resolvedEnv, metadata, authParams map[string]string) (Scaler, error) {
+	meta, err := parseSNMPMetadata(resolvedEnv, metadata, authParams)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing snmp metadata: %s", err)
+	}
+
+	return &snmpScaler{
+		metadata: meta,
+	}, nil
+}
+
+func parseSNMPMetadata(resolvedEnv, metadata, authParams map[string]string) (*snmpMetadata, error) {
+	meta := snmpMetadata{
+		port:      defaultSNMPPort,
+		version:   snmpVersion2c,
+		timeout:   defaultSNMPTimeout,
+		retries:   defaultSNMPRetries,
+		community: defaultSNMPCommunity,
+	}
+
+	target := metadata["target"]
+	if target == "" {
+		return nil, fmt.Errorf("no target given")
+	}
+	if host, port, err := net.SplitHostPort(target); err == nil {
+		p, err := strconv.ParseUint(port, 10, 16)
+		if err != nil {
+			return nil, fmt.Errorf("can't parse the port of target %s: %s", target, err)
+		}
+		meta.host, meta.port = host, uint16(p)
+	} else {
+		meta.host = target
+	}
+
+	if val, ok := metadata["oid"]; ok && val != "" {
+		meta.oid = val
+	} else {
+		return nil, fmt.Errorf("no oid given")
+	}
+
+	if val, ok := metadata[snmpTargetValueName]; ok && val != "" {
+		targetValue, err := strconv.ParseInt(val, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("can't parse %s: %s", snmpTargetValueName, err)
+		}
+		meta.targetValue = targetValue
+	} else {
+		return nil, fmt.Errorf("no %s given", snmpTargetValueName)
+	}
+
+	if val, ok := metadata["activationValue"]; ok && val != "" {
+		activationValue, err := strconv.ParseInt(val, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("can't parse activationValue: %s", err)
+		}
+		meta.activationValue = activationValue
+	}
+
+	if val, ok := metadata["timeout"]; ok && val != "" {
+		timeout, err := strconv.Atoi(val)
+		if err != nil || timeout <= 0 {
+			return nil, fmt.Errorf("timeout %s is not a positive number of seconds", val)
+		}
+		meta.timeout = time.Duration(timeout) * time.Second
+	}
+	if val, ok := metadata["retries"]; ok && val != "" {
+		retries, err := strconv.Atoi(val)
+		if err != nil || retries < 0 {
+			return nil, fmt.Errorf("retries %s is not a positive number", val)
+		}
+		meta.retries = retries
+	}
+
+	if val, ok := metadata["version"]; ok && val != "" {
+		meta.version = strings.ToLower(val)
+	}
+	switch meta.version {
+	case snmpVersion2c:
+		if val, ok := authParams["community"]; ok && val != "" {
+			meta.community = val
+		} else if val, ok := metadata["communityFromEnv"]; ok && val != "" {
+			meta.community = resolvedEnv[val]
+		}
+	case snmpVersion3:
+		if err := parseSNMPv3Auth(&meta, authParams); err != nil {
+			return nil, err
+		}
+		meta.contextName = metadata["contextName"]
+	default:
+		return nil, fmt.Errorf("version %s not supported, supported versions are %s and %s", meta.version, snmpVersion2c, snmpVersion3)
+	}
+
+	return &meta, nil
+}
+
+// parseSNMPv3Auth parses the user based security of v3, the security level is set by the protocols given
+func parseSNMPv3Auth(meta *snmpMetadata, authParams map[string]string) error {
+	meta.username = authParams["username"]
+	if meta.username == "" {
+		return fmt.Errorf("no username given for version %s", snmpVersion3)
+	}
+
+	meta.authProtocol = gosnmp.NoAuth
+	if val := authParams["authProtocol"]; val != "" {
+		protocol, ok := snmpAuthProtocols[strings.ToUpper(val)]
+		if !ok {
+			return fmt.Errorf("authProtocol %s not supported", val)
+		}
+		if authParams["authPassphrase"] == "" {
+			return fmt.Errorf("no authPassphrase given for authProtocol %s", val)
+		}
+		meta.authProtocol = protocol
+		meta.authPassphrase = authParams["authPassphrase"]
+	}
+
+	meta.privProtocol = gosnmp.NoPriv
+	if val := authParams["privProtocol"]; val != "" {
+		if meta.authProtocol == gosnmp.NoAuth {
+			return fmt.Errorf("privProtocol can't be given without authProtocol")
+		}
+		protocol, ok := snmpPrivProtocols[strings.ToUpper(val)]
+		if !ok {
+			return fmt.Errorf("privProtocol %s not supported", val)
+		}
+		if authParams["privPassphrase"] == "" {
+			return fmt.Errorf("no privPassphrase given for privProtocol %s", val)
+		}
+		meta.privProtocol = protocol
+		meta.privPassphrase = authParams["privPassphrase"]
+	}
+	return nil
+}
+
+// newClient returns the client of the agent for the version and security of the trigger
+func (s *snmpScaler) newClient() *gosnmp.GoSNMP {
+	client := &gosnmp.GoSNMP{
+		Target:             s.metadata.host,
+		Port:               s.metadata.port,
+		Transport:          "udp",
+		Timeout:            s.metadata.timeout,
+		Retries:            s.metadata.retries,
+		ExponentialTimeout: true,
+		MaxOids:            gosnmp.MaxOids,
+	}
+
+	if s.metadata.version == snmpVersion2c {
+		client.Version = gosnmp.Version2c
+		client.Community = s.metadata.community
+		return client
+	}
+
+	client.Version = gosnmp.Version3
+	client.SecurityModel = gosnmp.UserSecurityModel
+	client.ContextName = s.metadata.contextName
+	switch {
+	case s.metadata.privProtocol != gosnmp.NoPriv:
+		client.MsgFlags = gosnmp.AuthPriv
+	case s.metadata.authProtocol != gosnmp.NoAuth:
+		client.MsgFlags = gosnmp.AuthNoPriv
+	default:
+		client.MsgFlags = gosnmp.NoAuthNoPriv
+	}
+	client.SecurityParameters = &gosnmp.UsmSecurityParameters{
+		UserName:                 s.metadata.username,
+		AuthenticationProtocol:   s.metadata.authProtocol,
+		AuthenticationPassphrase: s.metadata.authPassphrase,
+		PrivacyProtocol:          s.metadata.privProtocol,
+		PrivacyPassphrase:        s.metadata.privPassphrase,
+	}
+	return client
+}
+
+// getValue gets the object of the oid from the agent, a connection is opened for each query
+func (s *snmpScaler) getValue(ctx context.Context) (int64, error) {
+	client := s.newClient()
+	client.Context = ctx
+	if err := client.Connect(); err != nil {
+		return 0, fmt.Errorf("error connecting to snmp agent %s: %s", s.metadata.host, err)
+	}
+	defer client.Conn.Close()
+
+	result, err := client.Get([]string{s.metadata.oid})
+	if err != nil {
+		return 0, fmt.Errorf("error getting %s from snmp agent %s: %s", s.metadata.oid, s.metadata.host, err)
+	}
+	if result.Error != gosnmp.NoError {
+		return 0, fmt.Errorf("snmp agent %s returned %s for %s", s.metadata.host, result.Error, s.metadata.oid)
+	}
+	if len(result.Variables) == 0 {
+		return 0, fmt.Errorf("snmp agent %s returned no value for %s", s.metadata.host, s.metadata.oid)
+	}
+	return snmpPDUValue(result.Variables[0])
+}
+
+// snmpPDUValue returns the integer of an integer, counter, gauge or time ticks object, or of a string holding an integer
+func snmpPDUValue(pdu gosnmp.SnmpPDU) (int64, error) {
+	switch pdu.Type {
+	case gosnmp.Integer, gosnmp.Counter32, gosnmp.Gauge32, gosnmp.TimeTicks, gosnmp.Counter64, gosnmp.Uinteger32:
+		value := gosnmp.ToBigInt(pdu.Value)
+		if !value.IsInt64() {
+			return 0, fmt.Errorf("value %s of %s overflows an int64", value, pdu.Name)
+		}
+		return value.Int64(), nil
+	case gosnmp.OctetString:
+		bytes, _ := pdu.Value.([]byte)
+		value, err := strconv.ParseInt(strings.TrimSpace(string(bytes)), 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("value %q of %s is not an integer", string(bytes), pdu.Name)
+		}
+		return value, nil
+	case gosnmp.NoSuchObject, gosnmp.NoSuchInstance:
+		return 0, fmt.Errorf("object %s doesn't exist on the snmp agent", pdu.Name)
+	default:
+		return 0, fmt.Errorf("value of %s has type %s, which is not an integer", pdu.Name, pdu.Type)
+	}
+}
+
+// IsActive returns true if the value of the object is greater than the activation value
+func (s *snmpScaler) IsActive(ctx context.Context) (bool, error) {
+	value, err := s.getValue(ctx)
+	if err != nil {
+		snmpLog.Error(err, "error getting snmp value", "oid", s.metadata.oid)
+		return false, err
+	}
+
+	return value > s.metadata.activationValue, nil
+}
+
+// Close has nothing to dispose, a connection is opened for each query
+func (s *snmpScaler) Close() error {
+	return nil
+}
+
+// GetMetricSpecForScaling returns the MetricSpec for the Horizontal Pod Autoscaler
+func (s *snmpScaler) GetMetricSpecForScaling() []v2beta2.MetricSpec {
+	targetValue := resource.NewQuantity(s.metadata.targetValue, resource.DecimalSI)
+	externalMetric := &v2beta2.ExternalMetricSource{
+		Metric: v2beta2.MetricIdentifier{
+			Name: kedautil.NormalizeString(fmt.Sprintf("%s-%s-%s", "snmp", s.metadata.host, strings.TrimPrefix(s.metadata.oid, "."))),
+		},
+		Target: v2beta2.MetricTarget{
+			Type:         v2beta2.AverageValueMetricType,
+			AverageValue: targetValue,
+		},
+	}
+	metricSpec := v2beta2.MetricSpec{External: externalMetric, Type: externalMetricType}
+	return []v2beta2.MetricSpec{metricSpec}
+}
+
+// GetMetrics returns the value of the object
+func (s *snmpScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
+	value, err := s.getValue(ctx)
+	if err != nil {
+		return []external_metrics.ExternalMetricValue{}, fmt.Errorf("error getting snmp value: %s", err)
+	}
+
+	metric := external_metrics.ExternalMetricValue{
+		MetricName: metricName,
+		Value:      *resource.NewQuantity(value, resource.DecimalSI),
+		Timestamp:  metav1.Now(),
+	}
+
+	return append([]external_metrics.ExternalMetricValue{}, metric), nil
+}
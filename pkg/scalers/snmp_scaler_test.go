@@ -0,0 +1,172 @@
+package scalers
+
+import (
+	"math"
+	"testing"
+
+	"github.com/gosnmp/gosnmp"
+	"github.com/stretchr/testify/assert"
+)
+
+type parseSNMPMetadataTestData struct {
+	metadata   map[string]string
+	authParams map[string]string
+	isError    bool
+}
+
+type snmpMetricIdentifier struct {
+	metadataTestData *parseSNMPMetadataTestData
+	name             string
+}
+
+var testSNMPMetadata = []parseSNMPMetadataTestData{
+	// nothing passed
+	{map[string]string{}, map[string]string{}, true},
+	// v2c with the default community
+	{map[string]string{"target": "10.0.0.1", "oid": ".1.3.6.1.4.1.9.9.1.1", "targetValue": "100"}, map[string]string{}, false},
+	// v2c with a port and a community
+	{map[string]string{"target": "appliance:1161", "oid": "1.3.6.1.4.1.9.9.1.2", "targetValue": "50", "activationValue": "5"}, map[string]string{"community": "private"}, false},
+	// missing oid
+	{map[string]string{"target": "10.0.0.1", "targetValue": "100"}, map[string]string{}, true},
+	// missing targetValue
+	{map[string]string{"target": "10.0.0.1", "oid": ".1.3.6.1.2.1.1.3.0"}, map[string]string{}, true},
+	// malformed targetValue
+	{map[string]string{"target": "10.0.0.1", "oid": ".1.3.6.1.2.1.1.3.0", "targetValue": "many"}, map[string]string{}, true},
+	// malformed activationValue
+	{map[string]string{"target": "10.0.0.1", "oid": ".1.3.6.1.2.1.1.3.0", "targetValue": "10", "activationValue": "few"}, map[string]string{}, true},
+	// malformed port
+	{map[string]string{"target": "10.0.0.1:snmp", "oid": ".1.3.6.1.2.1.1.3.0", "targetValue": "10"}, map[string]string{}, true},
+	// malformed timeout
+	{map[string]string{"target": "10.0.0.1", "oid": ".1.3.6.1.2.1.1.3.0", "targetValue": "10", "timeout": "0"}, map[string]string{}, true},
+	// unsupported version
+	{map[string]string{"target": "10.0.0.1", "oid": ".1.3.6.1.2.1.1.3.0", "targetValue": "10", "version": "v1"}, map[string]string{}, true},
+	// v3 without username
+	{map[string]string{"target": "10.0.0.1", "oid": ".1.3.6.1.2.1.1.3.0", "targetValue": "10", "version": "v3"}, map[string]string{}, true},
+	// v3 without authentication
+	{map[string]string{"target": "10.0.0.1", "oid": ".1.3.6.1.2.1.1.3.0", "targetValue": "10", "version": "v3"}, map[string]string{"username": "keda"}, false},
+	// v3 with authentication and privacy
+	{map[string]string{"target": "10.0.0.1", "oid": ".1.3.6.1.2.1.1.3.0", "targetValue": "10", "version": "V3", "contextName": "queues"}, map[string]string{"username": "keda", "authProtocol": "sha256", "authPassphrase": "authsecret", "privProtocol": "aes", "privPassphrase": "privsecret"}, false},
+	// v3 with an unsupported authProtocol
+	{map[string]string{"target": "10.0.0.1", "oid": ".1.3.6.1.2.1.1.3.0", "targetValue": "10", "version": "v3"}, map[string]string{"username": "keda", "authProtocol": "SHA1024", "authPassphrase": "authsecret"}, true},
+	// v3 with authProtocol without authPassphrase
+	{map[string]string{"target": "10.0.0.1", "oid": ".1.3.6.1.2.1.1.3.0", "targetValue": "10", "version": "v3"}, map[string]string{"username": "keda", "authProtocol": "SHA"}, true},
+	// v3 with privacy without authentication
+	{map[string]string{"target": "10.0.0.1", "oid": ".1.3.6.1.2.1.1.3.0", "targetValue": "10", "version": "v3"}, map[string]string{"username": "keda", "privProtocol": "AES", "privPassphrase": "privsecret"}, true},
+	// v3 with privProtocol without privPassphrase
+	{map[string]string{"target": "10.0.0.1", "oid": ".1.3.6.1.2.1.1.3.0", "targetValue": "10", "version": "v3"}, map[string]string{"username": "keda", "authProtocol": "SHA", "authPassphrase": "authsecret", "privProtocol": "AES"}, true},
+}
+
+var snmpMetricIdentifiers = []snmpMetricIdentifier{
+	{&testSNMPMetadata[1], "snmp-10-0-0-1-1-3-6-1-4-1-9-9-1-1"},
+	{&testSNMPMetadata[2], "snmp-appliance-1-3-6-1-4-1-9-9-1-2"},
+}
+
+func TestSNMPParseMetadata(t *testing.T) {
+	for _, testData := range testSNMPMetadata {
+		_, err := parseSNMPMetadata(map[string]string{}, testData.metadata, testData.authParams)
+		if err != nil && !testData.isError {
+			t.Error("Expected success but got error", err)
+		}
+		if testData.isError && err == nil {
+			t.Error("Expected error but got success")
+		}
+	}
+}
+
+func TestSNMPParseTarget(t *testing.T) {
+	meta, err := parseSNMPMetadata(map[string]string{}, testSNMPMetadata[1].metadata, testSNMPMetadata[1].authParams)
+	assert.NoError(t, err)
+	assert.Equal(t, "10.0.0.1", meta.host)
+	assert.Equal(t, uint16(161), meta.port)
+	assert.Equal(t, "public", meta.community)
+
+	meta, err = parseSNMPMetadata(map[string]string{}, testSNMPMetadata[2].metadata, testSNMPMetadata[2].authParams)
+	assert.NoError(t, err)
+	assert.Equal(t, "appliance", meta.host)
+	assert.Equal(t, uint16(1161), meta.port)
+	assert.Equal(t, "private", meta.community)
+	assert.Equal(t, int64(5), meta.activationValue)
+}
+
+func TestSNMPGetMetricSpecForScaling(t *testing.T) {
+	for _, testData := range snmpMetricIdentifiers {
+		meta, err := parseSNMPMetadata(map[string]string{}, testData.metadataTestData.metadata, testData.metadataTestData.authParams)
+		if err != nil {
+			t.Fatal("Could not parse metadata:", err)
+		}
+		mockSNMPScaler := snmpScaler{meta}
+
+		metricSpec := mockSNMPScaler.GetMetricSpecForScaling()
+		metricName := metricSpec[0].External.Metric.Name
+		if metricName != testData.name {
+			t.Error("Wrong External metric source name:", metricName)
+		}
+	}
+}
+
+func TestSNMPNewClient(t *testing.T) {
+	tests := []struct {
+		testData *parseSNMPMetadataTestData
+		version  gosnmp.SnmpVersion
+		msgFlags gosnmp.SnmpV3MsgFlags
+	}{
+		{&testSNMPMetadata[2], gosnmp.Version2c, gosnmp.NoAuthNoPriv},
+		{&testSNMPMetadata[11], gosnmp.Version3, gosnmp.NoAuthNoPriv},
+		{&testSNMPMetadata[12], gosnmp.Version3, gosnmp.AuthPriv},
+	}
+
+	for _, test := range tests {
+		meta, err := parseSNMPMetadata(map[string]string{}, test.testData.metadata, test.testData.authParams)
+		if err != nil {
+			t.Fatal("Could not parse metadata:", err)
+		}
+		client := (&snmpScaler{meta}).newClient()
+		assert.Equal(t, test.version, client.Version)
+		assert.Equal(t, test.msgFlags, client.MsgFlags)
+		assert.Equal(t, meta.port, client.Port)
+		if test.version == gosnmp.Version2c {
+			assert.Equal(t, "private", client.Community)
+			continue
+		}
+
+		usm := client.SecurityParameters.(*gosnmp.UsmSecurityParameters)
+		assert.Equal(t, gosnmp.UserSecurityModel, client.SecurityModel)
+		assert.Equal(t, "keda", usm.UserName)
+		assert.Equal(t, meta.authProtocol, usm.AuthenticationProtocol)
+		assert.Equal(t, meta.privProtocol, usm.PrivacyProtocol)
+	}
+
+	meta, _ := parseSNMPMetadata(map[string]string{}, testSNMPMetadata[12].metadata, testSNMPMetadata[12].authParams)
+	assert.Equal(t, gosnmp.SHA256, meta.authProtocol)
+	assert.Equal(t, gosnmp.AES, meta.privProtocol)
+	assert.Equal(t, "queues", meta.contextName)
+}
+
+func TestSNMPPDUValue(t *testing.T) {
+	tests := []struct {
+		pdu     gosnmp.SnmpPDU
+		value   int64
+		isError bool
+	}{
+		{gosnmp.SnmpPDU{Name: ".1.1", Type: gosnmp.Integer, Value: -3}, -3, false},
+		{gosnmp.SnmpPDU{Name: ".1.2", Type: gosnmp.Gauge32, Value: uint(42)}, 42, false},
+		{gosnmp.SnmpPDU{Name: ".1.3", Type: gosnmp.Counter32, Value: uint(7)}, 7, false},
+		{gosnmp.SnmpPDU{Name: ".1.4", Type: gosnmp.Counter64, Value: uint64(1 << 40)}, 1 << 40, false},
+		{gosnmp.SnmpPDU{Name: ".1.5", Type: gosnmp.TimeTicks, Value: uint32(300)}, 300, false},
+		{gosnmp.SnmpPDU{Name: ".1.6", Type: gosnmp.OctetString, Value: []byte(" 12 ")}, 12, false},
+		{gosnmp.SnmpPDU{Name: ".1.7", Type: gosnmp.OctetString, Value: []byte("busy")}, 0, true},
+		{gosnmp.SnmpPDU{Name: ".1.8", Type: gosnmp.Counter64, Value: uint64(math.MaxUint64)}, 0, true},
+		{gosnmp.SnmpPDU{Name: ".1.9", Type: gosnmp.NoSuchInstance}, 0, true},
+		{gosnmp.SnmpPDU{Name: ".1.10", Type: gosnmp.IPAddress, Value: "10.0.0.1"}, 0, true},
+	}
+
+	for _, test := range tests {
+		value, err := snmpPDUValue(test.pdu)
+		if test.isError {
+			assert.Error(t, err, test.pdu.Name)
+			continue
+		}
+		assert.NoError(t, err, test.pdu.Name)
+		assert.Equal(t, test.value, value, test.pdu.Name)
+	}
+}
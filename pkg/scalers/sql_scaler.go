@@ -0,0 +1,208 @@
+package scalers
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"strconv"
+	"strings"
+
+	// ClickHouse driver required for this scaler
+	_ "github.com/ClickHouse/clickhouse-go"
+	// MSSQL driver required for this scaler
+	_ "github.com/denisenkom/go-mssqldb"
+	// MySQL driver required for this scaler
+	_ "github.com/go-sql-driver/mysql"
+	// PostgreSQL driver required for this scaler
+	_ "github.com/lib/pq"
+	"k8s.io/api/autoscaling/v2beta2"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/metrics/pkg/apis/external_metrics"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	kedautil "github.com/kedacore/keda/pkg/util"
+)
+
+// sqlDrivers maps the supported values of the driver metadata to the names of the registered database/sql drivers
+var sqlDrivers = map[string]string{
+	"postgres":   "postgres",
+	"postgresql": "postgres",
+	"mysql":      "mysql",
+	"mssql":      "sqlserver",
+	"sqlserver":  "sqlserver",
+	"clickhouse": "clickhouse",
+}
+
+type sqlScaler struct {
+	metadata   *sqlMetadata
+	connection *sql.DB
+}
+
+type sqlMetadata struct {
+	driver           string
+	connection       string
+	query            string
+	targetQueryValue int
+	metricName       string
+}
+
+var sqlLog = logf.Log.WithName("sql_scaler")
+
+// NewSQLScaler creates a new scaler running a scalar query against any database with a supported driver
+func NewSQLScaler(resolvedEnv, metadata, authParams map[string]string) (Scaler, error) {
+	meta, err := parseSQLMetadata(resolvedEnv, metadata, authParams)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing sql metadata: %s", err)
+	}
+	return newSQLScaler(meta)
+}
+
+// newSQLScaler connects to the database of the metadata, the postgresql and mysql scalers are sql scalers with metadata of their own
+func newSQLScaler(meta *sqlMetadata) (Scaler, error) {
+	conn, err := newSQLConnection(meta)
+	if err != nil {
+		return nil, fmt.Errorf("error establishing %s connection: %s", meta.driver, err)
+	}
+	return &sqlScaler{
+		metadata:   meta,
+		connection: conn,
+	}, nil
+}
+
+func parseSQLMetadata(resolvedEnv, metadata, authParams map[string]string) (*sqlMetadata, error) {
+	meta := sqlMetadata{}
+
+	if val, ok := metadata["driver"]; ok && val != "" {
+		driver := strings.ToLower(val)
+		if _, ok := sqlDrivers[driver]; !ok {
+			return nil, fmt.Errorf("driver %s not supported, supported drivers are postgres, mysql, mssql and clickhouse", val)
+		}
+		meta.driver = driver
+	} else {
+		return nil, fmt.Errorf("no driver given")
+	}
+
+	if val, ok := metadata["query"]; ok && val != "" {
+		meta.query = val
+	} else {
+		return nil, fmt.Errorf("no query given")
+	}
+
+	if val, ok := metadata["targetQueryValue"]; ok && val != "" {
+		targetQueryValue, err := strconv.Atoi(val)
+		if err != nil {
+			return nil, fmt.Errorf("targetQueryValue parsing error %s", err.Error())
+		}
+		meta.targetQueryValue = targetQueryValue
+	} else {
+		return nil, fmt.Errorf("no targetQueryValue given")
+	}
+
+	if authParams["connection"] != "" {
+		meta.connection = authParams["connection"]
+	} else if metadata["connectionFromEnv"] != "" {
+		meta.connection = resolvedEnv[metadata["connectionFromEnv"]]
+	}
+	if meta.connection == "" {
+		return nil, fmt.Errorf("no connection given")
+	}
+
+	if val, ok := metadata["metricName"]; ok && val != "" {
+		meta.metricName = kedautil.NormalizeString(fmt.Sprintf("sql-%s-%s", meta.driver, val))
+	} else {
+		// the connection string isn't part of the metric name as it usually holds the password
+		hash := fnv.New32a()
+		hash.Write([]byte(meta.query))
+		meta.metricName = kedautil.NormalizeString(fmt.Sprintf("sql-%s-%x", meta.driver, hash.Sum32()))
+	}
+
+	return &meta, nil
+}
+
+// newSQLConnection opens and checks the connection to the database
+func newSQLConnection(meta *sqlMetadata) (*sql.DB, error) {
+	db, err := sql.Open(sqlDrivers[meta.driver], meta.connection)
+	if err != nil {
+		sqlLog.Error(err, fmt.Sprintf("Found error opening %s connection: %s", meta.driver, err))
+		return nil, err
+	}
+	err = db.Ping()
+	if err != nil {
+		sqlLog.Error(err, fmt.Sprintf("Found error pinging %s: %s", meta.driver, err))
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+// Close disposes of the database connections
+func (s *sqlScaler) Close() error {
+	err := s.connection.Close()
+	if err != nil {
+		sqlLog.Error(err, fmt.Sprintf("Error closing %s connection", s.metadata.driver))
+		return err
+	}
+	return nil
+}
+
+// IsActive returns true if the query result is greater than zero
+func (s *sqlScaler) IsActive(ctx context.Context) (bool, error) {
+	value, err := s.getQueryResult(ctx)
+	if err != nil {
+		return false, fmt.Errorf("error inspecting %s: %s", s.metadata.driver, err)
+	}
+
+	return value > 0, nil
+}
+
+// getQueryResult returns the first column of the first row of the query result rounded to the nearest int, NULL is 0
+func (s *sqlScaler) getQueryResult(ctx context.Context) (int64, error) {
+	var value sql.NullFloat64
+	err := s.connection.QueryRowContext(ctx, s.metadata.query).Scan(&value)
+	if err != nil {
+		sqlLog.Error(err, fmt.Sprintf("could not query %s: %s", s.metadata.driver, err))
+		return 0, fmt.Errorf("could not query %s: %s", s.metadata.driver, err)
+	}
+	if !value.Valid {
+		return 0, nil
+	}
+	return int64(math.Round(value.Float64)), nil
+}
+
+// GetMetricSpecForScaling returns the MetricSpec for the Horizontal Pod Autoscaler
+func (s *sqlScaler) GetMetricSpecForScaling() []v2beta2.MetricSpec {
+	targetQueryValue := resource.NewQuantity(int64(s.metadata.targetQueryValue), resource.DecimalSI)
+	externalMetric := &v2beta2.ExternalMetricSource{
+		Metric: v2beta2.MetricIdentifier{
+			Name: s.metadata.metricName,
+		},
+		Target: v2beta2.MetricTarget{
+			Type:         v2beta2.AverageValueMetricType,
+			AverageValue: targetQueryValue,
+		},
+	}
+	metricSpec := v2beta2.MetricSpec{
+		External: externalMetric, Type: externalMetricType,
+	}
+	return []v2beta2.MetricSpec{metricSpec}
+}
+
+// GetMetrics returns value for a supported metric and an error if there is a problem getting the metric
+func (s *sqlScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
+	value, err := s.getQueryResult(ctx)
+	if err != nil {
+		return []external_metrics.ExternalMetricValue{}, fmt.Errorf("error inspecting %s: %s", s.metadata.driver, err)
+	}
+
+	metric := external_metrics.ExternalMetricValue{
+		MetricName: metricName,
+		Value:      *resource.NewQuantity(value, resource.DecimalSI),
+		Timestamp:  metav1.Now(),
+	}
+
+	return append([]external_metrics.ExternalMetricValue{}, metric), nil
+}
@@ -5,7 +5,6 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"net/http"
 	"strconv"
 
 	v2beta2 "k8s.io/api/autoscaling/v2beta2"
@@ -49,6 +48,7 @@ type stanMetadata struct {
 	durableName                  string
 	subject                      string
 	lagThreshold                 int64
+	retryPolicy                  kedautil.RetryPolicy
 }
 
 const (
@@ -104,6 +104,12 @@ func parseStanMetadata(metadata map[string]string) (stanMetadata, error) {
 		meta.lagThreshold = t
 	}
 
+	retryPolicy, err := parseHTTPRetryPolicy(metadata)
+	if err != nil {
+		return meta, err
+	}
+	meta.retryPolicy = retryPolicy
+
 	return meta, nil
 }
 
@@ -111,14 +117,14 @@ func parseStanMetadata(metadata map[string]string) (stanMetadata, error) {
 func (s *stanScaler) IsActive(ctx context.Context) (bool, error) {
 	monitoringEndpoint := s.getMonitoringEndpoint()
 
-	resp, err := http.Get(monitoringEndpoint)
+	resp, err := doHTTPGetWithRetry(ctx, nil, monitoringEndpoint, s.metadata.retryPolicy)
 	if err != nil {
 		stanLog.Error(err, "Unable to access the nats streaming broker monitoring endpoint", "natsServerMonitoringEndpoint", s.metadata.natsServerMonitoringEndpoint)
 		return false, err
 	}
 
 	if resp.StatusCode == 404 {
-		baseResp, err := http.Get(s.getSTANChannelsEndpoint())
+		baseResp, err := doHTTPGetWithRetry(ctx, nil, s.getSTANChannelsEndpoint(), s.metadata.retryPolicy)
 		if err != nil {
 			return false, err
 		}
@@ -201,7 +207,7 @@ func (s *stanScaler) GetMetricSpecForScaling() []v2beta2.MetricSpec {
 
 //GetMetrics returns value for a supported metric and an error if there is a problem getting the metric
 func (s *stanScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
-	resp, err := http.Get(s.getMonitoringEndpoint())
+	resp, err := doHTTPGetWithRetry(ctx, nil, s.getMonitoringEndpoint(), s.metadata.retryPolicy)
 
 	if err != nil {
 		stanLog.Error(err, "Unable to access the nats streaming broker monitoring endpoint", "natsServerMonitoringEndpoint", s.metadata.natsServerMonitoringEndpoint)
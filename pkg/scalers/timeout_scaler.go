@@ -0,0 +1,104 @@
+package scalers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/metrics/pkg/apis/external_metrics"
+)
+
+// TimeoutMetadata is the trigger metadata key of the time a check of the scaler can take,
+// in seconds or as a duration
+const TimeoutMetadata = "timeout"
+
+// timeoutScaler bounds the checks of the wrapped scaler by a timeout, the context of the check is cancelled once it elapsed
+type timeoutScaler struct {
+	Scaler
+	timeout time.Duration
+}
+
+type isActiveResult struct {
+	active bool
+	err    error
+}
+
+type getMetricsResult struct {
+	metrics []external_metrics.ExternalMetricValue
+	err     error
+}
+
+// NewTimeoutScaler wraps the scaler so its checks return an error once the timeout elapsed,
+// even if the wrapped scaler doesn't return on the cancellation of the context
+func NewTimeoutScaler(scaler Scaler, timeout time.Duration) Scaler {
+	return &timeoutScaler{
+		Scaler:  scaler,
+		timeout: timeout,
+	}
+}
+
+// ParseTimeout returns the timeout in the trigger metadata, a number of seconds or a duration.
+// The returned timeout is 0 if no timeout is set.
+func ParseTimeout(metadata map[string]string) (time.Duration, error) {
+	val, ok := metadata[TimeoutMetadata]
+	if !ok || val == "" {
+		return 0, nil
+	}
+
+	var timeout time.Duration
+	if seconds, err := strconv.Atoi(val); err == nil {
+		timeout = time.Duration(seconds) * time.Second
+	} else if timeout, err = time.ParseDuration(val); err != nil {
+		return 0, fmt.Errorf("error parsing %s: %s is neither a number of seconds nor a duration", TimeoutMetadata, val)
+	}
+	if timeout <= 0 {
+		return 0, fmt.Errorf("%s must be greater than 0", TimeoutMetadata)
+	}
+	return timeout, nil
+}
+
+func (s *timeoutScaler) IsActive(ctx context.Context) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	// the result is buffered so the check can still return once it was given up
+	result := make(chan isActiveResult, 1)
+	go func() {
+		active, err := s.Scaler.IsActive(ctx)
+		result <- isActiveResult{active: active, err: err}
+	}()
+
+	select {
+	case r := <-result:
+		return r.active, r.err
+	case <-ctx.Done():
+		return false, s.timeoutError(ctx)
+	}
+}
+
+func (s *timeoutScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	result := make(chan getMetricsResult, 1)
+	go func() {
+		metrics, err := s.Scaler.GetMetrics(ctx, metricName, metricSelector)
+		result <- getMetricsResult{metrics: metrics, err: err}
+	}()
+
+	select {
+	case r := <-result:
+		return r.metrics, r.err
+	case <-ctx.Done():
+		return []external_metrics.ExternalMetricValue{}, s.timeoutError(ctx)
+	}
+}
+
+func (s *timeoutScaler) timeoutError(ctx context.Context) error {
+	if ctx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("the check of the scaler didn't return within the %s timeout", s.timeout)
+	}
+	return ctx.Err()
+}
@@ -0,0 +1,106 @@
+package scalers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	v2beta2 "k8s.io/api/autoscaling/v2beta2"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/metrics/pkg/apis/external_metrics"
+)
+
+type parseTimeoutTestData struct {
+	metadata        map[string]string
+	expectedTimeout time.Duration
+	isError         bool
+}
+
+var parseTimeoutTestDataset = []parseTimeoutTestData{
+	// no timeout
+	{map[string]string{}, 0, false},
+	{map[string]string{"timeout": ""}, 0, false},
+	// seconds
+	{map[string]string{"timeout": "30"}, 30 * time.Second, false},
+	// duration
+	{map[string]string{"timeout": "1m30s"}, 90 * time.Second, false},
+	{map[string]string{"timeout": "500ms"}, 500 * time.Millisecond, false},
+	// invalid timeout
+	{map[string]string{"timeout": "soon"}, 0, true},
+	{map[string]string{"timeout": "0"}, 0, true},
+	{map[string]string{"timeout": "-5s"}, 0, true},
+}
+
+func TestParseTimeout(t *testing.T) {
+	for _, testData := range parseTimeoutTestDataset {
+		timeout, err := ParseTimeout(testData.metadata)
+		if err != nil && !testData.isError {
+			t.Errorf("Expected success for %v but got error %s", testData.metadata, err)
+		}
+		if testData.isError && err == nil {
+			t.Errorf("Expected error for %v but got success", testData.metadata)
+		}
+		if timeout != testData.expectedTimeout {
+			t.Errorf("Expected timeout %s for %v, got %s", testData.expectedTimeout, testData.metadata, timeout)
+		}
+	}
+}
+
+// hangingScaler blocks its checks until it is released, it ignores the context like a client without context support
+type hangingScaler struct {
+	release chan struct{}
+}
+
+func (s *hangingScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
+	<-s.release
+	return []external_metrics.ExternalMetricValue{{MetricName: metricName}}, nil
+}
+
+func (s *hangingScaler) GetMetricSpecForScaling() []v2beta2.MetricSpec {
+	return nil
+}
+
+func (s *hangingScaler) IsActive(ctx context.Context) (bool, error) {
+	<-s.release
+	return true, nil
+}
+
+func (s *hangingScaler) Close() error {
+	return nil
+}
+
+func TestTimeoutScaler(t *testing.T) {
+	wrapped := &hangingScaler{release: make(chan struct{})}
+	defer close(wrapped.release)
+	scaler := NewTimeoutScaler(wrapped, 50*time.Millisecond)
+
+	if name := ScalerName(scaler); name != "hangingScaler" {
+		t.Errorf("Expected the name of the wrapped scaler, got %s", name)
+	}
+
+	start := time.Now()
+	if _, err := scaler.IsActive(context.TODO()); err == nil {
+		t.Error("Expected the hanging IsActive to fail once the timeout elapsed")
+	}
+	if _, err := scaler.GetMetrics(context.TODO(), "metric", nil); err == nil {
+		t.Error("Expected the hanging GetMetrics to fail once the timeout elapsed")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected the checks to return after the timeout, they took %s", elapsed)
+	}
+}
+
+func TestTimeoutScalerWithinTimeout(t *testing.T) {
+	wrapped := &countingScaler{}
+	scaler := NewTimeoutScaler(wrapped, time.Second)
+
+	if active, err := scaler.IsActive(context.TODO()); err != nil || !active {
+		t.Errorf("Expected the result of the wrapped scaler, got %v and %v", active, err)
+	}
+	if _, err := scaler.GetMetrics(context.TODO(), "metric", nil); err != nil {
+		t.Errorf("Expected the result of the wrapped scaler, got error %s", err)
+	}
+	if wrapped.calls != 2 {
+		t.Errorf("Expected 2 calls of the wrapped scaler, got %d", wrapped.calls)
+	}
+}
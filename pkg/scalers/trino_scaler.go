@@ -0,0 +1,231 @@
+package scalers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	v2beta2 "k8s.io/api/autoscaling/v2beta2"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/metrics/pkg/apis/external_metrics"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	kedautil "github.com/kedacore/keda/pkg/util"
+)
+
+const (
+	trinoServerType  = "trino"
+	prestoServerType = "presto"
+
+	trinoQueryPath            = "/v1/query"
+	trinoQueuedState          = "QUEUED"
+	defaultTrinoUser          = "keda"
+	defaultTrinoQueuedQueries = 5
+	trinoRequestTimeout       = 10 * time.Second
+)
+
+type trinoScaler struct {
+	metadata   *trinoMetadata
+	httpClient *http.Client
+}
+
+type trinoMetadata struct {
+	serverType     string
+	coordinatorURL string
+	user           string
+	username       string
+	password       string
+	resourceGroup  string
+	queuedQueries  int
+	retryPolicy    kedautil.RetryPolicy
+}
+
+// trinoQueryInfo is the part of the basic query info returned by the coordinator used by the scaler
+type trinoQueryInfo struct {
+	State           string   `json:"state"`
+	ResourceGroupID []string `json:"resourceGroupId"`
+}
+
+var trinoLog = logf.Log.WithName("trino_scaler")
+
+// NewTrinoScaler creates a new scaler for the queued queries of a Trino or Presto coordinator
+func NewTrinoScaler(resolvedEnv, metadata, authParams map[string]string) (Scaler, error) {
+	meta, err := parseTrinoMetadata(resolvedEnv, metadata, authParams)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing trino metadata: %s", err)
+	}
+
+	return &trinoScaler{
+		metadata:   meta,
+		httpClient: &http.Client{Timeout: trinoRequestTimeout},
+	}, nil
+}
+
+func parseTrinoMetadata(resolvedEnv, metadata, authParams map[string]string) (*trinoMetadata, error) {
+	meta := trinoMetadata{
+		serverType:    trinoServerType,
+		user:          defaultTrinoUser,
+		queuedQueries: defaultTrinoQueuedQueries,
+	}
+
+	if val, ok := metadata["serverType"]; ok && val != "" {
+		switch serverType := strings.ToLower(val); serverType {
+		case trinoServerType, prestoServerType:
+			meta.serverType = serverType
+		default:
+			return nil, fmt.Errorf("serverType %s not supported, supported server types are %s, %s", val, trinoServerType, prestoServerType)
+		}
+	}
+
+	if val, ok := metadata["coordinatorURL"]; ok && val != "" {
+		meta.coordinatorURL = strings.TrimSuffix(val, "/")
+	} else if val, ok := metadata["coordinatorURLFromEnv"]; ok && val != "" {
+		meta.coordinatorURL = strings.TrimSuffix(resolvedEnv[val], "/")
+	}
+	if meta.coordinatorURL == "" {
+		return nil, fmt.Errorf("no coordinatorURL given")
+	}
+
+	if val, ok := metadata["user"]; ok && val != "" {
+		meta.user = val
+	}
+
+	if val, ok := metadata["resourceGroup"]; ok && val != "" {
+		meta.resourceGroup = val
+	}
+
+	if val, ok := metadata["queuedQueries"]; ok && val != "" {
+		queuedQueries, err := strconv.Atoi(val)
+		if err != nil {
+			return nil, fmt.Errorf("can't parse queuedQueries: %s", err)
+		}
+		meta.queuedQueries = queuedQueries
+	}
+
+	if val, ok := authParams["username"]; ok && val != "" {
+		meta.username = val
+	}
+	if val, ok := authParams["password"]; ok && val != "" {
+		meta.password = val
+	} else if val, ok := metadata["passwordFromEnv"]; ok && val != "" {
+		meta.password = resolvedEnv[val]
+	}
+	if meta.password != "" && meta.username == "" {
+		meta.username = meta.user
+	}
+
+	retryPolicy, err := parseHTTPRetryPolicy(metadata)
+	if err != nil {
+		return nil, err
+	}
+	meta.retryPolicy = retryPolicy
+
+	return &meta, nil
+}
+
+// IsActive returns true if there are queued queries
+func (s *trinoScaler) IsActive(ctx context.Context) (bool, error) {
+	queued, err := s.getQueuedQueries(ctx)
+	if err != nil {
+		trinoLog.Error(err, "error getting queued queries", "coordinatorURL", s.metadata.coordinatorURL)
+		return false, err
+	}
+
+	return queued > 0, nil
+}
+
+// getQueuedQueries returns the number of the queued queries, in the resource group and its sub groups if a resourceGroup is given
+func (s *trinoScaler) getQueuedQueries(ctx context.Context) (int64, error) {
+	req, err := http.NewRequest(http.MethodGet, s.metadata.coordinatorURL+trinoQueryPath, nil)
+	if err != nil {
+		return 0, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Accept", "application/json")
+	if s.metadata.serverType == prestoServerType {
+		req.Header.Set("X-Presto-User", s.metadata.user)
+	} else {
+		req.Header.Set("X-Trino-User", s.metadata.user)
+	}
+	if s.metadata.username != "" {
+		req.SetBasicAuth(s.metadata.username, s.metadata.password)
+	}
+
+	resp, err := kedautil.DoWithRetry(s.httpClient, req, s.metadata.retryPolicy)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("%s coordinator returned %d", s.metadata.serverType, resp.StatusCode)
+	}
+
+	var queries []trinoQueryInfo
+	if err := json.NewDecoder(resp.Body).Decode(&queries); err != nil {
+		return 0, fmt.Errorf("error decoding %s coordinator response: %s", s.metadata.serverType, err)
+	}
+
+	var queued int64
+	for _, query := range queries {
+		if query.State == trinoQueuedState && s.inResourceGroup(query.ResourceGroupID) {
+			queued++
+		}
+	}
+	return queued, nil
+}
+
+func (s *trinoScaler) inResourceGroup(resourceGroupID []string) bool {
+	if s.metadata.resourceGroup == "" {
+		return true
+	}
+	id := strings.Join(resourceGroupID, ".")
+	return id == s.metadata.resourceGroup || strings.HasPrefix(id, s.metadata.resourceGroup+".")
+}
+
+// GetMetricSpecForScaling returns the MetricSpec for the Horizontal Pod Autoscaler
+func (s *trinoScaler) GetMetricSpecForScaling() []v2beta2.MetricSpec {
+	targetQueuedQueries := resource.NewQuantity(int64(s.metadata.queuedQueries), resource.DecimalSI)
+	resourceGroup := s.metadata.resourceGroup
+	if resourceGroup == "" {
+		resourceGroup = "all"
+	}
+	externalMetric := &v2beta2.ExternalMetricSource{
+		Metric: v2beta2.MetricIdentifier{
+			Name: kedautil.NormalizeString(fmt.Sprintf("%s-%s", s.metadata.serverType, resourceGroup)),
+		},
+		Target: v2beta2.MetricTarget{
+			Type:         v2beta2.AverageValueMetricType,
+			AverageValue: targetQueuedQueries,
+		},
+	}
+	metricSpec := v2beta2.MetricSpec{External: externalMetric, Type: externalMetricType}
+	return []v2beta2.MetricSpec{metricSpec}
+}
+
+// GetMetrics returns value for a supported metric and an error if there is a problem getting the metric
+func (s *trinoScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
+	queued, err := s.getQueuedQueries(ctx)
+	if err != nil {
+		return []external_metrics.ExternalMetricValue{}, fmt.Errorf("error getting %s queued queries: %s", s.metadata.serverType, err)
+	}
+
+	metric := external_metrics.ExternalMetricValue{
+		MetricName: metricName,
+		Value:      *resource.NewQuantity(queued, resource.DecimalSI),
+		Timestamp:  metav1.Now(),
+	}
+
+	return append([]external_metrics.ExternalMetricValue{}, metric), nil
+}
+
+// Close does nothing in case of trinoScaler
+func (s *trinoScaler) Close() error {
+	return nil
+}
@@ -0,0 +1,138 @@
+package scalers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type parseTrinoMetadataTestData struct {
+	metadata   map[string]string
+	authParams map[string]string
+	isError    bool
+}
+
+type trinoMetricIdentifier struct {
+	metadataTestData *parseTrinoMetadataTestData
+	name             string
+}
+
+var testTrinoResolvedEnv = map[string]string{
+	"TRINO_URL":      "http://trino:8080",
+	"TRINO_PASSWORD": "secret",
+}
+
+var testTrinoMetadata = []parseTrinoMetadataTestData{
+	// nothing passed
+	{map[string]string{}, map[string]string{}, true},
+	// properly formed
+	{map[string]string{"coordinatorURL": "http://trino:8080", "resourceGroup": "global.adhoc", "queuedQueries": "2"}, map[string]string{}, false},
+	// presto with coordinator url and password from env
+	{map[string]string{"serverType": "Presto", "coordinatorURLFromEnv": "TRINO_URL", "passwordFromEnv": "TRINO_PASSWORD"}, map[string]string{}, false},
+	// basic auth from authParams
+	{map[string]string{"coordinatorURL": "http://trino:8080"}, map[string]string{"username": "admin", "password": "admin"}, false},
+	// unsupported serverType
+	{map[string]string{"serverType": "hive", "coordinatorURL": "http://trino:8080"}, map[string]string{}, true},
+	// malformed queuedQueries
+	{map[string]string{"coordinatorURL": "http://trino:8080", "queuedQueries": "a few"}, map[string]string{}, true},
+}
+
+var trinoMetricIdentifiers = []trinoMetricIdentifier{
+	{&testTrinoMetadata[1], "trino-global-adhoc"},
+	{&testTrinoMetadata[2], "presto-all"},
+}
+
+func TestTrinoParseMetadata(t *testing.T) {
+	for _, testData := range testTrinoMetadata {
+		_, err := parseTrinoMetadata(testTrinoResolvedEnv, testData.metadata, testData.authParams)
+		if err != nil && !testData.isError {
+			t.Error("Expected success but got error", err)
+		}
+		if testData.isError && err == nil {
+			t.Errorf("Expected error but got success for %v", testData.metadata)
+		}
+	}
+}
+
+func TestTrinoGetMetricSpecForScaling(t *testing.T) {
+	for _, testData := range trinoMetricIdentifiers {
+		meta, err := parseTrinoMetadata(testTrinoResolvedEnv, testData.metadataTestData.metadata, testData.metadataTestData.authParams)
+		if err != nil {
+			t.Fatal("Could not parse metadata:", err)
+		}
+		mockTrinoScaler := trinoScaler{meta, http.DefaultClient}
+
+		metricSpec := mockTrinoScaler.GetMetricSpecForScaling()
+		metricName := metricSpec[0].External.Metric.Name
+		if metricName != testData.name {
+			t.Error("Wrong External metric source name:", metricName)
+		}
+	}
+}
+
+func TestTrinoGetQueuedQueries(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/query", r.URL.Path)
+		assert.Equal(t, "keda", r.Header.Get("X-Trino-User"))
+		w.Write([]byte(`[
+			{"state": "QUEUED", "resourceGroupId": ["global", "adhoc"]},
+			{"state": "QUEUED", "resourceGroupId": ["global", "adhoc", "bi"]},
+			{"state": "RUNNING", "resourceGroupId": ["global", "adhoc"]},
+			{"state": "QUEUED", "resourceGroupId": ["global", "adhocs"]},
+			{"state": "QUEUED", "resourceGroupId": ["global", "etl"]}
+		]`))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		resourceGroup string
+		expected      int64
+	}{
+		{"global.adhoc", 2},
+		{"global", 4},
+		{"", 4},
+		{"global.none", 0},
+	}
+
+	for _, test := range tests {
+		meta, err := parseTrinoMetadata(nil, map[string]string{"coordinatorURL": server.URL + "/", "resourceGroup": test.resourceGroup}, nil)
+		if err != nil {
+			t.Fatal("Could not parse metadata:", err)
+		}
+		scaler := trinoScaler{meta, http.DefaultClient}
+
+		queued, err := scaler.getQueuedQueries(context.TODO())
+		assert.NoError(t, err)
+		assert.Equal(t, test.expected, queued, test.resourceGroup)
+	}
+}
+
+func TestPrestoGetQueuedQueriesAuth(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "presto-user", r.Header.Get("X-Presto-User"))
+		username, password, ok := r.BasicAuth()
+		if !ok || username != "presto-user" || password != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`[{"state": "QUEUED", "resourceGroupId": ["global"]}]`))
+	}))
+	defer server.Close()
+
+	meta, err := parseTrinoMetadata(testTrinoResolvedEnv, map[string]string{"serverType": "presto", "coordinatorURL": server.URL, "user": "presto-user", "passwordFromEnv": "TRINO_PASSWORD"}, nil)
+	if err != nil {
+		t.Fatal("Could not parse metadata:", err)
+	}
+	scaler := trinoScaler{meta, http.DefaultClient}
+
+	active, err := scaler.IsActive(context.TODO())
+	assert.NoError(t, err)
+	assert.True(t, active)
+
+	meta.password = "wrong"
+	_, err = scaler.GetMetrics(context.TODO(), "presto-all", nil)
+	assert.Error(t, err)
+}
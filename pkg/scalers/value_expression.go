@@ -0,0 +1,155 @@
+package scalers
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/checker/decls"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/google/cel-go/interpreter/functions"
+	exprpb "google.golang.org/genproto/googleapis/api/expr/v1alpha1"
+)
+
+const (
+	// ValueExpressionMetadata is the trigger metadata key of the expression applied to the metric values of the scaler
+	ValueExpressionMetadata = "valueExpression"
+
+	// ValueVariable is the raw metric value in a value expression
+	ValueVariable = "value"
+	// ReplicasVariable is the current number of replicas of the scale target in a value expression
+	ReplicasVariable = "replicas"
+)
+
+// ValueExpression is a compiled CEL expression returning a number. The variables value and replicas are doubles,
+// so the numbers they are combined with are written as doubles, e.g. value / 100.0 * replicas. Besides the CEL
+// standard functions, the functions min, max, clamp, abs, ceil, floor and round take and return doubles.
+type ValueExpression struct {
+	source    string
+	program   cel.Program
+	variables map[string]bool
+}
+
+// valueExpressionEnv declares the variables and the functions of the value expressions
+var valueExpressionEnv, valueExpressionEnvErr = cel.NewEnv(
+	cel.Declarations(
+		decls.NewVar(ValueVariable, decls.Double),
+		decls.NewVar(ReplicasVariable, decls.Double),
+		decls.NewFunction("min", decls.NewOverload("min_double_double", []*exprpb.Type{decls.Double, decls.Double}, decls.Double)),
+		decls.NewFunction("max", decls.NewOverload("max_double_double", []*exprpb.Type{decls.Double, decls.Double}, decls.Double)),
+		decls.NewFunction("clamp", decls.NewOverload("clamp_double_double_double", []*exprpb.Type{decls.Double, decls.Double, decls.Double}, decls.Double)),
+		decls.NewFunction("abs", decls.NewOverload("abs_double", []*exprpb.Type{decls.Double}, decls.Double)),
+		decls.NewFunction("ceil", decls.NewOverload("ceil_double", []*exprpb.Type{decls.Double}, decls.Double)),
+		decls.NewFunction("floor", decls.NewOverload("floor_double", []*exprpb.Type{decls.Double}, decls.Double)),
+		decls.NewFunction("round", decls.NewOverload("round_double", []*exprpb.Type{decls.Double}, decls.Double)),
+	),
+)
+
+// valueExpressionFunctions implements the functions declared by valueExpressionEnv, the type checker ensures their arguments are doubles
+var valueExpressionFunctions = []*functions.Overload{
+	{Operator: "min_double_double", Binary: func(lhs, rhs ref.Val) ref.Val {
+		return types.Double(math.Min(float64(lhs.(types.Double)), float64(rhs.(types.Double))))
+	}},
+	{Operator: "max_double_double", Binary: func(lhs, rhs ref.Val) ref.Val {
+		return types.Double(math.Max(float64(lhs.(types.Double)), float64(rhs.(types.Double))))
+	}},
+	{Operator: "clamp_double_double_double", Function: func(args ...ref.Val) ref.Val {
+		return types.Double(math.Min(math.Max(float64(args[0].(types.Double)), float64(args[1].(types.Double))), float64(args[2].(types.Double))))
+	}},
+	{Operator: "abs_double", Unary: doubleFunction(math.Abs)},
+	{Operator: "ceil_double", Unary: doubleFunction(math.Ceil)},
+	{Operator: "floor_double", Unary: doubleFunction(math.Floor)},
+	{Operator: "round_double", Unary: doubleFunction(math.Round)},
+}
+
+func doubleFunction(fn func(float64) float64) functions.UnaryOp {
+	return func(value ref.Val) ref.Val {
+		return types.Double(fn(float64(value.(types.Double))))
+	}
+}
+
+// ParseValueExpression returns the value expression of the trigger metadata, nil if no expression is set
+func ParseValueExpression(metadata map[string]string) (*ValueExpression, error) {
+	val, ok := metadata[ValueExpressionMetadata]
+	if !ok || strings.TrimSpace(val) == "" {
+		return nil, nil
+	}
+	expression, err := CompileValueExpression(val)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing %s: %s", ValueExpressionMetadata, err)
+	}
+	return expression, nil
+}
+
+// CompileValueExpression compiles the expression, the variables referenced must be value or replicas and the result a number
+func CompileValueExpression(source string) (*ValueExpression, error) {
+	if valueExpressionEnvErr != nil {
+		return nil, valueExpressionEnvErr
+	}
+	ast, issues := valueExpressionEnv.Compile(source)
+	if issues != nil && issues.Err() != nil {
+		return nil, issues.Err()
+	}
+	switch ast.ResultType().GetPrimitive() {
+	case exprpb.Type_DOUBLE, exprpb.Type_INT64, exprpb.Type_UINT64:
+	default:
+		return nil, fmt.Errorf("the expression %q doesn't return a number", source)
+	}
+
+	checked, err := cel.AstToCheckedExpr(ast)
+	if err != nil {
+		return nil, err
+	}
+	variables := make(map[string]bool)
+	for _, reference := range checked.GetReferenceMap() {
+		if len(reference.GetOverloadId()) == 0 {
+			variables[reference.GetName()] = true
+		}
+	}
+
+	program, err := valueExpressionEnv.Program(ast, cel.Functions(valueExpressionFunctions...))
+	if err != nil {
+		return nil, err
+	}
+	return &ValueExpression{source: source, program: program, variables: variables}, nil
+}
+
+// String returns the source of the expression
+func (e *ValueExpression) String() string {
+	return e.source
+}
+
+// References returns true if the expression uses the variable
+func (e *ValueExpression) References(variable string) bool {
+	return e.variables[variable]
+}
+
+// Evaluate returns the result of the expression for the values of the variables
+func (e *ValueExpression) Evaluate(variables map[string]float64) (float64, error) {
+	activation := make(map[string]interface{}, len(variables))
+	for name, value := range variables {
+		activation[name] = value
+	}
+	value, _, err := e.program.Eval(activation)
+	if err != nil {
+		return 0, err
+	}
+
+	var result float64
+	switch value := value.(type) {
+	case types.Double:
+		result = float64(value)
+	case types.Int:
+		result = float64(value)
+	case types.Uint:
+		result = float64(value)
+	default:
+		return 0, fmt.Errorf("the expression %q isn't a number", e.source)
+	}
+	if math.IsNaN(result) || math.IsInf(result, 0) {
+		return 0, fmt.Errorf("the expression %q isn't a finite number", e.source)
+	}
+	return result, nil
+}
@@ -0,0 +1,74 @@
+package scalers
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/metrics/pkg/apis/external_metrics"
+)
+
+// ReplicasFunc returns the current number of replicas of the scale target
+type ReplicasFunc func(ctx context.Context) (int64, error)
+
+// valueExpressionScaler applies an expression to the metric values of the wrapped scaler before they are reported to the HPA
+type valueExpressionScaler struct {
+	Scaler
+	expression *ValueExpression
+	replicas   ReplicasFunc
+}
+
+// valueExpressionPushScaler keeps the push scalers able to push once their values are post-processed
+type valueExpressionPushScaler struct {
+	*valueExpressionScaler
+	pushScaler PushScaler
+}
+
+// NewValueExpressionScaler wraps the scaler so its metric values are the result of the expression, replicas is only called
+// if the expression references the replicas and can be nil if they aren't available
+func NewValueExpressionScaler(scaler Scaler, expression *ValueExpression, replicas ReplicasFunc) (Scaler, error) {
+	if expression.References(ReplicasVariable) && replicas == nil {
+		return nil, fmt.Errorf("the %s of %s isn't available for this scale target", ReplicasVariable, ValueExpressionMetadata)
+	}
+	wrapped := &valueExpressionScaler{
+		Scaler:     scaler,
+		expression: expression,
+		replicas:   replicas,
+	}
+	if pushScaler, ok := scaler.(PushScaler); ok {
+		return &valueExpressionPushScaler{valueExpressionScaler: wrapped, pushScaler: pushScaler}, nil
+	}
+	return wrapped, nil
+}
+
+func (s *valueExpressionScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
+	metrics, err := s.Scaler.GetMetrics(ctx, metricName, metricSelector)
+	if err != nil {
+		return metrics, err
+	}
+
+	variables := make(map[string]float64, 2)
+	if s.expression.References(ReplicasVariable) {
+		replicas, err := s.replicas(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error getting the replicas of %s: %s", ValueExpressionMetadata, err)
+		}
+		variables[ReplicasVariable] = float64(replicas)
+	}
+
+	for i, metric := range metrics {
+		variables[ValueVariable] = float64(metric.Value.MilliValue()) / 1000
+		result, err := s.expression.Evaluate(variables)
+		if err != nil {
+			return nil, fmt.Errorf("error evaluating %s: %s", ValueExpressionMetadata, err)
+		}
+		metrics[i].Value = *resource.NewMilliQuantity(int64(math.Round(result*1000)), resource.DecimalSI)
+	}
+	return metrics, nil
+}
+
+func (s *valueExpressionPushScaler) Run(ctx context.Context, active chan<- bool) {
+	s.pushScaler.Run(ctx, active)
+}
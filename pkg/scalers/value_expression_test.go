@@ -0,0 +1,131 @@
+package scalers
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	v2beta2 "k8s.io/api/autoscaling/v2beta2"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/metrics/pkg/apis/external_metrics"
+)
+
+type valueExpressionTestData struct {
+	expression string
+	expected   float64
+	isError    bool
+}
+
+var valueExpressionTestDataset = []valueExpressionTestData{
+	{"value", 50, false},
+	{"value / 100.0 * replicas", 1.5, false},
+	{"1 + 2 * 3 - 4 / 2", 5, false},
+	{"(1 + 2) * 3", 9, false},
+	{"-value + 60.0", 10, false},
+	{"int(value) % 7", 1, false},
+	{"clamp(value, 0.0, 20.0)", 20, false},
+	{"min(min(value, 10.0), replicas)", 3, false},
+	{"max(value, 100.0)", 100, false},
+	{"ceil(1.2) + floor(1.8) + round(1.5) + abs(-1.0)", 6, false},
+	{"value > 40.0 && replicas < 5.0 ? 1 : 0", 1, false},
+	{"value < 40.0 || !true ? 1 : 0", 0, false},
+	{"value == 50.0 ? (replicas == 3.0 ? 7 : 8) : 9", 7, false},
+	// errors
+	{"", 0, true},
+	{"value /", 0, true},
+	{"(value", 0, true},
+	{"value * 2", 0, true},
+	{"queueLength * 2.0", 0, true},
+	{"sqrt(value)", 0, true},
+	{"clamp(value, 1.0)", 0, true},
+	{"value > 40.0", 0, true},
+	{"'value'", 0, true},
+	{"value / 0.0", 0, true},
+	{"int(value) % 0", 0, true},
+}
+
+func TestValueExpression(t *testing.T) {
+	variables := map[string]float64{ValueVariable: 50, ReplicasVariable: 3}
+	for _, testData := range valueExpressionTestDataset {
+		expression, err := CompileValueExpression(testData.expression)
+		var result float64
+		if err == nil {
+			result, err = expression.Evaluate(variables)
+		}
+		if err != nil && !testData.isError {
+			t.Errorf("Expected success for %q but got error %s", testData.expression, err)
+		}
+		if testData.isError && err == nil {
+			t.Errorf("Expected error for %q but got success", testData.expression)
+		}
+		if err == nil && result != testData.expected {
+			t.Errorf("Expected %v for %q, got %v", testData.expected, testData.expression, result)
+		}
+	}
+}
+
+func TestParseValueExpression(t *testing.T) {
+	if expression, err := ParseValueExpression(map[string]string{}); expression != nil || err != nil {
+		t.Errorf("Expected no expression, got %v %v", expression, err)
+	}
+	expression, err := ParseValueExpression(map[string]string{"valueExpression": "value * replicas"})
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	if !expression.References(ValueVariable) || !expression.References(ReplicasVariable) {
+		t.Error("Expected the expression to reference value and replicas")
+	}
+	if _, err := ParseValueExpression(map[string]string{"valueExpression": "value *"}); err == nil {
+		t.Error("Expected an error for a malformed expression")
+	}
+}
+
+type fixedValueScaler struct {
+	countingScaler
+	values []int64
+}
+
+func (s *fixedValueScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
+	metrics := make([]external_metrics.ExternalMetricValue, len(s.values))
+	for i, value := range s.values {
+		metrics[i] = external_metrics.ExternalMetricValue{MetricName: metricName, Value: *resource.NewQuantity(value, resource.DecimalSI)}
+	}
+	return metrics, nil
+}
+
+func (s *fixedValueScaler) GetMetricSpecForScaling() []v2beta2.MetricSpec {
+	return nil
+}
+
+func TestValueExpressionScaler(t *testing.T) {
+	expression, err := CompileValueExpression("value / 100.0 * replicas")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewValueExpressionScaler(&fixedValueScaler{}, expression, nil); err == nil {
+		t.Error("Expected an error for an expression referencing unavailable replicas")
+	}
+
+	replicas := func(ctx context.Context) (int64, error) { return 4, nil }
+	scaler, err := NewValueExpressionScaler(&fixedValueScaler{values: []int64{250, 5}}, expression, replicas)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name := ScalerName(scaler); name != "fixedValueScaler" {
+		t.Errorf("Expected the name of the wrapped scaler, got %s", name)
+	}
+	metrics, err := scaler.GetMetrics(context.TODO(), "metric", nil)
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	if metrics[0].Value.MilliValue() != 10000 || metrics[1].Value.MilliValue() != 200 {
+		t.Errorf("Expected 10 and 0.2, got %s and %s", metrics[0].Value.String(), metrics[1].Value.String())
+	}
+
+	failing := func(ctx context.Context) (int64, error) { return 0, fmt.Errorf("not found") }
+	scaler, _ = NewValueExpressionScaler(&fixedValueScaler{values: []int64{1}}, expression, failing)
+	if _, err := scaler.GetMetrics(context.TODO(), "metric", nil); err == nil {
+		t.Error("Expected an error if the replicas can't be read")
+	}
+}
@@ -0,0 +1,413 @@
+package scalers
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	v2beta2 "k8s.io/api/autoscaling/v2beta2"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/metrics/pkg/apis/external_metrics"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	prommetrics "github.com/kedacore/keda/pkg/metrics"
+	kedautil "github.com/kedacore/keda/pkg/util"
+)
+
+const (
+	vropsTokenAcquirePath   = "/suite-api/api/auth/token/acquire"
+	vropsResourcesPath      = "/suite-api/api/resources"
+	vropsLatestStatsPath    = "/suite-api/api/resources/%s/stats/latest"
+	vropsTokenScheme        = "vRealizeOpsToken"
+	vropsRequestTimeout     = 10 * time.Second
+	vropsTokenExpiryDelta   = time.Minute
+	vropsTokenSource        = "vrealize-operations"
+	vropsTargetValueName    = "targetValue"
+	defaultVROpsTargetValue = 1
+)
+
+var vropsMetricNameUnsupportedChars = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
+
+type vropsScaler struct {
+	metadata   *vropsMetadata
+	httpClient *http.Client
+
+	// the token and the resource identifier resolved from the name of the resource are kept between the queries
+	lock        sync.Mutex
+	token       string
+	tokenExpiry time.Time
+	resourceID  string
+}
+
+type vropsMetadata struct {
+	serverURL string
+	// the resource is given by its identifier, or by its name and optionally its kind and adapter kind
+	resourceID      string
+	resourceName    string
+	resourceKind    string
+	adapterKind     string
+	statKey         string
+	targetValue     float64
+	activationValue float64
+	username        string
+	password        string
+	authSource      string
+	ca              string
+	unsafeSsl       bool
+	retryPolicy     kedautil.RetryPolicy
+}
+
+type vropsTokenResponse struct {
+	Token string `json:"token"`
+	// Validity is the expiry of the token in milliseconds since the epoch
+	Validity int64 `json:"validity"`
+}
+
+type vropsResourcesResponse struct {
+	ResourceList []struct {
+		Identifier  string `json:"identifier"`
+		ResourceKey struct {
+			Name            string `json:"name"`
+			ResourceKindKey string `json:"resourceKindKey"`
+			AdapterKindKey  string `json:"adapterKindKey"`
+		} `json:"resourceKey"`
+	} `json:"resourceList"`
+}
+
+type vropsLatestStatsResponse struct {
+	Values []struct {
+		ResourceID string `json:"resourceId"`
+		StatList   struct {
+			Stat []struct {
+				StatKey struct {
+					Key string `json:"key"`
+				} `json:"statKey"`
+				Timestamps []int64   `json:"timestamps"`
+				Data       []float64 `json:"data"`
+			} `json:"stat"`
+		} `json:"stat-list"`
+	} `json:"values"`
+}
+
+var vropsLog = logf.Log.WithName("vrealize_operations_scaler")
+
+// NewVROpsScaler creates a new scaler for a metric of a resource of vRealize Operations
+func NewVROpsScaler(resolvedEnv, metadata, authParams map[string]string) (Scaler, error) {
+	meta, err := parseVROpsMetadata(resolvedEnv, metadata, authParams)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing vrealize operations metadata: %s", err)
+	}
+
+	tlsConfig := &tls.Config{InsecureSkipVerify: meta.unsafeSsl}
+	if meta.ca != "" {
+		caCertPool := x509.NewCertPool()
+		if !caCertPool.AppendCertsFromPEM([]byte(meta.ca)) {
+			return nil, fmt.Errorf("error parsing vrealize operations metadata: ca is not a PEM certificate")
+		}
+		tlsConfig.RootCAs = caCertPool
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+
+	return &vropsScaler{
+		metadata:   meta,
+		httpClient: &http.Client{Timeout: vropsRequestTimeout, Transport: transport},
+		resourceID: meta.resourceID,
+	}, nil
+}
+
+func parseVROpsMetadata(resolvedEnv, metadata, authParams map[string]string) (*vropsMetadata, error) {
+	meta := vropsMetadata{
+		targetValue: defaultVROpsTargetValue,
+	}
+
+	if val, ok := metadata["serverURL"]; ok && val != "" {
+		meta.serverURL = strings.TrimSuffix(val, "/")
+	} else if val, ok := metadata["serverURLFromEnv"]; ok && val != "" {
+		meta.serverURL = strings.TrimSuffix(resolvedEnv[val], "/")
+	}
+	if meta.serverURL == "" {
+		return nil, fmt.Errorf("no serverURL given")
+	}
+
+	meta.resourceID = metadata["resourceId"]
+	meta.resourceName = metadata["resourceName"]
+	switch {
+	case meta.resourceID != "" && meta.resourceName != "":
+		return nil, fmt.Errorf("only one of resourceId or resourceName can be given")
+	case meta.resourceID == "" && meta.resourceName == "":
+		return nil, fmt.Errorf("no resourceId or resourceName given")
+	}
+	meta.resourceKind = metadata["resourceKind"]
+	meta.adapterKind = metadata["adapterKind"]
+
+	if val, ok := metadata["statKey"]; ok && val != "" {
+		meta.statKey = val
+	} else {
+		return nil, fmt.Errorf("no statKey given")
+	}
+
+	if val, ok := metadata[vropsTargetValueName]; ok && val != "" {
+		targetValue, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			return nil, fmt.Errorf("can't parse %s: %s", vropsTargetValueName, err)
+		}
+		meta.targetValue = targetValue
+	}
+	if val, ok := metadata["activationValue"]; ok && val != "" {
+		activationValue, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			return nil, fmt.Errorf("can't parse activationValue: %s", err)
+		}
+		meta.activationValue = activationValue
+	}
+
+	if val, ok := authParams["username"]; ok && val != "" {
+		meta.username = val
+	} else {
+		return nil, fmt.Errorf("no username given")
+	}
+	if val, ok := authParams["password"]; ok && val != "" {
+		meta.password = val
+	} else if val, ok := metadata["passwordFromEnv"]; ok && val != "" {
+		meta.password = resolvedEnv[val]
+	}
+	if meta.password == "" {
+		return nil, fmt.Errorf("no password given")
+	}
+	// the authSource is the name of the LDAP or vCenter source of the user, the local users have none
+	meta.authSource = authParams["authSource"]
+	meta.ca = authParams["ca"]
+
+	if val, ok := metadata["unsafeSsl"]; ok && val != "" {
+		unsafeSsl, err := strconv.ParseBool(val)
+		if err != nil {
+			return nil, fmt.Errorf("can't parse unsafeSsl: %s", err)
+		}
+		meta.unsafeSsl = unsafeSsl
+	}
+
+	retryPolicy, err := parseHTTPRetryPolicy(metadata)
+	if err != nil {
+		return nil, err
+	}
+	meta.retryPolicy = retryPolicy
+
+	return &meta, nil
+}
+
+// IsActive returns true if the metric is above the activation value
+func (s *vropsScaler) IsActive(ctx context.Context) (bool, error) {
+	value, err := s.getStatValue(ctx)
+	if err != nil {
+		vropsLog.Error(err, "error getting vrealize operations metric", "statKey", s.metadata.statKey)
+		return false, err
+	}
+
+	return value > s.metadata.activationValue, nil
+}
+
+// Close does nothing in case of vropsScaler
+func (s *vropsScaler) Close() error {
+	return nil
+}
+
+// getToken returns the token of the user, a new one is acquired shortly before the expiry of the current one
+func (s *vropsScaler) getToken(ctx context.Context) (string, error) {
+	if s.token != "" && time.Now().Add(vropsTokenExpiryDelta).Before(s.tokenExpiry) {
+		return s.token, nil
+	}
+
+	credentials := map[string]string{
+		"username": s.metadata.username,
+		"password": s.metadata.password,
+	}
+	if s.metadata.authSource != "" {
+		credentials["authSource"] = s.metadata.authSource
+	}
+	body, err := json.Marshal(credentials)
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, s.metadata.serverURL+vropsTokenAcquirePath, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	tokenResponse := vropsTokenResponse{}
+	err = s.doJSON(ctx, req, &tokenResponse)
+	if err == nil && tokenResponse.Token == "" {
+		err = fmt.Errorf("no token returned")
+	}
+	prommetrics.RecordTokenRefresh(vropsTokenSource, prommetrics.TokenRefreshReasonOnDemand, err)
+	if err != nil {
+		return "", fmt.Errorf("error acquiring vrealize operations token: %s", err)
+	}
+
+	s.token = tokenResponse.Token
+	s.tokenExpiry = time.Unix(0, tokenResponse.Validity*int64(time.Millisecond))
+	return s.token, nil
+}
+
+// getResourceID returns the identifier of the resource, resolved once from the resource name
+func (s *vropsScaler) getResourceID(ctx context.Context, token string) (string, error) {
+	if s.resourceID != "" {
+		return s.resourceID, nil
+	}
+
+	query := url.Values{}
+	query.Set("name", s.metadata.resourceName)
+	if s.metadata.resourceKind != "" {
+		query.Set("resourceKind", s.metadata.resourceKind)
+	}
+	if s.metadata.adapterKind != "" {
+		query.Set("adapterKind", s.metadata.adapterKind)
+	}
+	req, err := s.newAuthenticatedRequest(fmt.Sprintf("%s%s?%s", s.metadata.serverURL, vropsResourcesPath, query.Encode()), token)
+	if err != nil {
+		return "", err
+	}
+
+	resources := vropsResourcesResponse{}
+	if err := s.doJSON(ctx, req, &resources); err != nil {
+		return "", err
+	}
+
+	// the name of the query matches the resources containing it, only the resources with that exact name are kept
+	var ids []string
+	for _, r := range resources.ResourceList {
+		if r.ResourceKey.Name == s.metadata.resourceName {
+			ids = append(ids, r.Identifier)
+		}
+	}
+	switch len(ids) {
+	case 0:
+		return "", fmt.Errorf("no resource named %s found", s.metadata.resourceName)
+	case 1:
+		s.resourceID = ids[0]
+		return s.resourceID, nil
+	default:
+		return "", fmt.Errorf("%d resources named %s found, resourceKind or resourceId has to be given", len(ids), s.metadata.resourceName)
+	}
+}
+
+// getStatValue returns the latest value of the stat of the resource
+func (s *vropsScaler) getStatValue(ctx context.Context) (float64, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	token, err := s.getToken(ctx)
+	if err != nil {
+		return 0, err
+	}
+	resourceID, err := s.getResourceID(ctx, token)
+	if err != nil {
+		return 0, err
+	}
+
+	query := url.Values{}
+	query.Set("statKey", s.metadata.statKey)
+	statsURL := fmt.Sprintf("%s%s?%s", s.metadata.serverURL, fmt.Sprintf(vropsLatestStatsPath, url.PathEscape(resourceID)), query.Encode())
+	req, err := s.newAuthenticatedRequest(statsURL, token)
+	if err != nil {
+		return 0, err
+	}
+
+	stats := vropsLatestStatsResponse{}
+	if err := s.doJSON(ctx, req, &stats); err != nil {
+		return 0, err
+	}
+
+	for _, value := range stats.Values {
+		for _, stat := range value.StatList.Stat {
+			if stat.StatKey.Key == s.metadata.statKey && len(stat.Data) > 0 {
+				return stat.Data[len(stat.Data)-1], nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("no value of %s found for resource %s", s.metadata.statKey, resourceID)
+}
+
+func (s *vropsScaler) newAuthenticatedRequest(requestURL, token string) (*http.Request, error) {
+	req, err := http.NewRequest(http.MethodGet, requestURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("%s %s", vropsTokenScheme, token))
+	return req, nil
+}
+
+// doJSON sends the request and decodes its JSON response into the target, the token is dropped if it is rejected
+func (s *vropsScaler) doJSON(ctx context.Context, req *http.Request, target interface{}) error {
+	req = req.WithContext(ctx)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := kedautil.DoWithRetry(s.httpClient, req, s.metadata.retryPolicy)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		s.token = ""
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("vrealize operations %s returned %d", req.URL.Path, resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(target); err != nil {
+		return fmt.Errorf("error decoding vrealize operations response: %s", err)
+	}
+	return nil
+}
+
+// GetMetricSpecForScaling returns the MetricSpec for the Horizontal Pod Autoscaler
+func (s *vropsScaler) GetMetricSpecForScaling() []v2beta2.MetricSpec {
+	targetValue := resource.NewMilliQuantity(int64(math.Round(s.metadata.targetValue*1000)), resource.DecimalSI)
+	resourceName := s.metadata.resourceName
+	if resourceName == "" {
+		resourceName = s.metadata.resourceID
+	}
+	name := fmt.Sprintf("%s-%s-%s", "vrops", resourceName, s.metadata.statKey)
+	externalMetric := &v2beta2.ExternalMetricSource{
+		Metric: v2beta2.MetricIdentifier{
+			Name: vropsMetricNameUnsupportedChars.ReplaceAllString(kedautil.NormalizeString(name), "-"),
+		},
+		Target: v2beta2.MetricTarget{
+			Type:         v2beta2.AverageValueMetricType,
+			AverageValue: targetValue,
+		},
+	}
+	metricSpec := v2beta2.MetricSpec{External: externalMetric, Type: externalMetricType}
+	return []v2beta2.MetricSpec{metricSpec}
+}
+
+// GetMetrics returns the latest value of the stat of the resource
+func (s *vropsScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
+	value, err := s.getStatValue(ctx)
+	if err != nil {
+		return []external_metrics.ExternalMetricValue{}, fmt.Errorf("error getting vrealize operations metric: %s", err)
+	}
+
+	metric := external_metrics.ExternalMetricValue{
+		MetricName: metricName,
+		Value:      *resource.NewMilliQuantity(int64(math.Round(value*1000)), resource.DecimalSI),
+		Timestamp:  metav1.Now(),
+	}
+
+	return append([]external_metrics.ExternalMetricValue{}, metric), nil
+}
@@ -0,0 +1,190 @@
+package scalers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type parseVROpsMetadataTestData struct {
+	metadata   map[string]string
+	authParams map[string]string
+	isError    bool
+}
+
+type vropsMetricIdentifier struct {
+	metadataTestData *parseVROpsMetadataTestData
+	name             string
+}
+
+var testVROpsAuthParams = map[string]string{"username": "keda", "password": "secret"}
+
+var testVROpsMetadata = []parseVROpsMetadataTestData{
+	// nothing passed
+	{map[string]string{}, testVROpsAuthParams, true},
+	// properly formed resource name
+	{map[string]string{"serverURL": "https://vrops.example.com/", "resourceName": "datastore-01", "resourceKind": "Datastore", "statKey": "devices|totalLatency_average", "targetValue": "2.5"}, testVROpsAuthParams, false},
+	// properly formed resource id
+	{map[string]string{"serverURL": "https://vrops.example.com", "resourceId": "b2a7a1b8-0a25-4e9d-9f6c-2d3b8c2f1e10", "statKey": "virtualDisk|commandsAveraged_average", "unsafeSsl": "true"}, testVROpsAuthParams, false},
+	// both resource id and name
+	{map[string]string{"serverURL": "https://vrops.example.com", "resourceId": "id", "resourceName": "datastore-01", "statKey": "devices|totalLatency_average"}, testVROpsAuthParams, true},
+	// missing statKey
+	{map[string]string{"serverURL": "https://vrops.example.com", "resourceName": "datastore-01"}, testVROpsAuthParams, true},
+	// missing password
+	{map[string]string{"serverURL": "https://vrops.example.com", "resourceName": "datastore-01", "statKey": "devices|totalLatency_average"}, map[string]string{"username": "keda"}, true},
+	// password from env
+	{map[string]string{"serverURL": "https://vrops.example.com", "resourceName": "datastore-01", "statKey": "devices|totalLatency_average", "passwordFromEnv": "VROPS_PASSWORD"}, map[string]string{"username": "keda"}, false},
+	// malformed targetValue
+	{map[string]string{"serverURL": "https://vrops.example.com", "resourceName": "datastore-01", "statKey": "devices|totalLatency_average", "targetValue": "high"}, testVROpsAuthParams, true},
+	// malformed unsafeSsl
+	{map[string]string{"serverURL": "https://vrops.example.com", "resourceName": "datastore-01", "statKey": "devices|totalLatency_average", "unsafeSsl": "maybe"}, testVROpsAuthParams, true},
+}
+
+var vropsMetricIdentifiers = []vropsMetricIdentifier{
+	{&testVROpsMetadata[1], "vrops-datastore-01-devices-totalLatency_average"},
+	{&testVROpsMetadata[2], "vrops-b2a7a1b8-0a25-4e9d-9f6c-2d3b8c2f1e10-virtualDisk-commandsAveraged_average"},
+}
+
+func TestVROpsParseMetadata(t *testing.T) {
+	for _, testData := range testVROpsMetadata {
+		_, err := parseVROpsMetadata(map[string]string{"VROPS_PASSWORD": "secret"}, testData.metadata, testData.authParams)
+		if err != nil && !testData.isError {
+			t.Error("Expected success but got error", err)
+		}
+		if testData.isError && err == nil {
+			t.Error("Expected error but got success")
+		}
+	}
+}
+
+func TestVROpsGetMetricSpecForScaling(t *testing.T) {
+	for _, testData := range vropsMetricIdentifiers {
+		meta, err := parseVROpsMetadata(nil, testData.metadataTestData.metadata, testData.metadataTestData.authParams)
+		if err != nil {
+			t.Fatal("Could not parse metadata:", err)
+		}
+		mockVROpsScaler := vropsScaler{metadata: meta}
+
+		metricSpec := mockVROpsScaler.GetMetricSpecForScaling()
+		metricName := metricSpec[0].External.Metric.Name
+		if metricName != testData.name {
+			t.Error("Wrong External metric source name:", metricName)
+		}
+	}
+}
+
+func newTestVROpsServer(t *testing.T, tokenAcquires *int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == vropsTokenAcquirePath {
+			credentials := map[string]string{}
+			_ = json.NewDecoder(r.Body).Decode(&credentials)
+			assert.Equal(t, "keda", credentials["username"])
+			assert.Equal(t, "secret", credentials["password"])
+			*tokenAcquires++
+			validity := time.Now().Add(6*time.Hour).UnixNano() / int64(time.Millisecond)
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"token": "token-1", "validity": validity})
+			return
+		}
+
+		if r.Header.Get("Authorization") != "vRealizeOpsToken token-1" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case vropsResourcesPath:
+			assert.Equal(t, "Datastore", r.URL.Query().Get("resourceKind"))
+			_, _ = w.Write([]byte(`{"resourceList":[
+				{"identifier":"ds-1","resourceKey":{"name":"datastore-01","resourceKindKey":"Datastore"}},
+				{"identifier":"ds-10","resourceKey":{"name":"datastore-010","resourceKindKey":"Datastore"}},
+				{"identifier":"ds-2","resourceKey":{"name":"datastore-02","resourceKindKey":"Datastore"}},
+				{"identifier":"ds-2-copy","resourceKey":{"name":"datastore-02","resourceKindKey":"Datastore"}}
+			]}`))
+		case "/suite-api/api/resources/ds-1/stats/latest":
+			if r.URL.Query().Get("statKey") != "devices|totalLatency_average" {
+				_, _ = w.Write([]byte(`{"values":[]}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"values":[{"resourceId":"ds-1","stat-list":{"stat":[
+				{"timestamps":[1609459200000],"statKey":{"key":"devices|totalLatency_average"},"data":[7.25]}
+			]}}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestVROpsGetStatValue(t *testing.T) {
+	tokenAcquires := 0
+	server := newTestVROpsServer(t, &tokenAcquires)
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		metadata map[string]string
+		value    float64
+		isError  bool
+	}{
+		{"resource name", map[string]string{"resourceName": "datastore-01", "resourceKind": "Datastore"}, 7.25, false},
+		{"resource id", map[string]string{"resourceId": "ds-1"}, 7.25, false},
+		{"unknown resource name", map[string]string{"resourceName": "datastore-03", "resourceKind": "Datastore"}, 0, true},
+		{"ambiguous resource name", map[string]string{"resourceName": "datastore-02", "resourceKind": "Datastore"}, 0, true},
+		{"stat without value", map[string]string{"resourceId": "ds-1", "statKey": "devices|numberReadAveraged_average"}, 0, true},
+	}
+
+	for _, test := range tests {
+		metadata := map[string]string{"serverURL": server.URL, "statKey": "devices|totalLatency_average", "httpRetryMaxAttempts": "1"}
+		for k, v := range test.metadata {
+			metadata[k] = v
+		}
+		s, err := NewVROpsScaler(nil, metadata, testVROpsAuthParams)
+		if err != nil {
+			t.Fatal("Could not create scaler:", err)
+		}
+
+		value, err := s.(*vropsScaler).getStatValue(context.TODO())
+		if test.isError {
+			assert.Error(t, err, test.name)
+			continue
+		}
+		assert.NoError(t, err, test.name)
+		assert.Equal(t, test.value, value, test.name)
+	}
+}
+
+func TestVROpsTokenRenewal(t *testing.T) {
+	tokenAcquires := 0
+	server := newTestVROpsServer(t, &tokenAcquires)
+	defer server.Close()
+
+	s, err := NewVROpsScaler(nil, map[string]string{"serverURL": server.URL, "resourceId": "ds-1", "statKey": "devices|totalLatency_average"}, testVROpsAuthParams)
+	if err != nil {
+		t.Fatal("Could not create scaler:", err)
+	}
+	scaler := s.(*vropsScaler)
+
+	for i := 0; i < 2; i++ {
+		_, err := scaler.getStatValue(context.TODO())
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, 1, tokenAcquires, "the token is reused until its expiry")
+
+	// a rejected token is dropped and acquired again on the next query
+	scaler.token = "revoked"
+	_, err = scaler.getStatValue(context.TODO())
+	assert.Error(t, err)
+	assert.Equal(t, "", scaler.token)
+	_, err = scaler.getStatValue(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, tokenAcquires)
+
+	// the token is renewed shortly before its expiry
+	scaler.tokenExpiry = time.Now().Add(vropsTokenExpiryDelta / 2)
+	_, err = scaler.getStatValue(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, 3, tokenAcquires)
+}
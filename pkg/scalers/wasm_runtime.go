@@ -0,0 +1,70 @@
+package scalers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/api"
+)
+
+// wazeroRuntime executes the WebAssembly modules with wazero, a runtime written in Go without cgo.
+// The modules don't get any host function, a module importing one can't be instantiated.
+type wazeroRuntime struct {
+	// cache shares the modules compiled by the runtimes of the instances
+	cache wazero.CompilationCache
+}
+
+type wazeroInstance struct {
+	runtime wazero.Runtime
+	module  api.Module
+}
+
+func newWazeroRuntime() *wazeroRuntime {
+	return &wazeroRuntime{cache: wazero.NewCompilationCache()}
+}
+
+// Instantiate compiles and instantiates the module in a runtime of its own, the memory limit is a setting of the runtime
+func (r *wazeroRuntime) Instantiate(ctx context.Context, module []byte, limits WasmLimits) (WasmInstance, error) {
+	config := wazero.NewRuntimeConfig().
+		WithCompilationCache(r.cache).
+		WithMemoryLimitPages(limits.MaxMemoryPages).
+		// the functions looping forever are stopped once the timeout of the call elapsed
+		WithCloseOnContextDone(true)
+	runtime := wazero.NewRuntimeWithConfig(ctx, config)
+
+	compiled, err := runtime.CompileModule(ctx, module)
+	if err != nil {
+		_ = runtime.Close(ctx)
+		return nil, err
+	}
+	instance, err := runtime.InstantiateModule(ctx, compiled, wazero.NewModuleConfig())
+	if err != nil {
+		_ = runtime.Close(ctx)
+		return nil, err
+	}
+	return &wazeroInstance{runtime: runtime, module: instance}, nil
+}
+
+// Call calls the exported function, it must take no parameter and return an i64
+func (i *wazeroInstance) Call(ctx context.Context, function string) (int64, error) {
+	fn := i.module.ExportedFunction(function)
+	if fn == nil {
+		return 0, fmt.Errorf("function %s not exported", function)
+	}
+	definition := fn.Definition()
+	if len(definition.ParamTypes()) != 0 || len(definition.ResultTypes()) != 1 || definition.ResultTypes()[0] != api.ValueTypeI64 {
+		return 0, fmt.Errorf("function %s must take no parameter and return an i64", function)
+	}
+
+	results, err := fn.Call(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return int64(results[0]), nil
+}
+
+// Close releases the module and its runtime, the compiled module stays in the cache
+func (i *wazeroInstance) Close() error {
+	return i.runtime.Close(context.Background())
+}
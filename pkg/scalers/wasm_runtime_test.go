@@ -0,0 +1,90 @@
+package scalers
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// newTestWasmModule assembles a module exporting get_metric and is_active, two functions without parameter returning an i64,
+// with the code of their bodies. The module declares a memory of memoryPages pages
+func newTestWasmModule(getMetric, isActive []byte, memoryPages byte) []byte {
+	section := func(id byte, content ...byte) []byte {
+		return append([]byte{id, byte(len(content))}, content...)
+	}
+	body := func(code []byte) []byte {
+		// no local, the code and end
+		return append(append([]byte{byte(len(code) + 2), 0x00}, code...), 0x0b)
+	}
+
+	module := []byte{0x00, 'a', 's', 'm', 0x01, 0x00, 0x00, 0x00}
+	module = append(module, section(0x01, 0x01, 0x60, 0x00, 0x01, 0x7e)...)
+	module = append(module, section(0x03, 0x02, 0x00, 0x00)...)
+	module = append(module, section(0x05, 0x01, 0x00, memoryPages)...)
+	exports := []byte{0x02}
+	for i, name := range []string{WasmMetricFunction, WasmActiveFunction} {
+		exports = append(append(append(exports, byte(len(name))), name...), 0x00, byte(i))
+	}
+	module = append(module, section(0x07, exports...)...)
+	code := append(append([]byte{0x02}, body(getMetric)...), body(isActive)...)
+	return append(module, section(0x0a, code...)...)
+}
+
+var (
+	// i64.const 42
+	wasmReturn42 = []byte{0x42, 0x2a}
+	// i64.const 1
+	wasmReturn1 = []byte{0x42, 0x01}
+	// loop br 0 end i64.const 0
+	wasmLoopForever = []byte{0x03, 0x40, 0x0c, 0x00, 0x0b, 0x42, 0x00}
+)
+
+func TestWazeroRuntime(t *testing.T) {
+	runtime := newWazeroRuntime()
+	ctx := context.Background()
+
+	instance, err := runtime.Instantiate(ctx, newTestWasmModule(wasmReturn42, wasmReturn1, 1), WasmLimits{MaxMemoryPages: 1})
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	defer instance.Close()
+	if value, err := instance.Call(ctx, WasmMetricFunction); err != nil || value != 42 {
+		t.Errorf("Expected 42, got %d %v", value, err)
+	}
+	if value, err := instance.Call(ctx, WasmActiveFunction); err != nil || value != 1 {
+		t.Errorf("Expected 1, got %d %v", value, err)
+	}
+	if _, err := instance.Call(ctx, "run"); err == nil {
+		t.Error("Expected an error for a function not exported")
+	}
+
+	// the module needs more memory than the limit
+	if _, err := runtime.Instantiate(ctx, newTestWasmModule(wasmReturn42, wasmReturn1, 2), WasmLimits{MaxMemoryPages: 1}); err == nil {
+		t.Error("Expected an error for a module exceeding the memory limit")
+	}
+}
+
+func TestWazeroRuntimeTimeout(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	instance, err := newWazeroRuntime().Instantiate(ctx, newTestWasmModule(wasmLoopForever, wasmReturn1, 1), WasmLimits{MaxMemoryPages: 1})
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	defer instance.Close()
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := instance.Call(ctx, WasmMetricFunction)
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Error("Expected an error for a function stopped by the timeout")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the function looping forever to be stopped by the timeout")
+	}
+}
@@ -0,0 +1,417 @@
+package scalers
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	v2beta2 "k8s.io/api/autoscaling/v2beta2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/metrics/pkg/apis/external_metrics"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	kedautil "github.com/kedacore/keda/pkg/util"
+)
+
+const (
+	wasmConfigMapName    = "configMapName"
+	wasmConfigMapKey     = "configMapKey"
+	wasmOCIRef           = "ociRef"
+	wasmTargetValue      = "targetValue"
+	wasmMaxMemoryPages   = "maxMemoryPages"
+	wasmTimeout          = "timeout"
+	wasmMaxModuleSize    = "maxModuleSize"
+	wasmRegistryUsername = "registryUsername"
+	wasmRegistryPassword = "registryPassword"
+	wasmRegistryToken    = "registryToken"
+
+	defaultWasmConfigMapKey   = "module.wasm"
+	defaultWasmTargetValue    = 10
+	defaultWasmMaxMemoryPages = 16
+	defaultWasmTimeout        = time.Second
+	defaultWasmMaxModuleSize  = 10 * 1024 * 1024
+
+	// WasmMetricFunction is the function the module exports to return the metric value, it takes no parameter and returns an i64
+	WasmMetricFunction = "get_metric"
+	// WasmActiveFunction is the function the module exports to tell if the scale target is active, it takes no parameter and returns
+	// an i64 different from 0 if active
+	WasmActiveFunction = "is_active"
+
+	ociManifestMediaType = "application/vnd.oci.image.manifest.v1+json"
+)
+
+// wasmMagic is the header of the binary format of the WebAssembly modules
+var wasmMagic = []byte{0x00, 'a', 's', 'm'}
+
+// WasmLimits bound the resources of an instance of a WebAssembly module
+type WasmLimits struct {
+	// MaxMemoryPages is the maximum linear memory of the instance in pages of 64KiB
+	MaxMemoryPages uint32
+}
+
+// WasmRuntime executes the WebAssembly modules of the wasm triggers, the module has no access to the host except its return values.
+// KEDA executes the modules with wazero.
+type WasmRuntime interface {
+	// Instantiate compiles and instantiates the module within the limits, the context bounds the instantiation and the calls
+	Instantiate(ctx context.Context, module []byte, limits WasmLimits) (WasmInstance, error)
+}
+
+// WasmInstance is an instance of a WebAssembly module
+type WasmInstance interface {
+	// Call calls the exported function without parameters and returns its i64 result, it must return when the context is done
+	Call(ctx context.Context, function string) (int64, error)
+	Close() error
+}
+
+// wasmRuntime is the runtime of the wasm triggers of the process, the modules compiled are shared by its instances
+var wasmRuntime WasmRuntime = newWazeroRuntime()
+
+type wasmScaler struct {
+	metadata   *wasmMetadata
+	kubeClient client.Client
+	httpClient *http.Client
+	runtime    WasmRuntime
+	module     []byte
+}
+
+type wasmMetadata struct {
+	namespace        string
+	configMapName    string
+	configMapKey     string
+	ociRef           *ociReference
+	targetValue      int64
+	limits           WasmLimits
+	timeout          time.Duration
+	maxModuleSize    int64
+	registryUsername string
+	registryPassword string
+	registryToken    string
+}
+
+// ociReference is a reference to an artifact of an OCI registry, <registry>/<repository>:<tag> or <registry>/<repository>@<digest>
+type ociReference struct {
+	registry   string
+	repository string
+	reference  string
+}
+
+var wasmLog = logf.Log.WithName("wasm_scaler")
+
+// NewWasmScaler creates a new scaler executing a user provided WebAssembly module, the trigger type is experimental
+func NewWasmScaler(kubeClient client.Client, namespace string, metadata, authParams map[string]string) (Scaler, error) {
+	meta, err := parseWasmMetadata(namespace, metadata, authParams)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing wasm metadata: %s", err)
+	}
+
+	return &wasmScaler{
+		metadata:   meta,
+		kubeClient: kubeClient,
+		httpClient: &http.Client{Timeout: meta.timeout},
+		runtime:    wasmRuntime,
+	}, nil
+}
+
+func parseWasmMetadata(namespace string, metadata, authParams map[string]string) (*wasmMetadata, error) {
+	meta := wasmMetadata{
+		namespace:     namespace,
+		configMapKey:  defaultWasmConfigMapKey,
+		targetValue:   defaultWasmTargetValue,
+		limits:        WasmLimits{MaxMemoryPages: defaultWasmMaxMemoryPages},
+		timeout:       defaultWasmTimeout,
+		maxModuleSize: defaultWasmMaxModuleSize,
+	}
+
+	configMapName := metadata[wasmConfigMapName]
+	ociRef := metadata[wasmOCIRef]
+	switch {
+	case configMapName != "" && ociRef != "":
+		return nil, fmt.Errorf("only one of %s or %s can be given", wasmConfigMapName, wasmOCIRef)
+	case configMapName != "":
+		meta.configMapName = configMapName
+		if val, ok := metadata[wasmConfigMapKey]; ok && val != "" {
+			meta.configMapKey = val
+		}
+	case ociRef != "":
+		ref, err := parseOCIReference(ociRef)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing %s: %s", wasmOCIRef, err)
+		}
+		meta.ociRef = ref
+	default:
+		return nil, fmt.Errorf("no %s or %s given", wasmConfigMapName, wasmOCIRef)
+	}
+
+	if val, ok := metadata[wasmTargetValue]; ok && val != "" {
+		targetValue, err := strconv.ParseInt(val, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing %s: %s", wasmTargetValue, err)
+		}
+		if targetValue <= 0 {
+			return nil, fmt.Errorf("%s must be greater than 0", wasmTargetValue)
+		}
+		meta.targetValue = targetValue
+	}
+
+	if val, ok := metadata[wasmMaxMemoryPages]; ok && val != "" {
+		pages, err := strconv.ParseUint(val, 10, 16)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing %s: %s", wasmMaxMemoryPages, err)
+		}
+		if pages == 0 {
+			return nil, fmt.Errorf("%s must be greater than 0", wasmMaxMemoryPages)
+		}
+		meta.limits.MaxMemoryPages = uint32(pages)
+	}
+
+	if val, ok := metadata[wasmTimeout]; ok && val != "" {
+		timeout, err := time.ParseDuration(val)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing %s: %s", wasmTimeout, err)
+		}
+		if timeout <= 0 {
+			return nil, fmt.Errorf("%s must be greater than 0", wasmTimeout)
+		}
+		meta.timeout = timeout
+	}
+
+	if val, ok := metadata[wasmMaxModuleSize]; ok && val != "" {
+		size, err := resource.ParseQuantity(val)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing %s: %s", wasmMaxModuleSize, err)
+		}
+		if size.Value() <= 0 {
+			return nil, fmt.Errorf("%s must be greater than 0", wasmMaxModuleSize)
+		}
+		meta.maxModuleSize = size.Value()
+	}
+
+	meta.registryUsername = authParams[wasmRegistryUsername]
+	meta.registryPassword = authParams[wasmRegistryPassword]
+	meta.registryToken = authParams[wasmRegistryToken]
+
+	return &meta, nil
+}
+
+// parseOCIReference parses <registry>/<repository>:<tag> or <registry>/<repository>@<digest>, the tag is latest if not given
+func parseOCIReference(ref string) (*ociReference, error) {
+	slash := strings.Index(ref, "/")
+	if slash <= 0 {
+		return nil, fmt.Errorf("%q must be <registry>/<repository>:<tag>", ref)
+	}
+	result := &ociReference{registry: ref[:slash]}
+	repository := ref[slash+1:]
+
+	if at := strings.Index(repository, "@"); at >= 0 {
+		result.repository, result.reference = repository[:at], repository[at+1:]
+		if !strings.HasPrefix(result.reference, "sha256:") {
+			return nil, fmt.Errorf("unsupported digest %q, must be sha256", result.reference)
+		}
+	} else if colon := strings.LastIndex(repository, ":"); colon >= 0 {
+		result.repository, result.reference = repository[:colon], repository[colon+1:]
+	} else {
+		result.repository, result.reference = repository, "latest"
+	}
+	if result.repository == "" || result.reference == "" {
+		return nil, fmt.Errorf("%q must be <registry>/<repository>:<tag>", ref)
+	}
+	return result, nil
+}
+
+// IsActive returns true if the is_active function of the module returns a value different from 0
+func (s *wasmScaler) IsActive(ctx context.Context) (bool, error) {
+	value, err := s.call(ctx, WasmActiveFunction)
+	if err != nil {
+		wasmLog.Error(err, "error executing wasm module")
+		return false, err
+	}
+	return value != 0, nil
+}
+
+// Close does nothing in case of wasmScaler, the instances of the module are closed after each call
+func (s *wasmScaler) Close() error {
+	return nil
+}
+
+// GetMetricSpecForScaling returns the metric spec for the HPA
+func (s *wasmScaler) GetMetricSpecForScaling() []v2beta2.MetricSpec {
+	targetMetricValue := resource.NewQuantity(s.metadata.targetValue, resource.DecimalSI)
+	externalMetric := &v2beta2.ExternalMetricSource{
+		Metric: v2beta2.MetricIdentifier{
+			Name: kedautil.NormalizeString(fmt.Sprintf("%s-%s", "wasm", s.moduleName())),
+		},
+		Target: v2beta2.MetricTarget{
+			Type:         v2beta2.AverageValueMetricType,
+			AverageValue: targetMetricValue,
+		},
+	}
+	metricSpec := v2beta2.MetricSpec{External: externalMetric, Type: externalMetricType}
+	return []v2beta2.MetricSpec{metricSpec}
+}
+
+// GetMetrics returns the value returned by the get_metric function of the module
+func (s *wasmScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
+	value, err := s.call(ctx, WasmMetricFunction)
+	if err != nil {
+		wasmLog.Error(err, "error executing wasm module")
+		return []external_metrics.ExternalMetricValue{}, err
+	}
+
+	metric := external_metrics.ExternalMetricValue{
+		MetricName: metricName,
+		Value:      *resource.NewQuantity(value, resource.DecimalSI),
+		Timestamp:  metav1.Now(),
+	}
+
+	return append([]external_metrics.ExternalMetricValue{}, metric), nil
+}
+
+func (s *wasmScaler) moduleName() string {
+	if s.metadata.ociRef != nil {
+		return s.metadata.ociRef.repository
+	}
+	return s.metadata.configMapName
+}
+
+// call executes the function of the module in a new instance, the module can't keep a state between two calls
+func (s *wasmScaler) call(ctx context.Context, function string) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.metadata.timeout)
+	defer cancel()
+
+	module, err := s.getModule(ctx)
+	if err != nil {
+		return 0, err
+	}
+	instance, err := s.runtime.Instantiate(ctx, module, s.metadata.limits)
+	if err != nil {
+		return 0, fmt.Errorf("error instantiating wasm module: %s", err)
+	}
+	defer instance.Close()
+
+	value, err := instance.Call(ctx, function)
+	if err != nil {
+		return 0, fmt.Errorf("error calling %s of wasm module: %s", function, err)
+	}
+	return value, nil
+}
+
+// getModule returns the binary of the module, it is read once per scaler
+func (s *wasmScaler) getModule(ctx context.Context) ([]byte, error) {
+	if s.module != nil {
+		return s.module, nil
+	}
+
+	var module []byte
+	var err error
+	if s.metadata.ociRef != nil {
+		module, err = s.pullOCIModule(ctx)
+	} else {
+		module, err = s.readConfigMapModule(ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(module)) > s.metadata.maxModuleSize {
+		return nil, fmt.Errorf("the wasm module of %d bytes exceeds %s of %d bytes", len(module), wasmMaxModuleSize, s.metadata.maxModuleSize)
+	}
+	if !bytes.HasPrefix(module, wasmMagic) {
+		return nil, fmt.Errorf("the wasm module isn't a WebAssembly binary")
+	}
+	s.module = module
+	return module, nil
+}
+
+func (s *wasmScaler) readConfigMapModule(ctx context.Context) ([]byte, error) {
+	configMap := &corev1.ConfigMap{}
+	if err := s.kubeClient.Get(ctx, types.NamespacedName{Namespace: s.metadata.namespace, Name: s.metadata.configMapName}, configMap); err != nil {
+		return nil, fmt.Errorf("error getting ConfigMap %s: %s", s.metadata.configMapName, err)
+	}
+	if module, ok := configMap.BinaryData[s.metadata.configMapKey]; ok {
+		return module, nil
+	}
+	return nil, fmt.Errorf("no binaryData %s in ConfigMap %s", s.metadata.configMapKey, s.metadata.configMapName)
+}
+
+// pullOCIModule downloads the first layer of the OCI artifact with the registry API, the layer is verified against its digest
+func (s *wasmScaler) pullOCIModule(ctx context.Context) ([]byte, error) {
+	ref := s.metadata.ociRef
+	baseURL := fmt.Sprintf("https://%s/v2/%s", ref.registry, ref.repository)
+
+	body, err := s.registryGet(ctx, fmt.Sprintf("%s/manifests/%s", baseURL, ref.reference), ociManifestMediaType, 1024*1024)
+	if err != nil {
+		return nil, fmt.Errorf("error getting manifest of %s: %s", ref.repository, err)
+	}
+	manifest := struct {
+		Layers []struct {
+			Digest string `json:"digest"`
+		} `json:"layers"`
+	}{}
+	if err := json.Unmarshal(body, &manifest); err != nil {
+		return nil, fmt.Errorf("error parsing manifest of %s: %s", ref.repository, err)
+	}
+	if len(manifest.Layers) == 0 {
+		return nil, fmt.Errorf("the manifest of %s has no layer", ref.repository)
+	}
+
+	digest := manifest.Layers[0].Digest
+	if !strings.HasPrefix(digest, "sha256:") {
+		return nil, fmt.Errorf("unsupported digest %q of %s", digest, ref.repository)
+	}
+	module, err := s.registryGet(ctx, fmt.Sprintf("%s/blobs/%s", baseURL, digest), "", s.metadata.maxModuleSize)
+	if err != nil {
+		return nil, fmt.Errorf("error getting wasm module of %s: %s", ref.repository, err)
+	}
+	sum := sha256.Sum256(module)
+	if "sha256:"+hex.EncodeToString(sum[:]) != digest {
+		return nil, fmt.Errorf("the wasm module of %s doesn't match its digest %s", ref.repository, digest)
+	}
+	return module, nil
+}
+
+// registryGet reads at most maxSize bytes of the body of a request to the registry, one more byte is read to detect a larger body
+func (s *wasmScaler) registryGet(ctx context.Context, url, accept string, maxSize int64) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	switch {
+	case s.metadata.registryToken != "":
+		req.Header.Set("Authorization", "Bearer "+s.metadata.registryToken)
+	case s.metadata.registryUsername != "":
+		req.SetBasicAuth(s.metadata.registryUsername, s.metadata.registryPassword)
+	}
+
+	resp, err := kedautil.DoWithRetry(s.httpClient, req, kedautil.DefaultRetryPolicy())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("registry returned status %d", resp.StatusCode)
+	}
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(body)) > maxSize {
+		return nil, fmt.Errorf("the response exceeds %d bytes", maxSize)
+	}
+	return body, nil
+}
@@ -0,0 +1,183 @@
+package scalers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+type parseWasmMetadataTestData struct {
+	metadata map[string]string
+	isError  bool
+}
+
+var testWasmMetadata = []parseWasmMetadataTestData{
+	// nothing passed
+	{map[string]string{}, true},
+	// properly formed ConfigMap
+	{map[string]string{"configMapName": "scaler", "configMapKey": "scaler.wasm", "targetValue": "5", "maxMemoryPages": "4", "timeout": "500ms", "maxModuleSize": "1Mi"}, false},
+	// properly formed OCI reference
+	{map[string]string{"ociRef": "ghcr.io/acme/scaler:v1"}, false},
+	// OCI reference with digest
+	{map[string]string{"ociRef": "ghcr.io/acme/scaler@sha256:0123"}, false},
+	// both ConfigMap and OCI reference
+	{map[string]string{"configMapName": "scaler", "ociRef": "ghcr.io/acme/scaler:v1"}, true},
+	// OCI reference without registry
+	{map[string]string{"ociRef": "scaler:v1"}, true},
+	// OCI reference with unsupported digest
+	{map[string]string{"ociRef": "ghcr.io/acme/scaler@md5:0123"}, true},
+	// targetValue is zero
+	{map[string]string{"configMapName": "scaler", "targetValue": "0"}, true},
+	// maxMemoryPages is zero
+	{map[string]string{"configMapName": "scaler", "maxMemoryPages": "0"}, true},
+	// malformed timeout
+	{map[string]string{"configMapName": "scaler", "timeout": "1"}, true},
+	// malformed maxModuleSize
+	{map[string]string{"configMapName": "scaler", "maxModuleSize": "big"}, true},
+}
+
+func TestWasmParseMetadata(t *testing.T) {
+	for _, testData := range testWasmMetadata {
+		_, err := parseWasmMetadata("default", testData.metadata, map[string]string{})
+		if err != nil && !testData.isError {
+			t.Error("Expected success but got error", err)
+		}
+		if testData.isError && err == nil {
+			t.Errorf("Expected error but got success for %v", testData.metadata)
+		}
+	}
+}
+
+func TestParseOCIReference(t *testing.T) {
+	ref, err := parseOCIReference("localhost:5000/acme/scaler")
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	if ref.registry != "localhost:5000" || ref.repository != "acme/scaler" || ref.reference != "latest" {
+		t.Errorf("Unexpected reference %+v", ref)
+	}
+}
+
+// fakeWasmRuntime returns the results of the functions without executing the module
+type fakeWasmRuntime struct {
+	results map[string]int64
+	limits  WasmLimits
+}
+
+func (r *fakeWasmRuntime) Instantiate(ctx context.Context, module []byte, limits WasmLimits) (WasmInstance, error) {
+	r.limits = limits
+	return r, nil
+}
+
+func (r *fakeWasmRuntime) Call(ctx context.Context, function string) (int64, error) {
+	if result, ok := r.results[function]; ok {
+		return result, nil
+	}
+	return 0, fmt.Errorf("function %s not exported", function)
+}
+
+func (r *fakeWasmRuntime) Close() error {
+	return nil
+}
+
+// setTestWasmRuntime replaces the runtime of the wasm triggers, the returned function restores it
+func setTestWasmRuntime(runtime WasmRuntime) func() {
+	previous := wasmRuntime
+	wasmRuntime = runtime
+	return func() {
+		wasmRuntime = previous
+	}
+}
+
+var testWasmModule = []byte{0x00, 'a', 's', 'm', 0x01, 0x00, 0x00, 0x00}
+
+func TestWasmScalerConfigMap(t *testing.T) {
+	runtime := &fakeWasmRuntime{results: map[string]int64{WasmMetricFunction: 42, WasmActiveFunction: 1}}
+	defer setTestWasmRuntime(runtime)()
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "scaler"},
+		BinaryData: map[string][]byte{"module.wasm": testWasmModule, "invalid.wasm": []byte("not wasm")},
+	}
+	kubeClient := fake.NewFakeClientWithScheme(scheme.Scheme, configMap)
+
+	scaler, err := NewWasmScaler(kubeClient, "default", map[string]string{"configMapName": "scaler", "maxMemoryPages": "2"}, map[string]string{})
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	if name := scaler.GetMetricSpecForScaling()[0].External.Metric.Name; name != "wasm-scaler" {
+		t.Errorf("Expected metric name wasm-scaler, got %s", name)
+	}
+	metrics, err := scaler.GetMetrics(context.TODO(), "wasm-scaler", nil)
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	if value, _ := metrics[0].Value.AsInt64(); value != 42 {
+		t.Errorf("Expected metric 42, got %d", value)
+	}
+	if isActive, err := scaler.IsActive(context.TODO()); err != nil || !isActive {
+		t.Errorf("Expected the scaler to be active, got %t %v", isActive, err)
+	}
+	if runtime.limits.MaxMemoryPages != 2 {
+		t.Errorf("Expected the memory limit of 2 pages, got %d", runtime.limits.MaxMemoryPages)
+	}
+
+	invalid, err := NewWasmScaler(kubeClient, "default", map[string]string{"configMapName": "scaler", "configMapKey": "invalid.wasm"}, map[string]string{})
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	if _, err := invalid.IsActive(context.TODO()); err == nil {
+		t.Error("Expected an error for a module that isn't WebAssembly")
+	}
+}
+
+func TestWasmScalerOCI(t *testing.T) {
+	defer setTestWasmRuntime(&fakeWasmRuntime{results: map[string]int64{WasmMetricFunction: 7}})()
+
+	sum := sha256.Sum256(testWasmModule)
+	digest := "sha256:" + hex.EncodeToString(sum[:])
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/v2/acme/scaler/manifests/v1":
+			fmt.Fprintf(w, `{"layers": [{"digest": %q}]}`, digest)
+		case "/v2/acme/scaler/blobs/" + digest:
+			_, _ = w.Write(testWasmModule)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	registry := strings.TrimPrefix(server.URL, "https://")
+	scaler, err := NewWasmScaler(nil, "default", map[string]string{"ociRef": registry + "/acme/scaler:v1"}, map[string]string{"registryToken": "secret"})
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	scaler.(*wasmScaler).httpClient = server.Client()
+
+	metrics, err := scaler.GetMetrics(context.TODO(), "wasm-acme-scaler", nil)
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	if value, _ := metrics[0].Value.AsInt64(); value != 7 {
+		t.Errorf("Expected metric 7, got %d", value)
+	}
+	// is_active isn't exported by the module
+	if _, err := scaler.IsActive(context.TODO()); err == nil {
+		t.Error("Expected an error for a function not exported")
+	}
+}
@@ -0,0 +1,130 @@
+package scalers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	v2beta2 "k8s.io/api/autoscaling/v2beta2"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/metrics/pkg/apis/external_metrics"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/kedacore/keda/pkg/webhookreceiver"
+)
+
+const defaultWebhookActivationWindow = 5 * time.Minute
+
+type webhookScaler struct {
+	metadata *webhookMetadata
+	hub      *webhookreceiver.Hub
+}
+
+type webhookMetadata struct {
+	// the trigger is active for activationWindow after a webhook received on /webhooks/v1/<namespace>/<name>
+	name             string
+	namespace        string
+	secret           string
+	activationWindow time.Duration
+}
+
+var webhookLog = logf.Log.WithName("webhook_scaler")
+
+// NewWebhookScaler creates a new push scaler activated by the webhooks received by the webhook receiver of KEDA,
+// the metric driving the HPA comes from the other triggers of the ScaledObject
+func NewWebhookScaler(namespace string, metadata, authParams map[string]string) (PushScaler, error) {
+	meta, err := parseWebhookMetadata(namespace, metadata, authParams)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing webhook metadata: %s", err)
+	}
+
+	return &webhookScaler{
+		metadata: meta,
+		hub:      webhookreceiver.DefaultHub,
+	}, nil
+}
+
+func parseWebhookMetadata(namespace string, metadata, authParams map[string]string) (*webhookMetadata, error) {
+	meta := webhookMetadata{
+		namespace:        namespace,
+		activationWindow: defaultWebhookActivationWindow,
+	}
+
+	if val, ok := metadata["name"]; ok && val != "" {
+		meta.name = val
+	} else {
+		return nil, fmt.Errorf("no name given")
+	}
+
+	if val, ok := metadata["activationWindow"]; ok && val != "" {
+		window, err := strconv.Atoi(val)
+		if err != nil || window <= 0 {
+			return nil, fmt.Errorf("activationWindow %s is not a positive number of seconds", val)
+		}
+		meta.activationWindow = time.Duration(window) * time.Second
+	}
+
+	// the webhooks are only accepted with the secret, anyone able to reach the receiver could activate the trigger otherwise
+	if val, ok := authParams["secret"]; ok && val != "" {
+		meta.secret = val
+	} else {
+		return nil, fmt.Errorf("no secret given")
+	}
+
+	return &meta, nil
+}
+
+// IsActive returns true if a webhook was received within the activation window
+func (s *webhookScaler) IsActive(ctx context.Context) (bool, error) {
+	lastNotified := s.hub.LastNotified(s.metadata.namespace, s.metadata.name, s.metadata.secret)
+	return !lastNotified.IsZero() && time.Since(lastNotified) < s.metadata.activationWindow, nil
+}
+
+// Run reports the trigger as active as soon as a webhook is received, the polling deactivates it once the activation window elapsed
+func (s *webhookScaler) Run(ctx context.Context, active chan<- bool) {
+	notifications, cancel := s.hub.Subscribe(s.metadata.namespace, s.metadata.name, s.metadata.secret)
+	defer cancel()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-notifications:
+			webhookLog.V(1).Info("Webhook received, activating the trigger", "namespace", s.metadata.namespace, "name", s.metadata.name)
+			select {
+			case active <- true:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
+
+// Close does nothing in case of webhookScaler
+func (s *webhookScaler) Close() error {
+	return nil
+}
+
+// GetMetricSpecForScaling returns no MetricSpec, the trigger only activates the scale target
+func (s *webhookScaler) GetMetricSpecForScaling() []v2beta2.MetricSpec {
+	return nil
+}
+
+// GetMetrics returns 1 while the trigger is active, 0 otherwise
+func (s *webhookScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
+	var value int64
+	if active, _ := s.IsActive(ctx); active {
+		value = 1
+	}
+
+	metric := external_metrics.ExternalMetricValue{
+		MetricName: metricName,
+		Value:      *resource.NewQuantity(value, resource.DecimalSI),
+		Timestamp:  metav1.Now(),
+	}
+
+	return append([]external_metrics.ExternalMetricValue{}, metric), nil
+}
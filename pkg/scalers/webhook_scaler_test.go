@@ -0,0 +1,97 @@
+package scalers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kedacore/keda/pkg/webhookreceiver"
+)
+
+type parseWebhookMetadataTestData struct {
+	metadata   map[string]string
+	authParams map[string]string
+	isError    bool
+}
+
+var testWebhookMetadata = []parseWebhookMetadataTestData{
+	// properly formed metadata
+	{map[string]string{"name": "orders"}, map[string]string{"secret": "s3cr3t"}, false},
+	// with activation window
+	{map[string]string{"name": "orders", "activationWindow": "60"}, map[string]string{"secret": "s3cr3t"}, false},
+	// no secret
+	{map[string]string{"name": "orders"}, map[string]string{}, true},
+	// no name
+	{map[string]string{"activationWindow": "60"}, map[string]string{"secret": "s3cr3t"}, true},
+	// activation window not a number
+	{map[string]string{"name": "orders", "activationWindow": "1m"}, map[string]string{"secret": "s3cr3t"}, true},
+	// activation window not positive
+	{map[string]string{"name": "orders", "activationWindow": "0"}, map[string]string{"secret": "s3cr3t"}, true},
+}
+
+func TestParseWebhookMetadata(t *testing.T) {
+	for _, testData := range testWebhookMetadata {
+		_, err := parseWebhookMetadata("default", testData.metadata, testData.authParams)
+		if err != nil && !testData.isError {
+			t.Errorf("Expected success but got error %s for %v", err, testData.metadata)
+		}
+		if testData.isError && err == nil {
+			t.Errorf("Expected error but got success for %v", testData.metadata)
+		}
+	}
+}
+
+func TestWebhookScalerActivation(t *testing.T) {
+	hub := webhookreceiver.NewHub()
+	meta, err := parseWebhookMetadata("default", map[string]string{"name": "orders", "activationWindow": "60"}, map[string]string{"secret": "s3cr3t"})
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	scaler := &webhookScaler{metadata: meta, hub: hub}
+
+	if specs := scaler.GetMetricSpecForScaling(); len(specs) != 0 {
+		t.Errorf("Expected no metric spec, got %v", specs)
+	}
+	if active, _ := scaler.IsActive(context.Background()); active {
+		t.Error("Expected the trigger not to be active before a webhook")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	active := make(chan bool)
+	go scaler.Run(ctx, active)
+
+	server := webhookreceiver.NewServer("", hub)
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		req := httptest.NewRequest(http.MethodPost, webhookreceiver.PathPrefix+"default/orders", nil)
+		req.Header.Set(webhookreceiver.SecretHeader, "s3cr3t")
+		rec := httptest.NewRecorder()
+		server.ServeHTTP(rec, req)
+		if rec.Code == http.StatusAccepted {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("The scaler didn't subscribe to the webhooks")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	select {
+	case isActive := <-active:
+		if !isActive {
+			t.Error("Expected the webhook to activate the trigger")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the webhook to activate the trigger")
+	}
+	if isActive, _ := scaler.IsActive(context.Background()); !isActive {
+		t.Error("Expected the trigger to be active within the activation window")
+	}
+	metrics, _ := scaler.GetMetrics(context.Background(), "webhook-orders", nil)
+	if len(metrics) != 1 || metrics[0].Value.Value() != 1 {
+		t.Errorf("Expected a metric value of 1, got %v", metrics)
+	}
+}
@@ -0,0 +1,104 @@
+package scalersdk
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/kedacore/keda/pkg/globalconfig"
+	kedautil "github.com/kedacore/keda/pkg/util"
+)
+
+const (
+	// fromEnvSuffix is appended to a metadata key to read its value from the environment of the scale target
+	fromEnvSuffix = "FromEnv"
+	// fromFileSuffix is appended to a metadata key to read its value from a file mounted in the KEDA pods,
+	// within the secretFileDirectories of the global configuration
+	fromFileSuffix = "FromFile"
+)
+
+// ScalerConfig is what a scaler is built from, it is the trigger of a ScaledObject or ScaledJob with its resolved environment and authentication
+type ScalerConfig struct {
+	// Name and Namespace are the ones of the ScaledObject or ScaledJob
+	Name      string
+	Namespace string
+	// TriggerMetadata is the metadata of the trigger, its templates are already rendered
+	TriggerMetadata map[string]string
+	// ResolvedEnv is the environment of the container of the scale target
+	ResolvedEnv map[string]string
+	// AuthParams are the parameters resolved from the TriggerAuthentication of the trigger
+	AuthParams map[string]string
+	// PodIdentity is the pod identity provider of the TriggerAuthentication, empty if none
+	PodIdentity string
+}
+
+// GetParameter returns the value of a parameter of the trigger, looked up in the authentication parameters,
+// then in the metadata, in the environment variable named by the metadata <key>FromEnv and at last in the file
+// named by the metadata <key>FromFile
+func (c *ScalerConfig) GetParameter(key string) (string, bool) {
+	val, ok, _ := c.getParameter(key)
+	return val, ok
+}
+
+// getParameter returns the value of a parameter of the trigger, see GetParameter, or an error if its file can't be read
+func (c *ScalerConfig) getParameter(key string) (string, bool, error) {
+	if val, ok := c.AuthParams[key]; ok && val != "" {
+		return val, true, nil
+	}
+	if val, ok := c.TriggerMetadata[key]; ok && val != "" {
+		return val, true, nil
+	}
+	if envName, ok := c.TriggerMetadata[key+fromEnvSuffix]; ok && envName != "" {
+		if val, ok := c.ResolvedEnv[envName]; ok && val != "" {
+			return val, true, nil
+		}
+	}
+	if path, ok := c.TriggerMetadata[key+fromFileSuffix]; ok && path != "" {
+		val, err := kedautil.ReadSecretFile(path, globalconfig.Get().SecretFileDirectories)
+		if err != nil {
+			return "", false, fmt.Errorf("error reading %s: %s", key+fromFileSuffix, err)
+		}
+		if val != "" {
+			return val, true, nil
+		}
+	}
+	return "", false, nil
+}
+
+// GetRequiredParameter returns the value of a parameter of the trigger, see GetParameter, or an error if it isn't set
+func (c *ScalerConfig) GetRequiredParameter(key string) (string, error) {
+	val, ok, err := c.getParameter(key)
+	if err != nil {
+		return "", err
+	}
+	if ok {
+		return val, nil
+	}
+	return "", fmt.Errorf("no %s given", key)
+}
+
+// GetInt64Parameter returns the integer value of a parameter of the trigger, see GetParameter, or defaultValue if it isn't set
+func (c *ScalerConfig) GetInt64Parameter(key string, defaultValue int64) (int64, error) {
+	val, ok, err := c.getParameter(key)
+	if err != nil || !ok {
+		return defaultValue, err
+	}
+	value, err := strconv.ParseInt(val, 10, 64)
+	if err != nil {
+		return defaultValue, fmt.Errorf("error parsing %s: %s", key, err)
+	}
+	return value, nil
+}
+
+// GetDurationParameter returns the duration value of a parameter of the trigger, e.g. 30s, see GetParameter, or defaultValue if it isn't set
+func (c *ScalerConfig) GetDurationParameter(key string, defaultValue time.Duration) (time.Duration, error) {
+	val, ok, err := c.getParameter(key)
+	if err != nil || !ok {
+		return defaultValue, err
+	}
+	value, err := time.ParseDuration(val)
+	if err != nil {
+		return defaultValue, fmt.Errorf("error parsing %s: %s", key, err)
+	}
+	return value, nil
+}
@@ -0,0 +1,72 @@
+package scalersdk
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGetParameter(t *testing.T) {
+	config := &ScalerConfig{
+		TriggerMetadata: map[string]string{"host": "metadata-host", "user": "metadata-user", "passwordFromEnv": "PASSWORD", "port": "5432", "interval": "30s", "empty": ""},
+		ResolvedEnv:     map[string]string{"PASSWORD": "env-password"},
+		AuthParams:      map[string]string{"user": "auth-user"},
+	}
+
+	tests := []struct {
+		key      string
+		expected string
+		ok       bool
+	}{
+		{"host", "metadata-host", true},
+		{"user", "auth-user", true},
+		{"password", "env-password", true},
+		{"empty", "", false},
+		{"missing", "", false},
+	}
+	for _, test := range tests {
+		val, ok := config.GetParameter(test.key)
+		if val != test.expected || ok != test.ok {
+			t.Errorf("%s: expected %q %t, got %q %t", test.key, test.expected, test.ok, val, ok)
+		}
+	}
+
+	if _, err := config.GetRequiredParameter("missing"); err == nil {
+		t.Error("Expected an error for a missing required parameter")
+	}
+	if port, err := config.GetInt64Parameter("port", 0); err != nil || port != 5432 {
+		t.Errorf("Expected port 5432, got %d %v", port, err)
+	}
+	if port, err := config.GetInt64Parameter("missing", 80); err != nil || port != 80 {
+		t.Errorf("Expected the default port 80, got %d %v", port, err)
+	}
+	if _, err := config.GetInt64Parameter("host", 0); err == nil {
+		t.Error("Expected an error for a malformed integer")
+	}
+	if interval, err := config.GetDurationParameter("interval", time.Minute); err != nil || interval != 30*time.Second {
+		t.Errorf("Expected an interval of 30s, got %s %v", interval, err)
+	}
+	if _, err := config.GetDurationParameter("port", time.Minute); err == nil {
+		t.Error("Expected an error for a malformed duration")
+	}
+}
+
+func TestGetParameterFromFile(t *testing.T) {
+	// the files can't be read without secretFileDirectories in the global configuration
+	config := &ScalerConfig{TriggerMetadata: map[string]string{"tokenFromFile": "/etc/hostname"}}
+	if val, ok := config.GetParameter("token"); ok || val != "" {
+		t.Errorf("Expected no token, got %q", val)
+	}
+	if _, err := config.GetRequiredParameter("token"); err == nil || !strings.Contains(err.Error(), "tokenFromFile") {
+		t.Errorf("Expected an error reading tokenFromFile, got %v", err)
+	}
+	if _, err := config.GetInt64Parameter("token", 0); err == nil {
+		t.Error("Expected an error reading tokenFromFile")
+	}
+
+	// the value of the metadata takes precedence over the file
+	config.TriggerMetadata["token"] = "metadata-token"
+	if val, err := config.GetRequiredParameter("token"); err != nil || val != "metadata-token" {
+		t.Errorf("Expected the token of the metadata, got %q %v", val, err)
+	}
+}
@@ -0,0 +1,96 @@
+package scalersdk
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	kedautil "github.com/kedacore/keda/pkg/util"
+)
+
+const (
+	httpRetryMaxAttemptsMetadata = "httpRetryMaxAttempts"
+	httpRetryBackoffMetadata     = "httpRetryBackoff"
+	httpRetryOnStatusMetadata    = "httpRetryOnStatus"
+)
+
+// RetryPolicy defines how the requests of a scaler to its upstream API are retried on transient failures
+type RetryPolicy = kedautil.RetryPolicy
+
+// DefaultRetryPolicy returns the policy used if a scaler has no retry configuration
+func DefaultRetryPolicy() RetryPolicy {
+	return kedautil.DefaultRetryPolicy()
+}
+
+// NewHTTPClient returns a client for the requests of a scaler, 0 disables the timeout
+func NewHTTPClient(timeout time.Duration) *http.Client {
+	return &http.Client{Timeout: timeout}
+}
+
+// ParseHTTPRetryPolicy returns the retry policy of the HTTP requests of a scaler, the defaults are overridden by the trigger metadata:
+// httpRetryMaxAttempts is the number of attempts, 1 disables the retries, httpRetryBackoff is the wait before the first retry
+// as a duration, e.g. 500ms, and httpRetryOnStatus is a comma separated list of the status codes to retry on
+func ParseHTTPRetryPolicy(metadata map[string]string) (RetryPolicy, error) {
+	policy := DefaultRetryPolicy()
+
+	if val, ok := metadata[httpRetryMaxAttemptsMetadata]; ok && val != "" {
+		maxAttempts, err := strconv.Atoi(val)
+		if err != nil {
+			return policy, fmt.Errorf("error parsing %s: %s", httpRetryMaxAttemptsMetadata, err)
+		}
+		if maxAttempts < 1 {
+			return policy, fmt.Errorf("%s must be at least 1", httpRetryMaxAttemptsMetadata)
+		}
+		policy.MaxAttempts = maxAttempts
+	}
+
+	if val, ok := metadata[httpRetryBackoffMetadata]; ok && val != "" {
+		backoff, err := time.ParseDuration(val)
+		if err != nil {
+			return policy, fmt.Errorf("error parsing %s: %s", httpRetryBackoffMetadata, err)
+		}
+		if backoff < 0 {
+			return policy, fmt.Errorf("%s must not be negative", httpRetryBackoffMetadata)
+		}
+		policy.Backoff = backoff
+		if backoff > policy.MaxBackoff {
+			policy.MaxBackoff = backoff
+		}
+	}
+
+	if val, ok := metadata[httpRetryOnStatusMetadata]; ok && val != "" {
+		policy.RetryOnStatus = nil
+		for _, s := range strings.Split(val, ",") {
+			status, err := strconv.Atoi(strings.TrimSpace(s))
+			if err != nil || status < 100 || status > 599 {
+				return policy, fmt.Errorf("invalid status code %q in %s", s, httpRetryOnStatusMetadata)
+			}
+			policy.RetryOnStatus = append(policy.RetryOnStatus, status)
+		}
+	}
+
+	return policy, nil
+}
+
+// DoWithRetry sends the request with the client and retries it according to the policy, the default client of KEDA is used if client is nil
+func DoWithRetry(client *http.Client, req *http.Request, policy RetryPolicy) (*http.Response, error) {
+	return kedautil.DoWithRetry(client, req, policy)
+}
+
+// DoHTTPGetWithRetry sends a GET request to the url, it is retried according to the policy
+func DoHTTPGetWithRetry(client *http.Client, url string, policy RetryPolicy) (*http.Response, error) {
+	return DoHTTPGetWithRetryContext(context.Background(), client, url, policy)
+}
+
+// DoHTTPGetWithRetryContext sends a GET request to the url with the context, it is retried according to the policy
+// until the context is done
+func DoHTTPGetWithRetryContext(ctx context.Context, client *http.Client, url string, policy RetryPolicy) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return DoWithRetry(client, req, policy)
+}
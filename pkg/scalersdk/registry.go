@@ -0,0 +1,54 @@
+package scalersdk
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Factory builds the scaler of a trigger
+type Factory func(config *ScalerConfig) (Scaler, error)
+
+var (
+	registryMutex sync.RWMutex
+	registry      = make(map[string]Factory)
+)
+
+// Register makes an out-of-tree scaler available for the triggers of the given type, it is meant to be called from the init function
+// of the scaler package, which is then imported for its side effects by the binary embedding KEDA. The built-in scalers take precedence
+// over the registered ones. Register panics if the type is empty, the factory is nil or the type is already registered.
+func Register(triggerType string, factory Factory) {
+	if triggerType == "" {
+		panic("scalersdk: Register with an empty trigger type")
+	}
+	if factory == nil {
+		panic(fmt.Sprintf("scalersdk: Register of %s with a nil factory", triggerType))
+	}
+
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+	if _, ok := registry[triggerType]; ok {
+		panic(fmt.Sprintf("scalersdk: Register called twice for %s", triggerType))
+	}
+	registry[triggerType] = factory
+}
+
+// Lookup returns the factory registered for the trigger type
+func Lookup(triggerType string) (Factory, bool) {
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+	factory, ok := registry[triggerType]
+	return factory, ok
+}
+
+// RegisteredTypes returns the sorted trigger types of the registered scalers
+func RegisteredTypes() []string {
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+	types := make([]string, 0, len(registry))
+	for triggerType := range registry {
+		types = append(types, triggerType)
+	}
+	sort.Strings(types)
+	return types
+}
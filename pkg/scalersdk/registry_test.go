@@ -0,0 +1,39 @@
+package scalersdk
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRegister(t *testing.T) {
+	defer func() { registry = make(map[string]Factory) }()
+
+	factory := func(config *ScalerConfig) (Scaler, error) { return nil, nil }
+	Register("vendor-queue", factory)
+	Register("vendor-api", factory)
+
+	if _, ok := Lookup("vendor-queue"); !ok {
+		t.Error("Expected vendor-queue to be registered")
+	}
+	if _, ok := Lookup("kafka"); ok {
+		t.Error("Expected kafka not to be registered")
+	}
+	if types := RegisteredTypes(); !reflect.DeepEqual(types, []string{"vendor-api", "vendor-queue"}) {
+		t.Errorf("Expected the sorted registered types, got %v", types)
+	}
+
+	for name, register := range map[string]func(){
+		"duplicate":   func() { Register("vendor-queue", factory) },
+		"empty type":  func() { Register("", factory) },
+		"nil factory": func() { Register("vendor-nil", nil) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Expected Register to panic for a %s", name)
+				}
+			}()
+			register()
+		}()
+	}
+}
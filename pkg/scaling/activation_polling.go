@@ -0,0 +1,37 @@
+package scaling
+
+import (
+	"sync"
+	"time"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+)
+
+// getScaleLoopInterval returns the time until the next check of the triggers of a scalable object, the activationPollingInterval
+// of a ScaledObject is used instead of the pollingInterval while its ScaleTarget is scaled to zero
+func getScaleLoopInterval(withTriggers *kedav1alpha1.WithTriggers, scalableObject interface{}, scalingMutex *sync.Mutex) time.Duration {
+	pollingInterval := getPollingInterval(withTriggers)
+
+	scaledObject, ok := scalableObject.(*kedav1alpha1.ScaledObject)
+	if !ok || scaledObject.Spec.ActivationPollingInterval == nil {
+		return pollingInterval
+	}
+	activationPollingInterval := time.Second * time.Duration(*scaledObject.Spec.ActivationPollingInterval)
+	if activationPollingInterval <= 0 || activationPollingInterval >= pollingInterval {
+		return pollingInterval
+	}
+
+	// the status is updated by the scale executor under the scaling mutex
+	scalingMutex.Lock()
+	defer scalingMutex.Unlock()
+	if !isScaledToZero(scaledObject) {
+		return pollingInterval
+	}
+	return activationPollingInterval
+}
+
+// isScaledToZero returns true if the scale executor scaled the ScaleTarget of the ScaledObject to zero and it has no replica since,
+// the triggers are also inactive while the ScaleTarget keeps replicas, e.g. during the cooldown or above the minReplicaCount
+func isScaledToZero(scaledObject *kedav1alpha1.ScaledObject) bool {
+	return scaledObject.Status.ScaledToZero
+}
@@ -0,0 +1,59 @@
+package scaling
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+)
+
+func TestGetScaleLoopInterval(t *testing.T) {
+	int32Ptr := func(i int32) *int32 { return &i }
+	newScaledObject := func(activationPollingInterval, minReplicaCount *int32, reason string, scaledToZero bool) *kedav1alpha1.ScaledObject {
+		scaledObject := &kedav1alpha1.ScaledObject{Spec: kedav1alpha1.ScaledObjectSpec{
+			PollingInterval:           int32Ptr(30),
+			ActivationPollingInterval: activationPollingInterval,
+			MinReplicaCount:           minReplicaCount,
+		}}
+		scaledObject.Status.Conditions = *kedav1alpha1.GetInitializedConditions()
+		if reason != "" {
+			scaledObject.Status.Conditions.SetActiveCondition(metav1.ConditionFalse, reason, "")
+		}
+		scaledObject.Status.ScaledToZero = scaledToZero
+		return scaledObject
+	}
+
+	tests := []struct {
+		name         string
+		scaledObject *kedav1alpha1.ScaledObject
+		expected     time.Duration
+	}{
+		{"no activation polling interval", newScaledObject(nil, nil, "ScalerNotActive", true), 30 * time.Second},
+		{"scaled to zero", newScaledObject(int32Ptr(2), nil, "ScalerNotActive", true), 2 * time.Second},
+		{"cooling down", newScaledObject(int32Ptr(2), nil, "ScalerCooldown", false), 30 * time.Second},
+		{"active condition unknown", newScaledObject(int32Ptr(2), nil, "", false), 30 * time.Second},
+		{"not active with replicas", newScaledObject(int32Ptr(2), nil, "ScalerNotActive", false), 30 * time.Second},
+		{"min replica count above zero", newScaledObject(int32Ptr(2), int32Ptr(1), "ScalerNotActive", false), 30 * time.Second},
+		{"not shorter than the polling interval", newScaledObject(int32Ptr(60), nil, "ScalerNotActive", true), 30 * time.Second},
+	}
+
+	for _, test := range tests {
+		withTriggers, err := asDuckWithTriggers(test.scaledObject)
+		if err != nil {
+			t.Fatalf("Unexpected error %s", err)
+		}
+		if interval := getScaleLoopInterval(withTriggers, test.scaledObject, &sync.Mutex{}); interval != test.expected {
+			t.Errorf("%s: expected %s, got %s", test.name, test.expected, interval)
+		}
+	}
+
+	active := newScaledObject(int32Ptr(2), nil, "", false)
+	active.Status.Conditions.SetActiveCondition(metav1.ConditionTrue, "ScalerActive", "")
+	withTriggers, _ := asDuckWithTriggers(active)
+	if interval := getScaleLoopInterval(withTriggers, active, &sync.Mutex{}); interval != 30*time.Second {
+		t.Errorf("Expected the polling interval while active, got %s", interval)
+	}
+}
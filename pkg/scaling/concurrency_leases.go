@@ -0,0 +1,131 @@
+package scaling
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// scalerConcurrencyLeaseLabel is set on the Leases of the processes sharing the scaler concurrency limits
+	scalerConcurrencyLeaseLabel  = "keda.sh/scaler-concurrency"
+	scalerConcurrencyLeasePrefix = "keda-scaler-concurrency-"
+	// scalerConcurrencyLeaseDuration is how long the Lease of a process stopped without removing it is counted in the shares
+	scalerConcurrencyLeaseDuration = 30 * time.Second
+	scalerConcurrencyRenewInterval = 10 * time.Second
+)
+
+var scalerConcurrencyLog = logf.Log.WithName("scaler_concurrency")
+
+// ScalerConcurrencyLeases renews the Lease of the process and resizes its slots to its share of the scaler concurrency limits,
+// the share is taken from the number of Leases renewed by the processes. Until the first renewal the process has the whole limits.
+type ScalerConcurrencyLeases struct {
+	reader    client.Reader
+	writer    client.Writer
+	namespace string
+	name      string
+	identity  string
+	limited   bool
+}
+
+// NewScalerConcurrencyLeases returns the Leases of the limits of the config, the reader must not be cached so the Leases
+// of the other processes are up to date
+func NewScalerConcurrencyLeases(reader client.Reader, writer client.Writer, config ScalerConcurrencyConfig) (*ScalerConcurrencyLeases, error) {
+	identity := config.Identity
+	if identity == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return nil, fmt.Errorf("error getting the identity of the scaler concurrency Lease: %s", err)
+		}
+		identity = hostname
+	}
+	return &ScalerConcurrencyLeases{
+		reader:    reader,
+		writer:    writer,
+		namespace: config.Namespace,
+		name:      scalerConcurrencyLeasePrefix + identity,
+		identity:  identity,
+		limited:   config.Namespace != "" && config.isLimited(),
+	}, nil
+}
+
+// Start renews the Lease until stop is closed, the Lease is then removed so the other processes take its share.
+// It returns at once if the limits aren't shared
+func (l *ScalerConcurrencyLeases) Start(stop <-chan struct{}) error {
+	if !l.limited {
+		return nil
+	}
+
+	ticker := time.NewTicker(scalerConcurrencyRenewInterval)
+	defer ticker.Stop()
+	for {
+		if err := l.renew(context.TODO(), time.Now()); err != nil {
+			scalerConcurrencyLog.Error(err, "Failed to renew the scaler concurrency Lease, the last shares are kept", "Lease", l.name)
+		}
+
+		select {
+		case <-stop:
+			lease := &coordinationv1.Lease{ObjectMeta: metav1.ObjectMeta{Namespace: l.namespace, Name: l.name}}
+			if err := l.writer.Delete(context.TODO(), lease); err != nil && !errors.IsNotFound(err) {
+				scalerConcurrencyLog.Error(err, "Failed to remove the scaler concurrency Lease", "Lease", l.name)
+			}
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// renew creates or renews the Lease of the process and sets the shares from the Leases renewed, the process included
+func (l *ScalerConcurrencyLeases) renew(ctx context.Context, now time.Time) error {
+	renewTime := metav1.NewMicroTime(now)
+	lease := &coordinationv1.Lease{}
+	err := l.reader.Get(ctx, types.NamespacedName{Namespace: l.namespace, Name: l.name}, lease)
+	switch {
+	case errors.IsNotFound(err):
+		leaseDurationSeconds := int32(scalerConcurrencyLeaseDuration.Seconds())
+		lease = &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: l.namespace,
+				Name:      l.name,
+				Labels:    map[string]string{scalerConcurrencyLeaseLabel: "true"},
+			},
+			Spec: coordinationv1.LeaseSpec{
+				HolderIdentity:       &l.identity,
+				LeaseDurationSeconds: &leaseDurationSeconds,
+				AcquireTime:          &renewTime,
+				RenewTime:            &renewTime,
+			},
+		}
+		err = l.writer.Create(ctx, lease)
+	case err == nil:
+		lease.Spec.RenewTime = &renewTime
+		err = l.writer.Update(ctx, lease)
+	}
+	if err != nil {
+		return err
+	}
+
+	leases := &coordinationv1.LeaseList{}
+	if err := l.reader.List(ctx, leases, client.InNamespace(l.namespace), client.MatchingLabels{scalerConcurrencyLeaseLabel: "true"}); err != nil {
+		return err
+	}
+	members := []string{l.name}
+	for _, lease := range leases.Items {
+		if lease.Name != l.name && lease.Spec.RenewTime != nil && lease.Spec.LeaseDurationSeconds != nil &&
+			lease.Spec.RenewTime.Add(time.Duration(*lease.Spec.LeaseDurationSeconds)*time.Second).After(now) {
+			members = append(members, lease.Name)
+		}
+	}
+	sort.Strings(members)
+	setScalerConcurrencyMembers(len(members), sort.SearchStrings(members, l.name))
+	return nil
+}
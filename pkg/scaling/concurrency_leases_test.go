@@ -0,0 +1,87 @@
+package scaling
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newTestScalerConcurrencyLease(name string, renewed time.Time) *coordinationv1.Lease {
+	renewTime := metav1.NewMicroTime(renewed)
+	leaseDurationSeconds := int32(scalerConcurrencyLeaseDuration.Seconds())
+	return &coordinationv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "keda", Name: name, Labels: map[string]string{scalerConcurrencyLeaseLabel: "true"}},
+		Spec:       coordinationv1.LeaseSpec{RenewTime: &renewTime, LeaseDurationSeconds: &leaseDurationSeconds},
+	}
+}
+
+func TestScalerConcurrencyLeases(t *testing.T) {
+	defer func() { _ = ConfigureScalerConcurrency(ScalerConcurrencyConfig{}) }()
+
+	now := time.Now()
+	unlabeled := newTestScalerConcurrencyLease("other", now)
+	unlabeled.Labels = nil
+	kubeClient := fake.NewFakeClientWithScheme(clientgoscheme.Scheme,
+		newTestScalerConcurrencyLease(scalerConcurrencyLeasePrefix+"a", now),
+		newTestScalerConcurrencyLease(scalerConcurrencyLeasePrefix+"z", now.Add(-time.Hour)),
+		unlabeled)
+
+	config := ScalerConcurrencyConfig{Limit: 5, Namespace: "keda", Identity: "b"}
+	if err := ConfigureScalerConcurrency(config); err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	slots := getScalerConcurrencySlots("prometheus")
+	if slots.Limit() != 5 {
+		t.Errorf("Expected the whole limit before the first renewal, got %d", slots.Limit())
+	}
+
+	leases, err := NewScalerConcurrencyLeases(kubeClient, kubeClient, config)
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	if err := leases.renew(context.TODO(), now); err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	// the expired Lease of z isn't counted, b is the second of a and b
+	if slots.Limit() != 2 {
+		t.Errorf("Expected the share of 2 slots, got %d", slots.Limit())
+	}
+	key := types.NamespacedName{Namespace: "keda", Name: scalerConcurrencyLeasePrefix + "b"}
+	lease := &coordinationv1.Lease{}
+	if err := kubeClient.Get(context.TODO(), key, lease); err != nil {
+		t.Fatalf("Expected the Lease of the process to be created, got %s", err)
+	}
+	if lease.Spec.HolderIdentity == nil || *lease.Spec.HolderIdentity != "b" || lease.Spec.RenewTime == nil {
+		t.Errorf("Expected the Lease to be held and renewed by b, got %+v", lease.Spec)
+	}
+
+	// the Lease is removed once the process stops
+	stop := make(chan struct{})
+	close(stop)
+	if err := leases.Start(stop); err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	if err := kubeClient.Get(context.TODO(), key, lease); !errors.IsNotFound(err) {
+		t.Errorf("Expected the Lease of the stopped process to be removed, got %v", err)
+	}
+
+	// the processes without a shared limit don't take a Lease
+	unlimited, err := NewScalerConcurrencyLeases(kubeClient, kubeClient, ScalerConcurrencyConfig{Namespace: "keda", Identity: "c"})
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	if err := unlimited.Start(stop); err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	key.Name = scalerConcurrencyLeasePrefix + "c"
+	if err := kubeClient.Get(context.TODO(), key, lease); !errors.IsNotFound(err) {
+		t.Errorf("Expected no Lease without a limit, got %v", err)
+	}
+}
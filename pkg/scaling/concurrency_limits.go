@@ -0,0 +1,139 @@
+package scaling
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/kedacore/keda/pkg/scalers"
+)
+
+// ScalerConcurrencyConfig bounds the number of the calls of the scalers of a trigger type made at the same time, e.g. to stay
+// under the throttling limits of a provider. With a Namespace, the limits are shared by the operator and the metrics adapters:
+// each process renews a Lease in the Namespace and takes an even share of the limits between the processes holding one.
+// Without it, the limits apply to each process
+type ScalerConcurrencyConfig struct {
+	// Limit is the number of concurrent calls of the scalers of each trigger type, 0 doesn't limit them
+	Limit int
+	// Limits overrides Limit for the trigger types given, 0 doesn't limit the calls of the trigger type
+	Limits map[string]int
+	// Namespace is the namespace of the Leases of the processes sharing the limits
+	Namespace string
+	// Identity is the name of the Lease of the process, the hostname if not set
+	Identity string
+}
+
+var (
+	scalerConcurrencyConfig ScalerConcurrencyConfig
+	// scalerConcurrencySlots are the slots of each limited trigger type, they are shared by all the scalers of the type
+	scalerConcurrencySlots = map[string]*scalers.ConcurrencySlots{}
+	// scalerConcurrencyMembers is the number of processes sharing the limits and scalerConcurrencyMember the position of the process
+	scalerConcurrencyMembers = 1
+	scalerConcurrencyMember  = 0
+	scalerConcurrencyMutex   sync.Mutex
+)
+
+// ConfigureScalerConcurrency sets the concurrency limits of the scalers, it must be called before the scale loops are started
+func ConfigureScalerConcurrency(config ScalerConcurrencyConfig) error {
+	if config.Limit < 0 {
+		return fmt.Errorf("the scaler concurrency limit must not be negative, got %d", config.Limit)
+	}
+	for triggerType, limit := range config.Limits {
+		if limit < 0 {
+			return fmt.Errorf("the scaler concurrency limit of %s must not be negative, got %d", triggerType, limit)
+		}
+	}
+
+	scalerConcurrencyMutex.Lock()
+	defer scalerConcurrencyMutex.Unlock()
+	scalerConcurrencyConfig = config
+	scalerConcurrencySlots = map[string]*scalers.ConcurrencySlots{}
+	scalerConcurrencyMembers, scalerConcurrencyMember = 1, 0
+	return nil
+}
+
+// setScalerConcurrencyMembers sets the processes sharing the limits and resizes the slots to the share of the process
+func setScalerConcurrencyMembers(members, member int) {
+	scalerConcurrencyMutex.Lock()
+	defer scalerConcurrencyMutex.Unlock()
+	scalerConcurrencyMembers, scalerConcurrencyMember = members, member
+	for triggerType, slots := range scalerConcurrencySlots {
+		slots.SetLimit(getScalerConcurrencyShare(getScalerConcurrencyLimit(triggerType)))
+	}
+}
+
+// getScalerConcurrencyLimit returns the limit of the trigger type, 0 if its calls aren't limited
+func getScalerConcurrencyLimit(triggerType string) int {
+	limit, ok := scalerConcurrencyConfig.Limits[triggerType]
+	if !ok {
+		limit = scalerConcurrencyConfig.Limit
+	}
+	return limit
+}
+
+// getScalerConcurrencyShare returns the share of the limit of the process, the remainder of the limit goes to the first processes.
+// Each process keeps at least one slot, so the limit is exceeded if there are more processes than slots
+func getScalerConcurrencyShare(limit int) int {
+	share := limit / scalerConcurrencyMembers
+	if scalerConcurrencyMember < limit%scalerConcurrencyMembers {
+		share++
+	}
+	if share < 1 {
+		share = 1
+	}
+	return share
+}
+
+// ParseScalerConcurrencyLimits parses the comma separated <trigger type>=<limit> pairs of the limits of the trigger types
+func ParseScalerConcurrencyLimits(limits string) (map[string]int, error) {
+	result := map[string]int{}
+	for _, pair := range strings.Split(limits, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		parts := strings.SplitN(pair, "=", 2)
+		triggerType := strings.TrimSpace(parts[0])
+		if len(parts) != 2 || triggerType == "" {
+			return nil, fmt.Errorf("the scaler concurrency limit %q isn't a <trigger type>=<limit> pair", pair)
+		}
+		limit, err := strconv.Atoi(strings.TrimSpace(parts[1]))
+		if err != nil {
+			return nil, fmt.Errorf("error parsing the scaler concurrency limit of %s: %s", triggerType, err)
+		}
+		result[triggerType] = limit
+	}
+	return result, nil
+}
+
+// getScalerConcurrencySlots returns the slots shared by the scalers of the trigger type, nil if their calls aren't limited
+func getScalerConcurrencySlots(triggerType string) *scalers.ConcurrencySlots {
+	scalerConcurrencyMutex.Lock()
+	defer scalerConcurrencyMutex.Unlock()
+
+	limit := getScalerConcurrencyLimit(triggerType)
+	if limit == 0 {
+		return nil
+	}
+
+	slots, ok := scalerConcurrencySlots[triggerType]
+	if !ok {
+		slots = scalers.NewConcurrencySlots(getScalerConcurrencyShare(limit))
+		scalerConcurrencySlots[triggerType] = slots
+	}
+	return slots
+}
+
+// isScalerConcurrencyLimited returns whether the calls of any trigger type are limited
+func (c ScalerConcurrencyConfig) isLimited() bool {
+	if c.Limit > 0 {
+		return true
+	}
+	for _, limit := range c.Limits {
+		if limit > 0 {
+			return true
+		}
+	}
+	return false
+}
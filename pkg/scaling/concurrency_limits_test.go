@@ -0,0 +1,86 @@
+package scaling
+
+import (
+	"testing"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+	"github.com/kedacore/keda/pkg/scalers"
+)
+
+func TestParseScalerConcurrencyLimits(t *testing.T) {
+	limits, err := ParseScalerConcurrencyLimits(" azure-log-analytics=10, prometheus = 20,")
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	if len(limits) != 2 || limits["azure-log-analytics"] != 10 || limits["prometheus"] != 20 {
+		t.Errorf("Expected the limits of azure-log-analytics and prometheus, got %v", limits)
+	}
+
+	if limits, err := ParseScalerConcurrencyLimits(""); err != nil || len(limits) != 0 {
+		t.Errorf("Expected no limit, got %v and %v", limits, err)
+	}
+	for _, invalid := range []string{"prometheus", "=10", "prometheus=many"} {
+		if _, err := ParseScalerConcurrencyLimits(invalid); err == nil {
+			t.Errorf("Expected an error for %q", invalid)
+		}
+	}
+}
+
+func TestScalerConcurrencySlots(t *testing.T) {
+	defer func() { _ = ConfigureScalerConcurrency(ScalerConcurrencyConfig{}) }()
+
+	if slots := getScalerConcurrencySlots("prometheus"); slots != nil {
+		t.Error("Expected the calls not to be limited by default")
+	}
+
+	if err := ConfigureScalerConcurrency(ScalerConcurrencyConfig{Limit: 5, Limits: map[string]int{"azure-log-analytics": 2, "cron": 0}}); err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	slots := getScalerConcurrencySlots("azure-log-analytics")
+	if slots.Limit() != 2 {
+		t.Errorf("Expected 2 slots for azure-log-analytics, got %d", slots.Limit())
+	}
+	if getScalerConcurrencySlots("azure-log-analytics") != slots {
+		t.Error("Expected the slots to be shared by the scalers of the trigger type")
+	}
+	if slots := getScalerConcurrencySlots("prometheus"); slots.Limit() != 5 {
+		t.Errorf("Expected the default of 5 slots for prometheus, got %d", slots.Limit())
+	}
+	if slots := getScalerConcurrencySlots("cron"); slots != nil {
+		t.Error("Expected the calls of cron not to be limited")
+	}
+
+	scaler := withConcurrencyLimit(kedav1alpha1.ScaleTriggers{Type: "cron"}, scalers.NewDisabledScaler())
+	if scaler != scalers.UnwrapScaler(scaler) {
+		t.Error("Expected the scaler of a trigger type not limited not to be wrapped")
+	}
+	scaler = withConcurrencyLimit(kedav1alpha1.ScaleTriggers{Type: "prometheus"}, scalers.NewDisabledScaler())
+	if scaler == scalers.UnwrapScaler(scaler) {
+		t.Error("Expected the scaler of a limited trigger type to be wrapped")
+	}
+
+	if err := ConfigureScalerConcurrency(ScalerConcurrencyConfig{Limits: map[string]int{"prometheus": -1}}); err == nil {
+		t.Error("Expected an error for a negative limit")
+	}
+}
+
+func TestScalerConcurrencyShares(t *testing.T) {
+	defer func() { _ = ConfigureScalerConcurrency(ScalerConcurrencyConfig{}) }()
+
+	if err := ConfigureScalerConcurrency(ScalerConcurrencyConfig{Limit: 5, Limits: map[string]int{"cron": 1}}); err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	slots := getScalerConcurrencySlots("prometheus")
+
+	// the remainder of the limit goes to the first processes
+	for member, expected := range []int{2, 2, 1} {
+		setScalerConcurrencyMembers(3, member)
+		if slots.Limit() != expected {
+			t.Errorf("Expected %d slots for the process %d of 3, got %d", expected, member, slots.Limit())
+		}
+	}
+	// each process keeps a slot
+	if slots := getScalerConcurrencySlots("cron"); slots.Limit() != 1 {
+		t.Errorf("Expected the process over the limit to keep a slot, got %d", slots.Limit())
+	}
+}
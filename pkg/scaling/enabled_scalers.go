@@ -0,0 +1,32 @@
+package scaling
+
+import (
+	"fmt"
+	"strings"
+)
+
+// enabledScalers are the trigger types the scalers can be built for, all the trigger types if nil
+var enabledScalers map[string]bool
+
+// EnableScalers restricts the scalers built to the trigger types given, all the scalers are enabled if triggerTypes is empty.
+// It must be called before the scale loops are started, the triggers of other types fail as if their type was unknown.
+func EnableScalers(triggerTypes []string) error {
+	if len(triggerTypes) == 0 {
+		enabledScalers = nil
+		return nil
+	}
+	enabled := make(map[string]bool, len(triggerTypes))
+	for _, triggerType := range triggerTypes {
+		triggerType = strings.TrimSpace(triggerType)
+		if triggerType == "" {
+			return fmt.Errorf("the enabled scalers must not contain an empty trigger type")
+		}
+		enabled[triggerType] = true
+	}
+	enabledScalers = enabled
+	return nil
+}
+
+func isScalerEnabled(triggerType string) bool {
+	return enabledScalers == nil || enabledScalers[triggerType]
+}
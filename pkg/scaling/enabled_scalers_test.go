@@ -0,0 +1,57 @@
+package scaling
+
+import (
+	"testing"
+
+	"github.com/kedacore/keda/pkg/scalers"
+	"github.com/kedacore/keda/pkg/scalersdk"
+)
+
+func TestEnableScalers(t *testing.T) {
+	defer func() { enabledScalers = nil }()
+
+	if !isScalerEnabled("cron") {
+		t.Error("Expected all the scalers to be enabled by default")
+	}
+
+	if err := EnableScalers([]string{"cron", " cpu "}); err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	if !isScalerEnabled("cron") || !isScalerEnabled("cpu") {
+		t.Error("Expected cron and cpu to be enabled")
+	}
+	if isScalerEnabled("kafka") {
+		t.Error("Expected kafka not to be enabled")
+	}
+	if _, err := buildScaler(nil, "test", "default", "kafka", map[string]string{}, map[string]string{}, map[string]string{}, ""); err == nil {
+		t.Error("Expected an error building a scaler not enabled")
+	}
+
+	if err := EnableScalers([]string{"cron", ""}); err == nil {
+		t.Error("Expected an error for an empty trigger type")
+	}
+
+	if err := EnableScalers(nil); err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	if !isScalerEnabled("kafka") {
+		t.Error("Expected all the scalers to be enabled again")
+	}
+}
+
+func TestBuildRegisteredScaler(t *testing.T) {
+	var config *scalersdk.ScalerConfig
+	scalersdk.Register("test-registered", func(c *scalersdk.ScalerConfig) (scalersdk.Scaler, error) {
+		config = c
+		return scalers.NewDisabledScaler(), nil
+	})
+
+	scaler, err := buildScaler(nil, "test", "default", "test-registered", map[string]string{}, map[string]string{"queue": "orders"}, map[string]string{}, "")
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	defer scaler.Close()
+	if config == nil || config.Name != "test" || config.Namespace != "default" || config.TriggerMetadata["queue"] != "orders" {
+		t.Errorf("Expected the registered factory to be called with the trigger, got %+v", config)
+	}
+}
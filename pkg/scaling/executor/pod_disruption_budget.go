@@ -0,0 +1,65 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-logr/logr"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+	"github.com/kedacore/keda/pkg/eventemitter"
+	kedautil "github.com/kedacore/keda/pkg/util"
+)
+
+// scaleToZeroDeferredReason is the reason of the ScaleDownDeferred condition set by the executor,
+// the controller sets its own reason when it defers lowering the MinReplicas of the HPA
+const scaleToZeroDeferredReason = "ScaleToZeroViolatesPDB"
+
+// isScaleToZeroDeferred returns true if scaling the ScaleTarget to zero would violate a PodDisruptionBudget covering its pods,
+// the ScaleDownDeferred condition is set then. The scale to zero isn't deferred if the PodDisruptionBudgets can't be read
+func (e *scaleExecutor) isScaleToZeroDeferred(ctx context.Context, logger logr.Logger, scaledObject *kedav1alpha1.ScaledObject, scale *autoscalingv1.Scale) bool {
+	if !scaledObject.IsCheckingPodDisruptionBudgets() {
+		return false
+	}
+
+	violated, err := kedautil.GetViolatedPodDisruptionBudgets(ctx, e.client, scaledObject.Namespace, scale.Status.Selector, 0)
+	if err != nil {
+		logger.Error(err, "Error checking the PodDisruptionBudgets of the ScaleTarget, the scale to zero isn't deferred")
+		return false
+	}
+	if len(violated) == 0 {
+		e.clearScaleToZeroDeferred(ctx, logger, scaledObject)
+		return false
+	}
+
+	msg := fmt.Sprintf("the scale to zero of the scaleTargetRef %s is deferred as it violates the PodDisruptionBudget %s",
+		scaledObject.Spec.ScaleTargetRef.Name, strings.Join(violated, ", "))
+	condition := scaledObject.Status.Conditions.GetScaleDownDeferredCondition()
+	if condition.IsTrue() && condition.Message == msg {
+		return true
+	}
+	logger.Info("ScaleTarget not scaled to 0 replicas, " + msg)
+	e.eventEmitter.Emit("ScaledObject", scaledObject.Namespace, scaledObject.Name, eventemitter.ScaleDownDeferredType, msg)
+	e.setScaleDownDeferredCondition(ctx, logger, scaledObject, metav1.ConditionTrue, scaleToZeroDeferredReason, msg)
+	return true
+}
+
+// clearScaleToZeroDeferred sets the ScaleDownDeferred condition to false if the executor deferred the scale to zero
+func (e *scaleExecutor) clearScaleToZeroDeferred(ctx context.Context, logger logr.Logger, scaledObject *kedav1alpha1.ScaledObject) {
+	condition := scaledObject.Status.Conditions.GetScaleDownDeferredCondition()
+	if condition.IsTrue() && condition.Reason == scaleToZeroDeferredReason {
+		e.setScaleDownDeferredCondition(ctx, logger, scaledObject, metav1.ConditionFalse, "NoScaleDownDeferred", "No scale down of the ScaleTarget is deferred")
+	}
+}
+
+func (e *scaleExecutor) setScaleDownDeferredCondition(ctx context.Context, logger logr.Logger, scaledObject *kedav1alpha1.ScaledObject, status metav1.ConditionStatus, reason string, message string) {
+	patch := client.MergeFrom(scaledObject.DeepCopy())
+	scaledObject.Status.Conditions.SetScaleDownDeferredCondition(status, reason, message)
+	if err := e.client.Status().Patch(ctx, scaledObject, patch); err != nil {
+		logger.Error(err, "Failed to patch Objects Status")
+	}
+}
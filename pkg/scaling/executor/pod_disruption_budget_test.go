@@ -0,0 +1,64 @@
+package executor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	corev1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+	"github.com/kedacore/keda/pkg/eventemitter"
+)
+
+func TestIsScaleToZeroDeferred(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = kedav1alpha1.AddToScheme(scheme)
+
+	minAvailable := intstr.FromInt(1)
+	pdb := &policyv1beta1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "worker"},
+		Spec: policyv1beta1.PodDisruptionBudgetSpec{
+			MinAvailable: &minAvailable,
+			Selector:     &metav1.LabelSelector{MatchLabels: map[string]string{"app": "worker"}},
+		},
+	}
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "worker-1", Labels: map[string]string{"app": "worker"}}}
+	scaledObject := &kedav1alpha1.ScaledObject{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "worker"},
+		Spec:       kedav1alpha1.ScaledObjectSpec{ScaleTargetRef: &kedav1alpha1.ScaleTarget{Name: "worker"}},
+	}
+	scaledObject.Status.Conditions = *kedav1alpha1.GetInitializedConditions()
+
+	client := fake.NewFakeClientWithScheme(scheme, pdb, pod, scaledObject)
+	scaleExecutor := &scaleExecutor{client: client, eventEmitter: eventemitter.NewEventEmitter(client), logger: logf.Log.WithName("test")}
+	scale := &autoscalingv1.Scale{Status: autoscalingv1.ScaleStatus{Replicas: 1, Selector: "app=worker"}}
+	ctx := context.TODO()
+
+	assert.True(t, scaleExecutor.isScaleToZeroDeferred(ctx, scaleExecutor.logger, scaledObject, scale))
+	condition := scaledObject.Status.Conditions.GetScaleDownDeferredCondition()
+	assert.True(t, condition.IsTrue())
+	assert.Equal(t, scaleToZeroDeferredReason, condition.Reason)
+
+	// the scale to zero isn't deferred once the check is disabled, the condition is cleared by the next active scaling
+	scaledObject.Spec.Advanced = &kedav1alpha1.AdvancedConfig{DisablePodDisruptionBudgetCheck: true}
+	assert.False(t, scaleExecutor.isScaleToZeroDeferred(ctx, scaleExecutor.logger, scaledObject, scale))
+	scaleExecutor.clearScaleToZeroDeferred(ctx, scaleExecutor.logger, scaledObject)
+	condition = scaledObject.Status.Conditions.GetScaleDownDeferredCondition()
+	assert.True(t, condition.IsFalse())
+
+	// a deferral of the controller isn't cleared by the executor
+	scaledObject.Status.Conditions.SetScaleDownDeferredCondition(metav1.ConditionTrue, "MinReplicasViolatesPDB", "")
+	scaleExecutor.clearScaleToZeroDeferred(ctx, scaleExecutor.logger, scaledObject)
+	condition = scaledObject.Status.Conditions.GetScaleDownDeferredCondition()
+	assert.True(t, condition.IsTrue())
+}
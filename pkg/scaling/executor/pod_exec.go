@@ -0,0 +1,114 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+)
+
+// maxExecStderrBytes is the size of the stderr of a command kept in its error
+const maxExecStderrBytes = 1024
+
+// podExecutor runs a command in a container of a pod, an error is returned if the command doesn't exit with 0
+type podExecutor interface {
+	Exec(ctx context.Context, namespace, pod, container string, command []string) error
+}
+
+// remotePodExecutor runs the commands through the exec subresource of the pods, its client is created on the first command
+// so the executors running no exec hook don't need a config
+type remotePodExecutor struct {
+	once      sync.Once
+	config    *rest.Config
+	clientset kubernetes.Interface
+	err       error
+}
+
+func (e *remotePodExecutor) init() error {
+	e.once.Do(func() {
+		e.config, e.err = config.GetConfig()
+		if e.err != nil {
+			return
+		}
+		e.clientset, e.err = kubernetes.NewForConfig(e.config)
+	})
+	return e.err
+}
+
+// Exec runs the command in the container. The exec streams of client-go don't take a context, the command is left running
+// in the pod once the context is done and its streams are closed when it exits
+func (e *remotePodExecutor) Exec(ctx context.Context, namespace, pod, container string, command []string) error {
+	if err := e.init(); err != nil {
+		return fmt.Errorf("error creating the exec client: %s", err)
+	}
+
+	req := e.clientset.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(namespace).
+		Name(pod).
+		SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{
+			Container: container,
+			Command:   command,
+			Stdout:    true,
+			Stderr:    true,
+		}, scheme.ParameterCodec)
+	exec, err := remotecommand.NewSPDYExecutor(e.config, http.MethodPost, req.URL())
+	if err != nil {
+		return err
+	}
+
+	stderr := &limitedBuffer{limit: maxExecStderrBytes}
+	done := make(chan error, 1)
+	go func() {
+		done <- exec.Stream(remotecommand.StreamOptions{Stdout: ioutil.Discard, Stderr: stderr})
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			if output := strings.TrimSpace(stderr.String()); output != "" {
+				return fmt.Errorf("%s: %s", err, output)
+			}
+			return err
+		}
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// limitedBuffer keeps the first bytes written to it up to its limit, the remaining ones are discarded
+type limitedBuffer struct {
+	lock   sync.Mutex
+	buffer bytes.Buffer
+	limit  int
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if remaining := b.limit - b.buffer.Len(); remaining > 0 {
+		if len(p) > remaining {
+			b.buffer.Write(p[:remaining])
+		} else {
+			b.buffer.Write(p)
+		}
+	}
+	return len(p), nil
+}
+
+func (b *limitedBuffer) String() string {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.buffer.String()
+}
@@ -0,0 +1,276 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"net/url"
+	"reflect"
+	"time"
+
+	"github.com/go-logr/logr"
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+	"github.com/kedacore/keda/pkg/eventemitter"
+	kedautil "github.com/kedacore/keda/pkg/util"
+)
+
+// Default timeout of the pre-scale-down hook of a pod
+const defaultPreScaleDownHookTimeout = 10 * time.Second
+
+// hpaTolerance is the ratio of the metrics to their targets the HPA doesn't scale within, the default of the kube-controller-manager
+const hpaTolerance = 0.1
+
+// preScaleDownHook is the hook of the annotations of a ScaledObject, an HTTP hook with its URL or an exec hook with its command
+type preScaleDownHook struct {
+	url       *url.URL
+	command   []string
+	container string
+	timeout   time.Duration
+}
+
+// parsePreScaleDownHook returns the hook of the annotations, nil if there is no hook
+func parsePreScaleDownHook(annotations map[string]string) (*preScaleDownHook, error) {
+	hookURL := annotations[kedav1alpha1.PreScaleDownHookAnnotation]
+	command := annotations[kedav1alpha1.PreScaleDownExecHookAnnotation]
+	if hookURL == "" && command == "" {
+		return nil, nil
+	}
+	if hookURL != "" && command != "" {
+		return nil, fmt.Errorf("%s and %s can't be set both", kedav1alpha1.PreScaleDownHookAnnotation, kedav1alpha1.PreScaleDownExecHookAnnotation)
+	}
+
+	hook := &preScaleDownHook{timeout: defaultPreScaleDownHookTimeout}
+	if hookURL != "" {
+		parsed, err := url.Parse(hookURL)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing %s: %s", kedav1alpha1.PreScaleDownHookAnnotation, err)
+		}
+		if parsed.Scheme != "http" && parsed.Scheme != "https" {
+			return nil, fmt.Errorf("the scheme of %s must be http or https, got %q", kedav1alpha1.PreScaleDownHookAnnotation, parsed.Scheme)
+		}
+		hook.url = parsed
+	} else {
+		hook.command = []string{"/bin/sh", "-c", command}
+		hook.container = annotations[kedav1alpha1.PreScaleDownExecContainerAnnotation]
+	}
+
+	if val, ok := annotations[kedav1alpha1.PreScaleDownHookTimeoutAnnotation]; ok && val != "" {
+		timeout, err := time.ParseDuration(val)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing %s: %s", kedav1alpha1.PreScaleDownHookTimeoutAnnotation, err)
+		}
+		if timeout <= 0 {
+			return nil, fmt.Errorf("%s must be greater than 0", kedav1alpha1.PreScaleDownHookTimeoutAnnotation)
+		}
+		hook.timeout = timeout
+	}
+	return hook, nil
+}
+
+// podURL returns the URL of the hook with the host replaced by the IP of the pod, the port of the hook is kept
+func (h *preScaleDownHook) podURL(podIP string) string {
+	podURL := *h.url
+	if port := h.url.Port(); port != "" {
+		podURL.Host = net.JoinHostPort(podIP, port)
+	} else {
+		podURL.Host = podIP
+	}
+	return podURL.String()
+}
+
+// callPreScaleDownHook sends the POST request of the HTTP hook to the pod, the response must have a 2xx status within the timeout,
+// or runs the command of the exec hook in the container of the pod, it must exit with 0 within the timeout
+func (e *scaleExecutor) callPreScaleDownHook(ctx context.Context, hook *preScaleDownHook, pod *corev1.Pod) error {
+	if hook.url != nil {
+		return postHook(ctx, hook.podURL(pod.Status.PodIP), nil, hook.timeout)
+	}
+
+	container := hook.container
+	if container == "" {
+		if len(pod.Spec.Containers) == 0 {
+			return fmt.Errorf("pod %s has no container", pod.Name)
+		}
+		container = pod.Spec.Containers[0].Name
+	}
+	ctx, cancel := context.WithTimeout(ctx, hook.timeout)
+	defer cancel()
+	return e.podExecutor.Exec(ctx, pod.Namespace, pod.Name, container, hook.command)
+}
+
+// isStatefulSet returns true if the ScaleTarget of the ScaledObject is a StatefulSet, its pods are then named after their ordinal
+func isStatefulSet(scaledObject *kedav1alpha1.ScaledObject) bool {
+	gvkr := scaledObject.Status.ScaleTargetGVKR
+	return gvkr != nil && gvkr.Group == "apps" && gvkr.Kind == "StatefulSet"
+}
+
+// getUnhookedReplicas returns the replica count of the StatefulSet whose pods didn't run their pre-scale-down hook yet,
+// the pods above the replica count the HPA is held at already ran it
+func getUnhookedReplicas(scaledObject *kedav1alpha1.ScaledObject, currentReplicas int32) int32 {
+	if hold := scaledObject.Status.PreScaleDownHookMinReplicas; hold != nil && *hold < currentReplicas {
+		return *hold
+	}
+	return currentReplicas
+}
+
+// runPreScaleDownHooks calls the pre-scale-down hook of the ScaledObject on the pods of its StatefulSet removed by scaling from
+// currentReplicas to replicas, from the highest ordinal as the StatefulSet removes them. The pods not found or without IP are skipped,
+// an error is returned if a hook fails so the scale down is postponed to the next check
+func (e *scaleExecutor) runPreScaleDownHooks(ctx context.Context, logger logr.Logger, scaledObject *kedav1alpha1.ScaledObject, currentReplicas, replicas int32) error {
+	if !isStatefulSet(scaledObject) {
+		return nil
+	}
+	hook, err := parsePreScaleDownHook(scaledObject.Annotations)
+	if err != nil || hook == nil {
+		return err
+	}
+
+	for ordinal := currentReplicas - 1; ordinal >= replicas; ordinal-- {
+		podName := fmt.Sprintf("%s-%d", scaledObject.Spec.ScaleTargetRef.Name, ordinal)
+		pod := &corev1.Pod{}
+		if err := e.client.Get(ctx, types.NamespacedName{Namespace: scaledObject.Namespace, Name: podName}, pod); err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return fmt.Errorf("error getting pod %s: %s", podName, err)
+		}
+		if pod.Status.PodIP == "" || pod.DeletionTimestamp != nil {
+			continue
+		}
+
+		logger.V(1).Info("Calling the pre-scale-down hook", "pod", podName)
+		if err := e.callPreScaleDownHook(ctx, hook, pod); err != nil {
+			return fmt.Errorf("pre-scale-down hook of pod %s failed: %s", podName, err)
+		}
+	}
+	return nil
+}
+
+// runHPAPreScaleDownHooks holds the HPA of the ScaledObject from scaling its StatefulSet below the pods that didn't run their pre-scale-down hook.
+// The MinReplicas of the HPA is raised to the current replica count, once the metrics of the HPA call for fewer replicas the hooks of the pods
+// the HPA removes are called and the MinReplicas is lowered to the replica count of the HPA. The hold is released once the hook is removed.
+func (e *scaleExecutor) runHPAPreScaleDownHooks(ctx context.Context, logger logr.Logger, scaledObject *kedav1alpha1.ScaledObject, currentReplicas int32) {
+	if !isStatefulSet(scaledObject) {
+		return
+	}
+	hook, err := parsePreScaleDownHook(scaledObject.Annotations)
+	if err != nil {
+		logger.Error(err, "Error parsing the pre-scale-down hook, the HPA is left as is")
+		return
+	}
+
+	var hold *int32
+	if hook != nil && currentReplicas > 0 {
+		replicas := e.getHPAScaleDownReplicas(ctx, logger, scaledObject, currentReplicas)
+		if unhooked := getUnhookedReplicas(scaledObject, currentReplicas); replicas < unhooked {
+			if err := e.runPreScaleDownHooks(ctx, logger, scaledObject, unhooked, replicas); err != nil {
+				logger.Error(err, "The HPA isn't allowed to scale the StatefulSet down, the scale down is retried on the next check")
+				e.eventEmitter.Emit("ScaledObject", scaledObject.Namespace, scaledObject.Name, eventemitter.PreScaleDownHookFailedType, err.Error())
+				replicas = unhooked
+			}
+		}
+		hold = &replicas
+	}
+	if reflect.DeepEqual(hold, scaledObject.Status.PreScaleDownHookMinReplicas) {
+		return
+	}
+
+	patch := client.MergeFrom(scaledObject.DeepCopy())
+	scaledObject.Status.PreScaleDownHookMinReplicas = hold
+	if err := e.client.Status().Patch(ctx, scaledObject, patch); err != nil {
+		logger.Error(err, "Failed to patch Objects Status")
+		return
+	}
+	if err := kedautil.UpdateHPAMinReplicas(ctx, e.client, scaledObject); err != nil {
+		logger.Error(err, "Failed to update the MinReplicas of the HPA for the pre-scale-down hook")
+	}
+}
+
+// getHPAScaleDownReplicas returns the replica count the metrics of the HPA of the ScaledObject call for if it's below the current replica count,
+// not below the minReplicaCount of the ScaledObject. The current replica count is returned if the HPA isn't scaling down or can't be read
+func (e *scaleExecutor) getHPAScaleDownReplicas(ctx context.Context, logger logr.Logger, scaledObject *kedav1alpha1.ScaledObject, currentReplicas int32) int32 {
+	hpa := &autoscalingv2beta2.HorizontalPodAutoscaler{}
+	if err := e.client.Get(ctx, types.NamespacedName{Namespace: scaledObject.Namespace, Name: kedautil.GetHPAName(scaledObject)}, hpa); err != nil {
+		if !errors.IsNotFound(err) {
+			logger.Error(err, "Error getting the HPA, the StatefulSet isn't scaled down")
+		}
+		return currentReplicas
+	}
+
+	replicas, ok := getHPADesiredReplicas(hpa)
+	if !ok || replicas >= currentReplicas {
+		return currentReplicas
+	}
+	minReplicas := kedautil.DefaultHPAMinReplicas
+	if scaledObject.Spec.MinReplicaCount != nil && *scaledObject.Spec.MinReplicaCount > 0 {
+		minReplicas = *scaledObject.Spec.MinReplicaCount
+	}
+	if replicas < minReplicas {
+		replicas = minReplicas
+	}
+	return replicas
+}
+
+// getHPADesiredReplicas returns the replica count the HPA computes from the current values of its metrics before applying its MinReplicas,
+// the highest one of its metrics within its MaxReplicas. It returns false if the value of a metric is missing, the HPA doesn't scale down then
+func getHPADesiredReplicas(hpa *autoscalingv2beta2.HorizontalPodAutoscaler) (int32, bool) {
+	currentReplicas := hpa.Status.CurrentReplicas
+	if currentReplicas <= 0 || len(hpa.Spec.Metrics) == 0 {
+		return 0, false
+	}
+
+	var desiredReplicas int32
+	for _, metric := range hpa.Spec.Metrics {
+		ratio, ok := getHPAMetricRatio(metric, hpa.Status.CurrentMetrics)
+		if !ok {
+			return 0, false
+		}
+		replicas := currentReplicas
+		if math.Abs(ratio-1) > hpaTolerance {
+			replicas = int32(math.Ceil(ratio * float64(currentReplicas)))
+		}
+		if replicas > desiredReplicas {
+			desiredReplicas = replicas
+		}
+	}
+	if desiredReplicas > hpa.Spec.MaxReplicas {
+		desiredReplicas = hpa.Spec.MaxReplicas
+	}
+	return desiredReplicas, true
+}
+
+// getHPAMetricRatio returns the ratio of the current value of the External or Resource metric of the HPA to its target
+func getHPAMetricRatio(metric autoscalingv2beta2.MetricSpec, statuses []autoscalingv2beta2.MetricStatus) (float64, bool) {
+	for _, status := range statuses {
+		switch {
+		case metric.Type == autoscalingv2beta2.ExternalMetricSourceType && status.Type == autoscalingv2beta2.ExternalMetricSourceType &&
+			metric.External != nil && status.External != nil && metric.External.Metric.Name == status.External.Metric.Name:
+			return getMetricTargetRatio(metric.External.Target, status.External.Current)
+		case metric.Type == autoscalingv2beta2.ResourceMetricSourceType && status.Type == autoscalingv2beta2.ResourceMetricSourceType &&
+			metric.Resource != nil && status.Resource != nil && metric.Resource.Name == status.Resource.Name:
+			return getMetricTargetRatio(metric.Resource.Target, status.Resource.Current)
+		}
+	}
+	return 0, false
+}
+
+// getMetricTargetRatio returns the ratio of the current value of a metric to its target value, average value or average utilization
+func getMetricTargetRatio(target autoscalingv2beta2.MetricTarget, current autoscalingv2beta2.MetricValueStatus) (float64, bool) {
+	switch {
+	case target.Type == autoscalingv2beta2.UtilizationMetricType && target.AverageUtilization != nil && *target.AverageUtilization > 0 &&
+		current.AverageUtilization != nil:
+		return float64(*current.AverageUtilization) / float64(*target.AverageUtilization), true
+	case target.Type == autoscalingv2beta2.AverageValueMetricType && target.AverageValue != nil && target.AverageValue.MilliValue() > 0 &&
+		current.AverageValue != nil:
+		return float64(current.AverageValue.MilliValue()) / float64(target.AverageValue.MilliValue()), true
+	case target.Type == autoscalingv2beta2.ValueMetricType && target.Value != nil && target.Value.MilliValue() > 0 && current.Value != nil:
+		return float64(current.Value.MilliValue()) / float64(target.Value.MilliValue()), true
+	}
+	return 0, false
+}
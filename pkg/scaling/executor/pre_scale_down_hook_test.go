@@ -0,0 +1,294 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+	"github.com/kedacore/keda/pkg/eventemitter"
+	"github.com/kedacore/keda/pkg/mock/mock_client"
+)
+
+func TestParsePreScaleDownHook(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		podURL      string
+		timeout     string
+		isError     bool
+	}{
+		{"no hook", map[string]string{}, "", "", false},
+		{"hook", map[string]string{kedav1alpha1.PreScaleDownHookAnnotation: "http://:8080/drain"}, "http://10.0.0.1:8080/drain", "10s", false},
+		{"hook without port", map[string]string{kedav1alpha1.PreScaleDownHookAnnotation: "https://pod/drain?all=true"}, "https://10.0.0.1/drain?all=true", "10s", false},
+		{"timeout", map[string]string{kedav1alpha1.PreScaleDownHookAnnotation: "http://:8080/drain", kedav1alpha1.PreScaleDownHookTimeoutAnnotation: "1m"}, "http://10.0.0.1:8080/drain", "1m0s", false},
+		{"invalid scheme", map[string]string{kedav1alpha1.PreScaleDownHookAnnotation: "tcp://:8080"}, "", "", true},
+		{"invalid timeout", map[string]string{kedav1alpha1.PreScaleDownHookAnnotation: "http://:8080/drain", kedav1alpha1.PreScaleDownHookTimeoutAnnotation: "30"}, "", "", true},
+		{"zero timeout", map[string]string{kedav1alpha1.PreScaleDownHookAnnotation: "http://:8080/drain", kedav1alpha1.PreScaleDownHookTimeoutAnnotation: "0s"}, "", "", true},
+		{"http and exec hooks", map[string]string{kedav1alpha1.PreScaleDownHookAnnotation: "http://:8080/drain", kedav1alpha1.PreScaleDownExecHookAnnotation: "drain"}, "", "", true},
+	}
+
+	for _, test := range tests {
+		hook, err := parsePreScaleDownHook(test.annotations)
+		if test.isError {
+			assert.Error(t, err, test.name)
+			continue
+		}
+		assert.NoError(t, err, test.name)
+		if test.podURL == "" {
+			assert.Nil(t, hook, test.name)
+			continue
+		}
+		assert.Equal(t, test.podURL, hook.podURL("10.0.0.1"), test.name)
+		assert.Equal(t, test.timeout, hook.timeout.String(), test.name)
+	}
+
+	hook, err := parsePreScaleDownHook(map[string]string{kedav1alpha1.PreScaleDownExecHookAnnotation: "/opt/drain.sh --all", kedav1alpha1.PreScaleDownExecContainerAnnotation: "consumer"})
+	assert.NoError(t, err)
+	assert.Nil(t, hook.url)
+	assert.Equal(t, []string{"/bin/sh", "-c", "/opt/drain.sh --all"}, hook.command)
+	assert.Equal(t, "consumer", hook.container)
+	assert.Equal(t, defaultPreScaleDownHookTimeout, hook.timeout)
+}
+
+func TestRunPreScaleDownHooks(t *testing.T) {
+	var mutex sync.Mutex
+	var drained []string
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		assert.Equal(t, http.MethodPost, r.Method)
+		drained = append(drained, r.URL.Path)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+	serverURL, _ := url.Parse(server.URL)
+	_, port, _ := net.SplitHostPort(serverURL.Host)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	client := mock_client.NewMockClient(ctrl)
+	var requested []string
+	client.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, key types.NamespacedName, obj runtime.Object) error {
+		requested = append(requested, key.Name)
+		if key.Name == "kafka-consumer-1" {
+			return errors.NewNotFound(schema.GroupResource{Resource: "pods"}, key.Name)
+		}
+		pod := obj.(*corev1.Pod)
+		pod.Name = key.Name
+		pod.Status.PodIP = "127.0.0.1"
+		return nil
+	}).AnyTimes()
+	scaleExecutor := getMockScaleExecutor(client)
+	logger := logf.Log.WithName("test")
+
+	scaledObject := &kedav1alpha1.ScaledObject{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "kafka-consumer",
+			Namespace: "default",
+			Annotations: map[string]string{
+				kedav1alpha1.PreScaleDownHookAnnotation: "http://:" + port + "/drain",
+			},
+		},
+		Spec: kedav1alpha1.ScaledObjectSpec{ScaleTargetRef: &kedav1alpha1.ScaleTarget{Name: "kafka-consumer"}},
+	}
+
+	// not a StatefulSet
+	assert.NoError(t, scaleExecutor.runPreScaleDownHooks(context.TODO(), logger, scaledObject, 3, 0))
+	assert.Empty(t, drained)
+
+	// the pods are drained from the highest ordinal, the pods not found are skipped
+	scaledObject.Status.ScaleTargetGVKR = &kedav1alpha1.GroupVersionKindResource{Group: "apps", Version: "v1", Kind: "StatefulSet", Resource: "statefulsets"}
+	assert.NoError(t, scaleExecutor.runPreScaleDownHooks(context.TODO(), logger, scaledObject, 3, 0))
+	assert.Equal(t, []string{"kafka-consumer-2", "kafka-consumer-1", "kafka-consumer-0"}, requested)
+	assert.Equal(t, []string{"/drain", "/drain"}, drained)
+
+	// a failed hook postpones the scale down
+	status = http.StatusServiceUnavailable
+	drained = nil
+	requested = nil
+	assert.Error(t, scaleExecutor.runPreScaleDownHooks(context.TODO(), logger, scaledObject, 3, 0))
+	assert.Equal(t, []string{"kafka-consumer-2"}, requested)
+	assert.Len(t, drained, 1)
+}
+
+// recordingPodExecutor records the commands run in the pods and fails them with err
+type recordingPodExecutor struct {
+	commands []string
+	err      error
+}
+
+func (e *recordingPodExecutor) Exec(ctx context.Context, namespace, pod, container string, command []string) error {
+	e.commands = append(e.commands, namespace+"/"+pod+"/"+container+": "+command[len(command)-1])
+	return e.err
+}
+
+func newTestHPA(currentReplicas int32, averageValue int64) *autoscalingv2beta2.HorizontalPodAutoscaler {
+	return &autoscalingv2beta2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "keda-hpa-kafka-consumer"},
+		Spec: autoscalingv2beta2.HorizontalPodAutoscalerSpec{
+			MaxReplicas: 10,
+			Metrics: []autoscalingv2beta2.MetricSpec{{
+				Type: autoscalingv2beta2.ExternalMetricSourceType,
+				External: &autoscalingv2beta2.ExternalMetricSource{
+					Metric: autoscalingv2beta2.MetricIdentifier{Name: "s0-kafka-orders"},
+					Target: autoscalingv2beta2.MetricTarget{Type: autoscalingv2beta2.AverageValueMetricType, AverageValue: resource.NewQuantity(10, resource.DecimalSI)},
+				},
+			}},
+		},
+		Status: autoscalingv2beta2.HorizontalPodAutoscalerStatus{
+			CurrentReplicas: currentReplicas,
+			CurrentMetrics: []autoscalingv2beta2.MetricStatus{{
+				Type: autoscalingv2beta2.ExternalMetricSourceType,
+				External: &autoscalingv2beta2.ExternalMetricStatus{
+					Metric:  autoscalingv2beta2.MetricIdentifier{Name: "s0-kafka-orders"},
+					Current: autoscalingv2beta2.MetricValueStatus{AverageValue: resource.NewQuantity(averageValue, resource.DecimalSI)},
+				},
+			}},
+		},
+	}
+}
+
+func TestGetHPADesiredReplicas(t *testing.T) {
+	utilization := func(value int32) *int32 { return &value }
+	resourceHPA := &autoscalingv2beta2.HorizontalPodAutoscaler{
+		Spec: autoscalingv2beta2.HorizontalPodAutoscalerSpec{
+			MaxReplicas: 10,
+			Metrics: []autoscalingv2beta2.MetricSpec{{
+				Type: autoscalingv2beta2.ResourceMetricSourceType,
+				Resource: &autoscalingv2beta2.ResourceMetricSource{
+					Name:   corev1.ResourceCPU,
+					Target: autoscalingv2beta2.MetricTarget{Type: autoscalingv2beta2.UtilizationMetricType, AverageUtilization: utilization(80)},
+				},
+			}},
+		},
+		Status: autoscalingv2beta2.HorizontalPodAutoscalerStatus{
+			CurrentReplicas: 4,
+			CurrentMetrics: []autoscalingv2beta2.MetricStatus{{
+				Type: autoscalingv2beta2.ResourceMetricSourceType,
+				Resource: &autoscalingv2beta2.ResourceMetricStatus{
+					Name:    corev1.ResourceCPU,
+					Current: autoscalingv2beta2.MetricValueStatus{AverageUtilization: utilization(40)},
+				},
+			}},
+		},
+	}
+	missingHPA := newTestHPA(3, 10)
+	missingHPA.Status.CurrentMetrics = nil
+
+	tests := []struct {
+		name     string
+		hpa      *autoscalingv2beta2.HorizontalPodAutoscaler
+		replicas int32
+		ok       bool
+	}{
+		{"average value", newTestHPA(4, 5), 2, true},
+		{"within the tolerance", newTestHPA(4, 9), 4, true},
+		{"scale up to the max replicas", newTestHPA(4, 100), 10, true},
+		{"utilization", resourceHPA, 2, true},
+		{"missing metric", missingHPA, 0, false},
+		{"no replicas", newTestHPA(0, 5), 0, false},
+	}
+	for _, test := range tests {
+		replicas, ok := getHPADesiredReplicas(test.hpa)
+		assert.Equal(t, test.ok, ok, test.name)
+		assert.Equal(t, test.replicas, replicas, test.name)
+	}
+}
+
+func TestRunHPAPreScaleDownHooks(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = kedav1alpha1.AddToScheme(scheme)
+	scaledObject := &kedav1alpha1.ScaledObject{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "kafka-consumer",
+			Namespace:   "default",
+			Annotations: map[string]string{kedav1alpha1.PreScaleDownExecHookAnnotation: "/opt/drain.sh"},
+		},
+		Spec: kedav1alpha1.ScaledObjectSpec{ScaleTargetRef: &kedav1alpha1.ScaleTarget{Name: "kafka-consumer"}},
+		Status: kedav1alpha1.ScaledObjectStatus{
+			ScaleTargetGVKR: &kedav1alpha1.GroupVersionKindResource{Group: "apps", Version: "v1", Kind: "StatefulSet", Resource: "statefulsets"},
+		},
+	}
+	objects := []runtime.Object{scaledObject, newTestHPA(3, 10)}
+	for i := 0; i < 3; i++ {
+		objects = append(objects, &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: fmt.Sprintf("kafka-consumer-%d", i)},
+			Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "consumer"}, {Name: "sidecar"}}},
+			Status:     corev1.PodStatus{PodIP: "10.0.0.1"},
+		})
+	}
+	client := fake.NewFakeClientWithScheme(scheme, objects...)
+	podExecutor := &recordingPodExecutor{}
+	scaleExecutor := &scaleExecutor{client: client, eventEmitter: eventemitter.NewEventEmitter(client), podExecutor: podExecutor, logger: logf.Log.WithName("test")}
+	ctx := context.TODO()
+
+	hpaMinReplicas := func() int32 {
+		hpa := &autoscalingv2beta2.HorizontalPodAutoscaler{}
+		assert.NoError(t, client.Get(ctx, types.NamespacedName{Namespace: "default", Name: "keda-hpa-kafka-consumer"}, hpa))
+		if hpa.Spec.MinReplicas == nil {
+			return 0
+		}
+		return *hpa.Spec.MinReplicas
+	}
+	setHPAMetric := func(averageValue int64) {
+		hpa := &autoscalingv2beta2.HorizontalPodAutoscaler{}
+		assert.NoError(t, client.Get(ctx, types.NamespacedName{Namespace: "default", Name: "keda-hpa-kafka-consumer"}, hpa))
+		hpa.Status = newTestHPA(3, averageValue).Status
+		assert.NoError(t, client.Update(ctx, hpa))
+	}
+
+	// the HPA isn't scaling down, it is held at the current replica count
+	scaleExecutor.runHPAPreScaleDownHooks(ctx, scaleExecutor.logger, scaledObject, 3)
+	assert.Equal(t, int32(3), *scaledObject.Status.PreScaleDownHookMinReplicas)
+	assert.Equal(t, int32(3), hpaMinReplicas())
+	assert.Empty(t, podExecutor.commands)
+
+	// a failed hook keeps the HPA from scaling down
+	setHPAMetric(3)
+	podExecutor.err = fmt.Errorf("command terminated with exit code 1")
+	scaleExecutor.runHPAPreScaleDownHooks(ctx, scaleExecutor.logger, scaledObject, 3)
+	assert.Equal(t, []string{"default/kafka-consumer-2/consumer: /opt/drain.sh"}, podExecutor.commands)
+	assert.Equal(t, int32(3), hpaMinReplicas())
+
+	// the hooks of the pods removed by the HPA ran, from the highest ordinal, the HPA can scale down
+	podExecutor.err = nil
+	podExecutor.commands = nil
+	scaleExecutor.runHPAPreScaleDownHooks(ctx, scaleExecutor.logger, scaledObject, 3)
+	assert.Equal(t, []string{"default/kafka-consumer-2/consumer: /opt/drain.sh", "default/kafka-consumer-1/consumer: /opt/drain.sh"}, podExecutor.commands)
+	assert.Equal(t, int32(1), *scaledObject.Status.PreScaleDownHookMinReplicas)
+	assert.Equal(t, int32(1), hpaMinReplicas())
+
+	// the hooks aren't called again until the HPA removes the pods
+	podExecutor.commands = nil
+	scaleExecutor.runHPAPreScaleDownHooks(ctx, scaleExecutor.logger, scaledObject, 3)
+	assert.Empty(t, podExecutor.commands)
+	assert.Equal(t, int32(1), hpaMinReplicas())
+
+	// the hold is released once the hook is removed
+	delete(scaledObject.Annotations, kedav1alpha1.PreScaleDownExecHookAnnotation)
+	scaleExecutor.runHPAPreScaleDownHooks(ctx, scaleExecutor.logger, scaledObject, 1)
+	assert.Nil(t, scaledObject.Status.PreScaleDownHookMinReplicas)
+	assert.Equal(t, int32(1), hpaMinReplicas())
+}
@@ -0,0 +1,59 @@
+package executor
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+)
+
+// isRollingOut returns true if the Deployment, StatefulSet or Argo Rollout hasn't completed the rollout of its pods,
+// the other kinds of ScaleTarget are never rolling out
+func isRollingOut(gvkr *kedav1alpha1.GroupVersionKindResource, target *unstructured.Unstructured) bool {
+	generation, _, _ := unstructured.NestedInt64(target.Object, "metadata", "generation")
+	observedGeneration, _, _ := unstructured.NestedInt64(target.Object, "status", "observedGeneration")
+	specReplicas, found, _ := unstructured.NestedInt64(target.Object, "spec", "replicas")
+	if !found {
+		specReplicas = 1
+	}
+	replicas, _, _ := unstructured.NestedInt64(target.Object, "status", "replicas")
+	updatedReplicas, _, _ := unstructured.NestedInt64(target.Object, "status", "updatedReplicas")
+
+	switch {
+	case gvkr.Group == "apps" && gvkr.Kind == "Deployment":
+		// as kubectl rollout status, the new pods must be available and the old ones removed
+		availableReplicas, _, _ := unstructured.NestedInt64(target.Object, "status", "availableReplicas")
+		return generation > observedGeneration || updatedReplicas < specReplicas || replicas > updatedReplicas || availableReplicas < updatedReplicas
+	case gvkr.Group == "apps" && gvkr.Kind == "StatefulSet":
+		currentRevision, _, _ := unstructured.NestedString(target.Object, "status", "currentRevision")
+		updateRevision, _, _ := unstructured.NestedString(target.Object, "status", "updateRevision")
+		readyReplicas, _, _ := unstructured.NestedInt64(target.Object, "status", "readyReplicas")
+		return generation > observedGeneration || (updateRevision != "" && currentRevision != updateRevision) || readyReplicas < specReplicas
+	case gvkr.Group == "argoproj.io" && gvkr.Kind == "Rollout":
+		// the Rollout is stable once the pod template hash is the one of its stable ReplicaSet, whatever its strategy
+		stableRS, _, _ := unstructured.NestedString(target.Object, "status", "stableRS")
+		currentPodHash, _, _ := unstructured.NestedString(target.Object, "status", "currentPodHash")
+		return stableRS != currentPodHash || updatedReplicas < specReplicas
+	default:
+		return false
+	}
+}
+
+// isScaleTargetRollingOut returns true if the ScaledObject is kept active during the rollouts and its ScaleTarget is rolling out,
+// the ScaleTarget isn't considered rolling out if it can't be read
+func (e *scaleExecutor) isScaleTargetRollingOut(ctx context.Context, logger logr.Logger, scaledObject *kedav1alpha1.ScaledObject) bool {
+	if !scaledObject.Spec.ActiveDuringRollout || scaledObject.Status.ScaleTargetGVKR == nil {
+		return false
+	}
+
+	target := &unstructured.Unstructured{}
+	target.SetGroupVersionKind(scaledObject.Status.ScaleTargetGVKR.GroupVersionKind())
+	if err := e.client.Get(ctx, types.NamespacedName{Namespace: scaledObject.Namespace, Name: scaledObject.Spec.ScaleTargetRef.Name}, target); err != nil {
+		logger.Error(err, "Error getting the ScaleTarget, it isn't kept active for its rollout")
+		return false
+	}
+	return isRollingOut(scaledObject.Status.ScaleTargetGVKR, target)
+}
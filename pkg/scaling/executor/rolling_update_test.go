@@ -0,0 +1,46 @@
+package executor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+)
+
+func TestIsRollingOut(t *testing.T) {
+	deployment := &kedav1alpha1.GroupVersionKindResource{Group: "apps", Version: "v1", Kind: "Deployment", Resource: "deployments"}
+	statefulSet := &kedav1alpha1.GroupVersionKindResource{Group: "apps", Version: "v1", Kind: "StatefulSet", Resource: "statefulsets"}
+	rollout := &kedav1alpha1.GroupVersionKindResource{Group: "argoproj.io", Version: "v1alpha1", Kind: "Rollout", Resource: "rollouts"}
+	other := &kedav1alpha1.GroupVersionKindResource{Group: "example.com", Version: "v1", Kind: "Worker", Resource: "workers"}
+
+	newTarget := func(generation int64, specReplicas int64, status map[string]interface{}) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"metadata": map[string]interface{}{"generation": generation},
+			"spec":     map[string]interface{}{"replicas": specReplicas},
+			"status":   status,
+		}}
+	}
+
+	tests := []struct {
+		name       string
+		gvkr       *kedav1alpha1.GroupVersionKindResource
+		target     *unstructured.Unstructured
+		rollingOut bool
+	}{
+		{"deployment rolled out", deployment, newTarget(2, 3, map[string]interface{}{"observedGeneration": int64(2), "replicas": int64(3), "updatedReplicas": int64(3), "availableReplicas": int64(3)}), false},
+		{"deployment not observed", deployment, newTarget(3, 3, map[string]interface{}{"observedGeneration": int64(2), "replicas": int64(3), "updatedReplicas": int64(3), "availableReplicas": int64(3)}), true},
+		{"deployment with old pods", deployment, newTarget(2, 3, map[string]interface{}{"observedGeneration": int64(2), "replicas": int64(4), "updatedReplicas": int64(3), "availableReplicas": int64(3)}), true},
+		{"deployment with new pods not available", deployment, newTarget(2, 3, map[string]interface{}{"observedGeneration": int64(2), "replicas": int64(3), "updatedReplicas": int64(3), "availableReplicas": int64(2)}), true},
+		{"statefulset rolled out", statefulSet, newTarget(2, 3, map[string]interface{}{"observedGeneration": int64(2), "readyReplicas": int64(3), "currentRevision": "app-1", "updateRevision": "app-1"}), false},
+		{"statefulset updating", statefulSet, newTarget(2, 3, map[string]interface{}{"observedGeneration": int64(2), "readyReplicas": int64(3), "currentRevision": "app-1", "updateRevision": "app-2"}), true},
+		{"rollout stable", rollout, newTarget(2, 3, map[string]interface{}{"stableRS": "abc", "currentPodHash": "abc", "updatedReplicas": int64(3)}), false},
+		{"rollout updating", rollout, newTarget(2, 3, map[string]interface{}{"stableRS": "abc", "currentPodHash": "def", "updatedReplicas": int64(1)}), true},
+		{"other kind", other, newTarget(3, 3, map[string]interface{}{"observedGeneration": int64(2)}), false},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.rollingOut, isRollingOut(test.gvkr, test.target), test.name)
+	}
+}
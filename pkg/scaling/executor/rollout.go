@@ -0,0 +1,92 @@
+package executor
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+	kedautil "github.com/kedacore/keda/pkg/util"
+)
+
+// isArgoRollout returns true if the ScaleTarget of the ScaledObject is an Argo Rollout
+func isArgoRollout(scaledObject *kedav1alpha1.ScaledObject) bool {
+	gvkr := scaledObject.Status.ScaleTargetGVKR
+	return gvkr != nil && gvkr.Group == "argoproj.io" && gvkr.Kind == "Rollout"
+}
+
+// getRolloutCanary returns the replica split of the Rollout if its canary is in progress, nil otherwise. The canary is in progress
+// when the pod template hash of the Rollout isn't the one of its stable ReplicaSet, the updated replicas are the canary ones.
+func getRolloutCanary(rollout *unstructured.Unstructured) *kedav1alpha1.RolloutCanaryStatus {
+	if _, found, _ := unstructured.NestedMap(rollout.Object, "spec", "strategy", "canary"); !found {
+		return nil
+	}
+	if aborted, _, _ := unstructured.NestedBool(rollout.Object, "status", "abort"); aborted {
+		return nil
+	}
+	stableRS, _, _ := unstructured.NestedString(rollout.Object, "status", "stableRS")
+	currentPodHash, _, _ := unstructured.NestedString(rollout.Object, "status", "currentPodHash")
+	if stableRS == "" || currentPodHash == "" || stableRS == currentPodHash {
+		return nil
+	}
+
+	replicas, _, _ := unstructured.NestedInt64(rollout.Object, "status", "replicas")
+	updatedReplicas, _, _ := unstructured.NestedInt64(rollout.Object, "status", "updatedReplicas")
+	stableReplicas := replicas - updatedReplicas
+	if stableReplicas < 0 {
+		stableReplicas = 0
+	}
+	return &kedav1alpha1.RolloutCanaryStatus{
+		StableReplicas: int32(stableReplicas),
+		CanaryReplicas: int32(updatedReplicas),
+	}
+}
+
+// updateRolloutCanary keeps the canary of the Argo Rollout ScaleTarget of the ScaledObject in its status and the HPA from scaling the Rollout
+// down while the canary is in progress. The Rollout splits its replica count between its stable and canary ReplicaSets on each step of the
+// canary, a scale down in the middle of the steps would shrink the stable ReplicaSet the canary is analyzed against. The MinReplicas of the HPA
+// is raised to the largest replica count of the Rollout during the canary and restored once it completes. It returns true if a canary is in progress.
+func (e *scaleExecutor) updateRolloutCanary(ctx context.Context, logger logr.Logger, scaledObject *kedav1alpha1.ScaledObject, currentReplicas int32) bool {
+	if !isArgoRollout(scaledObject) {
+		return false
+	}
+
+	rollout := &unstructured.Unstructured{}
+	rollout.SetGroupVersionKind(scaledObject.Status.ScaleTargetGVKR.GroupVersionKind())
+	if err := e.client.Get(ctx, types.NamespacedName{Namespace: scaledObject.Namespace, Name: scaledObject.Spec.ScaleTargetRef.Name}, rollout); err != nil {
+		logger.Error(err, "Error getting the Rollout, its canary is left as is")
+		return scaledObject.Status.RolloutCanary != nil
+	}
+
+	canary := getRolloutCanary(rollout)
+	if canary != nil {
+		canary.MinReplicas = currentReplicas
+		if previous := scaledObject.Status.RolloutCanary; previous != nil && previous.MinReplicas > canary.MinReplicas {
+			canary.MinReplicas = previous.MinReplicas
+		}
+	}
+	if reflect.DeepEqual(canary, scaledObject.Status.RolloutCanary) {
+		return canary != nil
+	}
+
+	if canary != nil && scaledObject.Status.RolloutCanary == nil {
+		logger.Info("The canary of the Rollout is in progress, the Rollout isn't scaled down until it completes", "MinReplicas", canary.MinReplicas)
+	} else if canary == nil {
+		logger.Info("The canary of the Rollout completed, the Rollout can be scaled down")
+	}
+
+	patch := client.MergeFrom(scaledObject.DeepCopy())
+	scaledObject.Status.RolloutCanary = canary
+	if err := e.client.Status().Patch(ctx, scaledObject, patch); err != nil {
+		logger.Error(err, "Failed to patch Objects Status")
+	}
+
+	if err := kedautil.UpdateHPAMinReplicas(ctx, e.client, scaledObject); err != nil {
+		logger.Error(err, "Failed to update the MinReplicas of the HPA for the canary of the Rollout")
+	}
+	return canary != nil
+}
@@ -0,0 +1,112 @@
+package executor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+	"github.com/kedacore/keda/pkg/mock/mock_client"
+)
+
+func newTestRollout(canary bool, stableRS, currentPodHash string, replicas, updatedReplicas int64, abort bool) map[string]interface{} {
+	strategy := map[string]interface{}{"blueGreen": map[string]interface{}{}}
+	if canary {
+		strategy = map[string]interface{}{"canary": map[string]interface{}{}}
+	}
+	return map[string]interface{}{
+		"spec": map[string]interface{}{"strategy": strategy},
+		"status": map[string]interface{}{
+			"stableRS":        stableRS,
+			"currentPodHash":  currentPodHash,
+			"replicas":        replicas,
+			"updatedReplicas": updatedReplicas,
+			"abort":           abort,
+		},
+	}
+}
+
+func TestGetRolloutCanary(t *testing.T) {
+	tests := []struct {
+		name    string
+		rollout map[string]interface{}
+		canary  *kedav1alpha1.RolloutCanaryStatus
+	}{
+		{"stable", newTestRollout(true, "abc", "abc", 4, 4, false), nil},
+		{"canary in progress", newTestRollout(true, "abc", "def", 5, 1, false), &kedav1alpha1.RolloutCanaryStatus{StableReplicas: 4, CanaryReplicas: 1}},
+		{"canary aborted", newTestRollout(true, "abc", "def", 5, 1, true), nil},
+		{"blue green", newTestRollout(false, "abc", "def", 5, 1, false), nil},
+		{"not reconciled yet", newTestRollout(true, "", "def", 0, 0, false), nil},
+	}
+
+	for _, test := range tests {
+		canary := getRolloutCanary(&unstructured.Unstructured{Object: test.rollout})
+		assert.Equal(t, test.canary, canary, test.name)
+	}
+}
+
+func TestUpdateRolloutCanary(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	client := mock_client.NewMockClient(ctrl)
+	statusWriter := mock_client.NewMockStatusWriter(ctrl)
+	client.EXPECT().Status().Return(statusWriter).AnyTimes()
+	scaleExecutor := getMockScaleExecutor(client)
+	logger := logf.Log.WithName("test")
+
+	rollout := newTestRollout(true, "abc", "def", 5, 1, false)
+	hpaMinReplicas := int32(1)
+	client.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, key types.NamespacedName, obj runtime.Object) error {
+		switch obj := obj.(type) {
+		case *unstructured.Unstructured:
+			assert.Equal(t, "my-rollout", key.Name)
+			obj.Object = rollout
+		case *autoscalingv2beta2.HorizontalPodAutoscaler:
+			assert.Equal(t, "keda-hpa-my-scaledobject", key.Name)
+			obj.Spec.MinReplicas = &hpaMinReplicas
+		}
+		return nil
+	}).AnyTimes()
+
+	scaledObject := &kedav1alpha1.ScaledObject{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-scaledobject", Namespace: "default"},
+		Spec:       kedav1alpha1.ScaledObjectSpec{ScaleTargetRef: &kedav1alpha1.ScaleTarget{Name: "my-rollout"}},
+	}
+
+	// not a Rollout
+	assert.False(t, scaleExecutor.updateRolloutCanary(context.TODO(), logger, scaledObject, 5))
+
+	// the canary starts, the HPA is kept at the replica count of the Rollout
+	scaledObject.Status.ScaleTargetGVKR = &kedav1alpha1.GroupVersionKindResource{Group: "argoproj.io", Version: "v1alpha1", Kind: "Rollout", Resource: "rollouts"}
+	statusWriter.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(1)
+	client.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, obj runtime.Object, patch interface{}, opts ...interface{}) error {
+		hpaMinReplicas = *obj.(*autoscalingv2beta2.HorizontalPodAutoscaler).Spec.MinReplicas
+		return nil
+	}).Times(1)
+	assert.True(t, scaleExecutor.updateRolloutCanary(context.TODO(), logger, scaledObject, 5))
+	assert.Equal(t, &kedav1alpha1.RolloutCanaryStatus{StableReplicas: 4, CanaryReplicas: 1, MinReplicas: 5}, scaledObject.Status.RolloutCanary)
+	assert.Equal(t, int32(5), hpaMinReplicas)
+
+	// the canary is still in progress, nothing changes
+	assert.True(t, scaleExecutor.updateRolloutCanary(context.TODO(), logger, scaledObject, 5))
+
+	// the canary completes, the MinReplicas of the HPA is restored
+	rollout = newTestRollout(true, "def", "def", 5, 5, false)
+	statusWriter.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(1)
+	client.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, obj runtime.Object, patch interface{}, opts ...interface{}) error {
+		hpaMinReplicas = *obj.(*autoscalingv2beta2.HorizontalPodAutoscaler).Spec.MinReplicas
+		return nil
+	}).Times(1)
+	assert.False(t, scaleExecutor.updateRolloutCanary(context.TODO(), logger, scaledObject, 5))
+	assert.Nil(t, scaledObject.Status.RolloutCanary)
+	assert.Equal(t, int32(1), hpaMinReplicas)
+}
@@ -12,37 +12,38 @@ import (
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
 	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
-)
-
-const (
-	// Default cooldown period for a ScaleTarget if no cooldownPeriod is defined on the scaledObject
-	defaultCooldownPeriod = 5 * 60 // 5 minutes
+	"github.com/kedacore/keda/pkg/eventemitter"
 )
 
 // ScaleExecutor contains methods RequestJobScale and RequestScale
 type ScaleExecutor interface {
-	RequestJobScale(ctx context.Context, scaledJob *kedav1alpha1.ScaledJob, isActive bool, scaleTo int64, maxScale int64)
-	RequestScale(ctx context.Context, scaledObject *kedav1alpha1.ScaledObject, isActive bool)
+	RequestJobScale(ctx context.Context, scaledJob *kedav1alpha1.ScaledJob, isActive bool, activeTriggers []int, scaleTo int64, maxScale int64)
+	RequestScale(ctx context.Context, scaledObject *kedav1alpha1.ScaledObject, isActive bool, activeTriggers []int)
 }
 
 type scaleExecutor struct {
 	client           client.Client
 	scaleClient      *scale.ScalesGetter
 	reconcilerScheme *runtime.Scheme
+	eventEmitter     eventemitter.EventEmitter
+	podExecutor      podExecutor
 	logger           logr.Logger
 }
 
 // NewScaleExecutor creates a ScaleExecutor object
-func NewScaleExecutor(client client.Client, scaleClient *scale.ScalesGetter, reconcilerScheme *runtime.Scheme) ScaleExecutor {
+func NewScaleExecutor(client client.Client, scaleClient *scale.ScalesGetter, reconcilerScheme *runtime.Scheme, eventEmitter eventemitter.EventEmitter) ScaleExecutor {
 	return &scaleExecutor{
 		client:           client,
 		scaleClient:      scaleClient,
 		reconcilerScheme: reconcilerScheme,
+		eventEmitter:     eventEmitter,
+		podExecutor:      &remotePodExecutor{},
 		logger:           logf.Log.WithName("scaleexecutor"),
 	}
 }
 
-func (e *scaleExecutor) updateLastActiveTime(ctx context.Context, logger logr.Logger, object interface{}) error {
+// updateLastActiveTime sets the last active time of the object and of its active triggers, the indexes of the triggers in the spec
+func (e *scaleExecutor) updateLastActiveTime(ctx context.Context, logger logr.Logger, object interface{}, activeTriggers []int) error {
 	var patch client.Patch
 
 	now := metav1.Now()
@@ -51,9 +52,12 @@ func (e *scaleExecutor) updateLastActiveTime(ctx context.Context, logger logr.Lo
 	case *kedav1alpha1.ScaledObject:
 		patch = client.MergeFrom(obj.DeepCopy())
 		obj.Status.LastActiveTime = &now
+		obj.Status.InactiveSince = nil
+		obj.Status.TriggersActivity = getTriggersActivity(obj.Status.TriggersActivity, obj.Spec.Triggers, activeTriggers, now)
 	case *kedav1alpha1.ScaledJob:
 		patch = client.MergeFrom(obj.DeepCopy())
 		obj.Status.LastActiveTime = &now
+		obj.Status.TriggersActivity = getTriggersActivity(obj.Status.TriggersActivity, obj.Spec.Triggers, activeTriggers, now)
 	default:
 		err := fmt.Errorf("Unknown scalable object type %v", obj)
 		logger.Error(err, "Failed to patch Objects Status")
@@ -3,6 +3,7 @@ package executor
 import (
 	"context"
 	"sort"
+	"time"
 
 	"github.com/go-logr/logr"
 	batchv1 "k8s.io/api/batch/v1"
@@ -21,32 +22,42 @@ const (
 	defaultFailedJobsHistoryLimit     = int32(100)
 )
 
-func (e *scaleExecutor) RequestJobScale(ctx context.Context, scaledJob *kedav1alpha1.ScaledJob, isActive bool, scaleTo int64, maxScale int64) {
+func (e *scaleExecutor) RequestJobScale(ctx context.Context, scaledJob *kedav1alpha1.ScaledJob, isActive bool, activeTriggers []int, scaleTo int64, maxScale int64) {
 	logger := e.logger.WithValues("scaledJob.Name", scaledJob.Name, "scaledJob.Namespace", scaledJob.Namespace)
 
 	runningJobCount := e.getRunningJobCount(scaledJob)
 	logger.Info("Scaling Jobs", "Number of running Jobs", runningJobCount)
 
-	var effectiveMaxScale int64
-	if (maxScale + runningJobCount) > scaledJob.MaxReplicaCount() {
-		effectiveMaxScale = scaledJob.MaxReplicaCount() - runningJobCount
-	} else {
-		effectiveMaxScale = maxScale
+	strategy := getScalingStrategy(scaledJob)
+	var pendingJobCount int64
+	if strategy == pendingJobsScalingStrategy {
+		pendingJobCount = e.getPendingJobCount(scaledJob)
+		logger.V(1).Info("Scaling Jobs", "Number of pending Jobs", pendingJobCount)
 	}
+	effectiveMaxScale := getEffectiveMaxScale(strategy, maxScale, scaledJob.MaxReplicaCount(), runningJobCount, pendingJobCount)
 
-	if effectiveMaxScale < 0 {
-		effectiveMaxScale = 0
-	}
+	// the Jobs finished below minReplicaCount are replaced even if the triggers are inactive
+	minJobs := getMinJobsToCreate(scaledJob.MinReplicaCount(), runningJobCount)
 
 	if isActive {
 		logger.V(1).Info("At least one scaler is active")
 		now := metav1.Now()
 		scaledJob.Status.LastActiveTime = &now
-		e.updateLastActiveTime(ctx, logger, scaledJob)
+		e.updateLastActiveTime(ctx, logger, scaledJob, activeTriggers)
+		if scaleTo < minJobs {
+			scaleTo = minJobs
+		}
+		if effectiveMaxScale < minJobs {
+			effectiveMaxScale = minJobs
+		}
 		e.createJobs(logger, scaledJob, scaleTo, effectiveMaxScale)
+	} else if minJobs > 0 {
+		logger.V(1).Info("Starting Jobs to keep minReplicaCount Jobs running", "minReplicaCount", scaledJob.MinReplicaCount())
+		e.createJobs(logger, scaledJob, minJobs, minJobs)
 	} else {
 		logger.V(1).Info("No change in activity")
 	}
+	e.updateJobActiveCondition(ctx, logger, scaledJob, isActive, time.Now())
 
 	err := e.cleanUp(scaledJob)
 	if err != nil {
@@ -54,6 +65,23 @@ func (e *scaleExecutor) RequestJobScale(ctx context.Context, scaledJob *kedav1al
 	}
 }
 
+// updateJobActiveCondition sets the Active condition of the ScaledJob, the ScaledJob is cooling down while a trigger with a cooldownPeriod
+// was active less than its cooldownPeriod ago
+func (e *scaleExecutor) updateJobActiveCondition(ctx context.Context, logger logr.Logger, scaledJob *kedav1alpha1.ScaledJob, isActive bool, now time.Time) {
+	status, reason, message := metav1.ConditionTrue, "ScalerActive", "Scaling is performed because triggers are active"
+	if !isActive {
+		status, reason, message = metav1.ConditionFalse, "ScalerNotActive", "Scaling is not performed because triggers are not active"
+		if cooldownEnd, wasActive := getCooldownEnd(scaledJob.Status.LastActiveTime, scaledJob.Status.TriggersActivity, scaledJob.Spec.Triggers, 0); wasActive && cooldownEnd.After(now) {
+			reason, message = "ScalerCooldown", "Scaler cooling down because triggers are not active"
+		}
+	}
+
+	condition := scaledJob.Status.Conditions.GetActiveCondition()
+	if condition.Status != status || condition.Reason != reason {
+		e.setActiveCondition(ctx, logger, scaledJob, status, reason, message)
+	}
+}
+
 func (e *scaleExecutor) createJobs(logger logr.Logger, scaledJob *kedav1alpha1.ScaledJob, scaleTo int64, maxScale int64) {
 	scaledJob.Spec.JobTargetRef.Template.GenerateName = scaledJob.GetName() + "-"
 	if scaledJob.Spec.JobTargetRef.Template.Labels == nil {
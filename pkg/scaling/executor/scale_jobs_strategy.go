@@ -0,0 +1,112 @@
+package executor
+
+import (
+	"context"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+)
+
+const (
+	defaultScalingStrategy     = "default"
+	runningJobsScalingStrategy = "runningJobs"
+	pendingJobsScalingStrategy = "pendingJobs"
+)
+
+// getScalingStrategy returns the scaling strategy of the ScaledJob, default if not set
+func getScalingStrategy(scaledJob *kedav1alpha1.ScaledJob) string {
+	if scaledJob.Spec.ScalingStrategy == nil || scaledJob.Spec.ScalingStrategy.Strategy == "" {
+		return defaultScalingStrategy
+	}
+	return scaledJob.Spec.ScalingStrategy.Strategy
+}
+
+// getEffectiveMaxScale returns the number of Jobs to start for the scaling strategy, the Jobs running never exceed maxReplicaCount.
+// The runningJobs strategy deducts the running Jobs from maxScale as their messages are still counted in the queue length,
+// the pendingJobs strategy only deducts the Jobs whose pod didn't start yet as the messages received are hidden from the queue
+func getEffectiveMaxScale(strategy string, maxScale, maxReplicaCount, runningJobCount, pendingJobCount int64) int64 {
+	var effectiveMaxScale int64
+	switch {
+	case maxScale+runningJobCount > maxReplicaCount && strategy != runningJobsScalingStrategy:
+		effectiveMaxScale = maxReplicaCount - runningJobCount
+	case strategy == runningJobsScalingStrategy:
+		effectiveMaxScale = maxScale - runningJobCount
+	case strategy == pendingJobsScalingStrategy:
+		effectiveMaxScale = maxScale - pendingJobCount
+	default:
+		effectiveMaxScale = maxScale
+	}
+
+	if effectiveMaxScale < 0 {
+		return 0
+	}
+	return effectiveMaxScale
+}
+
+// getMinJobsToCreate returns the number of Jobs to start so minReplicaCount Jobs are running
+func getMinJobsToCreate(minReplicaCount, runningJobCount int64) int64 {
+	if runningJobCount >= minReplicaCount {
+		return 0
+	}
+	return minReplicaCount - runningJobCount
+}
+
+// getPendingJobCount returns the number of Jobs of the ScaledJob not finished whose pods didn't start yet, a pod is started once it has
+// the pendingPodConditions of the ScaledJob or once it is running or completed if there are no conditions
+func (e *scaleExecutor) getPendingJobCount(scaledJob *kedav1alpha1.ScaledJob) int64 {
+	opts := []client.ListOption{
+		client.InNamespace(scaledJob.GetNamespace()),
+		client.MatchingLabels(map[string]string{"scaledjob": scaledJob.GetName()}),
+	}
+
+	jobs := &batchv1.JobList{}
+	if err := e.client.List(context.TODO(), jobs, opts...); err != nil {
+		return 0
+	}
+	pods := &corev1.PodList{}
+	if err := e.client.List(context.TODO(), pods, opts...); err != nil {
+		return 0
+	}
+
+	var conditions []string
+	if scaledJob.Spec.ScalingStrategy != nil {
+		conditions = scaledJob.Spec.ScalingStrategy.PendingPodConditions
+	}
+	startedJobs := make(map[string]bool)
+	for i := range pods.Items {
+		if isPodStarted(&pods.Items[i], conditions) {
+			startedJobs[pods.Items[i].Labels["job-name"]] = true
+		}
+	}
+
+	var pendingJobs int64
+	for i := range jobs.Items {
+		if !e.isJobFinished(&jobs.Items[i]) && !startedJobs[jobs.Items[i].Name] {
+			pendingJobs++
+		}
+	}
+	return pendingJobs
+}
+
+// isPodStarted returns true if all the conditions of the pod are true, or if the pod is running or completed if there are no conditions
+func isPodStarted(pod *corev1.Pod, conditions []string) bool {
+	if len(conditions) == 0 {
+		return pod.Status.Phase == corev1.PodRunning || pod.Status.Phase == corev1.PodSucceeded
+	}
+	for _, conditionType := range conditions {
+		found := false
+		for _, condition := range pod.Status.Conditions {
+			if string(condition.Type) == conditionType && condition.Status == corev1.ConditionTrue {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,101 @@
+package executor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+	"github.com/kedacore/keda/pkg/mock/mock_client"
+)
+
+func TestGetEffectiveMaxScale(t *testing.T) {
+	tests := []struct {
+		strategy        string
+		maxScale        int64
+		maxReplicaCount int64
+		runningJobs     int64
+		pendingJobs     int64
+		expected        int64
+	}{
+		{defaultScalingStrategy, 5, 10, 3, 1, 5},
+		{defaultScalingStrategy, 8, 10, 3, 1, 7},
+		{runningJobsScalingStrategy, 5, 10, 3, 1, 2},
+		{runningJobsScalingStrategy, 2, 10, 3, 1, 0},
+		{pendingJobsScalingStrategy, 5, 10, 3, 1, 4},
+		{pendingJobsScalingStrategy, 8, 10, 3, 1, 7},
+		{pendingJobsScalingStrategy, 1, 10, 3, 2, 0},
+	}
+	for _, test := range tests {
+		effectiveMaxScale := getEffectiveMaxScale(test.strategy, test.maxScale, test.maxReplicaCount, test.runningJobs, test.pendingJobs)
+		assert.Equal(t, test.expected, effectiveMaxScale, "%s with maxScale %d, %d running and %d pending Jobs", test.strategy, test.maxScale, test.runningJobs, test.pendingJobs)
+	}
+}
+
+func TestIsPodStarted(t *testing.T) {
+	pod := &corev1.Pod{Status: corev1.PodStatus{
+		Phase:      corev1.PodRunning,
+		Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionFalse}, {Type: corev1.ContainersReady, Status: corev1.ConditionTrue}},
+	}}
+	assert.True(t, isPodStarted(pod, nil))
+	assert.True(t, isPodStarted(pod, []string{"ContainersReady"}))
+	assert.False(t, isPodStarted(pod, []string{"ContainersReady", "Ready"}))
+
+	pod.Status.Phase = corev1.PodPending
+	assert.False(t, isPodStarted(pod, nil))
+}
+
+func TestGetPendingJobCount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	jobs := []batchv1.Job{
+		{ObjectMeta: metav1.ObjectMeta{Name: "job-started"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "job-pending"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "job-without-pod"}},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "job-finished"},
+			Status:     batchv1.JobStatus{Conditions: []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}}},
+		},
+	}
+	pods := []corev1.Pod{
+		{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"job-name": "job-started"}}, Status: corev1.PodStatus{Phase: corev1.PodRunning}},
+		{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"job-name": "job-pending"}}, Status: corev1.PodStatus{Phase: corev1.PodPending}},
+	}
+
+	client := mock_client.NewMockClient(ctrl)
+	client.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, list runtime.Object, opts ...runtimeclient.ListOption) error {
+		switch l := list.(type) {
+		case *batchv1.JobList:
+			l.Items = jobs
+		case *corev1.PodList:
+			l.Items = pods
+		}
+		return nil
+	}).AnyTimes()
+	scaleExecutor := getMockScaleExecutor(client)
+
+	scaledJob := &kedav1alpha1.ScaledJob{
+		ObjectMeta: metav1.ObjectMeta{Name: "job", Namespace: "default"},
+		Spec:       kedav1alpha1.ScaledJobSpec{ScalingStrategy: &kedav1alpha1.ScalingStrategy{Strategy: pendingJobsScalingStrategy}},
+	}
+	assert.Equal(t, int64(2), scaleExecutor.getPendingJobCount(scaledJob))
+
+	// the running pod isn't ready yet
+	scaledJob.Spec.ScalingStrategy.PendingPodConditions = []string{"Ready"}
+	assert.Equal(t, int64(3), scaleExecutor.getPendingJobCount(scaledJob))
+}
+
+func TestGetMinJobsToCreate(t *testing.T) {
+	assert.Equal(t, int64(0), getMinJobsToCreate(0, 0))
+	assert.Equal(t, int64(2), getMinJobsToCreate(3, 1))
+	assert.Equal(t, int64(0), getMinJobsToCreate(3, 3))
+	assert.Equal(t, int64(0), getMinJobsToCreate(3, 5))
+}
@@ -2,16 +2,22 @@ package executor
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/go-logr/logr"
 	autoscalingv1 "k8s.io/api/autoscaling/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+	"github.com/kedacore/keda/pkg/audit"
+	"github.com/kedacore/keda/pkg/eventemitter"
+	"github.com/kedacore/keda/pkg/globalconfig"
+	kedautil "github.com/kedacore/keda/pkg/util"
 )
 
-func (e *scaleExecutor) RequestScale(ctx context.Context, scaledObject *kedav1alpha1.ScaledObject, isActive bool) {
+func (e *scaleExecutor) RequestScale(ctx context.Context, scaledObject *kedav1alpha1.ScaledObject, isActive bool, activeTriggers []int) {
 	logger := e.logger.WithValues("scaledobject.Name", scaledObject.Name,
 		"scaledObject.Namespace", scaledObject.Namespace,
 		"scaleTarget.Name", scaledObject.Spec.ScaleTargetRef.Name)
@@ -22,11 +28,40 @@ func (e *scaleExecutor) RequestScale(ctx context.Context, scaledObject *kedav1al
 		return
 	}
 
+	// the ScaleTarget may have been scaled from zero outside of KEDA
+	if currentScale.Spec.Replicas > 0 {
+		e.setScaledToZero(ctx, logger, scaledObject, false)
+	}
+
+	inScaleToZeroSchedule, err := isInScaleToZeroSchedule(scaledObject, time.Now())
+	if err != nil {
+		logger.Error(err, "Error checking the scaleToZeroSchedule, the ScaleTarget isn't scaled to zero")
+	}
+
+	// the cooldown doesn't start while the ScaleTarget rolls out its pods, it is kept active until the rollout completes
+	keptActiveForRollout := false
+	if !isActive && currentScale.Spec.Replicas > 0 && e.isScaleTargetRollingOut(ctx, logger, scaledObject) {
+		logger.V(1).Info("ScaleTarget kept active during its rollout")
+		isActive = true
+		keptActiveForRollout = true
+	}
+
+	// the Rollout isn't scaled down by the HPA nor to zero while its canary is in progress
+	inRolloutCanary := e.updateRolloutCanary(ctx, logger, scaledObject, currentScale.Spec.Replicas)
+
+	// the HPA doesn't scale the StatefulSet down before the pods it removes ran their pre-scale-down hook
+	e.runHPAPreScaleDownHooks(ctx, logger, scaledObject, currentScale.Spec.Replicas)
+
 	if currentScale.Spec.Replicas == 0 && isActive {
 		// current replica count is 0, but there is an active trigger.
 		// scale the ScaleTarget up
-		e.scaleFromZero(ctx, logger, scaledObject, currentScale)
+		e.scaleFromZero(ctx, logger, scaledObject, currentScale, activeTriggers)
+	} else if currentScale.Spec.Replicas == 0 && !inScaleToZeroSchedule {
+		// there are no active triggers, but the ScaleTarget can't have zero replicas outside of the scale to zero schedule
+		e.scaleOutsideScaleToZeroSchedule(ctx, logger, scaledObject, currentScale)
 	} else if !isActive &&
+		inScaleToZeroSchedule &&
+		!inRolloutCanary &&
 		currentScale.Spec.Replicas > 0 &&
 		(scaledObject.Spec.MinReplicaCount == nil || *scaledObject.Spec.MinReplicaCount == 0) {
 		// there are no active triggers, but the ScaleTarget has replicas.
@@ -48,18 +83,29 @@ func (e *scaleExecutor) RequestScale(ctx context.Context, scaledObject *kedav1al
 		if err == nil {
 			logger.Info("Successfully set ScaleTarget replicas count to ScaledObject minReplicaCount",
 				"ScaleTarget.Replicas", currentScale.Spec.Replicas)
+			recordScaleDecision(scaledObject, isActive, currentScale.Spec.Replicas)
 		}
 	} else if isActive {
 		// triggers are active, but we didn't need to scale (replica count > 0)
 		// Update LastActiveTime to now.
-		e.updateLastActiveTime(ctx, logger, scaledObject)
+		e.updateLastActiveTime(ctx, logger, scaledObject, activeTriggers)
+	} else if currentScale.Spec.Replicas == 0 && scaledObject.Status.PostDeactivationWebhookPending {
+		// the postDeactivation webhook with the Fail policy is called until it succeeds
+		e.runPostDeactivationWebhook(ctx, logger, scaledObject)
 	} else {
 		logger.V(1).Info("ScaleTarget no change")
 	}
 
+	// a deferred scale to zero is no longer pending once the triggers are active again
+	if isActive {
+		e.clearScaleToZeroDeferred(ctx, logger, scaledObject)
+	}
+
 	condition := scaledObject.Status.Conditions.GetActiveCondition()
-	if condition.IsUnknown() || condition.IsTrue() != isActive {
-		if isActive {
+	if condition.IsUnknown() || condition.IsTrue() != isActive || (condition.Reason == "ScaleTargetRollingOut") != keptActiveForRollout {
+		if keptActiveForRollout {
+			e.setActiveCondition(ctx, logger, scaledObject, metav1.ConditionTrue, "ScaleTargetRollingOut", "Scaling is performed because the ScaleTarget is rolling out")
+		} else if isActive {
 			e.setActiveCondition(ctx, logger, scaledObject, metav1.ConditionTrue, "ScalerActive", "Scaling is performed because triggers are active")
 		} else {
 			e.setActiveCondition(ctx, logger, scaledObject, metav1.ConditionFalse, "ScalerNotActive", "Scaling is not performed because triggers are not active")
@@ -68,40 +114,140 @@ func (e *scaleExecutor) RequestScale(ctx context.Context, scaledObject *kedav1al
 }
 
 // An object will be scaled down to 0 only if it's passed its cooldown period
-// or if LastActiveTime is nil
+// or if LastActiveTime is nil, the triggers with their own cooldownPeriod are cooled down separately
 func (e *scaleExecutor) scaleToZero(ctx context.Context, logger logr.Logger, scaledObject *kedav1alpha1.ScaledObject, scale *autoscalingv1.Scale) {
 	var cooldownPeriod time.Duration
 
 	if scaledObject.Spec.CooldownPeriod != nil {
 		cooldownPeriod = time.Second * time.Duration(*scaledObject.Spec.CooldownPeriod)
 	} else {
-		cooldownPeriod = time.Second * time.Duration(defaultCooldownPeriod)
+		// the default cooldown period is set by the global configuration
+		cooldownPeriod = time.Second * time.Duration(globalconfig.Get().DefaultCooldownPeriod)
+	}
+
+	if isInInitialCooldownPeriod(scaledObject, time.Now()) {
+		logger.V(1).Info("ScaleTarget in initial cooldown period",
+			"CreationTimestamp", scaledObject.CreationTimestamp,
+			"InitialCooldownPeriod", *scaledObject.Spec.InitialCooldownPeriod)
+
+		e.setCooldownCondition(ctx, logger, scaledObject)
+		return
+	}
+
+	if !e.isScaleToZeroGracePeriodOver(ctx, logger, scaledObject) {
+		logger.V(1).Info("ScaleTarget in scale to zero grace period",
+			"InactiveSince", scaledObject.Status.InactiveSince,
+			"ScaleToZeroGracePeriod", *scaledObject.Spec.ScaleToZeroGracePeriod)
+
+		e.setCooldownCondition(ctx, logger, scaledObject)
+		return
+	}
+
+	if !isZeroQueueWaitOver(scaledObject) {
+		logger.V(1).Info("ScaleTarget waiting for the metrics of the triggers to be zero",
+			"ZeroQueueChecks", scaledObject.Status.ZeroQueueChecks,
+			"WaitForZeroQueueBeforeScaleToZero", *scaledObject.Spec.WaitForZeroQueueBeforeScaleToZero)
+
+		e.setCooldownCondition(ctx, logger, scaledObject)
+		return
 	}
 
 	// LastActiveTime can be nil if the ScaleTarget was scaled outside of Keda.
 	// In this case we will ignore the cooldown period and scale it down
-	if scaledObject.Status.LastActiveTime == nil ||
-		scaledObject.Status.LastActiveTime.Add(cooldownPeriod).Before(time.Now()) {
+	cooldownEnd, wasActive := getCooldownEnd(scaledObject.Status.LastActiveTime, scaledObject.Status.TriggersActivity, scaledObject.Spec.Triggers, cooldownPeriod)
+	if !wasActive || cooldownEnd.Before(time.Now()) {
 		// or last time a trigger was active was > cooldown period, so scale down.
+		if e.isScaleToZeroDeferred(ctx, logger, scaledObject, scale) {
+			return
+		}
+		if err := e.runPreScaleDownHooks(ctx, logger, scaledObject, getUnhookedReplicas(scaledObject, scale.Spec.Replicas), 0); err != nil {
+			logger.Error(err, "ScaleTarget not scaled to 0 replicas, the scale down is retried on the next check")
+			e.eventEmitter.Emit("ScaledObject", scaledObject.Namespace, scaledObject.Name, eventemitter.PreScaleDownHookFailedType, err.Error())
+			return
+		}
 		scale.Spec.Replicas = 0
 		err := e.updateScaleOnScaleTarget(ctx, scaledObject, scale)
 		if err == nil {
 			logger.Info("Successfully scaled ScaleTarget to 0 replicas")
+			recordScaleDecision(scaledObject, false, 0)
+			e.eventEmitter.Emit("ScaledObject", scaledObject.Namespace, scaledObject.Name, eventemitter.ScaleTargetDeactivatedType, "Scaled ScaleTarget to 0 replicas")
 			e.setActiveCondition(ctx, logger, scaledObject, metav1.ConditionFalse, "ScalerNotActive", "Scaling is not performed because triggers are not active")
+			e.setScaledToZero(ctx, logger, scaledObject, true)
+			e.runPostDeactivationWebhook(ctx, logger, scaledObject)
 		}
 	} else {
 		logger.V(1).Info("ScaleTarget cooling down",
 			"LastActiveTime", scaledObject.Status.LastActiveTime,
-			"CoolDownPeriod", cooldownPeriod)
+			"CoolDownPeriod", cooldownPeriod,
+			"CooldownEnd", cooldownEnd)
+
+		e.setCooldownCondition(ctx, logger, scaledObject)
+	}
+}
+
+// setScaledToZero sets whether the ScaleTarget was scaled to zero by KEDA in the status of the ScaledObject, if it changed
+func (e *scaleExecutor) setScaledToZero(ctx context.Context, logger logr.Logger, scaledObject *kedav1alpha1.ScaledObject, scaledToZero bool) {
+	if scaledObject.Status.ScaledToZero == scaledToZero {
+		return
+	}
+	patch := client.MergeFrom(scaledObject.DeepCopy())
+	scaledObject.Status.ScaledToZero = scaledToZero
+	if err := e.client.Status().Patch(ctx, scaledObject, patch); err != nil {
+		logger.Error(err, "Failed to patch Objects Status")
+	}
+}
+
+// setCooldownCondition sets the Active condition of the ScaledObject waiting to be scaled to zero to ScalerCooldown, if it isn't set already
+func (e *scaleExecutor) setCooldownCondition(ctx context.Context, logger logr.Logger, scaledObject *kedav1alpha1.ScaledObject) {
+	activeCondition := scaledObject.Status.Conditions.GetActiveCondition()
+	if !activeCondition.IsFalse() || activeCondition.Reason != "ScalerCooldown" {
+		e.setActiveCondition(ctx, logger, scaledObject, metav1.ConditionFalse, "ScalerCooldown", "Scaler cooling down because triggers are not active")
+	}
+}
 
-		activeCondition := scaledObject.Status.Conditions.GetActiveCondition()
-		if !activeCondition.IsFalse() || activeCondition.Reason != "ScalerCooldown" {
-			e.setActiveCondition(ctx, logger, scaledObject, metav1.ConditionFalse, "ScalerCooldown", "Scaler cooling down because triggers are not active")
+// isScaleToZeroGracePeriodOver returns true if the triggers have been inactive for the scaleToZeroGracePeriod of the ScaledObject,
+// or if there is no grace period. The time the triggers became inactive is stored in the status on the first inactive check.
+func (e *scaleExecutor) isScaleToZeroGracePeriodOver(ctx context.Context, logger logr.Logger, scaledObject *kedav1alpha1.ScaledObject) bool {
+	if scaledObject.Spec.ScaleToZeroGracePeriod == nil || *scaledObject.Spec.ScaleToZeroGracePeriod <= 0 {
+		return true
+	}
+
+	if scaledObject.Status.InactiveSince == nil {
+		now := metav1.Now()
+		patch := client.MergeFrom(scaledObject.DeepCopy())
+		scaledObject.Status.InactiveSince = &now
+		if err := e.client.Status().Patch(ctx, scaledObject, patch); err != nil {
+			logger.Error(err, "Failed to patch Objects Status")
 		}
+		return false
 	}
+
+	return isGracePeriodOver(scaledObject.Status.InactiveSince.Time, *scaledObject.Spec.ScaleToZeroGracePeriod, time.Now())
 }
 
-func (e *scaleExecutor) scaleFromZero(ctx context.Context, logger logr.Logger, scaledObject *kedav1alpha1.ScaledObject, scale *autoscalingv1.Scale) {
+// isZeroQueueWaitOver returns true if the metrics of the triggers were zero for the waitForZeroQueueBeforeScaleToZero consecutive checks
+// of the ScaledObject, or if it doesn't wait for the queues to be empty
+func isZeroQueueWaitOver(scaledObject *kedav1alpha1.ScaledObject) bool {
+	if scaledObject.Spec.WaitForZeroQueueBeforeScaleToZero == nil {
+		return true
+	}
+	return scaledObject.Status.ZeroQueueChecks >= *scaledObject.Spec.WaitForZeroQueueBeforeScaleToZero
+}
+
+// isInInitialCooldownPeriod returns true if the ScaledObject was created less than initialCooldownPeriod ago,
+// so the first polls of the triggers establish a baseline before the ScaleTarget can be scaled to zero
+func isInInitialCooldownPeriod(scaledObject *kedav1alpha1.ScaledObject, now time.Time) bool {
+	if scaledObject.Spec.InitialCooldownPeriod == nil || *scaledObject.Spec.InitialCooldownPeriod <= 0 {
+		return false
+	}
+	return scaledObject.CreationTimestamp.Add(time.Second * time.Duration(*scaledObject.Spec.InitialCooldownPeriod)).After(now)
+}
+
+func isGracePeriodOver(inactiveSince time.Time, gracePeriod int32, now time.Time) bool {
+	return !inactiveSince.Add(time.Second * time.Duration(gracePeriod)).After(now)
+}
+
+func (e *scaleExecutor) scaleFromZero(ctx context.Context, logger logr.Logger, scaledObject *kedav1alpha1.ScaledObject, scale *autoscalingv1.Scale, activeTriggers []int) {
 	currentReplicas := scale.Spec.Replicas
 	if scaledObject.Spec.MinReplicaCount != nil && *scaledObject.Spec.MinReplicaCount > 0 {
 		scale.Spec.Replicas = *scaledObject.Spec.MinReplicaCount
@@ -109,16 +255,76 @@ func (e *scaleExecutor) scaleFromZero(ctx context.Context, logger logr.Logger, s
 		scale.Spec.Replicas = 1
 	}
 
+	if err := e.runPreActivationWebhook(ctx, logger, scaledObject, scale.Spec.Replicas); err != nil {
+		logger.Error(err, "ScaleTarget not scaled from zero, the activation is retried on the next check")
+		return
+	}
 	err := e.updateScaleOnScaleTarget(ctx, scaledObject, scale)
 
 	if err == nil {
 		logger.Info("Successfully updated ScaleTarget",
 			"Original Replicas Count", currentReplicas,
 			"New Replicas Count", scale.Spec.Replicas)
+		recordScaleDecision(scaledObject, true, scale.Spec.Replicas)
+		e.eventEmitter.Emit("ScaledObject", scaledObject.Namespace, scaledObject.Name, eventemitter.ScaleTargetActivatedType,
+			fmt.Sprintf("Scaled ScaleTarget from %d to %d replicas", currentReplicas, scale.Spec.Replicas))
+		// the state isn't flushed anymore once the ScaleTarget is active again
+		e.setPostDeactivationWebhookPending(ctx, logger, scaledObject, false)
+		e.setScaledToZero(ctx, logger, scaledObject, false)
 
 		// Scale was successful. Update lastScaleTime and lastActiveTime on the scaledObject
-		e.updateLastActiveTime(ctx, logger, scaledObject)
+		e.updateLastActiveTime(ctx, logger, scaledObject, activeTriggers)
+	}
+}
+
+// isInScaleToZeroSchedule returns true if the ScaleTarget can be scaled to zero at the time, always if the ScaledObject has no scaleToZeroSchedule
+func isInScaleToZeroSchedule(scaledObject *kedav1alpha1.ScaledObject, now time.Time) (bool, error) {
+	if scaledObject.Spec.ScaleToZeroSchedule == nil {
+		return true, nil
+	}
+	schedule, err := kedautil.ParseScaleToZeroSchedule(scaledObject.Spec.ScaleToZeroSchedule)
+	if err != nil {
+		return false, err
 	}
+	return schedule.Contains(now), nil
+}
+
+// scaleOutsideScaleToZeroSchedule scales the ScaleTarget from zero to minReplicaCount, at least 1 replica, as it can't have zero replicas
+// outside of the scale to zero schedule. The last active time isn't updated, the triggers are still inactive
+func (e *scaleExecutor) scaleOutsideScaleToZeroSchedule(ctx context.Context, logger logr.Logger, scaledObject *kedav1alpha1.ScaledObject, scale *autoscalingv1.Scale) {
+	scale.Spec.Replicas = 1
+	if scaledObject.Spec.MinReplicaCount != nil && *scaledObject.Spec.MinReplicaCount > 0 {
+		scale.Spec.Replicas = *scaledObject.Spec.MinReplicaCount
+	}
+
+	if err := e.runPreActivationWebhook(ctx, logger, scaledObject, scale.Spec.Replicas); err != nil {
+		logger.Error(err, "ScaleTarget not scaled from zero outside of the scale to zero schedule, it is retried on the next check")
+		return
+	}
+	err := e.updateScaleOnScaleTarget(ctx, scaledObject, scale)
+	if err == nil {
+		logger.Info("Successfully scaled ScaleTarget outside of the scale to zero schedule",
+			"New Replicas Count", scale.Spec.Replicas)
+		e.setPostDeactivationWebhookPending(ctx, logger, scaledObject, false)
+		e.setScaledToZero(ctx, logger, scaledObject, false)
+		recordScaleDecision(scaledObject, false, scale.Spec.Replicas)
+		e.eventEmitter.Emit("ScaledObject", scaledObject.Namespace, scaledObject.Name, eventemitter.ScaleTargetActivatedType,
+			fmt.Sprintf("Scaled ScaleTarget from 0 to %d replicas outside of the scale to zero schedule", scale.Spec.Replicas))
+	}
+}
+
+// recordScaleDecision records the replica count KEDA set on the ScaleTarget in the audit log,
+// scaling between the minimum and maximum replica count is performed by the HPA
+func recordScaleDecision(scaledObject *kedav1alpha1.ScaledObject, isActive bool, replicas int32) {
+	desiredReplicas := int64(replicas)
+	audit.Record(audit.Decision{
+		Source:          audit.SourceOperator,
+		Kind:            "ScaledObject",
+		Namespace:       scaledObject.Namespace,
+		Name:            scaledObject.Name,
+		Active:          &isActive,
+		DesiredReplicas: &desiredReplicas,
+	})
 }
 
 func (e *scaleExecutor) getScaleTargetScale(ctx context.Context, scaledObject *kedav1alpha1.ScaledObject) (*autoscalingv1.Scale, error) {
@@ -0,0 +1,131 @@
+package executor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+	"github.com/kedacore/keda/pkg/mock/mock_client"
+)
+
+func TestIsGracePeriodOver(t *testing.T) {
+	now := time.Date(2020, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	assert.False(t, isGracePeriodOver(now.Add(-30*time.Second), 60, now))
+	assert.True(t, isGracePeriodOver(now.Add(-60*time.Second), 60, now))
+	assert.True(t, isGracePeriodOver(now.Add(-90*time.Second), 60, now))
+}
+
+func TestIsScaleToZeroGracePeriodOver(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	client := mock_client.NewMockClient(ctrl)
+	statusWriter := mock_client.NewMockStatusWriter(ctrl)
+	client.EXPECT().Status().Return(statusWriter).AnyTimes()
+	scaleExecutor := getMockScaleExecutor(client)
+	logger := logf.Log.WithName("test")
+
+	// no grace period
+	scaledObject := &kedav1alpha1.ScaledObject{}
+	assert.True(t, scaleExecutor.isScaleToZeroGracePeriodOver(context.TODO(), logger, scaledObject))
+
+	// the start of the inactivity is stored on the first inactive check
+	gracePeriod := int32(60)
+	scaledObject.Spec.ScaleToZeroGracePeriod = &gracePeriod
+	statusWriter.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(1)
+	assert.False(t, scaleExecutor.isScaleToZeroGracePeriodOver(context.TODO(), logger, scaledObject))
+	assert.NotNil(t, scaledObject.Status.InactiveSince)
+
+	// still in the grace period
+	assert.False(t, scaleExecutor.isScaleToZeroGracePeriodOver(context.TODO(), logger, scaledObject))
+
+	// grace period over
+	inactiveSince := metav1.NewTime(time.Now().Add(-2 * time.Minute))
+	scaledObject.Status.InactiveSince = &inactiveSince
+	assert.True(t, scaleExecutor.isScaleToZeroGracePeriodOver(context.TODO(), logger, scaledObject))
+}
+
+func TestIsInInitialCooldownPeriod(t *testing.T) {
+	now := time.Date(2020, 10, 1, 12, 0, 0, 0, time.UTC)
+	initialCooldownPeriod := int32(300)
+	scaledObject := &kedav1alpha1.ScaledObject{
+		ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(now.Add(-time.Minute))},
+	}
+
+	// no initial cooldown period
+	assert.False(t, isInInitialCooldownPeriod(scaledObject, now))
+
+	scaledObject.Spec.InitialCooldownPeriod = &initialCooldownPeriod
+	assert.True(t, isInInitialCooldownPeriod(scaledObject, now))
+	assert.False(t, isInInitialCooldownPeriod(scaledObject, now.Add(5*time.Minute)))
+}
+
+func TestIsInScaleToZeroSchedule(t *testing.T) {
+	now := time.Date(2020, 10, 7, 12, 0, 0, 0, time.UTC)
+
+	// no schedule
+	scaledObject := &kedav1alpha1.ScaledObject{}
+	inSchedule, err := isInScaleToZeroSchedule(scaledObject, now)
+	assert.NoError(t, err)
+	assert.True(t, inSchedule)
+
+	// business hours
+	scaledObject.Spec.ScaleToZeroSchedule = &kedav1alpha1.ScaleToZeroSchedule{
+		Windows: []kedav1alpha1.ScheduleWindow{{Start: "0 19 * * *", End: "0 7 * * *"}},
+	}
+	inSchedule, err = isInScaleToZeroSchedule(scaledObject, now)
+	assert.NoError(t, err)
+	assert.False(t, inSchedule)
+
+	// off-hours
+	inSchedule, err = isInScaleToZeroSchedule(scaledObject, now.Add(10*time.Hour))
+	assert.NoError(t, err)
+	assert.True(t, inSchedule)
+
+	// an invalid schedule never allows scaling to zero
+	scaledObject.Spec.ScaleToZeroSchedule.Windows[0].Start = "invalid"
+	inSchedule, err = isInScaleToZeroSchedule(scaledObject, now.Add(10*time.Hour))
+	assert.Error(t, err)
+	assert.False(t, inSchedule)
+}
+
+func TestIsZeroQueueWaitOver(t *testing.T) {
+	scaledObject := &kedav1alpha1.ScaledObject{}
+	assert.True(t, isZeroQueueWaitOver(scaledObject))
+
+	checks := int32(3)
+	scaledObject.Spec.WaitForZeroQueueBeforeScaleToZero = &checks
+	scaledObject.Status.ZeroQueueChecks = 2
+	assert.False(t, isZeroQueueWaitOver(scaledObject))
+
+	scaledObject.Status.ZeroQueueChecks = 3
+	assert.True(t, isZeroQueueWaitOver(scaledObject))
+}
+
+func TestSetScaledToZero(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	client := mock_client.NewMockClient(ctrl)
+	statusWriter := mock_client.NewMockStatusWriter(ctrl)
+	client.EXPECT().Status().Return(statusWriter).AnyTimes()
+	scaleExecutor := getMockScaleExecutor(client)
+	logger := logf.Log.WithName("test")
+
+	// the status is only patched once it changes
+	scaledObject := &kedav1alpha1.ScaledObject{}
+	scaleExecutor.setScaledToZero(context.TODO(), logger, scaledObject, false)
+	statusWriter.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(2)
+	scaleExecutor.setScaledToZero(context.TODO(), logger, scaledObject, true)
+	assert.True(t, scaledObject.Status.ScaledToZero)
+	scaleExecutor.setScaledToZero(context.TODO(), logger, scaledObject, true)
+	scaleExecutor.setScaledToZero(context.TODO(), logger, scaledObject, false)
+	assert.False(t, scaledObject.Status.ScaledToZero)
+}
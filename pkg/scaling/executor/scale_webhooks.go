@@ -0,0 +1,128 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/go-logr/logr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+	"github.com/kedacore/keda/pkg/eventemitter"
+	kedautil "github.com/kedacore/keda/pkg/util"
+)
+
+// The names of the webhooks of a ScaledObject, sent in the requests
+const (
+	preActivationWebhook    = "preActivation"
+	postDeactivationWebhook = "postDeactivation"
+)
+
+// scaleWebhookRequest is the body of the POST request sent to a webhook of a ScaledObject
+type scaleWebhookRequest struct {
+	Webhook         string `json:"webhook"`
+	Namespace       string `json:"namespace"`
+	ScaledObject    string `json:"scaledObject"`
+	ScaleTargetKind string `json:"scaleTargetKind,omitempty"`
+	ScaleTargetName string `json:"scaleTargetName"`
+	// Replicas is the replica count the ScaleTarget is about to be scaled to, or was scaled to
+	Replicas int32 `json:"replicas"`
+}
+
+// postHook sends a POST request with the JSON body, if any, to the URL of a hook, the response must have a 2xx status within the timeout
+func postHook(ctx context.Context, hookURL string, body []byte, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var reqBody io.Reader
+	if body != nil {
+		reqBody = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hookURL, reqBody)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("the hook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// callScaleWebhook calls the webhook of the ScaledObject about the ScaleTarget scaled to replicas. A failure of a webhook with the
+// Ignore policy is only reported in an event, the error is returned for a webhook with the Fail policy
+func (e *scaleExecutor) callScaleWebhook(ctx context.Context, logger logr.Logger, scaledObject *kedav1alpha1.ScaledObject, name string, webhook *kedav1alpha1.ScaleWebhook, replicas int32) error {
+	parsed, err := kedautil.ParseScaleWebhook(webhook)
+	if err == nil {
+		body, _ := json.Marshal(scaleWebhookRequest{
+			Webhook:         name,
+			Namespace:       scaledObject.Namespace,
+			ScaledObject:    scaledObject.Name,
+			ScaleTargetKind: scaledObject.Status.ScaleTargetKind,
+			ScaleTargetName: scaledObject.Spec.ScaleTargetRef.Name,
+			Replicas:        replicas,
+		})
+		logger.V(1).Info("Calling the webhook of the ScaledObject", "webhook", name)
+		err = postHook(ctx, parsed.URL, body, parsed.Timeout)
+	}
+	if err == nil {
+		return nil
+	}
+
+	err = fmt.Errorf("%s webhook failed: %s", name, err)
+	e.eventEmitter.Emit("ScaledObject", scaledObject.Namespace, scaledObject.Name, eventemitter.ScaleWebhookFailedType, err.Error())
+	if parsed != nil && parsed.FailurePolicy == kedav1alpha1.ScaleWebhookFailurePolicyFail {
+		return err
+	}
+	logger.Error(err, "The failure of the webhook is ignored")
+	return nil
+}
+
+// runPreActivationWebhook calls the preActivation webhook of the ScaledObject before its ScaleTarget is scaled from zero to replicas,
+// an error is returned if the webhook with the Fail policy fails so the activation is postponed to the next check
+func (e *scaleExecutor) runPreActivationWebhook(ctx context.Context, logger logr.Logger, scaledObject *kedav1alpha1.ScaledObject, replicas int32) error {
+	if scaledObject.Spec.ScaleWebhooks == nil || scaledObject.Spec.ScaleWebhooks.PreActivation == nil {
+		return nil
+	}
+	return e.callScaleWebhook(ctx, logger, scaledObject, preActivationWebhook, scaledObject.Spec.ScaleWebhooks.PreActivation, replicas)
+}
+
+// runPostDeactivationWebhook calls the postDeactivation webhook of the ScaledObject once its ScaleTarget is scaled to zero,
+// the webhook with the Fail policy is marked as pending in the status until it succeeds
+func (e *scaleExecutor) runPostDeactivationWebhook(ctx context.Context, logger logr.Logger, scaledObject *kedav1alpha1.ScaledObject) {
+	pending := false
+	if scaledObject.Spec.ScaleWebhooks != nil && scaledObject.Spec.ScaleWebhooks.PostDeactivation != nil {
+		if err := e.callScaleWebhook(ctx, logger, scaledObject, postDeactivationWebhook, scaledObject.Spec.ScaleWebhooks.PostDeactivation, 0); err != nil {
+			logger.Error(err, "The webhook is called again on the next check")
+			pending = true
+		}
+	}
+	e.setPostDeactivationWebhookPending(ctx, logger, scaledObject, pending)
+}
+
+// setPostDeactivationWebhookPending patches the status of the ScaledObject if the pending postDeactivation webhook changes
+func (e *scaleExecutor) setPostDeactivationWebhookPending(ctx context.Context, logger logr.Logger, scaledObject *kedav1alpha1.ScaledObject, pending bool) {
+	if scaledObject.Status.PostDeactivationWebhookPending == pending {
+		return
+	}
+	patch := client.MergeFrom(scaledObject.DeepCopy())
+	scaledObject.Status.PostDeactivationWebhookPending = pending
+	if err := e.client.Status().Patch(ctx, scaledObject, patch); err != nil {
+		logger.Error(err, "Failed to patch Objects Status")
+	}
+}
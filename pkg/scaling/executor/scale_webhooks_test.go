@@ -0,0 +1,73 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+	"github.com/kedacore/keda/pkg/eventemitter"
+)
+
+func TestScaleWebhooks(t *testing.T) {
+	var requests []scaleWebhookRequest
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		request := scaleWebhookRequest{}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		requests = append(requests, request)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = kedav1alpha1.AddToScheme(scheme)
+	scaledObject := &kedav1alpha1.ScaledObject{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "worker"},
+		Spec: kedav1alpha1.ScaledObjectSpec{
+			ScaleTargetRef: &kedav1alpha1.ScaleTarget{Name: "worker"},
+			ScaleWebhooks: &kedav1alpha1.ScaleWebhooks{
+				PreActivation:    &kedav1alpha1.ScaleWebhook{URL: server.URL + "/warm", FailurePolicy: kedav1alpha1.ScaleWebhookFailurePolicyFail},
+				PostDeactivation: &kedav1alpha1.ScaleWebhook{URL: server.URL + "/flush", FailurePolicy: kedav1alpha1.ScaleWebhookFailurePolicyFail},
+			},
+		},
+		Status: kedav1alpha1.ScaledObjectStatus{ScaleTargetKind: "apps/v1.Deployment"},
+	}
+	client := fake.NewFakeClientWithScheme(scheme, scaledObject)
+	scaleExecutor := &scaleExecutor{client: client, eventEmitter: eventemitter.NewEventEmitter(client), logger: logf.Log.WithName("test")}
+	ctx := context.TODO()
+
+	assert.NoError(t, scaleExecutor.runPreActivationWebhook(ctx, scaleExecutor.logger, scaledObject, 2))
+	assert.Equal(t, []scaleWebhookRequest{{
+		Webhook: preActivationWebhook, Namespace: "default", ScaledObject: "worker", ScaleTargetKind: "apps/v1.Deployment", ScaleTargetName: "worker", Replicas: 2,
+	}}, requests)
+
+	// the activation is postponed while the webhook with the Fail policy fails
+	status = http.StatusInternalServerError
+	assert.Error(t, scaleExecutor.runPreActivationWebhook(ctx, scaleExecutor.logger, scaledObject, 1))
+
+	// the failure of a webhook with the Ignore policy doesn't postpone the activation
+	scaledObject.Spec.ScaleWebhooks.PreActivation.FailurePolicy = ""
+	assert.NoError(t, scaleExecutor.runPreActivationWebhook(ctx, scaleExecutor.logger, scaledObject, 1))
+
+	// the postDeactivation webhook with the Fail policy is pending until it succeeds
+	scaleExecutor.runPostDeactivationWebhook(ctx, scaleExecutor.logger, scaledObject)
+	assert.True(t, scaledObject.Status.PostDeactivationWebhookPending)
+	status = http.StatusOK
+	scaleExecutor.runPostDeactivationWebhook(ctx, scaleExecutor.logger, scaledObject)
+	assert.False(t, scaledObject.Status.PostDeactivationWebhookPending)
+	assert.Equal(t, postDeactivationWebhook, requests[len(requests)-1].Webhook)
+	assert.Equal(t, int32(0), requests[len(requests)-1].Replicas)
+}
@@ -0,0 +1,77 @@
+package executor
+
+import (
+	"sort"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+)
+
+// hasTriggerCooldown returns true if a trigger defines its own cooldownPeriod, the last active time of each trigger is then kept in the status
+func hasTriggerCooldown(triggers []kedav1alpha1.ScaleTriggers) bool {
+	for _, trigger := range triggers {
+		if trigger.CooldownPeriod != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// getTriggersActivity returns the last active times of the triggers with the active triggers set to now,
+// the times of the triggers removed or whose type changed are dropped
+func getTriggersActivity(current []kedav1alpha1.TriggerActivityStatus, triggers []kedav1alpha1.ScaleTriggers, activeTriggers []int, now metav1.Time) []kedav1alpha1.TriggerActivityStatus {
+	if !hasTriggerCooldown(triggers) {
+		return nil
+	}
+
+	activity := make(map[int]kedav1alpha1.TriggerActivityStatus, len(triggers))
+	for _, status := range current {
+		if status.Index < len(triggers) && triggers[status.Index].Type == status.Type {
+			activity[status.Index] = status
+		}
+	}
+	for _, index := range activeTriggers {
+		if index >= 0 && index < len(triggers) {
+			activity[index] = kedav1alpha1.TriggerActivityStatus{Index: index, Type: triggers[index].Type, LastActiveTime: now}
+		}
+	}
+
+	result := make([]kedav1alpha1.TriggerActivityStatus, 0, len(activity))
+	for _, status := range activity {
+		result = append(result, status)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Index < result[j].Index })
+	return result
+}
+
+// getCooldownEnd returns the time the triggers stop keeping the object active, the latest end of the cooldown periods of the triggers
+// after they were last active. The triggers without cooldownPeriod use the default cooldown period, the last active time of the object
+// is used if the triggers don't define their own cooldownPeriod or weren't seen active yet. It returns false if nothing was ever active.
+func getCooldownEnd(lastActiveTime *metav1.Time, triggersActivity []kedav1alpha1.TriggerActivityStatus, triggers []kedav1alpha1.ScaleTriggers, defaultCooldownPeriod time.Duration) (time.Time, bool) {
+	var end time.Time
+	found := false
+	if hasTriggerCooldown(triggers) {
+		for _, status := range triggersActivity {
+			if status.Index >= len(triggers) || triggers[status.Index].Type != status.Type {
+				continue
+			}
+			cooldownPeriod := defaultCooldownPeriod
+			if triggers[status.Index].CooldownPeriod != nil {
+				cooldownPeriod = time.Second * time.Duration(*triggers[status.Index].CooldownPeriod)
+			}
+			if triggerEnd := status.LastActiveTime.Add(cooldownPeriod); !found || triggerEnd.After(end) {
+				end = triggerEnd
+				found = true
+			}
+		}
+	}
+	if !found {
+		if lastActiveTime == nil {
+			return time.Time{}, false
+		}
+		return lastActiveTime.Add(defaultCooldownPeriod), true
+	}
+	return end, true
+}
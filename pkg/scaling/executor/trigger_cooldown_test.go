@@ -0,0 +1,108 @@
+package executor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+)
+
+func TestGetTriggersActivity(t *testing.T) {
+	before := metav1.NewTime(time.Date(2020, 10, 1, 11, 0, 0, 0, time.UTC))
+	now := metav1.NewTime(time.Date(2020, 10, 1, 12, 0, 0, 0, time.UTC))
+	cooldownPeriod := int32(600)
+	triggers := []kedav1alpha1.ScaleTriggers{{Type: "kafka", CooldownPeriod: &cooldownPeriod}, {Type: "cron"}}
+
+	// no trigger with its own cooldownPeriod
+	assert.Nil(t, getTriggersActivity(nil, []kedav1alpha1.ScaleTriggers{{Type: "kafka"}}, []int{0}, now))
+
+	// the active triggers are set to now, the others are kept
+	current := []kedav1alpha1.TriggerActivityStatus{{Index: 0, Type: "kafka", LastActiveTime: before}}
+	assert.Equal(t, []kedav1alpha1.TriggerActivityStatus{
+		{Index: 0, Type: "kafka", LastActiveTime: before},
+		{Index: 1, Type: "cron", LastActiveTime: now},
+	}, getTriggersActivity(current, triggers, []int{1}, now))
+
+	// the triggers removed or whose type changed are dropped
+	current = []kedav1alpha1.TriggerActivityStatus{{Index: 1, Type: "prometheus", LastActiveTime: before}, {Index: 2, Type: "cron", LastActiveTime: before}}
+	assert.Equal(t, []kedav1alpha1.TriggerActivityStatus{
+		{Index: 0, Type: "kafka", LastActiveTime: now},
+	}, getTriggersActivity(current, triggers, []int{0}, now))
+}
+
+func TestGetCooldownEnd(t *testing.T) {
+	now := time.Date(2020, 10, 1, 12, 0, 0, 0, time.UTC)
+	lastActiveTime := metav1.NewTime(now)
+	defaultCooldownPeriod := 5 * time.Minute
+	kafkaCooldownPeriod := int32(600)
+	cronCooldownPeriod := int32(0)
+	triggers := []kedav1alpha1.ScaleTriggers{{Type: "kafka", CooldownPeriod: &kafkaCooldownPeriod}, {Type: "cron", CooldownPeriod: &cronCooldownPeriod}, {Type: "prometheus"}}
+
+	tests := []struct {
+		name             string
+		lastActiveTime   *metav1.Time
+		triggersActivity []kedav1alpha1.TriggerActivityStatus
+		triggers         []kedav1alpha1.ScaleTriggers
+		end              time.Time
+		wasActive        bool
+	}{
+		{
+			name: "never active",
+		},
+		{
+			name:           "without trigger cooldownPeriod",
+			lastActiveTime: &lastActiveTime,
+			triggers:       []kedav1alpha1.ScaleTriggers{{Type: "kafka"}},
+			end:            now.Add(defaultCooldownPeriod),
+			wasActive:      true,
+		},
+		{
+			name:           "triggers not seen active yet",
+			lastActiveTime: &lastActiveTime,
+			triggers:       triggers,
+			end:            now.Add(defaultCooldownPeriod),
+			wasActive:      true,
+		},
+		{
+			name:             "kept warm by kafka",
+			lastActiveTime:   &lastActiveTime,
+			triggersActivity: []kedav1alpha1.TriggerActivityStatus{{Index: 0, Type: "kafka", LastActiveTime: metav1.NewTime(now.Add(-time.Minute))}, {Index: 1, Type: "cron", LastActiveTime: lastActiveTime}},
+			triggers:         triggers,
+			end:              now.Add(9 * time.Minute),
+			wasActive:        true,
+		},
+		{
+			name:             "cron ends instantly",
+			lastActiveTime:   &lastActiveTime,
+			triggersActivity: []kedav1alpha1.TriggerActivityStatus{{Index: 0, Type: "kafka", LastActiveTime: metav1.NewTime(now.Add(-time.Hour))}, {Index: 1, Type: "cron", LastActiveTime: lastActiveTime}},
+			triggers:         triggers,
+			end:              now,
+			wasActive:        true,
+		},
+		{
+			name:             "default cooldown of the trigger without cooldownPeriod",
+			lastActiveTime:   &lastActiveTime,
+			triggersActivity: []kedav1alpha1.TriggerActivityStatus{{Index: 1, Type: "cron", LastActiveTime: lastActiveTime}, {Index: 2, Type: "prometheus", LastActiveTime: lastActiveTime}},
+			triggers:         triggers,
+			end:              now.Add(defaultCooldownPeriod),
+			wasActive:        true,
+		},
+		{
+			name:             "trigger type changed",
+			lastActiveTime:   &lastActiveTime,
+			triggersActivity: []kedav1alpha1.TriggerActivityStatus{{Index: 0, Type: "rabbitmq", LastActiveTime: lastActiveTime}},
+			triggers:         triggers,
+			end:              now.Add(defaultCooldownPeriod),
+			wasActive:        true,
+		},
+	}
+
+	for _, test := range tests {
+		end, wasActive := getCooldownEnd(test.lastActiveTime, test.triggersActivity, test.triggers, defaultCooldownPeriod)
+		assert.Equal(t, test.wasActive, wasActive, test.name)
+		assert.True(t, test.end.Equal(end), "%s: expected %s, got %s", test.name, test.end, end)
+	}
+}
@@ -0,0 +1,47 @@
+package scaling
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+)
+
+// getFallbackCondition returns the Fallback condition of a scalable object whose triggers failed with the errors
+func getFallbackCondition(triggerErrors []string) (metav1.ConditionStatus, string, string) {
+	if len(triggerErrors) == 0 {
+		return metav1.ConditionFalse, "NoTriggerError", "All the triggers are evaluated"
+	}
+	return metav1.ConditionTrue, "TriggerError", fmt.Sprintf("%d triggers failed: %s", len(triggerErrors), strings.Join(triggerErrors, "; "))
+}
+
+// updateFallbackCondition stores in the status of a ScaledObject or ScaledJob whether some of its triggers failed, if it changed
+func (h *scaleHandler) updateFallbackCondition(ctx context.Context, scalableObject interface{}, triggerErrors []string) {
+	status, reason, message := getFallbackCondition(triggerErrors)
+
+	var object runtime.Object
+	var conditions *kedav1alpha1.Conditions
+	var patch client.Patch
+	switch obj := scalableObject.(type) {
+	case *kedav1alpha1.ScaledObject:
+		object, conditions, patch = obj, &obj.Status.Conditions, client.MergeFrom(obj.DeepCopy())
+	case *kedav1alpha1.ScaledJob:
+		object, conditions, patch = obj, &obj.Status.Conditions, client.MergeFrom(obj.DeepCopy())
+	default:
+		return
+	}
+
+	current := conditions.GetFallbackCondition()
+	if current.Status == status && current.Reason == reason && current.Message == message {
+		return
+	}
+	conditions.SetFallbackCondition(status, reason, message)
+	if err := h.client.Status().Patch(ctx, object, patch); err != nil {
+		h.logger.Error(err, "Failed to patch the Fallback condition", "object", scalableObject)
+	}
+}
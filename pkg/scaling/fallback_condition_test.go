@@ -0,0 +1,33 @@
+package scaling
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type fallbackConditionTestData struct {
+	triggerErrors   []string
+	expectedStatus  metav1.ConditionStatus
+	expectedReason  string
+	expectedMessage string
+}
+
+var fallbackConditionTestDataset = []fallbackConditionTestData{
+	// no trigger failed
+	{nil, metav1.ConditionFalse, "NoTriggerError", "All the triggers are evaluated"},
+	// one trigger failed
+	{[]string{"trigger #0: connection refused"}, metav1.ConditionTrue, "TriggerError", "1 triggers failed: trigger #0: connection refused"},
+	// several triggers failed
+	{[]string{"trigger #0: timeout", "trigger #2: not found"}, metav1.ConditionTrue, "TriggerError", "2 triggers failed: trigger #0: timeout; trigger #2: not found"},
+}
+
+func TestGetFallbackCondition(t *testing.T) {
+	for _, testData := range fallbackConditionTestDataset {
+		status, reason, message := getFallbackCondition(testData.triggerErrors)
+		if status != testData.expectedStatus || reason != testData.expectedReason || message != testData.expectedMessage {
+			t.Errorf("Expected %s %s %q for %v, got %s %s %q", testData.expectedStatus, testData.expectedReason, testData.expectedMessage,
+				testData.triggerErrors, status, reason, message)
+		}
+	}
+}
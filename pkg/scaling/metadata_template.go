@@ -0,0 +1,56 @@
+package scaling
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+)
+
+// templateDelimiter marks the metadata values rendered as Go templates for the triggers with renderMetadataTemplates,
+// the other values are used as they are
+const templateDelimiter = "{{"
+
+// templateObject is the scalable object exposed to the trigger metadata templates,
+// e.g. {{.ScaledObject.Namespace}} or {{index .ScaledJob.Labels "app"}}
+type templateObject struct {
+	Name        string
+	Namespace   string
+	Labels      map[string]string
+	Annotations map[string]string
+}
+
+// renderTriggerMetadata returns the trigger metadata with the Go templates of its values executed against the scalable object,
+// so a single trigger definition can be shared by many ScaledObjects or ScaledJobs. The templates are only rendered if the trigger
+// opts in with renderMetadataTemplates, so the values which contain {{ as a literal, e.g. in a query, are left unchanged
+func renderTriggerMetadata(withTriggers *kedav1alpha1.WithTriggers, trigger kedav1alpha1.ScaleTriggers) (map[string]string, error) {
+	var data map[string]templateObject
+	rendered := make(map[string]string, len(trigger.Metadata))
+	for key, value := range trigger.Metadata {
+		if !trigger.RenderMetadataTemplates || !strings.Contains(value, templateDelimiter) {
+			rendered[key] = value
+			continue
+		}
+		if data == nil {
+			object := templateObject{
+				Name:        withTriggers.Name,
+				Namespace:   withTriggers.Namespace,
+				Labels:      withTriggers.Labels,
+				Annotations: withTriggers.Annotations,
+			}
+			data = map[string]templateObject{withTriggers.Kind: object}
+		}
+
+		tmpl, err := template.New(key).Option("missingkey=error").Parse(value)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing template of metadata %s: %s", key, err)
+		}
+		var builder strings.Builder
+		if err := tmpl.Execute(&builder, data); err != nil {
+			return nil, fmt.Errorf("error rendering template of metadata %s: %s", key, err)
+		}
+		rendered[key] = builder.String()
+	}
+	return rendered, nil
+}
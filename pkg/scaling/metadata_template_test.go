@@ -0,0 +1,45 @@
+package scaling
+
+import (
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+)
+
+func TestRenderTriggerMetadata(t *testing.T) {
+	withTriggers := &kedav1alpha1.WithTriggers{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "apps", Name: "web", Labels: map[string]string{"app": "frontend"}},
+	}
+	withTriggers.Kind = "ScaledObject"
+
+	tests := []struct {
+		metadata map[string]string
+		render   bool
+		expected map[string]string
+		isError  bool
+	}{
+		// values without template are unchanged
+		{map[string]string{"threshold": "10", "query": "sum(up)"}, true, map[string]string{"threshold": "10", "query": "sum(up)"}, false},
+		// name, namespace and labels of the ScaledObject
+		{map[string]string{"query": `sum(rate(http_requests_total{namespace="{{.ScaledObject.Namespace}}",app="{{index .ScaledObject.Labels "app"}}"}[2m]))`, "metricName": "{{.ScaledObject.Name}}-requests"},
+			true, map[string]string{"query": `sum(rate(http_requests_total{namespace="apps",app="frontend"}[2m]))`, "metricName": "web-requests"}, false},
+		// the object is exposed under its own kind
+		{map[string]string{"query": "{{.ScaledJob.Name}}"}, true, nil, true},
+		// malformed template
+		{map[string]string{"query": "{{.ScaledObject.Name"}, true, nil, true},
+		// the values of the triggers not rendering templates are unchanged, even if they contain {{
+		{map[string]string{"query": "{{.ScaledObject.Name}}", "format": "{{ value }}"}, false, map[string]string{"query": "{{.ScaledObject.Name}}", "format": "{{ value }}"}, false},
+	}
+	for _, test := range tests {
+		rendered, err := renderTriggerMetadata(withTriggers, kedav1alpha1.ScaleTriggers{Metadata: test.metadata, RenderMetadataTemplates: test.render})
+		if (err != nil) != test.isError {
+			t.Errorf("%v: expected error %t, got %v", test.metadata, test.isError, err)
+		}
+		if err == nil && !reflect.DeepEqual(rendered, test.expected) {
+			t.Errorf("Expected %v, got %v", test.expected, rendered)
+		}
+	}
+}
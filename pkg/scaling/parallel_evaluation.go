@@ -0,0 +1,131 @@
+package scaling
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/kedacore/keda/pkg/scalers"
+)
+
+// Default number of triggers of a ScaledObject evaluated at the same time
+const defaultTriggerEvaluationConcurrency = 4
+
+// TriggerEvaluationConfig bounds the concurrent evaluation of the triggers of a ScaledObject
+type TriggerEvaluationConfig struct {
+	// Concurrency is the number of triggers of a ScaledObject evaluated at the same time, 1 evaluates them sequentially
+	Concurrency int
+	// Timeout is the time limit of the evaluation of a single trigger, the polling interval of the ScaledObject is used if not set
+	Timeout time.Duration
+}
+
+var triggerEvaluationConfig = TriggerEvaluationConfig{Concurrency: defaultTriggerEvaluationConcurrency}
+
+// ConfigureTriggerEvaluation sets how the triggers of the ScaledObjects are evaluated, it must be called before the scale loops are started
+func ConfigureTriggerEvaluation(config TriggerEvaluationConfig) error {
+	if config.Concurrency < 1 {
+		return fmt.Errorf("the trigger evaluation concurrency must be at least 1, got %d", config.Concurrency)
+	}
+	if config.Timeout < 0 {
+		return fmt.Errorf("the trigger evaluation timeout must not be negative, got %s", config.Timeout)
+	}
+	triggerEvaluationConfig = config
+	return nil
+}
+
+// getTriggerEvaluationTimeout returns the configured timeout of the evaluation of a trigger or the polling interval if not set,
+// a trigger evaluated for longer would delay the next check
+func getTriggerEvaluationTimeout(pollingInterval *int32) time.Duration {
+	if triggerEvaluationConfig.Timeout > 0 {
+		return triggerEvaluationConfig.Timeout
+	}
+	if pollingInterval != nil && *pollingInterval > 0 {
+		return time.Second * time.Duration(*pollingInterval)
+	}
+	return time.Second * time.Duration(defaultPollingInterval)
+}
+
+// scalerActivity is the result of IsActive of a scaler
+type scalerActivity struct {
+	isActive bool
+	// isZero is true if the scaler is inactive and its metrics are zero, it is only checked if requested
+	isZero bool
+	err    error
+}
+
+// evaluateScalersActivity calls IsActive of the scalers, at most concurrency at a time and each within the timeout if set,
+// the scalers are closed once evaluated and the results are returned in the order of the scalers so the aggregation is deterministic.
+// If checkZero is true the metrics of the inactive scalers are also read to check they are zero
+func evaluateScalersActivity(ctx context.Context, ss []scalers.Scaler, concurrency int, timeout time.Duration, checkZero bool) []scalerActivity {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	results := make([]scalerActivity, len(ss))
+	workers := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, scaler := range ss {
+		workers <- struct{}{}
+		wg.Add(1)
+		go func(i int, scaler scalers.Scaler) {
+			defer wg.Done()
+			defer func() { <-workers }()
+			results[i] = evaluateScalerActivity(ctx, scaler, timeout, checkZero)
+		}(i, scaler)
+	}
+	wg.Wait()
+
+	return results
+}
+
+// evaluateScalerActivity calls IsActive of the scaler and returns once it is done or the timeout expired,
+// a scaler not honouring the context is closed when IsActive eventually returns
+func evaluateScalerActivity(ctx context.Context, scaler scalers.Scaler, timeout time.Duration, checkZero bool) scalerActivity {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	done := make(chan scalerActivity, 1)
+	go func() {
+		activity := scalerActivity{}
+		activity.isActive, activity.err = isScalerActive(ctx, scaler)
+		if checkZero && activity.err == nil && !activity.isActive {
+			activity.isZero = areScalerMetricsZero(ctx, scaler)
+		}
+		scaler.Close()
+		done <- activity
+	}()
+
+	select {
+	case activity := <-done:
+		return activity
+	case <-ctx.Done():
+		if ctx.Err() == context.DeadlineExceeded {
+			return scalerActivity{err: fmt.Errorf("trigger evaluation timed out after %s", timeout)}
+		}
+		return scalerActivity{err: ctx.Err()}
+	}
+}
+
+// areScalerMetricsZero returns true if the external metrics of the scaler are zero, a scaler without external metrics
+// has nothing queued and a scaler failing to read its metrics isn't known to be zero
+func areScalerMetricsZero(ctx context.Context, scaler scalers.Scaler) bool {
+	for _, metricSpec := range scaler.GetMetricSpecForScaling() {
+		if metricSpec.External == nil {
+			continue
+		}
+		metrics, err := getScalerMetrics(ctx, scaler, metricSpec.External.Metric.Name)
+		if err != nil {
+			return false
+		}
+		for _, metric := range metrics {
+			if !metric.Value.IsZero() {
+				return false
+			}
+		}
+	}
+	return true
+}
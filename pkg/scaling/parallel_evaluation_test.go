@@ -0,0 +1,109 @@
+package scaling
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kedacore/keda/pkg/scalers"
+)
+
+type activityTestScaler struct {
+	scalers.Scaler
+	delay    time.Duration
+	isActive bool
+	err      error
+	running  *int32
+	maxSeen  *int32
+	mutex    *sync.Mutex
+	closed   int32
+}
+
+func (s *activityTestScaler) IsActive(ctx context.Context) (bool, error) {
+	running := atomic.AddInt32(s.running, 1)
+	defer atomic.AddInt32(s.running, -1)
+	s.mutex.Lock()
+	if running > *s.maxSeen {
+		*s.maxSeen = running
+	}
+	s.mutex.Unlock()
+
+	time.Sleep(s.delay)
+	return s.isActive, s.err
+}
+
+func (s *activityTestScaler) Close() error {
+	atomic.AddInt32(&s.closed, 1)
+	return nil
+}
+
+func TestEvaluateScalersActivity(t *testing.T) {
+	var running, maxSeen int32
+	mutex := &sync.Mutex{}
+	newScaler := func(delay time.Duration, isActive bool, err error) *activityTestScaler {
+		return &activityTestScaler{delay: delay, isActive: isActive, err: err, running: &running, maxSeen: &maxSeen, mutex: mutex}
+	}
+
+	testScalers := []*activityTestScaler{
+		newScaler(30*time.Millisecond, false, nil),
+		newScaler(10*time.Millisecond, true, nil),
+		newScaler(20*time.Millisecond, false, fmt.Errorf("query failed")),
+		newScaler(time.Second, true, nil),
+		newScaler(0, true, nil),
+	}
+	ss := make([]scalers.Scaler, len(testScalers))
+	for i, scaler := range testScalers {
+		ss[i] = scaler
+	}
+
+	results := evaluateScalersActivity(context.TODO(), ss, 2, 200*time.Millisecond, false)
+
+	expected := []struct {
+		isActive bool
+		isError  bool
+	}{{false, false}, {true, false}, {false, true}, {false, true}, {true, false}}
+	for i, result := range results {
+		if result.isActive != expected[i].isActive || (result.err != nil) != expected[i].isError {
+			t.Errorf("Trigger #%d: expected active %t and error %t, got %t and %v", i, expected[i].isActive, expected[i].isError, result.isActive, result.err)
+		}
+	}
+	if maxSeen > 2 {
+		t.Errorf("Expected at most 2 triggers evaluated at the same time, got %d", maxSeen)
+	}
+	if maxSeen < 2 {
+		t.Errorf("Expected the triggers to be evaluated concurrently, got %d at most", maxSeen)
+	}
+	for i, scaler := range testScalers[:3] {
+		if atomic.LoadInt32(&scaler.closed) != 1 {
+			t.Errorf("Expected the scaler of trigger #%d to be closed", i)
+		}
+	}
+}
+
+func TestGetTriggerEvaluationTimeout(t *testing.T) {
+	defer func(config TriggerEvaluationConfig) { triggerEvaluationConfig = config }(triggerEvaluationConfig)
+
+	if timeout := getTriggerEvaluationTimeout(nil); timeout != defaultPollingInterval*time.Second {
+		t.Errorf("Expected the default polling interval, got %s", timeout)
+	}
+	if timeout := getTriggerEvaluationTimeout(int32Ptr(5)); timeout != 5*time.Second {
+		t.Errorf("Expected the polling interval, got %s", timeout)
+	}
+
+	if err := ConfigureTriggerEvaluation(TriggerEvaluationConfig{Concurrency: 1, Timeout: time.Second}); err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	if timeout := getTriggerEvaluationTimeout(int32Ptr(5)); timeout != time.Second {
+		t.Errorf("Expected the configured timeout, got %s", timeout)
+	}
+
+	if err := ConfigureTriggerEvaluation(TriggerEvaluationConfig{Concurrency: 0}); err == nil {
+		t.Error("Expected an error for a concurrency of 0")
+	}
+	if err := ConfigureTriggerEvaluation(TriggerEvaluationConfig{Concurrency: 1, Timeout: -time.Second}); err == nil {
+		t.Error("Expected an error for a negative timeout")
+	}
+}
@@ -0,0 +1,62 @@
+package scaling
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"golang.org/x/time/rate"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+)
+
+// scalerRateLimiters keeps a token bucket per trigger, the scalers are built for every check
+// so the bucket has to outlive the scaler instances for the limit to apply
+type scalerRateLimiters struct {
+	mutex    sync.Mutex
+	limiters map[string]*rate.Limiter
+}
+
+func newScalerRateLimiters() *scalerRateLimiters {
+	return &scalerRateLimiters{
+		limiters: make(map[string]*rate.Limiter),
+	}
+}
+
+func rateLimiterKeyPrefix(withTriggers *kedav1alpha1.WithTriggers) string {
+	return fmt.Sprintf("%s/%s/%s/", withTriggers.UID, withTriggers.Namespace, withTriggers.Name)
+}
+
+// get returns the limiter of the trigger of the scalable object, the limiter is updated if the rate limit changed
+func (r *scalerRateLimiters) get(withTriggers *kedav1alpha1.WithTriggers, triggerIndex int, qps rate.Limit, burst int) *rate.Limiter {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	key := fmt.Sprintf("%s%d", rateLimiterKeyPrefix(withTriggers), triggerIndex)
+	limiter, ok := r.limiters[key]
+	if !ok {
+		limiter = rate.NewLimiter(qps, burst)
+		r.limiters[key] = limiter
+		return limiter
+	}
+	if limiter.Limit() != qps {
+		limiter.SetLimit(qps)
+	}
+	if limiter.Burst() != burst {
+		limiter.SetBurst(burst)
+	}
+	return limiter
+}
+
+// delete removes the limiters of the triggers of the scalable object
+func (r *scalerRateLimiters) delete(withTriggers *kedav1alpha1.WithTriggers) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	prefix := rateLimiterKeyPrefix(withTriggers)
+	for key := range r.limiters {
+		if strings.HasPrefix(key, prefix) {
+			delete(r.limiters, key)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package scaling
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+)
+
+func TestScalerRateLimiters(t *testing.T) {
+	limiters := newScalerRateLimiters()
+	withTriggers := &kedav1alpha1.WithTriggers{ObjectMeta: metav1.ObjectMeta{UID: "uid", Namespace: "default", Name: "app"}}
+	other := &kedav1alpha1.WithTriggers{ObjectMeta: metav1.ObjectMeta{UID: "other", Namespace: "default", Name: "other"}}
+
+	first := limiters.get(withTriggers, 0, 1, 1)
+	if limiters.get(withTriggers, 0, 1, 1) != first {
+		t.Error("Expected the same limiter for the same trigger")
+	}
+	if limiters.get(withTriggers, 1, 1, 1) == first {
+		t.Error("Expected another limiter for another trigger")
+	}
+	limiters.get(other, 0, 1, 1)
+
+	updated := limiters.get(withTriggers, 0, 5, 10)
+	if updated != first || updated.Limit() != 5 || updated.Burst() != 10 {
+		t.Errorf("Expected the limiter to be updated to qps 5 and burst 10, got %v and %d", updated.Limit(), updated.Burst())
+	}
+
+	limiters.delete(withTriggers)
+	if len(limiters.limiters) != 1 {
+		t.Errorf("Expected only the limiter of the other object to be kept, got %d limiters", len(limiters.limiters))
+	}
+	if limiters.get(withTriggers, 0, 1, 1) == first {
+		t.Error("Expected a new limiter after the delete")
+	}
+}
@@ -0,0 +1,260 @@
+package resolver
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+	"github.com/kedacore/keda/pkg/globalconfig"
+	kedautil "github.com/kedacore/keda/pkg/util"
+)
+
+var (
+	// conjurClientCertPath is where Conjur injects the client certificate of the authn-k8s authentication
+	conjurClientCertPath = "/etc/conjur/ssl/client.pem"
+	// conjurClientCertTimeout is how long the client certificate is waited for once its injection was requested
+	conjurClientCertTimeout      = 30 * time.Second
+	conjurClientCertPollInterval = time.Second
+	// conjurKubernetesMutex serializes the authn-k8s authentications of the pod, their certificates are injected at the same path
+	conjurKubernetesMutex sync.Mutex
+)
+
+// ConjurHandler reads the variables of CyberArk Conjur with its REST API
+type ConjurHandler struct {
+	conjur                *kedav1alpha1.Conjur
+	apiKey                string
+	secretFileDirectories []string
+	tlsConfig             *tls.Config
+	client                *http.Client
+	accessToken           string
+}
+
+// NewConjurHandler creates a ConjurHandler object, the API key is the one of the credential resolved from its Secret
+func NewConjurHandler(c *kedav1alpha1.Conjur, apiKey string) *ConjurHandler {
+	return &ConjurHandler{
+		conjur:                c,
+		apiKey:                apiKey,
+		secretFileDirectories: globalconfig.Get().SecretFileDirectories,
+	}
+}
+
+// Initialize authenticates to Conjur, the access token is valid for the reads of the variables of a resolution
+func (ch *ConjurHandler) Initialize(ctx context.Context) error {
+	if ch.conjur.ApplianceURL == "" || ch.conjur.Account == "" {
+		return errors.New("Conjur applianceURL and account are required")
+	}
+
+	if ch.conjur.CACert != "" {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM([]byte(ch.conjur.CACert)) {
+			return errors.New("error parsing the Conjur caCert")
+		}
+		ch.tlsConfig = &tls.Config{RootCAs: pool}
+		ch.client = ch.newClient()
+	}
+
+	authnURL, body, contentType, err := ch.authnRequest(ctx)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, authnURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+	token, err := ch.do(req, http.StatusOK)
+	if err != nil {
+		return fmt.Errorf("error authenticating to Conjur: %s", err)
+	}
+	ch.accessToken = base64.StdEncoding.EncodeToString(token)
+	return nil
+}
+
+// newClient returns the HTTP client of the TLS configuration of the handler
+func (ch *ConjurHandler) newClient() *http.Client {
+	return &http.Client{
+		Timeout:   globalconfig.Get().HTTPTimeout,
+		Transport: &http.Transport{TLSClientConfig: ch.tlsConfig},
+	}
+}
+
+// authnRequest returns the URL, the body and its content type of the authentication request of the authentication method
+func (ch *ConjurHandler) authnRequest(ctx context.Context) (string, []byte, string, error) {
+	applianceURL := strings.TrimSuffix(ch.conjur.ApplianceURL, "/")
+	credential := ch.conjur.Credential
+	if credential == nil {
+		credential = &kedav1alpha1.ConjurCredential{}
+	}
+
+	switch ch.conjur.Authentication {
+	case kedav1alpha1.ConjurAuthenticationAPIKey:
+		if credential.HostID == "" || ch.apiKey == "" {
+			return "", nil, "", errors.New("Conjur hostID and apiKey are required for the apiKey authentication")
+		}
+		authnURL := fmt.Sprintf("%s/authn/%s/%s/authenticate", applianceURL, escapeConjurID(ch.conjur.Account), escapeConjurID(credential.HostID))
+		return authnURL, []byte(ch.apiKey), "text/plain", nil
+	case kedav1alpha1.ConjurAuthenticationJWT:
+		if ch.conjur.ServiceID == "" {
+			return "", nil, "", errors.New("Conjur serviceID is required for the jwt authentication")
+		}
+		if credential.ServiceAccount == "" {
+			return "", nil, "", errors.New("K8s SA file not in config")
+		}
+		jwt, err := kedautil.ReadSecretFile(credential.ServiceAccount, ch.secretFileDirectories)
+		if err != nil {
+			return "", nil, "", err
+		}
+		// the host is identified by the claims of the JWT unless its ID is given
+		authnURL := fmt.Sprintf("%s/authn-jwt/%s/%s", applianceURL, escapeConjurID(ch.conjur.ServiceID), escapeConjurID(ch.conjur.Account))
+		if credential.HostID != "" {
+			authnURL += "/" + escapeConjurID(credential.HostID)
+		}
+		form := url.Values{"jwt": {strings.TrimSpace(jwt)}}
+		return authnURL + "/authenticate", []byte(form.Encode()), "application/x-www-form-urlencoded", nil
+	case kedav1alpha1.ConjurAuthenticationKubernetes:
+		if ch.conjur.ServiceID == "" || credential.HostID == "" {
+			return "", nil, "", errors.New("Conjur serviceID and hostID are required for the kubernetes authentication")
+		}
+		certificate, err := ch.injectClientCert(ctx, credential.HostID)
+		if err != nil {
+			return "", nil, "", err
+		}
+		// the host is authenticated by the client certificate
+		tlsConfig := &tls.Config{Certificates: []tls.Certificate{certificate}}
+		if ch.tlsConfig != nil {
+			tlsConfig.RootCAs = ch.tlsConfig.RootCAs
+		}
+		ch.tlsConfig = tlsConfig
+		ch.client = ch.newClient()
+		authnURL := fmt.Sprintf("%s/authn-k8s/%s/%s/%s/authenticate", applianceURL, escapeConjurID(ch.conjur.ServiceID), escapeConjurID(ch.conjur.Account), escapeConjurID(credential.HostID))
+		return authnURL, nil, "text/plain", nil
+	default:
+		return "", nil, "", fmt.Errorf("Conjur auth method %s is not supported", ch.conjur.Authentication)
+	}
+}
+
+// injectClientCert requests Conjur to inject a client certificate of the host into the pod, identified by its POD_NAME and POD_NAMESPACE
+// environment variables, and returns the certificate with its private key once it was written
+func (ch *ConjurHandler) injectClientCert(ctx context.Context, hostID string) (tls.Certificate, error) {
+	podName, podNamespace := os.Getenv("POD_NAME"), os.Getenv("POD_NAMESPACE")
+	if podName == "" || podNamespace == "" {
+		return tls.Certificate{}, errors.New("the POD_NAME and POD_NAMESPACE environment variables are required for the Conjur kubernetes authentication")
+	}
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	spiffeID := &url.URL{Scheme: "spiffe", Host: "cluster.local", Path: fmt.Sprintf("/namespace/%s/pod/%s", podNamespace, podName)}
+	// the common name is the last segment of the host ID, the other ones are sent as its prefix joined by dots
+	segments := strings.Split(hostID, "/")
+	template := &x509.CertificateRequest{Subject: pkix.Name{CommonName: segments[len(segments)-1]}, URIs: []*url.URL{spiffeID}}
+	csr, err := x509.CreateCertificateRequest(rand.Reader, template, key)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	conjurKubernetesMutex.Lock()
+	defer conjurKubernetesMutex.Unlock()
+
+	injectURL := fmt.Sprintf("%s/authn-k8s/%s/inject_client_cert", strings.TrimSuffix(ch.conjur.ApplianceURL, "/"), escapeConjurID(ch.conjur.ServiceID))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, injectURL, bytes.NewReader(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: csr})))
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	req.Header.Set("Content-Type", "text/plain")
+	req.Header.Set("Host-Id-Prefix", strings.Join(segments[:len(segments)-1], "."))
+	if _, err := ch.do(req, http.StatusAccepted); err != nil {
+		return tls.Certificate{}, fmt.Errorf("error requesting the Conjur client certificate: %s", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, conjurClientCertTimeout)
+	defer cancel()
+	ticker := time.NewTicker(conjurClientCertPollInterval)
+	defer ticker.Stop()
+	for {
+		if certificate, ok := readClientCert(key); ok {
+			return tls.Certificate{Certificate: [][]byte{certificate.Raw}, PrivateKey: key, Leaf: certificate}, nil
+		}
+		select {
+		case <-ctx.Done():
+			return tls.Certificate{}, fmt.Errorf("the Conjur client certificate wasn't injected at %s within %s", conjurClientCertPath, conjurClientCertTimeout)
+		case <-ticker.C:
+		}
+	}
+}
+
+// readClientCert returns the injected client certificate if it is the one of the key, the certificate of a previous authentication may not be replaced yet
+func readClientCert(key *rsa.PrivateKey) (*x509.Certificate, bool) {
+	content, err := ioutil.ReadFile(conjurClientCertPath)
+	if err != nil {
+		return nil, false
+	}
+	block, _ := pem.Decode(content)
+	if block == nil {
+		return nil, false
+	}
+	certificate, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, false
+	}
+	publicKey, ok := certificate.PublicKey.(*rsa.PublicKey)
+	if !ok || publicKey.N.Cmp(key.N) != 0 || publicKey.E != key.E {
+		return nil, false
+	}
+	return certificate, true
+}
+
+// Read returns the value of the variable
+func (ch *ConjurHandler) Read(ctx context.Context, variableID string) (string, error) {
+	secretURL := fmt.Sprintf("%s/secrets/%s/variable/%s", strings.TrimSuffix(ch.conjur.ApplianceURL, "/"), escapeConjurID(ch.conjur.Account), escapeConjurID(variableID))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, secretURL, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("Token token=%q", ch.accessToken))
+	value, err := ch.do(req, http.StatusOK)
+	if err != nil {
+		return "", err
+	}
+	return string(value), nil
+}
+
+// do sends the request and returns the body of the response, an error if its status isn't the expected one
+func (ch *ConjurHandler) do(req *http.Request, expectedStatus int) ([]byte, error) {
+	resp, err := kedautil.DoWithRetry(ch.client, req, kedautil.DefaultRetryPolicy())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != expectedStatus {
+		return nil, fmt.Errorf("Conjur responded with status %d", resp.StatusCode)
+	}
+	return body, nil
+}
+
+// escapeConjurID escapes an ID for a path segment of the Conjur API, which requires its slashes to be escaped
+func escapeConjurID(id string) string {
+	return strings.ReplaceAll(url.QueryEscape(id), "+", "%20")
+}
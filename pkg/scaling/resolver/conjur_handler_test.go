@@ -0,0 +1,209 @@
+package resolver
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+)
+
+const conjurTestToken = `{"protected":"eyJhbGciOiJjb25qdXIub3JnL3Nsb3NpbG8vdjIifQ==","payload":"e30=","signature":"c2ln"}`
+
+// newConjurTestServer serves the authentication of the host/keda/operator host and of the JWT, and the variables of the keda account
+func newConjurTestServer(tls bool) *httptest.Server {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		switch r.URL.EscapedPath() {
+		case "/authn/keda/host%2Fkeda%2Foperator/authenticate":
+			if r.Method != http.MethodPost || string(body) != "api-key" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, _ = w.Write([]byte(conjurTestToken))
+		case "/authn-jwt/k8s-cluster/keda/authenticate":
+			if r.Method != http.MethodPost || string(body) != "jwt=service-account-jwt" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, _ = w.Write([]byte(conjurTestToken))
+		case "/secrets/keda/variable/apps%2Fqueue%2Fconnection%20string":
+			if r.Header.Get("Authorization") != `Token token="`+base64.StdEncoding.EncodeToString([]byte(conjurTestToken))+`"` {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, _ = w.Write([]byte("Endpoint=sb://queue"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	if tls {
+		return httptest.NewTLSServer(handler)
+	}
+	return httptest.NewServer(handler)
+}
+
+func TestConjurHandler(t *testing.T) {
+	server := newConjurTestServer(false)
+	defer server.Close()
+
+	jwtFile, err := ioutil.TempFile("", "jwt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(jwtFile.Name())
+	_, _ = jwtFile.WriteString("service-account-jwt\n")
+	jwtFile.Close()
+
+	tests := []struct {
+		name                  string
+		conjur                kedav1alpha1.Conjur
+		apiKey                string
+		secretFileDirectories []string
+		isError               bool
+	}{
+		{"api key", kedav1alpha1.Conjur{Authentication: kedav1alpha1.ConjurAuthenticationAPIKey, Credential: &kedav1alpha1.ConjurCredential{HostID: "host/keda/operator"}}, "api-key", nil, false},
+		{"jwt", kedav1alpha1.Conjur{Authentication: kedav1alpha1.ConjurAuthenticationJWT, ServiceID: "k8s-cluster", Credential: &kedav1alpha1.ConjurCredential{ServiceAccount: jwtFile.Name()}}, "", []string{filepath.Dir(jwtFile.Name())}, false},
+		{"jwt outside of the secret file directories", kedav1alpha1.Conjur{Authentication: kedav1alpha1.ConjurAuthenticationJWT, ServiceID: "k8s-cluster", Credential: &kedav1alpha1.ConjurCredential{ServiceAccount: jwtFile.Name()}}, "", nil, true},
+		{"wrong api key", kedav1alpha1.Conjur{Authentication: kedav1alpha1.ConjurAuthenticationAPIKey, Credential: &kedav1alpha1.ConjurCredential{HostID: "host/keda/operator"}}, "wrong", nil, true},
+		{"missing api key", kedav1alpha1.Conjur{Authentication: kedav1alpha1.ConjurAuthenticationAPIKey}, "", nil, true},
+		{"missing service id", kedav1alpha1.Conjur{Authentication: kedav1alpha1.ConjurAuthenticationJWT, Credential: &kedav1alpha1.ConjurCredential{ServiceAccount: jwtFile.Name()}}, "", []string{filepath.Dir(jwtFile.Name())}, true},
+		{"unsupported auth", kedav1alpha1.Conjur{Authentication: "authn-iam"}, "", nil, true},
+	}
+	for _, test := range tests {
+		test.conjur.ApplianceURL = server.URL + "/"
+		test.conjur.Account = "keda"
+		handler := NewConjurHandler(&test.conjur, test.apiKey)
+		handler.secretFileDirectories = test.secretFileDirectories
+		err := handler.Initialize(context.TODO())
+		if test.isError {
+			if err == nil {
+				t.Errorf("%s: expected an error", test.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error %s", test.name, err)
+			continue
+		}
+		value, err := handler.Read(context.TODO(), "apps/queue/connection string")
+		if err != nil || value != "Endpoint=sb://queue" {
+			t.Errorf("%s: expected the value of the variable, got %q %v", test.name, value, err)
+		}
+		if _, err := handler.Read(context.TODO(), "apps/missing"); err == nil {
+			t.Errorf("%s: expected an error for a missing variable", test.name)
+		}
+	}
+}
+
+func TestConjurHandlerCACert(t *testing.T) {
+	server := newConjurTestServer(true)
+	defer server.Close()
+
+	conjur := &kedav1alpha1.Conjur{
+		ApplianceURL:   server.URL,
+		Account:        "keda",
+		Authentication: kedav1alpha1.ConjurAuthenticationAPIKey,
+		Credential:     &kedav1alpha1.ConjurCredential{HostID: "host/keda/operator"},
+	}
+	// the certificate of the test server isn't trusted by the system
+	if err := NewConjurHandler(conjur, "api-key").Initialize(context.TODO()); err == nil {
+		t.Error("Expected an error without the CA certificate of the server")
+	}
+
+	conjur.CACert = string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}))
+	if err := NewConjurHandler(conjur, "api-key").Initialize(context.TODO()); err != nil {
+		t.Errorf("Unexpected error with the CA certificate of the server %s", err)
+	}
+
+	conjur.CACert = "not a certificate"
+	if err := NewConjurHandler(conjur, "api-key").Initialize(context.TODO()); err == nil {
+		t.Error("Expected an error for a malformed CA certificate")
+	}
+}
+
+func TestConjurHandlerKubernetes(t *testing.T) {
+	caKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	caTemplate := &x509.Certificate{SerialNumber: big.NewInt(1), Subject: pkix.Name{CommonName: "conjur-ca"}, NotAfter: time.Now().Add(time.Hour), IsCA: true,
+		BasicConstraintsValid: true, KeyUsage: x509.KeyUsageCertSign}
+	caDER, _ := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	ca, _ := x509.ParseCertificate(caDER)
+
+	certDir, err := ioutil.TempDir("", "conjur")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(certDir)
+	conjurClientCertPath = filepath.Join(certDir, "client.pem")
+	conjurClientCertTimeout = time.Second
+	conjurClientCertPollInterval = 10 * time.Millisecond
+	os.Setenv("POD_NAME", "keda-operator-abcde")
+	os.Setenv("POD_NAMESPACE", "keda")
+	defer os.Unsetenv("POD_NAME")
+	defer os.Unsetenv("POD_NAMESPACE")
+
+	// the server signs the CSR of the pod and writes the certificate like the injection into the container would
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		switch r.URL.EscapedPath() {
+		case "/authn-k8s/k8s-cluster/inject_client_cert":
+			block, _ := pem.Decode(body)
+			csr, err := x509.ParseCertificateRequest(block.Bytes)
+			if err != nil || r.Header.Get("Host-Id-Prefix") != "host.keda" || csr.Subject.CommonName != "operator" ||
+				len(csr.URIs) != 1 || csr.URIs[0].String() != "spiffe://cluster.local/namespace/keda/pod/keda-operator-abcde" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			template := &x509.Certificate{SerialNumber: big.NewInt(2), Subject: csr.Subject, URIs: csr.URIs, NotAfter: time.Now().Add(time.Hour),
+				ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}}
+			der, _ := x509.CreateCertificate(rand.Reader, template, ca, csr.PublicKey, caKey)
+			_ = ioutil.WriteFile(conjurClientCertPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600)
+			w.WriteHeader(http.StatusAccepted)
+		case "/authn-k8s/k8s-cluster/keda/host%2Fkeda%2Foperator/authenticate":
+			if len(r.TLS.PeerCertificates) == 0 || r.TLS.PeerCertificates[0].Subject.CommonName != "operator" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, _ = w.Write([]byte(conjurTestToken))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequestClientCert}
+	server.StartTLS()
+	defer server.Close()
+
+	conjur := &kedav1alpha1.Conjur{
+		ApplianceURL:   server.URL,
+		Account:        "keda",
+		Authentication: kedav1alpha1.ConjurAuthenticationKubernetes,
+		ServiceID:      "k8s-cluster",
+		Credential:     &kedav1alpha1.ConjurCredential{HostID: "host/keda/operator"},
+		CACert:         string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})),
+	}
+	handler := NewConjurHandler(conjur, "")
+	if err := handler.Initialize(context.TODO()); err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	if handler.accessToken != base64.StdEncoding.EncodeToString([]byte(conjurTestToken)) {
+		t.Errorf("Expected the access token of the host, got %s", handler.accessToken)
+	}
+
+	conjur.Credential.HostID = ""
+	if err := NewConjurHandler(conjur, "").Initialize(context.TODO()); err == nil {
+		t.Error("Expected an error without the host ID")
+	}
+}
@@ -0,0 +1,57 @@
+package resolver
+
+import (
+	"fmt"
+	"strings"
+
+	kedautil "github.com/kedacore/keda/pkg/util"
+)
+
+const (
+	fromEnvSuffix  = "FromEnv"
+	fromFileSuffix = "FromFile"
+	// fromFileEnvPrefix prefixes the names the files of the <key>FromFile metadata are stored with in the resolved environment,
+	// they can't collide with the names of the environment variables of the scale target
+	fromFileEnvPrefix = "keda.sh/file:"
+)
+
+// ResolveFileParameters reads the files named by the <key>FromFile metadata of a trigger into the resolved environment and points
+// the <key>FromEnv metadata at them, so the scalers reading their parameters from <key>FromEnv read the files as well.
+// The files must be in one of the directories. A <key>FromEnv found in the environment of the scale target takes precedence.
+// The metadata and the environment are copied before they are changed
+func ResolveFileParameters(triggerMetadata, resolvedEnv map[string]string, directories []string) (map[string]string, map[string]string, error) {
+	var metadata, env map[string]string
+	for metadataKey, path := range triggerMetadata {
+		key := strings.TrimSuffix(metadataKey, fromFileSuffix)
+		if key == metadataKey || key == "" || path == "" {
+			continue
+		}
+		if envName := triggerMetadata[key+fromEnvSuffix]; envName != "" && resolvedEnv[envName] != "" {
+			continue
+		}
+		value, err := kedautil.ReadSecretFile(path, directories)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error reading %s: %s", metadataKey, err)
+		}
+
+		if metadata == nil {
+			metadata = copyStringMap(triggerMetadata)
+			env = copyStringMap(resolvedEnv)
+		}
+		envName := fromFileEnvPrefix + path
+		env[envName] = value
+		metadata[key+fromEnvSuffix] = envName
+	}
+	if metadata == nil {
+		return triggerMetadata, resolvedEnv, nil
+	}
+	return metadata, env, nil
+}
+
+func copyStringMap(m map[string]string) map[string]string {
+	copied := make(map[string]string, len(m)+1)
+	for key, value := range m {
+		copied[key] = value
+	}
+	return copied
+}
@@ -0,0 +1,50 @@
+package resolver
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestResolveFileParameters(t *testing.T) {
+	dir, err := ioutil.TempDir("", "secrets")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "connection")
+	if err := ioutil.WriteFile(path, []byte("Endpoint=sb://queue\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	triggerMetadata := map[string]string{"queueName": "orders", "connectionFromFile": path}
+	resolvedEnv := map[string]string{"OTHER": "value"}
+	metadata, env, err := ResolveFileParameters(triggerMetadata, resolvedEnv, []string{dir})
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	if value := env[metadata["connectionFromEnv"]]; value != "Endpoint=sb://queue" {
+		t.Errorf("Expected connectionFromEnv to resolve to the content of the file, got %q", value)
+	}
+	if _, ok := triggerMetadata["connectionFromEnv"]; ok || len(resolvedEnv) != 1 {
+		t.Error("Expected the metadata and the environment of the caller not to be changed")
+	}
+
+	// the environment of the scale target takes precedence
+	triggerMetadata["connectionFromEnv"] = "CONNECTION"
+	resolvedEnv["CONNECTION"] = "Endpoint=sb://env"
+	metadata, env, err = ResolveFileParameters(triggerMetadata, resolvedEnv, []string{dir})
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	if value := env[metadata["connectionFromEnv"]]; value != "Endpoint=sb://env" {
+		t.Errorf("Expected connectionFromEnv to resolve to the environment, got %q", value)
+	}
+
+	// the files outside of the directories can't be read
+	delete(triggerMetadata, "connectionFromEnv")
+	if _, _, err := ResolveFileParameters(triggerMetadata, resolvedEnv, nil); err == nil {
+		t.Error("Expected an error for a file outside of the directories")
+	}
+}
@@ -54,46 +54,17 @@ func ResolveAuthRef(client client.Client, logger logr.Logger, triggerAuthRef *ke
 		} else {
 			if triggerAuth.Spec.PodIdentity != nil {
 				podIdentity = string(triggerAuth.Spec.PodIdentity.Provider)
-			}
-			if triggerAuth.Spec.Env != nil {
-				for _, e := range triggerAuth.Spec.Env {
-					if podSpec == nil {
-						result[e.Parameter] = ""
-						continue
-					}
-					env, err := ResolveContainerEnv(client, logger, podSpec, e.ContainerName, namespace)
-					if err != nil {
-						result[e.Parameter] = ""
-					} else {
-						result[e.Parameter] = env[e.Name]
-					}
-				}
-			}
-			if triggerAuth.Spec.SecretTargetRef != nil {
-				for _, e := range triggerAuth.Spec.SecretTargetRef {
-					result[e.Parameter] = resolveAuthSecret(client, logger, e.Name, namespace, e.Key)
-				}
-			}
-			if triggerAuth.Spec.HashiCorpVault != nil && len(triggerAuth.Spec.HashiCorpVault.Secrets) > 0 {
-				vault := NewHashicorpVaultHandler(triggerAuth.Spec.HashiCorpVault)
-				err := vault.Initialize(logger)
-				if err != nil {
-					logger.Error(err, "Error authenticate to Vault", "triggerAuthRef.Name", triggerAuthRef.Name)
-				} else {
-					for _, e := range triggerAuth.Spec.HashiCorpVault.Secrets {
-						secret, err := vault.Read(e.Path)
-						if err != nil {
-							logger.Error(err, "Error trying to read secret from Vault", "triggerAuthRef.Name", triggerAuthRef.Name,
-								"secret.path", e.Path)
-							continue
-						}
-
-						result[e.Parameter] = resolveVaultSecret(logger, secret.Data, e.Key)
-					}
-
-					vault.Stop()
+				if triggerAuth.Spec.PodIdentity.RoleArn != "" && (podIdentity == kedav1alpha1.PodIdentityProviderAwsEKS || podIdentity == kedav1alpha1.PodIdentityProviderAwsKiam) {
+					result["awsRoleArn"] = triggerAuth.Spec.PodIdentity.RoleArn
 				}
 			}
+			resolveSecretProviders(&SecretProviderContext{
+				Client:                client,
+				Logger:                logger,
+				Namespace:             namespace,
+				PodSpec:               podSpec,
+				TriggerAuthentication: triggerAuth,
+			}, result)
 		}
 	}
 
@@ -207,6 +207,46 @@ func TestResolveAuthRef(t *testing.T) {
 			expected:            map[string]string{"host": secretData},
 			expectedPodIdentity: "none",
 		},
+		{
+			name: "triggerauth exists, aws-eks podidentity with role",
+			existing: []runtime.Object{
+				&kedav1alpha1.TriggerAuthentication{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: namespace,
+						Name:      triggerAuthenticationName,
+					},
+					Spec: kedav1alpha1.TriggerAuthenticationSpec{
+						PodIdentity: &kedav1alpha1.AuthPodIdentity{
+							Provider: kedav1alpha1.PodIdentityProviderAwsEKS,
+							RoleArn:  "arn:aws:iam::123456789012:role/sqs-reader",
+						},
+					},
+				},
+			},
+			soar:                &kedav1alpha1.ScaledObjectAuthRef{Name: triggerAuthenticationName},
+			expected:            map[string]string{"awsRoleArn": "arn:aws:iam::123456789012:role/sqs-reader"},
+			expectedPodIdentity: "aws-eks",
+		},
+		{
+			name: "triggerauth exists, azure podidentity with role",
+			existing: []runtime.Object{
+				&kedav1alpha1.TriggerAuthentication{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: namespace,
+						Name:      triggerAuthenticationName,
+					},
+					Spec: kedav1alpha1.TriggerAuthenticationSpec{
+						PodIdentity: &kedav1alpha1.AuthPodIdentity{
+							Provider: kedav1alpha1.PodIdentityProviderAzure,
+							RoleArn:  "arn:aws:iam::123456789012:role/sqs-reader",
+						},
+					},
+				},
+			},
+			soar:                &kedav1alpha1.ScaledObjectAuthRef{Name: triggerAuthenticationName},
+			expected:            make(map[string]string),
+			expectedPodIdentity: "azure",
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
@@ -0,0 +1,218 @@
+package resolver
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+)
+
+// SecretProviderContext is what a SecretProvider resolves the parameters of a TriggerAuthentication with
+type SecretProviderContext struct {
+	Client client.Client
+	Logger logr.Logger
+	// Namespace is the namespace of the TriggerAuthentication and of the scalable object
+	Namespace string
+	// PodSpec is the pod spec of the scale target, nil if it has no pod template
+	PodSpec *corev1.PodSpec
+	// TriggerAuthentication is the TriggerAuthentication referenced by the trigger
+	TriggerAuthentication *kedav1alpha1.TriggerAuthentication
+	// Secrets are the secretProviders of the TriggerAuthentication using the provider, empty for the built-in providers
+	Secrets []kedav1alpha1.AuthSecretProviderRef
+}
+
+// SecretProvider resolves the parameters of a TriggerAuthentication from a secret store
+type SecretProvider interface {
+	// Resolve returns the values of the parameters of the TriggerAuthentication the provider is responsible for,
+	// the parameters which can't be resolved are returned with an empty value along with the error
+	Resolve(ctx *SecretProviderContext) (map[string]string, error)
+}
+
+// SecretProviderFunc is a function implementing SecretProvider
+type SecretProviderFunc func(ctx *SecretProviderContext) (map[string]string, error)
+
+// Resolve calls the function
+func (f SecretProviderFunc) Resolve(ctx *SecretProviderContext) (map[string]string, error) {
+	return f(ctx)
+}
+
+// builtinSecretProviders resolve the env, secretTargetRef, hashiCorpVault and conjur of a TriggerAuthentication,
+// in this order so the later ones take precedence for a parameter
+var builtinSecretProviders = []struct {
+	name     string
+	provider SecretProvider
+}{
+	{"env", SecretProviderFunc(resolveEnvAuth)},
+	{"secretTargetRef", SecretProviderFunc(resolveSecretTargetRefAuth)},
+	{"hashiCorpVault", SecretProviderFunc(resolveHashiCorpVaultAuth)},
+	{"conjur", SecretProviderFunc(resolveConjurAuth)},
+}
+
+var (
+	secretProvidersMutex sync.RWMutex
+	secretProviders      = make(map[string]SecretProvider)
+)
+
+// RegisterSecretProvider makes a secret provider available for the secretProviders of the TriggerAuthentications with the given provider name,
+// it is meant to be called from the init function of the provider package, which is then imported for its side effects by the binary
+// embedding KEDA. RegisterSecretProvider panics if the name is empty, the provider is nil or the name is already registered.
+func RegisterSecretProvider(name string, provider SecretProvider) {
+	if name == "" {
+		panic("resolver: RegisterSecretProvider with an empty name")
+	}
+	if provider == nil {
+		panic(fmt.Sprintf("resolver: RegisterSecretProvider of %s with a nil provider", name))
+	}
+
+	secretProvidersMutex.Lock()
+	defer secretProvidersMutex.Unlock()
+	if _, ok := secretProviders[name]; ok {
+		panic(fmt.Sprintf("resolver: RegisterSecretProvider called twice for %s", name))
+	}
+	secretProviders[name] = provider
+}
+
+// LookupSecretProvider returns the secret provider registered with the name
+func LookupSecretProvider(name string) (SecretProvider, bool) {
+	secretProvidersMutex.RLock()
+	defer secretProvidersMutex.RUnlock()
+	provider, ok := secretProviders[name]
+	return provider, ok
+}
+
+// resolveSecretProviders resolves the parameters of the TriggerAuthentication with the built-in providers, then with the registered
+// providers of its secretProviders sorted by name. The errors are logged, the parameters not resolved are set to an empty value.
+func resolveSecretProviders(ctx *SecretProviderContext, result map[string]string) {
+	resolve := func(name string, provider SecretProvider, ctx *SecretProviderContext) {
+		params, err := provider.Resolve(ctx)
+		if err != nil {
+			ctx.Logger.Error(err, "Error resolving the parameters of the triggerAuth", "triggerAuthRef.Name", ctx.TriggerAuthentication.Name, "provider", name)
+		}
+		for parameter, value := range params {
+			result[parameter] = value
+		}
+	}
+
+	for _, builtin := range builtinSecretProviders {
+		resolve(builtin.name, builtin.provider, ctx)
+	}
+
+	byProvider := make(map[string][]kedav1alpha1.AuthSecretProviderRef)
+	for _, secret := range ctx.TriggerAuthentication.Spec.SecretProviders {
+		byProvider[secret.Provider] = append(byProvider[secret.Provider], secret)
+	}
+	names := make([]string, 0, len(byProvider))
+	for name := range byProvider {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		provider, ok := LookupSecretProvider(name)
+		if !ok {
+			ctx.Logger.Error(fmt.Errorf("secret provider %s isn't registered", name), "Error resolving the parameters of the triggerAuth", "triggerAuthRef.Name", ctx.TriggerAuthentication.Name)
+			for _, secret := range byProvider[name] {
+				result[secret.Parameter] = ""
+			}
+			continue
+		}
+		providerCtx := *ctx
+		providerCtx.Secrets = byProvider[name]
+		resolve(name, provider, &providerCtx)
+	}
+}
+
+// resolveEnvAuth resolves the env of the TriggerAuthentication from the containers of the scale target
+func resolveEnvAuth(ctx *SecretProviderContext) (map[string]string, error) {
+	result := make(map[string]string)
+	for _, e := range ctx.TriggerAuthentication.Spec.Env {
+		if ctx.PodSpec == nil {
+			result[e.Parameter] = ""
+			continue
+		}
+		env, err := ResolveContainerEnv(ctx.Client, ctx.Logger, ctx.PodSpec, e.ContainerName, ctx.Namespace)
+		if err != nil {
+			result[e.Parameter] = ""
+		} else {
+			result[e.Parameter] = env[e.Name]
+		}
+	}
+	return result, nil
+}
+
+// resolveSecretTargetRefAuth resolves the secretTargetRef of the TriggerAuthentication from the Kubernetes secrets of its namespace
+func resolveSecretTargetRefAuth(ctx *SecretProviderContext) (map[string]string, error) {
+	result := make(map[string]string)
+	for _, e := range ctx.TriggerAuthentication.Spec.SecretTargetRef {
+		result[e.Parameter] = resolveAuthSecret(ctx.Client, ctx.Logger, e.Name, ctx.Namespace, e.Key)
+	}
+	return result, nil
+}
+
+// resolveHashiCorpVaultAuth resolves the hashiCorpVault secrets of the TriggerAuthentication
+func resolveHashiCorpVaultAuth(ctx *SecretProviderContext) (map[string]string, error) {
+	hashiCorpVault := ctx.TriggerAuthentication.Spec.HashiCorpVault
+	if hashiCorpVault == nil || len(hashiCorpVault.Secrets) == 0 {
+		return nil, nil
+	}
+
+	vault := NewHashicorpVaultHandler(hashiCorpVault)
+	if err := vault.Initialize(ctx.Logger); err != nil {
+		return nil, fmt.Errorf("error authenticate to Vault: %s", err)
+	}
+	defer vault.Stop()
+
+	result := make(map[string]string)
+	for _, e := range hashiCorpVault.Secrets {
+		secret, err := vault.Read(e.Path)
+		if err != nil {
+			ctx.Logger.Error(err, "Error trying to read secret from Vault", "triggerAuthRef.Name", ctx.TriggerAuthentication.Name,
+				"secret.path", e.Path)
+			continue
+		}
+
+		result[e.Parameter] = resolveVaultSecret(ctx.Logger, secret.Data, e.Key)
+	}
+	return result, nil
+}
+
+// resolveConjurAuth resolves the conjur secrets of the TriggerAuthentication from the Conjur variables
+func resolveConjurAuth(ctx *SecretProviderContext) (map[string]string, error) {
+	conjur := ctx.TriggerAuthentication.Spec.Conjur
+	if conjur == nil || len(conjur.Secrets) == 0 {
+		return nil, nil
+	}
+
+	var apiKey string
+	if conjur.Credential != nil && conjur.Credential.APIKey != nil {
+		ref := conjur.Credential.APIKey.SecretKeyRef
+		apiKey = resolveAuthSecret(ctx.Client, ctx.Logger, ref.Name, ctx.Namespace, ref.Key)
+	}
+
+	result := make(map[string]string)
+	handler := NewConjurHandler(conjur, apiKey)
+	if err := handler.Initialize(context.TODO()); err != nil {
+		for _, e := range conjur.Secrets {
+			result[e.Parameter] = ""
+		}
+		return result, err
+	}
+
+	for _, e := range conjur.Secrets {
+		value, err := handler.Read(context.TODO(), e.VariableID)
+		if err != nil {
+			ctx.Logger.Error(err, "Error trying to read variable from Conjur", "triggerAuthRef.Name", ctx.TriggerAuthentication.Name,
+				"variable.id", e.VariableID)
+			result[e.Parameter] = ""
+			continue
+		}
+		result[e.Parameter] = value
+	}
+	return result, nil
+}
@@ -0,0 +1,75 @@
+package resolver
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+)
+
+func init() {
+	// the test store returns the name and key of the secrets, or an error for the secrets without key
+	RegisterSecretProvider("test-store", SecretProviderFunc(func(ctx *SecretProviderContext) (map[string]string, error) {
+		result := make(map[string]string)
+		var err error
+		for _, secret := range ctx.Secrets {
+			if secret.Key == "" {
+				result[secret.Parameter] = ""
+				err = fmt.Errorf("no key given for secret %s", secret.Name)
+				continue
+			}
+			result[secret.Parameter] = secret.Name + "/" + secret.Key + secret.Metadata["suffix"]
+		}
+		return result, err
+	}))
+}
+
+func TestRegisterSecretProviderTwice(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected RegisterSecretProvider to panic for a name already registered")
+		}
+	}()
+	RegisterSecretProvider("test-store", SecretProviderFunc(func(ctx *SecretProviderContext) (map[string]string, error) {
+		return nil, nil
+	}))
+}
+
+func TestResolveAuthRefSecretProviders(t *testing.T) {
+	corev1.AddToScheme(scheme.Scheme)
+	kedav1alpha1.AddToScheme(scheme.Scheme)
+
+	triggerAuth := &kedav1alpha1.TriggerAuthentication{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: triggerAuthenticationName},
+		Spec: kedav1alpha1.TriggerAuthenticationSpec{
+			SecretTargetRef: []kedav1alpha1.AuthSecretTargetRef{
+				{Parameter: "host", Name: secretName, Key: secretKey},
+				{Parameter: "password", Name: secretName, Key: secretKey},
+			},
+			SecretProviders: []kedav1alpha1.AuthSecretProviderRef{
+				{Provider: "test-store", Parameter: "password", Name: "db", Key: "password", Metadata: map[string]string{"suffix": "!"}},
+				{Provider: "test-store", Parameter: "user", Name: "db"},
+				{Provider: "unknown-store", Parameter: "token", Name: "api"},
+			},
+		},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: secretName},
+		Data:       map[string][]byte{secretKey: []byte(secretData)},
+	}
+
+	authParams, _ := ResolveAuthRef(fake.NewFakeClientWithScheme(scheme.Scheme, triggerAuth, secret), logf.Log.WithName("test"),
+		&kedav1alpha1.ScaledObjectAuthRef{Name: triggerAuthenticationName}, nil, namespace)
+	// the registered providers take precedence over the built-in ones, the parameters not resolved are empty
+	expected := map[string]string{"host": secretData, "password": "db/password!", "user": "", "token": ""}
+	if diff := cmp.Diff(authParams, expected); diff != "" {
+		t.Errorf("Returned authParams are different: %s", diff)
+	}
+}
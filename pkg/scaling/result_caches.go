@@ -0,0 +1,55 @@
+package scaling
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+	"github.com/kedacore/keda/pkg/scalers"
+)
+
+// scalerResultCaches keeps a result cache per trigger, the scalers are built for every check
+// so the cache has to outlive the scaler instances for the results to be shared
+type scalerResultCaches struct {
+	mutex  sync.Mutex
+	caches map[string]*scalers.ScalerResultCache
+}
+
+func newScalerResultCaches() *scalerResultCaches {
+	return &scalerResultCaches{
+		caches: make(map[string]*scalers.ScalerResultCache),
+	}
+}
+
+// get returns the result cache of the trigger of the scalable object, the cache is updated if the ttl changed
+func (r *scalerResultCaches) get(withTriggers *kedav1alpha1.WithTriggers, triggerIndex int, ttl time.Duration) *scalers.ScalerResultCache {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	key := fmt.Sprintf("%s%d", rateLimiterKeyPrefix(withTriggers), triggerIndex)
+	cache, ok := r.caches[key]
+	if !ok {
+		cache = scalers.NewScalerResultCache(ttl)
+		r.caches[key] = cache
+		return cache
+	}
+	if cache.TTL() != ttl {
+		cache.SetTTL(ttl)
+	}
+	return cache
+}
+
+// delete removes the result caches of the triggers of the scalable object
+func (r *scalerResultCaches) delete(withTriggers *kedav1alpha1.WithTriggers) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	prefix := rateLimiterKeyPrefix(withTriggers)
+	for key := range r.caches {
+		if strings.HasPrefix(key, prefix) {
+			delete(r.caches, key)
+		}
+	}
+}
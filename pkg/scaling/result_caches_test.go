@@ -0,0 +1,38 @@
+package scaling
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+)
+
+func TestScalerResultCaches(t *testing.T) {
+	caches := newScalerResultCaches()
+	withTriggers := &kedav1alpha1.WithTriggers{ObjectMeta: metav1.ObjectMeta{UID: "uid", Namespace: "default", Name: "app"}}
+	other := &kedav1alpha1.WithTriggers{ObjectMeta: metav1.ObjectMeta{UID: "other", Namespace: "default", Name: "other"}}
+
+	first := caches.get(withTriggers, 0, time.Second)
+	if caches.get(withTriggers, 0, time.Second) != first {
+		t.Error("Expected the same cache for the same trigger")
+	}
+	if caches.get(withTriggers, 1, time.Second) == first {
+		t.Error("Expected another cache for another trigger")
+	}
+	caches.get(other, 0, time.Second)
+
+	updated := caches.get(withTriggers, 0, 10*time.Second)
+	if updated != first || updated.TTL() != 10*time.Second {
+		t.Errorf("Expected the cache to be updated to a ttl of 10s, got %s", updated.TTL())
+	}
+
+	caches.delete(withTriggers)
+	if len(caches.caches) != 1 {
+		t.Errorf("Expected only the cache of the other object to be kept, got %d caches", len(caches.caches))
+	}
+	if caches.get(withTriggers, 0, time.Second) == first {
+		t.Error("Expected a new cache after the delete")
+	}
+}
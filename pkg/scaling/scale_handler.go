@@ -3,29 +3,43 @@ package scaling
 import (
 	"context"
 	"fmt"
+	"reflect"
+	"strconv"
 	"sync"
 	"time"
 
 	"github.com/go-logr/logr"
+	"go.opentelemetry.io/otel/label"
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/scale"
+	"k8s.io/metrics/pkg/apis/external_metrics"
 	"knative.dev/pkg/apis/duck"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
 	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+	"github.com/kedacore/keda/pkg/audit"
+	"github.com/kedacore/keda/pkg/eventemitter"
+	"github.com/kedacore/keda/pkg/globalconfig"
+	prommetrics "github.com/kedacore/keda/pkg/metrics"
 	"github.com/kedacore/keda/pkg/scalers"
+	"github.com/kedacore/keda/pkg/scalersdk"
 	"github.com/kedacore/keda/pkg/scaling/executor"
 	"github.com/kedacore/keda/pkg/scaling/resolver"
+	"github.com/kedacore/keda/pkg/tracing"
 )
 
 const (
 	// Default polling interval for a ScaledObject triggers if no pollingInterval is defined.
 	defaultPollingInterval = 30
+	// Default maxReplicaCount of the HPA if no maxReplicaCount is defined, used to compute the desired replicas in dry-run mode.
+	defaultHPAMaxReplicas = 100
 )
 
 // ScaleHandler encapsulates the logic of calling the right scalers for
@@ -34,6 +48,7 @@ type ScaleHandler interface {
 	HandleScalableObject(scalableObject interface{}) error
 	DeleteScalableObject(scalableObject interface{}) error
 	GetScalers(scalableObject interface{}) ([]scalers.Scaler, error)
+	EvaluateTrigger(ctx context.Context, namespace, scaledObjectName string, trigger kedav1alpha1.ScaleTriggers) (*TriggerEvaluation, error)
 }
 
 type scaleHandler struct {
@@ -41,15 +56,22 @@ type scaleHandler struct {
 	logger            logr.Logger
 	scaleLoopContexts *sync.Map
 	scaleExecutor     executor.ScaleExecutor
+	eventEmitter      eventemitter.EventEmitter
+	rateLimiters      *scalerRateLimiters
+	resultCaches      *scalerResultCaches
 }
 
 // NewScaleHandler creates a ScaleHandler object
 func NewScaleHandler(client client.Client, scaleClient *scale.ScalesGetter, reconcilerScheme *runtime.Scheme) ScaleHandler {
+	eventEmitter := eventemitter.NewEventEmitter(client)
 	return &scaleHandler{
 		client:            client,
 		logger:            logf.Log.WithName("scalehandler"),
 		scaleLoopContexts: &sync.Map{},
-		scaleExecutor:     executor.NewScaleExecutor(client, scaleClient, reconcilerScheme),
+		scaleExecutor:     executor.NewScaleExecutor(client, scaleClient, reconcilerScheme, eventEmitter),
+		eventEmitter:      eventEmitter,
+		rateLimiters:      newScalerRateLimiters(),
+		resultCaches:      newScalerResultCaches(),
 	}
 }
 
@@ -64,7 +86,7 @@ func (h *scaleHandler) GetScalers(scalableObject interface{}) ([]scalers.Scaler,
 		return nil, err
 	}
 
-	return h.buildScalers(withTriggers, podTemplateSpec, containerName)
+	return h.buildScalers(withTriggers, podTemplateSpec, containerName, h.scaleTargetReplicas(scalableObject))
 }
 
 func (h *scaleHandler) HandleScalableObject(scalableObject interface{}) error {
@@ -114,23 +136,24 @@ func (h *scaleHandler) DeleteScalableObject(scalableObject interface{}) error {
 	} else {
 		h.logger.V(1).Info("ScaleObject was not found in controller cache", "key", key)
 	}
+	h.rateLimiters.delete(withTriggers)
+	h.resultCaches.delete(withTriggers)
 
 	return nil
 }
 
-// startScaleLoop blocks forever and checks the scaledObject based on its pollingInterval
+// startScaleLoop blocks forever and checks the scaledObject based on its pollingInterval, or its activationPollingInterval while scaled to zero
 func (h *scaleHandler) startScaleLoop(ctx context.Context, withTriggers *kedav1alpha1.WithTriggers, scalableObject interface{}, scalingMutex *sync.Mutex) {
 	logger := h.logger.WithValues("type", withTriggers.Kind, "namespace", withTriggers.Namespace, "name", withTriggers.Name)
 
 	// kick off one check to the scalers now
 	h.checkScalers(ctx, scalableObject, scalingMutex)
 
-	pollingInterval := getPollingInterval(withTriggers)
-	logger.V(1).Info("Watching with pollingInterval", "PollingInterval", pollingInterval)
+	logger.V(1).Info("Watching with pollingInterval", "PollingInterval", getPollingInterval(withTriggers))
 
 	for {
 		select {
-		case <-time.After(pollingInterval):
+		case <-time.After(getScaleLoopInterval(withTriggers, scalableObject, scalingMutex)):
 			h.checkScalers(ctx, scalableObject, scalingMutex)
 		case <-ctx.Done():
 			logger.V(1).Info("Context canceled")
@@ -147,11 +170,12 @@ func (h *scaleHandler) startPushScalers(ctx context.Context, withTriggers *kedav
 		return
 	}
 
-	for _, s := range ss {
+	for i, s := range ss {
 		scaler, ok := s.(scalers.PushScaler)
 		if !ok {
 			continue
 		}
+		triggerIndex := i
 
 		go func() {
 			activeCh := make(chan bool)
@@ -164,7 +188,15 @@ func (h *scaleHandler) startPushScalers(ctx context.Context, withTriggers *kedav
 					scalingMutex.Lock()
 					switch obj := scalableObject.(type) {
 					case *kedav1alpha1.ScaledObject:
-						h.scaleExecutor.RequestScale(ctx, obj, active)
+						if obj.IsDryRun() {
+							h.logger.V(1).Info("External Push Scaler activity is not applied in dry-run mode", "object", scalableObject, "active", active)
+							break
+						}
+						var activeTriggers []int
+						if active {
+							activeTriggers = []int{triggerIndex}
+						}
+						h.scaleExecutor.RequestScale(ctx, obj, active, activeTriggers)
 					case *kedav1alpha1.ScaledJob:
 						h.logger.Info("Warning: External Push Scaler does not support ScaledJob", "object", scalableObject)
 					}
@@ -178,7 +210,26 @@ func (h *scaleHandler) startPushScalers(ctx context.Context, withTriggers *kedav
 // checkScalers contains the main logic for the ScaleHandler scaling logic.
 // It'll check each trigger active status then call RequestScale
 func (h *scaleHandler) checkScalers(ctx context.Context, scalableObject interface{}, scalingMutex *sync.Mutex) {
+	var attributes []label.KeyValue
+	if withTriggers, err := asDuckWithTriggers(scalableObject); err == nil {
+		// the scale loops of the objects no longer watched are kept but don't scale
+		if !globalconfig.Get().IsWatched(withTriggers.Namespace, withTriggers.Labels) {
+			h.logger.V(1).Info("Skipping the check of the scalers, the object isn't watched", "namespace", withTriggers.Namespace, "name", withTriggers.Name)
+			return
+		}
+		if err := globalconfig.Get().CheckTriggers(withTriggers.Spec.Triggers); err != nil {
+			h.logger.V(1).Info("Skipping the check of the scalers, the object violates the scaling policy", "namespace", withTriggers.Namespace, "name", withTriggers.Name, "reason", err.Error())
+			return
+		}
+		attributes = tracing.ObjectAttributes(withTriggers.Kind, withTriggers.Namespace, withTriggers.Name)
+		defer prommetrics.RecordScaleLoop(withTriggers.Kind, time.Now())
+	}
+	ctx, span := tracing.StartSpan(ctx, "ScaleHandler.checkScalers", attributes...)
+	defer span.End()
+
+	buildCtx, buildSpan := tracing.StartSpan(ctx, "ScaleHandler.GetScalers")
 	scalers, err := h.GetScalers(scalableObject)
+	tracing.EndSpan(buildCtx, buildSpan, err)
 	if err != nil {
 		h.logger.Error(err, "Error getting scalers", "object", scalableObject)
 		return
@@ -188,50 +239,293 @@ func (h *scaleHandler) checkScalers(ctx context.Context, scalableObject interfac
 	defer scalingMutex.Unlock()
 	switch obj := scalableObject.(type) {
 	case *kedav1alpha1.ScaledObject:
-		h.scaleExecutor.RequestScale(ctx, obj, h.checkScaledObjectScalers(ctx, scalers))
+		if obj.IsDryRun() {
+			h.checkScaledObjectScalersDryRun(ctx, scalers, obj)
+			h.updateExternalScalersStatus(ctx, scalers, obj)
+			return
+		}
+		isActive, activeTriggers, triggerErrors := h.checkScaledObjectScalers(ctx, scalers, obj)
+		h.updateExternalScalersStatus(ctx, scalers, obj)
+		h.updateFallbackCondition(ctx, obj, triggerErrors)
+		scaleCtx, scaleSpan := tracing.StartSpan(ctx, "ScaleExecutor.RequestScale", label.Bool("keda.active", isActive))
+		h.scaleExecutor.RequestScale(scaleCtx, obj, isActive, activeTriggers)
+		scaleSpan.End()
 	case *kedav1alpha1.ScaledJob:
 		scaledJob := scalableObject.(*kedav1alpha1.ScaledJob)
-		isActive, scaleTo, maxScale := h.checkScaledJobScalers(ctx, scalers, scaledJob)
-		h.scaleExecutor.RequestJobScale(ctx, obj, isActive, scaleTo, maxScale)
+		isActive, activeTriggers, scaleTo, maxScale, triggerErrors := h.checkScaledJobScalers(ctx, scalers, scaledJob)
+		h.updateFallbackCondition(ctx, obj, triggerErrors)
+		scaleCtx, scaleSpan := tracing.StartSpan(ctx, "ScaleExecutor.RequestJobScale", label.Bool("keda.active", isActive), label.Int64("keda.scaleTo", scaleTo), label.Int64("keda.maxScale", maxScale))
+		h.scaleExecutor.RequestJobScale(scaleCtx, obj, isActive, activeTriggers, scaleTo, maxScale)
+		scaleSpan.End()
 	}
 }
 
-func (h *scaleHandler) checkScaledObjectScalers(ctx context.Context, scalers []scalers.Scaler) bool {
+// isScalerActive calls IsActive of the scaler in a span
+func isScalerActive(ctx context.Context, scaler scalers.Scaler) (bool, error) {
+	scalerName := scalers.ScalerName(scaler)
+	ctx, span := tracing.StartSpan(ctx, "Scaler.IsActive", tracing.ScalerAttribute(scalerName))
+	start := time.Now()
+	isActive, err := scaler.IsActive(ctx)
+	prommetrics.RecordScalerCall(scalerName, prommetrics.ScalerOperationIsActive, start, err)
+	span.SetAttributes(label.Bool("keda.active", isActive))
+	tracing.EndSpan(ctx, span, err)
+	return isActive, err
+}
+
+// getScalerMetrics calls GetMetrics of the scaler in a span
+func getScalerMetrics(ctx context.Context, scaler scalers.Scaler, metricName string) ([]external_metrics.ExternalMetricValue, error) {
+	scalerName := scalers.ScalerName(scaler)
+	ctx, span := tracing.StartSpan(ctx, "Scaler.GetMetrics", tracing.ScalerAttribute(scalerName))
+	start := time.Now()
+	metrics, err := scaler.GetMetrics(ctx, metricName, nil)
+	prommetrics.RecordScalerCall(scalerName, prommetrics.ScalerOperationGetMetrics, start, err)
+	tracing.EndSpan(ctx, span, err)
+	return metrics, err
+}
+
+// checkScaledObjectScalers evaluates the triggers of a ScaledObject concurrently, so a slow trigger doesn't delay the others,
+// and returns true if any trigger is active with the indexes of the active triggers and the errors of the triggers that failed
+func (h *scaleHandler) checkScaledObjectScalers(ctx context.Context, scalers []scalers.Scaler, scaledObject *kedav1alpha1.ScaledObject) (bool, []int, []string) {
+	checkZero := scaledObject.Spec.WaitForZeroQueueBeforeScaleToZero != nil
+	activities := evaluateScalersActivity(ctx, scalers, triggerEvaluationConfig.Concurrency, getTriggerEvaluationTimeout(scaledObject.Spec.PollingInterval), checkZero)
+	h.updateZeroQueueChecks(ctx, scaledObject, activities)
+
 	isActive := false
-	for _, scaler := range scalers {
-		isTriggerActive, err := scaler.IsActive(ctx)
-		scaler.Close()
+	var activeTriggers []int
+	var triggerErrors []string
+	for i, scaler := range scalers {
+		isTriggerActive, err := activities[i].isActive, activities[i].err
+
+		recordTriggerDecision("ScaledObject", scaledObject.Namespace, scaledObject.Name, scaledObject.Spec.Triggers, i, scaler, nil, nil, isTriggerActive, err)
 
 		if err != nil {
 			h.logger.V(1).Info("Error getting scale decision", "Error", err)
+			h.eventEmitter.Emit("ScaledObject", scaledObject.Namespace, scaledObject.Name, eventemitter.ScalerErrorType, fmt.Sprintf("Error checking trigger #%d: %s", i, err))
+			triggerErrors = append(triggerErrors, fmt.Sprintf("trigger #%d: %s", i, err))
+			prommetrics.RecordResourceError("ScaledObject", scaledObject.Namespace, scaledObject.Name)
 			continue
 		} else if isTriggerActive {
 			isActive = true
-			h.logger.V(1).Info("Scaler for scaledObject is active", "Metrics Name", scaler.GetMetricSpecForScaling()[0].External.Metric.Name)
+			activeTriggers = append(activeTriggers, i)
+			if metricSpecs := scaler.GetMetricSpecForScaling(); len(metricSpecs) > 0 && metricSpecs[0].External != nil {
+				h.logger.V(1).Info("Scaler for scaledObject is active", "Metrics Name", metricSpecs[0].External.Metric.Name)
+			}
+		}
+	}
+	return isActive, activeTriggers, triggerErrors
+}
+
+// checkScaledObjectScalersDryRun evaluates the triggers of a ScaledObject in dry-run mode,
+// the metric values and the replica count KEDA and the HPA would scale to are stored in the status
+func (h *scaleHandler) checkScaledObjectScalersDryRun(ctx context.Context, scalers []scalers.Scaler, scaledObject *kedav1alpha1.ScaledObject) {
+	status := &kedav1alpha1.DryRunStatus{}
+	var triggerReplicas int64
+	for i, scaler := range scalers {
+		triggerStatus := kedav1alpha1.DryRunTriggerStatus{}
+		isAverageValue := true
+		drivesHPA := true
+		if i < len(scaledObject.Spec.Triggers) {
+			triggerStatus.Type = scaledObject.Spec.Triggers[i].Type
+			isAverageValue = scaledObject.Spec.Triggers[i].GetThresholdType() == autoscalingv2beta2.AverageValueMetricType
+			drivesHPA = !scaledObject.Spec.Triggers[i].UseForActivationOnly
+		}
+
+		isTriggerActive, err := isScalerActive(ctx, scaler)
+		triggerStatus.Active = isTriggerActive
+		if err != nil {
+			triggerStatus.Error = err.Error()
+		}
+
+		for _, metricSpec := range scaler.GetMetricSpecForScaling() {
+			if metricSpec.External == nil {
+				continue
+			}
+			triggerStatus.MetricName = metricSpec.External.Metric.Name
+			if metricSpec.External.Target.AverageValue != nil {
+				triggerStatus.Threshold, _ = metricSpec.External.Target.AverageValue.AsInt64()
+			}
+
+			metrics, err := getScalerMetrics(ctx, scaler, metricSpec.External.Metric.Name)
+			if err != nil {
+				triggerStatus.Error = err.Error()
+				break
+			}
+			for _, metric := range metrics {
+				metricValue, _ := metric.Value.AsInt64()
+				triggerStatus.MetricValue += metricValue
+			}
+			// the replica count for a Value threshold depends on the current replica count of the ScaleTarget, so it isn't estimated
+			if triggerStatus.Threshold > 0 && isAverageValue {
+				triggerStatus.DesiredReplicas = devideWithCeil(triggerStatus.MetricValue, triggerStatus.Threshold)
+			}
+			// the HPA is created with a single metric per trigger
 			break
 		}
+		scaler.Close()
+
+		recordTriggerDecision("ScaledObject", scaledObject.Namespace, scaledObject.Name, scaledObject.Spec.Triggers, i, scaler, &triggerStatus.MetricValue, &triggerStatus.Threshold, isTriggerActive, err)
+
+		status.Active = status.Active || isTriggerActive
+		if drivesHPA && triggerStatus.DesiredReplicas > triggerReplicas {
+			triggerReplicas = triggerStatus.DesiredReplicas
+		}
+		status.Triggers = append(status.Triggers, triggerStatus)
+	}
+
+	now := metav1.Now()
+	status.LastEvaluationTime = &now
+	status.DesiredReplicas = getDryRunDesiredReplicas(scaledObject, status.Active, triggerReplicas)
+	h.logger.Info("Evaluated ScaledObject triggers in dry-run mode", "ScaledObject.Namespace", scaledObject.Namespace, "ScaledObject.Name", scaledObject.Name,
+		"active", status.Active, "desiredReplicas", status.DesiredReplicas)
+
+	h.reportDryRunEvaluation(scaledObject, status)
+	patch := client.MergeFrom(scaledObject.DeepCopy())
+	scaledObject.Status.DryRun = status
+	if err := h.client.Status().Patch(ctx, scaledObject, patch); err != nil {
+		h.logger.Error(err, "Failed to patch ScaledObject status with the dry-run result", "ScaledObject.Namespace", scaledObject.Namespace, "ScaledObject.Name", scaledObject.Name)
+	}
+
+	if scaledObject.IsRecommending() {
+		h.updateRecommendedReplicas(ctx, scaledObject, status.DesiredReplicas)
+	}
+}
+
+// reportDryRunEvaluation emits an event if the evaluation in dry-run mode changes the activity or the replica count of the ScaledObject,
+// the status of the ScaledObject still holds the previous evaluation
+func (h *scaleHandler) reportDryRunEvaluation(scaledObject *kedav1alpha1.ScaledObject, status *kedav1alpha1.DryRunStatus) {
+	previous := scaledObject.Status.DryRun
+	if previous != nil && previous.Active == status.Active && previous.DesiredReplicas == status.DesiredReplicas {
+		return
+	}
+	h.eventEmitter.Emit("ScaledObject", scaledObject.Namespace, scaledObject.Name, eventemitter.ScaledObjectDryRunEvaluatedType,
+		fmt.Sprintf("Dry-run evaluation: the ScaledObject is active %t and would scale the scaleTargetRef to %d replicas", status.Active, status.DesiredReplicas))
+}
+
+// updateRecommendedReplicas writes the recommended replica count in the annotation of a ScaledObject in recommendation mode if it changed,
+// the annotations aren't part of the generation so the ScaleLoop isn't restarted
+func (h *scaleHandler) updateRecommendedReplicas(ctx context.Context, scaledObject *kedav1alpha1.ScaledObject, replicas int32) {
+	recommendedReplicas := strconv.Itoa(int(replicas))
+	if scaledObject.Annotations[kedav1alpha1.RecommendedReplicasAnnotation] == recommendedReplicas {
+		return
+	}
+
+	patch := client.MergeFrom(scaledObject.DeepCopy())
+	if scaledObject.Annotations == nil {
+		scaledObject.Annotations = make(map[string]string)
+	}
+	scaledObject.Annotations[kedav1alpha1.RecommendedReplicasAnnotation] = recommendedReplicas
+	if err := h.client.Patch(ctx, scaledObject, patch); err != nil {
+		h.logger.Error(err, "Failed to patch ScaledObject with the recommended replica count", "ScaledObject.Namespace", scaledObject.Namespace, "ScaledObject.Name", scaledObject.Name)
+	}
+}
+
+// updateExternalScalersStatus stores the state of the connections to the external scalers of a ScaledObject in its status if it changed
+func (h *scaleHandler) updateExternalScalersStatus(ctx context.Context, ss []scalers.Scaler, scaledObject *kedav1alpha1.ScaledObject) {
+	externalScalers, changed := getExternalScalersStatus(scaledObject.Status.ExternalScalers, ss, metav1.Now())
+	if !changed {
+		return
+	}
+
+	patch := client.MergeFrom(scaledObject.DeepCopy())
+	scaledObject.Status.ExternalScalers = externalScalers
+	if err := h.client.Status().Patch(ctx, scaledObject, patch); err != nil {
+		h.logger.Error(err, "Failed to patch ScaledObject status with the external scalers connection state", "ScaledObject.Namespace", scaledObject.Namespace, "ScaledObject.Name", scaledObject.Name)
+	}
+}
+
+// getExternalScalersStatus returns the connection state per external scaler address, the state of a scaler that wasn't called
+// in the last check is kept and the transition time is only updated when the state changes
+func getExternalScalersStatus(current []kedav1alpha1.ExternalScalerStatus, ss []scalers.Scaler, now metav1.Time) ([]kedav1alpha1.ExternalScalerStatus, bool) {
+	currentByAddress := make(map[string]kedav1alpha1.ExternalScalerStatus, len(current))
+	for _, externalScaler := range current {
+		currentByAddress[externalScaler.ScalerAddress] = externalScaler
+	}
+
+	var result []kedav1alpha1.ExternalScalerStatus
+	seen := make(map[string]int)
+	for _, scaler := range ss {
+		connectionStateScaler, ok := scalers.UnwrapScaler(scaler).(scalers.ConnectionStateScaler)
+		if !ok {
+			continue
+		}
+		address, state, message := connectionStateScaler.ConnectionState()
+
+		previous, hasPrevious := currentByAddress[address]
+		externalScaler := kedav1alpha1.ExternalScalerStatus{ScalerAddress: address, State: state, Message: message, LastTransitionTime: &now}
+		switch {
+		case state == "" && !hasPrevious:
+			continue
+		case state == "":
+			externalScaler = previous
+		case hasPrevious && previous.State == state:
+			externalScaler.LastTransitionTime = previous.LastTransitionTime
+		}
+
+		if i, ok := seen[address]; ok {
+			// triggers sharing an external scaler share its connection, the last call wins
+			if state != "" {
+				result[i] = externalScaler
+			}
+			continue
+		}
+		seen[address] = len(result)
+		result = append(result, externalScaler)
+	}
+
+	return result, !reflect.DeepEqual(current, result)
+}
+
+// getDryRunDesiredReplicas returns the replica count KEDA and the HPA would scale the ScaleTarget to:
+// minReplicaCount if no trigger is active, otherwise the highest replica count of the triggers within the HPA bounds
+func getDryRunDesiredReplicas(scaledObject *kedav1alpha1.ScaledObject, isActive bool, triggerReplicas int64) int32 {
+	minReplicas := int64(0)
+	if scaledObject.Spec.MinReplicaCount != nil {
+		minReplicas = int64(*scaledObject.Spec.MinReplicaCount)
+	}
+	if !isActive {
+		return int32(minReplicas)
+	}
+
+	maxReplicas := int64(defaultHPAMaxReplicas)
+	if scaledObject.Spec.MaxReplicaCount != nil {
+		maxReplicas = int64(*scaledObject.Spec.MaxReplicaCount)
+	}
+	// the HPA doesn't scale below one replica
+	if minReplicas < 1 {
+		minReplicas = 1
+	}
+
+	desiredReplicas := triggerReplicas
+	if desiredReplicas < minReplicas {
+		desiredReplicas = minReplicas
 	}
-	return isActive
+	if desiredReplicas > maxReplicas {
+		desiredReplicas = maxReplicas
+	}
+	return int32(desiredReplicas)
 }
 
-func (h *scaleHandler) checkScaledJobScalers(ctx context.Context, scalers []scalers.Scaler, scaledJob *kedav1alpha1.ScaledJob) (bool, int64, int64) {
+func (h *scaleHandler) checkScaledJobScalers(ctx context.Context, scalers []scalers.Scaler, scaledJob *kedav1alpha1.ScaledJob) (bool, []int, int64, int64, []string) {
 	var queueLength int64
 	var targetAverageValue int64
 	var maxValue int64
+	var activeTriggers []int
+	var triggerErrors []string
 	isActive := false
 
-	for _, scaler := range scalers {
+	for i, scaler := range scalers {
 		scalerLogger := h.logger.WithValues("Scaler", scaler)
 
-		isTriggerActive, err := scaler.IsActive(ctx)
+		isTriggerActive, err := isScalerActive(ctx, scaler)
 
 		scalerLogger.Info("Active trigger", "isTriggerActive", isTriggerActive)
 		metricSpecs := scaler.GetMetricSpecForScaling()
 
 		var metricValue int64
 		var flag bool
+		var scalerTargetValue int64
 		for _, metric := range metricSpecs {
-			if metric.External.Target.AverageValue == nil {
+			if metric.External == nil || metric.External.Target.AverageValue == nil {
 				metricValue = 0
 			} else {
 				metricValue, flag = metric.External.Target.AverageValue.AsInt64()
@@ -240,32 +534,78 @@ func (h *scaleHandler) checkScaledJobScalers(ctx context.Context, scalers []scal
 				}
 			}
 
-			targetAverageValue += metricValue
+			scalerTargetValue += metricValue
 		}
+		targetAverageValue += scalerTargetValue
 		scalerLogger.Info("Scaler targetAverageValue", "targetAverageValue", targetAverageValue)
 
-		metrics, _ := scaler.GetMetrics(ctx, "queueLength", nil)
+		metrics, _ := getScalerMetrics(ctx, scaler, "queueLength")
 
+		var scalerQueueLength int64
 		for _, m := range metrics {
 			if m.MetricName == "queueLength" {
 				metricValue, _ = m.Value.AsInt64()
-				queueLength += metricValue
+				scalerQueueLength += metricValue
 			}
 		}
+		queueLength += scalerQueueLength
 		scalerLogger.Info("QueueLength Metric value", "queueLength", queueLength)
 
 		scaler.Close()
+		recordTriggerDecision("ScaledJob", scaledJob.Namespace, scaledJob.Name, scaledJob.Spec.Triggers, i, scaler, &scalerQueueLength, &scalerTargetValue, isTriggerActive, err)
 		if err != nil {
 			scalerLogger.V(1).Info("Error getting scale decision, but continue", "Error", err)
+			h.eventEmitter.Emit("ScaledJob", scaledJob.Namespace, scaledJob.Name, eventemitter.ScalerErrorType, fmt.Sprintf("Error checking trigger #%d: %s", i, err))
+			triggerErrors = append(triggerErrors, fmt.Sprintf("trigger #%d: %s", i, err))
+			prommetrics.RecordResourceError("ScaledJob", scaledJob.Namespace, scaledJob.Name)
 			continue
 		} else if isTriggerActive {
 			isActive = true
+			activeTriggers = append(activeTriggers, i)
 			scalerLogger.Info("Scaler is active")
 		}
 	}
 	maxValue = min(scaledJob.MaxReplicaCount(), devideWithCeil(queueLength, targetAverageValue))
 	h.logger.Info("Scaler maxValue", "maxValue", maxValue)
-	return isActive, queueLength, maxValue
+	audit.Record(audit.Decision{
+		Source:          audit.SourceOperator,
+		Kind:            "ScaledJob",
+		Namespace:       scaledJob.Namespace,
+		Name:            scaledJob.Name,
+		MetricValue:     &queueLength,
+		Threshold:       &targetAverageValue,
+		Active:          &isActive,
+		DesiredReplicas: &maxValue,
+	})
+	return isActive, activeTriggers, queueLength, maxValue, triggerErrors
+}
+
+// recordTriggerDecision records the result of a single trigger of a ScaledObject or ScaledJob in the audit log
+func recordTriggerDecision(kind, namespace, name string, triggers []kedav1alpha1.ScaleTriggers, index int, scaler scalers.Scaler, metricValue, threshold *int64, isActive bool, err error) {
+	if !audit.Enabled() {
+		return
+	}
+
+	decision := audit.Decision{
+		Source:       audit.SourceOperator,
+		Kind:         kind,
+		Namespace:    namespace,
+		Name:         name,
+		TriggerIndex: &index,
+		MetricValue:  metricValue,
+		Threshold:    threshold,
+		Active:       &isActive,
+	}
+	if index < len(triggers) {
+		decision.Trigger = triggers[index].Type
+	}
+	if metricSpecs := scaler.GetMetricSpecForScaling(); len(metricSpecs) > 0 && metricSpecs[0].External != nil {
+		decision.Metric = metricSpecs[0].External.Metric.Name
+	}
+	if err != nil {
+		decision.Error = err.Error()
+	}
+	audit.Record(decision)
 }
 
 func devideWithCeil(x, y int64) int64 {
@@ -286,38 +626,79 @@ func min(x, y int64) int64 {
 }
 
 // buildScalers returns list of Scalers for the specified triggers
-func (h *scaleHandler) buildScalers(withTriggers *kedav1alpha1.WithTriggers, podTemplateSpec *corev1.PodTemplateSpec, containerName string) ([]scalers.Scaler, error) {
+func (h *scaleHandler) buildScalers(withTriggers *kedav1alpha1.WithTriggers, podTemplateSpec *corev1.PodTemplateSpec, containerName string, replicas scalers.ReplicasFunc) ([]scalers.Scaler, error) {
 	logger := h.logger.WithValues("type", withTriggers.Kind, "namespace", withTriggers.Namespace, "name", withTriggers.Name)
 	var scalersRes []scalers.Scaler
-	var err error
-	resolvedEnv := make(map[string]string)
-	if podTemplateSpec != nil {
-		resolvedEnv, err = resolver.ResolveContainerEnv(h.client, logger, &podTemplateSpec.Spec, containerName, withTriggers.Namespace)
-		if err != nil {
-			return scalersRes, fmt.Errorf("error resolving secrets for ScaleTarget: %s", err)
-		}
+	resolvedEnv, err := h.resolveEnv(logger, withTriggers.Namespace, podTemplateSpec, containerName)
+	if err != nil {
+		return scalersRes, err
 	}
 
 	for i, trigger := range withTriggers.Spec.Triggers {
-		authParams, podIdentity := resolver.ResolveAuthRef(h.client, logger, trigger.AuthenticationRef, &podTemplateSpec.Spec, withTriggers.Namespace)
-		if podIdentity == kedav1alpha1.PodIdentityProviderAwsEKS {
-			serviceAccountName := podTemplateSpec.Spec.ServiceAccountName
-			serviceAccount := &corev1.ServiceAccount{}
-			err = h.client.Get(context.TODO(), types.NamespacedName{Name: serviceAccountName, Namespace: withTriggers.Namespace}, serviceAccount)
-			if err != nil {
-				closeScalers(scalersRes)
-				return []scalers.Scaler{}, fmt.Errorf("error getting service account: %s", err)
-			}
-			authParams["awsRoleArn"] = serviceAccount.Annotations[kedav1alpha1.PodIdentityAnnotationEKS]
-		} else if podIdentity == kedav1alpha1.PodIdentityProviderAwsKiam {
-			authParams["awsRoleArn"] = podTemplateSpec.ObjectMeta.Annotations[kedav1alpha1.PodIdentityAnnotationKiam]
+		if !trigger.IsEnabled() {
+			// the disabled trigger keeps its index, its authentication isn't resolved
+			scalersRes = append(scalersRes, scalers.NewDisabledScaler())
+			continue
+		}
+
+		authParams, podIdentity, err := h.resolveAuth(logger, withTriggers.Namespace, trigger, podTemplateSpec)
+		if err != nil {
+			closeScalers(scalersRes)
+			return []scalers.Scaler{}, err
+		}
+
+		triggerMetadata, err := renderTriggerMetadata(withTriggers, trigger)
+		if err != nil {
+			closeScalers(scalersRes)
+			return []scalers.Scaler{}, fmt.Errorf("error getting scaler for trigger #%d: %s", i, err)
+		}
+		triggerMetadata, triggerEnv, err := resolver.ResolveFileParameters(triggerMetadata, resolvedEnv, globalconfig.Get().SecretFileDirectories)
+		if err != nil {
+			closeScalers(scalersRes)
+			return []scalers.Scaler{}, fmt.Errorf("error getting scaler for trigger #%d: %s", i, err)
 		}
+		setResourceTargetType(trigger, triggerMetadata)
 
-		scaler, err := buildScaler(withTriggers.Name, withTriggers.Namespace, trigger.Type, resolvedEnv, trigger.Metadata, authParams, podIdentity)
+		scaler, err := buildScaler(h.client, withTriggers.Name, withTriggers.Namespace, trigger.Type, triggerEnv, triggerMetadata, authParams, podIdentity)
 		if err != nil {
 			closeScalers(scalersRes)
 			return []scalers.Scaler{}, fmt.Errorf("error getting scaler for trigger #%d: %s", i, err)
 		}
+		if intervalScaler, ok := scaler.(scalers.PollingIntervalScaler); ok {
+			intervalScaler.SetPollingInterval(getPollingInterval(withTriggers))
+		}
+
+		scaler, err = withTimeout(trigger, scaler)
+		if err != nil {
+			scaler.Close()
+			closeScalers(scalersRes)
+			return []scalers.Scaler{}, fmt.Errorf("error getting timeout for trigger #%d: %s", i, err)
+		}
+
+		scaler = withConcurrencyLimit(trigger, scaler)
+
+		scaler, err = h.withRateLimit(withTriggers, i, trigger, scaler)
+		if err != nil {
+			scaler.Close()
+			closeScalers(scalersRes)
+			return []scalers.Scaler{}, fmt.Errorf("error getting rate limit for trigger #%d: %s", i, err)
+		}
+
+		scaler = h.withResultCache(withTriggers, i, scaler)
+
+		scaler, err = withValueExpression(trigger, scaler, replicas)
+		if err != nil {
+			scaler.Close()
+			closeScalers(scalersRes)
+			return []scalers.Scaler{}, fmt.Errorf("error getting value expression for trigger #%d: %s", i, err)
+		}
+
+		scaler, err = withDesiredReplicas(trigger, scaler)
+		if err != nil {
+			scaler.Close()
+			closeScalers(scalersRes)
+			return []scalers.Scaler{}, fmt.Errorf("error getting desired replicas for trigger #%d: %s", i, err)
+		}
 
 		scalersRes = append(scalersRes, scaler)
 	}
@@ -325,6 +706,152 @@ func (h *scaleHandler) buildScalers(withTriggers *kedav1alpha1.WithTriggers, pod
 	return scalersRes, nil
 }
 
+// setResourceTargetType sets the type of a cpu or memory trigger without one to its thresholdType, the metadata is the rendered copy of the trigger metadata
+func setResourceTargetType(trigger kedav1alpha1.ScaleTriggers, metadata map[string]string) {
+	if trigger.IsResourceTrigger() && trigger.ThresholdType != "" && metadata["type"] == "" {
+		metadata["type"] = string(trigger.ThresholdType)
+	}
+}
+
+// withTimeout wraps the scaler of the trigger so its checks are bounded by the timeout set in the trigger metadata,
+// the wait for the rate limit isn't part of the timeout. The push scalers aren't wrapped as they aren't polled.
+func withTimeout(trigger kedav1alpha1.ScaleTriggers, scaler scalers.Scaler) (scalers.Scaler, error) {
+	timeout, err := scalers.ParseTimeout(trigger.Metadata)
+	if err != nil {
+		return scaler, err
+	}
+	if _, isPushScaler := scaler.(scalers.PushScaler); timeout == 0 || isPushScaler {
+		return scaler, nil
+	}
+	return scalers.NewTimeoutScaler(scaler, timeout), nil
+}
+
+// withConcurrencyLimit wraps the scaler of the trigger so its checks share the concurrency limit of the trigger type,
+// the wait for the rate limit doesn't hold a slot. The push scalers aren't wrapped as they aren't polled.
+func withConcurrencyLimit(trigger kedav1alpha1.ScaleTriggers, scaler scalers.Scaler) scalers.Scaler {
+	slots := getScalerConcurrencySlots(trigger.Type)
+	if _, isPushScaler := scaler.(scalers.PushScaler); slots == nil || isPushScaler {
+		return scaler
+	}
+	return scalers.NewConcurrencyLimitedScaler(scaler, slots)
+}
+
+// withRateLimit wraps the scaler of the trigger in a rate limited scaler if a rate limit is set in the trigger metadata,
+// the push scalers aren't wrapped as they aren't polled
+func (h *scaleHandler) withRateLimit(withTriggers *kedav1alpha1.WithTriggers, triggerIndex int, trigger kedav1alpha1.ScaleTriggers, scaler scalers.Scaler) (scalers.Scaler, error) {
+	qps, burst, err := scalers.ParseRateLimit(trigger.Metadata)
+	if err != nil {
+		return scaler, err
+	}
+	if _, isPushScaler := scaler.(scalers.PushScaler); qps == 0 || isPushScaler {
+		return scaler, nil
+	}
+	return scalers.NewRateLimitedScaler(scaler, h.rateLimiters.get(withTriggers, triggerIndex, qps, burst)), nil
+}
+
+// withResultCache wraps the scaler of the trigger so its results are shared by the checks made within the scaler result cache TTL
+// of the global configuration, a cache hit doesn't wait for the rate limit. The push scalers aren't wrapped as they aren't polled.
+func (h *scaleHandler) withResultCache(withTriggers *kedav1alpha1.WithTriggers, triggerIndex int, scaler scalers.Scaler) scalers.Scaler {
+	ttl := globalconfig.Get().ScalerResultCacheTTL
+	if _, isPushScaler := scaler.(scalers.PushScaler); ttl == 0 || isPushScaler {
+		return scaler
+	}
+	return scalers.NewCachedScaler(scaler, h.resultCaches.get(withTriggers, triggerIndex, ttl))
+}
+
+// withValueExpression wraps the scaler of the trigger so its metric values are post-processed if a value expression is set in the trigger metadata
+func withValueExpression(trigger kedav1alpha1.ScaleTriggers, scaler scalers.Scaler, replicas scalers.ReplicasFunc) (scalers.Scaler, error) {
+	expression, err := scalers.ParseValueExpression(trigger.Metadata)
+	if err != nil || expression == nil {
+		return scaler, err
+	}
+	wrapped, err := scalers.NewValueExpressionScaler(scaler, expression, replicas)
+	if err != nil {
+		return scaler, err
+	}
+	return wrapped, nil
+}
+
+// withDesiredReplicas wraps the scaler of the trigger so it reports the replicas mapped from its metric value if the trigger has desiredReplicas,
+// the value expression is applied before the mapping
+func withDesiredReplicas(trigger kedav1alpha1.ScaleTriggers, scaler scalers.Scaler) (scalers.Scaler, error) {
+	if len(trigger.DesiredReplicas) == 0 {
+		return scaler, nil
+	}
+	steps := make([]scalers.ReplicasStep, len(trigger.DesiredReplicas))
+	for i, step := range trigger.DesiredReplicas {
+		steps[i] = scalers.ReplicasStep{FromMilli: step.From.MilliValue(), Replicas: int64(step.Replicas)}
+	}
+	wrapped, err := scalers.NewDesiredReplicasScaler(scaler, steps)
+	if err != nil {
+		return scaler, err
+	}
+	return wrapped, nil
+}
+
+// scaleTargetReplicas returns a function reading the replicas of the scale target of a ScaledObject, nil for the other objects
+func (h *scaleHandler) scaleTargetReplicas(scalableObject interface{}) scalers.ReplicasFunc {
+	scaledObject, ok := scalableObject.(*kedav1alpha1.ScaledObject)
+	if !ok || scaledObject.Status.ScaleTargetGVKR == nil {
+		return nil
+	}
+	gvk := scaledObject.Status.ScaleTargetGVKR.GroupVersionKind()
+	key := client.ObjectKey{Namespace: scaledObject.Namespace, Name: scaledObject.Spec.ScaleTargetRef.Name}
+	return func(ctx context.Context) (int64, error) {
+		unstruct := &unstructured.Unstructured{}
+		unstruct.SetGroupVersionKind(gvk)
+		if err := h.client.Get(ctx, key, unstruct); err != nil {
+			return 0, err
+		}
+		replicas, found, err := unstructured.NestedInt64(unstruct.Object, "spec", "replicas")
+		if err != nil {
+			return 0, err
+		}
+		if !found {
+			return 0, fmt.Errorf("the scale target %s has no spec.replicas", key.Name)
+		}
+		return replicas, nil
+	}
+}
+
+// resolveEnv returns the environment of the container of the scale target, it is empty if there is no pod template
+func (h *scaleHandler) resolveEnv(logger logr.Logger, namespace string, podTemplateSpec *corev1.PodTemplateSpec, containerName string) (map[string]string, error) {
+	if podTemplateSpec == nil {
+		return make(map[string]string), nil
+	}
+	resolvedEnv, err := resolver.ResolveContainerEnv(h.client, logger, &podTemplateSpec.Spec, containerName, namespace)
+	if err != nil {
+		return nil, fmt.Errorf("error resolving secrets for ScaleTarget: %s", err)
+	}
+	return resolvedEnv, nil
+}
+
+// resolveAuth returns the authentication parameters and the pod identity provider of the trigger
+func (h *scaleHandler) resolveAuth(logger logr.Logger, namespace string, trigger kedav1alpha1.ScaleTriggers, podTemplateSpec *corev1.PodTemplateSpec) (map[string]string, string, error) {
+	var podSpec *corev1.PodSpec
+	if podTemplateSpec != nil {
+		podSpec = &podTemplateSpec.Spec
+	}
+	authParams, podIdentity := resolver.ResolveAuthRef(h.client, logger, trigger.AuthenticationRef, podSpec, namespace)
+	// the role of the TriggerAuthentication takes precedence over the role of the scale target
+	if podTemplateSpec == nil || authParams["awsRoleArn"] != "" {
+		return authParams, podIdentity, nil
+	}
+
+	if podIdentity == kedav1alpha1.PodIdentityProviderAwsEKS {
+		serviceAccountName := podTemplateSpec.Spec.ServiceAccountName
+		serviceAccount := &corev1.ServiceAccount{}
+		err := h.client.Get(context.TODO(), types.NamespacedName{Name: serviceAccountName, Namespace: namespace}, serviceAccount)
+		if err != nil {
+			return nil, "", fmt.Errorf("error getting service account: %s", err)
+		}
+		authParams["awsRoleArn"] = serviceAccount.Annotations[kedav1alpha1.PodIdentityAnnotationEKS]
+	} else if podIdentity == kedav1alpha1.PodIdentityProviderAwsKiam {
+		authParams["awsRoleArn"] = podTemplateSpec.ObjectMeta.Annotations[kedav1alpha1.PodIdentityAnnotationKiam]
+	}
+	return authParams, podIdentity, nil
+}
+
 func (h *scaleHandler) getPods(scalableObject interface{}) (*corev1.PodTemplateSpec, string, error) {
 	switch obj := scalableObject.(type) {
 	case *kedav1alpha1.ScaledObject:
@@ -358,11 +885,21 @@ func (h *scaleHandler) getPods(scalableObject interface{}) (*corev1.PodTemplateS
 	}
 }
 
-func buildScaler(name, namespace, triggerType string, resolvedEnv, triggerMetadata, authParams map[string]string, podIdentity string) (scalers.Scaler, error) {
+func buildScaler(kubeClient client.Client, name, namespace, triggerType string, resolvedEnv, triggerMetadata, authParams map[string]string, podIdentity string) (scalers.Scaler, error) {
+	if !isScalerEnabled(triggerType) {
+		return nil, fmt.Errorf("scaler %s isn't enabled", triggerType)
+	}
+
 	// TRIGGERS-START
 	switch triggerType {
+	case "airflow":
+		return scalers.NewAirflowScaler(resolvedEnv, triggerMetadata, authParams)
+	case "amqp":
+		return scalers.NewAMQPScaler(resolvedEnv, triggerMetadata, authParams)
 	case "artemis-queue":
 		return scalers.NewArtemisQueueScaler(resolvedEnv, triggerMetadata, authParams)
+	case "artifact-repository":
+		return scalers.NewArtifactRepositoryScaler(resolvedEnv, triggerMetadata, authParams)
 	case "aws-cloudwatch":
 		return scalers.NewAwsCloudwatchScaler(resolvedEnv, triggerMetadata, authParams)
 	case "aws-kinesis-stream":
@@ -381,37 +918,91 @@ func buildScaler(name, namespace, triggerType string, resolvedEnv, triggerMetada
 		return scalers.NewAzureQueueScaler(resolvedEnv, triggerMetadata, authParams, podIdentity)
 	case "azure-servicebus":
 		return scalers.NewAzureServiceBusScaler(resolvedEnv, triggerMetadata, authParams, podIdentity)
+	case "bullmq":
+		return scalers.NewBullMQScaler(resolvedEnv, triggerMetadata, authParams)
+	case "celery":
+		return scalers.NewCeleryScaler(resolvedEnv, triggerMetadata, authParams)
+	case "command":
+		return scalers.NewCommandScaler(kubeClient, name, namespace, triggerMetadata)
+	case "cpu":
+		return scalers.NewCPUMemoryScaler(corev1.ResourceCPU, triggerMetadata)
 	case "cron":
 		return scalers.NewCronScaler(resolvedEnv, triggerMetadata)
+	case "envoy":
+		return scalers.NewEnvoyScaler(resolvedEnv, triggerMetadata)
 	case "external":
-		return scalers.NewExternalScaler(name, namespace, triggerMetadata, resolvedEnv)
+		return scalers.NewExternalScaler(name, namespace, triggerMetadata, resolvedEnv, authParams)
 	case "external-push":
-		return scalers.NewExternalPushScaler(name, namespace, triggerMetadata, authParams)
+		return scalers.NewExternalPushScaler(name, namespace, triggerMetadata, resolvedEnv, authParams)
+	case "forecast":
+		return scalers.NewForecastScaler(name, namespace, triggerMetadata, func(innerType string, innerMetadata map[string]string) (scalers.Scaler, error) {
+			return buildScaler(kubeClient, name, namespace, innerType, resolvedEnv, innerMetadata, authParams, podIdentity)
+		})
 	case "gcp-pubsub":
 		return scalers.NewPubSubScaler(resolvedEnv, triggerMetadata)
+	case "http-interceptor":
+		return scalers.NewHTTPInterceptorScaler(triggerMetadata)
 	case "huawei-cloudeye":
 		return scalers.NewHuaweiCloudeyeScaler(triggerMetadata, authParams)
 	case "kafka":
 		return scalers.NewKafkaScaler(resolvedEnv, triggerMetadata, authParams)
+	case "kubernetes-capacity":
+		return scalers.NewKubernetesCapacityScaler(kubeClient, namespace, triggerMetadata)
+	case "kubernetes-queue":
+		return scalers.NewKubernetesQueueScaler(kubeClient, namespace, triggerMetadata)
+	case "kubernetes-workload":
+		return scalers.NewKubernetesWorkloadScaler(kubeClient, namespace, triggerMetadata)
 	case "liiklus":
 		return scalers.NewLiiklusScaler(resolvedEnv, triggerMetadata)
+	case "memory":
+		return scalers.NewCPUMemoryScaler(corev1.ResourceMemory, triggerMetadata)
 	case "metrics-api":
 		return scalers.NewMetricsAPIScaler(resolvedEnv, triggerMetadata, authParams)
 	case "mysql":
 		return scalers.NewMySQLScaler(resolvedEnv, triggerMetadata, authParams)
+	case "nsq":
+		return scalers.NewNSQScaler(resolvedEnv, triggerMetadata)
+	case "opcua":
+		return scalers.NewOPCUAScaler(resolvedEnv, triggerMetadata, authParams)
+	case "otel-collector":
+		return scalers.NewOTelCollectorScaler(kubeClient, namespace, triggerMetadata)
 	case "postgresql":
 		return scalers.NewPostgreSQLScaler(resolvedEnv, triggerMetadata, authParams)
 	case "prometheus":
 		return scalers.NewPrometheusScaler(resolvedEnv, triggerMetadata)
+	case "proxy-backlog":
+		return scalers.NewProxyBacklogScaler(resolvedEnv, triggerMetadata, authParams)
 	case "rabbitmq":
 		return scalers.NewRabbitMQScaler(resolvedEnv, triggerMetadata, authParams)
 	case "redis":
 		return scalers.NewRedisScaler(resolvedEnv, triggerMetadata, authParams)
 	case "redis-streams":
 		return scalers.NewRedisStreamsScaler(resolvedEnv, triggerMetadata, authParams)
+	case "snmp":
+		return scalers.NewSNMPScaler(resolvedEnv, triggerMetadata, authParams)
+	case "sql":
+		return scalers.NewSQLScaler(resolvedEnv, triggerMetadata, authParams)
 	case "stan":
 		return scalers.NewStanScaler(resolvedEnv, triggerMetadata)
+	case "trino":
+		return scalers.NewTrinoScaler(resolvedEnv, triggerMetadata, authParams)
+	case "vrealize-operations":
+		return scalers.NewVROpsScaler(resolvedEnv, triggerMetadata, authParams)
+	case "wasm":
+		return scalers.NewWasmScaler(kubeClient, namespace, triggerMetadata, authParams)
+	case "webhook":
+		return scalers.NewWebhookScaler(namespace, triggerMetadata, authParams)
 	default:
+		if factory, ok := scalersdk.Lookup(triggerType); ok {
+			return factory(&scalersdk.ScalerConfig{
+				Name:            name,
+				Namespace:       namespace,
+				TriggerMetadata: triggerMetadata,
+				ResolvedEnv:     resolvedEnv,
+				AuthParams:      authParams,
+				PodIdentity:     podIdentity,
+			})
+		}
 		return nil, fmt.Errorf("no scaler found for type: %s", triggerType)
 	}
 	// TRIGGERS-END
@@ -429,6 +1020,8 @@ func asDuckWithTriggers(scalableObject interface{}) (*kedav1alpha1.WithTriggers,
 				Triggers:        obj.Spec.Triggers,
 			},
 		}
+		// the kind isn't set on the objects read from the cache, the metadata templates refer to it
+		withTriggers.Kind = "ScaledObject"
 	case *kedav1alpha1.ScaledJob:
 		withTriggers = &kedav1alpha1.WithTriggers{
 			TypeMeta:   obj.TypeMeta,
@@ -438,6 +1031,7 @@ func asDuckWithTriggers(scalableObject interface{}) (*kedav1alpha1.WithTriggers,
 				Triggers:        obj.Spec.Triggers,
 			},
 		}
+		withTriggers.Kind = "ScaledJob"
 	default:
 		// here could be the conversion from unknown Duck type potentially in the future
 		return nil, fmt.Errorf("unknown scalable object type %v", scalableObject)
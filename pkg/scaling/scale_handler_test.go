@@ -0,0 +1,197 @@
+package scaling
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+	"github.com/kedacore/keda/pkg/eventemitter"
+	"github.com/kedacore/keda/pkg/mock/mock_client"
+	"github.com/kedacore/keda/pkg/scalers"
+)
+
+type dryRunDesiredReplicasTestData struct {
+	minReplicaCount *int32
+	maxReplicaCount *int32
+	isActive        bool
+	triggerReplicas int64
+	expected        int32
+}
+
+func int32Ptr(v int32) *int32 {
+	return &v
+}
+
+var dryRunDesiredReplicasTestDataset = []dryRunDesiredReplicasTestData{
+	// not active, scaled to zero
+	{nil, nil, false, 5, 0},
+	// not active, scaled to minReplicaCount
+	{int32Ptr(2), nil, false, 5, 2},
+	// active, replicas of the triggers
+	{nil, nil, true, 5, 5},
+	// active, at least one replica
+	{nil, nil, true, 0, 1},
+	// active, at least minReplicaCount
+	{int32Ptr(3), nil, true, 1, 3},
+	// active, at most maxReplicaCount
+	{nil, int32Ptr(4), true, 10, 4},
+	// active, at most the default maxReplicaCount of the HPA
+	{nil, nil, true, 1000, defaultHPAMaxReplicas},
+}
+
+func TestGetDryRunDesiredReplicas(t *testing.T) {
+	for i, testData := range dryRunDesiredReplicasTestDataset {
+		scaledObject := &kedav1alpha1.ScaledObject{
+			Spec: kedav1alpha1.ScaledObjectSpec{
+				MinReplicaCount: testData.minReplicaCount,
+				MaxReplicaCount: testData.maxReplicaCount,
+				Advanced:        &kedav1alpha1.AdvancedConfig{DryRun: true},
+			},
+		}
+		desiredReplicas := getDryRunDesiredReplicas(scaledObject, testData.isActive, testData.triggerReplicas)
+		if desiredReplicas != testData.expected {
+			t.Errorf("Test #%d: expected %d desired replicas, got %d", i, testData.expected, desiredReplicas)
+		}
+	}
+}
+
+func TestUpdateRecommendedReplicas(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	client := mock_client.NewMockClient(ctrl)
+	h := &scaleHandler{client: client, logger: logf.Log.WithName("test")}
+
+	scaledObject := &kedav1alpha1.ScaledObject{
+		Spec: kedav1alpha1.ScaledObjectSpec{Advanced: &kedav1alpha1.AdvancedConfig{Recommend: true}},
+	}
+	if !scaledObject.IsDryRun() || !scaledObject.IsRecommending() {
+		t.Errorf("Expected the triggers of a ScaledObject in recommendation mode to be evaluated in dry-run mode")
+	}
+
+	// the annotation is written on the first recommendation
+	client.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(1)
+	h.updateRecommendedReplicas(context.TODO(), scaledObject, 3)
+	if value := scaledObject.Annotations[kedav1alpha1.RecommendedReplicasAnnotation]; value != "3" {
+		t.Errorf("Expected 3 recommended replicas, got %q", value)
+	}
+
+	// the same recommendation isn't written again
+	h.updateRecommendedReplicas(context.TODO(), scaledObject, 3)
+
+	client.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(1)
+	h.updateRecommendedReplicas(context.TODO(), scaledObject, 0)
+	if value := scaledObject.Annotations[kedav1alpha1.RecommendedReplicasAnnotation]; value != "0" {
+		t.Errorf("Expected 0 recommended replicas, got %q", value)
+	}
+}
+
+type connectionStateTestScaler struct {
+	scalers.Scaler
+	address string
+	state   string
+	message string
+}
+
+func (s *connectionStateTestScaler) ConnectionState() (string, string, string) {
+	return s.address, s.state, s.message
+}
+
+func TestGetExternalScalersStatus(t *testing.T) {
+	before := metav1.NewTime(time.Now().Add(-time.Minute))
+	now := metav1.Now()
+	current := []kedav1alpha1.ExternalScalerStatus{
+		{ScalerAddress: "scaler-a:6000", State: "READY", LastTransitionTime: &before},
+		{ScalerAddress: "scaler-b:6000", State: "READY", LastTransitionTime: &before},
+		{ScalerAddress: "removed:6000", State: "READY", LastTransitionTime: &before},
+	}
+
+	ss := []scalers.Scaler{
+		&connectionStateTestScaler{address: "scaler-a:6000", state: "READY"},
+		&connectionStateTestScaler{address: "scaler-b:6000", state: "TRANSIENT_FAILURE", message: "unavailable"},
+		// not called in the last check
+		&connectionStateTestScaler{address: "scaler-c:6000"},
+		// wrapped by the timeout and the rate limit of its trigger
+		scalers.NewRateLimitedScaler(scalers.NewTimeoutScaler(&connectionStateTestScaler{address: "scaler-d:6000", state: "CONNECTING"}, time.Second), nil),
+	}
+
+	result, changed := getExternalScalersStatus(current, ss, now)
+	if !changed {
+		t.Error("Expected the status to change")
+	}
+	expected := []kedav1alpha1.ExternalScalerStatus{
+		{ScalerAddress: "scaler-a:6000", State: "READY", LastTransitionTime: &before},
+		{ScalerAddress: "scaler-b:6000", State: "TRANSIENT_FAILURE", Message: "unavailable", LastTransitionTime: &now},
+		{ScalerAddress: "scaler-d:6000", State: "CONNECTING", LastTransitionTime: &now},
+	}
+	if !reflect.DeepEqual(expected, result) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+
+	if _, changed := getExternalScalersStatus(result, ss, metav1.Now()); changed {
+		t.Error("Expected the status not to change if the states are the same")
+	}
+}
+
+func TestSetResourceTargetType(t *testing.T) {
+	tests := []struct {
+		trigger  kedav1alpha1.ScaleTriggers
+		metadata map[string]string
+		expected string
+	}{
+		{kedav1alpha1.ScaleTriggers{Type: "cpu", ThresholdType: autoscalingv2beta2.AverageValueMetricType}, map[string]string{"value": "500m"}, "AverageValue"},
+		{kedav1alpha1.ScaleTriggers{Type: "memory", ThresholdType: autoscalingv2beta2.UtilizationMetricType}, map[string]string{"value": "60"}, "Utilization"},
+		{kedav1alpha1.ScaleTriggers{Type: "cpu", ThresholdType: autoscalingv2beta2.AverageValueMetricType}, map[string]string{"value": "60", "type": "Utilization"}, "Utilization"},
+		{kedav1alpha1.ScaleTriggers{Type: "cpu"}, map[string]string{"value": "60"}, ""},
+		{kedav1alpha1.ScaleTriggers{Type: "kafka", ThresholdType: autoscalingv2beta2.ValueMetricType}, map[string]string{}, ""},
+	}
+	for i, test := range tests {
+		setResourceTargetType(test.trigger, test.metadata)
+		if test.metadata["type"] != test.expected {
+			t.Errorf("Test #%d: expected the type %q, got %q", i, test.expected, test.metadata["type"])
+		}
+	}
+}
+
+type recordingEventEmitter struct {
+	eventTypes []string
+}
+
+func (e *recordingEventEmitter) Emit(kind, namespace, name, eventType, message string) {
+	e.eventTypes = append(e.eventTypes, eventType)
+}
+
+func TestReportDryRunEvaluation(t *testing.T) {
+	emitter := &recordingEventEmitter{}
+	h := &scaleHandler{eventEmitter: emitter, logger: logf.Log.WithName("test")}
+	scaledObject := &kedav1alpha1.ScaledObject{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "app"}}
+
+	evaluations := []struct {
+		status  kedav1alpha1.DryRunStatus
+		emitted int
+	}{
+		// the first evaluation is reported
+		{kedav1alpha1.DryRunStatus{Active: true, DesiredReplicas: 3}, 1},
+		// the same result isn't reported again
+		{kedav1alpha1.DryRunStatus{Active: true, DesiredReplicas: 3}, 1},
+		{kedav1alpha1.DryRunStatus{Active: true, DesiredReplicas: 5}, 2},
+		{kedav1alpha1.DryRunStatus{Active: false, DesiredReplicas: 5}, 3},
+	}
+	for i, evaluation := range evaluations {
+		status := evaluation.status
+		h.reportDryRunEvaluation(scaledObject, &status)
+		scaledObject.Status.DryRun = &status
+		if len(emitter.eventTypes) != evaluation.emitted {
+			t.Errorf("Evaluation #%d: expected %d events, got %d", i, evaluation.emitted, len(emitter.eventTypes))
+		}
+	}
+	if emitter.eventTypes[0] != eventemitter.ScaledObjectDryRunEvaluatedType {
+		t.Errorf("Expected a dry-run evaluation event, got %s", emitter.eventTypes[0])
+	}
+}
@@ -0,0 +1,157 @@
+package scaling
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+	"github.com/kedacore/keda/pkg/globalconfig"
+	"github.com/kedacore/keda/pkg/scaling/resolver"
+	"github.com/kedacore/keda/pkg/tracing"
+)
+
+const fromEnvSuffix = "FromEnv"
+
+// TriggerEvaluation is the result of a single check of a trigger, it is used to debug the triggers
+type TriggerEvaluation struct {
+	Type string `json:"type"`
+	// Metadata is the trigger metadata, the values read from the environment of the scale target are not disclosed
+	Metadata map[string]string `json:"metadata,omitempty"`
+	// AuthParameters are the names of the parameters resolved from the TriggerAuthentication, their values are not disclosed
+	AuthParameters []string `json:"authParameters,omitempty"`
+	PodIdentity    string   `json:"podIdentity,omitempty"`
+	MetricName     string   `json:"metricName,omitempty"`
+	Threshold      int64    `json:"threshold"`
+	MetricValue    int64    `json:"metricValue"`
+	Active         bool     `json:"active"`
+	Error          string   `json:"error,omitempty"`
+}
+
+// EvaluateTrigger builds the scaler of the trigger, checks it once and closes it without scaling anything.
+// If the name of a ScaledObject is given, the environment of its scale target is used to resolve the trigger metadata.
+// An error is only returned if the scaler can't be built, the errors of the scaler checks are part of the evaluation.
+func (h *scaleHandler) EvaluateTrigger(ctx context.Context, namespace, scaledObjectName string, trigger kedav1alpha1.ScaleTriggers) (*TriggerEvaluation, error) {
+	ctx, span := tracing.StartSpan(ctx, "ScaleHandler.EvaluateTrigger", tracing.ObjectAttributes("ScaledObject", namespace, scaledObjectName)...)
+	evaluation, err := h.evaluateTrigger(ctx, namespace, scaledObjectName, trigger)
+	tracing.EndSpan(ctx, span, err)
+	return evaluation, err
+}
+
+func (h *scaleHandler) evaluateTrigger(ctx context.Context, namespace, scaledObjectName string, trigger kedav1alpha1.ScaleTriggers) (*TriggerEvaluation, error) {
+	var podTemplateSpec *corev1.PodTemplateSpec
+	var containerName string
+	withTriggers := &kedav1alpha1.WithTriggers{ObjectMeta: metav1.ObjectMeta{Namespace: namespace}}
+	withTriggers.Kind = "ScaledObject"
+	if scaledObjectName != "" {
+		scaledObject := &kedav1alpha1.ScaledObject{}
+		if err := h.client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: scaledObjectName}, scaledObject); err != nil {
+			return nil, fmt.Errorf("error getting ScaledObject: %s", err)
+		}
+		if scaledObject.Status.ScaleTargetGVKR == nil {
+			return nil, fmt.Errorf("the scale target of ScaledObject %s/%s isn't resolved yet", namespace, scaledObjectName)
+		}
+		withTriggers.ObjectMeta = scaledObject.ObjectMeta
+		var err error
+		podTemplateSpec, containerName, err = h.getPods(scaledObject)
+		if err != nil {
+			return nil, fmt.Errorf("error getting the scale target of ScaledObject: %s", err)
+		}
+	}
+
+	name := scaledObjectName
+	if name == "" {
+		name = trigger.Type
+	}
+	logger := h.logger.WithValues("type", "ScaledObject", "namespace", namespace, "name", name)
+	resolvedEnv, err := h.resolveEnv(logger, namespace, podTemplateSpec, containerName)
+	if err != nil {
+		return nil, err
+	}
+	authParams, podIdentity, err := h.resolveAuth(logger, namespace, trigger, podTemplateSpec)
+	if err != nil {
+		return nil, err
+	}
+	triggerMetadata, err := renderTriggerMetadata(withTriggers, trigger)
+	if err != nil {
+		return nil, fmt.Errorf("error getting scaler for trigger: %s", err)
+	}
+	triggerMetadata, resolvedEnv, err = resolver.ResolveFileParameters(triggerMetadata, resolvedEnv, globalconfig.Get().SecretFileDirectories)
+	if err != nil {
+		return nil, fmt.Errorf("error getting scaler for trigger: %s", err)
+	}
+	setResourceTargetType(trigger, triggerMetadata)
+	scaler, err := buildScaler(h.client, name, namespace, trigger.Type, resolvedEnv, triggerMetadata, authParams, podIdentity)
+	if err != nil {
+		return nil, fmt.Errorf("error getting scaler for trigger: %s", err)
+	}
+	defer scaler.Close()
+
+	evaluation := &TriggerEvaluation{
+		Type:           trigger.Type,
+		Metadata:       describeTriggerMetadata(triggerMetadata, resolvedEnv),
+		AuthParameters: sortedKeys(authParams),
+		PodIdentity:    podIdentity,
+	}
+
+	isActive, err := isScalerActive(ctx, scaler)
+	evaluation.Active = isActive
+	if err != nil {
+		evaluation.Error = err.Error()
+	}
+
+	for _, metricSpec := range scaler.GetMetricSpecForScaling() {
+		if metricSpec.External == nil {
+			continue
+		}
+		evaluation.MetricName = metricSpec.External.Metric.Name
+		if metricSpec.External.Target.AverageValue != nil {
+			evaluation.Threshold, _ = metricSpec.External.Target.AverageValue.AsInt64()
+		}
+
+		metrics, err := scaler.GetMetrics(ctx, metricSpec.External.Metric.Name, nil)
+		if err != nil {
+			evaluation.Error = err.Error()
+			break
+		}
+		for _, metric := range metrics {
+			metricValue, _ := metric.Value.AsInt64()
+			evaluation.MetricValue += metricValue
+		}
+		// the HPA is created with a single metric per trigger
+		break
+	}
+
+	return evaluation, nil
+}
+
+// describeTriggerMetadata returns the trigger metadata with the values referencing the environment of the scale target
+// replaced by a note whether the environment variable is resolved, so no secret is disclosed
+func describeTriggerMetadata(metadata, resolvedEnv map[string]string) map[string]string {
+	described := make(map[string]string, len(metadata))
+	for key, value := range metadata {
+		if strings.HasSuffix(key, fromEnvSuffix) && value != "" {
+			if _, ok := resolvedEnv[value]; ok {
+				value = fmt.Sprintf("<resolved from %s>", value)
+			} else {
+				value = fmt.Sprintf("<%s not found in the environment>", value)
+			}
+		}
+		described[key] = value
+	}
+	return described
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,39 @@
+package scaling
+
+import (
+	"reflect"
+	"testing"
+)
+
+type describeTriggerMetadataTestData struct {
+	metadata    map[string]string
+	resolvedEnv map[string]string
+	expected    map[string]string
+}
+
+var describeTriggerMetadataTestDataset = []describeTriggerMetadataTestData{
+	{
+		metadata:    map[string]string{"queueName": "orders", "connectionFromEnv": "CONNECTION"},
+		resolvedEnv: map[string]string{"CONNECTION": "secret"},
+		expected:    map[string]string{"queueName": "orders", "connectionFromEnv": "<resolved from CONNECTION>"},
+	},
+	{
+		metadata:    map[string]string{"connectionFromEnv": "MISSING"},
+		resolvedEnv: map[string]string{},
+		expected:    map[string]string{"connectionFromEnv": "<MISSING not found in the environment>"},
+	},
+	{
+		metadata:    map[string]string{"connectionFromEnv": ""},
+		resolvedEnv: nil,
+		expected:    map[string]string{"connectionFromEnv": ""},
+	},
+}
+
+func TestDescribeTriggerMetadata(t *testing.T) {
+	for _, testData := range describeTriggerMetadataTestDataset {
+		described := describeTriggerMetadata(testData.metadata, testData.resolvedEnv)
+		if !reflect.DeepEqual(described, testData.expected) {
+			t.Errorf("Expected %v, got %v", testData.expected, described)
+		}
+	}
+}
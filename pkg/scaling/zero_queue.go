@@ -0,0 +1,41 @@
+package scaling
+
+import (
+	"context"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+)
+
+// getZeroQueueChecks returns the number of consecutive checks the metrics of the triggers were zero after a check, up to the limit,
+// the count is reset if a trigger is active, failed or has metrics not zero
+func getZeroQueueChecks(current, limit int32, activities []scalerActivity) int32 {
+	for _, activity := range activities {
+		if activity.err != nil || activity.isActive || !activity.isZero {
+			return 0
+		}
+	}
+	if current >= limit {
+		return limit
+	}
+	return current + 1
+}
+
+// updateZeroQueueChecks stores in the status of a ScaledObject with waitForZeroQueueBeforeScaleToZero the number of consecutive checks
+// its triggers were zero, the scale executor only scales the ScaleTarget to zero once it reaches waitForZeroQueueBeforeScaleToZero
+func (h *scaleHandler) updateZeroQueueChecks(ctx context.Context, scaledObject *kedav1alpha1.ScaledObject, activities []scalerActivity) {
+	var zeroQueueChecks int32
+	if scaledObject.Spec.WaitForZeroQueueBeforeScaleToZero != nil {
+		zeroQueueChecks = getZeroQueueChecks(scaledObject.Status.ZeroQueueChecks, *scaledObject.Spec.WaitForZeroQueueBeforeScaleToZero, activities)
+	}
+	if zeroQueueChecks == scaledObject.Status.ZeroQueueChecks {
+		return
+	}
+
+	patch := client.MergeFrom(scaledObject.DeepCopy())
+	scaledObject.Status.ZeroQueueChecks = zeroQueueChecks
+	if err := h.client.Status().Patch(ctx, scaledObject, patch); err != nil {
+		h.logger.Error(err, "Failed to patch the zero queue checks", "scaledObject.Namespace", scaledObject.Namespace, "scaledObject.Name", scaledObject.Name)
+	}
+}
@@ -0,0 +1,84 @@
+package scaling
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"k8s.io/api/autoscaling/v2beta2"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/metrics/pkg/apis/external_metrics"
+
+	"github.com/kedacore/keda/pkg/scalers"
+)
+
+type queueTestScaler struct {
+	scalers.Scaler
+	isActive    bool
+	queueLength int64
+	err         error
+}
+
+func (s *queueTestScaler) IsActive(ctx context.Context) (bool, error) {
+	return s.isActive, nil
+}
+
+func (s *queueTestScaler) GetMetricSpecForScaling() []v2beta2.MetricSpec {
+	return []v2beta2.MetricSpec{{External: &v2beta2.ExternalMetricSource{Metric: v2beta2.MetricIdentifier{Name: "queueLength"}}}}
+}
+
+func (s *queueTestScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return []external_metrics.ExternalMetricValue{{MetricName: metricName, Value: *resource.NewQuantity(s.queueLength, resource.DecimalSI)}}, nil
+}
+
+func (s *queueTestScaler) Close() error {
+	return nil
+}
+
+func TestEvaluateScalersZeroQueue(t *testing.T) {
+	ss := []scalers.Scaler{
+		&queueTestScaler{},
+		&queueTestScaler{queueLength: 3},
+		&queueTestScaler{err: fmt.Errorf("query failed")},
+		&queueTestScaler{isActive: true},
+		scalers.NewDisabledScaler(),
+	}
+	expected := []bool{true, false, false, false, true}
+
+	for i, activity := range evaluateScalersActivity(context.TODO(), ss, 1, 0, true) {
+		if activity.isZero != expected[i] {
+			t.Errorf("Trigger #%d: expected zero %t, got %t", i, expected[i], activity.isZero)
+		}
+	}
+	for i, activity := range evaluateScalersActivity(context.TODO(), ss, 1, 0, false) {
+		if activity.isZero {
+			t.Errorf("Trigger #%d: expected the metrics not to be checked", i)
+		}
+	}
+}
+
+func TestGetZeroQueueChecks(t *testing.T) {
+	zero := scalerActivity{isZero: true}
+	tests := []struct {
+		name       string
+		current    int32
+		activities []scalerActivity
+		expected   int32
+	}{
+		{"first zero check", 0, []scalerActivity{zero, zero}, 1},
+		{"consecutive zero checks", 1, []scalerActivity{zero}, 2},
+		{"limit reached", 3, []scalerActivity{zero}, 3},
+		{"queue not empty", 2, []scalerActivity{zero, {}}, 0},
+		{"active trigger", 2, []scalerActivity{zero, {isActive: true, isZero: true}}, 0},
+		{"failed trigger", 2, []scalerActivity{zero, {err: fmt.Errorf("query failed")}}, 0},
+	}
+	for _, test := range tests {
+		if checks := getZeroQueueChecks(test.current, 3, test.activities); checks != test.expected {
+			t.Errorf("%s: expected %d, got %d", test.name, test.expected, checks)
+		}
+	}
+}
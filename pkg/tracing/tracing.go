@@ -0,0 +1,103 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel/api/global"
+	apitrace "go.opentelemetry.io/otel/api/trace"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp"
+	"go.opentelemetry.io/otel/label"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/semconv"
+
+	"github.com/kedacore/keda/version"
+)
+
+const tracerName = "github.com/kedacore/keda"
+
+// Config holds the configuration of the OTLP exporter the spans are sent to
+type Config struct {
+	// Endpoint is the address of the OTLP collector, tracing is disabled if empty
+	Endpoint string
+	// Insecure disables the client transport security of the exporter
+	Insecure bool
+	// SampleRatio is the fraction of traces sampled, between 0 and 1
+	SampleRatio float64
+	// ServiceName identifies the KEDA component in the traces
+	ServiceName string
+}
+
+// Init configures the global trace provider to export spans to the OTLP collector.
+// The returned function flushes the pending spans and stops the exporter.
+// If no endpoint is configured the spans are not recorded.
+func Init(config Config) (func(), error) {
+	if config.Endpoint == "" {
+		return func() {}, nil
+	}
+	if config.SampleRatio < 0 || config.SampleRatio > 1 {
+		return nil, fmt.Errorf("trace sample ratio must be between 0 and 1, got %v", config.SampleRatio)
+	}
+
+	opts := []otlp.ExporterOption{otlp.WithAddress(config.Endpoint)}
+	if config.Insecure {
+		opts = append(opts, otlp.WithInsecure())
+	}
+	exporter, err := otlp.NewExporter(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("error creating OTLP exporter: %s", err)
+	}
+
+	processor, err := sdktrace.NewBatchSpanProcessor(exporter)
+	if err != nil {
+		return nil, fmt.Errorf("error creating span processor: %s", err)
+	}
+
+	provider, err := sdktrace.NewProvider(
+		sdktrace.WithConfig(sdktrace.Config{DefaultSampler: sdktrace.ParentSample(sdktrace.ProbabilitySampler(config.SampleRatio))}),
+		sdktrace.WithResource(resource.New(
+			semconv.ServiceNameKey.String(config.ServiceName),
+			semconv.ServiceVersionKey.String(version.Version),
+		)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error creating trace provider: %s", err)
+	}
+	provider.RegisterSpanProcessor(processor)
+	global.SetTraceProvider(provider)
+
+	return func() {
+		processor.Shutdown()
+		_ = exporter.Stop()
+	}, nil
+}
+
+// StartSpan starts a span as a child of the span in the context, if there is any
+func StartSpan(ctx context.Context, name string, attributes ...label.KeyValue) (context.Context, apitrace.Span) {
+	return global.Tracer(tracerName).Start(ctx, name, apitrace.WithAttributes(attributes...))
+}
+
+// EndSpan records the error on the span, if there is any, and ends it
+func EndSpan(ctx context.Context, span apitrace.Span, err error) {
+	if err != nil {
+		span.RecordError(ctx, err, apitrace.WithErrorStatus(codes.Unknown))
+		span.SetStatus(codes.Unknown, err.Error())
+	}
+	span.End()
+}
+
+// ObjectAttributes returns the attributes identifying a KEDA resource on a span
+func ObjectAttributes(kind, namespace, name string) []label.KeyValue {
+	return []label.KeyValue{
+		label.String("keda.kind", kind),
+		label.String("keda.namespace", namespace),
+		label.String("keda.name", name),
+	}
+}
+
+// ScalerAttribute returns the attribute identifying the type of a scaler on a span
+func ScalerAttribute(scalerName string) label.KeyValue {
+	return label.String("keda.scaler", scalerName)
+}
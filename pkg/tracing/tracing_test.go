@@ -0,0 +1,35 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInitWithoutEndpoint(t *testing.T) {
+	shutdown, err := Init(Config{ServiceName: "keda-test"})
+	assert.NoError(t, err)
+	assert.NotNil(t, shutdown)
+	shutdown()
+}
+
+func TestInitInvalidSampleRatio(t *testing.T) {
+	for _, ratio := range []float64{-0.5, 1.5} {
+		_, err := Init(Config{Endpoint: "localhost:55680", SampleRatio: ratio})
+		assert.Error(t, err)
+	}
+}
+
+func TestStartAndEndSpan(t *testing.T) {
+	ctx, span := StartSpan(context.TODO(), "test", ObjectAttributes("ScaledObject", "default", "test")...)
+	assert.NotNil(t, ctx)
+	EndSpan(ctx, span, errors.New("test error"))
+}
+
+func TestScalerAttribute(t *testing.T) {
+	attribute := ScalerAttribute("prometheusScaler")
+	assert.Equal(t, "keda.scaler", string(attribute.Key))
+	assert.Equal(t, "prometheusScaler", attribute.Value.AsString())
+}
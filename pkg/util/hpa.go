@@ -0,0 +1,58 @@
+package util
+
+import (
+	"context"
+	"fmt"
+
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+)
+
+// DefaultHPAMinReplicas is the MinReplicas of the HPA of a ScaledObject without a minReplicaCount above zero
+const DefaultHPAMinReplicas int32 = 1
+
+// GetHPAName returns the name of the HPA KEDA creates for the ScaledObject
+func GetHPAName(scaledObject *kedav1alpha1.ScaledObject) string {
+	return fmt.Sprintf("keda-hpa-%s", scaledObject.Name)
+}
+
+// GetHPAMinReplicas returns the MinReplicas of the HPA of the ScaledObject, its minReplicaCount or DefaultHPAMinReplicas,
+// raised to the MinReplicas of the canary of the Rollout ScaleTarget while it is in progress
+// and to the replica count the StatefulSet is held at until the pre-scale-down hooks of its pods ran
+func GetHPAMinReplicas(scaledObject *kedav1alpha1.ScaledObject) int32 {
+	minReplicas := DefaultHPAMinReplicas
+	if scaledObject.Spec.MinReplicaCount != nil && *scaledObject.Spec.MinReplicaCount > 0 {
+		minReplicas = *scaledObject.Spec.MinReplicaCount
+	}
+	if canary := scaledObject.Status.RolloutCanary; canary != nil && canary.MinReplicas > minReplicas {
+		minReplicas = canary.MinReplicas
+	}
+	if hold := scaledObject.Status.PreScaleDownHookMinReplicas; hold != nil && *hold > minReplicas {
+		minReplicas = *hold
+	}
+	return minReplicas
+}
+
+// UpdateHPAMinReplicas sets the MinReplicas of the HPA of the ScaledObject to GetHPAMinReplicas. The HPA not created yet is skipped,
+// the controller creates it with the same MinReplicas
+func UpdateHPAMinReplicas(ctx context.Context, kubeClient client.Client, scaledObject *kedav1alpha1.ScaledObject) error {
+	hpa := &autoscalingv2beta2.HorizontalPodAutoscaler{}
+	if err := kubeClient.Get(ctx, types.NamespacedName{Namespace: scaledObject.Namespace, Name: GetHPAName(scaledObject)}, hpa); err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	minReplicas := GetHPAMinReplicas(scaledObject)
+	if hpa.Spec.MinReplicas != nil && *hpa.Spec.MinReplicas == minReplicas {
+		return nil
+	}
+
+	patch := client.MergeFrom(hpa.DeepCopy())
+	hpa.Spec.MinReplicas = &minReplicas
+	return kubeClient.Patch(ctx, hpa, patch)
+}
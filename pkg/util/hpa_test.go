@@ -0,0 +1,68 @@
+package util
+
+import (
+	"context"
+	"testing"
+
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+)
+
+func TestGetHPAMinReplicas(t *testing.T) {
+	scaledObject := &kedav1alpha1.ScaledObject{}
+	if minReplicas := GetHPAMinReplicas(scaledObject); minReplicas != DefaultHPAMinReplicas {
+		t.Errorf("Expected the default MinReplicas, got %d", minReplicas)
+	}
+
+	minReplicaCount := int32(2)
+	scaledObject.Spec.MinReplicaCount = &minReplicaCount
+	if minReplicas := GetHPAMinReplicas(scaledObject); minReplicas != 2 {
+		t.Errorf("Expected the MinReplicas of the spec, got %d", minReplicas)
+	}
+
+	// the HPA is kept at the MinReplicas of the canary of the Rollout while it is in progress
+	scaledObject.Status.RolloutCanary = &kedav1alpha1.RolloutCanaryStatus{StableReplicas: 4, CanaryReplicas: 1, MinReplicas: 5}
+	if minReplicas := GetHPAMinReplicas(scaledObject); minReplicas != 5 {
+		t.Errorf("Expected the MinReplicas of the canary, got %d", minReplicas)
+	}
+
+	// the StatefulSet is held at the replica count whose pods didn't run their pre-scale-down hook
+	holdReplicas := int32(7)
+	scaledObject.Status.PreScaleDownHookMinReplicas = &holdReplicas
+	if minReplicas := GetHPAMinReplicas(scaledObject); minReplicas != 7 {
+		t.Errorf("Expected the MinReplicas of the pre-scale-down hook, got %d", minReplicas)
+	}
+}
+
+func TestUpdateHPAMinReplicas(t *testing.T) {
+	scaledObject := &kedav1alpha1.ScaledObject{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "app"}}
+	canary := &kedav1alpha1.RolloutCanaryStatus{MinReplicas: 5}
+
+	// the HPA not created yet is skipped
+	kubeClient := fake.NewFakeClientWithScheme(clientgoscheme.Scheme)
+	if err := UpdateHPAMinReplicas(context.TODO(), kubeClient, scaledObject); err != nil {
+		t.Errorf("Expected the missing HPA to be skipped, got %s", err)
+	}
+
+	minReplicas := int32(1)
+	kubeClient = fake.NewFakeClientWithScheme(clientgoscheme.Scheme, &autoscalingv2beta2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: GetHPAName(scaledObject)},
+		Spec:       autoscalingv2beta2.HorizontalPodAutoscalerSpec{MinReplicas: &minReplicas, MaxReplicas: 10},
+	})
+	scaledObject.Status.RolloutCanary = canary
+	if err := UpdateHPAMinReplicas(context.TODO(), kubeClient, scaledObject); err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	hpa := &autoscalingv2beta2.HorizontalPodAutoscaler{}
+	if err := kubeClient.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: "keda-hpa-app"}, hpa); err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	if hpa.Spec.MinReplicas == nil || *hpa.Spec.MinReplicas != 5 {
+		t.Errorf("Expected the MinReplicas of the canary on the HPA, got %v", hpa.Spec.MinReplicas)
+	}
+}
@@ -0,0 +1,187 @@
+package util
+
+import (
+	"context"
+
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/discovery"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// The versions of the autoscaling API the HPAs are managed with
+var (
+	// HPAVersionV2 is served from Kubernetes 1.23, it is the only version left from Kubernetes 1.26
+	HPAVersionV2 = schema.GroupVersion{Group: "autoscaling", Version: "v2"}
+	// HPAVersionV2beta2 is served from Kubernetes 1.12 until 1.25
+	HPAVersionV2beta2 = schema.GroupVersion{Group: "autoscaling", Version: "v2beta2"}
+)
+
+// DetectHPAVersion returns the most recent version of the HPA API served by the cluster among the versions KEDA supports,
+// autoscaling/v2beta2 is returned with the error if the served versions can't be discovered
+func DetectHPAVersion(client discovery.ServerGroupsInterface) (schema.GroupVersion, error) {
+	groups, err := client.ServerGroups()
+	if err != nil {
+		return HPAVersionV2beta2, err
+	}
+	for _, group := range groups.Groups {
+		if group.Name != HPAVersionV2.Group {
+			continue
+		}
+		for _, version := range group.Versions {
+			if version.Version == HPAVersionV2.Version {
+				return HPAVersionV2, nil
+			}
+		}
+	}
+	return HPAVersionV2beta2, nil
+}
+
+// NewHPAWatchObject returns the object the HPAs of the version are watched with
+func NewHPAWatchObject(version schema.GroupVersion) runtime.Object {
+	if version == HPAVersionV2beta2 {
+		return &autoscalingv2beta2.HorizontalPodAutoscaler{}
+	}
+	hpa := &unstructured.Unstructured{}
+	hpa.SetGroupVersionKind(version.WithKind("HorizontalPodAutoscaler"))
+	return hpa
+}
+
+// NewHPAVersionClient returns a client reading and writing the autoscaling/v2beta2 HPAs with the version of the HPA API,
+// the HPAs of autoscaling/v2 have the same schema and are converted to v2beta2. The other objects are passed to the client.
+func NewHPAVersionClient(c client.Client, version schema.GroupVersion) client.Client {
+	if version == HPAVersionV2beta2 {
+		return c
+	}
+	return &hpaVersionClient{Client: c, version: version}
+}
+
+type hpaVersionClient struct {
+	client.Client
+	version schema.GroupVersion
+}
+
+func (c *hpaVersionClient) Get(ctx context.Context, key client.ObjectKey, obj runtime.Object) error {
+	hpa, ok := obj.(*autoscalingv2beta2.HorizontalPodAutoscaler)
+	if !ok {
+		return c.Client.Get(ctx, key, obj)
+	}
+	u := &unstructured.Unstructured{}
+	u.SetGroupVersionKind(c.version.WithKind("HorizontalPodAutoscaler"))
+	if err := c.Client.Get(ctx, key, u); err != nil {
+		return err
+	}
+	return c.fromUnstructured(u.Object, hpa)
+}
+
+func (c *hpaVersionClient) List(ctx context.Context, list runtime.Object, opts ...client.ListOption) error {
+	hpas, ok := list.(*autoscalingv2beta2.HorizontalPodAutoscalerList)
+	if !ok {
+		return c.Client.List(ctx, list, opts...)
+	}
+	u := &unstructured.UnstructuredList{}
+	u.SetGroupVersionKind(c.version.WithKind("HorizontalPodAutoscalerList"))
+	if err := c.Client.List(ctx, u, opts...); err != nil {
+		return err
+	}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.UnstructuredContent(), hpas); err != nil {
+		return err
+	}
+	hpas.TypeMeta = metav1.TypeMeta{}
+	for i := range hpas.Items {
+		hpas.Items[i].TypeMeta = metav1.TypeMeta{}
+	}
+	return nil
+}
+
+func (c *hpaVersionClient) Create(ctx context.Context, obj runtime.Object, opts ...client.CreateOption) error {
+	hpa, ok := obj.(*autoscalingv2beta2.HorizontalPodAutoscaler)
+	if !ok {
+		return c.Client.Create(ctx, obj, opts...)
+	}
+	u, err := c.toUnstructured(hpa)
+	if err != nil {
+		return err
+	}
+	if err := c.Client.Create(ctx, u, opts...); err != nil {
+		return err
+	}
+	return c.fromUnstructured(u.Object, hpa)
+}
+
+func (c *hpaVersionClient) Update(ctx context.Context, obj runtime.Object, opts ...client.UpdateOption) error {
+	hpa, ok := obj.(*autoscalingv2beta2.HorizontalPodAutoscaler)
+	if !ok {
+		return c.Client.Update(ctx, obj, opts...)
+	}
+	u, err := c.toUnstructured(hpa)
+	if err != nil {
+		return err
+	}
+	if err := c.Client.Update(ctx, u, opts...); err != nil {
+		return err
+	}
+	return c.fromUnstructured(u.Object, hpa)
+}
+
+func (c *hpaVersionClient) Patch(ctx context.Context, obj runtime.Object, patch client.Patch, opts ...client.PatchOption) error {
+	hpa, ok := obj.(*autoscalingv2beta2.HorizontalPodAutoscaler)
+	if !ok {
+		return c.Client.Patch(ctx, obj, patch, opts...)
+	}
+	u, err := c.toUnstructured(hpa)
+	if err != nil {
+		return err
+	}
+	// the patch is computed on the v2beta2 HPA, the apiVersion of the unstructured HPA isn't part of it.
+	// The applied HPA is the whole object, it is sent with the apiVersion of the unstructured HPA
+	var data []byte
+	if patch.Type() == types.ApplyPatchType {
+		data, err = u.MarshalJSON()
+	} else {
+		data, err = patch.Data(hpa)
+	}
+	if err != nil {
+		return err
+	}
+	if err := c.Client.Patch(ctx, u, client.RawPatch(patch.Type(), data), opts...); err != nil {
+		return err
+	}
+	return c.fromUnstructured(u.Object, hpa)
+}
+
+func (c *hpaVersionClient) Delete(ctx context.Context, obj runtime.Object, opts ...client.DeleteOption) error {
+	hpa, ok := obj.(*autoscalingv2beta2.HorizontalPodAutoscaler)
+	if !ok {
+		return c.Client.Delete(ctx, obj, opts...)
+	}
+	u, err := c.toUnstructured(hpa)
+	if err != nil {
+		return err
+	}
+	return c.Client.Delete(ctx, u, opts...)
+}
+
+func (c *hpaVersionClient) toUnstructured(hpa *autoscalingv2beta2.HorizontalPodAutoscaler) (*unstructured.Unstructured, error) {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(hpa)
+	if err != nil {
+		return nil, err
+	}
+	u := &unstructured.Unstructured{Object: content}
+	u.SetGroupVersionKind(c.version.WithKind("HorizontalPodAutoscaler"))
+	return u, nil
+}
+
+// fromUnstructured converts the HPA to v2beta2, the TypeMeta is cleared as on the objects read by the typed client
+func (c *hpaVersionClient) fromUnstructured(content map[string]interface{}, hpa *autoscalingv2beta2.HorizontalPodAutoscaler) error {
+	*hpa = autoscalingv2beta2.HorizontalPodAutoscaler{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(content, hpa); err != nil {
+		return err
+	}
+	hpa.TypeMeta = metav1.TypeMeta{}
+	return nil
+}
@@ -0,0 +1,149 @@
+package util
+
+import (
+	"context"
+	"testing"
+
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	clienttesting "k8s.io/client-go/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestDetectHPAVersion(t *testing.T) {
+	tests := []struct {
+		name          string
+		groupVersions []string
+		expected      string
+	}{
+		{"kubernetes 1.22", []string{"autoscaling/v1", "autoscaling/v2beta1", "autoscaling/v2beta2"}, "autoscaling/v2beta2"},
+		{"kubernetes 1.23", []string{"autoscaling/v1", "autoscaling/v2", "autoscaling/v2beta1", "autoscaling/v2beta2"}, "autoscaling/v2"},
+		{"kubernetes 1.26", []string{"autoscaling/v1", "autoscaling/v2"}, "autoscaling/v2"},
+	}
+
+	for _, test := range tests {
+		discovery := &fakediscovery.FakeDiscovery{Fake: &clienttesting.Fake{}}
+		for _, groupVersion := range test.groupVersions {
+			discovery.Resources = append(discovery.Resources, &metav1.APIResourceList{GroupVersion: groupVersion})
+		}
+		version, err := DetectHPAVersion(discovery)
+		if err != nil {
+			t.Fatalf("%s: unexpected error %s", test.name, err)
+		}
+		if version.String() != test.expected {
+			t.Errorf("%s: expected %s, got %s", test.name, test.expected, version)
+		}
+	}
+}
+
+func TestHPAVersionClient(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = autoscalingv2beta2.AddToScheme(scheme)
+	// the fake client only lists the kinds of its scheme
+	scheme.AddKnownTypeWithName(HPAVersionV2.WithKind("HorizontalPodAutoscalerList"), &unstructured.UnstructuredList{})
+	fakeClient := fake.NewFakeClientWithScheme(scheme)
+	c := NewHPAVersionClient(fakeClient, HPAVersionV2)
+
+	minReplicas := int32(1)
+	hpa := &autoscalingv2beta2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "keda-hpa-app"},
+		Spec: autoscalingv2beta2.HorizontalPodAutoscalerSpec{
+			MinReplicas:    &minReplicas,
+			MaxReplicas:    10,
+			ScaleTargetRef: autoscalingv2beta2.CrossVersionObjectReference{APIVersion: "apps/v1", Kind: "Deployment", Name: "app"},
+		},
+	}
+	if err := c.Create(context.Background(), hpa); err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+
+	// the HPA is stored with autoscaling/v2
+	u := &unstructured.Unstructured{}
+	u.SetGroupVersionKind(HPAVersionV2.WithKind("HorizontalPodAutoscaler"))
+	if err := fakeClient.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: "keda-hpa-app"}, u); err != nil {
+		t.Fatalf("Expected the HPA to be created with autoscaling/v2, got %s", err)
+	}
+
+	found := &autoscalingv2beta2.HorizontalPodAutoscaler{}
+	if err := c.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: "keda-hpa-app"}, found); err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	if found.Spec.MaxReplicas != 10 || found.Spec.ScaleTargetRef.Name != "app" {
+		t.Errorf("Expected the spec of the HPA, got %+v", found.Spec)
+	}
+
+	patch := client.MergeFrom(found.DeepCopy())
+	found.Spec.MaxReplicas = 20
+	if err := c.Patch(context.Background(), found, patch); err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+
+	hpas := &autoscalingv2beta2.HorizontalPodAutoscalerList{}
+	if err := c.List(context.Background(), hpas, client.InNamespace("default")); err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	if len(hpas.Items) != 1 || hpas.Items[0].Spec.MaxReplicas != 20 {
+		t.Errorf("Expected the patched HPA, got %+v", hpas.Items)
+	}
+
+	if err := c.Delete(context.Background(), found); err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	if err := c.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: "keda-hpa-app"}, found); err == nil {
+		t.Error("Expected the HPA to be deleted")
+	}
+
+	if NewHPAVersionClient(fakeClient, HPAVersionV2beta2) != fakeClient {
+		t.Error("Expected the client not to be wrapped for autoscaling/v2beta2")
+	}
+}
+
+// patchRecordingClient records the patches instead of sending them, the fake client doesn't support server-side apply
+type patchRecordingClient struct {
+	client.Client
+	obj   runtime.Object
+	patch client.Patch
+}
+
+func (c *patchRecordingClient) Patch(_ context.Context, obj runtime.Object, patch client.Patch, _ ...client.PatchOption) error {
+	c.obj = obj
+	c.patch = patch
+	return nil
+}
+
+func TestHPAVersionClientApply(t *testing.T) {
+	recorder := &patchRecordingClient{}
+	c := NewHPAVersionClient(recorder, HPAVersionV2)
+
+	hpa := &autoscalingv2beta2.HorizontalPodAutoscaler{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "autoscaling/v2beta2", Kind: "HorizontalPodAutoscaler"},
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "keda-hpa-app"},
+		Spec:       autoscalingv2beta2.HorizontalPodAutoscalerSpec{MaxReplicas: 10},
+	}
+	if err := c.Patch(context.Background(), hpa, client.Apply, client.FieldOwner("keda-operator")); err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+
+	if recorder.patch.Type() != types.ApplyPatchType {
+		t.Fatalf("Expected an apply patch, got %s", recorder.patch.Type())
+	}
+	data, err := recorder.patch.Data(recorder.obj)
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	applied := &unstructured.Unstructured{}
+	if err := applied.UnmarshalJSON(data); err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	if applied.GetAPIVersion() != "autoscaling/v2" || applied.GetKind() != "HorizontalPodAutoscaler" {
+		t.Errorf("Expected the HPA to be applied with autoscaling/v2, got %s %s", applied.GetAPIVersion(), applied.GetKind())
+	}
+	if maxReplicas, _, _ := unstructured.NestedInt64(applied.Object, "spec", "maxReplicas"); maxReplicas != 10 {
+		t.Errorf("Expected the spec of the HPA to be applied, got maxReplicas %d", maxReplicas)
+	}
+}
@@ -0,0 +1,142 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// RetryPolicy defines how the requests of a scaler to its upstream API are retried on transient failures
+type RetryPolicy struct {
+	// MaxAttempts is the number of times a request is sent, including the first one
+	MaxAttempts int
+	// Backoff is the wait before the first retry, it is doubled on each retry up to MaxBackoff
+	Backoff time.Duration
+	// MaxBackoff is the longest wait before a retry, a request is not retried if Retry-After asks for a longer wait
+	MaxBackoff time.Duration
+	// RetryOnStatus are the response status codes the request is retried on, the requests are always retried on connection errors
+	RetryOnStatus []int
+}
+
+// DefaultRetryPolicy returns the policy used if a scaler has no retry configuration
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:   3,
+		Backoff:       500 * time.Millisecond,
+		MaxBackoff:    10 * time.Second,
+		RetryOnStatus: []int{http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout},
+	}
+}
+
+// defaultHTTPClient holds the *http.Client used by DoWithRetry if no client is given
+var defaultHTTPClient atomic.Value
+
+// SetDefaultHTTPTimeout sets the timeout of the client used by DoWithRetry if no client is given, 0 disables the timeout
+func SetDefaultHTTPTimeout(timeout time.Duration) {
+	defaultHTTPClient.Store(&http.Client{Timeout: timeout})
+}
+
+func getDefaultHTTPClient() *http.Client {
+	if client, ok := defaultHTTPClient.Load().(*http.Client); ok {
+		return client
+	}
+	return http.DefaultClient
+}
+
+// DoWithRetry sends the request with the client and retries it according to the policy,
+// the response of the last attempt is returned if all the attempts failed with a retryable status.
+// The body of the request must be replayable, which is the case for the requests created by http.NewRequest from a buffer or a reader of strings or bytes.
+func DoWithRetry(client *http.Client, req *http.Request, policy RetryPolicy) (*http.Response, error) {
+	if client == nil {
+		client = getDefaultHTTPClient()
+	}
+	if policy.MaxAttempts < 1 {
+		policy.MaxAttempts = 1
+	}
+
+	backoff := policy.Backoff
+	for attempt := 1; ; attempt++ {
+		if attempt > 1 && req.Body != nil {
+			if req.GetBody == nil {
+				return nil, fmt.Errorf("the request can't be retried as its body can't be replayed")
+			}
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+
+		resp, err := client.Do(req)
+		if attempt >= policy.MaxAttempts || (err == nil && !policy.retryOnStatus(resp.StatusCode)) {
+			return resp, err
+		}
+
+		wait := backoff
+		if err == nil {
+			if retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+				if retryAfter > policy.MaxBackoff {
+					// the upstream API asks for a longer wait than the policy allows
+					return resp, nil
+				}
+				wait = retryAfter
+			}
+			// drain the body so the connection is reused
+			_, _ = io.Copy(ioutil.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		if err := sleep(req.Context(), wait); err != nil {
+			return nil, err
+		}
+		backoff *= 2
+		if backoff > policy.MaxBackoff {
+			backoff = policy.MaxBackoff
+		}
+	}
+}
+
+func (p RetryPolicy) retryOnStatus(status int) bool {
+	for _, retryStatus := range p.RetryOnStatus {
+		if status == retryStatus {
+			return true
+		}
+	}
+	return false
+}
+
+// parseRetryAfter parses the Retry-After header, which is a number of seconds or an HTTP date
+func parseRetryAfter(header string, now time.Time) (time.Duration, bool) {
+	if header == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(header); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(header); err == nil {
+		if wait := date.Sub(now); wait > 0 {
+			return wait, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
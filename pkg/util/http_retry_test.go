@@ -0,0 +1,109 @@
+package util
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testRetryPolicy() RetryPolicy {
+	policy := DefaultRetryPolicy()
+	policy.Backoff = time.Millisecond
+	policy.MaxBackoff = 10 * time.Millisecond
+	return policy
+}
+
+func newFlakyServer(t *testing.T, statuses []int, retryAfter string) (*httptest.Server, *int) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		if r.Method == http.MethodPost && string(body) != "payload" {
+			t.Errorf("Expected the body to be replayed on call #%d, got %q", calls, string(body))
+		}
+		status := statuses[len(statuses)-1]
+		if calls < len(statuses) {
+			status = statuses[calls]
+		}
+		calls++
+		if retryAfter != "" {
+			w.Header().Set("Retry-After", retryAfter)
+		}
+		w.WriteHeader(status)
+	}))
+	return server, &calls
+}
+
+type doWithRetryTestData struct {
+	name           string
+	statuses       []int
+	retryAfter     string
+	maxAttempts    int
+	expectedStatus int
+	expectedCalls  int
+}
+
+var doWithRetryTestDataset = []doWithRetryTestData{
+	{name: "success", statuses: []int{200}, maxAttempts: 3, expectedStatus: 200, expectedCalls: 1},
+	{name: "retried until success", statuses: []int{503, 429, 200}, maxAttempts: 3, expectedStatus: 200, expectedCalls: 3},
+	{name: "attempts exhausted", statuses: []int{503}, maxAttempts: 3, expectedStatus: 503, expectedCalls: 3},
+	{name: "status not retried", statuses: []int{500, 200}, maxAttempts: 3, expectedStatus: 500, expectedCalls: 1},
+	{name: "retries disabled", statuses: []int{503, 200}, maxAttempts: 1, expectedStatus: 503, expectedCalls: 1},
+	{name: "short Retry-After honored", statuses: []int{429, 200}, retryAfter: "0", maxAttempts: 3, expectedStatus: 200, expectedCalls: 2},
+	{name: "long Retry-After not waited", statuses: []int{429, 200}, retryAfter: "120", maxAttempts: 3, expectedStatus: 429, expectedCalls: 1},
+}
+
+func TestDoWithRetry(t *testing.T) {
+	for _, testData := range doWithRetryTestDataset {
+		server, calls := newFlakyServer(t, testData.statuses, testData.retryAfter)
+
+		policy := testRetryPolicy()
+		policy.MaxAttempts = testData.maxAttempts
+		req, _ := http.NewRequest(http.MethodPost, server.URL, strings.NewReader("payload"))
+		resp, err := DoWithRetry(nil, req, policy)
+		if err != nil {
+			t.Errorf("%s: unexpected error %s", testData.name, err)
+		} else {
+			resp.Body.Close()
+			if resp.StatusCode != testData.expectedStatus {
+				t.Errorf("%s: expected status %d, got %d", testData.name, testData.expectedStatus, resp.StatusCode)
+			}
+		}
+		if *calls != testData.expectedCalls {
+			t.Errorf("%s: expected %d calls, got %d", testData.name, testData.expectedCalls, *calls)
+		}
+		server.Close()
+	}
+}
+
+func TestDoWithRetryConnectionError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	url := server.URL
+	server.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, url, nil)
+	if _, err := DoWithRetry(nil, req, testRetryPolicy()); err == nil {
+		t.Error("Expected an error once the attempts are exhausted")
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2020, 10, 1, 12, 0, 0, 0, time.UTC)
+	if wait, ok := parseRetryAfter("5", now); !ok || wait != 5*time.Second {
+		t.Errorf("Expected 5s for seconds, got %s %v", wait, ok)
+	}
+	if wait, ok := parseRetryAfter(now.Add(time.Minute).Format(http.TimeFormat), now); !ok || wait != time.Minute {
+		t.Errorf("Expected 1m for a date, got %s %v", wait, ok)
+	}
+	if wait, ok := parseRetryAfter(now.Add(-time.Minute).Format(http.TimeFormat), now); !ok || wait != 0 {
+		t.Errorf("Expected 0 for a past date, got %s %v", wait, ok)
+	}
+	if _, ok := parseRetryAfter("soon", now); ok {
+		t.Error("Expected an invalid header to be ignored")
+	}
+	if _, ok := parseRetryAfter("", now); ok {
+		t.Error("Expected a missing header to be ignored")
+	}
+}
@@ -0,0 +1,78 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// GetViolatedPodDisruptionBudgets returns the names of the PodDisruptionBudgets covering the pods of the selector, the selector of a Scale,
+// whose minAvailable count isn't met once the pods of the selector are scaled to replicas. The healthy pods the PodDisruptionBudget
+// covers beside the pods of the selector count towards minAvailable, a percentage of minAvailable is always met by scaling
+func GetViolatedPodDisruptionBudgets(ctx context.Context, kubeClient client.Client, namespace, podSelector string, replicas int32) ([]string, error) {
+	selector, err := labels.Parse(podSelector)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing the selector of the pods %q: %s", podSelector, err)
+	}
+	// the pods of a ScaleTarget without selector can't be matched
+	if selector.Empty() {
+		return nil, nil
+	}
+
+	pdbs := &policyv1beta1.PodDisruptionBudgetList{}
+	if err := kubeClient.List(ctx, pdbs, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+	pods := &corev1.PodList{}
+	if err := kubeClient.List(ctx, pods, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+
+	var violated []string
+	for _, pdb := range pdbs.Items {
+		minAvailable := pdb.Spec.MinAvailable
+		if minAvailable == nil || minAvailable.Type != intstr.Int || pdb.Spec.Selector == nil {
+			continue
+		}
+		pdbSelector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+		if err != nil || pdbSelector.Empty() {
+			continue
+		}
+
+		covered := false
+		healthyOthers := int32(0)
+		for i := range pods.Items {
+			pod := &pods.Items[i]
+			if pod.DeletionTimestamp != nil || !pdbSelector.Matches(labels.Set(pod.Labels)) {
+				continue
+			}
+			if selector.Matches(labels.Set(pod.Labels)) {
+				covered = true
+			} else if isPodHealthy(pod) {
+				healthyOthers++
+			}
+		}
+		if covered && healthyOthers+replicas < int32(minAvailable.IntValue()) {
+			violated = append(violated, pdb.Name)
+		}
+	}
+	sort.Strings(violated)
+	return violated, nil
+}
+
+// isPodHealthy returns true if the pod is ready, as the disruption controller counts the healthy pods of a PodDisruptionBudget
+func isPodHealthy(pod *corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
@@ -0,0 +1,79 @@
+package util
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newTestPDB(name string, minAvailable intstr.IntOrString, matchLabels map[string]string) *policyv1beta1.PodDisruptionBudget {
+	return &policyv1beta1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name},
+		Spec: policyv1beta1.PodDisruptionBudgetSpec{
+			MinAvailable: &minAvailable,
+			Selector:     &metav1.LabelSelector{MatchLabels: matchLabels},
+		},
+	}
+}
+
+func newTestPod(name string, podLabels map[string]string, ready bool) *corev1.Pod {
+	status := corev1.ConditionFalse
+	if ready {
+		status = corev1.ConditionTrue
+	}
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name, Labels: podLabels},
+		Status:     corev1.PodStatus{Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: status}}},
+	}
+}
+
+func TestGetViolatedPodDisruptionBudgets(t *testing.T) {
+	objects := []runtime.Object{
+		newTestPDB("worker", intstr.FromInt(2), map[string]string{"app": "worker"}),
+		newTestPDB("queue", intstr.FromInt(3), map[string]string{"tier": "queue"}),
+		newTestPDB("worker-percent", intstr.FromString("50%"), map[string]string{"app": "worker"}),
+		newTestPDB("other", intstr.FromInt(5), map[string]string{"app": "other"}),
+		newTestPod("worker-1", map[string]string{"app": "worker", "tier": "queue"}, true),
+		newTestPod("worker-2", map[string]string{"app": "worker", "tier": "queue"}, true),
+		newTestPod("consumer-1", map[string]string{"app": "consumer", "tier": "queue"}, true),
+		newTestPod("consumer-2", map[string]string{"app": "consumer", "tier": "queue"}, false),
+	}
+	kubeClient := fake.NewFakeClientWithScheme(scheme.Scheme, objects...)
+
+	tests := []struct {
+		name        string
+		podSelector string
+		replicas    int32
+		violated    []string
+	}{
+		{"scale to zero", "app=worker", 0, []string{"queue", "worker"}},
+		{"one replica left", "app=worker", 1, []string{"queue", "worker"}},
+		// the ready consumer pod counts towards the minAvailable of the queue PodDisruptionBudget
+		{"two replicas left", "app=worker", 2, nil},
+		{"pods not covered", "app=api", 0, nil},
+		{"no selector", "", 0, nil},
+	}
+
+	for _, test := range tests {
+		violated, err := GetViolatedPodDisruptionBudgets(context.TODO(), kubeClient, "default", test.podSelector, test.replicas)
+		if err != nil {
+			t.Errorf("%s: unexpected error %s", test.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(violated, test.violated) {
+			t.Errorf("%s: expected %v, got %v", test.name, test.violated, violated)
+		}
+	}
+
+	if _, err := GetViolatedPodDisruptionBudgets(context.TODO(), kubeClient, "default", "app in worker", 0); err == nil {
+		t.Error("Expected an error for an invalid selector")
+	}
+}
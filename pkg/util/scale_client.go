@@ -0,0 +1,18 @@
+package util
+
+import (
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/scale"
+)
+
+// NewScaleClient creates the client of the scale subresource of the scale targets, their scale kind is resolved with the discovery
+func NewScaleClient(clientset *discovery.DiscoveryClient, restMapper meta.RESTMapper) scale.ScalesGetter {
+	scaleKindResolver := scale.NewDiscoveryScaleKindResolver(clientset)
+	return scale.New(
+		clientset.RESTClient(), restMapper,
+		dynamic.LegacyAPIPathResolverFunc,
+		scaleKindResolver,
+	)
+}
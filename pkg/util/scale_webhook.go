@@ -0,0 +1,67 @@
+package util
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+)
+
+// Default timeout of a webhook of a ScaledObject
+const defaultScaleWebhookTimeout = 10 * time.Second
+
+// ScaleWebhook is a parsed ScaleWebhook with its defaults
+type ScaleWebhook struct {
+	URL           string
+	Timeout       time.Duration
+	FailurePolicy kedav1alpha1.ScaleWebhookFailurePolicy
+}
+
+// ParseScaleWebhook returns the webhook with its defaults, an error if its URL isn't an http or https URL or its failure policy is unknown
+func ParseScaleWebhook(webhook *kedav1alpha1.ScaleWebhook) (*ScaleWebhook, error) {
+	webhookURL, err := url.Parse(webhook.URL)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing url: %s", err)
+	}
+	if webhookURL.Scheme != "http" && webhookURL.Scheme != "https" {
+		return nil, fmt.Errorf("the scheme of the url must be http or https, got %q", webhookURL.Scheme)
+	}
+	if webhookURL.Host == "" {
+		return nil, fmt.Errorf("no host in the url %s", webhook.URL)
+	}
+
+	result := &ScaleWebhook{URL: webhookURL.String(), Timeout: defaultScaleWebhookTimeout, FailurePolicy: kedav1alpha1.ScaleWebhookFailurePolicyIgnore}
+	if webhook.TimeoutSeconds != nil {
+		if *webhook.TimeoutSeconds <= 0 {
+			return nil, fmt.Errorf("timeoutSeconds must be greater than 0")
+		}
+		result.Timeout = time.Second * time.Duration(*webhook.TimeoutSeconds)
+	}
+	switch webhook.FailurePolicy {
+	case "", kedav1alpha1.ScaleWebhookFailurePolicyIgnore:
+	case kedav1alpha1.ScaleWebhookFailurePolicyFail:
+		result.FailurePolicy = kedav1alpha1.ScaleWebhookFailurePolicyFail
+	default:
+		return nil, fmt.Errorf("unknown failurePolicy %s, it must be Ignore or Fail", webhook.FailurePolicy)
+	}
+	return result, nil
+}
+
+// ValidateScaleWebhooks returns an error if one of the webhooks is invalid
+func ValidateScaleWebhooks(webhooks *kedav1alpha1.ScaleWebhooks) error {
+	if webhooks == nil {
+		return nil
+	}
+	if webhooks.PreActivation != nil {
+		if _, err := ParseScaleWebhook(webhooks.PreActivation); err != nil {
+			return fmt.Errorf("invalid preActivation webhook: %s", err)
+		}
+	}
+	if webhooks.PostDeactivation != nil {
+		if _, err := ParseScaleWebhook(webhooks.PostDeactivation); err != nil {
+			return fmt.Errorf("invalid postDeactivation webhook: %s", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package util
+
+import (
+	"testing"
+	"time"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+)
+
+func TestParseScaleWebhook(t *testing.T) {
+	zero := int32(0)
+	thirty := int32(30)
+	tests := []struct {
+		name          string
+		webhook       kedav1alpha1.ScaleWebhook
+		timeout       time.Duration
+		failurePolicy kedav1alpha1.ScaleWebhookFailurePolicy
+		isError       bool
+	}{
+		{"defaults", kedav1alpha1.ScaleWebhook{URL: "http://cache.default.svc/warm"}, 10 * time.Second, kedav1alpha1.ScaleWebhookFailurePolicyIgnore, false},
+		{"timeout and policy", kedav1alpha1.ScaleWebhook{URL: "https://cache:8443/warm", TimeoutSeconds: &thirty, FailurePolicy: "Fail"}, 30 * time.Second, kedav1alpha1.ScaleWebhookFailurePolicyFail, false},
+		{"invalid scheme", kedav1alpha1.ScaleWebhook{URL: "tcp://cache:8080"}, 0, "", true},
+		{"no host", kedav1alpha1.ScaleWebhook{URL: "http:///warm"}, 0, "", true},
+		{"zero timeout", kedav1alpha1.ScaleWebhook{URL: "http://cache/warm", TimeoutSeconds: &zero}, 0, "", true},
+		{"unknown policy", kedav1alpha1.ScaleWebhook{URL: "http://cache/warm", FailurePolicy: "Retry"}, 0, "", true},
+	}
+
+	for _, test := range tests {
+		webhook, err := ParseScaleWebhook(&test.webhook)
+		if test.isError {
+			if err == nil {
+				t.Errorf("%s: expected an error", test.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error %s", test.name, err)
+			continue
+		}
+		if webhook.Timeout != test.timeout || webhook.FailurePolicy != test.failurePolicy {
+			t.Errorf("%s: expected %s and %s, got %s and %s", test.name, test.timeout, test.failurePolicy, webhook.Timeout, webhook.FailurePolicy)
+		}
+	}
+}
+
+func TestValidateScaleWebhooks(t *testing.T) {
+	if err := ValidateScaleWebhooks(nil); err != nil {
+		t.Errorf("Unexpected error without webhooks: %s", err)
+	}
+	webhooks := &kedav1alpha1.ScaleWebhooks{PreActivation: &kedav1alpha1.ScaleWebhook{URL: "http://cache/warm"}}
+	if err := ValidateScaleWebhooks(webhooks); err != nil {
+		t.Errorf("Unexpected error: %s", err)
+	}
+	webhooks.PostDeactivation = &kedav1alpha1.ScaleWebhook{URL: "cache/flush"}
+	if err := ValidateScaleWebhooks(webhooks); err == nil {
+		t.Error("Expected an error for the invalid postDeactivation webhook")
+	}
+}
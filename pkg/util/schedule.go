@@ -0,0 +1,62 @@
+package util
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron/v3"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+)
+
+// Schedule is a parsed ScaleToZeroSchedule
+type Schedule struct {
+	location *time.Location
+	windows  []scheduleWindow
+}
+
+type scheduleWindow struct {
+	start cron.Schedule
+	end   cron.Schedule
+}
+
+// ParseScaleToZeroSchedule returns the schedule of the windows, an error if the timezone or a cron schedule is invalid
+func ParseScaleToZeroSchedule(schedule *kedav1alpha1.ScaleToZeroSchedule) (*Schedule, error) {
+	location := time.UTC
+	if schedule.Timezone != "" {
+		loaded, err := time.LoadLocation(schedule.Timezone)
+		if err != nil {
+			return nil, fmt.Errorf("error loading timezone %s: %s", schedule.Timezone, err)
+		}
+		location = loaded
+	}
+	if len(schedule.Windows) == 0 {
+		return nil, fmt.Errorf("no windows specified")
+	}
+
+	result := &Schedule{location: location}
+	for i, window := range schedule.Windows {
+		start, err := cron.ParseStandard(window.Start)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing start of window #%d: %s", i, err)
+		}
+		end, err := cron.ParseStandard(window.End)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing end of window #%d: %s", i, err)
+		}
+		result.windows = append(result.windows, scheduleWindow{start: start, end: end})
+	}
+	return result, nil
+}
+
+// Contains returns true if the time is within one of the windows, a window is entered at its start and left at its next end
+func (s *Schedule) Contains(t time.Time) bool {
+	t = t.In(s.location)
+	for _, window := range s.windows {
+		// the window is open if it ends before it starts again
+		if !window.end.Next(t).After(window.start.Next(t)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,73 @@
+package util
+
+import (
+	"testing"
+	"time"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+)
+
+func TestParseScaleToZeroSchedule(t *testing.T) {
+	tests := []struct {
+		name     string
+		schedule kedav1alpha1.ScaleToZeroSchedule
+		isError  bool
+	}{
+		{"valid", kedav1alpha1.ScaleToZeroSchedule{Windows: []kedav1alpha1.ScheduleWindow{{Start: "0 19 * * 1-5", End: "0 7 * * 2-6"}}}, false},
+		{"valid timezone", kedav1alpha1.ScaleToZeroSchedule{Timezone: "Europe/Paris", Windows: []kedav1alpha1.ScheduleWindow{{Start: "0 19 * * *", End: "0 7 * * *"}}}, false},
+		{"no windows", kedav1alpha1.ScaleToZeroSchedule{}, true},
+		{"invalid timezone", kedav1alpha1.ScaleToZeroSchedule{Timezone: "Mars/Olympus", Windows: []kedav1alpha1.ScheduleWindow{{Start: "0 19 * * *", End: "0 7 * * *"}}}, true},
+		{"invalid start", kedav1alpha1.ScaleToZeroSchedule{Windows: []kedav1alpha1.ScheduleWindow{{Start: "0 25 * * *", End: "0 7 * * *"}}}, true},
+		{"missing end", kedav1alpha1.ScaleToZeroSchedule{Windows: []kedav1alpha1.ScheduleWindow{{Start: "0 19 * * *"}}}, true},
+	}
+	for _, test := range tests {
+		_, err := ParseScaleToZeroSchedule(&test.schedule)
+		if test.isError && err == nil {
+			t.Errorf("%s: expected an error", test.name)
+		}
+		if !test.isError && err != nil {
+			t.Errorf("%s: unexpected error %s", test.name, err)
+		}
+	}
+}
+
+func TestScheduleContains(t *testing.T) {
+	// the nights of the working days and the weekend in Paris
+	schedule, err := ParseScaleToZeroSchedule(&kedav1alpha1.ScaleToZeroSchedule{
+		Timezone: "Europe/Paris",
+		Windows: []kedav1alpha1.ScheduleWindow{
+			{Start: "0 19 * * 1-5", End: "0 7 * * 2-5"},
+			{Start: "0 19 * * 5", End: "0 7 * * 1"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	paris, _ := time.LoadLocation("Europe/Paris")
+	tests := []struct {
+		time     time.Time
+		expected bool
+	}{
+		// Wednesday
+		{time.Date(2020, 10, 7, 12, 0, 0, 0, paris), false},
+		{time.Date(2020, 10, 7, 19, 0, 0, 0, paris), true},
+		{time.Date(2020, 10, 7, 23, 0, 0, 0, paris), true},
+		// Thursday
+		{time.Date(2020, 10, 8, 6, 59, 0, 0, paris), true},
+		{time.Date(2020, 10, 8, 7, 0, 0, 0, paris), false},
+		// Saturday
+		{time.Date(2020, 10, 10, 12, 0, 0, 0, paris), true},
+		// Monday
+		{time.Date(2020, 10, 12, 8, 0, 0, 0, paris), false},
+		// the timezone of the time doesn't matter, 10:00 in Paris
+		{time.Date(2020, 10, 7, 8, 0, 0, 0, time.UTC), false},
+		// 20:00 in Paris
+		{time.Date(2020, 10, 7, 18, 0, 0, 0, time.UTC), true},
+	}
+	for _, test := range tests {
+		if contains := schedule.Contains(test.time); contains != test.expected {
+			t.Errorf("%s: expected %v, got %v", test.time, test.expected, contains)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package util
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+)
+
+// ReadSecretFile returns the content of the file mounted in the pod without its trailing newline, e.g. a projected service account token
+// or a secret of the Secrets Store CSI driver. The path must be absolute and, once its symlinks are resolved, within one of the directories
+// so the triggers can't read the other files of the pod.
+func ReadSecretFile(path string, directories []string) (string, error) {
+	if !filepath.IsAbs(path) {
+		return "", fmt.Errorf("the path of the file %s must be absolute", path)
+	}
+	resolved, err := filepath.EvalSymlinks(filepath.Clean(path))
+	if err != nil {
+		return "", fmt.Errorf("error reading file %s: %s", path, err)
+	}
+	if !isWithinDirectories(resolved, directories) {
+		return "", fmt.Errorf("the file %s isn't in the directories the secrets can be read from", path)
+	}
+
+	content, err := ioutil.ReadFile(resolved)
+	if err != nil {
+		return "", fmt.Errorf("error reading file %s: %s", path, err)
+	}
+	return strings.TrimRight(string(content), "\r\n"), nil
+}
+
+// isWithinDirectories returns true if the path is in one of the directories or their subdirectories
+func isWithinDirectories(path string, directories []string) bool {
+	for _, directory := range directories {
+		if resolved, err := filepath.EvalSymlinks(directory); err == nil {
+			directory = resolved
+		}
+		rel, err := filepath.Rel(directory, path)
+		if err == nil && rel != "." && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,69 @@
+package util
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReadSecretFile(t *testing.T) {
+	root, err := ioutil.TempDir("", "secrets")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	secrets := filepath.Join(root, "secrets")
+	other := filepath.Join(root, "other")
+	for _, dir := range []string{filepath.Join(secrets, "..data"), other} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := ioutil.WriteFile(filepath.Join(secrets, "..data", "token"), []byte("s3cr3t\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(other, "token"), []byte("other"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	// the files of the projected volumes are symlinks to their current version
+	if err := os.Symlink(filepath.Join("..data", "token"), filepath.Join(secrets, "token")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(filepath.Join(other, "token"), filepath.Join(secrets, "escape")); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path     string
+		expected string
+		isError  bool
+	}{
+		{filepath.Join(secrets, "token"), "s3cr3t", false},
+		{filepath.Join(secrets, "..data", "token"), "s3cr3t", false},
+		{"secrets/token", "", true},
+		{filepath.Join(secrets, "missing"), "", true},
+		{filepath.Join(other, "token"), "", true},
+		{filepath.Join(secrets, "..", "other", "token"), "", true},
+		{filepath.Join(secrets, "escape"), "", true},
+	}
+	for _, test := range tests {
+		content, err := ReadSecretFile(test.path, []string{secrets})
+		if test.isError {
+			if err == nil {
+				t.Errorf("%s: expected an error", test.path)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error %s", test.path, err)
+		} else if content != test.expected {
+			t.Errorf("%s: expected %q, got %q", test.path, test.expected, content)
+		}
+	}
+
+	if _, err := ReadSecretFile(filepath.Join(secrets, "token"), nil); err == nil {
+		t.Error("Expected an error if no directory is allowed")
+	}
+}
@@ -0,0 +1,19 @@
+package util
+
+import (
+	"strings"
+)
+
+// WatchNamespaceEnvVar is the environment variable holding the namespaces watched by the operator and the metrics server
+const WatchNamespaceEnvVar = "WATCH_NAMESPACE"
+
+// ParseWatchNamespaces returns the namespaces of a comma-separated list, the list is empty if all the namespaces are watched
+func ParseWatchNamespaces(value string) []string {
+	namespaces := []string{}
+	for _, namespace := range strings.Split(value, ",") {
+		if namespace = strings.TrimSpace(namespace); namespace != "" {
+			namespaces = append(namespaces, namespace)
+		}
+	}
+	return namespaces
+}
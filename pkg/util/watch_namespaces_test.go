@@ -0,0 +1,22 @@
+package util
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseWatchNamespaces(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected []string
+	}{
+		{"", []string{}},
+		{"keda", []string{"keda"}},
+		{"tenant-a, tenant-b,", []string{"tenant-a", "tenant-b"}},
+	}
+	for _, test := range tests {
+		if namespaces := ParseWatchNamespaces(test.value); !reflect.DeepEqual(namespaces, test.expected) {
+			t.Errorf("%q: expected %v, got %v", test.value, test.expected, namespaces)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package webhookreceiver
+
+import (
+	"crypto/subtle"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	errNoSubscription = errors.New("no trigger subscribed to the webhook")
+	errUnauthorized   = errors.New("the secret of the webhook doesn't match")
+)
+
+// DefaultHub is the hub of the webhook triggers of the process, the receiver notifies it of the webhooks received
+var DefaultHub = NewHub()
+
+// Hub dispatches the webhooks received for a name of a namespace to the triggers subscribed to it
+type Hub struct {
+	mutex         sync.Mutex
+	subscriptions map[string]map[*subscription]bool
+	// lastNotified is the time of the last webhook of a subscribed name per secret, while the name has a subscription
+	lastNotified map[string]time.Time
+}
+
+type subscription struct {
+	secret string
+	ch     chan struct{}
+}
+
+// NewHub returns a hub without subscription
+func NewHub() *Hub {
+	return &Hub{
+		subscriptions: make(map[string]map[*subscription]bool),
+		lastNotified:  make(map[string]time.Time),
+	}
+}
+
+func subscriptionKey(namespace, name string) string {
+	return namespace + "/" + name
+}
+
+func notificationKey(namespace, name, secret string) string {
+	return subscriptionKey(namespace, name) + "\x00" + secret
+}
+
+// Subscribe returns the channel receiving a value for each webhook of the name accepted with the secret, an empty secret accepts no webhook.
+// The notifications are dropped while the previous one isn't received. The returned function cancels the subscription.
+func (h *Hub) Subscribe(namespace, name, secret string) (<-chan struct{}, func()) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	key := subscriptionKey(namespace, name)
+	s := &subscription{secret: secret, ch: make(chan struct{}, 1)}
+	if h.subscriptions[key] == nil {
+		h.subscriptions[key] = make(map[*subscription]bool)
+	}
+	h.subscriptions[key][s] = true
+
+	return s.ch, func() {
+		h.mutex.Lock()
+		defer h.mutex.Unlock()
+
+		delete(h.subscriptions[key], s)
+		if len(h.subscriptions[key]) == 0 {
+			delete(h.subscriptions, key)
+			for notified := range h.lastNotified {
+				if strings.HasPrefix(notified, key+"\x00") {
+					delete(h.lastNotified, notified)
+				}
+			}
+		}
+	}
+}
+
+// LastNotified returns the time of the last webhook of the name accepted with the secret, the zero time if there was none
+func (h *Hub) LastNotified(namespace, name, secret string) time.Time {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	return h.lastNotified[notificationKey(namespace, name, secret)]
+}
+
+// authorize returns an error if no subscription of the name accepts the secret
+func (h *Hub) authorize(namespace, name, secret string) error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	subscriptions := h.subscriptions[subscriptionKey(namespace, name)]
+	if len(subscriptions) == 0 {
+		return errNoSubscription
+	}
+	for s := range subscriptions {
+		if s.accepts(secret) {
+			return nil
+		}
+	}
+	return errUnauthorized
+}
+
+// notify notifies the subscriptions of the name accepting the secret of a webhook
+func (h *Hub) notify(namespace, name, secret string) error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	subscriptions := h.subscriptions[subscriptionKey(namespace, name)]
+	if len(subscriptions) == 0 {
+		return errNoSubscription
+	}
+	notified := false
+	now := time.Now()
+	for s := range subscriptions {
+		if !s.accepts(secret) {
+			continue
+		}
+		notified = true
+		h.lastNotified[notificationKey(namespace, name, s.secret)] = now
+		select {
+		case s.ch <- struct{}{}:
+		default:
+		}
+	}
+	if !notified {
+		return errUnauthorized
+	}
+	return nil
+}
+
+func (s *subscription) accepts(secret string) bool {
+	return s.secret != "" && subtle.ConstantTimeCompare([]byte(s.secret), []byte(secret)) == 1
+}
@@ -0,0 +1,63 @@
+package webhookreceiver
+
+import (
+	"testing"
+)
+
+func TestHubNotify(t *testing.T) {
+	hub := NewHub()
+	if err := hub.notify("default", "orders", ""); err != errNoSubscription {
+		t.Errorf("Expected %s, got %v", errNoSubscription, err)
+	}
+
+	withSecret, cancelWithSecret := hub.Subscribe("default", "orders", "s3cr3t")
+	other, cancelOther := hub.Subscribe("default", "orders", "0th3r")
+	_, cancelEmpty := hub.Subscribe("default", "orders", "")
+	defer cancelEmpty()
+
+	if err := hub.notify("default", "orders", ""); err != errUnauthorized {
+		t.Errorf("Expected %s without secret, got %v", errUnauthorized, err)
+	}
+	if err := hub.notify("default", "orders", "0th3r"); err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	select {
+	case <-withSecret:
+		t.Error("Expected the subscription with another secret not to be notified")
+	default:
+	}
+	select {
+	case <-other:
+	default:
+		t.Error("Expected the subscription with the secret to be notified")
+	}
+	if !hub.LastNotified("default", "orders", "s3cr3t").IsZero() || hub.LastNotified("default", "orders", "0th3r").IsZero() {
+		t.Error("Expected only the subscription with the secret to be notified")
+	}
+
+	// the notifications not received yet are coalesced
+	for i := 0; i < 3; i++ {
+		if err := hub.notify("default", "orders", "s3cr3t"); err != nil {
+			t.Fatalf("Unexpected error %s", err)
+		}
+	}
+	<-withSecret
+	select {
+	case <-withSecret:
+		t.Error("Expected a single pending notification")
+	default:
+	}
+
+	cancelOther()
+	if err := hub.authorize("default", "orders", "guess"); err != errUnauthorized {
+		t.Errorf("Expected %s, got %v", errUnauthorized, err)
+	}
+	cancelWithSecret()
+	cancelEmpty()
+	if err := hub.authorize("default", "orders", "s3cr3t"); err != errNoSubscription {
+		t.Errorf("Expected %s once unsubscribed, got %v", errNoSubscription, err)
+	}
+	if !hub.LastNotified("default", "orders", "s3cr3t").IsZero() {
+		t.Error("Expected the notification times to be dropped once unsubscribed")
+	}
+}
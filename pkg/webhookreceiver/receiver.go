@@ -0,0 +1,233 @@
+package webhookreceiver
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// PathPrefix is the prefix of the paths of the webhooks, a webhook is received on <PathPrefix><namespace>/<name>
+	PathPrefix = "/webhooks/v1/"
+	// SecretHeader is the header of the secret of a webhook, the secret can also be given as the password of the basic authentication,
+	// e.g. for SNS. The secret isn't accepted in the URL, it would be logged by the proxies and the clients
+	SecretHeader = "X-Keda-Webhook-Secret"
+
+	maxRequestBytes      = 1 << 20
+	confirmationTimeout  = 10 * time.Second
+	shutdownTimeout      = 5 * time.Second
+	eventGridTypeHeader  = "Aeg-Event-Type"
+	snsMessageTypeHeader = "X-Amz-Sns-Message-Type"
+)
+
+// eventGridEvent is an event of the Event Grid schema, only the data of the subscription validation events is read
+type eventGridEvent struct {
+	EventType string `json:"eventType"`
+	Data      struct {
+		ValidationCode string `json:"validationCode"`
+	} `json:"data"`
+}
+
+// Server receives the webhooks activating the webhook triggers, it is added to the manager so it runs along the scale loops.
+// The webhooks of Azure Event Grid, Amazon SNS and any other POST request are accepted with the secret of a trigger,
+// the signature of the SNS messages is verified as well.
+type Server struct {
+	addr   string
+	hub    *Hub
+	logger logr.Logger
+	// confirmSNSSubscription follows the SubscribeURL of an SNS subscription confirmation
+	confirmSNSSubscription func(ctx context.Context, subscribeURL string) error
+	// getSNSCertificate returns the certificate signing the SNS messages downloaded from the SigningCertURL
+	getSNSCertificate func(ctx context.Context, certURL string) (*x509.Certificate, error)
+}
+
+// NewServer creates a Server listening on addr, notifying the triggers subscribed to the hub
+func NewServer(addr string, hub *Hub) *Server {
+	return &Server{
+		addr:                   addr,
+		hub:                    hub,
+		logger:                 logf.Log.WithName("webhookreceiver"),
+		confirmSNSSubscription: confirmSNSSubscription,
+		getSNSCertificate:      getSNSCertificate,
+	}
+}
+
+// Start receives the webhooks until the stop channel is closed
+func (s *Server) Start(stop <-chan struct{}) error {
+	mux := http.NewServeMux()
+	mux.Handle(PathPrefix, s)
+	server := &http.Server{Addr: s.addr, Handler: mux}
+
+	errCh := make(chan error, 1)
+	go func() {
+		s.logger.Info("Starting webhook receiver", "address", s.addr)
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			errCh <- err
+		}
+		close(errCh)
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-stop:
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		return server.Shutdown(ctx)
+	}
+}
+
+// NeedLeaderElection returns true, the scale loops the webhooks are dispatched to only run in the leader
+func (s *Server) NeedLeaderElection() bool {
+	return true
+}
+
+// ServeHTTP handles the webhooks sent to <PathPrefix><namespace>/<name>
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, PathPrefix), "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		http.Error(w, fmt.Sprintf("the path must be %s<namespace>/<name>", PathPrefix), http.StatusNotFound)
+		return
+	}
+	namespace, name := parts[0], parts[1]
+
+	// the CloudEvents schema of Event Grid validates the endpoint with the abuse protection handshake
+	if r.Method == http.MethodOptions && r.Header.Get("WebHook-Request-Origin") != "" {
+		if !s.authorized(w, r, namespace, name) {
+			return
+		}
+		w.Header().Set("WebHook-Allowed-Origin", r.Header.Get("WebHook-Request-Origin"))
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, fmt.Sprintf("method %s not allowed", r.Method), http.StatusMethodNotAllowed)
+		return
+	}
+	if !s.authorized(w, r, namespace, name) {
+		return
+	}
+
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxRequestBytes))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("error reading request: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	if r.Header.Get(snsMessageTypeHeader) != "" {
+		message := snsMessage{}
+		if err := json.Unmarshal(body, &message); err != nil {
+			http.Error(w, fmt.Sprintf("error parsing the sns message: %s", err), http.StatusBadRequest)
+			return
+		}
+		if err := s.verifySNSMessage(r.Context(), &message); err != nil {
+			http.Error(w, fmt.Sprintf("error verifying the sns message: %s", err), http.StatusForbidden)
+			return
+		}
+		switch message.Type {
+		case "SubscriptionConfirmation":
+			s.confirmSNS(w, r, &message)
+			return
+		case "UnsubscribeConfirmation":
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+	} else if r.Header.Get(eventGridTypeHeader) == "SubscriptionValidation" {
+		s.validateEventGridSubscription(w, body)
+		return
+	}
+
+	if err := s.hub.notify(namespace, name, webhookSecret(r)); err != nil {
+		s.writeHubError(w, err)
+		return
+	}
+	s.logger.V(1).Info("Received webhook", "namespace", namespace, "name", name)
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// authorized writes the error response and returns false if no trigger subscribed to the name accepts the secret of the request
+func (s *Server) authorized(w http.ResponseWriter, r *http.Request, namespace, name string) bool {
+	if err := s.hub.authorize(namespace, name, webhookSecret(r)); err != nil {
+		s.writeHubError(w, err)
+		return false
+	}
+	return true
+}
+
+func (s *Server) writeHubError(w http.ResponseWriter, err error) {
+	if err == errUnauthorized {
+		// SNS only sends the credentials of the basic authentication once challenged
+		w.Header().Set("WWW-Authenticate", `Basic realm="keda"`)
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	http.Error(w, err.Error(), http.StatusNotFound)
+}
+
+// validateEventGridSubscription answers the validation event of an Event Grid subscription with its validation code
+func (s *Server) validateEventGridSubscription(w http.ResponseWriter, body []byte) {
+	events := []eventGridEvent{}
+	if err := json.Unmarshal(body, &events); err != nil || len(events) == 0 || events[0].Data.ValidationCode == "" {
+		http.Error(w, "no validation code in the event grid subscription validation event", http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]string{"validationResponse": events[0].Data.ValidationCode})
+}
+
+// confirmSNS confirms the subscription of the SNS topic by following its SubscribeURL
+func (s *Server) confirmSNS(w http.ResponseWriter, r *http.Request, message *snsMessage) {
+	if message.SubscribeURL == "" {
+		http.Error(w, "no SubscribeURL in the sns subscription confirmation", http.StatusBadRequest)
+		return
+	}
+	if !isSNSURL(message.SubscribeURL) {
+		http.Error(w, "the SubscribeURL of the sns subscription confirmation isn't an sns endpoint", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), confirmationTimeout)
+	defer cancel()
+	if err := s.confirmSNSSubscription(ctx, message.SubscribeURL); err != nil {
+		s.logger.Error(err, "Failed to confirm the sns subscription")
+		http.Error(w, fmt.Sprintf("error confirming the sns subscription: %s", err), http.StatusBadGateway)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func confirmSNSSubscription(ctx context.Context, subscribeURL string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, subscribeURL, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("sns returned %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// webhookSecret returns the secret of the request, from the SecretHeader or the password of the basic authentication
+func webhookSecret(r *http.Request) string {
+	if secret := r.Header.Get(SecretHeader); secret != "" {
+		return secret
+	}
+	if _, password, ok := r.BasicAuth(); ok {
+		return password
+	}
+	return ""
+}
@@ -0,0 +1,229 @@
+package webhookreceiver
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type serveHTTPTestData struct {
+	name      string
+	method    string
+	path      string
+	headers   map[string]string
+	basicAuth bool
+	body      string
+	// sns is signed with snsTestKey to make the body
+	sns            *snsMessage
+	expectedStatus int
+	expectedNotify bool
+}
+
+var serveHTTPTestDataset = []serveHTTPTestData{
+	{
+		name:           "generic webhook",
+		method:         http.MethodPost,
+		path:           "/webhooks/v1/default/orders",
+		headers:        map[string]string{SecretHeader: "s3cr3t"},
+		body:           `{"id":"1"}`,
+		expectedStatus: http.StatusAccepted,
+		expectedNotify: true,
+	},
+	{
+		name:           "secret in the query",
+		method:         http.MethodPost,
+		path:           "/webhooks/v1/default/orders?secret=s3cr3t",
+		expectedStatus: http.StatusUnauthorized,
+	},
+	{
+		name:           "secret in the basic authentication",
+		method:         http.MethodPost,
+		path:           "/webhooks/v1/default/orders",
+		basicAuth:      true,
+		expectedStatus: http.StatusAccepted,
+		expectedNotify: true,
+	},
+	{
+		name:           "wrong secret",
+		method:         http.MethodPost,
+		path:           "/webhooks/v1/default/orders",
+		headers:        map[string]string{SecretHeader: "guess"},
+		expectedStatus: http.StatusUnauthorized,
+	},
+	{
+		name:           "no subscription",
+		method:         http.MethodPost,
+		path:           "/webhooks/v1/default/invoices",
+		expectedStatus: http.StatusNotFound,
+	},
+	{
+		name:           "no name",
+		method:         http.MethodPost,
+		path:           "/webhooks/v1/default",
+		expectedStatus: http.StatusNotFound,
+	},
+	{
+		name:           "wrong method",
+		method:         http.MethodGet,
+		path:           "/webhooks/v1/default/orders",
+		headers:        map[string]string{SecretHeader: "s3cr3t"},
+		expectedStatus: http.StatusMethodNotAllowed,
+	},
+	{
+		name:           "cloudevents handshake",
+		method:         http.MethodOptions,
+		path:           "/webhooks/v1/default/orders",
+		headers:        map[string]string{SecretHeader: "s3cr3t", "WebHook-Request-Origin": "eventgrid.azure.net"},
+		expectedStatus: http.StatusOK,
+	},
+	{
+		name:           "event grid notification",
+		method:         http.MethodPost,
+		path:           "/webhooks/v1/default/orders",
+		headers:        map[string]string{SecretHeader: "s3cr3t", eventGridTypeHeader: "Notification"},
+		body:           `[{"eventType":"Microsoft.Storage.BlobCreated"}]`,
+		expectedStatus: http.StatusAccepted,
+		expectedNotify: true,
+	},
+	{
+		name:           "event grid validation without code",
+		method:         http.MethodPost,
+		path:           "/webhooks/v1/default/orders",
+		headers:        map[string]string{SecretHeader: "s3cr3t", eventGridTypeHeader: "SubscriptionValidation"},
+		body:           `[{"eventType":"Microsoft.EventGrid.SubscriptionValidationEvent"}]`,
+		expectedStatus: http.StatusBadRequest,
+	},
+	{
+		name:           "sns notification",
+		method:         http.MethodPost,
+		path:           "/webhooks/v1/default/orders",
+		headers:        map[string]string{SecretHeader: "s3cr3t", snsMessageTypeHeader: "Notification"},
+		sns:            &snsMessage{Type: "Notification", MessageID: "22b80b92", TopicArn: "arn:aws:sns:eu-west-1:123456789012:orders", Message: "hello"},
+		expectedStatus: http.StatusAccepted,
+		expectedNotify: true,
+	},
+	{
+		name:           "sns notification without signature",
+		method:         http.MethodPost,
+		path:           "/webhooks/v1/default/orders",
+		headers:        map[string]string{SecretHeader: "s3cr3t", snsMessageTypeHeader: "Notification"},
+		body:           `{"Type":"Notification","Message":"hello"}`,
+		expectedStatus: http.StatusForbidden,
+	},
+	{
+		name:           "sns notification with a wrong signature",
+		method:         http.MethodPost,
+		path:           "/webhooks/v1/default/orders",
+		headers:        map[string]string{SecretHeader: "s3cr3t", snsMessageTypeHeader: "Notification"},
+		body:           `{"Type":"Notification","Message":"hello","Timestamp":"2020-10-01T12:00:00Z","SignatureVersion":"1","Signature":"c2lnbmF0dXJl","SigningCertURL":"https://sns.eu-west-1.amazonaws.com/SimpleNotificationService.pem"}`,
+		expectedStatus: http.StatusForbidden,
+	},
+	{
+		name:           "sns confirmation to another host",
+		method:         http.MethodPost,
+		path:           "/webhooks/v1/default/orders",
+		headers:        map[string]string{SecretHeader: "s3cr3t", snsMessageTypeHeader: "SubscriptionConfirmation"},
+		sns:            &snsMessage{Type: "SubscriptionConfirmation", SubscribeURL: "https://example.com/confirm"},
+		expectedStatus: http.StatusBadRequest,
+	},
+	{
+		name:           "sns unsubscribe confirmation",
+		method:         http.MethodPost,
+		path:           "/webhooks/v1/default/orders",
+		headers:        map[string]string{SecretHeader: "s3cr3t", snsMessageTypeHeader: "UnsubscribeConfirmation"},
+		sns:            &snsMessage{Type: "UnsubscribeConfirmation"},
+		expectedStatus: http.StatusOK,
+	},
+}
+
+func TestServeHTTP(t *testing.T) {
+	for _, testData := range serveHTTPTestDataset {
+		hub := NewHub()
+		notifications, cancel := hub.Subscribe("default", "orders", "s3cr3t")
+		server := newTestServer(t, hub)
+
+		body := testData.body
+		if testData.sns != nil {
+			body = signSNSMessage(t, *testData.sns)
+		}
+		req := httptest.NewRequest(testData.method, testData.path, strings.NewReader(body))
+		for header, value := range testData.headers {
+			req.Header.Set(header, value)
+		}
+		if testData.basicAuth {
+			req.SetBasicAuth("keda", "s3cr3t")
+		}
+		rec := httptest.NewRecorder()
+		server.ServeHTTP(rec, req)
+
+		if rec.Code != testData.expectedStatus {
+			t.Errorf("%s: expected status %d, got %d: %s", testData.name, testData.expectedStatus, rec.Code, rec.Body.String())
+		}
+		select {
+		case <-notifications:
+			if !testData.expectedNotify {
+				t.Errorf("%s: expected no notification", testData.name)
+			}
+		default:
+			if testData.expectedNotify {
+				t.Errorf("%s: expected a notification", testData.name)
+			}
+		}
+		cancel()
+	}
+}
+
+func TestServeHTTPEventGridValidation(t *testing.T) {
+	hub := NewHub()
+	_, cancel := hub.Subscribe("default", "orders", "s3cr3t")
+	defer cancel()
+
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/v1/default/orders",
+		strings.NewReader(`[{"eventType":"Microsoft.EventGrid.SubscriptionValidationEvent","data":{"validationCode":"512d38b6"}}]`))
+	req.Header.Set(eventGridTypeHeader, "SubscriptionValidation")
+	req.Header.Set(SecretHeader, "s3cr3t")
+	rec := httptest.NewRecorder()
+	NewServer("", hub).ServeHTTP(rec, req)
+
+	response := map[string]string{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	if rec.Code != http.StatusOK || response["validationResponse"] != "512d38b6" {
+		t.Errorf("Expected the validation code in the response, got %d %v", rec.Code, response)
+	}
+	if !hub.LastNotified("default", "orders", "s3cr3t").IsZero() {
+		t.Error("Expected the validation event not to activate the trigger")
+	}
+}
+
+func TestServeHTTPSNSConfirmation(t *testing.T) {
+	hub := NewHub()
+	_, cancel := hub.Subscribe("default", "orders", "s3cr3t")
+	defer cancel()
+
+	var confirmed string
+	server := newTestServer(t, hub)
+	server.confirmSNSSubscription = func(ctx context.Context, subscribeURL string) error {
+		confirmed = subscribeURL
+		return nil
+	}
+
+	subscribeURL := "https://sns.eu-west-1.amazonaws.com/?Action=ConfirmSubscription&Token=2336412f37"
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/v1/default/orders",
+		strings.NewReader(signSNSMessage(t, snsMessage{Type: "SubscriptionConfirmation", Token: "2336412f37", SubscribeURL: subscribeURL})))
+	req.Header.Set(snsMessageTypeHeader, "SubscriptionConfirmation")
+	req.SetBasicAuth("keda", "s3cr3t")
+	rec := httptest.NewRecorder()
+	server.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+	if confirmed != subscribeURL {
+		t.Errorf("Expected the SubscribeURL to be followed, got %q", confirmed)
+	}
+}
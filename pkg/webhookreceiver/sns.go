@@ -0,0 +1,164 @@
+package webhookreceiver
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// snsMaxMessageAge is the age of the SNS messages rejected, a replayed message can't activate the triggers after it
+const snsMaxMessageAge = time.Hour
+
+// the SubscribeURL of an SNS subscription confirmation is only followed to an SNS endpoint,
+// the certificate signing the messages is only downloaded from one
+var snsHostPattern = regexp.MustCompile(`^sns\.[a-z0-9-]+\.amazonaws\.com(\.cn)?$`)
+
+// snsCertificates caches the certificates signing the SNS messages per URL, SNS signs all the messages with a few of them
+var snsCertificates sync.Map
+
+// snsMessage is the part of an SNS HTTP message used by the receiver
+type snsMessage struct {
+	Type             string `json:"Type"`
+	MessageID        string `json:"MessageId"`
+	Token            string `json:"Token"`
+	TopicArn         string `json:"TopicArn"`
+	Subject          string `json:"Subject"`
+	Message          string `json:"Message"`
+	Timestamp        string `json:"Timestamp"`
+	SignatureVersion string `json:"SignatureVersion"`
+	Signature        string `json:"Signature"`
+	SigningCertURL   string `json:"SigningCertURL"`
+	SubscribeURL     string `json:"SubscribeURL"`
+}
+
+// stringToSign returns the fields of the message signed by SNS, in the order of the signature of its type
+func (m *snsMessage) stringToSign() string {
+	fields := [][2]string{{"Message", m.Message}, {"MessageId", m.MessageID}}
+	if m.Type == "Notification" {
+		if m.Subject != "" {
+			fields = append(fields, [2]string{"Subject", m.Subject})
+		}
+		fields = append(fields, [2]string{"Timestamp", m.Timestamp}, [2]string{"TopicArn", m.TopicArn}, [2]string{"Type", m.Type})
+	} else {
+		fields = append(fields, [2]string{"SubscribeURL", m.SubscribeURL}, [2]string{"Timestamp", m.Timestamp},
+			[2]string{"Token", m.Token}, [2]string{"TopicArn", m.TopicArn}, [2]string{"Type", m.Type})
+	}
+
+	var builder strings.Builder
+	for _, field := range fields {
+		builder.WriteString(field[0] + "\n" + field[1] + "\n")
+	}
+	return builder.String()
+}
+
+// isSNSURL returns true if the URL is an https URL of an SNS endpoint
+func isSNSURL(rawURL string) bool {
+	parsed, err := url.Parse(rawURL)
+	return err == nil && parsed.Scheme == "https" && snsHostPattern.MatchString(parsed.Hostname())
+}
+
+// verifySNSMessage returns an error if the message isn't signed by SNS or is older than snsMaxMessageAge
+func (s *Server) verifySNSMessage(ctx context.Context, message *snsMessage) error {
+	var hash crypto.Hash
+	switch message.SignatureVersion {
+	case "1":
+		hash = crypto.SHA1
+	case "2":
+		hash = crypto.SHA256
+	default:
+		return fmt.Errorf("unsupported signature version %q", message.SignatureVersion)
+	}
+	if !isSNSURL(message.SigningCertURL) {
+		return errors.New("the SigningCertURL isn't an sns endpoint")
+	}
+	signature, err := base64.StdEncoding.DecodeString(message.Signature)
+	if err != nil {
+		return fmt.Errorf("error decoding the signature: %s", err)
+	}
+	timestamp, err := time.Parse(time.RFC3339, message.Timestamp)
+	if err != nil {
+		return fmt.Errorf("error parsing the timestamp: %s", err)
+	}
+	if time.Since(timestamp) > snsMaxMessageAge {
+		return fmt.Errorf("the message was sent at %s, more than %s ago", message.Timestamp, snsMaxMessageAge)
+	}
+
+	cert, err := s.getSNSCertificate(ctx, message.SigningCertURL)
+	if err != nil {
+		return fmt.Errorf("error getting the signing certificate: %s", err)
+	}
+	publicKey, ok := cert.PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return errors.New("the signing certificate doesn't have an RSA key")
+	}
+
+	// the signatures of version 1 are SHA1withRSA, those of version 2 SHA256withRSA
+	var digest []byte
+	if hash == crypto.SHA1 {
+		sum := sha1.Sum([]byte(message.stringToSign()))
+		digest = sum[:]
+	} else {
+		sum := sha256.Sum256([]byte(message.stringToSign()))
+		digest = sum[:]
+	}
+	if err := rsa.VerifyPKCS1v15(publicKey, hash, digest, signature); err != nil {
+		return errors.New("the signature doesn't match")
+	}
+	return nil
+}
+
+// getSNSCertificate returns the certificate signing the SNS messages downloaded from the URL, the certificates are cached while they are valid
+func getSNSCertificate(ctx context.Context, certURL string) (*x509.Certificate, error) {
+	if value, ok := snsCertificates.Load(certURL); ok {
+		if cert := value.(*x509.Certificate); time.Now().Before(cert.NotAfter) {
+			return cert, nil
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, confirmationTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, certURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("sns returned %d", resp.StatusCode)
+	}
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxRequestBytes))
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(body)
+	if block == nil {
+		return nil, errors.New("no PEM certificate found")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	if now := time.Now(); now.Before(cert.NotBefore) || now.After(cert.NotAfter) {
+		return nil, errors.New("the certificate isn't valid")
+	}
+	snsCertificates.Store(certURL, cert)
+	return cert, nil
+}
@@ -0,0 +1,136 @@
+package webhookreceiver
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+)
+
+const testSigningCertURL = "https://sns.eu-west-1.amazonaws.com/SimpleNotificationService-0123456789abcdef.pem"
+
+var (
+	snsTestKeyOnce sync.Once
+	snsTestKey     *rsa.PrivateKey
+	snsTestCert    *x509.Certificate
+)
+
+// snsTestSigner returns the key and the certificate the test SNS messages are signed with
+func snsTestSigner(t *testing.T) (*rsa.PrivateKey, *x509.Certificate) {
+	snsTestKeyOnce.Do(func() {
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			t.Fatal(err)
+		}
+		template := &x509.Certificate{
+			SerialNumber: big.NewInt(1),
+			Subject:      pkix.Name{CommonName: "sns.amazonaws.com"},
+			NotBefore:    time.Now().Add(-time.Hour),
+			NotAfter:     time.Now().Add(time.Hour),
+		}
+		der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cert, err := x509.ParseCertificate(der)
+		if err != nil {
+			t.Fatal(err)
+		}
+		snsTestKey, snsTestCert = key, cert
+	})
+	return snsTestKey, snsTestCert
+}
+
+// newTestServer returns a server verifying the SNS messages with the certificate of snsTestSigner
+func newTestServer(t *testing.T, hub *Hub) *Server {
+	_, cert := snsTestSigner(t)
+	server := NewServer("", hub)
+	server.getSNSCertificate = func(ctx context.Context, certURL string) (*x509.Certificate, error) {
+		return cert, nil
+	}
+	return server
+}
+
+// signSNSMessage returns the JSON of the message signed with version 2 by the key of snsTestSigner, sent now
+func signSNSMessage(t *testing.T, message snsMessage) string {
+	if message.Timestamp == "" {
+		message.Timestamp = time.Now().UTC().Format(time.RFC3339)
+	}
+	if message.SignatureVersion == "" {
+		message.SignatureVersion = "2"
+	}
+	message.SigningCertURL = testSigningCertURL
+	message.Signature = signSNSTestMessage(t, &message)
+
+	body, err := json.Marshal(message)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(body)
+}
+
+func signSNSTestMessage(t *testing.T, message *snsMessage) string {
+	key, _ := snsTestSigner(t)
+	hash := crypto.SHA256
+	var digest []byte
+	if message.SignatureVersion == "1" {
+		hash = crypto.SHA1
+		sum := sha1.Sum([]byte(message.stringToSign()))
+		digest = sum[:]
+	} else {
+		sum := sha256.Sum256([]byte(message.stringToSign()))
+		digest = sum[:]
+	}
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, hash, digest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return base64.StdEncoding.EncodeToString(signature)
+}
+
+func TestVerifySNSMessage(t *testing.T) {
+	server := newTestServer(t, NewHub())
+	now := time.Now().UTC().Format(time.RFC3339)
+	notification := snsMessage{Type: "Notification", MessageID: "22b80b92", TopicArn: "arn:aws:sns:eu-west-1:123456789012:orders",
+		Subject: "orders", Message: "hello", Timestamp: now, SigningCertURL: testSigningCertURL}
+
+	for _, version := range []string{"1", "2"} {
+		message := notification
+		message.SignatureVersion = version
+		message.Signature = signSNSTestMessage(t, &message)
+		if err := server.verifySNSMessage(context.Background(), &message); err != nil {
+			t.Errorf("Expected the message signed with version %s to be verified, got %s", version, err)
+		}
+
+		message.Message = "tampered"
+		if err := server.verifySNSMessage(context.Background(), &message); err == nil {
+			t.Errorf("Expected an error for the tampered message signed with version %s", version)
+		}
+	}
+
+	old := notification
+	old.SignatureVersion = "2"
+	old.Timestamp = time.Now().Add(-2 * snsMaxMessageAge).UTC().Format(time.RFC3339)
+	old.Signature = signSNSTestMessage(t, &old)
+	if err := server.verifySNSMessage(context.Background(), &old); err == nil {
+		t.Error("Expected an error for a message older than the max age")
+	}
+
+	otherHost := notification
+	otherHost.SignatureVersion = "2"
+	otherHost.SigningCertURL = "https://example.com/SimpleNotificationService.pem"
+	otherHost.Signature = signSNSTestMessage(t, &otherHost)
+	if err := server.verifySNSMessage(context.Background(), &otherHost); err == nil {
+		t.Error("Expected an error for a certificate outside of sns")
+	}
+}